@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	pcstore_server "github.com/square/p2/pkg/grpc/pcstore"
+	pcstore_protos "github.com/square/p2/pkg/grpc/pcstore/protos"
+	"github.com/square/p2/pkg/labels"
+	"github.com/square/p2/pkg/logging"
+	"github.com/square/p2/pkg/store/consul"
+	"github.com/square/p2/pkg/store/consul/flags"
+	"github.com/square/p2/pkg/store/consul/pcstore"
+
+	"google.golang.org/grpc"
+	"gopkg.in/yaml.v2"
+)
+
+type config struct {
+	Port int `yaml:"port"`
+}
+
+const defaultPort = 3000
+
+func main() {
+	// Parse custom flags + standard Consul routing options
+	_, opts, labeler := flags.ParseWithConsulOptions()
+
+	client := consul.NewConsulClient(opts)
+	pcLogger := logging.NewLogger(logrus.Fields{})
+	applicator := labels.NewConsulApplicator(client, 0, 1*time.Minute)
+	pcStore := pcstore.NewConsul(client, labeler, labels.DefaultAggregationRate, applicator, &pcLogger)
+
+	logger := log.New(os.Stderr, "", 0)
+	port := getPort(logger)
+
+	lis, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", port))
+	if err != nil {
+		logger.Fatalf("failed to listen: %v", err)
+	}
+
+	s := grpc.NewServer()
+	pcstore_protos.RegisterP2PodClusterStoreServer(s, pcstore_server.NewServer(
+		pcStore,
+		consul.NewConsulStore(client),
+	))
+	if err := s.Serve(lis); err != nil {
+		logger.Fatalf("failed to serve: %v", err)
+	}
+}
+
+func getPort(logger *log.Logger) int {
+	configPath := os.Getenv("CONFIG_PATH")
+	if configPath == "" {
+		return defaultPort
+	}
+
+	configBytes, err := ioutil.ReadFile(configPath)
+	if err != nil {
+		logger.Fatal(err)
+	}
+
+	var config config
+	err = yaml.Unmarshal(configBytes, &config)
+	if err != nil {
+		logger.Fatal(err)
+	}
+
+	if config.Port == 0 {
+		logger.Fatal("Port must be set")
+	}
+
+	return config.Port
+}
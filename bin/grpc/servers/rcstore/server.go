@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net"
+	"os"
+
+	rcstore_server "github.com/square/p2/pkg/grpc/rcstore"
+	rcstore_protos "github.com/square/p2/pkg/grpc/rcstore/protos"
+	"github.com/square/p2/pkg/store/consul"
+	"github.com/square/p2/pkg/store/consul/flags"
+	"github.com/square/p2/pkg/store/consul/rcstore"
+	"github.com/square/p2/pkg/store/consul/rollstore"
+	"github.com/square/p2/pkg/store/consul/statusstore"
+	"github.com/square/p2/pkg/store/consul/statusstore/rcstatus"
+	"github.com/square/p2/pkg/store/consul/statusstore/rollstatus"
+
+	"google.golang.org/grpc"
+	"gopkg.in/yaml.v2"
+)
+
+type config struct {
+	Port int `yaml:"port"`
+}
+
+const defaultPort = 3000
+
+func main() {
+	// Parse custom flags + standard Consul routing options
+	_, opts, labeler := flags.ParseWithConsulOptions()
+
+	client := consul.NewConsulClient(opts)
+	statusStore := statusstore.NewConsul(client)
+	rcStatusStore := rcstatus.NewConsul(statusStore, consul.RCStatusNamespace)
+	rollStatusStore := rollstatus.NewConsul(statusStore, consul.RollStatusNamespace)
+	rcStore := rcstore.NewConsul(client, labeler, 3)
+	rollStore := rollstore.NewConsul(client, labeler, nil)
+
+	logger := log.New(os.Stderr, "", 0)
+	port := getPort(logger)
+
+	lis, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", port))
+	if err != nil {
+		logger.Fatalf("failed to listen: %v", err)
+	}
+
+	s := grpc.NewServer()
+	rcstore_protos.RegisterP2RCStoreServer(s, rcstore_server.NewServer(
+		rcStatusStore,
+		rcStore,
+		rollStore,
+		rollStatusStore,
+		client.KV(),
+	))
+	if err := s.Serve(lis); err != nil {
+		logger.Fatalf("failed to serve: %v", err)
+	}
+}
+
+func getPort(logger *log.Logger) int {
+	configPath := os.Getenv("CONFIG_PATH")
+	if configPath == "" {
+		return defaultPort
+	}
+
+	configBytes, err := ioutil.ReadFile(configPath)
+	if err != nil {
+		logger.Fatal(err)
+	}
+
+	var config config
+	err = yaml.Unmarshal(configBytes, &config)
+	if err != nil {
+		logger.Fatal(err)
+	}
+
+	if config.Port == 0 {
+		logger.Fatal("Port must be set")
+	}
+
+	return config.Port
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os/user"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/square/p2/pkg/apply"
+	"github.com/square/p2/pkg/cli"
+	"github.com/square/p2/pkg/labels"
+	"github.com/square/p2/pkg/logging"
+	"github.com/square/p2/pkg/store/consul"
+	"github.com/square/p2/pkg/store/consul/auditlogstore"
+	"github.com/square/p2/pkg/store/consul/dsstore"
+	"github.com/square/p2/pkg/store/consul/flags"
+	"github.com/square/p2/pkg/store/consul/pcstore"
+	"github.com/square/p2/pkg/store/consul/rcstore"
+	"github.com/square/p2/pkg/store/consul/rollstore"
+)
+
+var (
+	files    = kingpin.Arg("files", "YAML files describing replication controllers, daemon sets and pod clusters. See the apply package for the format.").Required().ExistingFiles()
+	skipConf = kingpin.Flag("yes", "Apply the plan without asking for confirmation").Short('y').Bool()
+	dryRun   = kingpin.Flag("dry-run", "Print the plan without applying it").Bool()
+	userName = kingpin.Flag("user", "The user to attribute changes to in the audit log").Default(currentUserName()).String()
+)
+
+func main() {
+	_, consulOpts, _ := flags.ParseWithConsulOptions()
+	client := consul.NewConsulClient(consulOpts)
+	logger := logging.NewLogger(logrus.Fields{})
+
+	specs, err := apply.ReadFiles(*files)
+	if err != nil {
+		logger.WithError(err).Fatalln("Could not read specs")
+	}
+
+	// The stores' transactions need a labeler that accesses consul
+	// directly, rather than the one flags.ParseWithConsulOptions() returns
+	labeler := labels.NewConsulApplicator(client, 0, 0)
+	auditLogStore := auditlogstore.NewConsulStore(client.KV())
+	dsStore := dsstore.NewConsul(client, 3, &logger)
+	pcStore := pcstore.NewConsul(client, labeler, labels.DefaultAggregationRate, labeler, &logger)
+
+	session, _, err := consul.NewConsulStore(client).NewSession(fmt.Sprintf("p2-apply-%s", *userName), nil)
+	if err != nil {
+		logger.WithError(err).Fatalln("Could not create session")
+	}
+	defer session.Destroy()
+
+	applier := apply.NewApplier(
+		rcstore.NewConsul(client, labeler, 3),
+		rollstore.NewConsul(client, labeler, nil),
+		dsStore,
+		dsstore.NewAuditingStore(dsStore, auditLogStore),
+		pcStore,
+		labeler,
+		auditLogStore,
+		client.KV(),
+		session,
+	)
+
+	plan, err := applier.Plan(specs)
+	if err != nil {
+		logger.WithError(err).Fatalln("Could not plan changes")
+	}
+	fmt.Print(plan)
+	if plan.Empty() || *dryRun {
+		return
+	}
+	if !*skipConf && !cli.Confirm() {
+		logger.Fatalln("User aborted")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	err = applier.Apply(ctx, plan, *userName)
+	if err != nil {
+		logger.WithError(err).Fatalln("Could not apply changes")
+	}
+	logger.Infof("Applied %d changes", len(plan.Changes))
+}
+
+func currentUserName() string {
+	username := "unknown user"
+
+	if user, err := user.Current(); err == nil {
+		username = user.Username
+	}
+	return username
+}
@@ -0,0 +1,87 @@
+// p2-artifact-proxy runs a pull-through cache for artifacts. Preparers use it
+// when it is listed in their "artifact_proxy" config, either directly or
+// through a node label.
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/alecthomas/kingpin.v2"
+	"gopkg.in/yaml.v2"
+
+	"github.com/square/p2/pkg/artifact/cacheproxy"
+	"github.com/square/p2/pkg/logging"
+	p2metrics "github.com/square/p2/pkg/metrics"
+	"github.com/square/p2/pkg/uri"
+	"github.com/square/p2/pkg/util/size"
+	"github.com/square/p2/pkg/version"
+)
+
+var (
+	cacheDir       = kingpin.Flag("cache-dir", "Directory to cache artifacts in").Required().String()
+	maxCacheSize   = kingpin.Flag("max-cache-size", "Maximum size of the cache, e.g. 50G. Least recently used artifacts are evicted beyond this").Default("20G").String()
+	listenAddress  = kingpin.Flag("listen-address", "Address to serve the proxy on").Default(":8181").String()
+	allowedOrigins = kingpin.Flag("allowed-origin", "URI prefix, including a trailing slash, of origins the proxy may fetch from. May be repeated").Required().Strings()
+	s3ConfigPath   = kingpin.Flag("s3-config", "YAML file configuring access to an S3-compatible object store, for s3:// origins").ExistingFile()
+	logLevel       = kingpin.Flag("log", "Logging level to display").String()
+)
+
+func main() {
+	kingpin.Version(version.VERSION)
+	kingpin.Parse()
+
+	logger := logging.NewLogger(logrus.Fields{})
+	if *logLevel != "" {
+		lv, err := logrus.ParseLevel(*logLevel)
+		if err != nil {
+			logger.WithErrorAndFields(err, logrus.Fields{"level": *logLevel}).
+				Fatalln("Could not parse log level")
+		}
+		logger.Logger.Level = lv
+	}
+
+	maxBytes, err := size.Parse(*maxCacheSize)
+	if err != nil {
+		logger.WithError(err).Fatalln("Could not parse max cache size")
+	}
+
+	var fetcher uri.Fetcher = uri.NewResumableFetcher(nil, uri.ResumableFetcherOptions{
+		Logger:          logger,
+		MetricsRegistry: p2metrics.Registry,
+	})
+	if *s3ConfigPath != "" {
+		configBytes, err := ioutil.ReadFile(*s3ConfigPath)
+		if err != nil {
+			logger.WithError(err).Fatalln("Could not read S3 config")
+		}
+		var s3Config uri.S3Config
+		err = yaml.Unmarshal(configBytes, &s3Config)
+		if err != nil {
+			logger.WithError(err).Fatalln("Could not parse S3 config")
+		}
+		fetcher, err = uri.NewS3Fetcher(fetcher, nil, s3Config)
+		if err != nil {
+			logger.WithError(err).Fatalln("Could not configure S3")
+		}
+	}
+
+	server, err := cacheproxy.New(*cacheDir, maxBytes.Int64(), fetcher, *allowedOrigins, logger, p2metrics.Registry)
+	if err != nil {
+		logger.WithError(err).Fatalln("Could not start artifact proxy")
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(uri.ProxyFetchPath, server.Handler())
+	mux.Handle("/_metrics", p2metrics.ExpHandler)
+	mux.HandleFunc("/_status", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	logger.WithField("address", *listenAddress).Infoln("Serving artifact proxy")
+	err = http.ListenAndServe(*listenAddress, mux)
+	if err != nil {
+		logger.WithError(err).Fatalln("Artifact proxy exited")
+	}
+}
@@ -0,0 +1,91 @@
+// p2-artifact-registry runs a reference implementation of the artifact
+// registry protocol used by the preparer's "artifact_registry_url" setting.
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/alecthomas/kingpin.v2"
+	"gopkg.in/yaml.v2"
+
+	"github.com/square/p2/pkg/artifact/registryserver"
+	"github.com/square/p2/pkg/auth"
+	"github.com/square/p2/pkg/logging"
+	"github.com/square/p2/pkg/uri"
+	"github.com/square/p2/pkg/version"
+)
+
+var (
+	root          = kingpin.Flag("root", "Directory holding the registry's index and uploaded artifacts").Required().String()
+	listenAddress = kingpin.Flag("listen-address", "Address to serve the registry on").Default(":8080").String()
+	publicURL     = kingpin.Flag("public-url", "URL clients use to reach this registry, used to build the locations of uploaded artifacts").Required().URL()
+	verifyType    = kingpin.Flag("verify", "How uploaded artifacts are verified: by a signature over the build, over its build manifest or over either").Default(auth.VerifyEither).Enum(auth.VerifyBuild, auth.VerifyManifest, auth.VerifyEither)
+	noVerify      = kingpin.Flag("no-verify", "Accept uploaded artifacts without verifying their signatures. Only intended for testing.").Bool()
+	keyringPath   = kingpin.Flag("keyring", "The PGP keyring used to verify uploaded artifacts. Required unless --no-verify is set.").ExistingFile()
+	s3ConfigPath  = kingpin.Flag("s3-config", "YAML file configuring access to an S3-compatible object store, for artifacts registered by an s3:// location").ExistingFile()
+	logLevel      = kingpin.Flag("log", "Logging level to display").String()
+)
+
+func main() {
+	kingpin.Version(version.VERSION)
+	kingpin.Parse()
+
+	logger := logging.NewLogger(logrus.Fields{})
+	if *logLevel != "" {
+		lv, err := logrus.ParseLevel(*logLevel)
+		if err != nil {
+			logger.WithErrorAndFields(err, logrus.Fields{"level": *logLevel}).
+				Fatalln("Could not parse log level")
+		}
+		logger.Logger.Level = lv
+	}
+
+	var fetcher uri.Fetcher = uri.DefaultFetcher
+	if *s3ConfigPath != "" {
+		configBytes, err := ioutil.ReadFile(*s3ConfigPath)
+		if err != nil {
+			logger.WithError(err).Fatalln("Could not read S3 config")
+		}
+		var s3Config uri.S3Config
+		err = yaml.Unmarshal(configBytes, &s3Config)
+		if err != nil {
+			logger.WithError(err).Fatalln("Could not parse S3 config")
+		}
+		fetcher, err = uri.NewS3Fetcher(fetcher, nil, s3Config)
+		if err != nil {
+			logger.WithError(err).Fatalln("Could not configure S3")
+		}
+	}
+
+	var verifier auth.ArtifactVerifier
+	var err error
+	switch {
+	case *noVerify:
+		logger.Warnln("Artifact verification is disabled, any uploaded artifact will be accepted")
+		verifier = auth.NopVerifier()
+	case *keyringPath == "":
+		logger.Fatalln("A --keyring is required to verify uploaded artifacts, or pass --no-verify to accept them unverified")
+	case *verifyType == auth.VerifyBuild:
+		verifier, err = auth.NewBuildVerifier(*keyringPath, fetcher, &logger)
+	case *verifyType == auth.VerifyManifest:
+		verifier, err = auth.NewBuildManifestVerifier(*keyringPath, fetcher, &logger)
+	case *verifyType == auth.VerifyEither:
+		verifier, err = auth.NewCompositeVerifier(*keyringPath, fetcher, &logger)
+	}
+	if err != nil {
+		logger.WithError(err).Fatalln("Could not configure artifact verification")
+	}
+
+	server, err := registryserver.New(*root, *publicURL, fetcher, verifier, logger)
+	if err != nil {
+		logger.WithError(err).Fatalln("Could not start registry")
+	}
+
+	logger.WithField("address", *listenAddress).Infoln("Serving artifact registry")
+	err = http.ListenAndServe(*listenAddress, server.Handler())
+	if err != nil {
+		logger.WithError(err).Fatalln("Registry server exited")
+	}
+}
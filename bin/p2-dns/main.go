@@ -0,0 +1,73 @@
+package main
+
+import (
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/square/p2/pkg/health/checker"
+	"github.com/square/p2/pkg/labels"
+	"github.com/square/p2/pkg/logging"
+	"github.com/square/p2/pkg/pc/dnsserver"
+	"github.com/square/p2/pkg/store/consul"
+	"github.com/square/p2/pkg/store/consul/flags"
+	"github.com/square/p2/pkg/store/consul/pcstore"
+)
+
+var (
+	listenAddr = kingpin.Flag("listen", "The address to serve DNS on, over both UDP and TCP").Default(":8053").String()
+	domain     = kingpin.Flag("domain", "The domain pod cluster names are under, e.g. <cluster>.<pod_id>.<az>.<domain>").Default("p2").String()
+	ttl        = kingpin.Flag("ttl", "How long clients may cache answers").Default("5s").Duration()
+)
+
+func main() {
+	_, consulOpts, labeler := flags.ParseWithConsulOptions()
+	client := consul.NewConsulClient(consulOpts)
+	logger := logging.NewLogger(logrus.Fields{})
+
+	pcStore := pcstore.NewConsul(
+		client,
+		labeler,
+		labels.DefaultAggregationRate,
+		labels.NewConsulApplicator(client, 0, 1*time.Minute),
+		&logger,
+	)
+	server := dnsserver.NewServer(
+		*domain,
+		*ttl,
+		consul.NewConsulStore(client),
+		checker.NewHealthChecker(client),
+		net.LookupIP,
+		logger,
+	)
+	defer server.Stop()
+
+	for _, network := range []string{"udp", "tcp"} {
+		dnsServer := &dns.Server{Addr: *listenAddr, Net: network, Handler: server}
+		go func() {
+			err := dnsServer.ListenAndServe()
+			if err != nil {
+				logger.WithError(err).Fatalf("Could not serve DNS over %s", dnsServer.Net)
+			}
+		}()
+	}
+
+	quitCh := make(chan struct{})
+	go func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		<-signals
+		close(quitCh)
+	}()
+
+	err := pcStore.WatchAndSync(server, quitCh)
+	if err != nil {
+		logger.WithError(err).Fatalln("Could not sync pod clusters")
+	}
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"os"
 	"strconv"
+	"text/tabwriter"
 	"time"
 
 	"gopkg.in/alecthomas/kingpin.v2"
@@ -22,6 +24,8 @@ import (
 	"github.com/square/p2/pkg/store/consul"
 	"github.com/square/p2/pkg/store/consul/dsstore"
 	"github.com/square/p2/pkg/store/consul/flags"
+	"github.com/square/p2/pkg/store/consul/statusstore"
+	"github.com/square/p2/pkg/store/consul/statusstore/daemonsetstatus"
 	"github.com/square/p2/pkg/store/consul/transaction"
 	"github.com/square/p2/pkg/types"
 	"github.com/square/p2/pkg/util"
@@ -37,6 +41,7 @@ const (
 	CmdDelete       = "delete"
 	CmdUpdate       = "update"
 	CmdTestSelector = "test-selector"
+	CmdStatus       = "status"
 
 	TimeoutNotSpecified = time.Duration(-1)
 )
@@ -82,6 +87,14 @@ var (
 	updateName          = cmdUpdate.Flag("name", "The cluster name (ie. staging, production)").String()
 	updateTimeout       = cmdUpdate.Flag("timeout", "Non-zero timeout for replicating hosts. e.g. 1m2s for 1 minute and 2 seconds").Default(TimeoutNotSpecified.String()).Duration()
 	updateEverywhere    = cmdUpdate.Flag("everywhere", "Sets selector to match everything regardless of its value").Bool()
+	updateMaxUnavail    = cmdUpdate.Flag("max-unavailable", "Roll out changes in batches with at most this many nodes, or percentage of nodes (e.g. 10%), unavailable at once").String()
+	updateMaxSurge      = cmdUpdate.Flag("max-surge", "Number or percentage of nodes to update on top of --max-unavailable, for pods whose old and new versions can run side by side").String()
+	updateBatchDelay    = cmdUpdate.Flag("batch-delay", "How long to wait between batches of a rolling strategy").Default(TimeoutNotSpecified.String()).Duration()
+	updateNoRolling     = cmdUpdate.Flag("no-rolling-strategy", "Remove the rolling strategy, so that changes are rolled out to all nodes at once").Bool()
+	updateFailThreshold = cmdUpdate.Flag("failure-threshold", "Disable the daemon set once more than this many nodes, or percentage of nodes (e.g. 10%), fail to become healthy within the timeout").String()
+	updateNoFailThresh  = cmdUpdate.Flag("no-failure-threshold", "Remove the failure threshold, so that failing nodes never disable the daemon set").Bool()
+	updateOverrides     = cmdUpdate.Flag("overrides", "Path to a JSON file with the ordered list of manifest overrides, each with a node_selector and a patch. Patched manifests are unsigned, so overrides cannot be used with a signed manifest").String()
+	updateNoOverrides   = cmdUpdate.Flag("no-overrides", "Remove the manifest overrides, so that every node gets the same manifest").Bool()
 
 	cmdTestSelector = kingpin.Command(CmdTestSelector, `
 		This will output the hosts that match the selector,
@@ -91,6 +104,11 @@ var (
 	)
 	testSelectorString     = cmdTestSelector.Flag("selector", "The raw selector represented as a string").String()
 	testSelectorEverywhere = cmdTestSelector.Flag("everywhere", "Sets selector to match everything regardless of its value").Bool()
+
+	cmdStatus   = kingpin.Command(CmdStatus, "Show the status of a daemon set as published by the daemon set farm.")
+	statusID    = cmdStatus.Arg("id", "The uuid for the daemon set").Required().String()
+	statusJSON  = cmdStatus.Flag("json", "output the status as JSON instead of a table").Short('j').Bool()
+	statusWatch = cmdStatus.Flag("watch", "keep printing the status each time it changes").Short('w').Bool()
 )
 
 func main() {
@@ -293,6 +311,82 @@ func main() {
 					ds.Manifest = manifest
 				}
 			}
+			if *updateNoRolling {
+				if ds.RollingStrategy != nil {
+					changed = true
+					ds.RollingStrategy = nil
+				}
+			} else if *updateMaxUnavail != "" || *updateMaxSurge != "" || *updateBatchDelay != TimeoutNotSpecified {
+				var strategy ds_fields.RollingStrategy
+				if ds.RollingStrategy != nil {
+					strategy = *ds.RollingStrategy
+				}
+				if *updateMaxUnavail != "" {
+					strategy.MaxUnavailable = ds_fields.IntOrPercent(*updateMaxUnavail)
+				}
+				if *updateMaxSurge != "" {
+					strategy.MaxSurge = ds_fields.IntOrPercent(*updateMaxSurge)
+				}
+				if *updateBatchDelay != TimeoutNotSpecified {
+					strategy.BatchDelay = *updateBatchDelay
+				}
+				if err := strategy.Validate(); err != nil {
+					return ds, util.Errorf("Invalid rolling strategy: %s", err)
+				}
+				if ds.RollingStrategy == nil || *ds.RollingStrategy != strategy {
+					changed = true
+					ds.RollingStrategy = &strategy
+				}
+			}
+			if *updateNoFailThresh {
+				if ds.FailureThreshold != "" {
+					changed = true
+					ds.FailureThreshold = ""
+				}
+			} else if *updateFailThreshold != "" {
+				threshold := ds_fields.IntOrPercent(*updateFailThreshold)
+				if _, err := threshold.Resolve(100, false); err != nil {
+					return ds, util.Errorf("Invalid failure threshold: %s", err)
+				}
+				if ds.FailureThreshold != threshold {
+					changed = true
+					ds.FailureThreshold = threshold
+				}
+			}
+			if *updateNoOverrides {
+				if len(ds.Overrides) > 0 {
+					changed = true
+					ds.Overrides = nil
+				}
+			} else if *updateOverrides != "" {
+				overrides, err := readOverrides(*updateOverrides, ds.Manifest)
+				if err != nil {
+					return ds, err
+				}
+				oldOverrides, err := json.Marshal(rawOverrides(ds.Overrides))
+				if err != nil {
+					return ds, util.Errorf("Could not marshal current overrides: %s", err)
+				}
+				newOverrides, err := json.Marshal(rawOverrides(overrides))
+				if err != nil {
+					return ds, util.Errorf("Could not marshal new overrides: %s", err)
+				}
+				if string(oldOverrides) != string(newOverrides) {
+					changed = true
+					ds.Overrides = overrides
+				}
+			} else if *updateManifest != "" {
+				// make sure the existing overrides still apply to
+				// the new manifest
+				if len(ds.Overrides) > 0 && isSigned(ds.Manifest) {
+					return ds, util.Errorf("The daemon set has manifest overrides, which cannot be used with a signed manifest. Remove them with --no-overrides")
+				}
+				for i, override := range ds.Overrides {
+					if _, err := override.Patch.Apply(ds.Manifest); err != nil {
+						return ds, util.Errorf("Override %d does not apply to the new manifest: %s", i, err)
+					}
+				}
+			}
 			if updateSelectorGiven {
 				selectorString := *updateSelector
 				if *updateEverywhere {
@@ -348,11 +442,118 @@ func main() {
 		}
 		fmt.Println(matches)
 
+	case CmdStatus:
+		id := ds_fields.ID(*statusID)
+		statusStore := daemonsetstatus.NewConsul(statusstore.NewConsul(client), ds.DaemonSetStatusNamespace)
+		status, queryMeta, err := statusStore.Get(id)
+		for {
+			switch {
+			case statusstore.IsNoStatus(err):
+				fmt.Printf("no status found for %s\n", id)
+			case err != nil:
+				log.Fatalf("could not fetch daemon set status: %v", err)
+			case *statusJSON:
+				bytes, err := json.MarshalIndent(status, "", "    ")
+				if err != nil {
+					log.Fatalf("could not print daemon set status as JSON: %v", err)
+				}
+				fmt.Printf("%s\n", bytes)
+			default:
+				printStatusTable(id, status)
+			}
+			if !*statusWatch {
+				break
+			}
+
+			var waitIndex uint64
+			if queryMeta != nil {
+				waitIndex = queryMeta.LastIndex
+			}
+			status, queryMeta, err = statusStore.Watch(id, waitIndex)
+		}
+
 	default:
 		log.Fatalf("Unrecognized command %v", cmd)
 	}
 }
 
+func printStatusTable(id ds_fields.ID, status daemonsetstatus.Status) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "DAEMON SET\t%s\n", id)
+	fmt.Fprintf(w, "MANIFEST SHA\t%s\n", status.ManifestSHA)
+	fmt.Fprintf(w, "ELIGIBLE\t%d\n", status.Nodes.Eligible)
+	fmt.Fprintf(w, "SCHEDULED\t%d\n", status.Nodes.Scheduled)
+	fmt.Fprintf(w, "CURRENT\t%d\n", status.Nodes.Current)
+	fmt.Fprintf(w, "HEALTHY\t%d\n", status.Nodes.Healthy)
+	fmt.Fprintf(w, "UNHEALTHY\t%d\n", status.Nodes.Unhealthy)
+	fmt.Fprintf(w, "PENDING\t%d\n", status.Nodes.Pending)
+	fmt.Fprintf(w, "FAILED\t%d\n", len(status.FailedNodes))
+	if status.FailureBudgetExceeded {
+		fmt.Fprintf(w, "FAILURE BUDGET\texceeded, daemon set was disabled\n")
+	}
+	if status.ContendsWith != "" {
+		fmt.Fprintf(w, "CONTENDS WITH\t%s\n", status.ContendsWith)
+	}
+	if status.Rolling.BlockingReason != "" {
+		fmt.Fprintf(w, "ROLLOUT BLOCKED\t%s\n", status.Rolling.BlockingReason)
+	}
+	for _, node := range status.Nodes.OldSHA {
+		fmt.Fprintf(w, "OLD SHA\t%s\n", node)
+	}
+	for _, node := range status.FailedNodes {
+		fmt.Fprintf(w, "FAILED NODE\t%s\n", node)
+	}
+	w.Flush()
+	fmt.Println()
+}
+
+// readOverrides reads a JSON list of manifest overrides from a file and
+// checks that each of them can be applied to the manifest. Overrides are
+// refused for a signed manifest, because the patched manifests would lose the
+// signature and fail verification on the nodes they apply to.
+func readOverrides(path string, man manifest.Manifest) ([]ds_fields.ManifestOverride, error) {
+	if isSigned(man) {
+		return nil, util.Errorf("Manifest overrides cannot be used with a signed manifest")
+	}
+
+	bytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, util.Errorf("Could not read overrides: %s", err)
+	}
+
+	var raw []ds_fields.RawManifestOverride
+	err = json.Unmarshal(bytes, &raw)
+	if err != nil {
+		return nil, util.Errorf("Could not parse overrides as JSON: %s", err)
+	}
+
+	var overrides []ds_fields.ManifestOverride
+	for i, rawOverride := range raw {
+		override, err := rawOverride.ToOverride()
+		if err != nil {
+			return nil, util.Errorf("Invalid override %d: %s", i, err)
+		}
+		if _, err := override.Patch.Apply(man); err != nil {
+			return nil, util.Errorf("Override %d does not apply to the manifest: %s", i, err)
+		}
+		overrides = append(overrides, override)
+	}
+	return overrides, nil
+}
+
+func isSigned(man manifest.Manifest) bool {
+	_, signature := man.SignatureData()
+	return len(signature) > 0
+}
+
+func rawOverrides(overrides []ds_fields.ManifestOverride) []ds_fields.RawManifestOverride {
+	var raw []ds_fields.RawManifestOverride
+	for _, override := range overrides {
+		raw = append(raw, override.ToRaw())
+	}
+	return raw
+}
+
 func parseNodeSelectorWithPrompt(
 	oldSelector klabels.Selector,
 	newSelectorString string,
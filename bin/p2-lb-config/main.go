@@ -0,0 +1,65 @@
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/square/p2/pkg/health/checker"
+	"github.com/square/p2/pkg/labels"
+	"github.com/square/p2/pkg/logging"
+	"github.com/square/p2/pkg/pc/lbconfig"
+	"github.com/square/p2/pkg/store/consul"
+	"github.com/square/p2/pkg/store/consul/flags"
+	"github.com/square/p2/pkg/store/consul/pcstore"
+)
+
+var (
+	proxy         = kingpin.Flag("proxy", "The proxy to write config for").Default(lbconfig.HAProxy{}.Name()).Enum(lbconfig.HAProxy{}.Name(), lbconfig.Envoy{}.Name())
+	outputDir     = kingpin.Flag("output-dir", "The directory to write one config file per pod cluster to. Other files with the proxy's config extension will be removed.").Required().ExistingDir()
+	reloadCommand = kingpin.Flag("reload-command", "A shell command to run after the config in the output directory changes, e.g. to reload the proxy").String()
+)
+
+func main() {
+	_, consulOpts, labeler := flags.ParseWithConsulOptions()
+	client := consul.NewConsulClient(consulOpts)
+	logger := logging.NewLogger(logrus.Fields{})
+
+	renderer, err := lbconfig.RendererFor(*proxy)
+	if err != nil {
+		logger.WithError(err).Fatalln("Could not configure syncer")
+	}
+
+	pcStore := pcstore.NewConsul(
+		client,
+		labeler,
+		labels.DefaultAggregationRate,
+		labels.NewConsulApplicator(client, 0, 1*time.Minute),
+		&logger,
+	)
+	syncer := lbconfig.NewSyncer(
+		renderer,
+		*outputDir,
+		*reloadCommand,
+		consul.NewConsulStore(client),
+		checker.NewHealthChecker(client),
+		logger,
+	)
+
+	quitCh := make(chan struct{})
+	go func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		<-signals
+		close(quitCh)
+	}()
+
+	err = pcStore.WatchAndSync(syncer, quitCh)
+	if err != nil {
+		logger.WithError(err).Fatalln("Could not sync pod clusters")
+	}
+}
@@ -60,6 +60,11 @@ func main() {
 	}
 	defer prep.Close()
 
+	err = preparer.ReportCapacity(preparerConfig, logger)
+	if err != nil {
+		logger.WithError(err).Warnln("Could not report node capacity")
+	}
+
 	logger.WithFields(logrus.Fields{
 		"starting":    true,
 		"node_name":   preparerConfig.NodeName,
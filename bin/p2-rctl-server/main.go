@@ -16,6 +16,7 @@ import (
 
 	"github.com/square/p2/pkg/alerting"
 	"github.com/square/p2/pkg/artifact"
+	"github.com/square/p2/pkg/autoscale"
 	"github.com/square/p2/pkg/health/checker"
 	"github.com/square/p2/pkg/logging"
 	"github.com/square/p2/pkg/osversion"
@@ -27,9 +28,12 @@ import (
 	"github.com/square/p2/pkg/store/consul/consulutil"
 	"github.com/square/p2/pkg/store/consul/flags"
 	"github.com/square/p2/pkg/store/consul/rcstore"
+	"github.com/square/p2/pkg/store/consul/reservationstore"
 	"github.com/square/p2/pkg/store/consul/rollstore"
 	"github.com/square/p2/pkg/store/consul/statusstore"
+	"github.com/square/p2/pkg/store/consul/statusstore/autoscalestatus"
 	"github.com/square/p2/pkg/store/consul/statusstore/rcstatus"
+	"github.com/square/p2/pkg/store/consul/statusstore/rollstatus"
 	"github.com/square/p2/pkg/uri"
 	"github.com/square/p2/pkg/util/stream"
 	"github.com/square/p2/pkg/version"
@@ -39,6 +43,10 @@ import (
 var (
 	logLevel            = kingpin.Flag("log", "Logging level to display").String()
 	pagerdutyServiceKey = kingpin.Flag("pagerduty-service-key", "Pagerduty Service Key to use for alerting if provided").String()
+	resourceScheduling  = kingpin.Flag("resource-scheduling", "Only allocate nodes with room for a pod's cgroup, according to their capacity labels").Bool()
+	schedulingPolicy    = kingpin.Flag("scheduling-policy", "With --resource-scheduling, whether to fill nodes in turn (binpack) or evenly (spread)").Default(string(scheduler.BinpackPolicy)).String()
+	canaryJudgeURL      = kingpin.Flag("canary-judge-url", "If provided, rolling updates with canaries ask this URL for a verdict on them once they have baked").String()
+	autoscaleInterval   = kingpin.Flag("autoscale-interval", "How often replication controllers with an autoscale policy are evaluated").Default("30s").Duration()
 )
 
 // RetryCount defines the number of retries to attempt when accessing some storage
@@ -80,10 +88,19 @@ func main() {
 	consulStore := consul.NewConsulStore(client)
 	rcStore := rcstore.NewConsul(client, labeler, RetryCount)
 	rcStatusStore := rcstatus.NewConsul(statusStoreClient, consul.RCStatusNamespace)
+	autoscaleStatusStore := autoscalestatus.NewConsul(statusStoreClient, consul.AutoscaleStatusNamespace)
+	rollStatusStore := rollstatus.NewConsul(statusStoreClient, consul.RollStatusNamespace)
 
 	rollStore := rollstore.NewConsul(client, labeler, nil)
 	healthChecker := checker.NewHealthChecker(client)
-	sched := scheduler.NewApplicatorScheduler(labeler)
+	var sched rc.Scheduler = scheduler.NewApplicatorScheduler(labeler)
+	if *resourceScheduling {
+		policy, err := scheduler.ParsePolicy(*schedulingPolicy)
+		if err != nil {
+			logger.WithError(err).Fatalln("Invalid scheduling policy")
+		}
+		sched = scheduler.NewResourceScheduler(labeler, consulStore, reservationstore.NewConsul(client, RetryCount), policy)
+	}
 
 	// Start acquiring sessions
 	sessions := make(chan string)
@@ -135,12 +152,39 @@ func main() {
 		artifactRegistry,
 		nil,
 	).Start(nil)
+	go autoscale.NewFarm(
+		consulStore,
+		rcStore,
+		rcStore,
+		rollStore,
+		autoscaleStatusStore,
+		auditLogStore,
+		client.KV(),
+		autoscale.NewMetricSourceFactory(httpClient, labeler),
+		pub.Subscribe().Chan(),
+		logger,
+		*autoscaleInterval,
+	).Start(nil)
+	var canaryJudge roll.CanaryJudge
+	if *canaryJudgeURL != "" {
+		canaryJudge = roll.NewHTTPCanaryJudge(httpClient, *canaryJudgeURL)
+	}
 	roll.NewFarm(
 		roll.UpdateFactory{
-			Store:         consulStore,
-			RCStore:       rcStore,
-			HealthChecker: healthChecker,
-			Labeler:       labeler,
+			Store:                       consulStore,
+			Client:                      client,
+			Txner:                       client.KV(),
+			RCLocker:                    rcStore,
+			RCStore:                     rcStore,
+			RCStatusStore:               rcStatusStore,
+			RollStore:                   rollStore,
+			RollStatusStore:             rollStatusStore,
+			HealthChecker:               healthChecker,
+			Labeler:                     labeler,
+			Alerter:                     alerter,
+			ShouldCreateAuditLogRecords: true,
+			AuditLogStore:               auditLogStore,
+			CanaryJudge:                 canaryJudge,
 		},
 		consulStore,
 		rollStore,
@@ -7,6 +7,10 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"os/user"
+	"sort"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -17,6 +21,7 @@ import (
 	klabels "k8s.io/kubernetes/pkg/labels"
 
 	"github.com/square/p2/pkg/alerting"
+	"github.com/square/p2/pkg/audit"
 	"github.com/square/p2/pkg/cli"
 	"github.com/square/p2/pkg/health/checker"
 	"github.com/square/p2/pkg/labels"
@@ -36,7 +41,9 @@ import (
 	"github.com/square/p2/pkg/store/consul/rollstore"
 	"github.com/square/p2/pkg/store/consul/statusstore"
 	"github.com/square/p2/pkg/store/consul/statusstore/rcstatus"
+	"github.com/square/p2/pkg/store/consul/statusstore/rollstatus"
 	"github.com/square/p2/pkg/store/consul/transaction"
+	"github.com/square/p2/pkg/types"
 	"github.com/square/p2/pkg/version"
 )
 
@@ -54,6 +61,13 @@ const (
 	cmdSchedupText        = "schedule-update"
 	cmdUpdateManifestText = "update-manifest"
 	cmdUpdateStrategyText = "update-strategy"
+	cmdSetSpreadText      = "set-spread"
+	cmdHistoryText        = "history"
+	cmdRollbackText       = "rollback"
+	cmdSetAutoscaleText   = "set-autoscale"
+	cmdRollPauseText      = "roll-pause"
+	cmdRollResumeText     = "roll-resume"
+	cmdRollStatusText     = "roll-status"
 )
 
 var (
@@ -85,8 +99,9 @@ var (
 	getID       = cmdGet.Arg("id", "replication controller uuid to get").Required().String()
 	getManifest = cmdGet.Flag("manifest", "print just the manifest of the replication controller").Short('m').Bool()
 
-	cmdGetStatus = kingpin.Command(cmdGetStatusText, "Get the status entry for a replication controller")
-	getStatusID  = cmdGetStatus.Arg("id", "uuid of replication controller whose status should be fetched").Required().String()
+	cmdGetStatus   = kingpin.Command(cmdGetStatusText, "Get the status entry for a replication controller")
+	getStatusID    = cmdGetStatus.Arg("id", "uuid of replication controller whose status should be fetched").Required().String()
+	getStatusWatch = cmdGetStatus.Flag("watch", "keep printing the status each time it changes").Short('w').Bool()
 
 	cmdEnable = kingpin.Command(cmdEnableText, "Enable replication controller")
 	enableID  = cmdEnable.Arg("id", "replication controller uuid to enable").Required().String()
@@ -103,11 +118,21 @@ var (
 	cmdDeleteRoll = kingpin.Command(cmdDeleteRollText, "Delete a rolling update.")
 	deleteRollID  = cmdDeleteRoll.Flag("id", "rolling update uuid").Required().Short('i').String()
 
-	cmdSchedup   = kingpin.Command(cmdSchedupText, "Schedule new rolling update (will be run by farm)")
-	schedupOldID = cmdSchedup.Flag("old", "old replication controller uuid").Required().Short('o').String()
-	schedupNewID = cmdSchedup.Flag("new", "new replication controller uuid").Required().Short('n').String()
-	schedupWant  = cmdSchedup.Flag("desired", "number of replicas desired").Required().Short('d').Int()
-	schedupNeed  = cmdSchedup.Flag("minimum", "minimum number of healthy replicas during update").Required().Short('m').Int()
+	cmdSchedup      = kingpin.Command(cmdSchedupText, "Schedule new rolling update (will be run by farm)")
+	schedupOldID    = cmdSchedup.Flag("old", "old replication controller uuid").Required().Short('o').String()
+	schedupNewID    = cmdSchedup.Flag("new", "new replication controller uuid").Required().Short('n').String()
+	schedupWant     = cmdSchedup.Flag("desired", "number of replicas desired").Required().Short('d').Int()
+	schedupNeed     = cmdSchedup.Flag("minimum", "minimum number of healthy replicas during update").Required().Short('m').Int()
+	schedupCanaries = cmdSchedup.Flag("canaries", "number of replicas to update first and bake before updating the rest. The update is aborted if they fail").Int()
+	schedupBake     = cmdSchedup.Flag("canary-bake", "how long canaries must stay healthy before the rest of the replicas are updated").Duration()
+	schedupDeadline = cmdSchedup.Flag("progress-deadline", "abort the update if it makes no progress for this long").Duration()
+	schedupMaxBad   = cmdSchedup.Flag("max-unhealthy", "abort the update if more than this many new replicas are unhealthy at once. Negative means no limit").Default("-1").Int()
+	schedupMaxBadT  = cmdSchedup.Flag("max-unhealthy-duration", "abort the update if a new replica stays unhealthy for this long").Duration()
+	schedupRollback = cmdSchedup.Flag("rollback", "schedule an update back to the old RC if the update is aborted by its failure policy").Bool()
+	schedupZoneKey  = cmdSchedup.Flag("zone-label", "node label, such as an availability zone, to roll one value of at a time").String()
+	schedupZones    = cmdSchedup.Flag("zone-order", "comma separated order of the zones to roll. Defaults to the sorted zones of the RCs' nodes").String()
+	schedupZoneWait = cmdSchedup.Flag("zone-pause", "how long to wait after finishing a zone before starting the next").Duration()
+	schedupZoneOK   = cmdSchedup.Flag("zone-require-healthy", "wait for all new replicas to be healthy before starting the next zone").Bool()
 
 	cmdUpdateManifest  = kingpin.Command(cmdUpdateManifestText, "DANGEROUS. Forcefully update the manifest for the given RC. Consider disabling the RC before invoking this command.")
 	updateManifestRCID = cmdUpdateManifest.Arg("id", "replication controller uuid to update").Required().String()
@@ -116,6 +141,44 @@ var (
 	cmdUpdateStrategy  = kingpin.Command(cmdUpdateStrategyText, "Forcefully update the allocation strategy in the manifest.")
 	updateStrategyRCID = cmdUpdateStrategy.Flag("id", "replication controller uuid to update").Required().String()
 	updateStrategy     = cmdUpdateStrategy.Flag("strategy", "allocation strategy to use for the replication controller").Required().String()
+
+	cmdSetSpread  = kingpin.Command(cmdSetSpreadText, "Set the topology spread constraints of a replication controller. Passing no constraints removes them.")
+	setSpreadRCID = cmdSetSpread.Arg("id", "replication controller uuid to update").Required().String()
+	setSpreadKeys = cmdSetSpread.Flag("spread", "a spread constraint, in NODE_LABEL=MAX_SKEW form, e.g. availability_zone=1. Can be specified multiple times.").Short('s').StringMap()
+
+	cmdHistory      = kingpin.Command(cmdHistoryText, "List the manifest revisions of a replication controller")
+	historyID       = cmdHistory.Arg("id", "replication controller uuid whose history should be listed").Required().String()
+	historyRevision = cmdHistory.Flag("revision", "print just the manifest of the given revision").Short('r').Int()
+
+	cmdRollback         = kingpin.Command(cmdRollbackText, "Schedule a rolling update from a replication controller to a new one running a previous revision of its manifest")
+	rollbackID          = cmdRollback.Arg("id", "replication controller uuid to roll back").Required().String()
+	rollbackToRevision  = cmdRollback.Flag("to-revision", "revision to roll back to, as listed by the history command").Required().Int()
+	rollbackWant        = cmdRollback.Flag("desired", "number of replicas desired. Defaults to the current replica count of the replication controller").Short('d').Int()
+	rollbackNeed        = cmdRollback.Flag("minimum", "minimum number of healthy replicas during update").Required().Short('m').Int()
+	rollbackConfirmSkip = cmdRollback.Flag("yes", "auto confirm the rollback (i.e. no confirmation prompt)").Short('y').Bool()
+
+	cmdSetAutoscale        = kingpin.Command(cmdSetAutoscaleText, "Set the autoscale policy of a replication controller, which lets the autoscaler adjust its replica count")
+	setAutoscaleRCID       = cmdSetAutoscale.Arg("id", "replication controller uuid to update").Required().String()
+	setAutoscaleRemove     = cmdSetAutoscale.Flag("remove", "remove the autoscale policy, leaving the replica count as it is").Bool()
+	setAutoscaleMin        = cmdSetAutoscale.Flag("min", "minimum replica count").Int()
+	setAutoscaleMax        = cmdSetAutoscale.Flag("max", "maximum replica count").Int()
+	setAutoscaleTarget     = cmdSetAutoscale.Flag("target", "per-replica metric value to aim for").Float64()
+	setAutoscaleCooldown   = cmdSetAutoscale.Flag("cooldown", "minimum time between two changes to the replica count").Default("5m").Duration()
+	setAutoscaleMetricType = cmdSetAutoscale.Flag("metric-type", "where to read the metric from: a single URL (http) or each of the pods (pod)").Default(string(fields.HTTPMetricSource)).Enum(string(fields.HTTPMetricSource), string(fields.PodMetricSource))
+	setAutoscaleMetricURL  = cmdSetAutoscale.Flag("metric-url", "URL to read the metric from, for the http metric type").String()
+	setAutoscaleMetricPath = cmdSetAutoscale.Flag("metric-path", "path to request from each pod, for the pod metric type").String()
+	setAutoscaleMetricPort = cmdSetAutoscale.Flag("metric-port", "port to request the metric path from. Defaults to the pod's status port").Int()
+
+	cmdRollPause    = kingpin.Command(cmdRollPauseText, "Pause a rolling update. The farm stops moving replicas to the new RC until it is resumed")
+	rollPauseID     = cmdRollPause.Arg("id", "rolling update uuid to pause").Required().String()
+	rollPauseReason = cmdRollPause.Flag("reason", "why the rolling update is being paused").Required().String()
+
+	cmdRollResume = kingpin.Command(cmdRollResumeText, "Resume a paused rolling update")
+	rollResumeID  = cmdRollResume.Arg("id", "rolling update uuid to resume").Required().String()
+
+	cmdRollStatus   = kingpin.Command(cmdRollStatusText, "Show the progress of a rolling update as published by the roll farm")
+	rollStatusID    = cmdRollStatus.Arg("id", "rolling update uuid whose status should be fetched").Required().String()
+	rollStatusWatch = cmdRollStatus.Flag("watch", "keep printing the status each time it changes").Short('w').Bool()
 )
 
 func main() {
@@ -147,6 +210,7 @@ func main() {
 
 	rcStore := rcstore.NewConsul(client, labeler, 3)
 	rcStatusStore := rcstatus.NewConsul(statusstore.NewConsul(client), consul.RCStatusNamespace)
+	rollStatusStore := rollstatus.NewConsul(statusstore.NewConsul(client), consul.RollStatusNamespace)
 
 	// The roll labeler CANT be an http applicator because it uses consul
 	// transactions, so this might be different from labeler returned by
@@ -164,6 +228,9 @@ func main() {
 		rollRCStatusStore: rcStatusStore,
 		rcLocker:          rcStore,
 		rls:               rollstore.NewConsul(client, rollLabeler, nil),
+		rollStatusStore:   rollStatusStore,
+		rollLabeler:       rollLabeler,
+		auditLogStore:     auditlogstore.NewConsulStore(client.KV()),
 		consuls:           consul.NewConsulStore(client),
 		labeler:           labeler,
 		hcheck:            checker.NewHealthChecker(client),
@@ -190,7 +257,7 @@ func main() {
 	case cmdGetText:
 		rctl.Get(*getID, *getManifest)
 	case cmdGetStatusText:
-		rctl.GetStatus(*getStatusID)
+		rctl.GetStatus(*getStatusID, *getStatusWatch)
 	case cmdEnableText:
 		rctl.Enable(*enableID)
 	case cmdDisableText:
@@ -198,13 +265,42 @@ func main() {
 	case cmdRollText:
 		rctl.RollingUpdate(*rollOldID, *rollNewID, *rollWant, *rollNeed)
 	case cmdSchedupText:
-		rctl.ScheduleUpdate(*schedupOldID, *schedupNewID, *schedupWant, *schedupNeed, client.KV())
+		rctl.ScheduleUpdate(*schedupOldID, *schedupNewID, *schedupWant, *schedupNeed, *schedupCanaries, *schedupBake, schedupFailurePolicy(), schedupZoneSequence(), client.KV())
 	case cmdDeleteRollText:
 		rctl.DeleteRollingUpdate(*deleteRollID, client.KV())
+	case cmdRollPauseText:
+		rctl.PauseRollingUpdate(roll_fields.ID(*rollPauseID), *rollPauseReason, client.KV())
+	case cmdRollResumeText:
+		rctl.ResumeRollingUpdate(roll_fields.ID(*rollResumeID), client.KV())
+	case cmdRollStatusText:
+		rctl.RollStatus(roll_fields.ID(*rollStatusID), *rollStatusWatch)
 	case cmdUpdateManifestText:
 		rctl.UpdateManifest(fields.ID(*updateManifestRCID), *updateManifestPath)
 	case cmdUpdateStrategyText:
 		rctl.UpdateStrategy(fields.ID(*updateStrategyRCID), fields.Strategy(*updateStrategy))
+	case cmdSetSpreadText:
+		rctl.SetSpread(fields.ID(*setSpreadRCID), *setSpreadKeys)
+	case cmdHistoryText:
+		rctl.History(fields.ID(*historyID), *historyRevision)
+	case cmdRollbackText:
+		rctl.Rollback(fields.ID(*rollbackID), *rollbackToRevision, *rollbackWant, *rollbackNeed, client.KV())
+	case cmdSetAutoscaleText:
+		if *setAutoscaleRemove {
+			rctl.SetAutoscale(fields.ID(*setAutoscaleRCID), nil)
+			break
+		}
+		rctl.SetAutoscale(fields.ID(*setAutoscaleRCID), &fields.AutoscalePolicy{
+			MinReplicas: *setAutoscaleMin,
+			MaxReplicas: *setAutoscaleMax,
+			Target:      *setAutoscaleTarget,
+			Cooldown:    *setAutoscaleCooldown,
+			Metric: fields.MetricSource{
+				Type: fields.MetricSourceType(*setAutoscaleMetricType),
+				URL:  *setAutoscaleMetricURL,
+				Path: *setAutoscaleMetricPath,
+				Port: *setAutoscaleMetricPort,
+			},
+		})
 	}
 }
 
@@ -219,13 +315,25 @@ func SessionName() string {
 	return fmt.Sprintf("p2-rctl:%s:%s", hostname, timeStr)
 }
 
+// currentUserName returns the name recorded as the author of manifest
+// revisions made by this invocation.
+func currentUserName() string {
+	username := "unknown user"
+
+	if user, err := user.Current(); err == nil {
+		username = user.Username
+	}
+	return username
+}
+
 type Store interface {
 	// for passing into a roll farm
 	roll.Store
 }
 
 type ReplicationControllerStore interface {
-	Create(
+	CreateTxn(
+		ctx context.Context,
 		manifest manifest.Manifest,
 		nodeSelector klabels.Selector,
 		availabilityZone pc_fields.AvailabilityZone,
@@ -240,18 +348,49 @@ type ReplicationControllerStore interface {
 	Disable(id fields.ID) error
 	Delete(id fields.ID, force bool) error
 	Get(id fields.ID) (fields.RC, error)
-	UpdateManifest(id fields.ID, man manifest.Manifest) error
+	UpdateManifestTxn(ctx context.Context, id fields.ID, man manifest.Manifest) error
 	UpdateStrategy(id fields.ID, strategy fields.Strategy) error
+	UpdateSpreadConstraints(id fields.ID, constraints []fields.SpreadConstraint) error
+	UpdateAutoscalePolicy(id fields.ID, policy *fields.AutoscalePolicy) error
+	History(id fields.ID) ([]fields.Revision, error)
+	GetRevision(id fields.ID, number int) (fields.Revision, error)
 }
 
 type RollingUpdateStore interface {
 	Delete(ctx context.Context, id roll_fields.ID) error
 	CreateRollingUpdateFromExistingRCs(ctx context.Context, u roll_fields.Update, newRCLabels klabels.Set, rollLabels klabels.Set) (roll_fields.Update, error)
+	CreateRollingUpdateFromOneExistingRCWithID(
+		ctx context.Context,
+		oldRCID rc_fields.ID,
+		desiredReplicas int,
+		minimumReplicas int,
+		leaveOld bool,
+		rollDelay time.Duration,
+		availabilityZone pc_fields.AvailabilityZone,
+		clusterName pc_fields.ClusterName,
+		newRCManifest manifest.Manifest,
+		newRCNodeSelector klabels.Selector,
+		newRCPodLabels klabels.Set,
+		newRCLabels klabels.Set,
+		rollLabels klabels.Set,
+		newAllocationStrategy rc_fields.Strategy,
+	) (roll_fields.Update, error)
 	Watch(quit <-chan struct{}, jitterWindow time.Duration) (<-chan []roll_fields.Update, <-chan error)
+	ReplaceTxn(ctx context.Context, id roll_fields.ID, replacement roll_fields.Update) error
+	Get(id roll_fields.ID) (roll_fields.Update, error)
+	PauseTxn(ctx context.Context, id roll_fields.ID, reason string, user string) error
+	ResumeTxn(ctx context.Context, id roll_fields.ID) error
 }
 
 type RCStatusStore interface {
 	Get(rcID rc_fields.ID) (rcstatus.Status, *api.QueryMeta, error)
+	Watch(rcID rc_fields.ID, waitIndex uint64) (rcstatus.Status, *api.QueryMeta, error)
+}
+
+type RollStatusStore interface {
+	roll.RollStatusStore
+	Get(id roll_fields.ID) (rollstatus.Status, *api.QueryMeta, error)
+	Watch(id roll_fields.ID, waitIndex uint64) (rollstatus.Status, *api.QueryMeta, error)
 }
 
 // rctl is a struct for the data structures shared between commands
@@ -267,6 +406,9 @@ type rctlParams struct {
 	rcLocker          roll.ReplicationControllerLocker
 	rcWatcher         rc.ReplicationControllerWatcher
 	rls               RollingUpdateStore
+	rollStatusStore   RollStatusStore
+	rollLabeler       audit.Labeler
+	auditLogStore     auditlogstore.ConsulStore
 	labeler           labels.ApplicatorWithoutWatches
 	consuls           Store
 	hcheck            checker.HealthChecker
@@ -296,7 +438,20 @@ func (r rctlParams) Create(
 		}).Fatalln("Could not parse node selector")
 	}
 
-	newRC, err := r.rcs.Create(manifest, nodeSel, availabilityZone, clusterName, klabels.Set(podLabels), rcLabels, allocationStrategy)
+	if podLabels == nil {
+		podLabels = make(map[string]string)
+	}
+	podLabels[types.ClusterNameLabel] = clusterName.String()
+	podLabels[types.AvailabilityZoneLabel] = availabilityZone.String()
+
+	ctx, cancelFunc := transaction.New(rcstore.WithAuthor(context.Background(), currentUserName()))
+	defer cancelFunc()
+	newRC, err := r.rcs.CreateTxn(ctx, manifest, nodeSel, availabilityZone, clusterName, klabels.Set(podLabels), rcLabels, allocationStrategy)
+	if err != nil {
+		r.logger.WithError(err).Fatalln("Could not create replication controller in Consul")
+	}
+
+	err = transaction.MustCommit(ctx, r.baseClient.KV())
 	if err != nil {
 		r.logger.WithError(err).Fatalln("Could not create replication controller in Consul")
 	}
@@ -325,6 +480,58 @@ func (r rctlParams) DeleteRollingUpdate(id string, txner transaction.Txner) {
 	}
 }
 
+func (r rctlParams) PauseRollingUpdate(id roll_fields.ID, reason string, txner transaction.Txner) {
+	user := currentUserName()
+	ctx, cancelFunc := transaction.New(context.Background())
+	defer cancelFunc()
+	err := r.rls.PauseTxn(ctx, id, reason, user)
+	if err != nil {
+		r.logger.WithError(err).Fatalln("Could not pause RU")
+	}
+
+	details, err := audit.NewRUPauseEventDetails(id, user, reason, r.rollLabeler)
+	if err != nil {
+		r.logger.WithError(err).Fatalln("Could not create audit log record for pausing RU")
+	}
+	err = r.auditLogStore.Create(ctx, audit.RUPauseEvent, details)
+	if err != nil {
+		r.logger.WithError(err).Fatalln("Could not create audit log record for pausing RU")
+	}
+
+	err = transaction.MustCommit(ctx, txner)
+	if err != nil {
+		r.logger.WithError(err).Fatalln("Could not pause RU. Consider a retry.")
+	}
+
+	r.logger.WithField("id", id).Infoln("Paused rolling update")
+}
+
+func (r rctlParams) ResumeRollingUpdate(id roll_fields.ID, txner transaction.Txner) {
+	user := currentUserName()
+	ctx, cancelFunc := transaction.New(context.Background())
+	defer cancelFunc()
+	err := r.rls.ResumeTxn(ctx, id)
+	if err != nil {
+		r.logger.WithError(err).Fatalln("Could not resume RU")
+	}
+
+	details, err := audit.NewRUResumeEventDetails(id, user, r.rollLabeler)
+	if err != nil {
+		r.logger.WithError(err).Fatalln("Could not create audit log record for resuming RU")
+	}
+	err = r.auditLogStore.Create(ctx, audit.RUResumeEvent, details)
+	if err != nil {
+		r.logger.WithError(err).Fatalln("Could not create audit log record for resuming RU")
+	}
+
+	err = transaction.MustCommit(ctx, txner)
+	if err != nil {
+		r.logger.WithError(err).Fatalln("Could not resume RU. Consider a retry.")
+	}
+
+	r.logger.WithField("id", id).Infoln("Resumed rolling update")
+}
+
 func (r rctlParams) SetReplicas(id string, replicas int) {
 	if replicas < 0 {
 		r.logger.NoFields().Fatalln("Cannot set negative replica count")
@@ -384,21 +591,58 @@ func (r rctlParams) Get(id string, manifest bool) {
 	}
 }
 
-func (r rctlParams) GetStatus(id string) {
-	status, _, err := r.rcStatusStore.Get(rc_fields.ID(id))
-	switch {
-	case statusstore.IsNoStatus(err):
-		fmt.Printf("no status found for %s\n", id)
-		return
-	case err != nil:
-		r.logger.WithError(err).Fatalln("could not fetch RC status")
+func (r rctlParams) GetStatus(id string, watch bool) {
+	status, queryMeta, err := r.rcStatusStore.Get(rc_fields.ID(id))
+	for {
+		switch {
+		case statusstore.IsNoStatus(err):
+			fmt.Printf("no status found for %s\n", id)
+		case err != nil:
+			r.logger.WithError(err).Fatalln("could not fetch RC status")
+		default:
+			out, err := json.MarshalIndent(status, "", "    ")
+			if err != nil {
+				r.logger.WithError(err).Fatalln("could not print rc status as JSON")
+			}
+			fmt.Printf("%s\n", out)
+		}
+		if !watch {
+			return
+		}
+
+		var waitIndex uint64
+		if queryMeta != nil {
+			waitIndex = queryMeta.LastIndex
+		}
+		status, queryMeta, err = r.rcStatusStore.Watch(rc_fields.ID(id), waitIndex)
 	}
+}
 
-	out, err := json.MarshalIndent(status, "", "    ")
-	if err != nil {
-		r.logger.WithError(err).Fatalln("could not print rc status as JSON")
+func (r rctlParams) RollStatus(id roll_fields.ID, watch bool) {
+	status, queryMeta, err := r.rollStatusStore.Get(id)
+	for {
+		switch {
+		case statusstore.IsNoStatus(err):
+			fmt.Printf("no status found for %s\n", id)
+		case err != nil:
+			r.logger.WithError(err).Fatalln("could not fetch rolling update status")
+		default:
+			out, err := json.MarshalIndent(status, "", "    ")
+			if err != nil {
+				r.logger.WithError(err).Fatalln("could not print rolling update status as JSON")
+			}
+			fmt.Printf("%s\n", out)
+		}
+		if !watch {
+			return
+		}
+
+		var waitIndex uint64
+		if queryMeta != nil {
+			waitIndex = queryMeta.LastIndex
+		}
+		status, queryMeta, err = r.rollStatusStore.Watch(id, waitIndex)
 	}
-	fmt.Printf("%s\n", out)
 }
 
 func (r rctlParams) Enable(id string) {
@@ -459,6 +703,7 @@ func (r rctlParams) RollingUpdate(oldID, newID string, want, need int) {
 			r.rollRCStore,
 			r.rollRCStatusStore,
 			r.rls,
+			r.rollStatusStore,
 			r.baseClient.KV(),
 			r.hcheck,
 			r.labeler,
@@ -468,6 +713,7 @@ func (r rctlParams) RollingUpdate(oldID, newID string, want, need int) {
 			alerting.NewNop(),
 			false,                       // no audit logging
 			auditlogstore.ConsulStore{}, // no audit logging
+			nil,                         // no canary judge
 		).Run(ctx)
 		close(result)
 	}()
@@ -498,7 +744,57 @@ LOOP:
 	}
 }
 
-func (r rctlParams) ScheduleUpdate(oldID, newID string, want, need int, txner transaction.Txner) {
+// schedupFailurePolicy builds the failure policy for a scheduled update from
+// the command line, or returns nil if none of its flags were passed
+func schedupFailurePolicy() *roll_fields.FailurePolicy {
+	if *schedupDeadline == 0 && *schedupMaxBad < 0 && *schedupMaxBadT == 0 && !*schedupRollback {
+		return nil
+	}
+
+	policy := &roll_fields.FailurePolicy{
+		ProgressDeadline:     *schedupDeadline,
+		MaxUnhealthyDuration: *schedupMaxBadT,
+		Rollback:             *schedupRollback,
+	}
+	if *schedupMaxBad >= 0 {
+		maxUnhealthy := *schedupMaxBad
+		policy.MaxUnhealthy = &maxUnhealthy
+	}
+	return policy
+}
+
+// schedupZoneSequence builds the zone sequence for a scheduled update from the
+// command line, or returns nil if no zone label was passed
+func schedupZoneSequence() *roll_fields.ZoneSequence {
+	if *schedupZoneKey == "" {
+		if *schedupZones != "" || *schedupZoneWait != 0 || *schedupZoneOK {
+			kingpin.Fatalf("--zone-label is required to sequence an update by zone")
+		}
+		return nil
+	}
+
+	seq := &roll_fields.ZoneSequence{
+		Label:          *schedupZoneKey,
+		Pause:          *schedupZoneWait,
+		RequireHealthy: *schedupZoneOK,
+	}
+	for _, zone := range strings.Split(*schedupZones, ",") {
+		zone = strings.TrimSpace(zone)
+		if zone != "" {
+			seq.Order = append(seq.Order, zone)
+		}
+	}
+	return seq
+}
+
+func (r rctlParams) ScheduleUpdate(oldID, newID string, want, need int, canaries int, bake time.Duration, failurePolicy *roll_fields.FailurePolicy, zoneSequence *roll_fields.ZoneSequence, txner transaction.Txner) {
+	if canaries < 0 || (canaries > 0 && canaries >= want) {
+		r.logger.WithFields(logrus.Fields{
+			"want":     want,
+			"canaries": canaries,
+		}).Fatalln("Canary count must be positive and less than desired replicas")
+	}
+
 	ctx, cancelFunc := transaction.New(context.Background())
 	defer cancelFunc()
 	_, err := r.rls.CreateRollingUpdateFromExistingRCs(
@@ -508,6 +804,10 @@ func (r rctlParams) ScheduleUpdate(oldID, newID string, want, need int, txner tr
 			NewRC:           rc_fields.ID(newID),
 			DesiredReplicas: want,
 			MinimumReplicas: need,
+			CanaryReplicas:  canaries,
+			CanaryBake:      bake,
+			FailurePolicy:   failurePolicy,
+			ZoneSequence:    zoneSequence,
 		}, nil, nil)
 	if err != nil {
 		r.logger.WithError(err).Fatalln("Could not create rolling update")
@@ -523,8 +823,20 @@ func (r rctlParams) ScheduleUpdate(oldID, newID string, want, need int, txner tr
 
 func (r rctlParams) UpdateManifest(id fields.ID, manifestPath string) {
 	man, err := manifest.FromPath(manifestPath)
+	if err != nil {
+		r.logger.WithErrorAndFields(err, logrus.Fields{
+			"manifest": manifestPath,
+		}).Fatalln("Could not read pod manifest")
+	}
 
-	err = r.rcs.UpdateManifest(id, man)
+	ctx, cancelFunc := transaction.New(rcstore.WithAuthor(context.Background(), currentUserName()))
+	defer cancelFunc()
+	err = r.rcs.UpdateManifestTxn(ctx, id, man)
+	if err != nil {
+		r.logger.WithError(err).Fatalln("Manifest update failed! Please retry after checking the database")
+	}
+
+	err = transaction.MustCommit(ctx, r.baseClient.KV())
 	if err != nil {
 		r.logger.WithError(err).Fatalln("Manifest update failed! Please retry after checking the database")
 	}
@@ -536,3 +848,157 @@ func (r rctlParams) UpdateStrategy(id fields.ID, strategy fields.Strategy) {
 		r.logger.WithError(err).Fatalln("Strategy update failed")
 	}
 }
+
+func (r rctlParams) SetSpread(id fields.ID, spread map[string]string) {
+	var constraints []fields.SpreadConstraint
+	for key, maxSkewStr := range spread {
+		maxSkew, err := strconv.Atoi(maxSkewStr)
+		if err != nil {
+			r.logger.WithError(err).Fatalf("Could not parse max skew for %s", key)
+		}
+		constraints = append(constraints, fields.SpreadConstraint{
+			TopologyKey: key,
+			MaxSkew:     maxSkew,
+		})
+	}
+	// Constraints are applied in order, so make it independent of the
+	// order the flags were given in
+	sort.Slice(constraints, func(i, j int) bool {
+		return constraints[i].TopologyKey < constraints[j].TopologyKey
+	})
+
+	err := r.rcs.UpdateSpreadConstraints(id, constraints)
+	if err != nil {
+		r.logger.WithError(err).Fatalln("Spread constraint update failed")
+	}
+	r.logger.WithFields(logrus.Fields{
+		"id":     id,
+		"spread": spread,
+	}).Infoln("Updated spread constraints of replication controller")
+}
+
+func (r rctlParams) SetAutoscale(id fields.ID, policy *fields.AutoscalePolicy) {
+	err := r.rcs.UpdateAutoscalePolicy(id, policy)
+	if err != nil {
+		r.logger.WithError(err).Fatalln("Autoscale policy update failed")
+	}
+	if policy == nil {
+		r.logger.WithField("id", id).Infoln("Removed autoscale policy of replication controller")
+		return
+	}
+	r.logger.WithFields(logrus.Fields{
+		"id":     id,
+		"policy": *policy,
+	}).Infoln("Updated autoscale policy of replication controller")
+}
+
+func (r rctlParams) History(id fields.ID, revision int) {
+	if revision != 0 {
+		rev, err := r.rcs.GetRevision(id, revision)
+		if rcstore.IsNoRevision(err) {
+			r.logger.WithField("revision", revision).Fatalln("No such revision in the history of the replication controller")
+		} else if err != nil {
+			r.logger.WithError(err).Fatalln("Could not get replication controller history from Consul")
+		}
+
+		out, err := rev.Manifest.Marshal()
+		if err != nil {
+			r.logger.WithError(err).Fatalln("Could not marshal revision manifest")
+		}
+		fmt.Printf("%s", out)
+		return
+	}
+
+	history, err := r.rcs.History(id)
+	if err != nil {
+		r.logger.WithError(err).Fatalln("Could not get replication controller history from Consul")
+	}
+	if len(history) == 0 {
+		fmt.Printf("no history found for %s\n", id)
+		return
+	}
+
+	fmt.Printf("%-10s %-64s %-20s %s\n", "REVISION", "SHA", "AUTHOR", "TIMESTAMP")
+	for _, rev := range history {
+		timestamp := "unknown"
+		if !rev.Timestamp.IsZero() {
+			timestamp = rev.Timestamp.Format(time.RFC3339)
+		}
+		author := rev.Author
+		if author == "" {
+			author = "unknown"
+		}
+		fmt.Printf("%-10d %-64s %-20s %s\n", rev.Number, rev.SHA, author, timestamp)
+	}
+}
+
+// Rollback schedules a rolling update from the given RC to a new RC that is
+// identical apart from running the manifest of a previous revision. Unlike
+// update-manifest, the old manifest is rolled out using the usual health
+// checked process.
+func (r rctlParams) Rollback(id fields.ID, toRevision int, want int, need int, txner transaction.Txner) {
+	oldRC, err := r.rcs.Get(id)
+	if err != nil {
+		r.logger.WithError(err).Fatalln("Could not get replication controller in Consul")
+	}
+
+	rev, err := r.rcs.GetRevision(id, toRevision)
+	if rcstore.IsNoRevision(err) {
+		r.logger.WithField("revision", toRevision).Fatalln("No such revision in the history of the replication controller")
+	} else if err != nil {
+		r.logger.WithError(err).Fatalln("Could not get replication controller history from Consul")
+	}
+
+	if want == 0 {
+		want = oldRC.ReplicasDesired
+	}
+	if want < need {
+		r.logger.WithFields(logrus.Fields{
+			"want": want,
+			"need": need,
+		}).Fatalln("Cannot run update with desired replicas less than minimum replicas")
+	}
+
+	rcLabels, err := r.labeler.GetLabels(labels.RC, id.String())
+	if err != nil {
+		r.logger.WithError(err).Fatalln("Could not get replication controller labels from Consul")
+	}
+
+	fmt.Printf("rolling %s back to revision %d (%s) with %d desired and %d minimum replicas\n", id, rev.Number, rev.SHA, want, need)
+	if !*rollbackConfirmSkip && !cli.Confirm() {
+		r.logger.Fatal("user aborted")
+	}
+
+	ctx, cancelFunc := transaction.New(rcstore.WithAuthor(context.Background(), currentUserName()))
+	defer cancelFunc()
+	u, err := r.rls.CreateRollingUpdateFromOneExistingRCWithID(
+		ctx,
+		id,
+		want,
+		need,
+		false,
+		0,
+		pc_fields.AvailabilityZone(oldRC.PodLabels[types.AvailabilityZoneLabel]),
+		pc_fields.ClusterName(oldRC.PodLabels[types.ClusterNameLabel]),
+		rev.Manifest,
+		oldRC.NodeSelector,
+		oldRC.PodLabels,
+		rcLabels.Labels,
+		nil,
+		oldRC.AllocationStrategy,
+	)
+	if err != nil {
+		r.logger.WithError(err).Fatalln("Could not create rolling update")
+	}
+
+	err = transaction.MustCommit(ctx, txner)
+	if err != nil {
+		r.logger.WithError(err).Fatalln("Could not create rolling update")
+	}
+
+	r.logger.WithFields(logrus.Fields{
+		"old_rc":   u.OldRC,
+		"new_rc":   u.NewRC,
+		"revision": rev.Number,
+	}).Infoln("Created rolling update to previous revision")
+}
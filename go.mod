@@ -60,7 +60,7 @@ require (
 	github.com/kr/pretty v0.1.0 // indirect
 	github.com/mattn/go-isatty v0.0.0-20160806122752-66b8e73f3f5c // indirect
 	github.com/mattn/go-sqlite3 v0.0.0-20161028142218-86681de00ade
-	github.com/miekg/dns v0.0.0-20160726032027-db96a2b759cd // indirect
+	github.com/miekg/dns v0.0.0-20160726032027-db96a2b759cd
 	github.com/mitchellh/cli v0.0.0-20160323170700-168daae10d6f // indirect
 	github.com/mitchellh/copystructure v0.0.0-20160804032330-cdac8253d00f // indirect
 	github.com/mitchellh/mapstructure v0.0.0-20160808181253-ca63d7c062ee // indirect
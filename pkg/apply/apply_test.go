@@ -0,0 +1,475 @@
+// +build !race
+
+package apply
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	klabels "k8s.io/kubernetes/pkg/labels"
+
+	"github.com/square/p2/pkg/audit"
+	ds_fields "github.com/square/p2/pkg/ds/fields"
+	"github.com/square/p2/pkg/labels"
+	"github.com/square/p2/pkg/logging"
+	"github.com/square/p2/pkg/manifest"
+	pc_fields "github.com/square/p2/pkg/pc/fields"
+	rc_fields "github.com/square/p2/pkg/rc/fields"
+	roll_fields "github.com/square/p2/pkg/roll/fields"
+	"github.com/square/p2/pkg/store/consul/auditlogstore"
+	"github.com/square/p2/pkg/store/consul/consultest"
+	"github.com/square/p2/pkg/store/consul/consulutil"
+	"github.com/square/p2/pkg/store/consul/dsstore"
+	"github.com/square/p2/pkg/store/consul/pcstore"
+	"github.com/square/p2/pkg/store/consul/rcstore"
+	"github.com/square/p2/pkg/store/consul/rollstore"
+	"github.com/square/p2/pkg/store/consul/transaction"
+	"github.com/square/p2/pkg/types"
+)
+
+type testStores struct {
+	rcStore       *rcstore.ConsulStore
+	rollStore     rollstore.ConsulStore
+	dsStore       *dsstore.ConsulStore
+	pcStore       *pcstore.ConsulStore
+	labeler       *labels.ConsulApplicator
+	auditLogStore auditlogstore.ConsulStore
+}
+
+func newTestApplier(fixture consulutil.Fixture) (Applier, testStores) {
+	applicator := labels.NewConsulApplicator(fixture.Client, 0, 0)
+	stores := testStores{
+		rcStore:       rcstore.NewConsul(fixture.Client, applicator, 0),
+		rollStore:     rollstore.NewConsul(fixture.Client, applicator, &logging.DefaultLogger),
+		dsStore:       dsstore.NewConsul(fixture.Client, 0, &logging.DefaultLogger),
+		pcStore:       pcstore.NewConsul(fixture.Client, applicator, 0, applicator, &logging.DefaultLogger),
+		labeler:       applicator,
+		auditLogStore: auditlogstore.NewConsulStore(fixture.Client.KV()),
+	}
+	applier := NewApplier(
+		stores.rcStore,
+		stores.rollStore,
+		stores.dsStore,
+		dsstore.NewAuditingStore(stores.dsStore, stores.auditLogStore),
+		stores.pcStore,
+		stores.labeler,
+		stores.auditLogStore,
+		fixture.Client.KV(),
+		consultest.NewSession(),
+	)
+	return applier, stores
+}
+
+func testManifestWithPort(port int) manifest.Manifest {
+	builder := manifest.NewBuilder()
+	builder.SetID("slug")
+	builder.SetStatusPort(port)
+	return builder.GetManifest()
+}
+
+func testRCSpec(cluster string, replicas int, rcLabels klabels.Set) RCSpec {
+	return RCSpec{
+		AvailabilityZone: "us-west",
+		ClusterName:      pc_fields.ClusterName(cluster),
+		Manifest:         testManifestWithPort(8000),
+		NodeSelector:     klabels.Everything(),
+		PodLabels: klabels.Set{
+			types.AvailabilityZoneLabel: "us-west",
+			types.ClusterNameLabel:      cluster,
+		},
+		Labels:             rcLabels,
+		Replicas:           replicas,
+		MinimumReplicas:    1,
+		AllocationStrategy: rc_fields.StaticStrategy,
+	}
+}
+
+func testPCSpec(cluster string, pcLabels klabels.Set) PCSpec {
+	return PCSpec{
+		PodID:              "slug",
+		AvailabilityZone:   "us-west",
+		ClusterName:        pc_fields.ClusterName(cluster),
+		PodSelector:        klabels.Set{types.PodIDLabel: "slug"}.AsSelector(),
+		Annotations:        pc_fields.Annotations{},
+		Labels:             pcLabels,
+		AllocationStrategy: rc_fields.StaticStrategy,
+	}
+}
+
+func testDSSpec(minHealth int) DSSpec {
+	return DSSpec{
+		Name:         "production",
+		Manifest:     testManifestWithPort(8000),
+		NodeSelector: klabels.Everything(),
+		MinHealth:    minHealth,
+		Timeout:      time.Minute,
+	}
+}
+
+func planAndApply(t *testing.T, applier Applier, specs Specs) Plan {
+	plan, err := applier.Plan(specs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = applier.Apply(context.Background(), plan, "some_user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return plan
+}
+
+func summarize(plan Plan) []string {
+	var summary []string
+	for _, change := range plan.Changes {
+		summary = append(summary, strings.Join([]string{string(change.Action), change.Kind.String(), change.Key}, " "))
+	}
+	return summary
+}
+
+func auditEventTypes(t *testing.T, stores testStores) []string {
+	records, err := stores.auditLogStore.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var eventTypes []string
+	for _, record := range records {
+		eventTypes = append(eventTypes, string(record.EventType))
+	}
+	sort.Strings(eventTypes)
+	return eventTypes
+}
+
+func TestApplyCreates(t *testing.T) {
+	fixture := consulutil.NewFixture(t)
+	defer fixture.Stop()
+	applier, stores := newTestApplier(fixture)
+
+	specs := Specs{
+		RCs: []RCSpec{testRCSpec("production", 2, klabels.Set{"team": "payments"})},
+		DSs: []DSSpec{testDSSpec(80)},
+		PCs: []PCSpec{testPCSpec("production", klabels.Set{"team": "payments"})},
+	}
+	plan := planAndApply(t, applier, specs)
+	expected := []string{
+		"create replication_controller slug/us-west/production",
+		"create daemon_set slug/production",
+		"create pod_cluster slug/us-west/production",
+	}
+	if summary := summarize(plan); !reflect.DeepEqual(summary, expected) {
+		t.Errorf("expected plan %v, got %v", expected, summary)
+	}
+
+	rcs, err := stores.rcStore.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rcs) != 1 || rcs[0].ReplicasDesired != 2 {
+		t.Fatalf("expected one replication controller with 2 replicas, got %+v", rcs)
+	}
+	rcLabels, err := stores.labeler.GetLabels(labels.RC, rcs[0].ID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rcLabels.Labels["team"] != "payments" || rcLabels.Labels[types.PodIDLabel] != "slug" {
+		t.Errorf("expected the replication controller's labels to be set, got %s", rcLabels.Labels)
+	}
+
+	pcs, err := stores.pcStore.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pcs) != 1 {
+		t.Fatalf("expected one pod cluster, got %+v", pcs)
+	}
+	pcLabels, err := stores.labeler.GetLabels(labels.PC, pcs[0].ID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pcLabels.Labels["team"] != "payments" || pcLabels.Labels[types.ClusterNameLabel] != "production" {
+		t.Errorf("expected the pod cluster's labels to be set, got %s", pcLabels.Labels)
+	}
+
+	dss, err := stores.dsStore.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dss) != 1 || dss[0].MinHealth != 80 {
+		t.Fatalf("expected one daemon set with min health 80, got %+v", dss)
+	}
+
+	expectedEvents := []string{
+		string(audit.DSCreatedEvent),
+		string(audit.PCCreatedEvent),
+		string(audit.RCCreatedEvent),
+		string(audit.RCModifiedEvent),
+	}
+	if events := auditEventTypes(t, stores); !reflect.DeepEqual(events, expectedEvents) {
+		t.Errorf("expected audit events %v, got %v", expectedEvents, events)
+	}
+
+	plan, err = applier.Plan(specs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !plan.Empty() {
+		t.Errorf("expected no changes once applied, got:\n%s", plan)
+	}
+}
+
+func TestApplyUpdates(t *testing.T) {
+	fixture := consulutil.NewFixture(t)
+	defer fixture.Stop()
+	applier, stores := newTestApplier(fixture)
+
+	specs := Specs{
+		RCs: []RCSpec{testRCSpec("production", 2, klabels.Set{"team": "payments", "tier": "web"})},
+		DSs: []DSSpec{testDSSpec(80)},
+		PCs: []PCSpec{testPCSpec("production", nil)},
+	}
+	planAndApply(t, applier, specs)
+
+	// Replica and label changes are made in place, and a removed label is
+	// removed along with a changed one
+	specs.RCs[0].Replicas = 3
+	specs.RCs[0].Labels = klabels.Set{"team": "billing"}
+	specs.DSs[0].MinHealth = 90
+	specs.DSs[0].Manifest = testManifestWithPort(9000)
+	specs.PCs[0].MinHealthPercentage = 50
+	specs.PCs[0].Labels = klabels.Set{"team": "billing"}
+	plan := planAndApply(t, applier, specs)
+	expected := []string{
+		"update replication_controller slug/us-west/production",
+		"update daemon_set slug/production",
+		"update pod_cluster slug/us-west/production",
+	}
+	if summary := summarize(plan); !reflect.DeepEqual(summary, expected) {
+		t.Errorf("expected plan %v, got %v", expected, summary)
+	}
+	if !strings.Contains(plan.Changes[1].ManifestDiff, "-  port: 8000\n+  port: 9000") {
+		t.Errorf("expected a manifest diff for the daemon set, got:\n%s", plan.Changes[1].ManifestDiff)
+	}
+
+	rcs, err := stores.rcStore.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rcs) != 1 || rcs[0].ReplicasDesired != 3 {
+		t.Fatalf("expected one replication controller with 3 replicas, got %+v", rcs)
+	}
+	rcLabels, err := stores.labeler.GetLabels(labels.RC, rcs[0].ID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedLabels := klabels.Set{"team": "billing", types.PodIDLabel: "slug"}
+	if !reflect.DeepEqual(rcLabels.Labels, expectedLabels) {
+		t.Errorf("expected labels %s, got %s", expectedLabels, rcLabels.Labels)
+	}
+
+	dss, err := stores.dsStore.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dss[0].MinHealth != 90 || dss[0].Manifest.GetStatusPort() != 9000 {
+		t.Errorf("expected the daemon set to be updated in place, got %+v", dss[0])
+	}
+	pcs, err := stores.pcStore.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pcs[0].MinHealthPercentage != 50 {
+		t.Errorf("expected the pod cluster's min health percentage to be updated, got %d", pcs[0].MinHealthPercentage)
+	}
+
+	// Manifest changes to a replication controller are made by a rolling
+	// update to a new one
+	oldRCID := rcs[0].ID
+	specs.RCs[0].Manifest = testManifestWithPort(9000)
+	plan = planAndApply(t, applier, specs)
+	if len(plan.Changes) != 1 || !strings.Contains(plan.Changes[0].ManifestDiff, "+  port: 9000") {
+		t.Fatalf("expected a single replication controller update with a manifest diff, got:\n%s", plan)
+	}
+	updates, err := stores.rollStore.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updates) != 1 || updates[0].OldRC != oldRCID || updates[0].DesiredReplicas != 3 || updates[0].MinimumReplicas != 1 {
+		t.Fatalf("expected a rolling update from %s to 3 replicas, got %+v", oldRCID, updates)
+	}
+	events := auditEventTypes(t, stores)
+	if events[len(events)-1] != string(audit.RUCreationEvent) {
+		t.Errorf("expected the rolling update to be audited, got %v", events)
+	}
+
+	plan, err = applier.Plan(specs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !plan.Empty() || len(plan.Notes) != 1 || !strings.Contains(plan.Notes[0], "rolling update in progress") {
+		t.Errorf("expected the replication controller in a rolling update to be skipped, got:\n%s", plan)
+	}
+}
+
+func TestApplyLeavesAutoscaledReplicasAlone(t *testing.T) {
+	fixture := consulutil.NewFixture(t)
+	defer fixture.Stop()
+	applier, stores := newTestApplier(fixture)
+
+	maxUnhealthy := 0
+	rcSpec := testRCSpec("production", 2, nil)
+	rcSpec.SpreadConstraints = []rc_fields.SpreadConstraint{{TopologyKey: "rack", MaxSkew: 1}}
+	rcSpec.Autoscale = &rc_fields.AutoscalePolicy{
+		MinReplicas: 1,
+		MaxReplicas: 5,
+		Target:      10,
+		Metric:      rc_fields.MetricSource{Type: rc_fields.PodMetricSource, Path: "/load"},
+	}
+	rcSpec.CanaryReplicas = 1
+	rcSpec.CanaryBake = time.Minute
+	rcSpec.FailurePolicy = &roll_fields.FailurePolicy{MaxUnhealthy: &maxUnhealthy, Rollback: true}
+	dsSpec := testDSSpec(80)
+	dsSpec.RollingStrategy = &ds_fields.RollingStrategy{MaxUnavailable: "10%"}
+	dsSpec.FailureThreshold = "5%"
+	specs := Specs{RCs: []RCSpec{rcSpec}, DSs: []DSSpec{dsSpec}}
+	planAndApply(t, applier, specs)
+
+	rcs, err := stores.rcStore.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rcs) != 1 || rcs[0].ReplicasDesired != 2 {
+		t.Fatalf("expected one replication controller with 2 replicas, got %+v", rcs)
+	}
+	if !reflect.DeepEqual(rcs[0].SpreadConstraints, rcSpec.SpreadConstraints) || !reflect.DeepEqual(rcs[0].Autoscale, rcSpec.Autoscale) {
+		t.Errorf("expected the spread constraints and autoscale policy to be set, got %+v", rcs[0])
+	}
+	dss, err := stores.dsStore.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dss[0].RollingStrategy, dsSpec.RollingStrategy) || dss[0].FailureThreshold != "5%" {
+		t.Errorf("expected the rolling strategy and failure threshold to be set, got %+v", dss[0])
+	}
+
+	// The autoscaler scaling the replication controller up doesn't make it
+	// differ from its spec
+	ctx, cancel := transaction.New(context.Background())
+	defer cancel()
+	err = stores.rcStore.CASDesiredReplicasTxn(ctx, rcs[0].ID, 2, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = transaction.MustCommit(ctx, fixture.Client.KV())
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan, err := applier.Plan(specs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !plan.Empty() {
+		t.Fatalf("expected no changes to the autoscaled replication controller, got:\n%s", plan)
+	}
+
+	// A rolling update keeps the autoscaler's replica count and takes the
+	// spec's canary and failure policy
+	specs.RCs[0].Manifest = testManifestWithPort(9000)
+	planAndApply(t, applier, specs)
+	updates, err := stores.rollStore.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updates) != 1 || updates[0].DesiredReplicas != 4 || updates[0].CanaryReplicas != 1 || updates[0].CanaryBake != time.Minute {
+		t.Fatalf("expected a rolling update to 4 replicas with 1 canary, got %+v", updates)
+	}
+	if !reflect.DeepEqual(updates[0].FailurePolicy, rcSpec.FailurePolicy) {
+		t.Errorf("expected failure policy %+v, got %+v", rcSpec.FailurePolicy, updates[0].FailurePolicy)
+	}
+	newRC, err := stores.rcStore.Get(updates[0].NewRC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(newRC.Autoscale, rcSpec.Autoscale) || !reflect.DeepEqual(newRC.SpreadConstraints, rcSpec.SpreadConstraints) {
+		t.Errorf("expected the new replication controller to inherit the autoscale policy and spread constraints, got %+v", newRC)
+	}
+}
+
+func TestApplyDeletes(t *testing.T) {
+	fixture := consulutil.NewFixture(t)
+	defer fixture.Stop()
+	applier, stores := newTestApplier(fixture)
+
+	planAndApply(t, applier, Specs{
+		RCs: []RCSpec{testRCSpec("production", 2, nil), testRCSpec("staging", 1, nil)},
+		PCs: []PCSpec{testPCSpec("production", nil), testPCSpec("staging", nil)},
+	})
+
+	// Only the objects of declared pod IDs are deleted
+	other := testManifestWithPort(8000).GetBuilder()
+	other.SetID("other")
+	otherRC := testRCSpec("staging", 0, nil)
+	otherRC.Manifest = other.GetManifest()
+	planAndApply(t, applier, Specs{RCs: []RCSpec{otherRC}})
+
+	specs := Specs{
+		RCs: []RCSpec{testRCSpec("production", 2, nil)},
+		PCs: []PCSpec{testPCSpec("production", nil)},
+	}
+	plan := planAndApply(t, applier, specs)
+	expected := []string{
+		"delete replication_controller slug/us-west/staging",
+		"delete pod_cluster slug/us-west/staging",
+	}
+	if summary := summarize(plan); !reflect.DeepEqual(summary, expected) {
+		t.Errorf("expected plan %v, got %v", expected, summary)
+	}
+
+	// The staging replication controller had replicas, so it is scaled
+	// down first and deleted by the next apply
+	rcs, err := stores.rcStore.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rcs) != 3 {
+		t.Fatalf("expected the staging replication controller to be kept while it scales down, got %+v", rcs)
+	}
+	for _, rc := range rcs {
+		if rc.PodLabels[types.ClusterNameLabel] == "staging" && rc.Manifest.ID() == "slug" && rc.ReplicasDesired != 0 {
+			t.Errorf("expected the staging replication controller to be scaled down, got %d replicas", rc.ReplicasDesired)
+		}
+	}
+	pcs, err := stores.pcStore.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pcs) != 1 || pcs[0].Name != "production" {
+		t.Errorf("expected only the production pod cluster to be left, got %+v", pcs)
+	}
+
+	plan = planAndApply(t, applier, specs)
+	expected = []string{"delete replication_controller slug/us-west/staging"}
+	if summary := summarize(plan); !reflect.DeepEqual(summary, expected) {
+		t.Errorf("expected plan %v, got %v", expected, summary)
+	}
+	rcs, err = stores.rcStore.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rcs) != 2 {
+		t.Errorf("expected the staging replication controller to be deleted, got %+v", rcs)
+	}
+
+	events := auditEventTypes(t, stores)
+	deletes := 0
+	for _, event := range events {
+		if event == string(audit.RCDeletedEvent) || event == string(audit.PCDeletedEvent) {
+			deletes++
+		}
+	}
+	if deletes != 2 {
+		t.Errorf("expected both deletions to be audited, got %v", events)
+	}
+}
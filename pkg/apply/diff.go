@@ -0,0 +1,114 @@
+package apply
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/square/p2/pkg/manifest"
+)
+
+// diffContext is the number of unchanged lines shown around each change
+const diffContext = 3
+
+// ManifestDiff returns a line diff from the old manifest to the new one, in
+// the style of diff -u without hunk headers: removed lines are prefixed with
+// "-", added lines with "+" and context lines with a space. Unchanged runs
+// longer than the context are elided with "...". It returns an empty string
+// if the manifests marshal identically.
+func ManifestDiff(oldManifest manifest.Manifest, newManifest manifest.Manifest) (string, error) {
+	oldBytes, err := oldManifest.Marshal()
+	if err != nil {
+		return "", err
+	}
+	newBytes, err := newManifest.Marshal()
+	if err != nil {
+		return "", err
+	}
+	return diffLines(splitLines(oldBytes), splitLines(newBytes)), nil
+}
+
+func splitLines(b []byte) []string {
+	trimmed := strings.TrimRight(string(b), "\n")
+	if trimmed == "" {
+		return nil
+	}
+	return strings.Split(trimmed, "\n")
+}
+
+type diffOp struct {
+	prefix byte
+	line   string
+}
+
+func diffLines(a []string, b []string) string {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:]
+	// and b[j:]. Manifests are short, so the quadratic table is fine.
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var ops []diffOp
+	changed := false
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffOp{'-', a[i]})
+			changed = true
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			changed = true
+			j++
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	// Only show context lines near a change
+	show := make([]bool, len(ops))
+	for k, op := range ops {
+		if op.prefix == ' ' {
+			continue
+		}
+		for c := k - diffContext; c <= k+diffContext; c++ {
+			if c >= 0 && c < len(ops) {
+				show[c] = true
+			}
+		}
+	}
+
+	var out bytes.Buffer
+	elided := false
+	for k, op := range ops {
+		if !show[k] {
+			if !elided {
+				out.WriteString("...\n")
+				elided = true
+			}
+			continue
+		}
+		elided = false
+		out.WriteByte(op.prefix)
+		out.WriteString(op.line)
+		out.WriteByte('\n')
+	}
+	return out.String()
+}
@@ -0,0 +1,34 @@
+package apply
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	old := strings.Split("a b c d e f g h i j k l m n o p", " ")
+	new := strings.Split("a b c d x f g h i j k l m n o p q", " ")
+
+	expected := `...
+ b
+ c
+ d
+-e
++x
+ f
+ g
+ h
+...
+ n
+ o
+ p
++q
+`
+	if diff := diffLines(old, new); diff != expected {
+		t.Errorf("expected diff:\n%s\ngot:\n%s", expected, diff)
+	}
+
+	if diff := diffLines(old, old); diff != "" {
+		t.Errorf("expected no diff between identical lines, got:\n%s", diff)
+	}
+}
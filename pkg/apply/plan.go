@@ -0,0 +1,1296 @@
+/*
+Package apply makes the replication controllers, daemon sets and pod clusters
+of a set of pods match a declarative description of them. An Applier first
+computes a Plan: the objects to create, update and delete, by comparing specs
+read from YAML files against the live stores. The plan can be shown to the
+user before it is applied.
+
+Only objects whose pod ID is declared in some spec are considered, so a file
+describing one pod never deletes another pod's objects. Replication
+controller manifest, node selector, pod label and allocation strategy changes
+are made by rolling updates rather than written in place, and every change is
+recorded in the audit log. The replica count of a replication controller with
+an autoscale policy is left to the autoscaler once it has been created.
+*/
+package apply
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	klabels "k8s.io/kubernetes/pkg/labels"
+
+	"github.com/square/p2/pkg/audit"
+	ds_fields "github.com/square/p2/pkg/ds/fields"
+	"github.com/square/p2/pkg/labels"
+	"github.com/square/p2/pkg/manifest"
+	pc_fields "github.com/square/p2/pkg/pc/fields"
+	rc_fields "github.com/square/p2/pkg/rc/fields"
+	roll_fields "github.com/square/p2/pkg/roll/fields"
+	"github.com/square/p2/pkg/store/consul/pcstore"
+	"github.com/square/p2/pkg/store/consul/rcstore"
+	"github.com/square/p2/pkg/store/consul/transaction"
+	"github.com/square/p2/pkg/types"
+	"github.com/square/p2/pkg/util"
+)
+
+// Subset of rcstore.ConsulStore
+type RCStore interface {
+	List() ([]rc_fields.RC, error)
+	CreateTxn(
+		ctx context.Context,
+		manifest manifest.Manifest,
+		nodeSelector klabels.Selector,
+		availabilityZone pc_fields.AvailabilityZone,
+		clusterName pc_fields.ClusterName,
+		podLabels klabels.Set,
+		additionalLabels klabels.Set,
+		allocationStrategy rc_fields.Strategy,
+	) (rc_fields.RC, error)
+	CASDesiredReplicasTxn(ctx context.Context, id rc_fields.ID, expected int, n int) error
+	SetSpreadConstraintsTxn(ctx context.Context, id rc_fields.ID, constraints []rc_fields.SpreadConstraint) error
+	SetAutoscalePolicyTxn(ctx context.Context, id rc_fields.ID, policy *rc_fields.AutoscalePolicy) error
+	DeleteTxn(ctx context.Context, id rc_fields.ID, force bool) error
+}
+
+// Subset of rollstore.ConsulStore
+type RollStore interface {
+	List() ([]roll_fields.Update, error)
+	CreateRollingUpdateFromOneExistingRC(
+		ctx context.Context,
+		u roll_fields.Update,
+		availabilityZone pc_fields.AvailabilityZone,
+		clusterName pc_fields.ClusterName,
+		newRCManifest manifest.Manifest,
+		newRCNodeSelector klabels.Selector,
+		newRCPodLabels klabels.Set,
+		newRCLabels klabels.Set,
+		rollLabels klabels.Set,
+		newAllocationStrategy rc_fields.Strategy,
+	) (roll_fields.Update, error)
+}
+
+// Subset of dsstore.ConsulStore
+type DSStore interface {
+	List() ([]ds_fields.DaemonSet, error)
+}
+
+// Subset of dsstore.AuditingStore
+type DSAuditingStore interface {
+	Create(
+		ctx context.Context,
+		manifest manifest.Manifest,
+		minHealth int,
+		name ds_fields.ClusterName,
+		nodeSelector klabels.Selector,
+		podID types.PodID,
+		timeout time.Duration,
+		user string,
+	) (ds_fields.DaemonSet, error)
+	UpdateManifest(ctx context.Context, id ds_fields.ID, manifest manifest.Manifest, user string) (ds_fields.DaemonSet, error)
+	UpdateNodeSelector(ctx context.Context, id ds_fields.ID, nodeSelector klabels.Selector, user string) (ds_fields.DaemonSet, error)
+	UpdateMinHealth(ctx context.Context, id ds_fields.ID, minHealth int, user string) (ds_fields.DaemonSet, error)
+	UpdateTimeout(ctx context.Context, id ds_fields.ID, timeout time.Duration, user string) (ds_fields.DaemonSet, error)
+	UpdateRollingStrategy(ctx context.Context, id ds_fields.ID, strategy *ds_fields.RollingStrategy, user string) (ds_fields.DaemonSet, error)
+	UpdateFailureThreshold(ctx context.Context, id ds_fields.ID, threshold ds_fields.IntOrPercent, user string) (ds_fields.DaemonSet, error)
+	Delete(ctx context.Context, id ds_fields.ID, user string) error
+}
+
+// Subset of pcstore.ConsulStore
+type PCStore interface {
+	List() ([]pc_fields.PodCluster, error)
+	Create(
+		podID types.PodID,
+		availabilityZone pc_fields.AvailabilityZone,
+		clusterName pc_fields.ClusterName,
+		podSelector klabels.Selector,
+		annotations pc_fields.Annotations,
+		allocationStrategy rc_fields.Strategy,
+		minHealthPercentage pc_fields.MinHealthPercentage,
+		session pcstore.Session,
+	) (pc_fields.PodCluster, error)
+	MutatePC(id pc_fields.ID, mutator func(pc_fields.PodCluster) (pc_fields.PodCluster, error)) (pc_fields.PodCluster, error)
+	Delete(id pc_fields.ID) error
+}
+
+// Subset of labels.Applicator
+type Labeler interface {
+	GetLabels(labelType labels.Type, id string) (labels.Labeled, error)
+	SetLabelsTxn(ctx context.Context, labelType labels.Type, id string, labels map[string]string) error
+	RemoveLabelsTxn(ctx context.Context, labelType labels.Type, id string, keysToRemove []string) error
+}
+
+type AuditLogStore interface {
+	Create(ctx context.Context, eventType audit.EventType, eventDetails json.RawMessage) error
+}
+
+type Action string
+
+const (
+	CreateAction Action = "create"
+	UpdateAction Action = "update"
+	DeleteAction Action = "delete"
+)
+
+// Change is a single change to one object in a Plan
+type Change struct {
+	Kind   Kind
+	Action Action
+	// Key identifies the object: <pod_id>/<az>/<cluster_name> for
+	// replication controllers and pod clusters, and <pod_id>/<name> for
+	// daemon sets
+	Key string
+	// Details are human-readable descriptions of what will change
+	Details []string
+	// ManifestDiff is a line diff of the object's manifest, if it changes
+	ManifestDiff string
+
+	apply func(ctx context.Context, user string) error
+}
+
+type Plan struct {
+	Changes []Change
+	// Notes describe objects that were left alone, and why
+	Notes []string
+}
+
+func (p Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// String formats the plan for display to a user before it is applied
+func (p Plan) String() string {
+	var out bytes.Buffer
+	for _, change := range p.Changes {
+		symbol := map[Action]string{CreateAction: "+", UpdateAction: "~", DeleteAction: "-"}[change.Action]
+		fmt.Fprintf(&out, "%s %s %s %s\n", symbol, change.Action, change.Kind, change.Key)
+		for _, detail := range change.Details {
+			fmt.Fprintf(&out, "    %s\n", detail)
+		}
+		if change.ManifestDiff != "" {
+			out.WriteString("    manifest diff:\n")
+			for _, line := range strings.Split(strings.TrimRight(change.ManifestDiff, "\n"), "\n") {
+				fmt.Fprintf(&out, "      %s\n", line)
+			}
+		}
+	}
+	if p.Empty() {
+		out.WriteString("No changes\n")
+	}
+	for _, note := range p.Notes {
+		fmt.Fprintf(&out, "note: %s\n", note)
+	}
+	return out.String()
+}
+
+type Applier struct {
+	rcStore         RCStore
+	rollStore       RollStore
+	dsStore         DSStore
+	dsAuditingStore DSAuditingStore
+	pcStore         PCStore
+	labeler         Labeler
+	auditLogStore   AuditLogStore
+	txner           transaction.Txner
+
+	// session is used to lock pod clusters while they are created
+	session pcstore.Session
+}
+
+func NewApplier(
+	rcStore RCStore,
+	rollStore RollStore,
+	dsStore DSStore,
+	dsAuditingStore DSAuditingStore,
+	pcStore PCStore,
+	labeler Labeler,
+	auditLogStore AuditLogStore,
+	txner transaction.Txner,
+	session pcstore.Session,
+) Applier {
+	return Applier{
+		rcStore:         rcStore,
+		rollStore:       rollStore,
+		dsStore:         dsStore,
+		dsAuditingStore: dsAuditingStore,
+		pcStore:         pcStore,
+		labeler:         labeler,
+		auditLogStore:   auditLogStore,
+		txner:           txner,
+		session:         session,
+	}
+}
+
+// Plan computes the changes that would make the live objects of the pod IDs
+// in specs match them
+func (a Applier) Plan(specs Specs) (Plan, error) {
+	var plan Plan
+	podIDs := specs.PodIDs()
+
+	err := a.planRCs(&plan, specs.RCs, podIDs)
+	if err != nil {
+		return Plan{}, err
+	}
+	err = a.planDSs(&plan, specs.DSs, podIDs)
+	if err != nil {
+		return Plan{}, err
+	}
+	err = a.planPCs(&plan, specs.PCs, podIDs)
+	if err != nil {
+		return Plan{}, err
+	}
+	return plan, nil
+}
+
+// Apply makes the changes in the plan in order, attributing them to user. It
+// stops at the first change that fails. The changes before it have been
+// made, so a new plan should be computed before trying again.
+func (a Applier) Apply(ctx context.Context, plan Plan, user string) error {
+	ctx = rcstore.WithAuthor(ctx, user)
+	for _, change := range plan.Changes {
+		err := change.apply(ctx, user)
+		if err != nil {
+			return util.Errorf("could not %s %s %s: %s", change.Action, change.Kind, change.Key, err)
+		}
+	}
+	return nil
+}
+
+func (a Applier) planRCs(plan *Plan, specs []RCSpec, podIDs map[types.PodID]bool) error {
+	rcs, err := a.rcStore.List()
+	if err != nil {
+		return util.Errorf("could not list replication controllers: %s", err)
+	}
+	updates, err := a.rollStore.List()
+	if err != nil {
+		return util.Errorf("could not list rolling updates: %s", err)
+	}
+	rolling := make(map[rc_fields.ID]bool)
+	for _, u := range updates {
+		rolling[u.OldRC] = true
+		rolling[u.NewRC] = true
+	}
+
+	live := make(map[string][]rc_fields.RC)
+	for _, rc := range rcs {
+		podID := rc.Manifest.ID()
+		if !podIDs[podID] {
+			continue
+		}
+		az := rc.PodLabels[types.AvailabilityZoneLabel]
+		cn := rc.PodLabels[types.ClusterNameLabel]
+		if az == "" || cn == "" {
+			plan.Notes = append(plan.Notes, fmt.Sprintf(
+				"replication controller %s of %s has no %s or %s pod label, leaving it alone",
+				rc.ID,
+				podID,
+				types.AvailabilityZoneLabel,
+				types.ClusterNameLabel,
+			))
+			continue
+		}
+		key := rcKey(podID, pc_fields.AvailabilityZone(az), pc_fields.ClusterName(cn))
+		live[key] = append(live[key], rc)
+	}
+	isRolling := func(rcs []rc_fields.RC) bool {
+		for _, rc := range rcs {
+			if rolling[rc.ID] {
+				return true
+			}
+		}
+		return false
+	}
+
+	declared := make(map[string]bool)
+	for _, spec := range specs {
+		key := rcKey(spec.Manifest.ID(), spec.AvailabilityZone, spec.ClusterName)
+		declared[key] = true
+		existing := live[key]
+		if isRolling(existing) {
+			plan.Notes = append(plan.Notes, fmt.Sprintf("%s %s has a rolling update in progress, skipping it", RCKind, key))
+			continue
+		}
+
+		switch len(existing) {
+		case 0:
+			plan.Changes = append(plan.Changes, a.createRC(key, spec))
+		case 1:
+			change, err := a.updateRC(key, spec, existing[0])
+			if err != nil {
+				return err
+			}
+			if change != nil {
+				plan.Changes = append(plan.Changes, *change)
+			}
+		default:
+			return util.Errorf("%d replication controllers match %s %s and none are in a rolling update; delete all but one before applying", len(existing), RCKind, key)
+		}
+	}
+
+	var undeclared []string
+	for key := range live {
+		if !declared[key] {
+			undeclared = append(undeclared, key)
+		}
+	}
+	sort.Strings(undeclared)
+	for _, key := range undeclared {
+		if isRolling(live[key]) {
+			plan.Notes = append(plan.Notes, fmt.Sprintf("%s %s has a rolling update in progress, not deleting it", RCKind, key))
+			continue
+		}
+		for _, rc := range live[key] {
+			plan.Changes = append(plan.Changes, a.deleteRC(key, rc))
+		}
+	}
+	return nil
+}
+
+func (a Applier) createRC(key string, spec RCSpec) Change {
+	sha, _ := spec.Manifest.SHA()
+	details := []string{
+		fmt.Sprintf("manifest: %s", sha),
+		fmt.Sprintf("node selector: %s", spec.NodeSelector),
+		fmt.Sprintf("pod labels: %s", spec.PodLabels),
+		fmt.Sprintf("labels: %s", klabels.Set(rcLabels(spec))),
+		fmt.Sprintf("allocation strategy: %s", spec.AllocationStrategy),
+		fmt.Sprintf("replicas: %d", spec.Replicas),
+	}
+	if len(spec.SpreadConstraints) > 0 {
+		details = append(details, fmt.Sprintf("spread constraints: %s", formatSpread(spec.SpreadConstraints)))
+	}
+	if spec.Autoscale != nil {
+		details = append(details, fmt.Sprintf("autoscale: %s", formatAutoscale(spec.Autoscale)))
+	}
+
+	return Change{
+		Kind:    RCKind,
+		Action:  CreateAction,
+		Key:     key,
+		Details: details,
+		apply: func(ctx context.Context, user string) error {
+			txnCtx, cancel := transaction.New(ctx)
+			defer cancel()
+			rc, err := a.rcStore.CreateTxn(
+				txnCtx,
+				spec.Manifest,
+				spec.NodeSelector,
+				spec.AvailabilityZone,
+				spec.ClusterName,
+				spec.PodLabels,
+				spec.Labels,
+				spec.AllocationStrategy,
+			)
+			if err != nil {
+				return err
+			}
+			err = a.auditRCTxn(txnCtx, audit.RCCreatedEvent, rc, rcLabels(spec), user)
+			if err != nil {
+				return err
+			}
+			err = transaction.MustCommit(txnCtx, a.txner)
+			if err != nil {
+				return err
+			}
+
+			// Replication controllers are always created with no replicas
+			// and none of these settings. The spread constraints are set
+			// before any pods are scheduled, and the autoscale policy after
+			// the initial replica count so the two don't conflict.
+			if len(spec.SpreadConstraints) > 0 {
+				rc.SpreadConstraints = spec.SpreadConstraints
+				err = a.modifyRC(ctx, rc, rcLabels(spec), user, func(txnCtx context.Context) error {
+					return a.rcStore.SetSpreadConstraintsTxn(txnCtx, rc.ID, spec.SpreadConstraints)
+				})
+				if err != nil {
+					return err
+				}
+			}
+			if spec.Replicas > 0 {
+				rc.ReplicasDesired = spec.Replicas
+				err = a.modifyRC(ctx, rc, rcLabels(spec), user, func(txnCtx context.Context) error {
+					return a.rcStore.CASDesiredReplicasTxn(txnCtx, rc.ID, 0, spec.Replicas)
+				})
+				if err != nil {
+					return err
+				}
+			}
+			if spec.Autoscale != nil {
+				rc.Autoscale = spec.Autoscale
+				err = a.modifyRC(ctx, rc, rcLabels(spec), user, func(txnCtx context.Context) error {
+					return a.rcStore.SetAutoscalePolicyTxn(txnCtx, rc.ID, spec.Autoscale)
+				})
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// updateRC returns the change that makes rc match spec, or nil if it already
+// does. If spec has an autoscale policy, the replica count is the
+// autoscaler's to change and is left as it is.
+func (a Applier) updateRC(key string, spec RCSpec, rc rc_fields.RC) (*Change, error) {
+	labeled, err := a.labeler.GetLabels(labels.RC, rc.ID.String())
+	if err != nil {
+		return nil, util.Errorf("could not get labels of replication controller %s: %s", rc.ID, err)
+	}
+	currentLabels := labeled.Labels
+	desiredLabels := rcLabels(spec)
+
+	oldSHA, err := rc.Manifest.SHA()
+	if err != nil {
+		return nil, err
+	}
+	newSHA, err := spec.Manifest.SHA()
+	if err != nil {
+		return nil, err
+	}
+
+	var rollDetails []string
+	if oldSHA != newSHA {
+		rollDetails = append(rollDetails, fmt.Sprintf("manifest: %s -> %s", oldSHA, newSHA))
+	}
+	if rc.NodeSelector.String() != spec.NodeSelector.String() {
+		rollDetails = append(rollDetails, fmt.Sprintf("node selector: %s -> %s", rc.NodeSelector, spec.NodeSelector))
+	}
+	if rc.PodLabels.String() != spec.PodLabels.String() {
+		rollDetails = append(rollDetails, fmt.Sprintf("pod labels: %s -> %s", rc.PodLabels, spec.PodLabels))
+	}
+	if rc.AllocationStrategy != spec.AllocationStrategy {
+		rollDetails = append(rollDetails, fmt.Sprintf("allocation strategy: %s -> %s", rc.AllocationStrategy, spec.AllocationStrategy))
+	}
+
+	replicas := spec.Replicas
+	if spec.Autoscale != nil {
+		replicas = rc.ReplicasDesired
+	}
+
+	// The spread constraints and autoscale policy are set on rc in place,
+	// each in a transaction of its own since every change is a
+	// check-and-set of rc. When rc is replaced by a rolling update this
+	// happens first, so that the new replication controller inherits them.
+	var details []string
+	var settings []func(ctx context.Context, modified rc_fields.RC, user string) (rc_fields.RC, error)
+	spreadChanged := len(rc.SpreadConstraints) != len(spec.SpreadConstraints) ||
+		(len(spec.SpreadConstraints) > 0 && !reflect.DeepEqual(rc.SpreadConstraints, spec.SpreadConstraints))
+	if spreadChanged {
+		details = append(details, fmt.Sprintf("spread constraints: %s -> %s", formatSpread(rc.SpreadConstraints), formatSpread(spec.SpreadConstraints)))
+		settings = append(settings, func(ctx context.Context, modified rc_fields.RC, user string) (rc_fields.RC, error) {
+			modified.SpreadConstraints = spec.SpreadConstraints
+			return modified, a.modifyRC(ctx, modified, currentLabels, user, func(txnCtx context.Context) error {
+				return a.rcStore.SetSpreadConstraintsTxn(txnCtx, rc.ID, spec.SpreadConstraints)
+			})
+		})
+	}
+	if !reflect.DeepEqual(rc.Autoscale, spec.Autoscale) {
+		details = append(details, fmt.Sprintf("autoscale: %s -> %s", formatAutoscale(rc.Autoscale), formatAutoscale(spec.Autoscale)))
+		settings = append(settings, func(ctx context.Context, modified rc_fields.RC, user string) (rc_fields.RC, error) {
+			modified.Autoscale = spec.Autoscale
+			return modified, a.modifyRC(ctx, modified, currentLabels, user, func(txnCtx context.Context) error {
+				return a.rcStore.SetAutoscalePolicyTxn(txnCtx, rc.ID, spec.Autoscale)
+			})
+		})
+	}
+	applySettings := func(ctx context.Context, user string) (rc_fields.RC, error) {
+		modified := rc
+		for _, setting := range settings {
+			var err error
+			modified, err = setting(ctx, modified, user)
+			if err != nil {
+				return rc_fields.RC{}, err
+			}
+		}
+		return modified, nil
+	}
+
+	if rc.ReplicasDesired != replicas {
+		details = append(details, fmt.Sprintf("replicas: %d -> %d", rc.ReplicasDesired, replicas))
+	}
+	if klabels.Set(currentLabels).String() != klabels.Set(desiredLabels).String() {
+		details = append(details, fmt.Sprintf("labels: %s -> %s", klabels.Set(currentLabels), klabels.Set(desiredLabels)))
+	}
+
+	if len(rollDetails) > 0 {
+		diff, err := ManifestDiff(rc.Manifest, spec.Manifest)
+		if err != nil {
+			return nil, err
+		}
+		u := rollUpdate(spec, rc, replicas)
+		details = append(rollDetails, details...)
+		details = append(details, fmt.Sprintf(
+			"by a rolling update from %s to a new replication controller, keeping at least %d replicas",
+			rc.ID,
+			u.MinimumReplicas,
+		))
+		if u.CanaryReplicas > 0 {
+			details = append(details, fmt.Sprintf("starting with %d canaries baked for %s", u.CanaryReplicas, u.CanaryBake))
+		}
+		return &Change{
+			Kind:         RCKind,
+			Action:       UpdateAction,
+			Key:          key,
+			Details:      details,
+			ManifestDiff: diff,
+			apply: func(ctx context.Context, user string) error {
+				_, err := applySettings(ctx, user)
+				if err != nil {
+					return err
+				}
+				return a.rollRC(ctx, spec, u, user)
+			},
+		}, nil
+	}
+
+	if len(details) == 0 {
+		return nil, nil
+	}
+	return &Change{
+		Kind:    RCKind,
+		Action:  UpdateAction,
+		Key:     key,
+		Details: details,
+		apply: func(ctx context.Context, user string) error {
+			// An autoscale policy being removed is removed before the
+			// replica count is set, so the autoscaler can't change it
+			// in between
+			modified, err := applySettings(ctx, user)
+			if err != nil {
+				return err
+			}
+			if rc.ReplicasDesired == replicas && klabels.Set(currentLabels).String() == klabels.Set(desiredLabels).String() {
+				return nil
+			}
+
+			err = a.commitLabelRemovals(ctx, labels.RC, rc.ID.String(), currentLabels, desiredLabels)
+			if err != nil {
+				return err
+			}
+
+			txnCtx, cancel := transaction.New(ctx)
+			defer cancel()
+			if rc.ReplicasDesired != replicas {
+				err = a.rcStore.CASDesiredReplicasTxn(txnCtx, rc.ID, rc.ReplicasDesired, replicas)
+				if err != nil {
+					return err
+				}
+			}
+			err = a.setLabelsTxn(txnCtx, labels.RC, rc.ID.String(), currentLabels, desiredLabels)
+			if err != nil {
+				return err
+			}
+
+			modified.ReplicasDesired = replicas
+			err = a.auditRCTxn(txnCtx, audit.RCModifiedEvent, modified, desiredLabels, user)
+			if err != nil {
+				return err
+			}
+			return transaction.MustCommit(txnCtx, a.txner)
+		},
+	}, nil
+}
+
+// rollUpdate returns the rolling update that replaces oldRC with a new
+// replication controller created from spec, ending with replicas replicas
+func rollUpdate(spec RCSpec, oldRC rc_fields.RC, replicas int) roll_fields.Update {
+	// An autoscaled replication controller may have been scaled below the
+	// minimum, which the update could then never satisfy
+	minimum := spec.MinimumReplicas
+	if minimum > replicas {
+		minimum = replicas
+	}
+	return roll_fields.Update{
+		OldRC:           oldRC.ID,
+		DesiredReplicas: replicas,
+		MinimumReplicas: minimum,
+		CanaryReplicas:  spec.CanaryReplicas,
+		CanaryBake:      spec.CanaryBake,
+		FailurePolicy:   spec.FailurePolicy,
+		ZoneSequence:    spec.ZoneSequence,
+	}
+}
+
+// rollRC starts the rolling update u to a new replication controller created
+// from spec
+func (a Applier) rollRC(ctx context.Context, spec RCSpec, u roll_fields.Update, user string) error {
+	txnCtx, cancel := transaction.New(ctx)
+	defer cancel()
+	u, err := a.rollStore.CreateRollingUpdateFromOneExistingRC(
+		txnCtx,
+		u,
+		spec.AvailabilityZone,
+		spec.ClusterName,
+		spec.Manifest,
+		spec.NodeSelector,
+		spec.PodLabels,
+		spec.Labels,
+		nil,
+		spec.AllocationStrategy,
+	)
+	if err != nil {
+		return err
+	}
+
+	details, err := audit.NewRUCreationEventDetails(
+		spec.Manifest.ID(),
+		spec.AvailabilityZone,
+		spec.ClusterName,
+		user,
+		spec.Manifest,
+		u.ID(),
+	)
+	if err != nil {
+		return err
+	}
+	err = a.auditLogStore.Create(txnCtx, audit.RUCreationEvent, details)
+	if err != nil {
+		return util.Errorf("could not create audit log record for rolling update creation: %s", err)
+	}
+	return transaction.MustCommit(txnCtx, a.txner)
+}
+
+// deleteRC deletes a replication controller with no replicas. One with
+// replicas is scaled down instead, since deleting it would orphan its pods;
+// a later apply deletes it once the scale down has happened.
+func (a Applier) deleteRC(key string, rc rc_fields.RC) Change {
+	if rc.ReplicasDesired > 0 {
+		return Change{
+			Kind:   RCKind,
+			Action: DeleteAction,
+			Key:    key,
+			Details: []string{
+				fmt.Sprintf("replicas: %d -> 0", rc.ReplicasDesired),
+				fmt.Sprintf("replication controller %s will be deleted by a later apply, once its pods are unscheduled", rc.ID),
+			},
+			apply: func(ctx context.Context, user string) error {
+				labeled, err := a.labeler.GetLabels(labels.RC, rc.ID.String())
+				if err != nil {
+					return err
+				}
+
+				txnCtx, cancel := transaction.New(ctx)
+				defer cancel()
+				err = a.rcStore.CASDesiredReplicasTxn(txnCtx, rc.ID, rc.ReplicasDesired, 0)
+				if err != nil {
+					return err
+				}
+				modified := rc
+				modified.ReplicasDesired = 0
+				err = a.auditRCTxn(txnCtx, audit.RCModifiedEvent, modified, labeled.Labels, user)
+				if err != nil {
+					return err
+				}
+				return transaction.MustCommit(txnCtx, a.txner)
+			},
+		}
+	}
+
+	return Change{
+		Kind:    RCKind,
+		Action:  DeleteAction,
+		Key:     key,
+		Details: []string{fmt.Sprintf("replication controller %s", rc.ID)},
+		apply: func(ctx context.Context, user string) error {
+			labeled, err := a.labeler.GetLabels(labels.RC, rc.ID.String())
+			if err != nil {
+				return err
+			}
+
+			txnCtx, cancel := transaction.New(ctx)
+			defer cancel()
+			err = a.rcStore.DeleteTxn(txnCtx, rc.ID, false)
+			if err != nil {
+				return err
+			}
+			err = a.auditRCTxn(txnCtx, audit.RCDeletedEvent, rc, labeled.Labels, user)
+			if err != nil {
+				return err
+			}
+			return transaction.MustCommit(txnCtx, a.txner)
+		},
+	}
+}
+
+// modifyRC makes a single change to a replication controller in a
+// transaction of its own, recording modified as the result in the audit log
+func (a Applier) modifyRC(ctx context.Context, modified rc_fields.RC, rcLabels map[string]string, user string, change func(txnCtx context.Context) error) error {
+	txnCtx, cancel := transaction.New(ctx)
+	defer cancel()
+	err := change(txnCtx)
+	if err != nil {
+		return err
+	}
+	err = a.auditRCTxn(txnCtx, audit.RCModifiedEvent, modified, rcLabels, user)
+	if err != nil {
+		return err
+	}
+	return transaction.MustCommit(txnCtx, a.txner)
+}
+
+func (a Applier) auditRCTxn(ctx context.Context, eventType audit.EventType, rc rc_fields.RC, rcLabels map[string]string, user string) error {
+	details, err := audit.NewRCDetails(rc, rcLabels, user)
+	if err != nil {
+		return err
+	}
+	err = a.auditLogStore.Create(ctx, eventType, details)
+	if err != nil {
+		return util.Errorf("could not create audit log record for replication controller %s: %s", rc.ID, err)
+	}
+	return nil
+}
+
+func formatSpread(constraints []rc_fields.SpreadConstraint) string {
+	if len(constraints) == 0 {
+		return "none"
+	}
+	var formatted []string
+	for _, constraint := range constraints {
+		formatted = append(formatted, fmt.Sprintf("%s (max skew %d)", constraint.TopologyKey, constraint.MaxSkew))
+	}
+	return strings.Join(formatted, ", ")
+}
+
+func formatAutoscale(policy *rc_fields.AutoscalePolicy) string {
+	if policy == nil {
+		return "none"
+	}
+	return fmt.Sprintf("%+v", *policy)
+}
+
+// rcLabels returns the labels the RC store gives a replication controller
+// created from spec
+func rcLabels(spec RCSpec) map[string]string {
+	rcLabels := make(map[string]string)
+	for k, v := range spec.Labels {
+		rcLabels[k] = v
+	}
+	rcLabels[rcstore.PodIDLabel] = spec.Manifest.ID().String()
+	return rcLabels
+}
+
+func (a Applier) planDSs(plan *Plan, specs []DSSpec, podIDs map[types.PodID]bool) error {
+	dss, err := a.dsStore.List()
+	if err != nil {
+		return util.Errorf("could not list daemon sets: %s", err)
+	}
+
+	live := make(map[string][]ds_fields.DaemonSet)
+	for _, ds := range dss {
+		if !podIDs[ds.PodID] {
+			continue
+		}
+		key := dsKey(ds.PodID, ds.Name)
+		live[key] = append(live[key], ds)
+	}
+
+	declared := make(map[string]bool)
+	for _, spec := range specs {
+		key := dsKey(spec.Manifest.ID(), spec.Name)
+		declared[key] = true
+
+		switch existing := live[key]; len(existing) {
+		case 0:
+			plan.Changes = append(plan.Changes, a.createDS(key, spec))
+		case 1:
+			if existing[0].Disabled {
+				plan.Notes = append(plan.Notes, fmt.Sprintf("%s %s is disabled, changes to it won't be rolled out until it is enabled", DSKind, key))
+			}
+			change, err := a.updateDS(key, spec, existing[0])
+			if err != nil {
+				return err
+			}
+			if change != nil {
+				plan.Changes = append(plan.Changes, *change)
+			}
+		default:
+			return util.Errorf("%d daemon sets match %s %s; delete all but one before applying", len(existing), DSKind, key)
+		}
+	}
+
+	var undeclared []string
+	for key := range live {
+		if !declared[key] {
+			undeclared = append(undeclared, key)
+		}
+	}
+	sort.Strings(undeclared)
+	for _, key := range undeclared {
+		for _, ds := range live[key] {
+			plan.Changes = append(plan.Changes, a.deleteDS(key, ds))
+		}
+	}
+	return nil
+}
+
+func (a Applier) createDS(key string, spec DSSpec) Change {
+	sha, _ := spec.Manifest.SHA()
+	details := []string{
+		fmt.Sprintf("manifest: %s", sha),
+		fmt.Sprintf("node selector: %s", spec.NodeSelector),
+		fmt.Sprintf("min health: %d", spec.MinHealth),
+		fmt.Sprintf("timeout: %s", spec.Timeout),
+	}
+	if spec.RollingStrategy != nil {
+		details = append(details, fmt.Sprintf("rolling strategy: %s", formatRollingStrategy(spec.RollingStrategy)))
+	}
+	if spec.FailureThreshold != "" {
+		details = append(details, fmt.Sprintf("failure threshold: %s", spec.FailureThreshold))
+	}
+
+	return Change{
+		Kind:    DSKind,
+		Action:  CreateAction,
+		Key:     key,
+		Details: details,
+		apply: func(ctx context.Context, user string) error {
+			txnCtx, cancel := transaction.New(ctx)
+			defer cancel()
+			ds, err := a.dsAuditingStore.Create(
+				txnCtx,
+				spec.Manifest,
+				spec.MinHealth,
+				spec.Name,
+				spec.NodeSelector,
+				spec.Manifest.ID(),
+				spec.Timeout,
+				user,
+			)
+			if err != nil {
+				return err
+			}
+			err = transaction.MustCommit(txnCtx, a.txner)
+			if err != nil {
+				return err
+			}
+
+			// Daemon sets are created without these settings, which
+			// only matter once the daemon set has been deployed
+			var updates []func(ctx context.Context, user string) (ds_fields.DaemonSet, error)
+			if spec.RollingStrategy != nil {
+				updates = append(updates, func(ctx context.Context, user string) (ds_fields.DaemonSet, error) {
+					return a.dsAuditingStore.UpdateRollingStrategy(ctx, ds.ID, spec.RollingStrategy, user)
+				})
+			}
+			if spec.FailureThreshold != "" {
+				updates = append(updates, func(ctx context.Context, user string) (ds_fields.DaemonSet, error) {
+					return a.dsAuditingStore.UpdateFailureThreshold(ctx, ds.ID, spec.FailureThreshold, user)
+				})
+			}
+			return a.updateDSFields(ctx, updates, user)
+		},
+	}
+}
+
+// updateDS returns the change that makes ds match spec, or nil if it already
+// does. The daemon set farm rolls manifest changes out to the daemon set's
+// nodes, so unlike replication controllers they are written in place.
+func (a Applier) updateDS(key string, spec DSSpec, ds ds_fields.DaemonSet) (*Change, error) {
+	oldSHA, err := ds.Manifest.SHA()
+	if err != nil {
+		return nil, err
+	}
+	newSHA, err := spec.Manifest.SHA()
+	if err != nil {
+		return nil, err
+	}
+
+	// Each field is updated in a transaction of its own, since every update
+	// is a check-and-set of the same key
+	var details []string
+	var updates []func(ctx context.Context, user string) (ds_fields.DaemonSet, error)
+	diff := ""
+	if oldSHA != newSHA {
+		details = append(details, fmt.Sprintf("manifest: %s -> %s", oldSHA, newSHA))
+		diff, err = ManifestDiff(ds.Manifest, spec.Manifest)
+		if err != nil {
+			return nil, err
+		}
+		updates = append(updates, func(ctx context.Context, user string) (ds_fields.DaemonSet, error) {
+			return a.dsAuditingStore.UpdateManifest(ctx, ds.ID, spec.Manifest, user)
+		})
+	}
+	if ds.NodeSelector.String() != spec.NodeSelector.String() {
+		details = append(details, fmt.Sprintf("node selector: %s -> %s", ds.NodeSelector, spec.NodeSelector))
+		updates = append(updates, func(ctx context.Context, user string) (ds_fields.DaemonSet, error) {
+			return a.dsAuditingStore.UpdateNodeSelector(ctx, ds.ID, spec.NodeSelector, user)
+		})
+	}
+	if ds.MinHealth != spec.MinHealth {
+		details = append(details, fmt.Sprintf("min health: %d -> %d", ds.MinHealth, spec.MinHealth))
+		updates = append(updates, func(ctx context.Context, user string) (ds_fields.DaemonSet, error) {
+			return a.dsAuditingStore.UpdateMinHealth(ctx, ds.ID, spec.MinHealth, user)
+		})
+	}
+	if ds.Timeout != spec.Timeout {
+		details = append(details, fmt.Sprintf("timeout: %s -> %s", ds.Timeout, spec.Timeout))
+		updates = append(updates, func(ctx context.Context, user string) (ds_fields.DaemonSet, error) {
+			return a.dsAuditingStore.UpdateTimeout(ctx, ds.ID, spec.Timeout, user)
+		})
+	}
+	if !reflect.DeepEqual(ds.RollingStrategy, spec.RollingStrategy) {
+		details = append(details, fmt.Sprintf("rolling strategy: %s -> %s", formatRollingStrategy(ds.RollingStrategy), formatRollingStrategy(spec.RollingStrategy)))
+		updates = append(updates, func(ctx context.Context, user string) (ds_fields.DaemonSet, error) {
+			return a.dsAuditingStore.UpdateRollingStrategy(ctx, ds.ID, spec.RollingStrategy, user)
+		})
+	}
+	if ds.FailureThreshold != spec.FailureThreshold {
+		details = append(details, fmt.Sprintf("failure threshold: %q -> %q", ds.FailureThreshold, spec.FailureThreshold))
+		updates = append(updates, func(ctx context.Context, user string) (ds_fields.DaemonSet, error) {
+			return a.dsAuditingStore.UpdateFailureThreshold(ctx, ds.ID, spec.FailureThreshold, user)
+		})
+	}
+	if len(updates) == 0 {
+		return nil, nil
+	}
+
+	return &Change{
+		Kind:         DSKind,
+		Action:       UpdateAction,
+		Key:          key,
+		Details:      details,
+		ManifestDiff: diff,
+		apply: func(ctx context.Context, user string) error {
+			return a.updateDSFields(ctx, updates, user)
+		},
+	}, nil
+}
+
+// updateDSFields commits each of updates in a transaction of its own
+func (a Applier) updateDSFields(ctx context.Context, updates []func(ctx context.Context, user string) (ds_fields.DaemonSet, error), user string) error {
+	for _, update := range updates {
+		txnCtx, cancel := transaction.New(ctx)
+		_, err := update(txnCtx, user)
+		if err == nil {
+			err = transaction.MustCommit(txnCtx, a.txner)
+		}
+		cancel()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatRollingStrategy(strategy *ds_fields.RollingStrategy) string {
+	if strategy == nil {
+		return "none"
+	}
+	return fmt.Sprintf("max unavailable %q, max surge %q, batch delay %s", strategy.MaxUnavailable, strategy.MaxSurge, strategy.BatchDelay)
+}
+
+func (a Applier) deleteDS(key string, ds ds_fields.DaemonSet) Change {
+	return Change{
+		Kind:    DSKind,
+		Action:  DeleteAction,
+		Key:     key,
+		Details: []string{fmt.Sprintf("daemon set %s", ds.ID)},
+		apply: func(ctx context.Context, user string) error {
+			txnCtx, cancel := transaction.New(ctx)
+			defer cancel()
+			err := a.dsAuditingStore.Delete(txnCtx, ds.ID, user)
+			if err != nil {
+				return err
+			}
+			return transaction.MustCommit(txnCtx, a.txner)
+		},
+	}
+}
+
+func (a Applier) planPCs(plan *Plan, specs []PCSpec, podIDs map[types.PodID]bool) error {
+	pcs, err := a.pcStore.List()
+	if err != nil {
+		return util.Errorf("could not list pod clusters: %s", err)
+	}
+
+	// The pod cluster store doesn't allow two pod clusters with the same
+	// pod ID, availability zone and cluster name
+	live := make(map[string]pc_fields.PodCluster)
+	for _, pc := range pcs {
+		if podIDs[pc.PodID] {
+			live[pcKey(pc.PodID, pc.AvailabilityZone, pc.Name)] = pc
+		}
+	}
+
+	declared := make(map[string]bool)
+	for _, spec := range specs {
+		key := pcKey(spec.PodID, spec.AvailabilityZone, spec.ClusterName)
+		declared[key] = true
+
+		existing, ok := live[key]
+		if !ok {
+			plan.Changes = append(plan.Changes, a.createPC(key, spec))
+			continue
+		}
+		change, err := a.updatePC(key, spec, existing)
+		if err != nil {
+			return err
+		}
+		if change != nil {
+			plan.Changes = append(plan.Changes, *change)
+		}
+	}
+
+	var undeclared []string
+	for key := range live {
+		if !declared[key] {
+			undeclared = append(undeclared, key)
+		}
+	}
+	sort.Strings(undeclared)
+	for _, key := range undeclared {
+		plan.Changes = append(plan.Changes, a.deletePC(key, live[key]))
+	}
+	return nil
+}
+
+func (a Applier) createPC(key string, spec PCSpec) Change {
+	return Change{
+		Kind:   PCKind,
+		Action: CreateAction,
+		Key:    key,
+		Details: []string{
+			fmt.Sprintf("pod selector: %s", spec.PodSelector),
+			fmt.Sprintf("annotations: %s", formatAnnotations(spec.Annotations)),
+			fmt.Sprintf("labels: %s", klabels.Set(pcLabels(spec))),
+			fmt.Sprintf("allocation strategy: %s", spec.AllocationStrategy),
+			fmt.Sprintf("min health percentage: %d", spec.MinHealthPercentage),
+		},
+		apply: func(ctx context.Context, user string) error {
+			pc, err := a.pcStore.Create(
+				spec.PodID,
+				spec.AvailabilityZone,
+				spec.ClusterName,
+				spec.PodSelector,
+				spec.Annotations,
+				spec.AllocationStrategy,
+				spec.MinHealthPercentage,
+				a.session,
+			)
+			if err != nil {
+				return err
+			}
+
+			// The pod cluster store labels new pod clusters with their
+			// pod ID, availability zone and cluster name
+			created := pcLabels(PCSpec{PodID: spec.PodID, AvailabilityZone: spec.AvailabilityZone, ClusterName: spec.ClusterName})
+			txnCtx, cancel := transaction.New(ctx)
+			defer cancel()
+			err = a.setLabelsTxn(txnCtx, labels.PC, pc.ID.String(), created, pcLabels(spec))
+			if err != nil {
+				return err
+			}
+			err = a.auditPCTxn(txnCtx, audit.PCCreatedEvent, pc, pcLabels(spec), user)
+			if err != nil {
+				return err
+			}
+			return transaction.MustCommit(txnCtx, a.txner)
+		},
+	}
+}
+
+// updatePC returns the change that makes pc match spec, or nil if it already
+// does. The pod cluster store has no transactional writes, so the pod
+// cluster is written first and its labels and audit record after.
+func (a Applier) updatePC(key string, spec PCSpec, pc pc_fields.PodCluster) (*Change, error) {
+	labeled, err := a.labeler.GetLabels(labels.PC, pc.ID.String())
+	if err != nil {
+		return nil, util.Errorf("could not get labels of pod cluster %s: %s", pc.ID, err)
+	}
+	currentLabels := labeled.Labels
+	desiredLabels := pcLabels(spec)
+
+	var details []string
+	fieldsChanged := false
+	if pc.PodSelector.String() != spec.PodSelector.String() {
+		details = append(details, fmt.Sprintf("pod selector: %s -> %s", pc.PodSelector, spec.PodSelector))
+		fieldsChanged = true
+	}
+	if !equalAnnotations(pc.Annotations, spec.Annotations) {
+		details = append(details, fmt.Sprintf("annotations: %s -> %s", formatAnnotations(pc.Annotations), formatAnnotations(spec.Annotations)))
+		fieldsChanged = true
+	}
+	if pc.AllocationStrategy != spec.AllocationStrategy {
+		details = append(details, fmt.Sprintf("allocation strategy: %s -> %s", pc.AllocationStrategy, spec.AllocationStrategy))
+		fieldsChanged = true
+	}
+	if pc.MinHealthPercentage != spec.MinHealthPercentage {
+		details = append(details, fmt.Sprintf("min health percentage: %d -> %d", pc.MinHealthPercentage, spec.MinHealthPercentage))
+		fieldsChanged = true
+	}
+	if klabels.Set(currentLabels).String() != klabels.Set(desiredLabels).String() {
+		details = append(details, fmt.Sprintf("labels: %s -> %s", klabels.Set(currentLabels), klabels.Set(desiredLabels)))
+	}
+	if len(details) == 0 {
+		return nil, nil
+	}
+
+	return &Change{
+		Kind:    PCKind,
+		Action:  UpdateAction,
+		Key:     key,
+		Details: details,
+		apply: func(ctx context.Context, user string) error {
+			updated := pc
+			if fieldsChanged {
+				var err error
+				updated, err = a.pcStore.MutatePC(pc.ID, func(pc pc_fields.PodCluster) (pc_fields.PodCluster, error) {
+					pc.PodSelector = spec.PodSelector
+					pc.Annotations = spec.Annotations
+					pc.AllocationStrategy = spec.AllocationStrategy
+					pc.MinHealthPercentage = spec.MinHealthPercentage
+					return pc, nil
+				})
+				if err != nil {
+					return err
+				}
+			}
+
+			err := a.commitLabelRemovals(ctx, labels.PC, pc.ID.String(), currentLabels, desiredLabels)
+			if err != nil {
+				return err
+			}
+			txnCtx, cancel := transaction.New(ctx)
+			defer cancel()
+			err = a.setLabelsTxn(txnCtx, labels.PC, pc.ID.String(), currentLabels, desiredLabels)
+			if err != nil {
+				return err
+			}
+			err = a.auditPCTxn(txnCtx, audit.PCModifiedEvent, updated, desiredLabels, user)
+			if err != nil {
+				return err
+			}
+			return transaction.MustCommit(txnCtx, a.txner)
+		},
+	}, nil
+}
+
+func (a Applier) deletePC(key string, pc pc_fields.PodCluster) Change {
+	return Change{
+		Kind:    PCKind,
+		Action:  DeleteAction,
+		Key:     key,
+		Details: []string{fmt.Sprintf("pod cluster %s", pc.ID)},
+		apply: func(ctx context.Context, user string) error {
+			labeled, err := a.labeler.GetLabels(labels.PC, pc.ID.String())
+			if err != nil {
+				return err
+			}
+			err = a.pcStore.Delete(pc.ID)
+			if err != nil {
+				return err
+			}
+
+			txnCtx, cancel := transaction.New(ctx)
+			defer cancel()
+			err = a.auditPCTxn(txnCtx, audit.PCDeletedEvent, pc, labeled.Labels, user)
+			if err != nil {
+				return err
+			}
+			return transaction.MustCommit(txnCtx, a.txner)
+		},
+	}
+}
+
+func (a Applier) auditPCTxn(ctx context.Context, eventType audit.EventType, pc pc_fields.PodCluster, pcLabels map[string]string, user string) error {
+	details, err := audit.NewPodClusterDetails(pc, pcLabels, user)
+	if err != nil {
+		return err
+	}
+	err = a.auditLogStore.Create(ctx, eventType, details)
+	if err != nil {
+		return util.Errorf("could not create audit log record for pod cluster %s: %s", pc.ID, err)
+	}
+	return nil
+}
+
+// pcLabels returns the labels of a pod cluster matching spec: those in the
+// spec along with the labels the pod cluster store gives every pod cluster
+func pcLabels(spec PCSpec) map[string]string {
+	pcLabels := make(map[string]string)
+	for k, v := range spec.Labels {
+		pcLabels[k] = v
+	}
+	pcLabels[pc_fields.PodIDLabel] = spec.PodID.String()
+	pcLabels[pc_fields.AvailabilityZoneLabel] = spec.AvailabilityZone.String()
+	pcLabels[pc_fields.ClusterNameLabel] = spec.ClusterName.String()
+	return pcLabels
+}
+
+func equalAnnotations(a, b pc_fields.Annotations) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func formatAnnotations(annotations pc_fields.Annotations) string {
+	if len(annotations) == 0 {
+		return "{}"
+	}
+	// Map keys are sorted when marshaling
+	b, err := json.Marshal(annotations)
+	if err != nil {
+		return fmt.Sprintf("%v", map[string]interface{}(annotations))
+	}
+	return string(b)
+}
+
+// commitLabelRemovals commits the removal of labels not in desired, if
+// labels are also being added or changed. An object's labels are stored in
+// one key and every label write is a check-and-set of it, so removing and
+// setting labels can't share a transaction. Otherwise setLabelsTxn removes
+// them along with the rest of the change.
+func (a Applier) commitLabelRemovals(ctx context.Context, labelType labels.Type, id string, current, desired map[string]string) error {
+	set, removed := labelChanges(current, desired)
+	if len(set) == 0 || len(removed) == 0 {
+		return nil
+	}
+
+	txnCtx, cancel := transaction.New(ctx)
+	defer cancel()
+	err := a.labeler.RemoveLabelsTxn(txnCtx, labelType, id, removed)
+	if err != nil {
+		return err
+	}
+	return transaction.MustCommit(txnCtx, a.txner)
+}
+
+// setLabelsTxn adds the label writes that make current equal to desired to
+// the transaction in ctx. commitLabelRemovals must have been called first.
+func (a Applier) setLabelsTxn(ctx context.Context, labelType labels.Type, id string, current, desired map[string]string) error {
+	set, removed := labelChanges(current, desired)
+	if len(set) > 0 {
+		return a.labeler.SetLabelsTxn(ctx, labelType, id, set)
+	}
+	if len(removed) > 0 {
+		return a.labeler.RemoveLabelsTxn(ctx, labelType, id, removed)
+	}
+	return nil
+}
+
+// labelChanges returns the labels in desired that are missing from current
+// or have a different value, and the keys in current that aren't in desired
+func labelChanges(current, desired map[string]string) (map[string]string, []string) {
+	set := make(map[string]string)
+	for k, v := range desired {
+		if currentValue, ok := current[k]; !ok || currentValue != v {
+			set[k] = v
+		}
+	}
+	var removed []string
+	for k := range current {
+		if _, ok := desired[k]; !ok {
+			removed = append(removed, k)
+		}
+	}
+	sort.Strings(removed)
+	return set, removed
+}
@@ -0,0 +1,643 @@
+package apply
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+	klabels "k8s.io/kubernetes/pkg/labels"
+
+	ds_fields "github.com/square/p2/pkg/ds/fields"
+	"github.com/square/p2/pkg/manifest"
+	pc_fields "github.com/square/p2/pkg/pc/fields"
+	rc_fields "github.com/square/p2/pkg/rc/fields"
+	roll_fields "github.com/square/p2/pkg/roll/fields"
+	"github.com/square/p2/pkg/types"
+	"github.com/square/p2/pkg/util"
+)
+
+type Kind string
+
+func (k Kind) String() string { return string(k) }
+
+const (
+	RCKind Kind = "replication_controller"
+	DSKind Kind = "daemon_set"
+	PCKind Kind = "pod_cluster"
+)
+
+// Document is the raw form of one YAML document in an apply file. Which
+// fields apply depends on the kind:
+//
+//	kind: replication_controller
+//	availability_zone: us-west
+//	cluster_name: production
+//	manifest: slug.yaml        # relative to the file
+//	node_selector: pool=web
+//	pod_labels: {team: payments}
+//	labels: {team: payments}
+//	replicas: 3
+//	minimum_replicas: 2        # used by rolling updates
+//	allocation_strategy: static_strategy
+//	spread_constraints:
+//	- {topology_key: rack, max_skew: 1}
+//	autoscale:                 # replicas is then only the initial count
+//	  min_replicas: 2
+//	  max_replicas: 10
+//	  target: 100
+//	  cooldown: 5m
+//	  metric: {type: http, url: "http://metrics/slug"}
+//	canary_replicas: 1         # the remaining fields are used by rolling updates
+//	canary_bake: 10m
+//	failure_policy:
+//	  progress_deadline: 30m
+//	  max_unhealthy: 1
+//	  max_unhealthy_duration: 10m
+//	  rollback: true
+//	zone_sequence:
+//	  label: availability_zone
+//	  order: [us-west-1a, us-west-1b]
+//	  pause: 15m
+//	  require_healthy: true
+//
+//	kind: daemon_set
+//	cluster_name: production
+//	manifest: agent.yaml
+//	node_selector: pool=web    # or everywhere: true
+//	min_health: 80
+//	timeout: 10m
+//	rolling_strategy: {max_unavailable: 10%, max_surge: 1, batch_delay: 1m}
+//	failure_threshold: 5%
+//
+//	kind: pod_cluster
+//	pod_id: slug
+//	availability_zone: us-west
+//	cluster_name: production
+//	pod_selector: app=slug     # defaults to the pod ID, zone and cluster labels
+//	annotations: {owner: payments}
+//	labels: {team: payments}
+//	allocation_strategy: static_strategy
+//	min_health_percentage: 80
+//
+// The pod ID of replication controllers and daemon sets is that of their
+// manifest.
+type Document struct {
+	Kind                Kind                   `yaml:"kind"`
+	PodID               string                 `yaml:"pod_id"`
+	AvailabilityZone    string                 `yaml:"availability_zone"`
+	ClusterName         string                 `yaml:"cluster_name"`
+	Manifest            string                 `yaml:"manifest"`
+	NodeSelector        string                 `yaml:"node_selector"`
+	Everywhere          bool                   `yaml:"everywhere"`
+	PodLabels           map[string]string      `yaml:"pod_labels"`
+	Labels              map[string]string      `yaml:"labels"`
+	Replicas            int                    `yaml:"replicas"`
+	MinimumReplicas     int                    `yaml:"minimum_replicas"`
+	AllocationStrategy  string                 `yaml:"allocation_strategy"`
+	MinHealth           int                    `yaml:"min_health"`
+	Timeout             time.Duration          `yaml:"timeout"`
+	PodSelector         string                 `yaml:"pod_selector"`
+	Annotations         map[string]interface{} `yaml:"annotations"`
+	MinHealthPercentage int                    `yaml:"min_health_percentage"`
+
+	SpreadConstraints []SpreadDocument       `yaml:"spread_constraints"`
+	Autoscale         *AutoscaleDocument     `yaml:"autoscale"`
+	CanaryReplicas    int                    `yaml:"canary_replicas"`
+	CanaryBake        time.Duration          `yaml:"canary_bake"`
+	FailurePolicy     *FailurePolicyDocument `yaml:"failure_policy"`
+	ZoneSequence      *ZoneSequenceDocument  `yaml:"zone_sequence"`
+
+	RollingStrategy  *RollingStrategyDocument `yaml:"rolling_strategy"`
+	FailureThreshold string                   `yaml:"failure_threshold"`
+}
+
+// The documents below are the YAML forms of the replication controller,
+// rolling update and daemon set settings of the same names
+
+type SpreadDocument struct {
+	TopologyKey string `yaml:"topology_key"`
+	MaxSkew     int    `yaml:"max_skew"`
+}
+
+type AutoscaleDocument struct {
+	MinReplicas int           `yaml:"min_replicas"`
+	MaxReplicas int           `yaml:"max_replicas"`
+	Target      float64       `yaml:"target"`
+	Cooldown    time.Duration `yaml:"cooldown"`
+	Metric      struct {
+		Type string `yaml:"type"`
+		URL  string `yaml:"url"`
+		Path string `yaml:"path"`
+		Port int    `yaml:"port"`
+	} `yaml:"metric"`
+}
+
+type FailurePolicyDocument struct {
+	ProgressDeadline     time.Duration `yaml:"progress_deadline"`
+	MaxUnhealthy         *int          `yaml:"max_unhealthy"`
+	MaxUnhealthyDuration time.Duration `yaml:"max_unhealthy_duration"`
+	Rollback             bool          `yaml:"rollback"`
+}
+
+type ZoneSequenceDocument struct {
+	Label          string        `yaml:"label"`
+	Order          []string      `yaml:"order"`
+	Pause          time.Duration `yaml:"pause"`
+	RequireHealthy bool          `yaml:"require_healthy"`
+}
+
+type RollingStrategyDocument struct {
+	MaxUnavailable string        `yaml:"max_unavailable"`
+	MaxSurge       string        `yaml:"max_surge"`
+	BatchDelay     time.Duration `yaml:"batch_delay"`
+}
+
+// RCSpec is the desired state of a replication controller. Replication
+// controllers are identified by their pod ID, availability zone and cluster
+// name. The canary, failure policy and zone sequence settings are given to
+// the rolling updates that change the replication controller, and Replicas
+// is only the initial replica count if Autoscale is set.
+type RCSpec struct {
+	Source             string
+	AvailabilityZone   pc_fields.AvailabilityZone
+	ClusterName        pc_fields.ClusterName
+	Manifest           manifest.Manifest
+	NodeSelector       klabels.Selector
+	PodLabels          klabels.Set
+	Labels             klabels.Set
+	Replicas           int
+	MinimumReplicas    int
+	AllocationStrategy rc_fields.Strategy
+	SpreadConstraints  []rc_fields.SpreadConstraint
+	Autoscale          *rc_fields.AutoscalePolicy
+	CanaryReplicas     int
+	CanaryBake         time.Duration
+	FailurePolicy      *roll_fields.FailurePolicy
+	ZoneSequence       *roll_fields.ZoneSequence
+}
+
+// DSSpec is the desired state of a daemon set. Daemon sets are identified by
+// their pod ID and cluster name.
+type DSSpec struct {
+	Source           string
+	Name             ds_fields.ClusterName
+	Manifest         manifest.Manifest
+	NodeSelector     klabels.Selector
+	MinHealth        int
+	Timeout          time.Duration
+	RollingStrategy  *ds_fields.RollingStrategy
+	FailureThreshold ds_fields.IntOrPercent
+}
+
+// PCSpec is the desired state of a pod cluster. Pod clusters are identified
+// by their pod ID, availability zone and cluster name.
+type PCSpec struct {
+	Source              string
+	PodID               types.PodID
+	AvailabilityZone    pc_fields.AvailabilityZone
+	ClusterName         pc_fields.ClusterName
+	PodSelector         klabels.Selector
+	Annotations         pc_fields.Annotations
+	Labels              klabels.Set
+	AllocationStrategy  rc_fields.Strategy
+	MinHealthPercentage pc_fields.MinHealthPercentage
+}
+
+// Specs is the desired state read from a set of apply files
+type Specs struct {
+	RCs []RCSpec
+	DSs []DSSpec
+	PCs []PCSpec
+}
+
+// PodIDs returns every pod ID with a spec of any kind
+func (s Specs) PodIDs() map[types.PodID]bool {
+	podIDs := make(map[types.PodID]bool)
+	for _, rc := range s.RCs {
+		podIDs[rc.Manifest.ID()] = true
+	}
+	for _, ds := range s.DSs {
+		podIDs[ds.Manifest.ID()] = true
+	}
+	for _, pc := range s.PCs {
+		podIDs[pc.PodID] = true
+	}
+	return podIDs
+}
+
+// ReadFiles reads the specs in each of the paths, which may each contain
+// several YAML documents separated by "---" lines
+func ReadFiles(paths []string) (Specs, error) {
+	var specs Specs
+	for _, path := range paths {
+		contents, err := ioutil.ReadFile(path)
+		if err != nil {
+			return Specs{}, err
+		}
+		docs, err := splitDocuments(contents)
+		if err != nil {
+			return Specs{}, util.Errorf("could not read %s: %s", path, err)
+		}
+
+		for i, raw := range docs {
+			var doc Document
+			err = yaml.Unmarshal(raw, &doc)
+			if err != nil {
+				return Specs{}, util.Errorf("could not parse document %d of %s: %s", i+1, path, err)
+			}
+			err = specs.add(doc, path, i+1)
+			if err != nil {
+				return Specs{}, util.Errorf("document %d of %s: %s", i+1, path, err)
+			}
+		}
+	}
+
+	return specs, specs.checkDuplicates()
+}
+
+func splitDocuments(contents []byte) ([][]byte, error) {
+	var docs [][]byte
+	var current bytes.Buffer
+	flush := func() {
+		if len(bytes.TrimSpace(current.Bytes())) > 0 {
+			docs = append(docs, append([]byte(nil), current.Bytes()...))
+		}
+		current.Reset()
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimRight(line, " \t") == "---" {
+			flush()
+			continue
+		}
+		current.WriteString(line)
+		current.WriteByte('\n')
+	}
+	flush()
+	return docs, scanner.Err()
+}
+
+func (s *Specs) add(doc Document, path string, index int) error {
+	source := filepath.Base(path)
+	if index > 1 {
+		source = fmt.Sprintf("%s#%d", source, index)
+	}
+	readManifest := func() (manifest.Manifest, error) {
+		if doc.Manifest == "" {
+			return nil, util.Errorf("manifest must be set")
+		}
+		manifestPath := doc.Manifest
+		if !filepath.IsAbs(manifestPath) {
+			manifestPath = filepath.Join(filepath.Dir(path), manifestPath)
+		}
+		return manifest.FromPath(manifestPath)
+	}
+
+	switch doc.Kind {
+	case RCKind:
+		if doc.AvailabilityZone == "" || doc.ClusterName == "" {
+			return util.Errorf("availability_zone and cluster_name must be set")
+		}
+		if doc.Replicas < 0 || doc.MinimumReplicas < 0 || doc.MinimumReplicas > doc.Replicas {
+			return util.Errorf("replicas must be at least minimum_replicas, and neither may be negative")
+		}
+		man, err := readManifest()
+		if err != nil {
+			return err
+		}
+		nodeSelector, err := klabels.Parse(doc.NodeSelector)
+		if err != nil {
+			return util.Errorf("could not parse node_selector: %s", err)
+		}
+		strategy, err := parseStrategy(doc.AllocationStrategy)
+		if err != nil {
+			return err
+		}
+		var spread []rc_fields.SpreadConstraint
+		for _, constraint := range doc.SpreadConstraints {
+			spread = append(spread, rc_fields.SpreadConstraint{
+				TopologyKey: constraint.TopologyKey,
+				MaxSkew:     constraint.MaxSkew,
+			})
+			err = spread[len(spread)-1].Validate()
+			if err != nil {
+				return err
+			}
+		}
+		autoscale, err := parseAutoscale(doc.Autoscale, doc.Replicas)
+		if err != nil {
+			return err
+		}
+		if doc.CanaryReplicas < 0 || doc.CanaryBake < 0 {
+			return util.Errorf("canary_replicas and canary_bake must not be negative")
+		}
+		failurePolicy, err := parseFailurePolicy(doc.FailurePolicy)
+		if err != nil {
+			return err
+		}
+		var zoneSequence *roll_fields.ZoneSequence
+		if doc.ZoneSequence != nil {
+			if doc.ZoneSequence.Label == "" || doc.ZoneSequence.Pause < 0 {
+				return util.Errorf("zone_sequence must have a label and a non-negative pause")
+			}
+			zoneSequence = &roll_fields.ZoneSequence{
+				Label:          doc.ZoneSequence.Label,
+				Order:          doc.ZoneSequence.Order,
+				Pause:          doc.ZoneSequence.Pause,
+				RequireHealthy: doc.ZoneSequence.RequireHealthy,
+			}
+		}
+
+		// As p2-rctl does, the pods are labeled with the RC's zone and
+		// cluster so that pod clusters can select them
+		podLabels := klabels.Set{}
+		for k, v := range doc.PodLabels {
+			podLabels[k] = v
+		}
+		podLabels[types.AvailabilityZoneLabel] = doc.AvailabilityZone
+		podLabels[types.ClusterNameLabel] = doc.ClusterName
+
+		s.RCs = append(s.RCs, RCSpec{
+			Source:             source,
+			AvailabilityZone:   pc_fields.AvailabilityZone(doc.AvailabilityZone),
+			ClusterName:        pc_fields.ClusterName(doc.ClusterName),
+			Manifest:           man,
+			NodeSelector:       nodeSelector,
+			PodLabels:          podLabels,
+			Labels:             klabels.Set(doc.Labels),
+			Replicas:           doc.Replicas,
+			MinimumReplicas:    doc.MinimumReplicas,
+			AllocationStrategy: strategy,
+			SpreadConstraints:  spread,
+			Autoscale:          autoscale,
+			CanaryReplicas:     doc.CanaryReplicas,
+			CanaryBake:         doc.CanaryBake,
+			FailurePolicy:      failurePolicy,
+			ZoneSequence:       zoneSequence,
+		})
+
+	case DSKind:
+		if doc.ClusterName == "" {
+			return util.Errorf("cluster_name must be set")
+		}
+		if doc.Timeout <= 0 {
+			return util.Errorf("timeout must be positive")
+		}
+		man, err := readManifest()
+		if err != nil {
+			return err
+		}
+		nodeSelector := klabels.Everything()
+		if !doc.Everywhere {
+			if doc.NodeSelector == "" {
+				return util.Errorf("node_selector must be set, or everywhere must be true")
+			}
+			nodeSelector, err = klabels.Parse(doc.NodeSelector)
+			if err != nil {
+				return util.Errorf("could not parse node_selector: %s", err)
+			}
+		}
+		var rollingStrategy *ds_fields.RollingStrategy
+		if doc.RollingStrategy != nil {
+			rollingStrategy = &ds_fields.RollingStrategy{
+				MaxUnavailable: ds_fields.IntOrPercent(doc.RollingStrategy.MaxUnavailable),
+				MaxSurge:       ds_fields.IntOrPercent(doc.RollingStrategy.MaxSurge),
+				BatchDelay:     doc.RollingStrategy.BatchDelay,
+			}
+			err = rollingStrategy.Validate()
+			if err != nil {
+				return util.Errorf("invalid rolling_strategy: %s", err)
+			}
+		}
+		failureThreshold := ds_fields.IntOrPercent(doc.FailureThreshold)
+		_, err = failureThreshold.Resolve(100, false)
+		if err != nil {
+			return util.Errorf("invalid failure_threshold: %s", err)
+		}
+
+		s.DSs = append(s.DSs, DSSpec{
+			Source:           source,
+			Name:             ds_fields.ClusterName(doc.ClusterName),
+			Manifest:         man,
+			NodeSelector:     nodeSelector,
+			MinHealth:        doc.MinHealth,
+			Timeout:          doc.Timeout,
+			RollingStrategy:  rollingStrategy,
+			FailureThreshold: failureThreshold,
+		})
+
+	case PCKind:
+		if doc.PodID == "" || doc.AvailabilityZone == "" || doc.ClusterName == "" {
+			return util.Errorf("pod_id, availability_zone and cluster_name must be set")
+		}
+		if doc.MinHealthPercentage < 0 || doc.MinHealthPercentage > 100 {
+			return util.Errorf("min_health_percentage must be between 0 and 100")
+		}
+		// Selectors read back from the store are sorted by key, so the
+		// default is built from a set to sort it the same way
+		podSelector := klabels.Set{
+			pc_fields.PodIDLabel:            doc.PodID,
+			pc_fields.AvailabilityZoneLabel: doc.AvailabilityZone,
+			pc_fields.ClusterNameLabel:      doc.ClusterName,
+		}.AsSelector()
+		if doc.PodSelector != "" {
+			var err error
+			podSelector, err = klabels.Parse(doc.PodSelector)
+			if err != nil {
+				return util.Errorf("could not parse pod_selector: %s", err)
+			}
+		}
+		strategy, err := parseStrategy(doc.AllocationStrategy)
+		if err != nil {
+			return err
+		}
+		annotations, err := normalizeAnnotations(doc.Annotations)
+		if err != nil {
+			return err
+		}
+
+		s.PCs = append(s.PCs, PCSpec{
+			Source:              source,
+			PodID:               types.PodID(doc.PodID),
+			AvailabilityZone:    pc_fields.AvailabilityZone(doc.AvailabilityZone),
+			ClusterName:         pc_fields.ClusterName(doc.ClusterName),
+			PodSelector:         podSelector,
+			Annotations:         annotations,
+			Labels:              klabels.Set(doc.Labels),
+			AllocationStrategy:  strategy,
+			MinHealthPercentage: pc_fields.MinHealthPercentage(doc.MinHealthPercentage),
+		})
+
+	default:
+		return util.Errorf("kind must be one of %s, %s or %s, was %q", RCKind, DSKind, PCKind, doc.Kind)
+	}
+	return nil
+}
+
+func parseStrategy(strategy string) (rc_fields.Strategy, error) {
+	switch rc_fields.Strategy(strategy) {
+	case "":
+		return rc_fields.StaticStrategy, nil
+	case rc_fields.StaticStrategy, rc_fields.DynamicStrategy:
+		return rc_fields.Strategy(strategy), nil
+	default:
+		return "", util.Errorf("allocation_strategy must be %s or %s", rc_fields.StaticStrategy, rc_fields.DynamicStrategy)
+	}
+}
+
+// parseAutoscale converts an autoscale document, checking that the initial
+// replica count is one the autoscaler would allow
+func parseAutoscale(doc *AutoscaleDocument, replicas int) (*rc_fields.AutoscalePolicy, error) {
+	if doc == nil {
+		return nil, nil
+	}
+	policy := &rc_fields.AutoscalePolicy{
+		MinReplicas: doc.MinReplicas,
+		MaxReplicas: doc.MaxReplicas,
+		Target:      doc.Target,
+		Cooldown:    doc.Cooldown,
+		Metric: rc_fields.MetricSource{
+			Type: rc_fields.MetricSourceType(doc.Metric.Type),
+			URL:  doc.Metric.URL,
+			Path: doc.Metric.Path,
+			Port: doc.Metric.Port,
+		},
+	}
+	err := policy.Validate()
+	if err != nil {
+		return nil, err
+	}
+	if replicas < policy.MinReplicas || replicas > policy.MaxReplicas {
+		return nil, util.Errorf("replicas must be between the autoscale min_replicas and max_replicas")
+	}
+	return policy, nil
+}
+
+func parseFailurePolicy(doc *FailurePolicyDocument) (*roll_fields.FailurePolicy, error) {
+	if doc == nil {
+		return nil, nil
+	}
+	if doc.ProgressDeadline < 0 || doc.MaxUnhealthyDuration < 0 || (doc.MaxUnhealthy != nil && *doc.MaxUnhealthy < 0) {
+		return nil, util.Errorf("failure_policy limits must not be negative")
+	}
+	return &roll_fields.FailurePolicy{
+		ProgressDeadline:     doc.ProgressDeadline,
+		MaxUnhealthy:         doc.MaxUnhealthy,
+		MaxUnhealthyDuration: doc.MaxUnhealthyDuration,
+		Rollback:             doc.Rollback,
+	}, nil
+}
+
+// checkDuplicates returns an error if two specs describe the same object
+func (s Specs) checkDuplicates() error {
+	seen := make(map[string]string)
+	check := func(kind Kind, key string, source string) error {
+		described := fmt.Sprintf("%s %s", kind, key)
+		if previous, ok := seen[described]; ok {
+			return util.Errorf("%s and %s both describe %s", previous, source, described)
+		}
+		seen[described] = source
+		return nil
+	}
+
+	for _, rc := range s.RCs {
+		err := check(RCKind, rcKey(rc.Manifest.ID(), rc.AvailabilityZone, rc.ClusterName), rc.Source)
+		if err != nil {
+			return err
+		}
+	}
+	for _, ds := range s.DSs {
+		err := check(DSKind, dsKey(ds.Manifest.ID(), ds.Name), ds.Source)
+		if err != nil {
+			return err
+		}
+	}
+	for _, pc := range s.PCs {
+		err := check(PCKind, pcKey(pc.PodID, pc.AvailabilityZone, pc.ClusterName), pc.Source)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// normalizeAnnotations converts annotations parsed from YAML into the form
+// they take when read back from Consul as JSON, so that the two can be
+// compared: nested maps have string keys and numbers are float64s
+func normalizeAnnotations(raw map[string]interface{}) (pc_fields.Annotations, error) {
+	annotations := pc_fields.Annotations{}
+	if len(raw) == 0 {
+		return annotations, nil
+	}
+
+	converted := make(map[string]interface{}, len(raw))
+	for k, v := range raw {
+		value, err := stringKeys(v)
+		if err != nil {
+			return nil, err
+		}
+		converted[k] = value
+	}
+	b, err := json.Marshal(converted)
+	if err != nil {
+		return nil, util.Errorf("could not convert annotations to JSON: %s", err)
+	}
+	err = json.Unmarshal(b, &annotations)
+	if err != nil {
+		return nil, util.Errorf("could not convert annotations to JSON: %s", err)
+	}
+	return annotations, nil
+}
+
+// stringKeys replaces the map[interface{}]interface{} values yaml produces
+// for nested maps with map[string]interface{}, which can be marshaled as JSON
+func stringKeys(v interface{}) (interface{}, error) {
+	switch typed := v.(type) {
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(typed))
+		for k, value := range typed {
+			key, ok := k.(string)
+			if !ok {
+				return nil, util.Errorf("annotation keys must be strings, found %v", k)
+			}
+			convertedValue, err := stringKeys(value)
+			if err != nil {
+				return nil, err
+			}
+			converted[key] = convertedValue
+		}
+		return converted, nil
+	case []interface{}:
+		converted := make([]interface{}, len(typed))
+		for i, value := range typed {
+			convertedValue, err := stringKeys(value)
+			if err != nil {
+				return nil, err
+			}
+			converted[i] = convertedValue
+		}
+		return converted, nil
+	default:
+		return v, nil
+	}
+}
+
+func rcKey(podID types.PodID, az pc_fields.AvailabilityZone, cn pc_fields.ClusterName) string {
+	return fmt.Sprintf("%s/%s/%s", podID, az, cn)
+}
+
+func dsKey(podID types.PodID, name ds_fields.ClusterName) string {
+	return fmt.Sprintf("%s/%s", podID, name)
+}
+
+func pcKey(podID types.PodID, az pc_fields.AvailabilityZone, cn pc_fields.ClusterName) string {
+	return fmt.Sprintf("%s/%s/%s", podID, az, cn)
+}
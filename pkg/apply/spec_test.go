@@ -0,0 +1,196 @@
+package apply
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	ds_fields "github.com/square/p2/pkg/ds/fields"
+	pc_fields "github.com/square/p2/pkg/pc/fields"
+	rc_fields "github.com/square/p2/pkg/rc/fields"
+	"github.com/square/p2/pkg/types"
+)
+
+const testManifest = `id: slug
+status_port: 8000
+`
+
+// writeSpecs writes files, keyed by name, to a temporary directory along
+// with a manifest named slug.yaml, and returns the directory
+func writeSpecs(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "apply")
+	if err != nil {
+		t.Fatal(err)
+	}
+	files["slug.yaml"] = testManifest
+	for name, contents := range files {
+		err = ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestReadFiles(t *testing.T) {
+	dir := writeSpecs(t, map[string]string{"apply.yaml": `
+kind: replication_controller
+availability_zone: us-west
+cluster_name: production
+manifest: slug.yaml
+node_selector: pool=web
+labels: {team: payments}
+replicas: 3
+minimum_replicas: 2
+---
+kind: daemon_set
+cluster_name: production
+manifest: slug.yaml
+everywhere: true
+min_health: 80
+timeout: 10m
+---
+kind: pod_cluster
+pod_id: slug
+availability_zone: us-west
+cluster_name: production
+annotations:
+  load_balancer_ports: {http: 8080}
+min_health_percentage: 50
+`})
+	defer os.RemoveAll(dir)
+
+	specs, err := ReadFiles([]string{filepath.Join(dir, "apply.yaml")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(specs.RCs) != 1 || len(specs.DSs) != 1 || len(specs.PCs) != 1 {
+		t.Fatalf("expected one spec of each kind, got %+v", specs)
+	}
+
+	rc := specs.RCs[0]
+	if rc.Manifest.ID() != "slug" || rc.NodeSelector.String() != "pool=web" || rc.Replicas != 3 || rc.MinimumReplicas != 2 {
+		t.Errorf("unexpected replication controller spec %+v", rc)
+	}
+	if rc.AllocationStrategy != rc_fields.StaticStrategy {
+		t.Errorf("expected the static strategy by default, got %s", rc.AllocationStrategy)
+	}
+	if rc.PodLabels[types.AvailabilityZoneLabel] != "us-west" || rc.PodLabels[types.ClusterNameLabel] != "production" {
+		t.Errorf("expected the zone and cluster as pod labels, got %s", rc.PodLabels)
+	}
+	if rc.Source != "apply.yaml" {
+		t.Errorf("expected the first document's source to be the file name, got %s", rc.Source)
+	}
+
+	ds := specs.DSs[0]
+	if !ds.NodeSelector.Empty() || ds.Timeout != 10*time.Minute || ds.MinHealth != 80 || ds.Source != "apply.yaml#2" {
+		t.Errorf("unexpected daemon set spec %+v", ds)
+	}
+
+	pc := specs.PCs[0]
+	if pc.PodSelector.String() != "availability_zone=us-west,cluster_name=production,pod_id=slug" {
+		t.Errorf("expected the default pod selector, got %s", pc.PodSelector)
+	}
+	expected := pc_fields.Annotations{"load_balancer_ports": map[string]interface{}{"http": float64(8080)}}
+	if !reflect.DeepEqual(pc.Annotations, expected) {
+		t.Errorf("expected annotations to be converted to their JSON form %v, got %v", expected, pc.Annotations)
+	}
+}
+
+func TestReadFilesOptions(t *testing.T) {
+	dir := writeSpecs(t, map[string]string{"apply.yaml": `
+kind: replication_controller
+availability_zone: us-west
+cluster_name: production
+manifest: slug.yaml
+replicas: 3
+spread_constraints:
+- {topology_key: rack, max_skew: 1}
+autoscale:
+  min_replicas: 2
+  max_replicas: 10
+  target: 100
+  cooldown: 5m
+  metric: {type: http, url: "http://metrics/slug"}
+canary_replicas: 1
+canary_bake: 10m
+failure_policy:
+  max_unhealthy: 0
+  rollback: true
+zone_sequence:
+  label: availability_zone
+  order: [us-west-1a, us-west-1b]
+---
+kind: daemon_set
+cluster_name: production
+manifest: slug.yaml
+everywhere: true
+timeout: 10m
+rolling_strategy: {max_unavailable: 10%, batch_delay: 1m}
+failure_threshold: 5%
+`})
+	defer os.RemoveAll(dir)
+
+	specs, err := ReadFiles([]string{filepath.Join(dir, "apply.yaml")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rc := specs.RCs[0]
+	expectedSpread := []rc_fields.SpreadConstraint{{TopologyKey: "rack", MaxSkew: 1}}
+	if !reflect.DeepEqual(rc.SpreadConstraints, expectedSpread) {
+		t.Errorf("expected spread constraints %+v, got %+v", expectedSpread, rc.SpreadConstraints)
+	}
+	expectedAutoscale := &rc_fields.AutoscalePolicy{
+		MinReplicas: 2,
+		MaxReplicas: 10,
+		Target:      100,
+		Cooldown:    5 * time.Minute,
+		Metric:      rc_fields.MetricSource{Type: rc_fields.HTTPMetricSource, URL: "http://metrics/slug"},
+	}
+	if !reflect.DeepEqual(rc.Autoscale, expectedAutoscale) {
+		t.Errorf("expected autoscale policy %+v, got %+v", expectedAutoscale, rc.Autoscale)
+	}
+	if rc.CanaryReplicas != 1 || rc.CanaryBake != 10*time.Minute {
+		t.Errorf("expected 1 canary baked for 10m, got %d baked for %s", rc.CanaryReplicas, rc.CanaryBake)
+	}
+	if rc.FailurePolicy == nil || rc.FailurePolicy.MaxUnhealthy == nil || *rc.FailurePolicy.MaxUnhealthy != 0 || !rc.FailurePolicy.Rollback {
+		t.Errorf("expected a failure policy allowing no unhealthy replicas and rolling back, got %+v", rc.FailurePolicy)
+	}
+	if rc.ZoneSequence == nil || rc.ZoneSequence.Label != "availability_zone" || !reflect.DeepEqual(rc.ZoneSequence.Order, []string{"us-west-1a", "us-west-1b"}) {
+		t.Errorf("unexpected zone sequence %+v", rc.ZoneSequence)
+	}
+
+	ds := specs.DSs[0]
+	expectedStrategy := &ds_fields.RollingStrategy{MaxUnavailable: "10%", BatchDelay: time.Minute}
+	if !reflect.DeepEqual(ds.RollingStrategy, expectedStrategy) || ds.FailureThreshold != "5%" {
+		t.Errorf("expected rolling strategy %+v and failure threshold 5%%, got %+v and %s", expectedStrategy, ds.RollingStrategy, ds.FailureThreshold)
+	}
+}
+
+func TestReadFilesErrors(t *testing.T) {
+	for name, contents := range map[string]string{
+		"unknown kind":    "kind: pod",
+		"no manifest":     "kind: daemon_set\ncluster_name: production\ntimeout: 1m\neverywhere: true",
+		"no selector":     "kind: daemon_set\ncluster_name: production\nmanifest: slug.yaml\ntimeout: 1m",
+		"bad strategy":    "kind: pod_cluster\npod_id: slug\navailability_zone: us-west\ncluster_name: production\nallocation_strategy: random",
+		"bad replicas":    "kind: replication_controller\navailability_zone: us-west\ncluster_name: production\nmanifest: slug.yaml\nreplicas: 1\nminimum_replicas: 2",
+		"bad autoscale":   "kind: replication_controller\navailability_zone: us-west\ncluster_name: production\nmanifest: slug.yaml\nreplicas: 1\nautoscale: {min_replicas: 2, max_replicas: 4, target: 1, metric: {type: pod, path: /load}}",
+		"bad spread":      "kind: replication_controller\navailability_zone: us-west\ncluster_name: production\nmanifest: slug.yaml\nspread_constraints: [{topology_key: rack}]",
+		"bad rolling":     "kind: daemon_set\ncluster_name: production\nmanifest: slug.yaml\ntimeout: 1m\neverywhere: true\nrolling_strategy: {max_unavailable: 0}",
+		"duplicate specs": "kind: pod_cluster\npod_id: slug\navailability_zone: us-west\ncluster_name: production\n---\nkind: pod_cluster\npod_id: slug\navailability_zone: us-west\ncluster_name: production",
+	} {
+		dir := writeSpecs(t, map[string]string{"apply.yaml": contents})
+		_, err := ReadFiles([]string{filepath.Join(dir, "apply.yaml")})
+		if err == nil {
+			t.Errorf("%s: expected an error", name)
+		} else if name == "duplicate specs" && !strings.Contains(err.Error(), "apply.yaml and apply.yaml#2") {
+			t.Errorf("expected the duplicate error to name both documents, got %s", err)
+		}
+		os.RemoveAll(dir)
+	}
+}
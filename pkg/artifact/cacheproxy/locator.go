@@ -0,0 +1,84 @@
+package cacheproxy
+
+import (
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	klabels "k8s.io/kubernetes/pkg/labels"
+
+	"github.com/square/p2/pkg/labels"
+	"github.com/square/p2/pkg/uri"
+)
+
+// DefaultLocatorRefresh is how long a LabelLocator caches the set of proxy
+// nodes before asking the label store again.
+const DefaultLocatorRefresh = 1 * time.Minute
+
+// Labeler is the subset of labels.Applicator used to find proxy nodes.
+type Labeler interface {
+	GetMatches(selector klabels.Selector, labelType labels.Type) ([]labels.Labeled, error)
+}
+
+// LabelLocator advertises the proxies running on every node matching a label
+// selector, e.g. "artifact_proxy=true". It implements uri.ProxyLocator.
+type LabelLocator struct {
+	labeler  Labeler
+	selector klabels.Selector
+	scheme   string
+	port     int
+	refresh  time.Duration
+
+	mux       sync.Mutex
+	proxies   []*url.URL
+	refreshed time.Time
+}
+
+var _ uri.ProxyLocator = &LabelLocator{}
+
+func NewLabelLocator(labeler Labeler, selector klabels.Selector, scheme string, port int) *LabelLocator {
+	if scheme == "" {
+		scheme = "http"
+	}
+	return &LabelLocator{
+		labeler:  labeler,
+		selector: selector,
+		scheme:   scheme,
+		port:     port,
+		refresh:  DefaultLocatorRefresh,
+	}
+}
+
+func (l *LabelLocator) Proxies() ([]*url.URL, error) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	if l.proxies != nil && time.Since(l.refreshed) < l.refresh {
+		return l.proxies, nil
+	}
+
+	matches, err := l.labeler.GetMatches(l.selector, labels.NODE)
+	if err != nil {
+		// Keep using the last known proxies, if there are any
+		if l.proxies != nil {
+			return l.proxies, nil
+		}
+		return nil, err
+	}
+
+	proxies := make([]*url.URL, 0, len(matches))
+	for _, match := range matches {
+		proxies = append(proxies, &url.URL{
+			Scheme: l.scheme,
+			Host:   net.JoinHostPort(match.ID, strconv.Itoa(l.port)),
+		})
+	}
+	sort.Slice(proxies, func(i, j int) bool {
+		return proxies[i].Host < proxies[j].Host
+	})
+	l.proxies = proxies
+	l.refreshed = time.Now()
+	return proxies, nil
+}
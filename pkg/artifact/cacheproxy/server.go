@@ -0,0 +1,300 @@
+// Package cacheproxy implements a pull-through cache for artifacts. Nodes
+// configured to use it (see uri.ProxyFetcher) ask the proxy for an origin URI
+// and the proxy downloads it once, no matter how many nodes ask for it at the
+// same time, and serves every later request from its cache.
+//
+// The proxy does not verify what it caches. Nodes continue to check every
+// artifact with their auth.ArtifactVerifier, fetching signatures from the
+// origin, so a misbehaving proxy can cause failed installs but not
+// untrusted ones.
+package cacheproxy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+	"github.com/sirupsen/logrus"
+
+	"github.com/square/p2/pkg/logging"
+	"github.com/square/p2/pkg/uri"
+	"github.com/square/p2/pkg/util"
+)
+
+const tempPrefix = ".fill-"
+
+// fill tracks a download into the cache so that concurrent requests for the
+// same origin wait for it rather than starting their own.
+type fill struct {
+	done chan struct{}
+	err  error
+}
+
+type Server struct {
+	cacheDir     string
+	maxCacheSize int64
+	fetcher      uri.Fetcher
+	allowed      []string
+	logger       logging.Logger
+
+	mux   sync.Mutex
+	fills map[string]*fill
+
+	hits   metrics.Counter
+	misses metrics.Counter
+	errors metrics.Counter
+}
+
+// New creates a caching proxy that stores at most maxCacheSize bytes in
+// cacheDir, evicting the least recently used artifacts first. Only origin
+// URIs starting with one of allowedOrigins are proxied.
+func New(
+	cacheDir string,
+	maxCacheSize int64,
+	fetcher uri.Fetcher,
+	allowedOrigins []string,
+	logger logging.Logger,
+	registry metrics.Registry,
+) (*Server, error) {
+	if len(allowedOrigins) == 0 {
+		return nil, util.Errorf("at least one allowed origin must be configured")
+	}
+	if fetcher == nil {
+		fetcher = uri.DefaultFetcher
+	}
+	if registry == nil {
+		registry = metrics.NewRegistry()
+	}
+	err := os.MkdirAll(cacheDir, 0755)
+	if err != nil {
+		return nil, util.Errorf("could not create cache directory: %s", err)
+	}
+	// Remove partial downloads left by a previous run
+	partials, err := filepath.Glob(filepath.Join(cacheDir, tempPrefix+"*"))
+	if err != nil {
+		return nil, err
+	}
+	for _, partial := range partials {
+		_ = os.Remove(partial)
+	}
+
+	return &Server{
+		cacheDir:     cacheDir,
+		maxCacheSize: maxCacheSize,
+		fetcher:      fetcher,
+		allowed:      allowedOrigins,
+		logger:       logger,
+		fills:        make(map[string]*fill),
+
+		hits:   metrics.GetOrRegisterCounter("artifact_proxy_hits", registry),
+		misses: metrics.GetOrRegisterCounter("artifact_proxy_misses", registry),
+		errors: metrics.GetOrRegisterCounter("artifact_proxy_errors", registry),
+	}, nil
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(uri.ProxyFetchPath, s.Fetch)
+	return mux
+}
+
+// Fetch serves the origin URI named by the "url" query parameter, filling
+// the cache first if necessary. Range requests are honored so that clients
+// can resume interrupted downloads from the proxy.
+func (s *Server) Fetch(resp http.ResponseWriter, req *http.Request) {
+	if req.Method != "GET" && req.Method != "HEAD" {
+		http.Error(resp, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	origin, err := url.Parse(req.URL.Query().Get(uri.ProxyURLParam))
+	if err != nil || origin.String() == "" {
+		http.Error(resp, "a url must be provided", http.StatusBadRequest)
+		return
+	}
+	if !s.isAllowed(origin) {
+		http.Error(resp, "origin is not allowed", http.StatusForbidden)
+		return
+	}
+
+	logger := s.logger.SubLogger(logrus.Fields{"url": origin.String()})
+	path := s.cachePath(origin)
+
+	if req.Method == "HEAD" {
+		if _, err := os.Stat(path); err != nil {
+			s.headOrigin(resp, origin, logger)
+			return
+		}
+	} else {
+		err = s.ensureCached(origin, path, logger)
+		if err != nil {
+			s.errors.Inc(1)
+			logger.WithError(err).Warnln("Could not fetch from origin")
+			http.Error(resp, err.Error(), http.StatusBadGateway)
+			return
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		// The file may have been evicted between filling and opening it;
+		// the client will retry or fall back to the origin
+		http.Error(resp, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		http.Error(resp, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	resp.Header().Set("Content-Type", "application/octet-stream")
+	// The cache key and size serve as a validator for If-Range, so that a
+	// client resuming a download never splices in a refilled entry
+	resp.Header().Set("ETag", fmt.Sprintf(`"%s-%d"`, filepath.Base(path), info.Size()))
+	http.ServeContent(resp, req, "", time.Time{}, file)
+}
+
+func (s *Server) headOrigin(resp http.ResponseWriter, origin *url.URL, logger logging.Logger) {
+	originResp, err := s.fetcher.Head(origin)
+	if err != nil {
+		logger.WithError(err).Warnln("Could not reach origin")
+		http.Error(resp, err.Error(), http.StatusBadGateway)
+		return
+	}
+	_ = originResp.Body.Close()
+	resp.WriteHeader(originResp.StatusCode)
+}
+
+// ensureCached returns once the origin is present in the cache, downloading
+// it if needed. Only one download per origin runs at a time.
+func (s *Server) ensureCached(origin *url.URL, path string, logger logging.Logger) error {
+	s.mux.Lock()
+	if _, err := os.Stat(path); err == nil {
+		s.mux.Unlock()
+		s.hits.Inc(1)
+		return nil
+	}
+	f, inProgress := s.fills[path]
+	if !inProgress {
+		f = &fill{done: make(chan struct{})}
+		s.fills[path] = f
+	}
+	s.mux.Unlock()
+
+	if inProgress {
+		<-f.done
+		return f.err
+	}
+
+	s.misses.Inc(1)
+	logger.Infoln("Filling artifact cache")
+	f.err = s.download(origin, path)
+	if f.err == nil {
+		s.evict(path)
+	}
+
+	s.mux.Lock()
+	delete(s.fills, path)
+	s.mux.Unlock()
+	close(f.done)
+	return f.err
+}
+
+func (s *Server) download(origin *url.URL, path string) error {
+	src, err := s.fetcher.Open(origin)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tmp, err := ioutil.TempFile(s.cacheDir, tempPrefix)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = io.Copy(tmp, src)
+	if errC := tmp.Close(); err == nil {
+		err = errC
+	}
+	if err != nil {
+		return err
+	}
+	err = os.Chmod(tmp.Name(), 0644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// evict removes the least recently used artifacts until the cache fits in
+// its size limit. The artifact that was just added is never removed.
+func (s *Server) evict(keep string) {
+	if s.maxCacheSize <= 0 {
+		return
+	}
+	infos, err := ioutil.ReadDir(s.cacheDir)
+	if err != nil {
+		s.logger.WithError(err).Errorln("Could not list artifact cache")
+		return
+	}
+
+	var total int64
+	var entries []os.FileInfo
+	for _, info := range infos {
+		if strings.HasPrefix(info.Name(), tempPrefix) || info.IsDir() {
+			continue
+		}
+		total += info.Size()
+		entries = append(entries, info)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ModTime().Before(entries[j].ModTime())
+	})
+
+	for _, entry := range entries {
+		if total <= s.maxCacheSize {
+			return
+		}
+		path := filepath.Join(s.cacheDir, entry.Name())
+		if path == keep {
+			continue
+		}
+		err := os.Remove(path)
+		if err != nil {
+			s.logger.WithError(err).Errorln("Could not evict cached artifact")
+			continue
+		}
+		total -= entry.Size()
+	}
+}
+
+func (s *Server) isAllowed(origin *url.URL) bool {
+	originStr := origin.String()
+	for _, prefix := range s.allowed {
+		if strings.HasPrefix(originStr, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) cachePath(origin *url.URL) string {
+	sum := sha256.Sum256([]byte(origin.String()))
+	return filepath.Join(s.cacheDir, hex.EncodeToString(sum[:]))
+}
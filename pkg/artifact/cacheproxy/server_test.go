@@ -0,0 +1,121 @@
+package cacheproxy
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/square/p2/pkg/logging"
+	"github.com/square/p2/pkg/uri"
+)
+
+func newTestProxy(t *testing.T, maxCacheSize int64, allowed ...string) (*Server, *httptest.Server, func()) {
+	cacheDir, err := ioutil.TempDir("", "cacheproxy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := New(cacheDir, maxCacheSize, nil, allowed, logging.TestLogger(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(server.Handler())
+	return server, ts, func() {
+		ts.Close()
+		os.RemoveAll(cacheDir)
+	}
+}
+
+func fetchThrough(t *testing.T, proxy *httptest.Server, origin string) (int, string) {
+	resp, err := http.Get(proxy.URL + uri.ProxyFetchPath + "?" + url.Values{uri.ProxyURLParam: []string{origin}}.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
+
+func TestProxyFetchesOriginOnce(t *testing.T) {
+	var originRequests int32
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&originRequests, 1)
+		_, _ = w.Write([]byte("artifact " + r.URL.Path))
+	}))
+	defer origin.Close()
+
+	_, proxy, cleanup := newTestProxy(t, 0, origin.URL+"/")
+	defer cleanup()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			status, body := fetchThrough(t, proxy, origin.URL+"/myapp.tar.gz")
+			if status != http.StatusOK || body != "artifact /myapp.tar.gz" {
+				t.Errorf("Unexpected response %d %q", status, body)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if requests := atomic.LoadInt32(&originRequests); requests != 1 {
+		t.Errorf("Expected the origin to be asked once, got %d requests", requests)
+	}
+}
+
+func TestProxyRejectsDisallowedOrigins(t *testing.T) {
+	_, proxy, cleanup := newTestProxy(t, 0, "https://artifacts.example.com/")
+	defer cleanup()
+
+	status, _ := fetchThrough(t, proxy, "https://artifacts.example.com.evil.com/myapp.tar.gz")
+	if status != http.StatusForbidden {
+		t.Errorf("Expected disallowed origin to be forbidden, got %d", status)
+	}
+}
+
+func TestProxyReportsOriginFailures(t *testing.T) {
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer origin.Close()
+
+	_, proxy, cleanup := newTestProxy(t, 0, origin.URL+"/")
+	defer cleanup()
+
+	status, _ := fetchThrough(t, proxy, origin.URL+"/missing.tar.gz")
+	if status != http.StatusBadGateway {
+		t.Errorf("Expected origin failure to be reported as a bad gateway, got %d", status)
+	}
+}
+
+func TestProxyEvictsLeastRecentlyUsed(t *testing.T) {
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(strings.Repeat("x", 100)))
+	}))
+	defer origin.Close()
+
+	server, proxy, cleanup := newTestProxy(t, 250, origin.URL+"/")
+	defer cleanup()
+
+	for _, name := range []string{"/a", "/b", "/c"} {
+		status, _ := fetchThrough(t, proxy, origin.URL+name)
+		if status != http.StatusOK {
+			t.Fatalf("Unexpected status %d", status)
+		}
+	}
+
+	for name, expected := range map[string]bool{"/a": false, "/b": true, "/c": true} {
+		u, _ := url.Parse(origin.URL + name)
+		_, err := os.Stat(server.cachePath(u))
+		if cached := err == nil; cached != expected {
+			t.Errorf("Expected %s cached to be %t", name, expected)
+		}
+	}
+}
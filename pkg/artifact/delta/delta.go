@@ -0,0 +1,334 @@
+// Package delta implements a simple binary delta format used to ship a new
+// version of an artifact as the differences from an older one.
+//
+// A delta is a sequence of instructions that rebuild the target from the
+// base: copy a range of bytes out of the base, or insert bytes carried in
+// the delta itself. Deltas are computed by matching fixed-size blocks of the
+// base anywhere in the target using a rolling checksum, in the manner of
+// rsync. Deltas between compressed artifacts are only small when the
+// compressor resynchronizes after a change, as gzip --rsyncable does.
+//
+// A delta carries no integrity information of its own. Callers are expected
+// to check the digest of the rebuilt artifact.
+package delta
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+
+	"github.com/square/p2/pkg/util"
+)
+
+const (
+	magic = "P2DELTA1"
+
+	opEnd    byte = 0
+	opCopy   byte = 1
+	opInsert byte = 2
+
+	// DefaultBlockSize is the granularity at which the base is matched
+	// against the target.
+	DefaultBlockSize = 4096
+
+	// Literal bytes are flushed in chunks of at most this size so that
+	// Apply never needs to buffer an unbounded insert.
+	maxInsert = 1 << 20
+)
+
+// Diff writes a delta that rebuilds target from base. base must support
+// random access because matching blocks are confirmed by reading them back.
+func Diff(base io.ReaderAt, target io.Reader, w io.Writer) error {
+	return DiffBlockSize(base, target, w, DefaultBlockSize)
+}
+
+// DiffBlockSize is Diff with a configurable block size. Smaller blocks find
+// more matches at the cost of a larger index.
+func DiffBlockSize(base io.ReaderAt, target io.Reader, w io.Writer, blockSize int) error {
+	if blockSize <= 0 {
+		return util.Errorf("block size must be positive")
+	}
+
+	index, err := indexBase(base, blockSize)
+	if err != nil {
+		return err
+	}
+
+	e := &encoder{w: bufio.NewWriter(w)}
+	_, err = e.w.WriteString(magic)
+	if err != nil {
+		return err
+	}
+
+	in := bufio.NewReader(target)
+	window := make([]byte, 0, blockSize)
+	candidate := make([]byte, blockSize)
+
+	// fill reads up to a block into the window, returning false at the end
+	// of the target
+	fill := func() (bool, error) {
+		for len(window) < blockSize {
+			c, err := in.ReadByte()
+			if err == io.EOF {
+				return false, nil
+			} else if err != nil {
+				return false, err
+			}
+			window = append(window, c)
+		}
+		return true, nil
+	}
+
+	full, err := fill()
+	if err != nil {
+		return err
+	}
+	sum := newChecksum(window)
+	for full {
+		matched := false
+		for _, offset := range index[sum.value()] {
+			n, err := base.ReadAt(candidate, offset)
+			if n < blockSize && err != nil && err != io.EOF {
+				return err
+			}
+			if n == blockSize && bytes.Equal(candidate, window) {
+				err = e.copy(offset, int64(blockSize))
+				if err != nil {
+					return err
+				}
+				matched = true
+				break
+			}
+		}
+
+		if matched {
+			window = window[:0]
+			full, err = fill()
+			if err != nil {
+				return err
+			}
+			sum = newChecksum(window)
+			continue
+		}
+
+		// Slide the window forward by one byte
+		c, err := in.ReadByte()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		out := window[0]
+		err = e.insert(out)
+		if err != nil {
+			return err
+		}
+		copy(window, window[1:])
+		window[blockSize-1] = c
+		sum.roll(out, c)
+	}
+
+	for _, c := range window {
+		err = e.insert(c)
+		if err != nil {
+			return err
+		}
+	}
+	return e.close()
+}
+
+// indexBase maps the checksum of every complete block of the base to the
+// offsets at which it occurs.
+func indexBase(base io.ReaderAt, blockSize int) (map[uint32][]int64, error) {
+	index := make(map[uint32][]int64)
+	block := make([]byte, blockSize)
+	var offset int64
+	for {
+		n, err := base.ReadAt(block, offset)
+		if n == blockSize {
+			sum := newChecksum(block).value()
+			index[sum] = append(index[sum], offset)
+			offset += int64(n)
+		}
+		if err == io.EOF || (err == nil && n < blockSize) {
+			return index, nil
+		} else if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// encoder merges adjacent copies and buffers literal bytes so that the
+// delta contains as few instructions as possible.
+type encoder struct {
+	w *bufio.Writer
+
+	copyOffset int64
+	copyLength int64
+	literal    []byte
+}
+
+func (e *encoder) copy(offset int64, length int64) error {
+	err := e.flushInsert()
+	if err != nil {
+		return err
+	}
+	if e.copyLength > 0 && e.copyOffset+e.copyLength == offset {
+		e.copyLength += length
+		return nil
+	}
+	err = e.flushCopy()
+	if err != nil {
+		return err
+	}
+	e.copyOffset, e.copyLength = offset, length
+	return nil
+}
+
+func (e *encoder) insert(c byte) error {
+	err := e.flushCopy()
+	if err != nil {
+		return err
+	}
+	e.literal = append(e.literal, c)
+	if len(e.literal) >= maxInsert {
+		return e.flushInsert()
+	}
+	return nil
+}
+
+func (e *encoder) flushCopy() error {
+	if e.copyLength == 0 {
+		return nil
+	}
+	err := e.w.WriteByte(opCopy)
+	if err != nil {
+		return err
+	}
+	err = e.writeUvarint(uint64(e.copyOffset))
+	if err != nil {
+		return err
+	}
+	err = e.writeUvarint(uint64(e.copyLength))
+	e.copyLength = 0
+	return err
+}
+
+func (e *encoder) flushInsert() error {
+	if len(e.literal) == 0 {
+		return nil
+	}
+	err := e.w.WriteByte(opInsert)
+	if err != nil {
+		return err
+	}
+	err = e.writeUvarint(uint64(len(e.literal)))
+	if err != nil {
+		return err
+	}
+	_, err = e.w.Write(e.literal)
+	e.literal = e.literal[:0]
+	return err
+}
+
+func (e *encoder) writeUvarint(v uint64) error {
+	buf := make([]byte, binary.MaxVarintLen64)
+	_, err := e.w.Write(buf[:binary.PutUvarint(buf, v)])
+	return err
+}
+
+func (e *encoder) close() error {
+	err := e.flushCopy()
+	if err != nil {
+		return err
+	}
+	err = e.flushInsert()
+	if err != nil {
+		return err
+	}
+	err = e.w.WriteByte(opEnd)
+	if err != nil {
+		return err
+	}
+	return e.w.Flush()
+}
+
+// Apply rebuilds the target described by delta from base and writes it to
+// w. It returns an error if the delta is malformed or refers to data outside
+// the base.
+func Apply(base io.ReaderAt, delta io.Reader, w io.Writer) error {
+	in := bufio.NewReader(delta)
+	header := make([]byte, len(magic))
+	_, err := io.ReadFull(in, header)
+	if err != nil || string(header) != magic {
+		return util.Errorf("not a delta: bad header")
+	}
+
+	for {
+		op, err := in.ReadByte()
+		if err != nil {
+			return util.Errorf("truncated delta: %s", err)
+		}
+		switch op {
+		case opEnd:
+			return nil
+		case opCopy:
+			offset, err := binary.ReadUvarint(in)
+			if err != nil {
+				return util.Errorf("truncated delta: %s", err)
+			}
+			length, err := binary.ReadUvarint(in)
+			if err != nil {
+				return util.Errorf("truncated delta: %s", err)
+			}
+			n, err := io.Copy(w, io.NewSectionReader(base, int64(offset), int64(length)))
+			if err != nil {
+				return err
+			}
+			if n != int64(length) {
+				return util.Errorf("delta copies %d bytes at offset %d, beyond the end of the base", length, offset)
+			}
+		case opInsert:
+			length, err := binary.ReadUvarint(in)
+			if err != nil {
+				return util.Errorf("truncated delta: %s", err)
+			}
+			if length > maxInsert {
+				return util.Errorf("delta inserts %d bytes at once, more than the limit of %d", length, maxInsert)
+			}
+			_, err = io.CopyN(w, in, int64(length))
+			if err != nil {
+				return util.Errorf("truncated delta: %s", err)
+			}
+		default:
+			return util.Errorf("unknown delta instruction %d", op)
+		}
+	}
+}
+
+// checksum is the rolling checksum used by rsync: the sum of the bytes in
+// the window and the sum of those sums, both modulo 2^16.
+type checksum struct {
+	a, b uint32
+	n    uint32
+}
+
+func newChecksum(window []byte) checksum {
+	var s checksum
+	s.n = uint32(len(window))
+	for i, c := range window {
+		s.a += uint32(c)
+		s.b += uint32(len(window)-i) * uint32(c)
+	}
+	return s
+}
+
+func (s *checksum) roll(out byte, in byte) {
+	s.a = s.a - uint32(out) + uint32(in)
+	s.b = s.b - s.n*uint32(out) + s.a
+}
+
+func (s checksum) value() uint32 {
+	return (s.b&0xffff)<<16 | s.a&0xffff
+}
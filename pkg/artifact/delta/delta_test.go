@@ -0,0 +1,84 @@
+package delta
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+func roundTrip(t *testing.T, base []byte, target []byte, blockSize int) []byte {
+	var d bytes.Buffer
+	err := DiffBlockSize(bytes.NewReader(base), bytes.NewReader(target), &d, blockSize)
+	if err != nil {
+		t.Fatalf("Unexpected error computing delta: %s", err)
+	}
+
+	var rebuilt bytes.Buffer
+	err = Apply(bytes.NewReader(base), bytes.NewReader(d.Bytes()), &rebuilt)
+	if err != nil {
+		t.Fatalf("Unexpected error applying delta: %s", err)
+	}
+	if !bytes.Equal(rebuilt.Bytes(), target) {
+		t.Fatalf("Rebuilt target did not match: got %d bytes, wanted %d", rebuilt.Len(), len(target))
+	}
+	return d.Bytes()
+}
+
+func randomBytes(r *rand.Rand, n int) []byte {
+	b := make([]byte, n)
+	_, _ = r.Read(b)
+	return b
+}
+
+func TestDiffSmallChange(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	base := randomBytes(r, 256*1024)
+
+	// Change a few bytes in the middle and insert some more
+	target := append([]byte{}, base[:100000]...)
+	target = append(target, []byte("a few new bytes")...)
+	target = append(target, base[100010:]...)
+
+	d := roundTrip(t, base, target, 1024)
+	if len(d) > 4*1024 {
+		t.Errorf("Expected a small delta for a small change, got %d bytes", len(d))
+	}
+}
+
+func TestDiffEdgeCases(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	data := randomBytes(r, 10000)
+
+	for name, testCase := range map[string]struct {
+		base   []byte
+		target []byte
+	}{
+		"identical":          {data, data},
+		"empty base":         {nil, data},
+		"empty target":       {data, nil},
+		"shorter than block": {data, data[:10]},
+		"unrelated":          {data, randomBytes(r, 5000)},
+		"reordered":          {data, append(append([]byte{}, data[5000:]...), data[:5000]...)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			roundTrip(t, testCase.base, testCase.target, 512)
+		})
+	}
+}
+
+func TestApplyRejectsBadDeltas(t *testing.T) {
+	base := bytes.NewReader([]byte("base"))
+
+	for name, d := range map[string][]byte{
+		"no header":      []byte("garbage"),
+		"truncated":      []byte(magic + string([]byte{opInsert, 10, 'a'})),
+		"past the base":  []byte(magic + string([]byte{opCopy, 2, 10, opEnd})),
+		"unknown opcode": []byte(magic + string([]byte{9})),
+		"no end":         []byte(magic),
+	} {
+		var out bytes.Buffer
+		if err := Apply(base, bytes.NewReader(d), &out); err == nil {
+			t.Errorf("%s: expected an error applying a bad delta", name)
+		}
+	}
+}
@@ -1,14 +1,21 @@
 package artifact
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"io"
 	"io/ioutil"
 	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/sirupsen/logrus"
+
+	"github.com/square/p2/pkg/artifact/delta"
 	"github.com/square/p2/pkg/auth"
 	"github.com/square/p2/pkg/gzip"
+	"github.com/square/p2/pkg/logging"
 	"github.com/square/p2/pkg/uri"
 	"github.com/square/p2/pkg/util"
 )
@@ -18,62 +25,297 @@ type Downloader interface {
 	// Downloads the artifact represented by the Downloader to the
 	// specified path and transfers file ownership to the specified user
 	Download(location *url.URL, verificationData auth.VerificationData, destination string, owner string) error
+
+	// Like Download, but first tries to rebuild the artifact by applying
+	// delta to an artifact kept from an earlier install. If that fails for
+	// any reason the full artifact is downloaded instead. Either way the
+	// artifact is kept next to destination afterwards, so that the next
+	// version can be installed from a delta in turn. delta may be nil.
+	DownloadDelta(location *url.URL, verificationData auth.VerificationData, delta *Delta, destination string, owner string) error
 }
 
 // Implements the Downloader interface. Simply fetches a .tar.gz file from a
 // configured URL and extracts it to the location passed to DownloadTo
+//
+// Artifacts downloaded by DownloadDelta with a known digest are kept next to
+// the directory they were extracted to, so that a later version can be
+// installed from a delta. They are named "<install>.<sha256>.artifact" and
+// are pruned along with their install (see hoist.Launchable.Prune).
 type downloader struct {
 	fetcher  uri.Fetcher
 	verifier auth.ArtifactVerifier
+	logger   logging.Logger
 }
 
-func NewLocationDownloader(fetcher uri.Fetcher, verifier auth.ArtifactVerifier) Downloader {
+func NewLocationDownloader(fetcher uri.Fetcher, verifier auth.ArtifactVerifier, logger logging.Logger) Downloader {
 	return &downloader{
 		fetcher:  fetcher,
 		verifier: verifier,
+		logger:   logger,
 	}
 }
 
 func (l *downloader) Download(location *url.URL, verificationData auth.VerificationData, dst string, owner string) error {
-	// Write to a temporary file for easy cleanup if the network transfer fails
-	// TODO: the end of the artifact URL may not always be suitable as a directory
-	// name
-	artifactFile, err := ioutil.TempFile("", filepath.Base(location.Path))
+	return l.download(location, verificationData, nil, dst, owner, false)
+}
+
+func (l *downloader) DownloadDelta(location *url.URL, verificationData auth.VerificationData, delta *Delta, dst string, owner string) error {
+	return l.download(location, verificationData, delta, dst, owner, true)
+}
+
+func (l *downloader) download(location *url.URL, verificationData auth.VerificationData, delta *Delta, dst string, owner string, keep bool) error {
+	var artifactFile *os.File
+	var err error
+	if delta != nil {
+		artifactFile, err = l.rebuild(location, verificationData, delta, filepath.Dir(dst))
+		if err != nil {
+			l.logger.WithError(err).WithFields(logrus.Fields{
+				"delta": delta.Location.String(),
+				"base":  delta.BaseSHA256,
+			}).Warnln("Could not rebuild artifact from delta, downloading the full artifact")
+		}
+	}
+	if artifactFile != nil {
+		err = l.verify(artifactFile, verificationData)
+	} else {
+		artifactFile, err = l.fetchVerified(location, verificationData)
+	}
 	if err != nil {
 		return err
 	}
 	defer os.Remove(artifactFile.Name())
 	defer artifactFile.Close()
 
-	remoteData, err := l.fetcher.Open(location)
+	err = artifactFile.Chmod(0644)
 	if err != nil {
 		return err
 	}
+
+	err = gzip.ExtractTarGz(owner, artifactFile.Name(), dst)
+	if err != nil {
+		_ = os.RemoveAll(dst)
+		return util.Errorf("error while extracting artifact: %s", err)
+	}
+
+	if keep && verificationData.ArtifactSHA256 != "" {
+		err = keepArtifact(artifactFile.Name(), dst, verificationData.ArtifactSHA256)
+		if err != nil {
+			// The install itself succeeded, so this only costs a delta later
+			l.logger.WithError(err).Warnln("Could not keep artifact for future delta downloads")
+		}
+	}
+	return nil
+}
+
+// fetchVerified downloads and verifies the full artifact. If the fetcher
+// serves artifacts through a cache, such as an artifact proxy, and the copy
+// it served can't be verified, the artifact is downloaded again from its
+// origin: the cache may hold a corrupt or outdated copy.
+func (l *downloader) fetchVerified(location *url.URL, verificationData auth.VerificationData) (*os.File, error) {
+	artifactFile, err := l.fetch(l.fetcher, location, verificationData)
+	if err == nil {
+		err = l.verify(artifactFile, verificationData)
+	}
+	if err == nil {
+		return artifactFile, nil
+	}
+
+	cached, ok := l.fetcher.(uri.OriginFetcher)
+	if !ok {
+		return nil, err
+	}
+	l.logger.WithError(err).WithField("url", location.String()).
+		Warnln("Could not download a verified artifact through the cache, downloading it from its origin")
+	artifactFile, err = l.fetch(cached.Origin(), location, verificationData)
+	if err != nil {
+		return nil, err
+	}
+	err = l.verify(artifactFile, verificationData)
+	if err != nil {
+		return nil, err
+	}
+	return artifactFile, nil
+}
+
+// verify checks the artifact's signature, and closes and removes it if the
+// check fails.
+func (l *downloader) verify(artifactFile *os.File, verificationData auth.VerificationData) error {
+	err := l.verifier.VerifyHoistArtifact(artifactFile, verificationData)
+	if err != nil {
+		_ = artifactFile.Close()
+		_ = os.Remove(artifactFile.Name())
+	}
+	return err
+}
+
+// fetch downloads the full artifact to a temporary file, checking its
+// digest if one is known. The returned file is positioned at its start.
+func (l *downloader) fetch(fetcher uri.Fetcher, location *url.URL, verificationData auth.VerificationData) (*os.File, error) {
+	// Write to a temporary file for easy cleanup if the network transfer fails
+	// TODO: the end of the artifact URL may not always be suitable as a directory
+	// name
+	artifactFile, err := ioutil.TempFile("", filepath.Base(location.Path))
+	if err != nil {
+		return nil, err
+	}
+	success := false
+	defer func() {
+		if !success {
+			_ = artifactFile.Close()
+			_ = os.Remove(artifactFile.Name())
+		}
+	}()
+
+	remoteData, err := fetcher.Open(location)
+	if err != nil {
+		return nil, err
+	}
 	defer remoteData.Close()
-	_, err = io.Copy(artifactFile, remoteData)
+	// Hash the artifact as it streams in so that a corrupt download is
+	// caught without a second pass over the file
+	hasher := sha256.New()
+	_, err = io.Copy(io.MultiWriter(artifactFile, hasher), remoteData)
 	if err != nil {
-		return util.Errorf("Could not copy artifact locally: %v", err)
+		return nil, util.Errorf("Could not copy artifact locally: %v", err)
+	}
+	if verificationData.ArtifactSHA256 != "" {
+		digest := hex.EncodeToString(hasher.Sum(nil))
+		if !strings.EqualFold(digest, verificationData.ArtifactSHA256) {
+			return nil, util.Errorf("Artifact digest %s does not match expected digest %s", digest, verificationData.ArtifactSHA256)
+		}
 	}
 	// rewind once so we can ask the verifier
 	_, err = artifactFile.Seek(0, os.SEEK_SET)
 	if err != nil {
-		return util.Errorf("Could not reset artifact file position for verification: %v", err)
+		return nil, util.Errorf("Could not reset artifact file position for verification: %v", err)
 	}
+	success = true
+	return artifactFile, nil
+}
 
-	err = l.verifier.VerifyHoistArtifact(artifactFile, verificationData)
+// rebuild applies a delta to a kept artifact in installsDir and checks that
+// the result has the expected digest. The returned file is positioned at its
+// start.
+func (l *downloader) rebuild(location *url.URL, verificationData auth.VerificationData, d *Delta, installsDir string) (*os.File, error) {
+	if verificationData.ArtifactSHA256 == "" {
+		return nil, util.Errorf("Cannot check an artifact rebuilt from a delta without its digest")
+	}
+	basePath, ok := keptArtifact(installsDir, d.BaseSHA256)
+	if !ok {
+		return nil, util.Errorf("No artifact with digest %s in %s", d.BaseSHA256, installsDir)
+	}
+	base, err := os.Open(basePath)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer base.Close()
 
-	err = artifactFile.Chmod(0644)
+	artifactFile, err := ioutil.TempFile("", filepath.Base(location.Path))
 	if err != nil {
-		return err
+		return nil, err
 	}
+	success := false
+	defer func() {
+		if !success {
+			_ = artifactFile.Close()
+			_ = os.Remove(artifactFile.Name())
+		}
+	}()
 
-	err = gzip.ExtractTarGz(owner, artifactFile.Name(), dst)
+	deltaData, err := l.fetcher.Open(d.Location)
 	if err != nil {
-		_ = os.RemoveAll(dst)
-		return util.Errorf("error while extracting artifact: %s", err)
+		return nil, err
 	}
-	return err
+	defer deltaData.Close()
+
+	hasher := sha256.New()
+	err = delta.Apply(base, deltaData, io.MultiWriter(artifactFile, hasher))
+	if err != nil {
+		return nil, util.Errorf("Could not apply delta: %s", err)
+	}
+	digest := hex.EncodeToString(hasher.Sum(nil))
+	if !strings.EqualFold(digest, verificationData.ArtifactSHA256) {
+		return nil, util.Errorf("Rebuilt artifact digest %s does not match expected digest %s", digest, verificationData.ArtifactSHA256)
+	}
+	_, err = artifactFile.Seek(0, os.SEEK_SET)
+	if err != nil {
+		return nil, err
+	}
+	success = true
+	return artifactFile, nil
+}
+
+const keptArtifactSuffix = ".artifact"
+
+// KeptArtifactDigests returns the digests of the artifacts kept in an
+// installs directory, which can be offered to a registry as delta bases.
+func KeptArtifactDigests(installsDir string) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(installsDir, "*"+keptArtifactSuffix))
+	if err != nil {
+		return nil, err
+	}
+	var digests []string
+	for _, match := range matches {
+		name := strings.TrimSuffix(filepath.Base(match), keptArtifactSuffix)
+		digest := filepath.Ext(name)
+		if len(digest) > 1 {
+			digests = append(digests, digest[1:])
+		}
+	}
+	return digests, nil
+}
+
+// KeptArtifactInstall returns the name of the install that a file in an
+// installs directory was kept for, if the file is a kept artifact.
+func KeptArtifactInstall(name string) (string, bool) {
+	if !strings.HasSuffix(name, keptArtifactSuffix) || strings.HasPrefix(name, ".") {
+		return "", false
+	}
+	name = strings.TrimSuffix(name, keptArtifactSuffix)
+	digest := filepath.Ext(name)
+	if len(digest) <= 1 {
+		return "", false
+	}
+	return strings.TrimSuffix(name, digest), true
+}
+
+func keptArtifact(installsDir string, digest string) (string, bool) {
+	matches, err := filepath.Glob(filepath.Join(installsDir, "*."+strings.ToLower(digest)+keptArtifactSuffix))
+	if err != nil || len(matches) == 0 {
+		return "", false
+	}
+	return matches[0], true
+}
+
+// keepArtifact moves a verified artifact next to the directory it was
+// extracted to, copying it if it can't be moved.
+func keepArtifact(artifactPath string, dst string, digest string) error {
+	keptPath := dst + "." + strings.ToLower(digest) + keptArtifactSuffix
+	err := os.Rename(artifactPath, keptPath)
+	if err == nil {
+		return nil
+	}
+
+	src, err := os.Open(artifactPath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	tmp, err := ioutil.TempFile(filepath.Dir(dst), "."+filepath.Base(keptPath))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = io.Copy(tmp, src)
+	if errC := tmp.Close(); err == nil {
+		err = errC
+	}
+	if err != nil {
+		return err
+	}
+	err = os.Chmod(tmp.Name(), 0644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), keptPath)
 }
@@ -0,0 +1,251 @@
+package artifact
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/square/p2/pkg/artifact/delta"
+	"github.com/square/p2/pkg/auth"
+	"github.com/square/p2/pkg/logging"
+	"github.com/square/p2/pkg/uri"
+)
+
+func TestDownloadRejectsDigestMismatch(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "downloader_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	fetcher := &FakeFetcher{Data: []byte("not the artifact you were looking for")}
+	downloader := NewLocationDownloader(fetcher, auth.NopVerifier(), logging.TestLogger())
+
+	dst := filepath.Join(tempDir, "installs", "myapp_123")
+	err = downloader.Download(
+		&url.URL{Scheme: "https", Host: "fileserver.com", Path: "/myapp_123.tar.gz"},
+		auth.VerificationData{ArtifactSHA256: strings.Repeat("0", 64)},
+		dst,
+		"nobody",
+	)
+	if err == nil {
+		t.Fatal("Expected an error when the downloaded artifact doesn't match the expected digest")
+	}
+	if !strings.Contains(err.Error(), "does not match expected digest") {
+		t.Errorf("Unexpected error: %s", err)
+	}
+	if _, err := os.Stat(dst); !os.IsNotExist(err) {
+		t.Errorf("Expected %s not to be created", dst)
+	}
+}
+
+// testTarball returns a gzipped tarball containing one file. The contents
+// are stored uncompressed so that small changes make for small deltas.
+func testTarball(t *testing.T, contents []byte) []byte {
+	var buf bytes.Buffer
+	gz, _ := gzip.NewWriterLevel(&buf, gzip.NoCompression)
+	tw := tar.NewWriter(gz)
+	err := tw.WriteHeader(&tar.Header{Name: "data", Mode: 0644, Size: int64(len(contents))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = tw.Write(contents)
+	if err = tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err = gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestDownloadDelta(t *testing.T) {
+	currentUser, err := user.Current()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tempDir, err := ioutil.TempDir("", "downloader_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	installsDir := filepath.Join(tempDir, "installs")
+	err = os.MkdirAll(installsDir, 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := rand.New(rand.NewSource(1))
+	oldContents := make([]byte, 64*1024)
+	_, _ = r.Read(oldContents)
+	newContents := append([]byte{}, oldContents...)
+	copy(newContents[1000:], "changed")
+	oldArtifact := testTarball(t, oldContents)
+	newArtifact := testTarball(t, newContents)
+
+	// The old version was kept by an earlier install
+	oldDigest := sha256Hex(oldArtifact)
+	err = ioutil.WriteFile(filepath.Join(installsDir, "myapp_1."+oldDigest+keptArtifactSuffix), oldArtifact, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digests, err := KeptArtifactDigests(installsDir)
+	if err != nil || len(digests) != 1 || digests[0] != oldDigest {
+		t.Fatalf("Expected the kept artifact to be found, got %v %v", digests, err)
+	}
+
+	var deltaBytes bytes.Buffer
+	err = delta.Diff(bytes.NewReader(oldArtifact), bytes.NewReader(newArtifact), &deltaBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deltaPath := filepath.Join(tempDir, "myapp_2.tar.gz.delta")
+	err = ioutil.WriteFile(deltaPath, deltaBytes.Bytes(), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The full artifact is deliberately missing, so the download only
+	// succeeds if the delta is used
+	downloader := NewLocationDownloader(uri.DefaultFetcher, auth.NopVerifier(), logging.TestLogger())
+	dst := filepath.Join(installsDir, "myapp_2")
+	err = downloader.DownloadDelta(
+		&url.URL{Scheme: "file", Path: filepath.Join(tempDir, "missing.tar.gz")},
+		auth.VerificationData{ArtifactSHA256: sha256Hex(newArtifact)},
+		&Delta{BaseSHA256: oldDigest, Location: &url.URL{Scheme: "file", Path: deltaPath}},
+		dst,
+		currentUser.Username,
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error installing from delta: %s", err)
+	}
+	extracted, err := ioutil.ReadFile(filepath.Join(dst, "data"))
+	if err != nil || !bytes.Equal(extracted, newContents) {
+		t.Errorf("Extracted artifact did not match the new version: %v", err)
+	}
+	if _, err := os.Stat(dst + "." + sha256Hex(newArtifact) + keptArtifactSuffix); err != nil {
+		t.Errorf("Expected the new artifact to be kept for future deltas: %s", err)
+	}
+}
+
+func TestDownloadDeltaFallsBackToFullArtifact(t *testing.T) {
+	currentUser, err := user.Current()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tempDir, err := ioutil.TempDir("", "downloader_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	installsDir := filepath.Join(tempDir, "installs")
+	err = os.MkdirAll(installsDir, 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldArtifact := testTarball(t, []byte("old"))
+	newArtifact := testTarball(t, []byte("new"))
+	oldDigest := sha256Hex(oldArtifact)
+	err = ioutil.WriteFile(filepath.Join(installsDir, "myapp_1."+oldDigest+keptArtifactSuffix), oldArtifact, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	artifactPath := filepath.Join(tempDir, "myapp_2.tar.gz")
+	err = ioutil.WriteFile(artifactPath, newArtifact, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A delta that rebuilds the wrong artifact
+	deltaPath := filepath.Join(tempDir, "myapp_2.tar.gz.delta")
+	var deltaBytes bytes.Buffer
+	err = delta.Diff(bytes.NewReader(oldArtifact), bytes.NewReader(oldArtifact), &deltaBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(deltaPath, deltaBytes.Bytes(), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	downloader := NewLocationDownloader(uri.DefaultFetcher, auth.NopVerifier(), logging.TestLogger())
+	dst := filepath.Join(installsDir, "myapp_2")
+	err = downloader.DownloadDelta(
+		&url.URL{Scheme: "file", Path: artifactPath},
+		auth.VerificationData{ArtifactSHA256: sha256Hex(newArtifact)},
+		&Delta{BaseSHA256: oldDigest, Location: &url.URL{Scheme: "file", Path: deltaPath}},
+		dst,
+		currentUser.Username,
+	)
+	if err != nil {
+		t.Fatalf("Expected a bad delta to fall back to the full artifact, got %s", err)
+	}
+	extracted, err := ioutil.ReadFile(filepath.Join(dst, "data"))
+	if err != nil || string(extracted) != "new" {
+		t.Errorf("Extracted artifact did not match the new version: %q %v", extracted, err)
+	}
+}
+
+func TestDownloadRefetchesUnverifiedProxiedArtifactFromOrigin(t *testing.T) {
+	currentUser, err := user.Current()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tempDir, err := ioutil.TempDir("", "downloader_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	artifact := testTarball(t, []byte("new"))
+	originRequests := 0
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		originRequests++
+		_, _ = w.Write(artifact)
+	}))
+	defer origin.Close()
+	// The proxy has cached an outdated copy of the artifact
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(testTarball(t, []byte("old")))
+	}))
+	defer proxy.Close()
+
+	proxyURL, _ := url.Parse(proxy.URL)
+	fetcher := uri.NewProxyFetcher(uri.DefaultFetcher, uri.StaticProxies{proxyURL}, logging.TestLogger())
+	downloader := NewLocationDownloader(fetcher, auth.NopVerifier(), logging.TestLogger())
+
+	location, _ := url.Parse(origin.URL + "/myapp_2.tar.gz")
+	dst := filepath.Join(tempDir, "installs", "myapp_2")
+	err = downloader.Download(location, auth.VerificationData{ArtifactSHA256: sha256Hex(artifact)}, dst, currentUser.Username)
+	if err != nil {
+		t.Fatalf("Expected the artifact to be downloaded from its origin, got %s", err)
+	}
+	if originRequests != 1 {
+		t.Errorf("Expected one request to the origin, got %d", originRequests)
+	}
+	extracted, err := ioutil.ReadFile(filepath.Join(dst, "data"))
+	if err != nil || string(extracted) != "new" {
+		t.Errorf("Extracted artifact did not match the origin's: %q %v", extracted, err)
+	}
+	// Only DownloadDelta keeps artifacts for later deltas
+	if digests, _ := KeptArtifactDigests(filepath.Dir(dst)); len(digests) != 0 {
+		t.Errorf("Expected Download not to keep the artifact, but kept %v", digests)
+	}
+}
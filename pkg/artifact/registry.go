@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/square/p2/pkg/auth"
 	"github.com/square/p2/pkg/launch"
@@ -23,6 +24,7 @@ const (
 	osTag            = "os"
 	osVersionTag     = "os_version"
 	versionTag       = "version"
+	deltaFromTag     = "delta_from"
 )
 
 // interface for running operations against an artifact registry.
@@ -32,13 +34,34 @@ type Registry interface {
 	// can be used to verify artifact integrity
 	LocationDataForLaunchable(podID types.PodID, launchableID launch.LaunchableID, stanza launch.LaunchableStanza) (*url.URL, auth.VerificationData, error)
 
+	// Like LocationDataForLaunchable, but also offers the registry the
+	// digests of artifacts already held by the node. If the registry has a
+	// delta from one of them to the requested artifact, it is returned
+	// along with the full artifact's location; otherwise the returned
+	// Delta is nil.
+	DeltaLocationDataForLaunchable(podID types.PodID, launchableID launch.LaunchableID, stanza launch.LaunchableStanza, baseDigests []string) (*url.URL, auth.VerificationData, *Delta, error)
+
+	// DeltasEnabled returns whether installs from this registry should keep
+	// their artifacts and offer them as delta bases. Kept artifacts take as
+	// much disk as the installs they came from, so this is opt-in.
+	DeltasEnabled() bool
+
 	CheckArtifactExists(u *url.URL) (bool, error)
 }
 
+// Delta describes a binary delta (see the delta package) that rebuilds an
+// artifact from an older artifact held by the node.
+type Delta struct {
+	// The SHA-256 digest of the artifact the delta applies to
+	BaseSHA256 string
+	Location   *url.URL
+}
+
 type registry struct {
 	registryURL       *url.URL
 	fetcher           uri.Fetcher
 	osVersionDetector osversion.Detector
+	deltas            bool
 }
 
 func NewRegistry(registryURL *url.URL, fetcher uri.Fetcher, osVersionDetector osversion.Detector) Registry {
@@ -53,6 +76,19 @@ func NewRegistry(registryURL *url.URL, fetcher uri.Fetcher, osVersionDetector os
 	}
 }
 
+// NewDeltaRegistry is like NewRegistry, but the returned registry has
+// DeltasEnabled, so installs keep their artifacts to download later versions
+// as deltas from them.
+func NewDeltaRegistry(registryURL *url.URL, fetcher uri.Fetcher, osVersionDetector osversion.Detector) Registry {
+	r := NewRegistry(registryURL, fetcher, osVersionDetector).(*registry)
+	r.deltas = true
+	return r
+}
+
+func (a registry) DeltasEnabled() bool {
+	return a.deltas
+}
+
 // Given a launchable stanza, returns the URL from which the artifact may be downloaded, as
 // well as an auth.VerificationData which can be used to verify the artifact.
 // There are two schemes for specifying this information in a launchable stanza:
@@ -67,30 +103,38 @@ func NewRegistry(registryURL *url.URL, fetcher uri.Fetcher, osVersionDetector os
 // manifest signature: ".manifest.sig"
 // build signature: ".sig"
 func (a registry) LocationDataForLaunchable(podID types.PodID, launchableID launch.LaunchableID, stanza launch.LaunchableStanza) (*url.URL, auth.VerificationData, error) {
+	location, verificationData, _, err := a.DeltaLocationDataForLaunchable(podID, launchableID, stanza, nil)
+	return location, verificationData, err
+}
+
+// Deltas are only ever offered by an artifact registry, and only when it
+// also provides the digest of the full artifact, since that digest is the
+// only way to tell that the delta was applied correctly.
+func (a registry) DeltaLocationDataForLaunchable(podID types.PodID, launchableID launch.LaunchableID, stanza launch.LaunchableStanza, baseDigests []string) (*url.URL, auth.VerificationData, *Delta, error) {
 	if stanza.Location == "" && stanza.Version.ID == "" {
-		return nil, auth.VerificationData{}, util.Errorf("Launchable must provide either \"location\" or \"version\" fields")
+		return nil, auth.VerificationData{}, nil, util.Errorf("Launchable must provide either \"location\" or \"version\" fields")
 	}
 
 	if stanza.Location != "" && stanza.Version.ID != "" {
-		return nil, auth.VerificationData{}, util.Errorf("Launchable must not provide both \"location\" and \"version\" fields")
+		return nil, auth.VerificationData{}, nil, util.Errorf("Launchable must not provide both \"location\" and \"version\" fields")
 	}
 
 	// infer the verification data using magical suffixes
 	if stanza.Location != "" {
 		location, err := url.Parse(stanza.Location)
 		if err != nil {
-			return nil, auth.VerificationData{}, util.Errorf("Couldn't parse launchable url '%s': %s", stanza.Location, err)
+			return nil, auth.VerificationData{}, nil, util.Errorf("Couldn't parse launchable url '%s': %s", stanza.Location, err)
 		}
 
 		verificationData := VerificationDataForLocation(location)
-		return location, verificationData, nil
+		return location, verificationData, nil, nil
 	}
 
 	if a.registryURL == nil {
-		return nil, auth.VerificationData{}, util.Errorf("No artifact registry configured and location field not present on launchable %s", launchableID)
+		return nil, auth.VerificationData{}, nil, util.Errorf("No artifact registry configured and location field not present on launchable %s", launchableID)
 	}
 
-	return a.fetchRegistryData(podID, launchableID, stanza.Version)
+	return a.fetchRegistryData(podID, launchableID, stanza.Version, baseDigests)
 }
 
 func (a registry) CheckArtifactExists(u *url.URL) (bool, error) {
@@ -110,7 +154,7 @@ func (a registry) CheckArtifactExists(u *url.URL) (bool, error) {
 			return false, err
 		}
 		return true, nil
-	case "http", "https":
+	case "http", "https", uri.S3Scheme:
 		resp, err := a.fetcher.Head(u)
 		if err != nil {
 			return false, err
@@ -129,9 +173,15 @@ type RegistryResponse struct {
 	ManifestLocation          string `json:"manifest_location"`
 	ManifestSignatureLocation string `json:"manifest_signature_location"`
 	BuildSignatureLocation    string `json:"signature_location"`
+	ArtifactSHA256            string `json:"sha256,omitempty"`
+
+	// Set only when the request offered base digests with "delta_from" and
+	// the registry holds a delta from one of them
+	DeltaLocation   string `json:"delta_location,omitempty"`
+	DeltaBaseSHA256 string `json:"delta_base_sha256,omitempty"`
 }
 
-func (a registry) fetchRegistryData(podID types.PodID, launchableID launch.LaunchableID, version launch.LaunchableVersion) (*url.URL, auth.VerificationData, error) {
+func (a registry) fetchRegistryData(podID types.PodID, launchableID launch.LaunchableID, version launch.LaunchableVersion, baseDigests []string) (*url.URL, auth.VerificationData, *Delta, error) {
 	requestURL := &url.URL{
 		Path: fmt.Sprintf("%s/%s", discoverBasePath, podID),
 	}
@@ -142,7 +192,7 @@ func (a registry) fetchRegistryData(podID types.PodID, launchableID launch.Launc
 
 	os, osVersion, err := a.osVersionDetector.Version()
 	if err != nil {
-		return nil, auth.VerificationData{}, err
+		return nil, auth.VerificationData{}, nil, err
 	}
 
 	if version.ArtifactOverride.String() != "" {
@@ -153,18 +203,21 @@ func (a registry) fetchRegistryData(podID types.PodID, launchableID launch.Launc
 	query.Add(osTag, os.String())
 	query.Add(osVersionTag, osVersion.String())
 	query.Add(versionTag, version.ID.String())
+	for _, digest := range baseDigests {
+		query.Add(deltaFromTag, digest)
+	}
 
 	requestURL.RawQuery = query.Encode()
 
 	data, err := a.fetcher.Open(a.registryURL.ResolveReference(requestURL))
 	if err != nil {
-		return nil, auth.VerificationData{}, err
+		return nil, auth.VerificationData{}, nil, err
 	}
 	defer data.Close()
 
 	respBytes, err := ioutil.ReadAll(data)
 	if err != nil {
-		return nil, auth.VerificationData{}, util.Errorf("Could not read response from artifact registry: %s", err)
+		return nil, auth.VerificationData{}, nil, util.Errorf("Could not read response from artifact registry: %s", err)
 	}
 
 	var registryResponse RegistryResponse
@@ -174,7 +227,7 @@ func (a registry) fetchRegistryData(podID types.PodID, launchableID launch.Launc
 		if l > 80 {
 			l = 80
 		}
-		return nil, auth.VerificationData{}, util.Errorf(
+		return nil, auth.VerificationData{}, nil, util.Errorf(
 			"bad response from artifact registry: %s: %q",
 			err,
 			string(respBytes[:l]),
@@ -184,20 +237,25 @@ func (a registry) fetchRegistryData(podID types.PodID, launchableID launch.Launc
 	// Require artifact URL to be present, other fields are optional but returned
 	var artifactURL *url.URL
 	if registryResponse.ArtifactLocation == "" {
-		return nil, auth.VerificationData{}, util.Errorf("No artifact url returned in registry response")
+		return nil, auth.VerificationData{}, nil, util.Errorf("No artifact url returned in registry response")
 	} else {
 		artifactURL, err = url.Parse(registryResponse.ArtifactLocation)
 		if err != nil {
-			return nil, auth.VerificationData{}, util.Errorf("Could not parse artifact url in registry response: %s", err)
+			return nil, auth.VerificationData{}, nil, util.Errorf("Could not parse artifact url in registry response: %s", err)
 		}
 	}
 
 	authData, err := a.authDataFromRegistryResponse(registryResponse)
 	if err != nil {
-		return nil, auth.VerificationData{}, err
+		return nil, auth.VerificationData{}, nil, err
 	}
 
-	return artifactURL, authData, nil
+	delta, err := deltaFromRegistryResponse(registryResponse, baseDigests)
+	if err != nil {
+		return nil, auth.VerificationData{}, nil, err
+	}
+
+	return artifactURL, authData, delta, nil
 }
 
 func (a registry) authDataFromRegistryResponse(registryResponse RegistryResponse) (auth.VerificationData, error) {
@@ -226,9 +284,37 @@ func (a registry) authDataFromRegistryResponse(registryResponse RegistryResponse
 		verificationData.BuildSignatureLocation = buildSignatureURL
 	}
 
+	verificationData.ArtifactSHA256 = registryResponse.ArtifactSHA256
+
 	return verificationData, nil
 }
 
+func deltaFromRegistryResponse(registryResponse RegistryResponse, baseDigests []string) (*Delta, error) {
+	if registryResponse.DeltaLocation == "" || registryResponse.ArtifactSHA256 == "" {
+		return nil, nil
+	}
+	// Ignore a delta from a base the node didn't offer
+	offered := false
+	for _, digest := range baseDigests {
+		if strings.EqualFold(digest, registryResponse.DeltaBaseSHA256) {
+			offered = true
+			break
+		}
+	}
+	if !offered {
+		return nil, nil
+	}
+
+	deltaURL, err := url.Parse(registryResponse.DeltaLocation)
+	if err != nil {
+		return nil, util.Errorf("Couldn't parse delta URL from registry response: %s", err)
+	}
+	return &Delta{
+		BaseSHA256: strings.ToLower(registryResponse.DeltaBaseSHA256),
+		Location:   deltaURL,
+	}, nil
+}
+
 func VerificationDataForLocation(location *url.URL) auth.VerificationData {
 	manifestLocation := &url.URL{}
 	*manifestLocation = *location
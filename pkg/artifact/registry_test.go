@@ -142,12 +142,14 @@ func TestVersionScheme(t *testing.T) {
 	manifestPath := "/path/to/manifest"
 	manifestSignaturePath := "/path/to/manifest/signature"
 	buildSignaturePath := "/path/to/build/signature"
+	artifactSHA256 := "6ca13d52ca70c883e0f0bb101e425a89e8624de51db2d2392593af6a84118090"
 
 	cannedRegResponse := RegistryResponse{
 		ArtifactLocation:          artifactPath,
 		ManifestLocation:          manifestPath,
 		ManifestSignatureLocation: manifestSignaturePath,
 		BuildSignatureLocation:    buildSignaturePath,
+		ArtifactSHA256:            artifactSHA256,
 	}
 
 	data, err := json.Marshal(cannedRegResponse)
@@ -221,6 +223,10 @@ func TestVersionScheme(t *testing.T) {
 		t.Errorf("Expected build signature URL to be '%s', was '%s'", expectedBuildSignatureURL.String(), verificationData.BuildSignatureLocation.String())
 	}
 
+	if verificationData.ArtifactSHA256 != artifactSHA256 {
+		t.Errorf("Expected artifact digest to be '%s', was '%s'", artifactSHA256, verificationData.ArtifactSHA256)
+	}
+
 	// Now make sure the correct URL was requested
 	if fakeFetcher.FetchedURL.Host != registryHost {
 		t.Errorf("Expected registry to make request to host '%s', but made request to '%s'", registryHost, fakeFetcher.FetchedURL.Host)
@@ -290,3 +296,41 @@ func TestDiscoveryArtifactOverride(t *testing.T) {
 		}
 	}
 }
+
+type statusFetcher struct {
+	FakeFetcher
+	status int
+}
+
+func (f *statusFetcher) Head(u *url.URL) (*http.Response, error) {
+	f.FetchedURL = u
+	return &http.Response{StatusCode: f.status, Body: ioutil.NopCloser(bytes.NewReader(nil))}, nil
+}
+
+func TestCheckArtifactExistsS3(t *testing.T) {
+	u, err := url.Parse("s3://artifacts/myapp_abc123.tar.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, testCase := range []struct {
+		status   int
+		expected bool
+	}{
+		{status: http.StatusOK, expected: true},
+		{status: http.StatusNotFound, expected: false},
+	} {
+		fetcher := &statusFetcher{status: testCase.status}
+		registry := NewRegistry(nil, fetcher, &fixedDetector{})
+		exists, err := registry.CheckArtifactExists(u)
+		if err != nil {
+			t.Fatalf("Unexpected error checking artifact: %s", err)
+		}
+		if exists != testCase.expected {
+			t.Errorf("Expected exists to be %t for status %d", testCase.expected, testCase.status)
+		}
+		if fetcher.FetchedURL != u {
+			t.Errorf("Expected HEAD request for %s", u)
+		}
+	}
+}
@@ -0,0 +1,756 @@
+// Package registryserver implements a reference artifact registry that speaks
+// the protocol expected by artifact.Registry. Artifact versions are uploaded
+// to the registry, checked against an auth.ArtifactVerifier and recorded in an
+// index on local disk. Once recorded, a version can never be replaced.
+//
+// Artifacts may either be uploaded directly, in which case the registry
+// stores and serves them itself, or registered by location, in which case
+// they are read through a uri.Fetcher (for example from an object store) for
+// verification and clients download them from that location.
+package registryserver
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/square/p2/pkg/artifact"
+	"github.com/square/p2/pkg/artifact/delta"
+	"github.com/square/p2/pkg/auth"
+	"github.com/square/p2/pkg/launch"
+	"github.com/square/p2/pkg/logging"
+	"github.com/square/p2/pkg/osversion"
+	"github.com/square/p2/pkg/types"
+	"github.com/square/p2/pkg/uri"
+	"github.com/square/p2/pkg/util"
+)
+
+const (
+	filesDir   = "files"
+	indexDir   = "index"
+	stagingDir = "staging"
+
+	// anyValue stands in for an unset pod ID, OS or OS version in the
+	// on-disk layout. It can never collide with a real value because those
+	// must start with a letter or digit.
+	anyValue = "_"
+
+	// Names of the multipart form parts accepted by the upload endpoint
+	artifactPart          = "artifact"
+	signaturePart         = "signature"
+	manifestPart          = "manifest"
+	manifestSignaturePart = "manifest_signature"
+
+	// Uploads larger than this are spooled to disk while being parsed
+	maxUploadMemory = 32 << 20
+
+	deltaSuffix = ".delta"
+)
+
+var safeName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Version describes one registered build of an artifact. A version may be
+// restricted to a pod and to an OS and OS version; empty fields match any
+// value.
+type Version struct {
+	ArtifactName launch.ArtifactName        `json:"artifact_name"`
+	VersionID    launch.LaunchableVersionID `json:"version"`
+	PodID        types.PodID                `json:"pod_id,omitempty"`
+	OS           osversion.OS               `json:"os,omitempty"`
+	OSVersion    osversion.OSVersion        `json:"os_version,omitempty"`
+
+	Location                  string `json:"location"`
+	ManifestLocation          string `json:"manifest_location,omitempty"`
+	ManifestSignatureLocation string `json:"manifest_signature_location,omitempty"`
+	BuildSignatureLocation    string `json:"signature_location,omitempty"`
+
+	SHA256     string    `json:"sha256"`
+	Size       int64     `json:"size"`
+	UploadedAt time.Time `json:"uploaded_at"`
+
+	// A delta from the previous build of the same variant, if one was
+	// uploaded to the registry and the delta is smaller than this build
+	DeltaBaseSHA256 string `json:"delta_base_sha256,omitempty"`
+	DeltaLocation   string `json:"delta_location,omitempty"`
+}
+
+func (v Version) RegistryResponse() artifact.RegistryResponse {
+	return artifact.RegistryResponse{
+		ArtifactLocation:          v.Location,
+		ManifestLocation:          v.ManifestLocation,
+		ManifestSignatureLocation: v.ManifestSignatureLocation,
+		BuildSignatureLocation:    v.BuildSignatureLocation,
+		ArtifactSHA256:            v.SHA256,
+	}
+}
+
+// variant is the directory name that distinguishes builds of the same
+// version for different pods and operating systems.
+func (v Version) variant() string {
+	return variantName(v.PodID.String(), v.OS.String(), v.OSVersion.String())
+}
+
+func variantName(podID string, os string, osVersion string) string {
+	parts := []string{podID, os, osVersion}
+	for i, part := range parts {
+		if part == "" {
+			parts[i] = anyValue
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+type Server struct {
+	root      string
+	publicURL *url.URL
+	fetcher   uri.Fetcher
+	verifier  auth.ArtifactVerifier
+	logger    logging.Logger
+
+	// uploadMux serializes the final step of every upload so that the
+	// check for an existing version and the write of a new one are atomic
+	uploadMux sync.Mutex
+}
+
+// New creates a registry server that keeps its index and any uploaded
+// artifacts under root. publicURL is the address clients use to reach the
+// server, and is used to build the locations of uploaded artifacts. fetcher
+// is used to read artifacts registered by location, and verifier checks
+// every artifact before it is recorded.
+func New(root string, publicURL *url.URL, fetcher uri.Fetcher, verifier auth.ArtifactVerifier, logger logging.Logger) (*Server, error) {
+	if fetcher == nil {
+		fetcher = uri.DefaultFetcher
+	}
+	if verifier == nil {
+		verifier = auth.NopVerifier()
+	}
+	for _, dir := range []string{filesDir, indexDir, stagingDir} {
+		err := os.MkdirAll(filepath.Join(root, dir), 0755)
+		if err != nil {
+			return nil, util.Errorf("could not create registry directory: %s", err)
+		}
+	}
+	return &Server{
+		root:      root,
+		publicURL: publicURL,
+		fetcher:   fetcher,
+		verifier:  verifier,
+		logger:    logger,
+	}, nil
+}
+
+func (s *Server) AddRoutes(r *mux.Router) {
+	r.Methods("GET").Path("/discover/{pod_id}").HandlerFunc(s.Discover)
+	r.Methods("GET").Path("/artifacts").HandlerFunc(s.ListArtifacts)
+	r.Methods("GET").Path("/artifacts/{artifact_name}").HandlerFunc(s.ListVersions)
+	r.Methods("GET").Path("/artifacts/{artifact_name}/{version}").HandlerFunc(s.GetVersion)
+	r.Methods("POST").Path("/artifacts/{artifact_name}/{version}").HandlerFunc(s.Upload)
+	r.Methods("GET", "HEAD").PathPrefix("/" + filesDir + "/").Handler(
+		http.StripPrefix("/"+filesDir+"/", http.FileServer(http.Dir(filepath.Join(s.root, filesDir)))),
+	)
+}
+
+func (s *Server) Handler() *mux.Router {
+	r := mux.NewRouter()
+	s.AddRoutes(r)
+	return r
+}
+
+// Discover answers the queries made by artifact.Registry. The most specific
+// matching build is returned: one registered for the pod beats one
+// registered for the OS, which beats one registered for any pod and OS.
+func (s *Server) Discover(resp http.ResponseWriter, req *http.Request) {
+	podID := mux.Vars(req)["pod_id"]
+	query := req.URL.Query()
+	artifactName := query.Get("artifact_name")
+	versionID := query.Get("version")
+	osName := query.Get("os")
+	osVersion := query.Get("os_version")
+
+	if !safeName.MatchString(artifactName) || !safeName.MatchString(versionID) {
+		http.Error(resp, "artifact_name and version must be provided", http.StatusBadRequest)
+		return
+	}
+	for _, value := range []string{podID, osName, osVersion} {
+		if value != "" && !safeName.MatchString(value) {
+			http.Error(resp, util.Errorf("invalid value %q", value).Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	version, err := s.lookup(podID, launch.ArtifactName(artifactName), launch.LaunchableVersionID(versionID), osName, osVersion)
+	switch {
+	case os.IsNotExist(err):
+		http.Error(resp, "no matching artifact version", http.StatusNotFound)
+		return
+	case err != nil:
+		s.serverError(resp, err)
+		return
+	}
+
+	registryResponse := version.RegistryResponse()
+	// Only offer the delta to clients that hold its base
+	for _, base := range query["delta_from"] {
+		if version.DeltaLocation != "" && strings.EqualFold(base, version.DeltaBaseSHA256) {
+			registryResponse.DeltaLocation = version.DeltaLocation
+			registryResponse.DeltaBaseSHA256 = version.DeltaBaseSHA256
+			break
+		}
+	}
+	s.writeJSON(resp, http.StatusOK, registryResponse)
+}
+
+func (s *Server) lookup(podID string, name launch.ArtifactName, versionID launch.LaunchableVersionID, osName string, osVersion string) (Version, error) {
+	var candidates []string
+	if podID != "" {
+		candidates = append(candidates,
+			variantName(podID, osName, osVersion),
+			variantName(podID, osName, ""),
+			variantName(podID, "", ""),
+		)
+	}
+	candidates = append(candidates,
+		variantName("", osName, osVersion),
+		variantName("", osName, ""),
+		variantName("", "", ""),
+	)
+
+	for _, candidate := range candidates {
+		version, err := s.readVersion(name, versionID, candidate)
+		if os.IsNotExist(err) {
+			continue
+		}
+		return version, err
+	}
+	return Version{}, os.ErrNotExist
+}
+
+// ListArtifacts returns the names of every artifact with at least one
+// registered version.
+func (s *Server) ListArtifacts(resp http.ResponseWriter, req *http.Request) {
+	names, err := readDirNames(filepath.Join(s.root, indexDir))
+	if err != nil {
+		s.serverError(resp, err)
+		return
+	}
+	s.writeJSON(resp, http.StatusOK, names)
+}
+
+// ListVersions returns every registered build of an artifact, oldest first.
+func (s *Server) ListVersions(resp http.ResponseWriter, req *http.Request) {
+	name := mux.Vars(req)["artifact_name"]
+	if !safeName.MatchString(name) {
+		http.Error(resp, "invalid artifact name", http.StatusBadRequest)
+		return
+	}
+
+	versionIDs, err := readDirNames(filepath.Join(s.root, indexDir, name))
+	if err != nil {
+		s.serverError(resp, err)
+		return
+	}
+
+	versions := []Version{}
+	for _, versionID := range versionIDs {
+		builds, err := s.readVersions(launch.ArtifactName(name), launch.LaunchableVersionID(versionID))
+		if err != nil {
+			s.serverError(resp, err)
+			return
+		}
+		versions = append(versions, builds...)
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].UploadedAt.Before(versions[j].UploadedAt)
+	})
+	s.writeJSON(resp, http.StatusOK, versions)
+}
+
+// GetVersion returns every build registered for one version of an artifact.
+func (s *Server) GetVersion(resp http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	name, versionID := vars["artifact_name"], vars["version"]
+	if !safeName.MatchString(name) || !safeName.MatchString(versionID) {
+		http.Error(resp, "invalid artifact name or version", http.StatusBadRequest)
+		return
+	}
+
+	versions, err := s.readVersions(launch.ArtifactName(name), launch.LaunchableVersionID(versionID))
+	if err != nil {
+		s.serverError(resp, err)
+		return
+	}
+	if len(versions) == 0 {
+		http.Error(resp, "no such version", http.StatusNotFound)
+		return
+	}
+	s.writeJSON(resp, http.StatusOK, versions)
+}
+
+// Upload registers a new build of an artifact version. The request is a
+// multipart form. The artifact is either sent as the "artifact" file, along
+// with its verification files as "signature", "manifest" and
+// "manifest_signature", or referenced by the "location" field, in which
+// case the verification files are found through the
+// "signature_location", "manifest_location" and
+// "manifest_signature_location" fields or the usual suffixes. The optional
+// "pod_id", "os" and "os_version" fields restrict which nodes the build is
+// offered to.
+//
+// A build that already exists is never replaced: the upload fails with 409
+// Conflict. A build that fails verification is rejected with 422.
+func (s *Server) Upload(resp http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	version := Version{
+		ArtifactName: launch.ArtifactName(vars["artifact_name"]),
+		VersionID:    launch.LaunchableVersionID(vars["version"]),
+	}
+	if !safeName.MatchString(version.ArtifactName.String()) || !safeName.MatchString(version.VersionID.String()) {
+		http.Error(resp, "invalid artifact name or version", http.StatusBadRequest)
+		return
+	}
+
+	err := req.ParseMultipartForm(maxUploadMemory)
+	if err != nil {
+		http.Error(resp, util.Errorf("could not parse upload: %s", err).Error(), http.StatusBadRequest)
+		return
+	}
+	defer req.MultipartForm.RemoveAll()
+
+	version.PodID = types.PodID(req.FormValue("pod_id"))
+	version.OS = osversion.OS(req.FormValue("os"))
+	version.OSVersion = osversion.OSVersion(req.FormValue("os_version"))
+	for _, value := range []string{version.PodID.String(), version.OS.String(), version.OSVersion.String()} {
+		if value != "" && !safeName.MatchString(value) {
+			http.Error(resp, util.Errorf("invalid value %q", value).Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if version.OSVersion != "" && version.OS == "" {
+		http.Error(resp, "os_version requires os", http.StatusBadRequest)
+		return
+	}
+
+	logger := s.logger.SubLogger(logrus.Fields{
+		"artifact_name": version.ArtifactName,
+		"version":       version.VersionID,
+		"variant":       version.variant(),
+	})
+
+	if _, err := s.readVersion(version.ArtifactName, version.VersionID, version.variant()); err == nil {
+		http.Error(resp, "version already exists and cannot be replaced", http.StatusConflict)
+		return
+	}
+
+	staging, err := ioutil.TempDir(filepath.Join(s.root, stagingDir), "upload")
+	if err != nil {
+		s.serverError(resp, err)
+		return
+	}
+	defer os.RemoveAll(staging)
+
+	if _, _, missing := req.FormFile(artifactPart); missing == nil {
+		err = s.stageUpload(req, staging, &version)
+	} else {
+		err = s.stageLocation(req, staging, &version)
+	}
+	if err != nil {
+		http.Error(resp, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = s.verify(staging, &version)
+	if err != nil {
+		logger.WithError(err).Warnln("Rejected artifact upload")
+		http.Error(resp, util.Errorf("artifact failed verification: %s", err).Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	if version.Location == "" {
+		err = s.stageDelta(staging, &version)
+		if err != nil {
+			// Clients can always fall back to the full artifact
+			logger.WithError(err).Warnln("Could not compute delta from previous build")
+		}
+	}
+
+	version.UploadedAt = time.Now().UTC()
+	err = s.commit(staging, version)
+	if os.IsExist(err) {
+		http.Error(resp, "version already exists and cannot be replaced", http.StatusConflict)
+		return
+	} else if err != nil {
+		s.serverError(resp, err)
+		return
+	}
+
+	logger.WithField("sha256", version.SHA256).Infoln("Registered artifact version")
+	s.writeJSON(resp, http.StatusCreated, version)
+}
+
+func (s *Server) artifactFileName(version Version) string {
+	return version.ArtifactName.String() + "_" + version.VersionID.String() + ".tar.gz"
+}
+
+// stageUpload copies the uploaded files into the staging directory.
+func (s *Server) stageUpload(req *http.Request, staging string, version *Version) error {
+	artifactName := s.artifactFileName(*version)
+	files := map[string]string{
+		artifactPart:          artifactName,
+		signaturePart:         artifactName + ".sig",
+		manifestPart:          artifactName + ".manifest",
+		manifestSignaturePart: artifactName + ".manifest.sig",
+	}
+	for part, name := range files {
+		file, _, err := req.FormFile(part)
+		if err == http.ErrMissingFile {
+			continue
+		} else if err != nil {
+			return util.Errorf("could not read %s: %s", part, err)
+		}
+		err = writeFile(filepath.Join(staging, name), file)
+		_ = file.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stageLocation downloads an artifact that is stored elsewhere into the
+// staging directory so it can be verified, and records its locations.
+func (s *Server) stageLocation(req *http.Request, staging string, version *Version) error {
+	location := req.FormValue("location")
+	if location == "" {
+		return util.Errorf("either an artifact or a location must be provided")
+	}
+	artifactURL, err := url.Parse(location)
+	if err != nil {
+		return util.Errorf("could not parse location: %s", err)
+	}
+
+	verificationData := artifact.VerificationDataForLocation(artifactURL)
+	for field, dst := range map[string]**url.URL{
+		"manifest_location":           &verificationData.ManifestLocation,
+		"manifest_signature_location": &verificationData.ManifestSignatureLocation,
+		"signature_location":          &verificationData.BuildSignatureLocation,
+	} {
+		if value := req.FormValue(field); value != "" {
+			parsed, err := url.Parse(value)
+			if err != nil {
+				return util.Errorf("could not parse %s: %s", field, err)
+			}
+			*dst = parsed
+		}
+	}
+
+	artifactFile, err := s.fetcher.Open(artifactURL)
+	if err != nil {
+		return util.Errorf("could not read artifact from %s: %s", artifactURL, err)
+	}
+	defer artifactFile.Close()
+	err = writeFile(filepath.Join(staging, s.artifactFileName(*version)), artifactFile)
+	if err != nil {
+		return err
+	}
+
+	version.Location = artifactURL.String()
+	version.ManifestLocation = verificationData.ManifestLocation.String()
+	version.ManifestSignatureLocation = verificationData.ManifestSignatureLocation.String()
+	version.BuildSignatureLocation = verificationData.BuildSignatureLocation.String()
+	return nil
+}
+
+// verify hashes the staged artifact and runs the configured verifier over
+// it, using either the staged verification files or the recorded locations.
+func (s *Server) verify(staging string, version *Version) error {
+	artifactPath := filepath.Join(staging, s.artifactFileName(*version))
+	artifactFile, err := os.Open(artifactPath)
+	if err != nil {
+		return err
+	}
+	defer artifactFile.Close()
+
+	hasher := sha256.New()
+	size, err := io.Copy(hasher, artifactFile)
+	if err != nil {
+		return err
+	}
+	version.SHA256 = hex.EncodeToString(hasher.Sum(nil))
+	version.Size = size
+
+	_, err = artifactFile.Seek(0, os.SEEK_SET)
+	if err != nil {
+		return err
+	}
+
+	var verificationData auth.VerificationData
+	if version.Location == "" {
+		verificationData = artifact.VerificationDataForLocation(&url.URL{Scheme: "file", Path: artifactPath})
+	} else {
+		for src, dst := range map[string]**url.URL{
+			version.ManifestLocation:          &verificationData.ManifestLocation,
+			version.ManifestSignatureLocation: &verificationData.ManifestSignatureLocation,
+			version.BuildSignatureLocation:    &verificationData.BuildSignatureLocation,
+		} {
+			*dst, err = url.Parse(src)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return s.verifier.VerifyHoistArtifact(artifactFile, verificationData)
+}
+
+// stageDelta computes a delta from the most recent earlier build of the same
+// variant that is stored by the registry. The delta is discarded if it isn't
+// smaller than the artifact itself.
+func (s *Server) stageDelta(staging string, version *Version) error {
+	previous, previousPath, ok, err := s.previousBuild(*version)
+	if err != nil || !ok {
+		return err
+	}
+
+	base, err := os.Open(previousPath)
+	if err != nil {
+		return err
+	}
+	defer base.Close()
+	artifactPath := filepath.Join(staging, s.artifactFileName(*version))
+	target, err := os.Open(artifactPath)
+	if err != nil {
+		return err
+	}
+	defer target.Close()
+
+	deltaPath := artifactPath + deltaSuffix
+	deltaFile, err := os.OpenFile(deltaPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	err = delta.Diff(base, target, deltaFile)
+	if errC := deltaFile.Close(); err == nil {
+		err = errC
+	}
+	if err != nil {
+		_ = os.Remove(deltaPath)
+		return err
+	}
+
+	info, err := os.Stat(deltaPath)
+	if err != nil {
+		return err
+	}
+	if info.Size() >= version.Size {
+		return os.Remove(deltaPath)
+	}
+	version.DeltaBaseSHA256 = previous.SHA256
+	return nil
+}
+
+// previousBuild finds the most recently uploaded build of another version of
+// the same artifact and variant whose file is stored by the registry.
+func (s *Server) previousBuild(version Version) (Version, string, bool, error) {
+	versionIDs, err := readDirNames(filepath.Join(s.root, indexDir, version.ArtifactName.String()))
+	if err != nil {
+		return Version{}, "", false, err
+	}
+
+	var previous Version
+	var previousPath string
+	found := false
+	for _, versionID := range versionIDs {
+		if versionID == version.VersionID.String() {
+			continue
+		}
+		candidate, err := s.readVersion(version.ArtifactName, launch.LaunchableVersionID(versionID), version.variant())
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return Version{}, "", false, err
+		}
+		if found && !candidate.UploadedAt.After(previous.UploadedAt) {
+			continue
+		}
+		candidatePath := filepath.Join(s.root, filesDir, version.ArtifactName.String(), versionID, version.variant(), s.artifactFileName(candidate))
+		if _, err := os.Stat(candidatePath); err != nil {
+			continue
+		}
+		previous, previousPath, found = candidate, candidatePath, true
+	}
+	return previous, previousPath, found, nil
+}
+
+// commit moves staged files into place and writes the index record. The
+// record is linked into place so that an existing record is never
+// overwritten.
+func (s *Server) commit(staging string, version Version) error {
+	s.uploadMux.Lock()
+	defer s.uploadMux.Unlock()
+
+	recordPath := s.recordPath(version.ArtifactName, version.VersionID, version.variant())
+	if _, err := os.Stat(recordPath); err == nil {
+		return os.ErrExist
+	}
+
+	if version.Location == "" {
+		filesPath := path.Join(version.ArtifactName.String(), version.VersionID.String(), version.variant())
+		dst := filepath.Join(s.root, filesDir, filepath.FromSlash(filesPath))
+		// Clear out any files left by an upload that failed before its
+		// record was written
+		err := os.RemoveAll(dst)
+		if err != nil {
+			return err
+		}
+		err = os.MkdirAll(filepath.Dir(dst), 0755)
+		if err != nil {
+			return err
+		}
+		err = os.Chmod(staging, 0755)
+		if err != nil {
+			return err
+		}
+		err = os.Rename(staging, dst)
+		if err != nil {
+			return err
+		}
+
+		base := s.fileURL(path.Join(filesPath, s.artifactFileName(version)))
+		version.Location = base
+		for suffix, field := range map[string]*string{
+			".sig":          &version.BuildSignatureLocation,
+			".manifest":     &version.ManifestLocation,
+			".manifest.sig": &version.ManifestSignatureLocation,
+			deltaSuffix:     &version.DeltaLocation,
+		} {
+			if _, err := os.Stat(filepath.Join(dst, s.artifactFileName(version)+suffix)); err == nil {
+				*field = base + suffix
+			}
+		}
+	}
+
+	recordBytes, err := json.MarshalIndent(version, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(recordPath), 0755)
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Join(s.root, stagingDir), "record")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(recordBytes)
+	if errC := tmp.Close(); err == nil {
+		err = errC
+	}
+	if err != nil {
+		return err
+	}
+	return os.Link(tmp.Name(), recordPath)
+}
+
+func (s *Server) fileURL(filePath string) string {
+	u := *s.publicURL
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + filesDir + "/" + filePath
+	return u.String()
+}
+
+func (s *Server) recordPath(name launch.ArtifactName, versionID launch.LaunchableVersionID, variant string) string {
+	return filepath.Join(s.root, indexDir, name.String(), versionID.String(), variant+".json")
+}
+
+func (s *Server) readVersion(name launch.ArtifactName, versionID launch.LaunchableVersionID, variant string) (Version, error) {
+	recordBytes, err := ioutil.ReadFile(s.recordPath(name, versionID, variant))
+	if err != nil {
+		return Version{}, err
+	}
+	var version Version
+	err = json.Unmarshal(recordBytes, &version)
+	if err != nil {
+		return Version{}, util.Errorf("corrupt index record for %s %s %s: %s", name, versionID, variant, err)
+	}
+	return version, nil
+}
+
+func (s *Server) readVersions(name launch.ArtifactName, versionID launch.LaunchableVersionID) ([]Version, error) {
+	records, err := readDirNames(filepath.Join(s.root, indexDir, name.String(), versionID.String()))
+	if err != nil {
+		return nil, err
+	}
+	versions := []Version{}
+	for _, record := range records {
+		if !strings.HasSuffix(record, ".json") {
+			continue
+		}
+		version, err := s.readVersion(name, versionID, strings.TrimSuffix(record, ".json"))
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+	return versions, nil
+}
+
+func (s *Server) writeJSON(resp http.ResponseWriter, status int, body interface{}) {
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		s.serverError(resp, err)
+		return
+	}
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(status)
+	_, err = resp.Write(bodyBytes)
+	if err != nil {
+		s.logger.WithError(err).Errorln("Could not write response")
+	}
+}
+
+func (s *Server) serverError(resp http.ResponseWriter, err error) {
+	s.logger.WithError(err).Errorln("Registry request failed")
+	http.Error(resp, err.Error(), http.StatusInternalServerError)
+}
+
+// readDirNames returns the sorted names in a directory, or nothing if the
+// directory doesn't exist.
+func readDirNames(dir string) ([]string, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return []string{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(infos))
+	for _, info := range infos {
+		names = append(names, info.Name())
+	}
+	return names, nil
+}
+
+func writeFile(dst string, src io.Reader) error {
+	file, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(file, src)
+	if errC := file.Close(); err == nil {
+		err = errC
+	}
+	return err
+}
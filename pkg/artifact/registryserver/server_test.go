@@ -0,0 +1,350 @@
+package registryserver
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math/rand"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/square/p2/pkg/artifact"
+	"github.com/square/p2/pkg/artifact/delta"
+	"github.com/square/p2/pkg/auth"
+	"github.com/square/p2/pkg/launch"
+	"github.com/square/p2/pkg/logging"
+	"github.com/square/p2/pkg/osversion"
+	"github.com/square/p2/pkg/uri"
+)
+
+type fixedDetector struct {
+	os        osversion.OS
+	osVersion osversion.OSVersion
+}
+
+func (f fixedDetector) Version() (osversion.OS, osversion.OSVersion, error) {
+	return f.os, f.osVersion, nil
+}
+
+type rejectingVerifier struct{}
+
+func (rejectingVerifier) VerifyHoistArtifact(_ *os.File, _ auth.VerificationData) error {
+	return errors.New("bad signature")
+}
+
+func newTestServer(t *testing.T, verifier auth.ArtifactVerifier) (*httptest.Server, func()) {
+	root, err := ioutil.TempDir("", "registry_server")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var server *Server
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.Handler().ServeHTTP(w, r)
+	}))
+	publicURL, _ := url.Parse(ts.URL)
+	server, err = New(root, publicURL, nil, verifier, logging.TestLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ts, func() {
+		ts.Close()
+		os.RemoveAll(root)
+	}
+}
+
+func upload(t *testing.T, ts *httptest.Server, name string, version string, fields map[string]string, files map[string][]byte) *http.Response {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for key, value := range fields {
+		if err := writer.WriteField(key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for part, contents := range files {
+		w, err := writer.CreateFormFile(part, part)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = w.Write(contents)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := http.Post(ts.URL+"/artifacts/"+name+"/"+version, writer.FormDataContentType(), body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	return resp
+}
+
+func discover(t *testing.T, ts *httptest.Server, detector osversion.Detector, versionID string) (*url.URL, auth.VerificationData, error) {
+	registryURL, _ := url.Parse(ts.URL)
+	registry := artifact.NewRegistry(registryURL, uri.DefaultFetcher, detector)
+	return registry.LocationDataForLaunchable("mypod", "myapp", launch.LaunchableStanza{
+		Version: launch.LaunchableVersion{ID: launch.LaunchableVersionID(versionID)},
+	})
+}
+
+func TestUploadAndDiscover(t *testing.T) {
+	ts, cleanup := newTestServer(t, nil)
+	defer cleanup()
+
+	contents := []byte("pretend this is a tarball")
+	resp := upload(t, ts, "myapp", "abc123", nil, map[string][]byte{
+		artifactPart:  contents,
+		signaturePart: []byte("signature"),
+	})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected upload to succeed, got %s", resp.Status)
+	}
+
+	location, verificationData, err := discover(t, ts, fixedDetector{"CentOS", "7.2"}, "abc123")
+	if err != nil {
+		t.Fatalf("Unexpected error discovering artifact: %s", err)
+	}
+
+	downloaded, err := uri.DefaultFetcher.Open(location)
+	if err != nil {
+		t.Fatalf("Could not download artifact from %s: %s", location, err)
+	}
+	defer downloaded.Close()
+	downloadedBytes, _ := ioutil.ReadAll(downloaded)
+	if !bytes.Equal(downloadedBytes, contents) {
+		t.Errorf("Downloaded artifact did not match upload")
+	}
+
+	sum := sha256.Sum256(contents)
+	if verificationData.ArtifactSHA256 != hex.EncodeToString(sum[:]) {
+		t.Errorf("Expected digest %x, got %s", sum, verificationData.ArtifactSHA256)
+	}
+	if verificationData.BuildSignatureLocation.String() != location.String()+".sig" {
+		t.Errorf("Unexpected signature location %s", verificationData.BuildSignatureLocation)
+	}
+	if verificationData.ManifestLocation != nil {
+		t.Errorf("Expected no manifest location since none was uploaded, got %s", verificationData.ManifestLocation)
+	}
+
+	_, _, err = discover(t, ts, fixedDetector{"CentOS", "7.2"}, "def456")
+	if err == nil {
+		t.Error("Expected an error discovering a version that was never uploaded")
+	}
+}
+
+func TestVersionsAreImmutable(t *testing.T) {
+	ts, cleanup := newTestServer(t, nil)
+	defer cleanup()
+
+	resp := upload(t, ts, "myapp", "abc123", nil, map[string][]byte{artifactPart: []byte("first")})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected upload to succeed, got %s", resp.Status)
+	}
+
+	resp = upload(t, ts, "myapp", "abc123", nil, map[string][]byte{artifactPart: []byte("second")})
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("Expected re-upload to be rejected with a conflict, got %s", resp.Status)
+	}
+
+	// A build of the same version for a specific OS is a different variant
+	resp = upload(t, ts, "myapp", "abc123", map[string]string{"os": "CentOS"}, map[string][]byte{artifactPart: []byte("centos")})
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("Expected OS-specific upload to succeed, got %s", resp.Status)
+	}
+}
+
+func TestUploadRejectedByVerifier(t *testing.T) {
+	ts, cleanup := newTestServer(t, rejectingVerifier{})
+	defer cleanup()
+
+	resp := upload(t, ts, "myapp", "abc123", nil, map[string][]byte{artifactPart: []byte("unsigned")})
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("Expected upload to fail verification, got %s", resp.Status)
+	}
+
+	_, _, err := discover(t, ts, fixedDetector{"CentOS", "7.2"}, "abc123")
+	if err == nil {
+		t.Error("Expected the rejected version not to be discoverable")
+	}
+}
+
+func TestDiscoverPrefersMostSpecificBuild(t *testing.T) {
+	ts, cleanup := newTestServer(t, nil)
+	defer cleanup()
+
+	for _, fields := range []map[string]string{
+		{},
+		{"os": "CentOS"},
+		{"os": "CentOS", "os_version": "6.6"},
+		{"pod_id": "mypod", "os": "Ubuntu"},
+	} {
+		resp := upload(t, ts, "myapp", "abc123", fields, map[string][]byte{artifactPart: []byte(variantName(fields["pod_id"], fields["os"], fields["os_version"]))})
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("Expected upload of %v to succeed, got %s", fields, resp.Status)
+		}
+	}
+
+	for _, testCase := range []struct {
+		detector osversion.Detector
+		expected string
+	}{
+		{fixedDetector{"CentOS", "6.6"}, "_,CentOS,6.6"},
+		{fixedDetector{"CentOS", "7.2"}, "_,CentOS,_"},
+		{fixedDetector{"Debian", "9"}, "_,_,_"},
+		{fixedDetector{"Ubuntu", "18.04"}, "mypod,Ubuntu,_"},
+	} {
+		location, _, err := discover(t, ts, testCase.detector, "abc123")
+		if err != nil {
+			t.Fatalf("Unexpected error discovering artifact: %s", err)
+		}
+		reader, err := uri.DefaultFetcher.Open(location)
+		if err != nil {
+			t.Fatal(err)
+		}
+		contents, _ := ioutil.ReadAll(reader)
+		_ = reader.Close()
+		if string(contents) != testCase.expected {
+			t.Errorf("Expected build %q for %v, got %q", testCase.expected, testCase.detector, string(contents))
+		}
+	}
+}
+
+func TestRegisterByLocation(t *testing.T) {
+	ts, cleanup := newTestServer(t, nil)
+	defer cleanup()
+
+	dir, err := ioutil.TempDir("", "registry_location")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	artifactPath := filepath.Join(dir, "myapp_abc123.tar.gz")
+	err = ioutil.WriteFile(artifactPath, []byte("stored elsewhere"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	location := "file://" + artifactPath
+	resp := upload(t, ts, "myapp", "abc123", map[string]string{"location": location}, nil)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected registration to succeed, got %s", resp.Status)
+	}
+
+	discovered, verificationData, err := discover(t, ts, fixedDetector{"CentOS", "7.2"}, "abc123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if discovered.String() != location {
+		t.Errorf("Expected location %s, got %s", location, discovered)
+	}
+	if verificationData.ManifestLocation.String() != location+".manifest" {
+		t.Errorf("Expected manifest location to be inferred, got %s", verificationData.ManifestLocation)
+	}
+}
+
+func TestListVersions(t *testing.T) {
+	ts, cleanup := newTestServer(t, nil)
+	defer cleanup()
+
+	for _, version := range []string{"abc123", "def456"} {
+		resp := upload(t, ts, "myapp", version, nil, map[string][]byte{artifactPart: []byte(version)})
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("Expected upload to succeed, got %s", resp.Status)
+		}
+	}
+
+	resp, err := http.Get(ts.URL + "/artifacts/myapp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var versions []Version
+	err = json.NewDecoder(resp.Body).Decode(&versions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 2 || versions[0].VersionID != "abc123" || versions[1].VersionID != "def456" {
+		t.Errorf("Unexpected versions listed: %+v", versions)
+	}
+
+	resp, err = http.Get(ts.URL + "/artifacts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var names []string
+	err = json.NewDecoder(resp.Body).Decode(&names)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 1 || names[0] != "myapp" {
+		t.Errorf("Unexpected artifacts listed: %v", names)
+	}
+}
+
+func TestDeltaFromPreviousVersion(t *testing.T) {
+	ts, cleanup := newTestServer(t, nil)
+	defer cleanup()
+
+	r := rand.New(rand.NewSource(1))
+	first := make([]byte, 128*1024)
+	_, _ = r.Read(first)
+	second := append([]byte{}, first...)
+	copy(second[5000:], "a small change")
+
+	// The second upload is diffed against the first
+	for _, version := range []struct {
+		id       string
+		contents []byte
+	}{{"abc123", first}, {"def456", second}} {
+		resp := upload(t, ts, "myapp", version.id, nil, map[string][]byte{artifactPart: version.contents})
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("Expected upload to succeed, got %s", resp.Status)
+		}
+	}
+
+	registryURL, _ := url.Parse(ts.URL)
+	registry := artifact.NewRegistry(registryURL, uri.DefaultFetcher, fixedDetector{"CentOS", "7.2"})
+	stanza := launch.LaunchableStanza{Version: launch.LaunchableVersion{ID: "def456"}}
+
+	firstSum := sha256.Sum256(first)
+	_, _, d, err := registry.DeltaLocationDataForLaunchable("mypod", "myapp", stanza, []string{"unrelated"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d != nil {
+		t.Errorf("Expected no delta for a base the registry doesn't know, got %+v", d)
+	}
+
+	_, verificationData, d, err := registry.DeltaLocationDataForLaunchable("mypod", "myapp", stanza, []string{hex.EncodeToString(firstSum[:])})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d == nil {
+		t.Fatal("Expected the registry to offer a delta from the previous version")
+	}
+
+	deltaData, err := uri.DefaultFetcher.Open(d.Location)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer deltaData.Close()
+	var rebuilt bytes.Buffer
+	err = delta.Apply(bytes.NewReader(first), deltaData, &rebuilt)
+	if err != nil {
+		t.Fatalf("Could not apply delta: %s", err)
+	}
+	rebuiltSum := sha256.Sum256(rebuilt.Bytes())
+	if hex.EncodeToString(rebuiltSum[:]) != verificationData.ArtifactSHA256 {
+		t.Error("Rebuilt artifact did not match the uploaded version")
+	}
+}
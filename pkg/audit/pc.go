@@ -0,0 +1,51 @@
+package audit
+
+import (
+	"encoding/json"
+
+	"github.com/square/p2/pkg/pc/fields"
+	"github.com/square/p2/pkg/util"
+)
+
+const (
+	// PCCreatedEvent signifies that a pod cluster was created
+	PCCreatedEvent EventType = "POD_CLUSTER_CREATED"
+
+	// PCModifiedEvent signifies that a pod cluster's pod selector,
+	// annotations, allocation strategy, minimum health or labels were
+	// changed
+	PCModifiedEvent EventType = "POD_CLUSTER_MODIFIED"
+
+	// PCDeletedEvent signifies that a pod cluster was deleted
+	PCDeletedEvent EventType = "POD_CLUSTER_DELETED"
+)
+
+// PCEventDetails defines a JSON structure for the details related to a pod
+// cluster event. The schema is the same for every event type.
+type PCEventDetails struct {
+	// PodCluster is the pod cluster that resulted from the event, or in the
+	// case of deletions, the pod cluster BEFORE deletion
+	PodCluster fields.PodCluster `json:"pod_cluster"`
+
+	// Labels are the pod cluster's labels after the event
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// User represents the name of the user who executed the action to
+	// which the event record pertains
+	User string `json:"user"`
+}
+
+func NewPodClusterDetails(pc fields.PodCluster, pcLabels map[string]string, user string) (json.RawMessage, error) {
+	details := PCEventDetails{
+		PodCluster: pc,
+		Labels:     pcLabels,
+		User:       user,
+	}
+
+	bytes, err := json.Marshal(details)
+	if err != nil {
+		return nil, util.Errorf("could not marshal pod cluster event details as json: %s", err)
+	}
+
+	return json.RawMessage(bytes), nil
+}
@@ -0,0 +1,47 @@
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	klabels "k8s.io/kubernetes/pkg/labels"
+
+	pc_fields "github.com/square/p2/pkg/pc/fields"
+	rc_fields "github.com/square/p2/pkg/rc/fields"
+)
+
+func TestPodClusterDetails(t *testing.T) {
+	pc := pc_fields.PodCluster{
+		ID:                  "some_pc_id",
+		PodID:               "some_pod_id",
+		AvailabilityZone:    "some_availability_zone",
+		Name:                "some_cluster_name",
+		PodSelector:         klabels.Everything().Add("app", klabels.EqualsOperator, []string{"some_pod_id"}),
+		Annotations:         pc_fields.Annotations{"owner": "some_team"},
+		AllocationStrategy:  rc_fields.StaticStrategy,
+		MinHealthPercentage: 80,
+	}
+	pcLabels := map[string]string{"team": "some_team"}
+
+	detailsJSON, err := NewPodClusterDetails(pc, pcLabels, "some_user")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var details PCEventDetails
+	err = json.Unmarshal(detailsJSON, &details)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !details.PodCluster.Equals(&pc) || details.PodCluster.MinHealthPercentage != 80 {
+		t.Errorf("expected pod cluster to be %+v but was %+v", pc, details.PodCluster)
+	}
+	if !reflect.DeepEqual(details.Labels, pcLabels) {
+		t.Errorf("expected labels to be %s but were %s", pcLabels, details.Labels)
+	}
+	if details.User != "some_user" {
+		t.Errorf("expected user to be some_user but was %s", details.User)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 
 	pc_fields "github.com/square/p2/pkg/pc/fields"
+	rc_fields "github.com/square/p2/pkg/rc/fields"
 	"github.com/square/p2/pkg/types"
 	"github.com/square/p2/pkg/util"
 )
@@ -42,3 +43,90 @@ func NewRCRetargetingEventDetails(
 
 	return json.RawMessage(bytes), nil
 }
+
+const (
+	// RCAutoscaleEvent represents the autoscaler changing the desired
+	// replica count of an RC
+	RCAutoscaleEvent EventType = "REPLICATION_CONTROLLER_AUTOSCALE"
+)
+
+type RCAutoscaleDetails struct {
+	RCID             rc_fields.ID               `json:"rc_id"`
+	PodID            types.PodID                `json:"pod_id"`
+	AvailabilityZone pc_fields.AvailabilityZone `json:"availability_zone"`
+	ClusterName      pc_fields.ClusterName      `json:"cluster_name"`
+	OldReplicas      int                        `json:"old_replicas"`
+	NewReplicas      int                        `json:"new_replicas"`
+	MetricValue      float64                    `json:"metric_value"`
+	Target           float64                    `json:"target"`
+}
+
+func NewRCAutoscaleEventDetails(
+	rc rc_fields.RC,
+	newReplicas int,
+	metricValue float64,
+) (json.RawMessage, error) {
+	details := RCAutoscaleDetails{
+		RCID:             rc.ID,
+		PodID:            rc.Manifest.ID(),
+		AvailabilityZone: pc_fields.AvailabilityZone(rc.PodLabels[types.AvailabilityZoneLabel]),
+		ClusterName:      pc_fields.ClusterName(rc.PodLabels[types.ClusterNameLabel]),
+		OldReplicas:      rc.ReplicasDesired,
+		NewReplicas:      newReplicas,
+		MetricValue:      metricValue,
+	}
+	if rc.Autoscale != nil {
+		details.Target = rc.Autoscale.Target
+	}
+
+	bytes, err := json.Marshal(details)
+	if err != nil {
+		return nil, util.Errorf("could not marshal rc autoscale details as json: %s", err)
+	}
+
+	return json.RawMessage(bytes), nil
+}
+
+const (
+	// RCCreatedEvent signifies that a replication controller was created
+	RCCreatedEvent EventType = "REPLICATION_CONTROLLER_CREATED"
+
+	// RCModifiedEvent signifies a change to a replication controller that
+	// doesn't replace its pod manifest, such as a change to its desired
+	// replica count or its labels. Manifest changes are made by rolling
+	// updates, which have their own events.
+	RCModifiedEvent EventType = "REPLICATION_CONTROLLER_MODIFIED"
+
+	// RCDeletedEvent signifies that a replication controller was deleted
+	RCDeletedEvent EventType = "REPLICATION_CONTROLLER_DELETED"
+)
+
+// RCEventDetails defines a JSON structure for the details related to the
+// creation, modification or deletion of a replication controller
+type RCEventDetails struct {
+	// RC is the replication controller that resulted from the event, or in
+	// the case of deletions, the replication controller BEFORE deletion
+	RC rc_fields.RC `json:"replication_controller"`
+
+	// Labels are the replication controller's labels after the event
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// User represents the name of the user who executed the action to
+	// which the event record pertains
+	User string `json:"user"`
+}
+
+func NewRCDetails(rc rc_fields.RC, rcLabels map[string]string, user string) (json.RawMessage, error) {
+	details := RCEventDetails{
+		RC:     rc,
+		Labels: rcLabels,
+		User:   user,
+	}
+
+	bytes, err := json.Marshal(details)
+	if err != nil {
+		return nil, util.Errorf("could not marshal rc event details as json: %s", err)
+	}
+
+	return json.RawMessage(bytes), nil
+}
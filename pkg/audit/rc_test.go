@@ -5,7 +5,11 @@ import (
 	"reflect"
 	"testing"
 
+	klabels "k8s.io/kubernetes/pkg/labels"
+
+	"github.com/square/p2/pkg/manifest"
 	pc_fields "github.com/square/p2/pkg/pc/fields"
+	rc_fields "github.com/square/p2/pkg/rc/fields"
 	"github.com/square/p2/pkg/types"
 )
 
@@ -42,3 +46,82 @@ func TestRCRetargetingEventDetails(t *testing.T) {
 		t.Errorf("expected node list to be %s but was %s", nodes, details.Nodes)
 	}
 }
+
+func TestRCAutoscaleEventDetails(t *testing.T) {
+	builder := manifest.NewBuilder()
+	builder.SetID("some_pod_id")
+	rc := rc_fields.RC{
+		ID:       "some_rc_id",
+		Manifest: builder.GetManifest(),
+		PodLabels: klabels.Set{
+			types.AvailabilityZoneLabel: "some_availability_zone",
+			types.ClusterNameLabel:      "some_cluster_name",
+		},
+		ReplicasDesired: 3,
+		Autoscale: &rc_fields.AutoscalePolicy{
+			Target: 0.5,
+		},
+	}
+
+	detailsJSON, err := NewRCAutoscaleEventDetails(rc, 5, 0.8)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var details RCAutoscaleDetails
+	err = json.Unmarshal(detailsJSON, &details)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := RCAutoscaleDetails{
+		RCID:             "some_rc_id",
+		PodID:            "some_pod_id",
+		AvailabilityZone: "some_availability_zone",
+		ClusterName:      "some_cluster_name",
+		OldReplicas:      3,
+		NewReplicas:      5,
+		MetricValue:      0.8,
+		Target:           0.5,
+	}
+	if details != expected {
+		t.Errorf("expected details to be %+v but were %+v", expected, details)
+	}
+}
+
+func TestRCEventDetails(t *testing.T) {
+	builder := manifest.NewBuilder()
+	builder.SetID("some_pod_id")
+	rc := rc_fields.RC{
+		ID:              "some_rc_id",
+		Manifest:        builder.GetManifest(),
+		NodeSelector:    klabels.Everything().Add("zone", klabels.EqualsOperator, []string{"west"}),
+		PodLabels:       klabels.Set{types.ClusterNameLabel: "some_cluster_name"},
+		ReplicasDesired: 3,
+	}
+	rcLabels := map[string]string{"team": "some_team"}
+
+	detailsJSON, err := NewRCDetails(rc, rcLabels, "some_user")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var details RCEventDetails
+	err = json.Unmarshal(detailsJSON, &details)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if details.RC.ID != rc.ID || details.RC.ReplicasDesired != 3 || details.RC.Manifest.ID() != "some_pod_id" {
+		t.Errorf("expected rc to be %+v but was %+v", rc, details.RC)
+	}
+	if details.RC.NodeSelector.String() != rc.NodeSelector.String() {
+		t.Errorf("expected node selector to be %s but was %s", rc.NodeSelector, details.RC.NodeSelector)
+	}
+	if !reflect.DeepEqual(details.Labels, rcLabels) {
+		t.Errorf("expected labels to be %s but were %s", rcLabels, details.Labels)
+	}
+	if details.User != "some_user" {
+		t.Errorf("expected user to be some_user but was %s", details.User)
+	}
+}
@@ -14,6 +14,9 @@ import (
 const (
 	RUCreationEvent   EventType = "ROLLING_UPDATE_CREATION"
 	RUCompletionEvent EventType = "ROLLING_UPDATE_COMPLETION"
+	RUAbortEvent      EventType = "ROLLING_UPDATE_ABORT"
+	RUPauseEvent      EventType = "ROLLING_UPDATE_PAUSE"
+	RUResumeEvent     EventType = "ROLLING_UPDATE_RESUME"
 )
 
 type RUCreationDetails struct {
@@ -34,6 +37,34 @@ type RUCompletionDetails struct {
 	Canceled         bool                       `json:"canceled"`
 }
 
+type RUAbortDetails struct {
+	PodID            types.PodID                `json:"pod_id"`
+	AvailabilityZone pc_fields.AvailabilityZone `json:"availability_zone"`
+	ClusterName      pc_fields.ClusterName      `json:"cluster_name"`
+	RollingUpdateID  roll_fields.ID             `json:"rolling_update_id"`
+	Reason           string                     `json:"reason"`
+	// RolledBack is set when replicas were moved back to the old RC, or an
+	// update back to it was scheduled
+	RolledBack bool `json:"rolled_back"`
+}
+
+type RUPauseDetails struct {
+	PodID            types.PodID                `json:"pod_id"`
+	AvailabilityZone pc_fields.AvailabilityZone `json:"availability_zone"`
+	ClusterName      pc_fields.ClusterName      `json:"cluster_name"`
+	RollingUpdateID  roll_fields.ID             `json:"rolling_update_id"`
+	User             string                     `json:"user"`
+	Reason           string                     `json:"reason"`
+}
+
+type RUResumeDetails struct {
+	PodID            types.PodID                `json:"pod_id"`
+	AvailabilityZone pc_fields.AvailabilityZone `json:"availability_zone"`
+	ClusterName      pc_fields.ClusterName      `json:"cluster_name"`
+	RollingUpdateID  roll_fields.ID             `json:"rolling_update_id"`
+	User             string                     `json:"user"`
+}
+
 func NewRUCreationEventDetails(
 	podID types.PodID,
 	az pc_fields.AvailabilityZone,
@@ -96,3 +127,88 @@ func NewRUCompletionEventDetails(
 
 	return json.RawMessage(bytes), nil
 }
+
+func NewRUAbortEventDetails(
+	rollingUpdateID roll_fields.ID,
+	reason string,
+	rolledBack bool,
+	labeler Labeler,
+) (json.RawMessage, error) {
+	details := RUAbortDetails{
+		RollingUpdateID: rollingUpdateID,
+		Reason:          reason,
+		RolledBack:      rolledBack,
+	}
+
+	labels, err := labeler.GetLabels(labels.RU, rollingUpdateID.String())
+	if err != nil {
+		return nil, util.Errorf("could not determine pod cluster for RU %s: %s", rollingUpdateID, err)
+	}
+
+	details.PodID = types.PodID(labels.Labels[pc_fields.PodIDLabel])
+	details.AvailabilityZone = pc_fields.AvailabilityZone(labels.Labels[pc_fields.AvailabilityZoneLabel])
+	details.ClusterName = pc_fields.ClusterName(labels.Labels[pc_fields.ClusterNameLabel])
+
+	bytes, err := json.Marshal(details)
+	if err != nil {
+		return nil, util.Errorf("could not marshal ru abort details as json: %s", err)
+	}
+
+	return json.RawMessage(bytes), nil
+}
+
+func NewRUPauseEventDetails(
+	rollingUpdateID roll_fields.ID,
+	user string,
+	reason string,
+	labeler Labeler,
+) (json.RawMessage, error) {
+	details := RUPauseDetails{
+		RollingUpdateID: rollingUpdateID,
+		User:            user,
+		Reason:          reason,
+	}
+
+	labels, err := labeler.GetLabels(labels.RU, rollingUpdateID.String())
+	if err != nil {
+		return nil, util.Errorf("could not determine pod cluster for RU %s: %s", rollingUpdateID, err)
+	}
+
+	details.PodID = types.PodID(labels.Labels[pc_fields.PodIDLabel])
+	details.AvailabilityZone = pc_fields.AvailabilityZone(labels.Labels[pc_fields.AvailabilityZoneLabel])
+	details.ClusterName = pc_fields.ClusterName(labels.Labels[pc_fields.ClusterNameLabel])
+
+	bytes, err := json.Marshal(details)
+	if err != nil {
+		return nil, util.Errorf("could not marshal ru pause details as json: %s", err)
+	}
+
+	return json.RawMessage(bytes), nil
+}
+
+func NewRUResumeEventDetails(
+	rollingUpdateID roll_fields.ID,
+	user string,
+	labeler Labeler,
+) (json.RawMessage, error) {
+	details := RUResumeDetails{
+		RollingUpdateID: rollingUpdateID,
+		User:            user,
+	}
+
+	labels, err := labeler.GetLabels(labels.RU, rollingUpdateID.String())
+	if err != nil {
+		return nil, util.Errorf("could not determine pod cluster for RU %s: %s", rollingUpdateID, err)
+	}
+
+	details.PodID = types.PodID(labels.Labels[pc_fields.PodIDLabel])
+	details.AvailabilityZone = pc_fields.AvailabilityZone(labels.Labels[pc_fields.AvailabilityZoneLabel])
+	details.ClusterName = pc_fields.ClusterName(labels.Labels[pc_fields.ClusterNameLabel])
+
+	bytes, err := json.Marshal(details)
+	if err != nil {
+		return nil, util.Errorf("could not marshal ru resume details as json: %s", err)
+	}
+
+	return json.RawMessage(bytes), nil
+}
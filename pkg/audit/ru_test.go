@@ -78,3 +78,104 @@ func TestRUCompletionEventDetails(t *testing.T) {
 		t.Errorf("expected ru ID to be %s but was %s", ruID, details.RollingUpdateID)
 	}
 }
+
+func TestRUAbortEventDetails(t *testing.T) {
+	podID := types.PodID("some_pod_id")
+	labeler := fakeLabeler{
+		labelMap: map[string]labels.Labeled{
+			"some_ru": labels.Labeled{
+				Labels: map[string]string{
+					pc_fields.PodIDLabel: podID.String(),
+				},
+			},
+		},
+	}
+
+	ruID := roll_fields.ID("some_ru")
+	detailsJSON, err := NewRUAbortEventDetails(ruID, "some reason", true, labeler)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var details RUAbortDetails
+	err = json.Unmarshal(detailsJSON, &details)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if details.Reason != "some reason" {
+		t.Errorf("expected reason to be %q but was %q", "some reason", details.Reason)
+	}
+
+	if !details.RolledBack {
+		t.Error("rolled back bool didn't get set on details as expected")
+	}
+
+	if details.PodID != podID {
+		t.Errorf("expected pod id to be %s but was %s", podID, details.PodID)
+	}
+
+	if details.RollingUpdateID != ruID {
+		t.Errorf("expected ru ID to be %s but was %s", ruID, details.RollingUpdateID)
+	}
+}
+
+func TestRUPauseEventDetails(t *testing.T) {
+	podID := types.PodID("some_pod_id")
+	labeler := fakeLabeler{
+		labelMap: map[string]labels.Labeled{
+			"some_ru": labels.Labeled{
+				Labels: map[string]string{
+					pc_fields.PodIDLabel: podID.String(),
+				},
+			},
+		},
+	}
+
+	ruID := roll_fields.ID("some_ru")
+	detailsJSON, err := NewRUPauseEventDetails(ruID, "some_user", "some reason", labeler)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var details RUPauseDetails
+	err = json.Unmarshal(detailsJSON, &details)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if details.User != "some_user" {
+		t.Errorf("expected user to be %q but was %q", "some_user", details.User)
+	}
+
+	if details.Reason != "some reason" {
+		t.Errorf("expected reason to be %q but was %q", "some reason", details.Reason)
+	}
+
+	if details.PodID != podID {
+		t.Errorf("expected pod id to be %s but was %s", podID, details.PodID)
+	}
+
+	if details.RollingUpdateID != ruID {
+		t.Errorf("expected ru ID to be %s but was %s", ruID, details.RollingUpdateID)
+	}
+
+	detailsJSON, err = NewRUResumeEventDetails(ruID, "some_user", labeler)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var resumeDetails RUResumeDetails
+	err = json.Unmarshal(detailsJSON, &resumeDetails)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resumeDetails.User != "some_user" {
+		t.Errorf("expected user to be %q but was %q", "some_user", resumeDetails.User)
+	}
+
+	if resumeDetails.PodID != podID {
+		t.Errorf("expected pod id to be %s but was %s", podID, resumeDetails.PodID)
+	}
+}
@@ -32,6 +32,11 @@ type VerificationData struct {
 
 	// Used by BuildVerifier
 	BuildSignatureLocation *url.URL
+
+	// ArtifactSHA256 is the hex encoded digest the artifact registry expects
+	// the artifact to have. When set, it is checked while the artifact is
+	// downloaded, before any verifier runs.
+	ArtifactSHA256 string
 }
 
 // The artifact verifier is responsible for checking that the artifact
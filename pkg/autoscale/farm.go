@@ -0,0 +1,297 @@
+// Package autoscale adjusts the desired replica count of replication
+// controllers that carry an autoscale policy, based on a metric read from a
+// pluggable source.
+package autoscale
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/sirupsen/logrus"
+
+	"github.com/square/p2/pkg/audit"
+	"github.com/square/p2/pkg/logging"
+	"github.com/square/p2/pkg/rc/fields"
+	roll_fields "github.com/square/p2/pkg/roll/fields"
+	"github.com/square/p2/pkg/store/consul"
+	"github.com/square/p2/pkg/store/consul/consulutil"
+	"github.com/square/p2/pkg/store/consul/statusstore"
+	"github.com/square/p2/pkg/store/consul/statusstore/autoscalestatus"
+	"github.com/square/p2/pkg/store/consul/transaction"
+)
+
+// The longest a metric source may take to return a value
+const metricTimeout = 10 * time.Second
+
+type Store interface {
+	NewUnmanagedSession(session, name string) consul.Session
+}
+
+type ReplicationControllerStore interface {
+	List() ([]fields.RC, error)
+	CASDesiredReplicasTxn(ctx context.Context, id fields.ID, expected int, n int) error
+}
+
+type ReplicationControllerLocker interface {
+	LockForMutation(rcID fields.ID, session consul.Session) (consul.Unlocker, error)
+}
+
+type RollingUpdateStore interface {
+	List() ([]roll_fields.Update, error)
+}
+
+type StatusStore interface {
+	Get(rcID fields.ID) (autoscalestatus.Status, *api.QueryMeta, error)
+	SetTxn(ctx context.Context, rcID fields.ID, status autoscalestatus.Status) error
+}
+
+type AuditLogStore interface {
+	Create(
+		ctx context.Context,
+		eventType audit.EventType,
+		eventDetails json.RawMessage,
+	) error
+}
+
+// The Farm periodically evaluates every enabled RC that has an autoscale
+// policy and adjusts its desired replica count.
+//
+// RCs taking part in a rolling update are left alone until the update is
+// over, because the update decides the replica counts of both of its RCs. The
+// farm also holds the RC's mutation lock while changing the replica count,
+// which rolling updates hold for their whole duration. Multiple farms can run
+// at once, the same lock keeps them from scaling an RC at the same time and
+// the cooldown is recorded in the status store so that they honor each
+// other's changes.
+type Farm struct {
+	store         Store
+	rcStore       ReplicationControllerStore
+	rcLocker      ReplicationControllerLocker
+	rollStore     RollingUpdateStore
+	statusStore   StatusStore
+	auditLogStore AuditLogStore
+	txner         transaction.Txner
+	metricSources MetricSourceFactory
+
+	sessions <-chan string
+	session  consul.Session
+
+	logger logging.Logger
+
+	// How often every RC is evaluated
+	interval time.Duration
+}
+
+func NewFarm(
+	store Store,
+	rcStore ReplicationControllerStore,
+	rcLocker ReplicationControllerLocker,
+	rollStore RollingUpdateStore,
+	statusStore StatusStore,
+	auditLogStore AuditLogStore,
+	txner transaction.Txner,
+	metricSources MetricSourceFactory,
+	sessions <-chan string,
+	logger logging.Logger,
+	interval time.Duration,
+) *Farm {
+	return &Farm{
+		store:         store,
+		rcStore:       rcStore,
+		rcLocker:      rcLocker,
+		rollStore:     rollStore,
+		statusStore:   statusStore,
+		auditLogStore: auditLogStore,
+		txner:         txner,
+		metricSources: metricSources,
+		sessions:      sessions,
+		logger:        logger,
+		interval:      interval,
+	}
+}
+
+// Start is a blocking function that evaluates autoscale policies until the
+// quit channel is closed.
+func (f *Farm) Start(quit <-chan struct{}) {
+	consulutil.WithSession(quit, f.sessions, func(sessionQuit <-chan struct{}, session string) {
+		f.logger.WithField("session", session).Infoln("Acquired new session")
+		f.session = f.store.NewUnmanagedSession(session, "")
+		f.mainLoop(sessionQuit)
+	})
+}
+
+func (f *Farm) mainLoop(quit <-chan struct{}) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-quit:
+			f.logger.NoFields().Infoln("Session expired, pausing autoscaling")
+			f.session = nil
+			return
+		case <-timer.C:
+			f.evaluateAll()
+			timer.Reset(f.interval)
+		}
+	}
+}
+
+func (f *Farm) evaluateAll() {
+	rcs, err := f.rcStore.List()
+	if err != nil {
+		f.logger.WithError(err).Errorln("Could not list replication controllers")
+		return
+	}
+
+	rolls, err := f.rollStore.List()
+	if err != nil {
+		f.logger.WithError(err).Errorln("Could not list rolling updates")
+		return
+	}
+	rolling := make(map[fields.ID]struct{})
+	for _, roll := range rolls {
+		rolling[roll.OldRC] = struct{}{}
+		rolling[roll.NewRC] = struct{}{}
+	}
+
+	for _, rcFields := range rcs {
+		if rcFields.Autoscale == nil || rcFields.Disabled {
+			continue
+		}
+
+		rcLogger := f.logger.SubLogger(logrus.Fields{
+			"rc":  rcFields.ID,
+			"pod": rcFields.Manifest.ID(),
+		})
+		if _, ok := rolling[rcFields.ID]; ok {
+			rcLogger.NoFields().Debugln("Not autoscaling RC during a rolling update")
+			continue
+		}
+
+		f.evaluate(rcFields, rcLogger)
+	}
+}
+
+// evaluate reads the metric of a single RC and changes its replica count if
+// the policy calls for it
+func (f *Farm) evaluate(rcFields fields.RC, logger logging.Logger) {
+	policy := *rcFields.Autoscale
+
+	status, _, err := f.statusStore.Get(rcFields.ID)
+	if err != nil && !statusstore.IsNoStatus(err) {
+		logger.WithError(err).Errorln("Could not read autoscale status")
+		return
+	}
+
+	now := time.Now()
+	status.LastEvaluationTime = now
+
+	value, err := f.readMetric(rcFields, policy)
+	if err != nil {
+		logger.WithError(err).Errorln("Could not read autoscale metric")
+		status.LastError = err.Error()
+		f.writeStatus(rcFields.ID, status, logger)
+		return
+	}
+	status.LastError = ""
+	status.LastMetricValue = value
+
+	desired := DesiredReplicas(rcFields.ReplicasDesired, value, policy)
+	status.RecommendedReplicas = desired
+	if desired == rcFields.ReplicasDesired {
+		f.writeStatus(rcFields.ID, status, logger)
+		return
+	}
+	if status.LastScaleTime != nil && now.Sub(*status.LastScaleTime) < policy.Cooldown {
+		logger.WithField("desired", desired).Debugln("Not autoscaling RC during its cooldown")
+		f.writeStatus(rcFields.ID, status, logger)
+		return
+	}
+
+	unlocker, err := f.rcLocker.LockForMutation(rcFields.ID, f.session)
+	if _, ok := err.(consul.AlreadyLockedError); ok {
+		logger.NoFields().Infoln("RC is locked for mutation, most likely by a rolling update. Not autoscaling it")
+		return
+	} else if err != nil {
+		logger.WithError(err).Errorln("Could not lock RC for mutation")
+		return
+	}
+	defer func() {
+		err := unlocker.Unlock()
+		if err != nil {
+			logger.WithError(err).Errorln("Could not release RC mutation lock")
+		}
+	}()
+
+	ctx, cancel := transaction.New(context.Background())
+	defer cancel()
+
+	err = f.rcStore.CASDesiredReplicasTxn(ctx, rcFields.ID, rcFields.ReplicasDesired, desired)
+	if err != nil {
+		logger.WithError(err).Errorln("Could not build replica count transaction")
+		return
+	}
+
+	details, err := audit.NewRCAutoscaleEventDetails(rcFields, desired, value)
+	if err != nil {
+		logger.WithError(err).Errorln("Could not build autoscale audit log record")
+		return
+	}
+	err = f.auditLogStore.Create(ctx, audit.RCAutoscaleEvent, details)
+	if err != nil {
+		logger.WithError(err).Errorln("Could not add autoscale audit log record to transaction")
+		return
+	}
+
+	status.LastScaleTime = &now
+	err = f.statusStore.SetTxn(ctx, rcFields.ID, status)
+	if err != nil {
+		logger.WithError(err).Errorln("Could not add autoscale status to transaction")
+		return
+	}
+
+	err = transaction.MustCommit(ctx, f.txner)
+	if err != nil {
+		// most likely the RC changed since it was listed, it will be
+		// evaluated again next time
+		logger.WithError(err).Errorln("Could not change replica count")
+		return
+	}
+
+	logger.WithFields(logrus.Fields{
+		"old_replicas": rcFields.ReplicasDesired,
+		"new_replicas": desired,
+		"metric":       value,
+		"target":       policy.Target,
+	}).Infoln("Autoscaled replication controller")
+}
+
+func (f *Farm) readMetric(rcFields fields.RC, policy fields.AutoscalePolicy) (float64, error) {
+	source, err := f.metricSources(policy.Metric)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), metricTimeout)
+	defer cancel()
+	return source.Value(ctx, rcFields)
+}
+
+func (f *Farm) writeStatus(rcID fields.ID, status autoscalestatus.Status, logger logging.Logger) {
+	ctx, cancel := transaction.New(context.Background())
+	defer cancel()
+
+	err := f.statusStore.SetTxn(ctx, rcID, status)
+	if err != nil {
+		logger.WithError(err).Errorln("Could not add autoscale status to transaction")
+		return
+	}
+
+	err = transaction.MustCommit(ctx, f.txner)
+	if err != nil {
+		logger.WithError(err).Errorln("Could not write autoscale status")
+	}
+}
@@ -0,0 +1,221 @@
+// +build !race
+
+package autoscale
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	klabels "k8s.io/kubernetes/pkg/labels"
+
+	"github.com/square/p2/pkg/audit"
+	"github.com/square/p2/pkg/labels"
+	"github.com/square/p2/pkg/logging"
+	"github.com/square/p2/pkg/manifest"
+	"github.com/square/p2/pkg/rc/fields"
+	roll_fields "github.com/square/p2/pkg/roll/fields"
+	"github.com/square/p2/pkg/store/consul"
+	"github.com/square/p2/pkg/store/consul/auditlogstore"
+	"github.com/square/p2/pkg/store/consul/consulutil"
+	"github.com/square/p2/pkg/store/consul/rcstore"
+	"github.com/square/p2/pkg/store/consul/statusstore"
+	"github.com/square/p2/pkg/store/consul/statusstore/autoscalestatus"
+)
+
+type fixedSource float64
+
+func (s fixedSource) Value(ctx context.Context, rcFields fields.RC) (float64, error) {
+	return float64(s), nil
+}
+
+type fakeRollStore []roll_fields.Update
+
+func (s fakeRollStore) List() ([]roll_fields.Update, error) {
+	return s, nil
+}
+
+type sessionStore interface {
+	Store
+	NewSession(name string, renewalCh <-chan time.Time) (consul.Session, chan error, error)
+}
+
+type farmFixture struct {
+	fixture       consulutil.Fixture
+	farm          *Farm
+	rcStore       *rcstore.ConsulStore
+	consulStore   sessionStore
+	statusStore   autoscalestatus.ConsulStore
+	auditLogStore auditlogstore.ConsulStore
+	rcID          fields.ID
+	metric        *fixedSource
+}
+
+func newFarmFixture(t *testing.T, rolls fakeRollStore) farmFixture {
+	fixture := consulutil.NewFixture(t)
+
+	applicator := labels.NewConsulApplicator(fixture.Client, 0, 0)
+	rcStore := rcstore.NewConsul(fixture.Client, applicator, 0)
+	consulStore := consul.NewConsulStore(fixture.Client)
+	statusStore := autoscalestatus.NewConsul(statusstore.NewConsul(fixture.Client), consul.AutoscaleStatusNamespace)
+	auditLogStore := auditlogstore.NewConsulStore(fixture.Client.KV())
+
+	builder := manifest.NewBuilder()
+	builder.SetID("some_pod")
+	rc, err := rcStore.Create(builder.GetManifest(), klabels.Everything(), "some_az", "some_cn", nil, nil, "some_strategy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = rcStore.SetDesiredReplicas(rc.ID, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = rcStore.UpdateAutoscalePolicy(rc.ID, &fields.AutoscalePolicy{
+		MinReplicas: 1,
+		MaxReplicas: 10,
+		Target:      100,
+		Cooldown:    time.Hour,
+		Metric:      fields.MetricSource{Type: fields.HTTPMetricSource, URL: "http://metrics.example.com/some_pod"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	metric := new(fixedSource)
+	sources := func(source fields.MetricSource) (MetricSource, error) {
+		return metric, nil
+	}
+	farm := NewFarm(
+		consulStore,
+		rcStore,
+		rcStore,
+		rolls,
+		statusStore,
+		auditLogStore,
+		fixture.Client.KV(),
+		sources,
+		nil,
+		logging.TestLogger(),
+		time.Minute,
+	)
+
+	session, _, err := consulStore.NewSession("autoscale_test", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	farm.session = session
+
+	return farmFixture{
+		fixture:       fixture,
+		farm:          farm,
+		rcStore:       rcStore,
+		consulStore:   consulStore,
+		statusStore:   statusStore,
+		auditLogStore: auditLogStore,
+		rcID:          rc.ID,
+		metric:        metric,
+	}
+}
+
+func (f farmFixture) replicas(t *testing.T) int {
+	rc, err := f.rcStore.Get(f.rcID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rc.ReplicasDesired
+}
+
+func (f farmFixture) autoscaleEvents(t *testing.T) int {
+	records, err := f.auditLogStore.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := 0
+	for _, record := range records {
+		if record.EventType == audit.RCAutoscaleEvent {
+			count++
+		}
+	}
+	return count
+}
+
+func TestEvaluateScalesAndHonorsCooldown(t *testing.T) {
+	f := newFarmFixture(t, nil)
+	defer f.fixture.Stop()
+
+	*f.metric = 150
+	f.farm.evaluateAll()
+
+	if replicas := f.replicas(t); replicas != 6 {
+		t.Fatalf("expected the RC to be scaled to 6 replicas, it has %d", replicas)
+	}
+	if events := f.autoscaleEvents(t); events != 1 {
+		t.Errorf("expected 1 autoscale audit log record, found %d", events)
+	}
+	status, _, err := f.statusStore.Get(f.rcID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.LastScaleTime == nil {
+		t.Error("expected the scale time to be recorded")
+	}
+	if status.LastMetricValue != 150 {
+		t.Errorf("expected the metric value to be recorded as 150, was %v", status.LastMetricValue)
+	}
+
+	*f.metric = 300
+	f.farm.evaluateAll()
+
+	if replicas := f.replicas(t); replicas != 6 {
+		t.Errorf("expected the RC not to be scaled during its cooldown, it has %d replicas", replicas)
+	}
+	status, _, err = f.statusStore.Get(f.rcID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.RecommendedReplicas != 10 {
+		t.Errorf("expected 10 recommended replicas to be recorded, was %d", status.RecommendedReplicas)
+	}
+}
+
+func TestEvaluateSkipsRCsInRollingUpdates(t *testing.T) {
+	f := newFarmFixture(t, fakeRollStore{{OldRC: "some_other_rc"}})
+	defer f.fixture.Stop()
+	f.farm.rollStore = fakeRollStore{{OldRC: "some_other_rc", NewRC: f.rcID}}
+
+	*f.metric = 150
+	f.farm.evaluateAll()
+
+	if replicas := f.replicas(t); replicas != 4 {
+		t.Errorf("expected the RC not to be scaled during a rolling update, it has %d replicas", replicas)
+	}
+}
+
+func TestEvaluateSkipsMutationLockedRCs(t *testing.T) {
+	f := newFarmFixture(t, nil)
+	defer f.fixture.Stop()
+
+	otherSession, _, err := f.consulStore.NewSession("roll_farm", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unlocker, err := f.rcStore.LockForMutation(f.rcID, otherSession)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	*f.metric = 150
+	f.farm.evaluateAll()
+	if replicas := f.replicas(t); replicas != 4 {
+		t.Errorf("expected the RC not to be scaled while locked for mutation, it has %d replicas", replicas)
+	}
+
+	err = unlocker.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.farm.evaluateAll()
+	if replicas := f.replicas(t); replicas != 6 {
+		t.Errorf("expected the RC to be scaled once unlocked, it has %d replicas", replicas)
+	}
+}
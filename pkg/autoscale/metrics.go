@@ -0,0 +1,150 @@
+package autoscale
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/square/p2/pkg/rc"
+	"github.com/square/p2/pkg/rc/fields"
+	"github.com/square/p2/pkg/types"
+	"github.com/square/p2/pkg/util"
+)
+
+// MetricSource reads the current per-replica value of the metric an RC is
+// scaled on.
+type MetricSource interface {
+	Value(ctx context.Context, rcFields fields.RC) (float64, error)
+}
+
+// MetricSourceFactory builds the MetricSource described by an autoscale
+// policy. Implementations other than NewMetricSource can be used to plug in
+// further kinds of source.
+type MetricSourceFactory func(source fields.MetricSource) (MetricSource, error)
+
+// metricDocument is the JSON served by metric endpoints
+type metricDocument struct {
+	Value *float64 `json:"value"`
+}
+
+// NewMetricSourceFactory returns a MetricSourceFactory for the source types
+// in pkg/rc/fields, whose requests are made using client and which finds the
+// pods of an RC using labeler.
+func NewMetricSourceFactory(client *http.Client, labeler rc.LabelMatcher) MetricSourceFactory {
+	return func(source fields.MetricSource) (MetricSource, error) {
+		switch source.Type {
+		case fields.HTTPMetricSource:
+			return HTTPSource{
+				client: client,
+				url:    source.URL,
+			}, nil
+		case fields.PodMetricSource:
+			return PodSource{
+				client:  client,
+				labeler: labeler,
+				path:    source.Path,
+				port:    source.Port,
+			}, nil
+		}
+		return nil, util.Errorf("unknown metric source type %q", source.Type)
+	}
+}
+
+// HTTPSource reads the metric from a single JSON endpoint.
+type HTTPSource struct {
+	client *http.Client
+	url    string
+}
+
+func (s HTTPSource) Value(ctx context.Context, rcFields fields.RC) (float64, error) {
+	return fetchMetric(ctx, s.client, s.url)
+}
+
+// PodSource reads the metric from every pod of the RC, on the node that it's
+// running on, and returns the average. Pods that can't be reached are left out
+// of the average; it's an error if none can be reached.
+type PodSource struct {
+	client  *http.Client
+	labeler rc.LabelMatcher
+	path    string
+	port    int
+}
+
+func (s PodSource) Value(ctx context.Context, rcFields fields.RC) (float64, error) {
+	pods, err := rc.CurrentPods(rcFields.ID, s.labeler)
+	if err != nil {
+		return 0, err
+	}
+	if len(pods) == 0 {
+		return 0, util.Errorf("RC %s has no pods to read a metric from", rcFields.ID)
+	}
+
+	port := s.port
+	if port == 0 {
+		port = rcFields.Manifest.GetStatusPort()
+	}
+	if port == 0 {
+		return 0, util.Errorf("pod %s has no status port and the metric source has no port", rcFields.Manifest.ID())
+	}
+	// like health checks, the status port speaks HTTPS unless the
+	// manifest says otherwise
+	scheme := "https"
+	if rcFields.Manifest.GetStatusHTTP() {
+		scheme = "http"
+	}
+
+	var sum float64
+	var count int
+	var lastErr error
+	for _, pod := range pods {
+		value, err := fetchMetric(ctx, s.client, podMetricURL(scheme, pod.Node, port, s.path))
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		sum += value
+		count++
+	}
+	if count == 0 {
+		return 0, util.Errorf("could not read metric from any of %d pods: %s", len(pods), lastErr)
+	}
+
+	return sum / float64(count), nil
+}
+
+func podMetricURL(scheme string, node types.NodeName, port int, path string) string {
+	return fmt.Sprintf("%s://%s:%d%s", scheme, node, port, path)
+}
+
+func fetchMetric(ctx context.Context, client *http.Client, url string) (float64, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return 0, util.Errorf("could not build metric request for %s: %s", url, err)
+	}
+	req = req.WithContext(ctx)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, util.Errorf("could not fetch metric from %s: %s", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		return 0, util.Errorf("metric endpoint %s returned status %d", url, resp.StatusCode)
+	}
+
+	var doc metricDocument
+	err = json.NewDecoder(resp.Body).Decode(&doc)
+	if err != nil {
+		return 0, util.Errorf("could not decode metric from %s: %s", url, err)
+	}
+	if doc.Value == nil {
+		return 0, util.Errorf("metric from %s has no value", url)
+	}
+
+	return *doc.Value, nil
+}
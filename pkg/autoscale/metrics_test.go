@@ -0,0 +1,121 @@
+package autoscale
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/square/p2/pkg/labels"
+	"github.com/square/p2/pkg/manifest"
+	"github.com/square/p2/pkg/rc"
+	"github.com/square/p2/pkg/rc/fields"
+)
+
+func TestHTTPSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/metric":
+			_, _ = w.Write([]byte(`{"value": 12.5}`))
+		case "/empty":
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	factory := NewMetricSourceFactory(server.Client(), nil)
+
+	source, err := factory(fields.MetricSource{Type: fields.HTTPMetricSource, URL: server.URL + "/metric"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, err := source.Value(context.Background(), fields.RC{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != 12.5 {
+		t.Errorf("expected metric value 12.5, got %v", value)
+	}
+
+	for _, path := range []string{"/empty", "/missing"} {
+		source, err := factory(fields.MetricSource{Type: fields.HTTPMetricSource, URL: server.URL + path})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = source.Value(context.Background(), fields.RC{})
+		if err == nil {
+			t.Errorf("expected an error reading metric from %s", path)
+		}
+	}
+}
+
+func TestUnknownMetricSource(t *testing.T) {
+	factory := NewMetricSourceFactory(http.DefaultClient, nil)
+	_, err := factory(fields.MetricSource{Type: "carrier_pigeon"})
+	if err == nil {
+		t.Error("expected an error building an unknown metric source")
+	}
+}
+
+func TestPodSourceAveragesReachablePods(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/load" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"value": 30}`))
+	}))
+	defer server.Close()
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	port, err := strconv.Atoi(serverURL.Port())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rcID := fields.ID("some_rc_id")
+	applicator := labels.NewFakeApplicator()
+	// the second pod is on a node that can't be reached
+	for _, podLabelID := range []string{"127.0.0.1/some_pod", "unreachable.invalid/some_pod"} {
+		err = applicator.SetLabel(labels.POD, podLabelID, rc.RCIDLabel, rcID.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	builder := manifest.NewBuilder()
+	builder.SetID("some_pod")
+	builder.SetStatusHTTP(true)
+	rcFields := fields.RC{
+		ID:       rcID,
+		Manifest: builder.GetManifest(),
+	}
+
+	factory := NewMetricSourceFactory(server.Client(), applicator)
+	source, err := factory(fields.MetricSource{Type: fields.PodMetricSource, Path: "/load", Port: port})
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, err := source.Value(context.Background(), rcFields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != 30 {
+		t.Errorf("expected metric value 30, got %v", value)
+	}
+
+	source, err = factory(fields.MetricSource{Type: fields.PodMetricSource, Path: "/missing", Port: port})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = source.Value(context.Background(), rcFields)
+	if err == nil {
+		t.Error("expected an error when no pod returns a metric")
+	}
+}
@@ -0,0 +1,31 @@
+package autoscale
+
+import (
+	"math"
+
+	"github.com/square/p2/pkg/rc/fields"
+)
+
+// Tolerance is how far the ratio of the metric to its target may stray from 1
+// before the replica count is changed. It keeps a metric that hovers around
+// the target from causing constant small adjustments.
+const Tolerance = 0.1
+
+// DesiredReplicas returns the replica count that would bring the per-replica
+// metric value to the policy's target, assuming the load is spread evenly
+// across replicas, clamped to the policy's bounds.
+func DesiredReplicas(current int, metricValue float64, policy fields.AutoscalePolicy) int {
+	desired := current
+	ratio := metricValue / policy.Target
+	if current > 0 && math.Abs(ratio-1) > Tolerance {
+		desired = int(math.Ceil(float64(current) * ratio))
+	}
+
+	if desired < policy.MinReplicas {
+		desired = policy.MinReplicas
+	}
+	if desired > policy.MaxReplicas {
+		desired = policy.MaxReplicas
+	}
+	return desired
+}
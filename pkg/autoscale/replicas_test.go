@@ -0,0 +1,39 @@
+package autoscale
+
+import (
+	"testing"
+
+	"github.com/square/p2/pkg/rc/fields"
+)
+
+func TestDesiredReplicas(t *testing.T) {
+	policy := fields.AutoscalePolicy{
+		MinReplicas: 2,
+		MaxReplicas: 10,
+		Target:      100,
+	}
+
+	for _, testCase := range []struct {
+		name     string
+		current  int
+		value    float64
+		expected int
+	}{
+		{name: "at target", current: 4, value: 100, expected: 4},
+		{name: "within tolerance above", current: 4, value: 109, expected: 4},
+		{name: "within tolerance below", current: 4, value: 91, expected: 4},
+		{name: "scale up", current: 4, value: 150, expected: 6},
+		{name: "scale up rounds up", current: 4, value: 130, expected: 6},
+		{name: "scale down", current: 6, value: 50, expected: 3},
+		{name: "clamped to max", current: 8, value: 500, expected: 10},
+		{name: "clamped to min", current: 4, value: 1, expected: 2},
+		{name: "no replicas", current: 0, value: 0, expected: 2},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			desired := DesiredReplicas(testCase.current, testCase.value, policy)
+			if desired != testCase.expected {
+				t.Errorf("expected %d replicas for %d replicas at %v, got %d", testCase.expected, testCase.current, testCase.value, desired)
+			}
+		})
+	}
+}
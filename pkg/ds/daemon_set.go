@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"os"
 	"os/user"
+	"reflect"
 	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
+	"github.com/square/p2/pkg/alerting"
 	"github.com/square/p2/pkg/ds/fields"
 	"github.com/square/p2/pkg/health"
 	"github.com/square/p2/pkg/health/checker"
@@ -104,6 +106,7 @@ type LabelWatcher interface {
 type store interface {
 	DeletePodTxn(ctx context.Context, podPrefix consul.PodPrefix, nodename types.NodeName, podID types.PodID) error
 	NewUnmanagedSession(session, name string) consul.Session
+	Pods(podPrefix consul.PodPrefix, locations types.PodLocations) (map[types.PodLocation]manifest.Manifest, error)
 
 	// For passing to the replication package:
 	replication.Store
@@ -164,6 +167,23 @@ type daemonSet struct {
 	labelsAggregationRate time.Duration
 
 	retryInterval time.Duration
+
+	// progress through the batches of a rolling strategy, see
+	// addNodesInBatches()
+	rollingStatus   daemonsetstatus.RollingStatus
+	rollingStatusMu sync.Mutex
+
+	// whether the daemon set halted because too many nodes failed, see
+	// checkFailureBudget()
+	failureBudget   failureBudget
+	failureBudgetMu sync.Mutex
+
+	// the expensive parts of the daemon set's status, see
+	// refreshStatusDetails()
+	statusDetails   statusDetails
+	statusDetailsMu sync.Mutex
+
+	alerter alerting.Alerter
 }
 
 type dsReplication struct {
@@ -188,6 +208,7 @@ func New(
 	unlocker consul.TxnUnlocker,
 	statusStore StatusStore,
 	statusWritingInterval time.Duration,
+	alerter alerting.Alerter,
 ) DaemonSet {
 
 	if retryInterval == 0 {
@@ -214,6 +235,7 @@ func New(
 		unlocker:              unlocker,
 		statusWritingInterval: statusWritingInterval,
 		statusStore:           statusStore,
+		alerter:               alerter,
 	}
 }
 
@@ -297,6 +319,8 @@ func (ds *daemonSet) WatchDesires(
 		ds.publishStatus(ctx)
 	}()
 
+	go ds.watchFailureBudget(ctx)
+
 	// buffer this channel so we don't block the WatchDesires() loop on
 	// replications being slow. In steady state we expect the rate of nodes
 	// being added to a daemon set to be much smaller than the speed at
@@ -406,11 +430,30 @@ func (ds *daemonSet) WatchDesires(
 				}
 
 				ds.mu.Lock()
-				if ds.Timeout != newDS.Timeout {
-					timeoutChange <- newDS.Timeout
+				oldDS := ds.DaemonSet
+				ds.mu.Unlock()
+				var changedOverrides bool
+				changedOverrides, err = overridesChanged(oldDS, newDS)
+				if err != nil {
+					err = util.Errorf("could not compare manifest overrides: %s", err)
+					continue
+				}
+				if changedOverrides {
+					// the nodes with an override need to be
+					// redeployed just like for a new manifest
+					ds.logger.Infoln("manifest overrides changed")
+					manifestChanged = true
 				}
+
+				ds.mu.Lock()
+				timeoutChanged := ds.Timeout != newDS.Timeout
 				ds.DaemonSet = newDS
 				ds.mu.Unlock()
+				// sent without holding the lock, since Replicate may
+				// need it to size a batch of a rolling strategy
+				if timeoutChanged {
+					timeoutChange <- newDS.Timeout
+				}
 
 				if reportErr := ds.reportEligible(); reportErr != nil {
 					// An error in sending the metrics shouldn't stop us from doing updates.
@@ -428,6 +471,7 @@ func (ds *daemonSet) WatchDesires(
 				if paused || manifestChanged {
 					if paused {
 						ds.logger.Infoln("daemon set enabled, unpausing replication")
+						ds.resetFailureBudget()
 					}
 
 					unpauseReplication <- struct{}{}
@@ -676,12 +720,22 @@ func (ds *daemonSet) Replicate(
 			thisUser = &user.User{}
 		}
 
+		// always use max parallelism for daemon set deploys, a rolling
+		// strategy limits how many nodes are updated at once itself
+		parallelism := 50
+		if strategy := ds.RollingStrategy(); strategy != nil {
+			maxUnavailable, maxSurge, err := strategy.Resolve(len(nodes))
+			if err == nil && maxUnavailable+maxSurge > parallelism {
+				parallelism = maxUnavailable + maxSurge
+			}
+		}
+
 		lockMessage := fmt.Sprintf("%q from %q at %q", thisUser.Username, thisHost, time.Now())
 		repl, err := replication.NewReplicator(
 			ds.Manifest(),
 			ds.logger,
 			nodes,
-			50, // always use max parallelism for daemon set deploys
+			parallelism,
 			ds.store,
 			ds.txner,
 			ds.applicator,
@@ -727,6 +781,8 @@ func (ds *daemonSet) Replicate(
 
 		ds.logger.Info("Replication initialized")
 
+		replication.SetManifestForNode(ds.manifestForNode)
+
 		// auto-drain this channel
 		go func() {
 			for err := range errCh {
@@ -749,12 +805,29 @@ func (ds *daemonSet) Replicate(
 	ds.logger.Info("Replication enacted")
 
 	paused := false
+	signals := replicationSignals{
+		pause:          pauseReplication,
+		unpause:        unpauseReplication,
+		manifestChange: manifestChange,
+		timeoutChange:  timeoutChange,
+	}
 	addMoreNodes := func(moreNodes []types.NodeName) {
-		if paused {
+		if paused || ds.halted() {
+			return
+		}
+
+		if ds.RollingStrategy() != nil {
+			paused = ds.addNodesInBatches(ctx, moreNodes, nodeQueue, signals)
 			return
 		}
 
 		for _, node := range moreNodes {
+			if ds.halted() {
+				// throw away the rest of the nodes, the daemon set
+				// is about to be disabled
+				return
+			}
+
 			// prioritize pauses and manifest changes
 			select {
 			case <-pauseReplication:
@@ -875,6 +948,12 @@ func (ds *daemonSet) publishStatus(ctx context.Context) {
 			}
 		}
 
+		err := ds.refreshStatusDetails()
+		if err != nil {
+			// the rest of the status is still worth writing
+			ds.logger.WithError(err).Errorln("could not compute detailed daemon set status")
+		}
+
 		written, err := ds.writeNewestStatus(ctx, lastStatus)
 		if err != nil {
 			ds.logger.WithError(err).Errorln("could not write daemon set status")
@@ -916,7 +995,16 @@ func (ds *daemonSet) writeNewestStatus(ctx context.Context, lastStatus daemonset
 		}
 	}
 
-	if toWrite == lastStatus {
+	toWrite.Rolling = ds.getRollingStatus()
+
+	toWrite.FailureBudgetExceeded = ds.halted()
+	toWrite.FailedNodes = ds.failedNodes()
+
+	details := ds.getStatusDetails()
+	toWrite.Nodes = details.nodes
+	toWrite.ContendsWith = details.contendsWith
+
+	if reflect.DeepEqual(toWrite, lastStatus) {
 		// nothing to do
 		return lastStatus, nil
 	}
@@ -5,6 +5,7 @@ package ds
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"testing"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/square/p2/pkg/store/consul"
 	"github.com/square/p2/pkg/util"
 
+	"github.com/square/p2/pkg/alerting"
 	"github.com/square/p2/pkg/labels"
 	"github.com/square/p2/pkg/logging"
 	"github.com/square/p2/pkg/manifest"
@@ -228,6 +230,7 @@ func TestSchedule(t *testing.T) {
 		nullUnlocker{},
 		statusStore,
 		DefaultStatusWritingInterval,
+		alerting.NewNop(),
 	).(*daemonSet)
 
 	labeled := labeledPods(t, ds)
@@ -493,6 +496,7 @@ func TestPublishToReplication(t *testing.T) {
 		nullUnlocker{},
 		statusStore,
 		DefaultStatusWritingInterval,
+		alerting.NewNop(),
 	).(*daemonSet)
 
 	labeled := labeledPods(t, ds)
@@ -613,6 +617,17 @@ func (n nullReplication) CompletedCount() int32 {
 	return n.completedCount
 }
 
+func (n nullReplication) FinishedCount() int32 {
+	return n.completedCount
+}
+
+func (n nullReplication) TimedOutNodes() []types.NodeName {
+	return nil
+}
+
+func (n nullReplication) ForgetTimedOutNodes() {
+}
+
 func (n nullReplication) InProgress() bool {
 	return n.inProgress
 }
@@ -620,6 +635,10 @@ func (n nullReplication) InProgress() bool {
 func (n nullReplication) SetManifest(manifest.Manifest) {
 	panic("SetManifest() not implemented on nullReplication")
 }
+func (n nullReplication) SetManifestForNode(replication.ManifestForNode) {
+	panic("SetManifestForNode() not implemented on nullReplication")
+}
+
 func (n nullReplication) SetTimeout(time.Duration) {
 	panic("SetTimeout() not implemented on nullReplication")
 }
@@ -769,7 +788,7 @@ func TestWriteNewestStatus(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		if newStatus != testCase.expectedStatus {
+		if !reflect.DeepEqual(newStatus, testCase.expectedStatus) {
 			t.Errorf("test case %d: expected %+v got %+v", i, testCase.expectedStatus, newStatus)
 		}
 	}
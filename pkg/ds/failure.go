@@ -0,0 +1,175 @@
+package ds
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/square/p2/pkg/alerting"
+	"github.com/square/p2/pkg/ds/fields"
+	"github.com/square/p2/pkg/types"
+	"github.com/square/p2/pkg/util"
+
+	"github.com/sirupsen/logrus"
+)
+
+// How often a daemon set with a failure threshold compares the nodes that
+// failed to become healthy against it
+var failureBudgetCheckInterval = 5 * time.Second
+
+// failureBudget records a daemon set halting because too many of its nodes
+// failed to become healthy within the timeout
+type failureBudget struct {
+	exceeded    bool
+	failedNodes []types.NodeName
+
+	// disabled is set once the daemon set has been disabled in the store
+	// and the alert has gone out
+	disabled bool
+}
+
+// watchFailureBudget periodically checks the daemon set's failed nodes
+// against its failure threshold until the context is canceled
+func (ds *daemonSet) watchFailureBudget(ctx context.Context) {
+	ticker := time.NewTicker(failureBudgetCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := ds.checkFailureBudget()
+		if err != nil {
+			ds.logger.WithError(err).Errorln("Could not check the daemon set's failure budget")
+		}
+	}
+}
+
+// checkFailureBudget halts the daemon set if more of its nodes failed to
+// become healthy within the timeout than its failure threshold allows. Halting
+// stops any more nodes from being passed to the replication, disables the
+// daemon set and raises an alert.
+func (ds *daemonSet) checkFailureBudget() error {
+	if ds.IsDisabled() || ds.getFailureBudget().disabled {
+		return nil
+	}
+
+	dsReplication := ds.getDSReplication()
+	if dsReplication == nil {
+		return nil
+	}
+
+	ds.mu.Lock()
+	threshold := ds.FailureThreshold
+	ds.mu.Unlock()
+	if threshold == "" {
+		return nil
+	}
+
+	failed := dsReplication.replication.TimedOutNodes()
+	if len(failed) == 0 {
+		return nil
+	}
+
+	eligible, err := ds.EligibleNodes()
+	if err != nil {
+		return util.Errorf("could not compute eligible nodes: %s", err)
+	}
+	exceeded, err := failureBudgetExceeded(threshold, len(eligible), len(failed))
+	if err != nil || !exceeded {
+		return err
+	}
+
+	ds.logger.WithFields(logrus.Fields{
+		"failed_nodes":      failed,
+		"failure_threshold": threshold,
+	}).Errorln("Too many nodes failed to become healthy, halting the daemon set")
+	ds.updateFailureBudget(func(budget *failureBudget) {
+		budget.exceeded = true
+		budget.failedNodes = failed
+	})
+
+	_, err = ds.dsStore.Disable(ds.ID())
+	if err != nil {
+		return util.Errorf("could not disable daemon set: %s", err)
+	}
+	ds.updateFailureBudget(func(budget *failureBudget) {
+		budget.disabled = true
+	})
+	ds.raiseFailureBudgetAlert(threshold, len(eligible), failed)
+	return nil
+}
+
+// failureBudgetExceeded returns whether more nodes failed than the threshold
+// allows out of the eligible nodes. Percentages are rounded down, so a
+// threshold of "0" or a small percentage halts on the first failure.
+func failureBudgetExceeded(threshold fields.IntOrPercent, eligible int, failed int) (bool, error) {
+	allowed, err := threshold.Resolve(eligible, false)
+	if err != nil {
+		return false, util.Errorf("invalid failure threshold: %s", err)
+	}
+	return failed > allowed, nil
+}
+
+func (ds *daemonSet) raiseFailureBudgetAlert(threshold fields.IntOrPercent, eligible int, failed []types.NodeName) {
+	if ds.alerter == nil {
+		return
+	}
+
+	if alertErr := ds.alerter.Alert(alerting.AlertInfo{
+		Description: fmt.Sprintf("Daemon set '%s' was disabled after %d of its %d nodes failed to become healthy", ds.ID(), len(failed), eligible),
+		IncidentKey: fmt.Sprintf("ds_failure_budget_%s", ds.ID()),
+		Details: struct {
+			ID               fields.ID           `json:"id"`
+			Name             fields.ClusterName  `json:"cluster_name"`
+			PodID            types.PodID         `json:"pod_id"`
+			FailureThreshold fields.IntOrPercent `json:"failure_threshold"`
+			EligibleNodes    int                 `json:"eligible_nodes"`
+			FailedNodes      []types.NodeName    `json:"failed_nodes"`
+		}{
+			ID:               ds.ID(),
+			Name:             ds.ClusterName(),
+			PodID:            ds.PodID(),
+			FailureThreshold: threshold,
+			EligibleNodes:    eligible,
+			FailedNodes:      failed,
+		},
+	}, alerting.HighUrgency); alertErr != nil {
+		ds.logger.WithError(alertErr).Errorln("Unable to deliver alert!")
+	}
+}
+
+// halted returns whether the daemon set exceeded its failure budget, in which
+// case no more nodes should be replicated to
+func (ds *daemonSet) halted() bool {
+	return ds.getFailureBudget().exceeded
+}
+
+// resetFailureBudget forgets that the daemon set halted, so that it can be
+// re-enabled. The nodes that failed are forgotten too, otherwise they would
+// halt the daemon set again right away. They are retried along with the rest
+// of the nodes, and count against the threshold again if they fail again.
+func (ds *daemonSet) resetFailureBudget() {
+	ds.updateFailureBudget(func(budget *failureBudget) {
+		*budget = failureBudget{}
+	})
+
+	dsReplication := ds.getDSReplication()
+	if dsReplication != nil {
+		dsReplication.replication.ForgetTimedOutNodes()
+	}
+}
+
+func (ds *daemonSet) updateFailureBudget(update func(budget *failureBudget)) {
+	ds.failureBudgetMu.Lock()
+	defer ds.failureBudgetMu.Unlock()
+	update(&ds.failureBudget)
+}
+
+func (ds *daemonSet) getFailureBudget() failureBudget {
+	ds.failureBudgetMu.Lock()
+	defer ds.failureBudgetMu.Unlock()
+	return ds.failureBudget
+}
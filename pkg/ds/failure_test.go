@@ -0,0 +1,142 @@
+// +build !race
+
+package ds
+
+import (
+	"testing"
+
+	"github.com/square/p2/pkg/alerting/alertingtest"
+	ds_fields "github.com/square/p2/pkg/ds/fields"
+	"github.com/square/p2/pkg/logging"
+	"github.com/square/p2/pkg/types"
+)
+
+func TestFailureBudgetExceeded(t *testing.T) {
+	type testCase struct {
+		threshold ds_fields.IntOrPercent
+		eligible  int
+		failed    int
+		expected  bool
+	}
+
+	for _, tc := range []testCase{
+		{threshold: "0", eligible: 10, failed: 0, expected: false},
+		{threshold: "0", eligible: 10, failed: 1, expected: true},
+		{threshold: "2", eligible: 10, failed: 2, expected: false},
+		{threshold: "2", eligible: 10, failed: 3, expected: true},
+		{threshold: "10%", eligible: 50, failed: 5, expected: false},
+		{threshold: "10%", eligible: 50, failed: 6, expected: true},
+		{threshold: "10%", eligible: 5, failed: 1, expected: true},
+	} {
+		exceeded, err := failureBudgetExceeded(tc.threshold, tc.eligible, tc.failed)
+		if err != nil {
+			t.Errorf("unexpected error for %+v: %s", tc, err)
+			continue
+		}
+		if exceeded != tc.expected {
+			t.Errorf("expected exceeded to be %t for %+v", tc.expected, tc)
+		}
+	}
+
+	if _, err := failureBudgetExceeded("lots", 10, 1); err == nil {
+		t.Error("expected an error for an invalid threshold")
+	}
+}
+
+// disablingDSStore records the daemon sets that were disabled
+type disablingDSStore struct {
+	DaemonSetStore
+
+	disabled []ds_fields.ID
+}
+
+func (s *disablingDSStore) Disable(id ds_fields.ID) (ds_fields.DaemonSet, error) {
+	s.disabled = append(s.disabled, id)
+	return ds_fields.DaemonSet{ID: id, Disabled: true}, nil
+}
+
+type timedOutReplication struct {
+	nullReplication
+
+	timedOut []types.NodeName
+}
+
+func (r *timedOutReplication) TimedOutNodes() []types.NodeName {
+	return r.timedOut
+}
+
+func (r *timedOutReplication) ForgetTimedOutNodes() {
+	r.timedOut = nil
+}
+
+func TestCheckFailureBudget(t *testing.T) {
+	nodes := []types.NodeName{"node1", "node2", "node3", "node4", "node5"}
+	dsStore := &disablingDSStore{}
+	alerter := alertingtest.NewRecorder()
+	ds := &daemonSet{
+		DaemonSet: ds_fields.DaemonSet{
+			ID:               ds_fields.ID("some_ds"),
+			FailureThreshold: "20%",
+		},
+		logger:    logging.TestLogger(),
+		scheduler: staticScheduler(nodes),
+		dsStore:   dsStore,
+		alerter:   alerter,
+	}
+
+	repl := &timedOutReplication{timedOut: []types.NodeName{"node1"}}
+	ds.setDSReplication(&dsReplication{replication: repl})
+
+	err := ds.checkFailureBudget()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ds.halted() {
+		t.Fatal("Expected one failed node out of five to be within the failure budget")
+	}
+
+	repl.timedOut = append(repl.timedOut, "node2")
+	err = ds.checkFailureBudget()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ds.halted() {
+		t.Fatal("Expected two failed nodes out of five to exceed the failure budget")
+	}
+	if len(dsStore.disabled) != 1 || dsStore.disabled[0] != ds.ID() {
+		t.Errorf("Expected the daemon set to have been disabled, got %v", dsStore.disabled)
+	}
+	if len(alerter.Alerts) != 1 {
+		t.Errorf("Expected an alert to have been raised, got %d", len(alerter.Alerts))
+	}
+
+	if failed := ds.getFailureBudget().failedNodes; len(failed) != 2 {
+		t.Errorf("Expected the failed nodes to be recorded, got %v", failed)
+	}
+
+	// checking again before the disabled daemon set is seen shouldn't
+	// disable it or alert again
+	err = ds.checkFailureBudget()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dsStore.disabled) != 1 || len(alerter.Alerts) != 1 {
+		t.Errorf("Expected the daemon set to be disabled once, got %d disables and %d alerts", len(dsStore.disabled), len(alerter.Alerts))
+	}
+
+	ds.resetFailureBudget()
+	if ds.halted() {
+		t.Error("Expected resetting the failure budget to un-halt the daemon set")
+	}
+
+	// the nodes that failed before shouldn't halt the re-enabled daemon
+	// set again
+	ds.DaemonSet.Disabled = false
+	err = ds.checkFailureBudget()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ds.halted() || len(dsStore.disabled) != 1 {
+		t.Errorf("Expected the re-enabled daemon set not to be halted by the nodes that failed before, got %d disables", len(dsStore.disabled))
+	}
+}
@@ -558,6 +558,7 @@ func (dsf *Farm) spawnDaemonSet(
 		unlocker,
 		dsf.statusStore,
 		dsf.statusWritingInterval,
+		dsf.alerter,
 	)
 
 	updatedCh := make(chan ds_fields.DaemonSet)
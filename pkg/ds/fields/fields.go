@@ -57,6 +57,21 @@ type DaemonSet struct {
 	PodID types.PodID
 
 	Timeout time.Duration
+
+	// If set, changes are rolled out in batches according to this
+	// strategy. Otherwise all nodes are updated at once.
+	RollingStrategy *RollingStrategy
+
+	// If set, the daemon set is disabled once more than this many nodes,
+	// or percentage of its eligible nodes, fail to become healthy within
+	// the timeout.
+	FailureThreshold IntOrPercent
+
+	// Overrides change the manifest on some of the daemon set's nodes. The
+	// first override whose node selector matches a node is applied. The
+	// patched manifests are unsigned, so overrides are only useful for
+	// daemon sets with an unsigned manifest.
+	Overrides []ManifestOverride
 }
 
 // RawDaemonSet defines the JSON format used to store data into Consul
@@ -69,6 +84,11 @@ type RawDaemonSet struct {
 	NodeSelector string        `json:"node_selector"`
 	PodID        types.PodID   `json:"pod_id"`
 	Timeout      time.Duration `json:"timeout"`
+
+	RollingStrategy  *RollingStrategy `json:"rolling_strategy,omitempty"`
+	FailureThreshold IntOrPercent     `json:"failure_threshold,omitempty"`
+
+	Overrides []RawManifestOverride `json:"overrides,omitempty"`
 }
 
 // MarshalJSON implements the json.Marshaler interface for serializing the DS
@@ -98,6 +118,11 @@ func (ds *DaemonSet) ToRaw() (RawDaemonSet, error) {
 		nodeSelector = ds.NodeSelector.String()
 	}
 
+	var overrides []RawManifestOverride
+	for _, override := range ds.Overrides {
+		overrides = append(overrides, override.ToRaw())
+	}
+
 	return RawDaemonSet{
 		ID:           ds.ID,
 		Disabled:     ds.Disabled,
@@ -107,6 +132,11 @@ func (ds *DaemonSet) ToRaw() (RawDaemonSet, error) {
 		NodeSelector: nodeSelector,
 		PodID:        ds.PodID,
 		Timeout:      ds.Timeout,
+
+		RollingStrategy:  ds.RollingStrategy,
+		FailureThreshold: ds.FailureThreshold,
+
+		Overrides: overrides,
 	}, nil
 }
 
@@ -136,6 +166,15 @@ func (ds *DaemonSet) UnmarshalJSON(b []byte) error {
 		return err
 	}
 
+	var overrides []ManifestOverride
+	for _, rawOverride := range rawDS.Overrides {
+		override, err := rawOverride.ToOverride()
+		if err != nil {
+			return err
+		}
+		overrides = append(overrides, override)
+	}
+
 	*ds = DaemonSet{
 		ID:           rawDS.ID,
 		Disabled:     rawDS.Disabled,
@@ -145,6 +184,11 @@ func (ds *DaemonSet) UnmarshalJSON(b []byte) error {
 		NodeSelector: nodeSelector,
 		PodID:        rawDS.PodID,
 		Timeout:      rawDS.Timeout,
+
+		RollingStrategy:  rawDS.RollingStrategy,
+		FailureThreshold: rawDS.FailureThreshold,
+
+		Overrides: overrides,
 	}
 	return nil
 }
@@ -0,0 +1,143 @@
+package fields
+
+import (
+	"github.com/square/p2/pkg/launch"
+	"github.com/square/p2/pkg/manifest"
+	"github.com/square/p2/pkg/util"
+	"github.com/square/p2/pkg/util/size"
+
+	"k8s.io/kubernetes/pkg/labels"
+)
+
+// ManifestOverride changes the daemon set's manifest on the nodes matching
+// its node selector, e.g. to give larger hosts more memory. Note that the
+// patched manifest is no longer signed.
+type ManifestOverride struct {
+	NodeSelector labels.Selector
+	Patch        ManifestPatch
+}
+
+// RawManifestOverride is the JSON format of a ManifestOverride
+type RawManifestOverride struct {
+	NodeSelector string        `json:"node_selector"`
+	Patch        ManifestPatch `json:"patch"`
+}
+
+// ManifestPatch describes the changes an override makes to a manifest
+type ManifestPatch struct {
+	// Config keys to set at the top level of the pod's config, replacing
+	// any existing value
+	Config map[string]interface{} `json:"config,omitempty"`
+
+	// Launchables patches the launchables with the given IDs, which must
+	// be in the manifest
+	Launchables map[launch.LaunchableID]LaunchablePatch `json:"launchables,omitempty"`
+}
+
+// LaunchablePatch describes the changes an override makes to a launchable
+type LaunchablePatch struct {
+	// Env variables to set, replacing any with the same name
+	Env map[string]string `json:"env,omitempty"`
+
+	// CPUs and Memory replace the launchable's cgroup limits if set.
+	// Memory uses the same format as manifests, e.g. "2G"
+	CPUs   int    `json:"cpus,omitempty"`
+	Memory string `json:"memory,omitempty"`
+}
+
+func (o ManifestOverride) ToRaw() RawManifestOverride {
+	var nodeSelector string
+	if o.NodeSelector != nil {
+		nodeSelector = o.NodeSelector.String()
+	}
+	return RawManifestOverride{
+		NodeSelector: nodeSelector,
+		Patch:        o.Patch,
+	}
+}
+
+func (r RawManifestOverride) ToOverride() (ManifestOverride, error) {
+	nodeSelector, err := labels.Parse(r.NodeSelector)
+	if err != nil {
+		return ManifestOverride{}, util.Errorf("invalid override node selector %q: %s", r.NodeSelector, err)
+	}
+	return ManifestOverride{
+		NodeSelector: nodeSelector,
+		Patch:        r.Patch,
+	}, nil
+}
+
+// ManifestFor returns the manifest to schedule on a node with the given
+// labels, which is the daemon set's manifest with the first matching
+// override applied
+func (ds DaemonSet) ManifestFor(nodeLabels labels.Labels) (manifest.Manifest, error) {
+	for _, override := range ds.Overrides {
+		if override.NodeSelector.Matches(nodeLabels) {
+			return override.Patch.Apply(ds.Manifest)
+		}
+	}
+	return ds.Manifest, nil
+}
+
+// Apply returns a copy of the manifest with the patch applied. The copy is
+// unsigned even if the manifest was signed, since the patch changes what was
+// signed.
+func (p ManifestPatch) Apply(man manifest.Manifest) (manifest.Manifest, error) {
+	builder := man.GetBuilder()
+
+	if len(p.Config) > 0 {
+		config := man.GetConfig()
+		for key, value := range p.Config {
+			config[key] = value
+		}
+		err := builder.SetConfig(config)
+		if err != nil {
+			return nil, util.Errorf("could not patch config: %s", err)
+		}
+	}
+
+	if len(p.Launchables) > 0 {
+		stanzas := make(map[launch.LaunchableID]launch.LaunchableStanza)
+		for id, stanza := range man.GetLaunchableStanzas() {
+			stanzas[id] = stanza
+		}
+		for id, patch := range p.Launchables {
+			stanza, ok := stanzas[id]
+			if !ok {
+				return nil, util.Errorf("manifest has no launchable %q to patch", id)
+			}
+			stanza, err := patch.apply(stanza)
+			if err != nil {
+				return nil, util.Errorf("could not patch launchable %q: %s", id, err)
+			}
+			stanzas[id] = stanza
+		}
+		builder.SetLaunchables(stanzas)
+	}
+
+	return builder.GetManifest(), nil
+}
+
+func (p LaunchablePatch) apply(stanza launch.LaunchableStanza) (launch.LaunchableStanza, error) {
+	if len(p.Env) > 0 {
+		env := make(map[string]string, len(stanza.Env)+len(p.Env))
+		for name, value := range stanza.Env {
+			env[name] = value
+		}
+		for name, value := range p.Env {
+			env[name] = value
+		}
+		stanza.Env = env
+	}
+	if p.CPUs != 0 {
+		stanza.CgroupConfig.CPUs = p.CPUs
+	}
+	if p.Memory != "" {
+		memory, err := size.Parse(p.Memory)
+		if err != nil {
+			return stanza, err
+		}
+		stanza.CgroupConfig.Memory = memory
+	}
+	return stanza, nil
+}
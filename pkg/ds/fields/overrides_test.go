@@ -0,0 +1,138 @@
+package fields
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/square/p2/pkg/launch"
+	"github.com/square/p2/pkg/manifest"
+	"github.com/square/p2/pkg/util/size"
+
+	"k8s.io/kubernetes/pkg/labels"
+)
+
+func overrideTestManifest(t *testing.T) manifest.Manifest {
+	builder := manifest.NewBuilder()
+	builder.SetID("some_pod")
+	builder.SetLaunchables(map[launch.LaunchableID]launch.LaunchableStanza{
+		"app": {
+			LaunchableType: "hoist",
+			Location:       "https://localhost/app.tar.gz",
+			Env:            map[string]string{"MODE": "normal", "COLOR": "blue"},
+		},
+	})
+	err := builder.SetConfig(map[interface{}]interface{}{"port": 8080, "zone": "default"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return builder.GetManifest()
+}
+
+func TestManifestFor(t *testing.T) {
+	large, err := labels.Parse("size=large")
+	if err != nil {
+		t.Fatal(err)
+	}
+	west, err := labels.Parse("az=west")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := DaemonSet{
+		Manifest: overrideTestManifest(t),
+		Overrides: []ManifestOverride{
+			{
+				NodeSelector: large,
+				Patch: ManifestPatch{
+					Launchables: map[launch.LaunchableID]LaunchablePatch{
+						"app": {CPUs: 8, Memory: "16G", Env: map[string]string{"MODE": "large"}},
+					},
+				},
+			},
+			{
+				NodeSelector: west,
+				Patch:        ManifestPatch{Config: map[string]interface{}{"zone": "west"}},
+			},
+		},
+	}
+
+	man, err := ds.ManifestFor(labels.Set{"size": "large", "az": "west"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	app := man.GetLaunchableStanzas()["app"]
+	if app.CgroupConfig.CPUs != 8 || app.CgroupConfig.Memory != 16*size.Gibibyte {
+		t.Errorf("expected the large override's cgroup to be applied, got %+v", app.CgroupConfig)
+	}
+	if app.Env["MODE"] != "large" || app.Env["COLOR"] != "blue" {
+		t.Errorf("expected the large override's env to be merged in, got %v", app.Env)
+	}
+	if zone := man.GetConfig()["zone"]; zone != "default" {
+		t.Errorf("expected only the first matching override to be applied, got zone %v", zone)
+	}
+	if original := ds.Manifest.GetLaunchableStanzas()["app"]; original.Env["MODE"] != "normal" {
+		t.Errorf("expected the daemon set's manifest not to be modified, got %v", original.Env)
+	}
+
+	man, err = ds.ManifestFor(labels.Set{"az": "west"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := man.GetConfig()
+	if config["zone"] != "west" || config["port"] != 8080 {
+		t.Errorf("expected the zone to be overridden, got config %v", config)
+	}
+
+	man, err = ds.ManifestFor(labels.Set{"az": "east"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if man != ds.Manifest {
+		t.Error("expected nodes without a matching override to get the daemon set's manifest")
+	}
+}
+
+func TestPatchUnknownLaunchable(t *testing.T) {
+	patch := ManifestPatch{
+		Launchables: map[launch.LaunchableID]LaunchablePatch{"other": {CPUs: 1}},
+	}
+	_, err := patch.Apply(overrideTestManifest(t))
+	if err == nil {
+		t.Error("expected an error patching a launchable that isn't in the manifest")
+	}
+}
+
+func TestOverridesRoundTrip(t *testing.T) {
+	selector, err := labels.Parse("size=large")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := DaemonSet{
+		Manifest:     overrideTestManifest(t),
+		NodeSelector: labels.Everything(),
+		Overrides: []ManifestOverride{
+			{
+				NodeSelector: selector,
+				Patch:        ManifestPatch{Config: map[string]interface{}{"zone": "west"}},
+			},
+		},
+	}
+
+	bytes, err := json.Marshal(ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var unmarshaled DaemonSet
+	err = json.Unmarshal(bytes, &unmarshaled)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(unmarshaled.Overrides) != 1 {
+		t.Fatalf("expected one override, got %d", len(unmarshaled.Overrides))
+	}
+	override := unmarshaled.Overrides[0]
+	if override.NodeSelector.String() != selector.String() || override.Patch.Config["zone"] != "west" {
+		t.Errorf("override didn't survive a round trip, got %+v", override)
+	}
+}
@@ -0,0 +1,96 @@
+package fields
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/square/p2/pkg/util"
+)
+
+// IntOrPercent is a number of nodes, either absolute ("5") or as a percentage
+// of the daemon set's eligible nodes ("10%").
+type IntOrPercent string
+
+// Resolve returns the number of nodes out of total. Percentages are rounded up
+// if roundUp is set and down otherwise.
+func (q IntOrPercent) Resolve(total int, roundUp bool) (int, error) {
+	s := strings.TrimSpace(string(q))
+	if s == "" {
+		return 0, nil
+	}
+
+	if strings.HasSuffix(s, "%") {
+		percent, err := strconv.Atoi(strings.TrimSuffix(s, "%"))
+		if err != nil || percent < 0 || percent > 100 {
+			return 0, util.Errorf("%q is not a valid percentage", string(q))
+		}
+		n := total * percent / 100
+		if roundUp && total*percent%100 != 0 {
+			n++
+		}
+		return n, nil
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, util.Errorf("%q is not a valid number of nodes or percentage", string(q))
+	}
+	return n, nil
+}
+
+// RollingStrategy makes a daemon set roll out changes to its manifest in
+// batches instead of to all of its nodes at once. Each batch is deployed and
+// given time to become healthy before the next one starts.
+type RollingStrategy struct {
+	// MaxUnavailable is how many nodes may be without a healthy pod at
+	// once, counting those being updated. Percentages are rounded down.
+	MaxUnavailable IntOrPercent `json:"max_unavailable"`
+
+	// MaxSurge is how many more nodes may be updated at once, on top of
+	// MaxUnavailable, for pods whose old and new versions can run side by
+	// side so that updating them doesn't take them out of service.
+	// Percentages are rounded up.
+	MaxSurge IntOrPercent `json:"max_surge,omitempty"`
+
+	// BatchDelay is how long to wait after a batch before starting the
+	// next one.
+	BatchDelay time.Duration `json:"batch_delay,omitempty"`
+}
+
+func (s RollingStrategy) Validate() error {
+	unavailable, err := s.MaxUnavailable.Resolve(100, false)
+	if err != nil {
+		return util.Errorf("invalid max unavailable: %s", err)
+	}
+	surge, err := s.MaxSurge.Resolve(100, true)
+	if err != nil {
+		return util.Errorf("invalid max surge: %s", err)
+	}
+	if unavailable == 0 && surge == 0 {
+		return util.Errorf("rolling strategy must allow at least one of max unavailable and max surge to be nonzero")
+	}
+	if s.BatchDelay < 0 {
+		return util.Errorf("batch delay must not be negative, got %s", s.BatchDelay)
+	}
+	return nil
+}
+
+// Resolve returns the maximum unavailable and surge node counts for a daemon
+// set with the given number of eligible nodes. If both would be zero, such as
+// for a small percentage of few nodes, one node may be unavailable so that the
+// daemon set still makes progress.
+func (s RollingStrategy) Resolve(eligible int) (maxUnavailable int, maxSurge int, err error) {
+	maxUnavailable, err = s.MaxUnavailable.Resolve(eligible, false)
+	if err != nil {
+		return 0, 0, err
+	}
+	maxSurge, err = s.MaxSurge.Resolve(eligible, true)
+	if err != nil {
+		return 0, 0, err
+	}
+	if maxUnavailable == 0 && maxSurge == 0 {
+		maxUnavailable = 1
+	}
+	return maxUnavailable, maxSurge, nil
+}
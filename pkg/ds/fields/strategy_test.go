@@ -0,0 +1,70 @@
+package fields
+
+import (
+	"testing"
+)
+
+func TestIntOrPercentResolve(t *testing.T) {
+	type testCase struct {
+		quantity IntOrPercent
+		total    int
+		roundUp  bool
+		expected int
+	}
+
+	for _, tc := range []testCase{
+		{quantity: "", total: 10, expected: 0},
+		{quantity: "3", total: 10, expected: 3},
+		{quantity: "3", total: 2, expected: 3},
+		{quantity: "25%", total: 10, expected: 2},
+		{quantity: "25%", total: 10, roundUp: true, expected: 3},
+		{quantity: "50%", total: 10, roundUp: true, expected: 5},
+		{quantity: "10%", total: 5000, expected: 500},
+	} {
+		n, err := tc.quantity.Resolve(tc.total, tc.roundUp)
+		if err != nil {
+			t.Errorf("unexpected error resolving %q: %s", tc.quantity, err)
+			continue
+		}
+		if n != tc.expected {
+			t.Errorf("expected %q of %d (round up %t) to be %d, got %d", tc.quantity, tc.total, tc.roundUp, tc.expected, n)
+		}
+	}
+
+	for _, invalid := range []IntOrPercent{"-1", "abc", "101%", "%"} {
+		if _, err := invalid.Resolve(10, false); err == nil {
+			t.Errorf("expected an error resolving %q", invalid)
+		}
+	}
+}
+
+func TestRollingStrategyResolve(t *testing.T) {
+	strategy := RollingStrategy{MaxUnavailable: "10%"}
+	if err := strategy.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	maxUnavailable, maxSurge, err := strategy.Resolve(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if maxUnavailable != 1 || maxSurge != 0 {
+		t.Errorf("expected a small daemon set to still update one node at a time, got %d unavailable and %d surge", maxUnavailable, maxSurge)
+	}
+
+	strategy = RollingStrategy{MaxUnavailable: "0", MaxSurge: "1%"}
+	maxUnavailable, maxSurge, err = strategy.Resolve(150)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if maxUnavailable != 0 || maxSurge != 2 {
+		t.Errorf("expected 0 unavailable and 2 surge, got %d and %d", maxUnavailable, maxSurge)
+	}
+
+	if err := (RollingStrategy{MaxUnavailable: "0"}).Validate(); err == nil {
+		t.Error("expected a strategy that can't update any node to be invalid")
+	}
+	if err := (RollingStrategy{MaxUnavailable: "1", BatchDelay: -1}).Validate(); err == nil {
+		t.Error("expected a negative batch delay to be invalid")
+	}
+}
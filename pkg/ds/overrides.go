@@ -0,0 +1,97 @@
+package ds
+
+import (
+	"encoding/json"
+
+	"github.com/square/p2/pkg/ds/fields"
+	"github.com/square/p2/pkg/labels"
+	"github.com/square/p2/pkg/manifest"
+	"github.com/square/p2/pkg/types"
+	"github.com/square/p2/pkg/util"
+
+	klabels "k8s.io/kubernetes/pkg/labels"
+)
+
+// manifestForNode applies the first of the daemon set's overrides that
+// matches the node's labels to man, which is the manifest being replicated.
+// It satisfies replication.ManifestForNode.
+func (ds *daemonSet) manifestForNode(node types.NodeName, man manifest.Manifest) (manifest.Manifest, error) {
+	ds.mu.Lock()
+	overrides := ds.Overrides
+	ds.mu.Unlock()
+	if len(overrides) == 0 {
+		return man, nil
+	}
+
+	nodeLabels, err := ds.applicator.GetLabels(labels.NODE, node.String())
+	if err != nil {
+		return nil, util.Errorf("could not get labels of %s: %s", node, err)
+	}
+
+	overridden := fields.DaemonSet{
+		Manifest:  man,
+		Overrides: overrides,
+	}
+	return overridden.ManifestFor(nodeLabels.Labels)
+}
+
+// manifestsForNodes is like manifestForNode for many nodes at once, reading
+// the labels of all nodes with a single query rather than one per node.
+func (ds *daemonSet) manifestsForNodes(nodes []types.NodeName, man manifest.Manifest) (map[types.NodeName]manifest.Manifest, error) {
+	ds.mu.Lock()
+	overrides := ds.Overrides
+	ds.mu.Unlock()
+
+	manifests := make(map[types.NodeName]manifest.Manifest, len(nodes))
+	if len(overrides) == 0 {
+		for _, node := range nodes {
+			manifests[node] = man
+		}
+		return manifests, nil
+	}
+
+	nodeMatches, err := ds.applicator.GetMatches(klabels.Everything(), labels.NODE)
+	if err != nil && err != labels.NoLabelsFound {
+		return nil, util.Errorf("could not get node labels: %s", err)
+	}
+	nodeLabels := make(map[types.NodeName]klabels.Set, len(nodeMatches))
+	for _, nodeMatch := range nodeMatches {
+		nodeLabels[types.NodeName(nodeMatch.ID)] = nodeMatch.Labels
+	}
+
+	overridden := fields.DaemonSet{
+		Manifest:  man,
+		Overrides: overrides,
+	}
+	for _, node := range nodes {
+		manifests[node], err = overridden.ManifestFor(nodeLabels[node])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return manifests, nil
+}
+
+// overridesChanged returns whether two daemon sets have different overrides.
+// Node selectors can't be compared directly, so this compares the overrides'
+// JSON representations.
+func overridesChanged(oldDS fields.DaemonSet, newDS fields.DaemonSet) (bool, error) {
+	oldRaw, err := oldDS.ToRaw()
+	if err != nil {
+		return false, err
+	}
+	newRaw, err := newDS.ToRaw()
+	if err != nil {
+		return false, err
+	}
+
+	oldOverrides, err := json.Marshal(oldRaw.Overrides)
+	if err != nil {
+		return false, err
+	}
+	newOverrides, err := json.Marshal(newRaw.Overrides)
+	if err != nil {
+		return false, err
+	}
+	return string(oldOverrides) != string(newOverrides), nil
+}
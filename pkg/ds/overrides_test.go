@@ -0,0 +1,153 @@
+// +build !race
+
+package ds
+
+import (
+	"testing"
+
+	ds_fields "github.com/square/p2/pkg/ds/fields"
+	"github.com/square/p2/pkg/labels"
+	"github.com/square/p2/pkg/types"
+
+	klabels "k8s.io/kubernetes/pkg/labels"
+)
+
+func TestManifestForNode(t *testing.T) {
+	applicator := labels.NewFakeApplicator()
+	err := applicator.SetLabel(labels.NODE, "big_node", "size", "large")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = applicator.SetLabel(labels.NODE, "small_node", "size", "small")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	large, err := klabels.Parse("size=large")
+	if err != nil {
+		t.Fatal(err)
+	}
+	man := testManifest("some_pod")
+	ds := &daemonSet{
+		DaemonSet: ds_fields.DaemonSet{
+			Manifest: man,
+			Overrides: []ds_fields.ManifestOverride{
+				{
+					NodeSelector: large,
+					Patch:        ds_fields.ManifestPatch{Config: map[string]interface{}{"threads": 64}},
+				},
+			},
+		},
+		applicator: applicator,
+	}
+
+	for node, expectedThreads := range map[types.NodeName]interface{}{
+		"big_node":   64,
+		"small_node": nil,
+	} {
+		nodeManifest, err := ds.manifestForNode(node, man)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if threads := nodeManifest.GetConfig()["threads"]; threads != expectedThreads {
+			t.Errorf("expected %s to be configured with %v threads, got %v", node, expectedThreads, threads)
+		}
+	}
+}
+
+func TestManifestsForNodes(t *testing.T) {
+	applicator := labels.NewFakeApplicator()
+	err := applicator.SetLabel(labels.NODE, "big_node", "size", "large")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = applicator.SetLabel(labels.NODE, "small_node", "size", "small")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	large, err := klabels.Parse("size=large")
+	if err != nil {
+		t.Fatal(err)
+	}
+	man := testManifest("some_pod")
+	ds := &daemonSet{
+		DaemonSet: ds_fields.DaemonSet{
+			Manifest: man,
+			Overrides: []ds_fields.ManifestOverride{
+				{
+					NodeSelector: large,
+					Patch:        ds_fields.ManifestPatch{Config: map[string]interface{}{"threads": 64}},
+				},
+			},
+		},
+		applicator: applicator,
+	}
+
+	expected := map[types.NodeName]interface{}{
+		"big_node":   64,
+		"small_node": nil,
+		// nodes without any labels get the manifest as-is
+		"unlabeled_node": nil,
+	}
+	nodes := make([]types.NodeName, 0, len(expected))
+	for node := range expected {
+		nodes = append(nodes, node)
+	}
+	manifests, err := ds.manifestsForNodes(nodes, man)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for node, expectedThreads := range expected {
+		nodeManifest, ok := manifests[node]
+		if !ok {
+			t.Errorf("expected a manifest for %s", node)
+			continue
+		}
+		if threads := nodeManifest.GetConfig()["threads"]; threads != expectedThreads {
+			t.Errorf("expected %s to be configured with %v threads, got %v", node, expectedThreads, threads)
+		}
+	}
+}
+
+func TestOverridesChanged(t *testing.T) {
+	large, err := klabels.Parse("size=large")
+	if err != nil {
+		t.Fatal(err)
+	}
+	alsoLarge, err := klabels.Parse("size=large")
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldDS := ds_fields.DaemonSet{
+		Overrides: []ds_fields.ManifestOverride{
+			{NodeSelector: large, Patch: ds_fields.ManifestPatch{Config: map[string]interface{}{"threads": 64}}},
+		},
+	}
+	sameDS := ds_fields.DaemonSet{
+		Overrides: []ds_fields.ManifestOverride{
+			{NodeSelector: alsoLarge, Patch: ds_fields.ManifestPatch{Config: map[string]interface{}{"threads": 64}}},
+		},
+	}
+	changedDS := ds_fields.DaemonSet{
+		Overrides: []ds_fields.ManifestOverride{
+			{NodeSelector: large, Patch: ds_fields.ManifestPatch{Config: map[string]interface{}{"threads": 32}}},
+		},
+	}
+
+	changed, err := overridesChanged(oldDS, sameDS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed {
+		t.Error("expected identical overrides not to be reported as changed")
+	}
+
+	changed, err = overridesChanged(oldDS, changedDS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changed {
+		t.Error("expected a different patch to be reported as changed")
+	}
+}
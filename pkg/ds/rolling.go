@@ -0,0 +1,234 @@
+package ds
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/square/p2/pkg/ds/fields"
+	"github.com/square/p2/pkg/health"
+	"github.com/square/p2/pkg/manifest"
+	"github.com/square/p2/pkg/store/consul/statusstore/daemonsetstatus"
+	"github.com/square/p2/pkg/types"
+	"github.com/square/p2/pkg/util"
+
+	"github.com/sirupsen/logrus"
+)
+
+// How often a daemon set with a rolling strategy checks whether its batch is
+// done, or whether enough nodes are healthy to start the next one
+var rollingPollInterval = 1 * time.Second
+
+// replicationSignals are the channels WatchDesires uses to steer Replicate
+type replicationSignals struct {
+	pause          <-chan struct{}
+	unpause        <-chan struct{}
+	manifestChange <-chan manifest.Manifest
+	timeoutChange  <-chan time.Duration
+}
+
+func (ds *daemonSet) RollingStrategy() *fields.RollingStrategy {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	return ds.DaemonSet.RollingStrategy
+}
+
+// addNodesInBatches passes nodes to the replication a batch at a time, as
+// sized by the daemon set's rolling strategy, and waits for each batch to
+// finish before starting the next one. If the strategy is removed along the
+// way, the rest of the nodes are passed on at once. It returns true if
+// replication was paused, in which case the rest of the nodes are dropped.
+func (ds *daemonSet) addNodesInBatches(
+	ctx context.Context,
+	nodes []types.NodeName,
+	nodeQueue chan<- types.NodeName,
+	signals replicationSignals,
+) bool {
+	remaining := nodes
+	for len(remaining) > 0 {
+		if ds.halted() {
+			return false
+		}
+
+		strategy := ds.RollingStrategy()
+		size := len(remaining)
+		unavailable := 0
+		if strategy != nil {
+			var err error
+			size, unavailable, err = ds.nextBatchSize(*strategy)
+			if err != nil {
+				ds.logger.WithError(err).Errorln("Could not size the next batch")
+				ds.updateRollingStatus(func(status *daemonsetstatus.RollingStatus) {
+					status.BlockingReason = fmt.Sprintf("could not size the next batch: %s", err)
+				})
+				if paused, ok := ds.rollingWait(ctx, rollingPollInterval, signals); !ok {
+					return paused
+				}
+				continue
+			}
+			if size == 0 {
+				ds.updateRollingStatus(func(status *daemonsetstatus.RollingStatus) {
+					status.Unavailable = unavailable
+					status.NodesRemaining = len(remaining)
+					status.BlockingReason = fmt.Sprintf("waiting for some of the %d unavailable nodes to become healthy", unavailable)
+				})
+				if paused, ok := ds.rollingWait(ctx, rollingPollInterval, signals); !ok {
+					return paused
+				}
+				continue
+			}
+			if size > len(remaining) {
+				size = len(remaining)
+			}
+		}
+
+		batch := remaining[:size]
+		remaining = remaining[size:]
+		ds.logger.WithFields(logrus.Fields{
+			"batch_size":  len(batch),
+			"remaining":   len(remaining),
+			"unavailable": unavailable,
+		}).Infoln("Starting batch")
+		ds.updateRollingStatus(func(status *daemonsetstatus.RollingStatus) {
+			status.BatchSize = len(batch)
+			status.NodesRemaining = len(remaining)
+			status.Unavailable = unavailable
+			status.BlockingReason = ""
+		})
+
+		replication := ds.getDSReplication().replication
+		finished := replication.FinishedCount()
+		for _, node := range batch {
+			select {
+			case nodeQueue <- node:
+			case <-ctx.Done():
+				return false
+			case <-signals.pause:
+				return true
+			}
+		}
+		if strategy == nil {
+			continue
+		}
+
+		for int(replication.FinishedCount()-finished) < len(batch) {
+			if paused, ok := ds.rollingWait(ctx, rollingPollInterval, signals); !ok {
+				return paused
+			}
+		}
+		ds.updateRollingStatus(func(status *daemonsetstatus.RollingStatus) {
+			status.BatchesCompleted++
+			status.BatchSize = 0
+			status.LastBatchTime = time.Now()
+		})
+
+		if len(remaining) > 0 && strategy.BatchDelay > 0 {
+			ds.updateRollingStatus(func(status *daemonsetstatus.RollingStatus) {
+				status.BlockingReason = fmt.Sprintf("waiting for the batch delay of %s", strategy.BatchDelay)
+			})
+			if paused, ok := ds.rollingWait(ctx, strategy.BatchDelay, signals); !ok {
+				return paused
+			}
+		}
+	}
+
+	ds.updateRollingStatus(func(status *daemonsetstatus.RollingStatus) {
+		status.NodesRemaining = 0
+		status.BlockingReason = ""
+	})
+	return false
+}
+
+// rollingWait waits for the given duration while still handling signals from
+// WatchDesires. It returns false if the wait was cut short, along with whether
+// that was because replication was paused.
+func (ds *daemonSet) rollingWait(ctx context.Context, d time.Duration, signals replicationSignals) (paused bool, ok bool) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+			return false, true
+		case <-ctx.Done():
+			return false, false
+		case <-signals.pause:
+			return true, false
+		case <-signals.unpause:
+		case man := <-signals.manifestChange:
+			if man != nil {
+				ds.getDSReplication().replication.SetManifest(man)
+			}
+		case timeout := <-signals.timeoutChange:
+			ds.getDSReplication().replication.SetTimeout(timeout)
+		}
+	}
+}
+
+// nextBatchSize returns how many nodes the next batch may update, and how
+// many of the daemon set's nodes are unavailable.
+func (ds *daemonSet) nextBatchSize(strategy fields.RollingStrategy) (int, int, error) {
+	eligible, err := ds.EligibleNodes()
+	if err != nil {
+		return 0, 0, util.Errorf("could not compute eligible nodes: %s", err)
+	}
+	maxUnavailable, maxSurge, err := strategy.Resolve(len(eligible))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	unavailable, err := ds.unavailableNodes()
+	if err != nil {
+		return 0, 0, err
+	}
+	return batchSize(maxUnavailable, maxSurge, unavailable), unavailable, nil
+}
+
+// batchSize returns how many nodes a batch may update. Unavailable nodes use
+// up the max unavailable, but not the surge, since surged pods run alongside
+// the ones they replace.
+func batchSize(maxUnavailable int, maxSurge int, unavailable int) int {
+	budget := maxUnavailable - unavailable
+	if budget < 0 {
+		budget = 0
+	}
+	return budget + maxSurge
+}
+
+// unavailableNodes counts the daemon set's nodes whose pod is reported as
+// unhealthy. Nodes without a health result aren't counted, since they are
+// usually just being scheduled.
+func (ds *daemonSet) unavailableNodes() (int, error) {
+	if ds.healthChecker == nil {
+		return 0, nil
+	}
+
+	current, err := ds.CurrentPods()
+	if err != nil {
+		return 0, err
+	}
+	results, err := (*ds.healthChecker).Service(ds.PodID().String())
+	if err != nil {
+		return 0, util.Errorf("could not get health of daemon set nodes: %s", err)
+	}
+
+	unavailable := 0
+	for _, node := range current.Nodes() {
+		result, ok := results[node]
+		if ok && health.Compare(result.Status, health.Passing) < 0 {
+			unavailable++
+		}
+	}
+	return unavailable, nil
+}
+
+func (ds *daemonSet) updateRollingStatus(update func(status *daemonsetstatus.RollingStatus)) {
+	ds.rollingStatusMu.Lock()
+	defer ds.rollingStatusMu.Unlock()
+	update(&ds.rollingStatus)
+}
+
+func (ds *daemonSet) getRollingStatus() daemonsetstatus.RollingStatus {
+	ds.rollingStatusMu.Lock()
+	defer ds.rollingStatusMu.Unlock()
+	return ds.rollingStatus
+}
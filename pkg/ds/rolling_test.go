@@ -0,0 +1,124 @@
+// +build !race
+
+package ds
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	klabels "k8s.io/kubernetes/pkg/labels"
+
+	ds_fields "github.com/square/p2/pkg/ds/fields"
+	"github.com/square/p2/pkg/logging"
+	"github.com/square/p2/pkg/manifest"
+	"github.com/square/p2/pkg/types"
+)
+
+func TestBatchSize(t *testing.T) {
+	type testCase struct {
+		maxUnavailable int
+		maxSurge       int
+		unavailable    int
+		expected       int
+	}
+
+	for _, tc := range []testCase{
+		{maxUnavailable: 5, expected: 5},
+		{maxUnavailable: 5, unavailable: 2, expected: 3},
+		{maxUnavailable: 5, unavailable: 7, expected: 0},
+		{maxUnavailable: 5, maxSurge: 2, unavailable: 7, expected: 2},
+		{maxUnavailable: 0, maxSurge: 3, expected: 3},
+	} {
+		size := batchSize(tc.maxUnavailable, tc.maxSurge, tc.unavailable)
+		if size != tc.expected {
+			t.Errorf("expected a batch of %d for %+v, got %d", tc.expected, tc, size)
+		}
+	}
+}
+
+type staticScheduler []types.NodeName
+
+func (s staticScheduler) EligibleNodes(manifest.Manifest, klabels.Selector) ([]types.NodeName, error) {
+	return s, nil
+}
+
+// batchReplication finishes the nodes it is given shortly after receiving
+// them, keeping track of how many it had in flight at once
+type batchReplication struct {
+	nullReplication
+
+	mu          sync.Mutex
+	finished    int32
+	inFlight    int
+	maxInFlight int
+}
+
+func (b *batchReplication) FinishedCount() int32 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.finished
+}
+
+func (b *batchReplication) run(nodeQueue <-chan types.NodeName) {
+	for range nodeQueue {
+		b.mu.Lock()
+		b.inFlight++
+		if b.inFlight > b.maxInFlight {
+			b.maxInFlight = b.inFlight
+		}
+		b.mu.Unlock()
+
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			b.mu.Lock()
+			b.inFlight--
+			b.finished++
+			b.mu.Unlock()
+		}()
+	}
+}
+
+func TestAddNodesInBatches(t *testing.T) {
+	rollingPollInterval = 5 * time.Millisecond
+	defer func() { rollingPollInterval = 1 * time.Second }()
+
+	nodes := []types.NodeName{"node1", "node2", "node3", "node4", "node5"}
+	ds := &daemonSet{
+		DaemonSet: ds_fields.DaemonSet{
+			RollingStrategy: &ds_fields.RollingStrategy{MaxUnavailable: "40%"},
+		},
+		logger:    logging.TestLogger(),
+		scheduler: staticScheduler(nodes),
+	}
+
+	nodeQueue := make(chan types.NodeName)
+	repl := &batchReplication{}
+	go repl.run(nodeQueue)
+	defer close(nodeQueue)
+	ds.setDSReplication(&dsReplication{replication: repl, nodeQueue: nodeQueue})
+
+	paused := ds.addNodesInBatches(context.Background(), nodes, nodeQueue, replicationSignals{})
+	if paused {
+		t.Fatal("Expected the batches not to be paused")
+	}
+
+	repl.mu.Lock()
+	maxInFlight := repl.maxInFlight
+	repl.mu.Unlock()
+	if maxInFlight != 2 {
+		t.Errorf("Expected at most 2 nodes to be updated at once, got %d", maxInFlight)
+	}
+	if finished := repl.FinishedCount(); finished != 5 {
+		t.Errorf("Expected all 5 nodes to be updated, got %d", finished)
+	}
+
+	status := ds.getRollingStatus()
+	if status.BatchesCompleted != 3 {
+		t.Errorf("Expected 3 batches to have completed, got %d", status.BatchesCompleted)
+	}
+	if status.NodesRemaining != 0 || status.BatchSize != 0 {
+		t.Errorf("Expected no nodes to remain, got %+v", status)
+	}
+}
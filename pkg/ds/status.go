@@ -0,0 +1,154 @@
+package ds
+
+import (
+	"time"
+
+	"github.com/square/p2/pkg/ds/fields"
+	"github.com/square/p2/pkg/health"
+	"github.com/square/p2/pkg/store/consul"
+	"github.com/square/p2/pkg/store/consul/statusstore/daemonsetstatus"
+	"github.com/square/p2/pkg/types"
+	"github.com/square/p2/pkg/util"
+)
+
+// How often a daemon set recomputes the breakdown of its nodes for its
+// status. This is much slower than the status writing interval because it
+// reads the reality of every node the daemon set is scheduled on
+var detailedStatusInterval = 1 * time.Minute
+
+// statusDetails are the parts of a daemon set's status that are expensive
+// to compute, so they're cached between refreshes
+type statusDetails struct {
+	nodes        daemonsetstatus.NodeStatus
+	contendsWith fields.ID
+	refreshed    time.Time
+}
+
+// refreshStatusDetails recomputes the node breakdown and contention state of
+// the daemon set if they haven't been for detailedStatusInterval
+func (ds *daemonSet) refreshStatusDetails() error {
+	if time.Since(ds.getStatusDetails().refreshed) < detailedStatusInterval {
+		return nil
+	}
+
+	nodes, err := ds.computeNodeStatus()
+	if err != nil {
+		return err
+	}
+
+	ds.mu.Lock()
+	dsFields := ds.DaemonSet
+	ds.mu.Unlock()
+	contended, isContended, err := DSContends(dsFields, ds.scheduler, ds.dsStore)
+	if err != nil {
+		return err
+	}
+
+	ds.statusDetailsMu.Lock()
+	defer ds.statusDetailsMu.Unlock()
+	ds.statusDetails.nodes = nodes
+	ds.statusDetails.contendsWith = ""
+	if isContended {
+		ds.statusDetails.contendsWith = contended.ID
+	}
+	ds.statusDetails.refreshed = time.Now()
+	return nil
+}
+
+// computeNodeStatus counts the daemon set's nodes by whether they are
+// scheduled, running the current manifest and healthy
+func (ds *daemonSet) computeNodeStatus() (daemonsetstatus.NodeStatus, error) {
+	eligible, err := ds.EligibleNodes()
+	if err != nil {
+		return daemonsetstatus.NodeStatus{}, util.Errorf("could not compute eligible nodes: %s", err)
+	}
+	scheduled, err := ds.CurrentPods()
+	if err != nil {
+		return daemonsetstatus.NodeStatus{}, err
+	}
+
+	var results map[types.NodeName]health.Result
+	if ds.healthChecker != nil {
+		results, err = (*ds.healthChecker).Service(ds.PodID().String())
+		if err != nil {
+			return daemonsetstatus.NodeStatus{}, util.Errorf("could not get health of daemon set nodes: %s", err)
+		}
+	}
+
+	status := daemonsetstatus.NodeStatus{
+		Eligible:  len(eligible),
+		Scheduled: len(scheduled),
+	}
+	nodeManifests, err := ds.manifestsForNodes(scheduled.Nodes(), ds.Manifest())
+	if err != nil {
+		return daemonsetstatus.NodeStatus{}, err
+	}
+	reality, err := ds.store.Pods(consul.REALITY_TREE, scheduled)
+	if err != nil {
+		return daemonsetstatus.NodeStatus{}, util.Errorf("could not read reality of daemon set nodes: %s", err)
+	}
+
+	current := make(map[types.NodeName]bool)
+	for _, location := range scheduled {
+		node := location.Node
+		// the pod hasn't been launched on the node yet if it has no
+		// manifest in the reality tree
+		if man, ok := reality[location]; ok {
+			nodeSHA, err := nodeManifests[node].SHA()
+			if err != nil {
+				return daemonsetstatus.NodeStatus{}, err
+			}
+			realitySHA, err := man.SHA()
+			if err != nil {
+				return daemonsetstatus.NodeStatus{}, err
+			}
+			if realitySHA == nodeSHA {
+				current[node] = true
+				status.Current++
+			} else {
+				status.OldSHA = append(status.OldSHA, node)
+			}
+		}
+
+		if result, ok := results[node]; ok {
+			if health.Compare(result.Status, health.Passing) >= 0 {
+				status.Healthy++
+			} else {
+				status.Unhealthy++
+			}
+		}
+	}
+
+	failed := make(map[types.NodeName]bool)
+	for _, node := range ds.failedNodes() {
+		failed[node] = true
+	}
+	for _, node := range eligible {
+		if !current[node] && !failed[node] {
+			status.Pending++
+		}
+	}
+	return status, nil
+}
+
+// failedNodes returns the nodes that failed to become healthy within the
+// timeout. Once the failure budget has been exceeded, these are the nodes
+// that exceeded it.
+func (ds *daemonSet) failedNodes() []types.NodeName {
+	budget := ds.getFailureBudget()
+	if budget.exceeded {
+		return budget.failedNodes
+	}
+
+	dsReplication := ds.getDSReplication()
+	if dsReplication == nil {
+		return nil
+	}
+	return dsReplication.replication.TimedOutNodes()
+}
+
+func (ds *daemonSet) getStatusDetails() statusDetails {
+	ds.statusDetailsMu.Lock()
+	defer ds.statusDetailsMu.Unlock()
+	return ds.statusDetails
+}
@@ -0,0 +1,103 @@
+// +build !race
+
+package ds
+
+import (
+	"reflect"
+	"testing"
+
+	ds_fields "github.com/square/p2/pkg/ds/fields"
+	"github.com/square/p2/pkg/health"
+	fake_checker "github.com/square/p2/pkg/health/checker/test"
+	"github.com/square/p2/pkg/labels"
+	"github.com/square/p2/pkg/logging"
+	"github.com/square/p2/pkg/manifest"
+	"github.com/square/p2/pkg/store/consul"
+	"github.com/square/p2/pkg/store/consul/statusstore/daemonsetstatus"
+	"github.com/square/p2/pkg/types"
+)
+
+// realityStore serves pods out of a map of node to the manifest in its
+// reality
+type realityStore struct {
+	store
+
+	reality map[types.NodeName]manifest.Manifest
+}
+
+func (s realityStore) Pods(podPrefix consul.PodPrefix, locations types.PodLocations) (map[types.PodLocation]manifest.Manifest, error) {
+	ret := make(map[types.PodLocation]manifest.Manifest)
+	if podPrefix != consul.REALITY_TREE {
+		return ret, nil
+	}
+	for _, location := range locations {
+		if man, ok := s.reality[location.Node]; ok {
+			ret[location] = man
+		}
+	}
+	return ret, nil
+}
+
+func TestComputeNodeStatus(t *testing.T) {
+	podID := types.PodID("some_pod")
+	currentManifest := testManifest(podID)
+	builder := currentManifest.GetBuilder()
+	builder.SetRunAsUser("someone_else")
+	oldManifest := builder.GetManifest()
+
+	applicator := labels.NewFakeApplicator()
+	for _, node := range []types.NodeName{"node1", "node2", "node3", "node4"} {
+		err := applicator.SetLabel(labels.POD, labels.MakePodLabelKey(node, podID), DSIDLabel, "some_ds")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	healthChecker := fake_checker.NewSingleService(podID.String(), map[types.NodeName]health.Result{
+		"node1": {Status: health.Passing},
+		"node2": {Status: health.Critical},
+		"node3": {Status: health.Passing},
+	})
+
+	ds := &daemonSet{
+		DaemonSet: ds_fields.DaemonSet{
+			ID:       ds_fields.ID("some_ds"),
+			PodID:    podID,
+			Manifest: currentManifest,
+		},
+		logger:     logging.TestLogger(),
+		scheduler:  staticScheduler{"node1", "node2", "node3", "node4", "node5"},
+		applicator: applicator,
+		store: realityStore{
+			reality: map[types.NodeName]manifest.Manifest{
+				"node1": currentManifest,
+				"node2": currentManifest,
+				"node3": oldManifest,
+			},
+		},
+		healthChecker: &healthChecker,
+	}
+	ds.setDSReplication(&dsReplication{
+		replication: &timedOutReplication{timedOut: []types.NodeName{"node4"}},
+	})
+
+	status, err := ds.computeNodeStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := daemonsetstatus.NodeStatus{
+		Eligible:  5,
+		Scheduled: 4,
+		Current:   2,
+		Healthy:   2,
+		Unhealthy: 1,
+		// node3 is on the old manifest and node5 isn't scheduled yet,
+		// while node4 failed
+		Pending: 2,
+		OldSHA:  []types.NodeName{"node3"},
+	}
+	if !reflect.DeepEqual(status, expected) {
+		t.Errorf("expected node status %+v, got %+v", expected, status)
+	}
+}
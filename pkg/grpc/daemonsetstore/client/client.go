@@ -2,17 +2,22 @@ package client
 
 import (
 	"context"
+	"time"
 
 	"github.com/square/p2/pkg/ds"
 	"github.com/square/p2/pkg/ds/fields"
 	"github.com/square/p2/pkg/grpc/daemonsetstore"
 	daemonsetstore_protos "github.com/square/p2/pkg/grpc/daemonsetstore/protos"
 	"github.com/square/p2/pkg/logging"
+	"github.com/square/p2/pkg/manifest"
 	"github.com/square/p2/pkg/store/consul/dsstore"
+	"github.com/square/p2/pkg/store/consul/statusstore/daemonsetstatus"
+	"github.com/square/p2/pkg/types"
 	"github.com/square/p2/pkg/util"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	klabels "k8s.io/kubernetes/pkg/labels"
 )
 
 type Client struct {
@@ -24,6 +29,21 @@ type Client struct {
 // so it may be dropped in
 var _ ds.DaemonSetStore = Client{}
 
+// and the interface used by the daemon set store server to make audited
+// changes, which it mirrors
+var _ daemonsetstore.MirroredAuditingStore = Client{}
+
+// FarmUser is the user that disables made through Disable are attributed to,
+// since they are made by the daemon set farm rather than a person
+const FarmUser = "p2-ds-farm"
+
+func New(conn *grpc.ClientConn, logger logging.Logger) Client {
+	return Client{
+		client: daemonsetstore_protos.NewP2DaemonSetStoreClient(conn),
+		logger: logger,
+	}
+}
+
 func (c Client) List() ([]fields.DaemonSet, error) {
 	resp, err := c.client.ListDaemonSets(context.Background(), &daemonsetstore_protos.ListDaemonSetsRequest{})
 	if err != nil {
@@ -46,6 +66,7 @@ func (c Client) List() ([]fields.DaemonSet, error) {
 func (c Client) Disable(id fields.ID) (fields.DaemonSet, error) {
 	resp, err := c.client.DisableDaemonSet(context.Background(), &daemonsetstore_protos.DisableDaemonSetRequest{
 		DaemonSetId: id.String(),
+		User:        FarmUser,
 	})
 	if err != nil {
 		return fields.DaemonSet{}, util.Errorf("disable daemon set grpc for %s failed: %s", id, err)
@@ -59,6 +80,159 @@ func (c Client) Disable(id fields.ID) (fields.DaemonSet, error) {
 	return ds, nil
 }
 
+func (c Client) Create(
+	ctx context.Context,
+	manifest manifest.Manifest,
+	minHealth int,
+	name fields.ClusterName,
+	nodeSelector klabels.Selector,
+	podID types.PodID,
+	timeout time.Duration,
+	user string,
+) (fields.DaemonSet, error) {
+	manifestBytes, err := manifest.Marshal()
+	if err != nil {
+		return fields.DaemonSet{}, util.Errorf("could not marshal manifest: %s", err)
+	}
+
+	resp, err := c.client.CreateDaemonSet(ctx, &daemonsetstore_protos.CreateDaemonSetRequest{
+		Manifest:     string(manifestBytes),
+		MinHealth:    int64(minHealth),
+		Name:         name.String(),
+		NodeSelector: nodeSelector.String(),
+		PodId:        podID.String(),
+		Timeout:      timeout.Nanoseconds(),
+		User:         user,
+	})
+	if err != nil {
+		return fields.DaemonSet{}, util.Errorf("create daemon set grpc failed: %s", err)
+	}
+
+	ds, err := daemonsetstore.ProtoDSToRawDS(resp.DaemonSet)
+	if err != nil {
+		return fields.DaemonSet{}, util.Errorf("creating succeeded but could not convert from grpc proto type to DaemonSet: %s", err)
+	}
+
+	return ds, nil
+}
+
+func (c Client) Get(ctx context.Context, id fields.ID) (fields.DaemonSet, error) {
+	resp, err := c.client.GetDaemonSet(ctx, &daemonsetstore_protos.GetDaemonSetRequest{
+		DaemonSetId: id.String(),
+	})
+	if err != nil {
+		return fields.DaemonSet{}, util.Errorf("get daemon set grpc for %s failed: %s", id, err)
+	}
+
+	ds, err := daemonsetstore.ProtoDSToRawDS(resp.DaemonSet)
+	if err != nil {
+		return fields.DaemonSet{}, util.Errorf("could not convert from grpc proto type to DaemonSet: %s", err)
+	}
+
+	return ds, nil
+}
+
+func (c Client) Enable(ctx context.Context, id fields.ID, user string) (fields.DaemonSet, error) {
+	resp, err := c.client.EnableDaemonSet(ctx, &daemonsetstore_protos.EnableDaemonSetRequest{
+		DaemonSetId: id.String(),
+		User:        user,
+	})
+	if err != nil {
+		return fields.DaemonSet{}, util.Errorf("enable daemon set grpc for %s failed: %s", id, err)
+	}
+
+	return updatedDS("enabling", resp.DaemonSet)
+}
+
+func (c Client) Delete(ctx context.Context, id fields.ID, user string) error {
+	_, err := c.client.DeleteDaemonSet(ctx, &daemonsetstore_protos.DeleteDaemonSetRequest{
+		DaemonSetId: id.String(),
+		User:        user,
+	})
+	if err != nil {
+		return util.Errorf("delete daemon set grpc for %s failed: %s", id, err)
+	}
+
+	return nil
+}
+
+func (c Client) UpdateManifest(ctx context.Context, id fields.ID, manifest manifest.Manifest, user string) (fields.DaemonSet, error) {
+	manifestBytes, err := manifest.Marshal()
+	if err != nil {
+		return fields.DaemonSet{}, util.Errorf("could not marshal manifest: %s", err)
+	}
+
+	resp, err := c.client.UpdateManifest(ctx, &daemonsetstore_protos.UpdateManifestRequest{
+		DaemonSetId: id.String(),
+		Manifest:    string(manifestBytes),
+		User:        user,
+	})
+	if err != nil {
+		return fields.DaemonSet{}, util.Errorf("update manifest grpc for %s failed: %s", id, err)
+	}
+
+	return updatedDS("updating the manifest", resp.DaemonSet)
+}
+
+func (c Client) UpdateNodeSelector(ctx context.Context, id fields.ID, nodeSelector klabels.Selector, user string) (fields.DaemonSet, error) {
+	resp, err := c.client.UpdateNodeSelector(ctx, &daemonsetstore_protos.UpdateNodeSelectorRequest{
+		DaemonSetId:  id.String(),
+		NodeSelector: nodeSelector.String(),
+		User:         user,
+	})
+	if err != nil {
+		return fields.DaemonSet{}, util.Errorf("update node selector grpc for %s failed: %s", id, err)
+	}
+
+	return updatedDS("updating the node selector", resp.DaemonSet)
+}
+
+func (c Client) UpdateMinHealth(ctx context.Context, id fields.ID, minHealth int, user string) (fields.DaemonSet, error) {
+	resp, err := c.client.UpdateMinHealth(ctx, &daemonsetstore_protos.UpdateMinHealthRequest{
+		DaemonSetId: id.String(),
+		MinHealth:   int64(minHealth),
+		User:        user,
+	})
+	if err != nil {
+		return fields.DaemonSet{}, util.Errorf("update min health grpc for %s failed: %s", id, err)
+	}
+
+	return updatedDS("updating the min health", resp.DaemonSet)
+}
+
+func (c Client) UpdateTimeout(ctx context.Context, id fields.ID, timeout time.Duration, user string) (fields.DaemonSet, error) {
+	resp, err := c.client.UpdateTimeout(ctx, &daemonsetstore_protos.UpdateTimeoutRequest{
+		DaemonSetId: id.String(),
+		Timeout:     timeout.Nanoseconds(),
+		User:        user,
+	})
+	if err != nil {
+		return fields.DaemonSet{}, util.Errorf("update timeout grpc for %s failed: %s", id, err)
+	}
+
+	return updatedDS("updating the timeout", resp.DaemonSet)
+}
+
+func (c Client) GetStatus(ctx context.Context, id fields.ID) (daemonsetstatus.Status, error) {
+	resp, err := c.client.GetDaemonSetStatus(ctx, &daemonsetstore_protos.GetDaemonSetStatusRequest{
+		DaemonSetId: id.String(),
+	})
+	if err != nil {
+		return daemonsetstatus.Status{}, util.Errorf("get status grpc for %s failed: %s", id, err)
+	}
+
+	return daemonsetstore.ProtoToDSStatus(resp), nil
+}
+
+func updatedDS(action string, dsProto *daemonsetstore_protos.DaemonSet) (fields.DaemonSet, error) {
+	ds, err := daemonsetstore.ProtoDSToRawDS(dsProto)
+	if err != nil {
+		return fields.DaemonSet{}, util.Errorf("%s succeeded but could not convert from grpc proto type to DaemonSet: %s", action, err)
+	}
+
+	return ds, nil
+}
+
 // TODO: pass a context here instead of a <-chan struct{}. It's like this so it matches the consul
 // implementation
 func (c Client) Watch(quitCh <-chan struct{}) <-chan dsstore.WatchedDaemonSets {
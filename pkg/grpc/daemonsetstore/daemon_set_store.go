@@ -1,15 +1,20 @@
 package daemonsetstore
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/square/p2/pkg/ds/fields"
 	daemonsetstore_protos "github.com/square/p2/pkg/grpc/daemonsetstore/protos"
 	"github.com/square/p2/pkg/manifest"
 	"github.com/square/p2/pkg/store/consul/dsstore"
+	"github.com/square/p2/pkg/store/consul/statusstore"
+	"github.com/square/p2/pkg/store/consul/statusstore/daemonsetstatus"
+	"github.com/square/p2/pkg/store/consul/transaction"
 	"github.com/square/p2/pkg/types"
 	"github.com/square/p2/pkg/util"
 
+	"github.com/hashicorp/consul/api"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -18,17 +23,61 @@ import (
 
 type ConsulStore interface {
 	List() ([]fields.DaemonSet, error)
-	Disable(id fields.ID) (fields.DaemonSet, error)
+	Get(id fields.ID) (fields.DaemonSet, *api.QueryMeta, error)
 	Watch(quitCh <-chan struct{}) <-chan dsstore.WatchedDaemonSets
 }
 
+// AuditingStore makes the changes requested by users, recording an audit log
+// for each of them in the same transaction. It is satisfied by
+// dsstore.AuditingStore
+type AuditingStore interface {
+	MirroredAuditingStore
+
+	// Disable is not mirrored by the client, whose Disable is the one the
+	// daemon set farm uses
+	Disable(ctx context.Context, id fields.ID, user string) (fields.DaemonSet, error)
+}
+
+// MirroredAuditingStore is the part of AuditingStore that the gRPC client
+// implements as well
+type MirroredAuditingStore interface {
+	Create(
+		ctx context.Context,
+		manifest manifest.Manifest,
+		minHealth int,
+		name fields.ClusterName,
+		nodeSelector klabels.Selector,
+		podID types.PodID,
+		timeout time.Duration,
+		user string,
+	) (fields.DaemonSet, error)
+	Enable(ctx context.Context, id fields.ID, user string) (fields.DaemonSet, error)
+	Delete(ctx context.Context, id fields.ID, user string) error
+	UpdateManifest(ctx context.Context, id fields.ID, manifest manifest.Manifest, user string) (fields.DaemonSet, error)
+	UpdateNodeSelector(ctx context.Context, id fields.ID, nodeSelector klabels.Selector, user string) (fields.DaemonSet, error)
+	UpdateMinHealth(ctx context.Context, id fields.ID, minHealth int, user string) (fields.DaemonSet, error)
+	UpdateTimeout(ctx context.Context, id fields.ID, timeout time.Duration, user string) (fields.DaemonSet, error)
+}
+
+var _ AuditingStore = dsstore.AuditingStore{}
+
+type DSStatusStore interface {
+	Get(dsID fields.ID) (daemonsetstatus.Status, *api.QueryMeta, error)
+}
+
 type Store struct {
-	consulStore ConsulStore
+	consulStore   ConsulStore
+	auditingStore AuditingStore
+	statusStore   DSStatusStore
+	txner         transaction.Txner
 }
 
-func NewServer(consulStore ConsulStore) Store {
+func NewServer(consulStore ConsulStore, auditingStore AuditingStore, statusStore DSStatusStore, txner transaction.Txner) Store {
 	return Store{
-		consulStore: consulStore,
+		consulStore:   consulStore,
+		auditingStore: auditingStore,
+		statusStore:   statusStore,
+		txner:         txner,
 	}
 }
 
@@ -53,28 +102,18 @@ func (s Store) ListDaemonSets(_ context.Context, _ *daemonsetstore_protos.ListDa
 	return ret, nil
 }
 
-func (s Store) DisableDaemonSet(_ context.Context, req *daemonsetstore_protos.DisableDaemonSetRequest) (*daemonsetstore_protos.DisableDaemonSetResponse, error) {
-	id, err := fields.ToDaemonSetID(req.DaemonSetId)
-	if err != nil {
-		return nil, grpc.Errorf(codes.InvalidArgument, err.Error())
-	}
-
-	ds, err := s.consulStore.Disable(id)
+func (s Store) DisableDaemonSet(ctx context.Context, req *daemonsetstore_protos.DisableDaemonSetRequest) (*daemonsetstore_protos.DisableDaemonSetResponse, error) {
+	id, err := parseUpdate(req.DaemonSetId, req.User)
 	if err != nil {
-		if err == dsstore.NoDaemonSet {
-			return nil, grpc.Errorf(codes.NotFound, "no daemon set with id %s was found", id)
-		}
-
-		return nil, grpc.Errorf(codes.Unavailable, "could not disable daemon set %s: %s", id, err)
+		return nil, err
 	}
 
-	dsProto, err := RawDSToProtoDS(ds)
+	dsProto, err := s.update(ctx, id, "disable", func(trxctx context.Context) (fields.DaemonSet, error) {
+		return s.auditingStore.Disable(trxctx, id, req.User)
+	})
 	if err != nil {
-		// this is awkward because the disable already worked, but it's
-		// idempotent. This also shouldn't happen
-		return nil, grpc.Errorf(codes.Unavailable, "disable succeeded, but could not convert daemon set to proto type: %s", err)
+		return nil, err
 	}
-
 	return &daemonsetstore_protos.DisableDaemonSetResponse{
 		DaemonSet: dsProto,
 	}, nil
@@ -100,21 +139,339 @@ func (s Store) WatchDaemonSets(_ *daemonsetstore_protos.WatchDaemonSetsRequest,
 	return nil
 }
 
+func (s Store) CreateDaemonSet(ctx context.Context, req *daemonsetstore_protos.CreateDaemonSetRequest) (*daemonsetstore_protos.CreateDaemonSetResponse, error) {
+	if req.User == "" {
+		return nil, grpc.Errorf(codes.InvalidArgument, "user must be provided")
+	}
+	if req.Name == "" {
+		return nil, grpc.Errorf(codes.InvalidArgument, "name must be provided")
+	}
+	if req.MinHealth < 0 {
+		return nil, grpc.Errorf(codes.InvalidArgument, "min_health must not be negative")
+	}
+	if req.Timeout < 0 {
+		return nil, grpc.Errorf(codes.InvalidArgument, "timeout must not be negative")
+	}
+
+	manifest, err := parseManifest(req.Manifest)
+	if err != nil {
+		return nil, err
+	}
+	if req.PodId != manifest.ID().String() {
+		return nil, grpc.Errorf(codes.InvalidArgument, "pod_id %q must match the manifest's pod ID %q", req.PodId, manifest.ID())
+	}
+
+	nodeSelector, err := klabels.Parse(req.NodeSelector)
+	if err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, "could not parse node selector: %s", err)
+	}
+
+	trxctx, cancelFunc := transaction.New(ctx)
+	defer cancelFunc()
+	ds, err := s.auditingStore.Create(
+		trxctx,
+		manifest,
+		int(req.MinHealth),
+		fields.ClusterName(req.Name),
+		nodeSelector,
+		types.PodID(req.PodId),
+		time.Duration(req.Timeout),
+		req.User,
+	)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Unavailable, "could not create daemon set: %s", err)
+	}
+
+	err = transaction.MustCommit(trxctx, s.txner)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Unavailable, "could not create daemon set: %s", err)
+	}
+
+	dsProto, err := RawDSToProtoDS(ds)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Unavailable, "create succeeded, but could not convert daemon set to proto type: %s", err)
+	}
+	return &daemonsetstore_protos.CreateDaemonSetResponse{
+		DaemonSet: dsProto,
+	}, nil
+}
+
+func (s Store) GetDaemonSet(_ context.Context, req *daemonsetstore_protos.GetDaemonSetRequest) (*daemonsetstore_protos.GetDaemonSetResponse, error) {
+	id, err := fields.ToDaemonSetID(req.DaemonSetId)
+	if err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, err.Error())
+	}
+
+	ds, _, err := s.consulStore.Get(id)
+	if err != nil {
+		if err == dsstore.NoDaemonSet {
+			return nil, grpc.Errorf(codes.NotFound, "no daemon set with id %s was found", id)
+		}
+
+		return nil, grpc.Errorf(codes.Unavailable, "could not get daemon set %s: %s", id, err)
+	}
+
+	dsProto, err := RawDSToProtoDS(ds)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Unavailable, err.Error())
+	}
+	return &daemonsetstore_protos.GetDaemonSetResponse{
+		DaemonSet: dsProto,
+	}, nil
+}
+
+func (s Store) EnableDaemonSet(ctx context.Context, req *daemonsetstore_protos.EnableDaemonSetRequest) (*daemonsetstore_protos.EnableDaemonSetResponse, error) {
+	id, err := parseUpdate(req.DaemonSetId, req.User)
+	if err != nil {
+		return nil, err
+	}
+
+	dsProto, err := s.update(ctx, id, "enable", func(trxctx context.Context) (fields.DaemonSet, error) {
+		return s.auditingStore.Enable(trxctx, id, req.User)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &daemonsetstore_protos.EnableDaemonSetResponse{
+		DaemonSet: dsProto,
+	}, nil
+}
+
+func (s Store) DeleteDaemonSet(ctx context.Context, req *daemonsetstore_protos.DeleteDaemonSetRequest) (*daemonsetstore_protos.DeleteDaemonSetResponse, error) {
+	id, err := parseUpdate(req.DaemonSetId, req.User)
+	if err != nil {
+		return nil, err
+	}
+
+	// The auditing store refuses to delete a daemon set that doesn't exist,
+	// so check for that first in order to return NotFound
+	_, _, err = s.consulStore.Get(id)
+	if err == dsstore.NoDaemonSet {
+		return nil, grpc.Errorf(codes.NotFound, "no daemon set with id %s was found", id)
+	} else if err != nil {
+		return nil, grpc.Errorf(codes.Unavailable, "could not get daemon set %s: %s", id, err)
+	}
+
+	trxctx, cancelFunc := transaction.New(ctx)
+	defer cancelFunc()
+	err = s.auditingStore.Delete(trxctx, id, req.User)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Unavailable, "could not delete daemon set %s: %s", id, err)
+	}
+
+	err = transaction.MustCommit(trxctx, s.txner)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Unavailable, "could not delete daemon set %s: %s", id, err)
+	}
+
+	return &daemonsetstore_protos.DeleteDaemonSetResponse{}, nil
+}
+
+func (s Store) UpdateManifest(ctx context.Context, req *daemonsetstore_protos.UpdateManifestRequest) (*daemonsetstore_protos.UpdateManifestResponse, error) {
+	id, err := parseUpdate(req.DaemonSetId, req.User)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := parseManifest(req.Manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	// check the pod ID here so that a mismatch is reported as an invalid
+	// argument rather than a failure to update the daemon set
+	ds, _, err := s.consulStore.Get(id)
+	if err == dsstore.NoDaemonSet {
+		return nil, grpc.Errorf(codes.NotFound, "no daemon set with id %s was found", id)
+	} else if err != nil {
+		return nil, grpc.Errorf(codes.Unavailable, "could not get daemon set %s: %s", id, err)
+	}
+	if manifest.ID() != ds.PodID {
+		return nil, grpc.Errorf(codes.InvalidArgument, "manifest's pod ID %q must match the daemon set's pod ID %q", manifest.ID(), ds.PodID)
+	}
+
+	dsProto, err := s.update(ctx, id, "update the manifest of", func(trxctx context.Context) (fields.DaemonSet, error) {
+		return s.auditingStore.UpdateManifest(trxctx, id, manifest, req.User)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &daemonsetstore_protos.UpdateManifestResponse{
+		DaemonSet: dsProto,
+	}, nil
+}
+
+func (s Store) UpdateNodeSelector(ctx context.Context, req *daemonsetstore_protos.UpdateNodeSelectorRequest) (*daemonsetstore_protos.UpdateNodeSelectorResponse, error) {
+	id, err := parseUpdate(req.DaemonSetId, req.User)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeSelector, err := klabels.Parse(req.NodeSelector)
+	if err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, "could not parse node selector: %s", err)
+	}
+
+	dsProto, err := s.update(ctx, id, "update the node selector of", func(trxctx context.Context) (fields.DaemonSet, error) {
+		return s.auditingStore.UpdateNodeSelector(trxctx, id, nodeSelector, req.User)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &daemonsetstore_protos.UpdateNodeSelectorResponse{
+		DaemonSet: dsProto,
+	}, nil
+}
+
+func (s Store) UpdateMinHealth(ctx context.Context, req *daemonsetstore_protos.UpdateMinHealthRequest) (*daemonsetstore_protos.UpdateMinHealthResponse, error) {
+	id, err := parseUpdate(req.DaemonSetId, req.User)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.MinHealth < 0 {
+		return nil, grpc.Errorf(codes.InvalidArgument, "min_health must not be negative")
+	}
+
+	dsProto, err := s.update(ctx, id, "update the min health of", func(trxctx context.Context) (fields.DaemonSet, error) {
+		return s.auditingStore.UpdateMinHealth(trxctx, id, int(req.MinHealth), req.User)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &daemonsetstore_protos.UpdateMinHealthResponse{
+		DaemonSet: dsProto,
+	}, nil
+}
+
+func (s Store) UpdateTimeout(ctx context.Context, req *daemonsetstore_protos.UpdateTimeoutRequest) (*daemonsetstore_protos.UpdateTimeoutResponse, error) {
+	id, err := parseUpdate(req.DaemonSetId, req.User)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Timeout < 0 {
+		return nil, grpc.Errorf(codes.InvalidArgument, "timeout must not be negative")
+	}
+
+	dsProto, err := s.update(ctx, id, "update the timeout of", func(trxctx context.Context) (fields.DaemonSet, error) {
+		return s.auditingStore.UpdateTimeout(trxctx, id, time.Duration(req.Timeout), req.User)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &daemonsetstore_protos.UpdateTimeoutResponse{
+		DaemonSet: dsProto,
+	}, nil
+}
+
+func (s Store) GetDaemonSetStatus(_ context.Context, req *daemonsetstore_protos.GetDaemonSetStatusRequest) (*daemonsetstore_protos.DaemonSetStatus, error) {
+	id, err := fields.ToDaemonSetID(req.DaemonSetId)
+	if err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, err.Error())
+	}
+
+	status, _, err := s.statusStore.Get(id)
+	if statusstore.IsNoStatus(err) {
+		return nil, grpc.Errorf(codes.NotFound, "no status found for daemon set %s", id)
+	} else if err != nil {
+		return nil, grpc.Errorf(codes.Unavailable, "could not fetch status for daemon set %s: %s", id, err)
+	}
+
+	return DSStatusToProto(status), nil
+}
+
+// update runs one of the auditing store's mutations in a transaction and
+// returns the resulting daemon set. action describes the mutation for error
+// messages
+func (s Store) update(ctx context.Context, id fields.ID, action string, mutate func(context.Context) (fields.DaemonSet, error)) (*daemonsetstore_protos.DaemonSet, error) {
+	trxctx, cancelFunc := transaction.New(ctx)
+	defer cancelFunc()
+	ds, err := mutate(trxctx)
+	if err != nil {
+		if err == dsstore.NoDaemonSet {
+			return nil, grpc.Errorf(codes.NotFound, "no daemon set with id %s was found", id)
+		}
+
+		return nil, grpc.Errorf(codes.Unavailable, "could not %s daemon set %s: %s", action, id, err)
+	}
+
+	err = transaction.MustCommit(trxctx, s.txner)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Unavailable, "could not %s daemon set %s: %s", action, id, err)
+	}
+
+	dsProto, err := RawDSToProtoDS(ds)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Unavailable, "update succeeded, but could not convert daemon set to proto type: %s", err)
+	}
+	return dsProto, nil
+}
+
+// parseUpdate validates the fields common to requests that modify a daemon
+// set
+func parseUpdate(dsID string, user string) (fields.ID, error) {
+	id, err := fields.ToDaemonSetID(dsID)
+	if err != nil {
+		return "", grpc.Errorf(codes.InvalidArgument, err.Error())
+	}
+	if user == "" {
+		return "", grpc.Errorf(codes.InvalidArgument, "user must be provided")
+	}
+	return id, nil
+}
+
+func parseManifest(manifestStr string) (manifest.Manifest, error) {
+	if manifestStr == "" {
+		return nil, grpc.Errorf(codes.InvalidArgument, "manifest must be provided")
+	}
+	manifest, err := manifest.FromBytes([]byte(manifestStr))
+	if err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, "could not parse passed manifest: %s", err)
+	}
+	return manifest, nil
+}
+
 func RawDSToProtoDS(rawDS fields.DaemonSet) (*daemonsetstore_protos.DaemonSet, error) {
 	manifest, err := rawDS.Manifest.Marshal()
 	if err != nil {
 		return nil, util.Errorf("could not convert daemon set %s's manifest to string for daemon set proto: %s", rawDS.ID, err)
 	}
 
+	var rollingStrategy *daemonsetstore_protos.RollingStrategy
+	if rawDS.RollingStrategy != nil {
+		rollingStrategy = &daemonsetstore_protos.RollingStrategy{
+			MaxUnavailable: string(rawDS.RollingStrategy.MaxUnavailable),
+			MaxSurge:       string(rawDS.RollingStrategy.MaxSurge),
+			BatchDelay:     rawDS.RollingStrategy.BatchDelay.Nanoseconds(),
+		}
+	}
+
+	overrides := make([]*daemonsetstore_protos.ManifestOverride, len(rawDS.Overrides))
+	for i, override := range rawDS.Overrides {
+		rawOverride := override.ToRaw()
+		patch, err := json.Marshal(rawOverride.Patch)
+		if err != nil {
+			return nil, util.Errorf("could not convert daemon set %s's overrides to JSON for daemon set proto: %s", rawDS.ID, err)
+		}
+		overrides[i] = &daemonsetstore_protos.ManifestOverride{
+			NodeSelector: rawOverride.NodeSelector,
+			Patch:        string(patch),
+		}
+	}
+
 	return &daemonsetstore_protos.DaemonSet{
-		Id:           rawDS.ID.String(),
-		Disabled:     rawDS.Disabled,
-		Manifest:     string(manifest),
-		MinHealth:    int64(rawDS.MinHealth),
-		Name:         rawDS.Name.String(),
-		NodeSelector: rawDS.NodeSelector.String(),
-		PodId:        rawDS.PodID.String(),
-		Timeout:      rawDS.Timeout.Nanoseconds(),
+		Id:               rawDS.ID.String(),
+		Disabled:         rawDS.Disabled,
+		Manifest:         string(manifest),
+		MinHealth:        int64(rawDS.MinHealth),
+		Name:             rawDS.Name.String(),
+		NodeSelector:     rawDS.NodeSelector.String(),
+		PodId:            rawDS.PodID.String(),
+		Timeout:          rawDS.Timeout.Nanoseconds(),
+		RollingStrategy:  rollingStrategy,
+		FailureThreshold: string(rawDS.FailureThreshold),
+		Overrides:        overrides,
 	}, nil
 }
 
@@ -129,18 +486,137 @@ func ProtoDSToRawDS(protoDS *daemonsetstore_protos.DaemonSet) (fields.DaemonSet,
 		return fields.DaemonSet{}, util.Errorf("could not convert daemon set proto to raw daemon set: %s", err)
 	}
 
+	var rollingStrategy *fields.RollingStrategy
+	if protoStrategy := protoDS.GetRollingStrategy(); protoStrategy != nil {
+		rollingStrategy = &fields.RollingStrategy{
+			MaxUnavailable: fields.IntOrPercent(protoStrategy.GetMaxUnavailable()),
+			MaxSurge:       fields.IntOrPercent(protoStrategy.GetMaxSurge()),
+			BatchDelay:     time.Duration(protoStrategy.GetBatchDelay()),
+		}
+	}
+
+	var overrides []fields.ManifestOverride
+	for _, protoOverride := range protoDS.GetOverrides() {
+		rawOverride := fields.RawManifestOverride{
+			NodeSelector: protoOverride.GetNodeSelector(),
+		}
+		err = json.Unmarshal([]byte(protoOverride.GetPatch()), &rawOverride.Patch)
+		if err != nil {
+			return fields.DaemonSet{}, util.Errorf("could not convert daemon set proto to raw daemon set: %s", err)
+		}
+		override, err := rawOverride.ToOverride()
+		if err != nil {
+			return fields.DaemonSet{}, util.Errorf("could not convert daemon set proto to raw daemon set: %s", err)
+		}
+		overrides = append(overrides, override)
+	}
+
 	return fields.DaemonSet{
-		ID:           fields.ID(protoDS.GetId()),
-		Disabled:     protoDS.GetDisabled(),
-		Manifest:     manifest,
-		MinHealth:    int(protoDS.GetMinHealth()),
-		Name:         fields.ClusterName(protoDS.GetName()),
-		NodeSelector: selector,
-		PodID:        types.PodID(protoDS.GetPodId()),
-		Timeout:      time.Duration(protoDS.GetTimeout()),
+		ID:               fields.ID(protoDS.GetId()),
+		Disabled:         protoDS.GetDisabled(),
+		Manifest:         manifest,
+		MinHealth:        int(protoDS.GetMinHealth()),
+		Name:             fields.ClusterName(protoDS.GetName()),
+		NodeSelector:     selector,
+		PodID:            types.PodID(protoDS.GetPodId()),
+		Timeout:          time.Duration(protoDS.GetTimeout()),
+		RollingStrategy:  rollingStrategy,
+		FailureThreshold: fields.IntOrPercent(protoDS.GetFailureThreshold()),
+		Overrides:        overrides,
 	}, nil
 }
 
+func DSStatusToProto(status daemonsetstatus.Status) *daemonsetstore_protos.DaemonSetStatus {
+	return &daemonsetstore_protos.DaemonSetStatus{
+		ManifestSha:           status.ManifestSHA,
+		NodesDeployed:         int64(status.NodesDeployed),
+		ReplicationInProgress: status.ReplicationInProgress,
+		Rolling: &daemonsetstore_protos.RollingStatus{
+			BatchesCompleted: int64(status.Rolling.BatchesCompleted),
+			BatchSize:        int64(status.Rolling.BatchSize),
+			NodesRemaining:   int64(status.Rolling.NodesRemaining),
+			Unavailable:      int64(status.Rolling.Unavailable),
+			BlockingReason:   status.Rolling.BlockingReason,
+			LastBatchTime:    timeToProto(status.Rolling.LastBatchTime),
+		},
+		FailureBudgetExceeded: status.FailureBudgetExceeded,
+		FailedNodes:           nodesToProto(status.FailedNodes),
+		Nodes: &daemonsetstore_protos.NodeStatus{
+			Eligible:  int64(status.Nodes.Eligible),
+			Scheduled: int64(status.Nodes.Scheduled),
+			Current:   int64(status.Nodes.Current),
+			Healthy:   int64(status.Nodes.Healthy),
+			Unhealthy: int64(status.Nodes.Unhealthy),
+			Pending:   int64(status.Nodes.Pending),
+			OldSha:    nodesToProto(status.Nodes.OldSHA),
+		},
+		ContendsWith: status.ContendsWith.String(),
+	}
+}
+
+func ProtoToDSStatus(proto *daemonsetstore_protos.DaemonSetStatus) daemonsetstatus.Status {
+	status := daemonsetstatus.Status{
+		ManifestSHA:           proto.GetManifestSha(),
+		NodesDeployed:         int(proto.GetNodesDeployed()),
+		ReplicationInProgress: proto.GetReplicationInProgress(),
+		FailureBudgetExceeded: proto.GetFailureBudgetExceeded(),
+		FailedNodes:           protoToNodes(proto.GetFailedNodes()),
+		ContendsWith:          fields.ID(proto.GetContendsWith()),
+	}
+	if rolling := proto.GetRolling(); rolling != nil {
+		status.Rolling = daemonsetstatus.RollingStatus{
+			BatchesCompleted: int(rolling.GetBatchesCompleted()),
+			BatchSize:        int(rolling.GetBatchSize()),
+			NodesRemaining:   int(rolling.GetNodesRemaining()),
+			Unavailable:      int(rolling.GetUnavailable()),
+			BlockingReason:   rolling.GetBlockingReason(),
+			LastBatchTime:    protoToTime(rolling.GetLastBatchTime()),
+		}
+	}
+	if nodes := proto.GetNodes(); nodes != nil {
+		status.Nodes = daemonsetstatus.NodeStatus{
+			Eligible:  int(nodes.GetEligible()),
+			Scheduled: int(nodes.GetScheduled()),
+			Current:   int(nodes.GetCurrent()),
+			Healthy:   int(nodes.GetHealthy()),
+			Unhealthy: int(nodes.GetUnhealthy()),
+			Pending:   int(nodes.GetPending()),
+			OldSHA:    protoToNodes(nodes.GetOldSha()),
+		}
+	}
+	return status
+}
+
+func nodesToProto(nodes []types.NodeName) []string {
+	var ret []string
+	for _, node := range nodes {
+		ret = append(ret, node.String())
+	}
+	return ret
+}
+
+func protoToNodes(nodes []string) []types.NodeName {
+	var ret []types.NodeName
+	for _, node := range nodes {
+		ret = append(ret, types.NodeName(node))
+	}
+	return ret
+}
+
+func timeToProto(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+func protoToTime(nanos int64) time.Time {
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
+
 func watchedDaemonSetsToResp(watchedDaemonSets dsstore.WatchedDaemonSets) (*daemonsetstore_protos.WatchDaemonSetsResponse, error) {
 	created := make([]*daemonsetstore_protos.DaemonSet, len(watchedDaemonSets.Created))
 	for i, ds := range watchedDaemonSets.Created {
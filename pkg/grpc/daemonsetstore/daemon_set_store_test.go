@@ -4,20 +4,26 @@ package daemonsetstore
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 	"time"
 
+	"github.com/square/p2/pkg/audit"
 	"github.com/square/p2/pkg/ds/fields"
 	daemonsetstore_protos "github.com/square/p2/pkg/grpc/daemonsetstore/protos"
 	"github.com/square/p2/pkg/grpc/testutil"
 	"github.com/square/p2/pkg/logging"
 	"github.com/square/p2/pkg/manifest"
+	"github.com/square/p2/pkg/store/consul/auditlogstore"
 	"github.com/square/p2/pkg/store/consul/consulutil"
 	"github.com/square/p2/pkg/store/consul/dsstore"
+	"github.com/square/p2/pkg/store/consul/statusstore"
+	"github.com/square/p2/pkg/store/consul/statusstore/daemonsetstatus"
 	"github.com/square/p2/pkg/store/consul/transaction"
 	"github.com/square/p2/pkg/types"
 
 	"github.com/gofrs/uuid"
+	"github.com/hashicorp/consul/api"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	klabels "k8s.io/kubernetes/pkg/labels"
@@ -32,7 +38,7 @@ func TestListDaemonSets(t *testing.T) {
 		t.Fatalf("could not seed daemon set store with a daemon set")
 	}
 
-	server := NewServer(dsStore)
+	server := NewServer(dsStore, nil, nil, nil)
 	resp, err := server.ListDaemonSets(context.Background(), &daemonsetstore_protos.ListDaemonSetsRequest{})
 	if err != nil {
 		t.Fatalf("error listing daemon sets: %s", err)
@@ -84,7 +90,7 @@ func TestListDaemonSets(t *testing.T) {
 func TestDisableDaemonSet(t *testing.T) {
 	fixture := consulutil.NewFixture(t)
 	defer fixture.Stop()
-	dsStore := dsstore.NewConsul(fixture.Client, 0, &logging.DefaultLogger)
+	server, dsStore := newTestServer(fixture)
 	daemonSet, err := createADaemonSet(dsStore, fixture.Client.KV())
 	if err != nil {
 		t.Fatal(err)
@@ -95,9 +101,9 @@ func TestDisableDaemonSet(t *testing.T) {
 		t.Fatal("daemon set already disabled")
 	}
 
-	server := NewServer(dsStore)
 	_, err = server.DisableDaemonSet(context.Background(), &daemonsetstore_protos.DisableDaemonSetRequest{
 		DaemonSetId: daemonSet.ID.String(),
+		User:        "some_user",
 	})
 	if err != nil {
 		t.Fatalf("error disabling daemon set: %s", err)
@@ -111,16 +117,18 @@ func TestDisableDaemonSet(t *testing.T) {
 	if !daemonSet.Disabled {
 		t.Error("daemon set wasn't disabled")
 	}
+
+	assertAuditLogs(t, fixture, "some_user", audit.DSDisabledEvent)
 }
 
 func TestDisableDaemonSetInvalidArgument(t *testing.T) {
 	fixture := consulutil.NewFixture(t)
 	defer fixture.Stop()
-	dsStore := dsstore.NewConsul(fixture.Client, 0, &logging.DefaultLogger)
-	server := NewServer(dsStore)
+	server, dsStore := newTestServer(fixture)
 
 	_, err := server.DisableDaemonSet(context.Background(), &daemonsetstore_protos.DisableDaemonSetRequest{
 		DaemonSetId: "bad daemon set ID",
+		User:        "some_user",
 	})
 	if err == nil {
 		t.Fatal("should have gotten an error passing a malformed daemon set ID to disable")
@@ -129,16 +137,27 @@ func TestDisableDaemonSetInvalidArgument(t *testing.T) {
 	if grpc.Code(err) != codes.InvalidArgument {
 		t.Errorf("should have gotten an invalid argument error but was %q", err)
 	}
+
+	daemonSet, err := createADaemonSet(dsStore, fixture.Client.KV())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = server.DisableDaemonSet(context.Background(), &daemonsetstore_protos.DisableDaemonSetRequest{
+		DaemonSetId: daemonSet.ID.String(),
+	})
+	if grpc.Code(err) != codes.InvalidArgument {
+		t.Errorf("should have gotten an invalid argument error disabling without a user but was %q", err)
+	}
 }
 
 func TestDisableDaemonSetNotFound(t *testing.T) {
 	fixture := consulutil.NewFixture(t)
 	defer fixture.Stop()
-	dsStore := dsstore.NewConsul(fixture.Client, 0, &logging.DefaultLogger)
-	server := NewServer(dsStore)
+	server, _ := newTestServer(fixture)
 
 	_, err := server.DisableDaemonSet(context.Background(), &daemonsetstore_protos.DisableDaemonSetRequest{
 		DaemonSetId: uuid.Must(uuid.NewV4()).String(),
+		User:        "some_user",
 	})
 	if err == nil {
 		t.Fatal("should have gotten an error passing a malformed daemon set ID to disable")
@@ -171,6 +190,9 @@ func newFakeDSWatcher(resultCh <-chan dsstore.WatchedDaemonSets) fakeDaemonSetWa
 }
 
 func (fakeDaemonSetWatcher) List() ([]fields.DaemonSet, error) { panic("List() not implemented") }
+func (fakeDaemonSetWatcher) Get(id fields.ID) (fields.DaemonSet, *api.QueryMeta, error) {
+	panic("Get() not implemented")
+}
 func (fakeDaemonSetWatcher) Disable(id fields.ID) (fields.DaemonSet, error) {
 	panic("Disable() not implemented")
 }
@@ -198,7 +220,7 @@ func (f fakeDaemonSetWatcher) Watch(quitCh <-chan struct{}) <-chan dsstore.Watch
 
 func TestWatchDaemonSets(t *testing.T) {
 	resultCh := make(chan dsstore.WatchedDaemonSets)
-	server := NewServer(newFakeDSWatcher(resultCh))
+	server := NewServer(newFakeDSWatcher(resultCh), nil, nil, nil)
 
 	respCh := make(chan *daemonsetstore_protos.WatchDaemonSetsResponse)
 	ctx, cancelFunc := context.WithCancel(context.Background())
@@ -322,6 +344,367 @@ func TestWatchDaemonSets(t *testing.T) {
 	}
 }
 
+func TestCreateAndGetDaemonSet(t *testing.T) {
+	fixture := consulutil.NewFixture(t)
+	defer fixture.Stop()
+	server, _ := newTestServer(fixture)
+
+	manifestBytes, err := validManifest().Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	createResp, err := server.CreateDaemonSet(context.Background(), &daemonsetstore_protos.CreateDaemonSetRequest{
+		Manifest:     string(manifestBytes),
+		MinHealth:    18,
+		Name:         "some_daemon_set",
+		NodeSelector: "foo=bar",
+		PodId:        "fooapp",
+		Timeout:      int64(time.Minute),
+		User:         "some_user",
+	})
+	if err != nil {
+		t.Fatalf("error creating daemon set: %s", err)
+	}
+
+	getResp, err := server.GetDaemonSet(context.Background(), &daemonsetstore_protos.GetDaemonSetRequest{
+		DaemonSetId: createResp.DaemonSet.Id,
+	})
+	if err != nil {
+		t.Fatalf("error getting created daemon set: %s", err)
+	}
+	ds := getResp.DaemonSet
+	if ds.MinHealth != 18 || ds.Name != "some_daemon_set" || ds.NodeSelector != "foo=bar" || ds.PodId != "fooapp" || ds.Timeout != int64(time.Minute) {
+		t.Errorf("daemon set was not created as requested: %+v", ds)
+	}
+
+	assertAuditLogs(t, fixture, "some_user", audit.DSCreatedEvent)
+}
+
+func TestCreateDaemonSetInvalidArgument(t *testing.T) {
+	fixture := consulutil.NewFixture(t)
+	defer fixture.Stop()
+	server, _ := newTestServer(fixture)
+
+	manifestBytes, err := validManifest().Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for desc, req := range map[string]*daemonsetstore_protos.CreateDaemonSetRequest{
+		"no user":           {Manifest: string(manifestBytes), Name: "some_daemon_set", PodId: "fooapp"},
+		"mismatched pod id": {Manifest: string(manifestBytes), Name: "some_daemon_set", PodId: "barapp", User: "some_user"},
+		"bad node selector": {Manifest: string(manifestBytes), Name: "some_daemon_set", PodId: "fooapp", NodeSelector: "=", User: "some_user"},
+		"no manifest":       {Name: "some_daemon_set", PodId: "fooapp", User: "some_user"},
+	} {
+		_, err := server.CreateDaemonSet(context.Background(), req)
+		if grpc.Code(err) != codes.InvalidArgument {
+			t.Errorf("%s: expected an invalid argument error but got %v", desc, err)
+		}
+	}
+
+	assertAuditLogs(t, fixture, "some_user")
+}
+
+func TestUpdateDaemonSet(t *testing.T) {
+	fixture := consulutil.NewFixture(t)
+	defer fixture.Stop()
+	server, dsStore := newTestServer(fixture)
+	daemonSet, err := createADaemonSet(dsStore, fixture.Client.KV())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = dsStore.Disable(daemonSet.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := daemonSet.ID.String()
+	ctx := context.Background()
+
+	_, err = server.EnableDaemonSet(ctx, &daemonsetstore_protos.EnableDaemonSetRequest{DaemonSetId: id, User: "some_user"})
+	if err != nil {
+		t.Fatalf("error enabling daemon set: %s", err)
+	}
+
+	builder := validManifest().GetBuilder()
+	err = builder.SetConfig(map[interface{}]interface{}{"threads": 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifestBytes, err := builder.GetManifest().Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = server.UpdateManifest(ctx, &daemonsetstore_protos.UpdateManifestRequest{DaemonSetId: id, Manifest: string(manifestBytes), User: "some_user"})
+	if err != nil {
+		t.Fatalf("error updating manifest: %s", err)
+	}
+
+	_, err = server.UpdateNodeSelector(ctx, &daemonsetstore_protos.UpdateNodeSelectorRequest{DaemonSetId: id, NodeSelector: "foo=baz", User: "some_user"})
+	if err != nil {
+		t.Fatalf("error updating node selector: %s", err)
+	}
+
+	_, err = server.UpdateMinHealth(ctx, &daemonsetstore_protos.UpdateMinHealthRequest{DaemonSetId: id, MinHealth: 50, User: "some_user"})
+	if err != nil {
+		t.Fatalf("error updating min health: %s", err)
+	}
+
+	resp, err := server.UpdateTimeout(ctx, &daemonsetstore_protos.UpdateTimeoutRequest{DaemonSetId: id, Timeout: int64(time.Hour), User: "some_user"})
+	if err != nil {
+		t.Fatalf("error updating timeout: %s", err)
+	}
+	if resp.DaemonSet.Timeout != int64(time.Hour) {
+		t.Errorf("expected the updated daemon set to be returned, got %+v", resp.DaemonSet)
+	}
+
+	daemonSet, _, err = dsStore.Get(daemonSet.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if daemonSet.Disabled {
+		t.Error("daemon set wasn't enabled")
+	}
+	if daemonSet.Manifest.GetConfig()["threads"] != 4 {
+		t.Errorf("manifest wasn't updated, config was %v", daemonSet.Manifest.GetConfig())
+	}
+	if daemonSet.NodeSelector.String() != "foo=baz" {
+		t.Errorf("expected node selector to be foo=baz but was %s", daemonSet.NodeSelector)
+	}
+	if daemonSet.MinHealth != 50 {
+		t.Errorf("expected min health to be 50 but was %d", daemonSet.MinHealth)
+	}
+	if daemonSet.Timeout != time.Hour {
+		t.Errorf("expected timeout to be %s but was %s", time.Hour, daemonSet.Timeout)
+	}
+
+	assertAuditLogs(
+		t,
+		fixture,
+		"some_user",
+		audit.DSEnabledEvent,
+		audit.DSManifestUpdatedEvent,
+		audit.DSNodeSelectorUpdatedEvent,
+		audit.DSModifiedEvent,
+		audit.DSModifiedEvent,
+	)
+}
+
+func TestUpdateDaemonSetErrors(t *testing.T) {
+	fixture := consulutil.NewFixture(t)
+	defer fixture.Stop()
+	server, dsStore := newTestServer(fixture)
+	daemonSet, err := createADaemonSet(dsStore, fixture.Client.KV())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	_, err = server.UpdateMinHealth(ctx, &daemonsetstore_protos.UpdateMinHealthRequest{DaemonSetId: daemonSet.ID.String(), MinHealth: 50})
+	if grpc.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected an invalid argument error updating without a user, got %v", err)
+	}
+
+	_, err = server.UpdateMinHealth(ctx, &daemonsetstore_protos.UpdateMinHealthRequest{DaemonSetId: uuid.Must(uuid.NewV4()).String(), MinHealth: 50, User: "some_user"})
+	if grpc.Code(err) != codes.NotFound {
+		t.Errorf("expected a not found error updating a nonexistent daemon set, got %v", err)
+	}
+
+	builder := validManifest().GetBuilder()
+	builder.SetID("barapp")
+	manifestBytes, err := builder.GetManifest().Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = server.UpdateManifest(ctx, &daemonsetstore_protos.UpdateManifestRequest{DaemonSetId: daemonSet.ID.String(), Manifest: string(manifestBytes), User: "some_user"})
+	if grpc.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected an invalid argument error updating to a manifest with a different pod ID, got %v", err)
+	}
+
+	assertAuditLogs(t, fixture, "some_user")
+}
+
+func TestDeleteDaemonSet(t *testing.T) {
+	fixture := consulutil.NewFixture(t)
+	defer fixture.Stop()
+	server, dsStore := newTestServer(fixture)
+	daemonSet, err := createADaemonSet(dsStore, fixture.Client.KV())
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := &daemonsetstore_protos.DeleteDaemonSetRequest{
+		DaemonSetId: daemonSet.ID.String(),
+		User:        "some_user",
+	}
+
+	_, err = server.DeleteDaemonSet(context.Background(), req)
+	if err != nil {
+		t.Fatalf("error deleting daemon set: %s", err)
+	}
+
+	_, err = server.GetDaemonSet(context.Background(), &daemonsetstore_protos.GetDaemonSetRequest{DaemonSetId: daemonSet.ID.String()})
+	if grpc.Code(err) != codes.NotFound {
+		t.Errorf("expected a not found error getting a deleted daemon set, got %v", err)
+	}
+
+	_, err = server.DeleteDaemonSet(context.Background(), req)
+	if grpc.Code(err) != codes.NotFound {
+		t.Errorf("expected a not found error deleting a deleted daemon set, got %v", err)
+	}
+
+	assertAuditLogs(t, fixture, "some_user", audit.DSDeletedEvent)
+}
+
+func TestGetDaemonSetStatus(t *testing.T) {
+	fixture := consulutil.NewFixture(t)
+	defer fixture.Stop()
+	server, _ := newTestServer(fixture)
+	id := fields.ID(uuid.Must(uuid.NewV4()).String())
+
+	_, err := server.GetDaemonSetStatus(context.Background(), &daemonsetstore_protos.GetDaemonSetStatusRequest{DaemonSetId: id.String()})
+	if grpc.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound for a missing status, got %v", err)
+	}
+
+	now := time.Now()
+	status := daemonsetstatus.Status{
+		ManifestSHA:           "abc123",
+		NodesDeployed:         3,
+		ReplicationInProgress: true,
+		Rolling: daemonsetstatus.RollingStatus{
+			BatchesCompleted: 1,
+			BatchSize:        2,
+			BlockingReason:   "waiting for batch delay",
+			LastBatchTime:    now,
+		},
+		FailedNodes: []types.NodeName{"node1"},
+		Nodes: daemonsetstatus.NodeStatus{
+			Eligible:  5,
+			Scheduled: 4,
+			Current:   3,
+			Healthy:   2,
+			Unhealthy: 1,
+			Pending:   1,
+			OldSHA:    []types.NodeName{"node2"},
+		},
+	}
+	ctx, cancel := transaction.New(context.Background())
+	defer cancel()
+	err = testStatusStore(fixture).SetTxn(ctx, id, status)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = transaction.MustCommit(ctx, fixture.Client.KV())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := server.GetDaemonSetStatus(context.Background(), &daemonsetstore_protos.GetDaemonSetStatusRequest{DaemonSetId: id.String()})
+	if err != nil {
+		t.Fatalf("unexpected error getting status: %s", err)
+	}
+	got := ProtoToDSStatus(resp)
+	if got.ManifestSHA != "abc123" || got.NodesDeployed != 3 || !got.ReplicationInProgress {
+		t.Errorf("status did not survive the round trip: %+v", got)
+	}
+	if got.Rolling.BatchSize != 2 || got.Rolling.BlockingReason != "waiting for batch delay" || !got.Rolling.LastBatchTime.Equal(now) {
+		t.Errorf("rolling status did not survive the round trip: %+v", got.Rolling)
+	}
+	if got.Nodes.Eligible != 5 || got.Nodes.Pending != 1 || len(got.Nodes.OldSHA) != 1 || got.Nodes.OldSHA[0] != "node2" {
+		t.Errorf("node status did not survive the round trip: %+v", got.Nodes)
+	}
+	if len(got.FailedNodes) != 1 || got.FailedNodes[0] != "node1" {
+		t.Errorf("expected node1 to have failed, got %v", got.FailedNodes)
+	}
+}
+
+func TestProtoDSRoundTrip(t *testing.T) {
+	nodeSelector, err := klabels.Parse("foo=bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	large, err := klabels.Parse("size=large")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := fields.DaemonSet{
+		ID:           fields.ID(uuid.Must(uuid.NewV4()).String()),
+		Manifest:     validManifest(),
+		NodeSelector: nodeSelector,
+		PodID:        "fooapp",
+		RollingStrategy: &fields.RollingStrategy{
+			MaxUnavailable: "10%",
+			MaxSurge:       "1",
+			BatchDelay:     time.Minute,
+		},
+		FailureThreshold: "5",
+		Overrides: []fields.ManifestOverride{
+			{NodeSelector: large, Patch: fields.ManifestPatch{Config: map[string]interface{}{"threads": 64}}},
+		},
+	}
+
+	proto, err := RawDSToProtoDS(ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ProtoDSToRawDS(proto)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.RollingStrategy == nil || *got.RollingStrategy != *ds.RollingStrategy {
+		t.Errorf("expected rolling strategy %+v, got %+v", ds.RollingStrategy, got.RollingStrategy)
+	}
+	if got.FailureThreshold != ds.FailureThreshold {
+		t.Errorf("expected failure threshold %q, got %q", ds.FailureThreshold, got.FailureThreshold)
+	}
+	if len(got.Overrides) != 1 || got.Overrides[0].NodeSelector.String() != "size=large" {
+		t.Fatalf("overrides did not survive the round trip: %+v", got.Overrides)
+	}
+	// the patch went through JSON, so numbers come back as float64
+	if threads := got.Overrides[0].Patch.Config["threads"]; threads != float64(64) {
+		t.Errorf("expected the override to set 64 threads, got %v", threads)
+	}
+}
+
+func newTestServer(fixture consulutil.Fixture) (Store, *dsstore.ConsulStore) {
+	dsStore := dsstore.NewConsul(fixture.Client, 0, &logging.DefaultLogger)
+	auditingStore := dsstore.NewAuditingStore(dsStore, auditlogstore.NewConsulStore(fixture.Client.KV()))
+	return NewServer(dsStore, auditingStore, testStatusStore(fixture), fixture.Client.KV()), dsStore
+}
+
+func testStatusStore(fixture consulutil.Fixture) daemonsetstatus.ConsulStore {
+	return daemonsetstatus.NewConsul(statusstore.NewConsul(fixture.Client), statusstore.Namespace("test_namespace"))
+}
+
+// assertAuditLogs checks that the audit log has a record by user for each of
+// the expected event types and no others
+func assertAuditLogs(t *testing.T, fixture consulutil.Fixture, user string, expected ...audit.EventType) {
+	auditLogs, err := auditlogstore.NewConsulStore(fixture.Client.KV()).List()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counts := make(map[audit.EventType]int)
+	for _, auditLog := range auditLogs {
+		var details audit.DSEventDetails
+		err = json.Unmarshal(*auditLog.EventDetails, &details)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if details.User != user {
+			t.Errorf("expected audit log to be attributed to %s but was %s", user, details.User)
+		}
+		counts[auditLog.EventType]++
+	}
+	for _, eventType := range expected {
+		counts[eventType]--
+	}
+	for eventType, count := range counts {
+		if count != 0 {
+			t.Errorf("expected %d more %s audit logs than there were", -count, eventType)
+		}
+	}
+}
+
 func validManifest() manifest.Manifest {
 	builder := manifest.NewBuilder()
 	builder.SetID("fooapp")
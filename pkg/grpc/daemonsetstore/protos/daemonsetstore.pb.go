@@ -1,22 +1,6 @@
-// Code generated by protoc-gen-go.
+// Code generated by protoc-gen-go. DO NOT EDIT.
 // source: pkg/grpc/daemonsetstore/protos/daemonsetstore.proto
-// DO NOT EDIT!
-
-/*
-Package daemonsetstore is a generated protocol buffer package.
-
-It is generated from these files:
-	pkg/grpc/daemonsetstore/protos/daemonsetstore.proto
-
-It has these top-level messages:
-	DaemonSet
-	ListDaemonSetsRequest
-	ListDaemonSetsResponse
-	DisableDaemonSetRequest
-	DisableDaemonSetResponse
-	WatchDaemonSetsRequest
-	WatchDaemonSetsResponse
-*/
+
 package daemonsetstore
 
 import proto "github.com/golang/protobuf/proto"
@@ -41,21 +25,47 @@ const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
 // models fields/DaemonSet
 type DaemonSet struct {
-	Id           string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
-	Disabled     bool   `protobuf:"varint,2,opt,name=disabled" json:"disabled,omitempty"`
-	Manifest     string `protobuf:"bytes,3,opt,name=manifest" json:"manifest,omitempty"`
-	MinHealth    int64  `protobuf:"varint,4,opt,name=min_health,json=minHealth" json:"min_health,omitempty"`
-	Name         string `protobuf:"bytes,5,opt,name=name" json:"name,omitempty"`
-	NodeSelector string `protobuf:"bytes,6,opt,name=node_selector,json=nodeSelector" json:"node_selector,omitempty"`
-	PodId        string `protobuf:"bytes,7,opt,name=pod_id,json=podId" json:"pod_id,omitempty"`
+	Id           string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Disabled     bool   `protobuf:"varint,2,opt,name=disabled,proto3" json:"disabled,omitempty"`
+	Manifest     string `protobuf:"bytes,3,opt,name=manifest,proto3" json:"manifest,omitempty"`
+	MinHealth    int64  `protobuf:"varint,4,opt,name=min_health,json=minHealth,proto3" json:"min_health,omitempty"`
+	Name         string `protobuf:"bytes,5,opt,name=name,proto3" json:"name,omitempty"`
+	NodeSelector string `protobuf:"bytes,6,opt,name=node_selector,json=nodeSelector,proto3" json:"node_selector,omitempty"`
+	PodId        string `protobuf:"bytes,7,opt,name=pod_id,json=podId,proto3" json:"pod_id,omitempty"`
 	// expressed in nanoseconds (matches time.Duration)
-	Timeout int64 `protobuf:"varint,8,opt,name=timeout" json:"timeout,omitempty"`
+	Timeout int64 `protobuf:"varint,8,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	// unset if the daemon set doesn't have a rolling strategy
+	RollingStrategy      *RollingStrategy    `protobuf:"bytes,9,opt,name=rolling_strategy,json=rollingStrategy,proto3" json:"rolling_strategy,omitempty"`
+	FailureThreshold     string              `protobuf:"bytes,10,opt,name=failure_threshold,json=failureThreshold,proto3" json:"failure_threshold,omitempty"`
+	Overrides            []*ManifestOverride `protobuf:"bytes,11,rep,name=overrides,proto3" json:"overrides,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *DaemonSet) Reset()         { *m = DaemonSet{} }
+func (m *DaemonSet) String() string { return proto.CompactTextString(m) }
+func (*DaemonSet) ProtoMessage()    {}
+func (*DaemonSet) Descriptor() ([]byte, []int) {
+	return fileDescriptor_daemonsetstore_4f2a0599aaff5bea, []int{0}
+}
+func (m *DaemonSet) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DaemonSet.Unmarshal(m, b)
+}
+func (m *DaemonSet) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DaemonSet.Marshal(b, m, deterministic)
+}
+func (dst *DaemonSet) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DaemonSet.Merge(dst, src)
+}
+func (m *DaemonSet) XXX_Size() int {
+	return xxx_messageInfo_DaemonSet.Size(m)
+}
+func (m *DaemonSet) XXX_DiscardUnknown() {
+	xxx_messageInfo_DaemonSet.DiscardUnknown(m)
 }
 
-func (m *DaemonSet) Reset()                    { *m = DaemonSet{} }
-func (m *DaemonSet) String() string            { return proto.CompactTextString(m) }
-func (*DaemonSet) ProtoMessage()               {}
-func (*DaemonSet) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+var xxx_messageInfo_DaemonSet proto.InternalMessageInfo
 
 func (m *DaemonSet) GetId() string {
 	if m != nil {
@@ -85,147 +95,1464 @@ func (m *DaemonSet) GetMinHealth() int64 {
 	return 0
 }
 
-func (m *DaemonSet) GetName() string {
+func (m *DaemonSet) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *DaemonSet) GetNodeSelector() string {
+	if m != nil {
+		return m.NodeSelector
+	}
+	return ""
+}
+
+func (m *DaemonSet) GetPodId() string {
+	if m != nil {
+		return m.PodId
+	}
+	return ""
+}
+
+func (m *DaemonSet) GetTimeout() int64 {
+	if m != nil {
+		return m.Timeout
+	}
+	return 0
+}
+
+func (m *DaemonSet) GetRollingStrategy() *RollingStrategy {
+	if m != nil {
+		return m.RollingStrategy
+	}
+	return nil
+}
+
+func (m *DaemonSet) GetFailureThreshold() string {
+	if m != nil {
+		return m.FailureThreshold
+	}
+	return ""
+}
+
+func (m *DaemonSet) GetOverrides() []*ManifestOverride {
+	if m != nil {
+		return m.Overrides
+	}
+	return nil
+}
+
+// models fields/RollingStrategy
+type RollingStrategy struct {
+	MaxUnavailable string `protobuf:"bytes,1,opt,name=max_unavailable,json=maxUnavailable,proto3" json:"max_unavailable,omitempty"`
+	MaxSurge       string `protobuf:"bytes,2,opt,name=max_surge,json=maxSurge,proto3" json:"max_surge,omitempty"`
+	// expressed in nanoseconds (matches time.Duration)
+	BatchDelay           int64    `protobuf:"varint,3,opt,name=batch_delay,json=batchDelay,proto3" json:"batch_delay,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RollingStrategy) Reset()         { *m = RollingStrategy{} }
+func (m *RollingStrategy) String() string { return proto.CompactTextString(m) }
+func (*RollingStrategy) ProtoMessage()    {}
+func (*RollingStrategy) Descriptor() ([]byte, []int) {
+	return fileDescriptor_daemonsetstore_4f2a0599aaff5bea, []int{1}
+}
+func (m *RollingStrategy) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RollingStrategy.Unmarshal(m, b)
+}
+func (m *RollingStrategy) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RollingStrategy.Marshal(b, m, deterministic)
+}
+func (dst *RollingStrategy) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RollingStrategy.Merge(dst, src)
+}
+func (m *RollingStrategy) XXX_Size() int {
+	return xxx_messageInfo_RollingStrategy.Size(m)
+}
+func (m *RollingStrategy) XXX_DiscardUnknown() {
+	xxx_messageInfo_RollingStrategy.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RollingStrategy proto.InternalMessageInfo
+
+func (m *RollingStrategy) GetMaxUnavailable() string {
+	if m != nil {
+		return m.MaxUnavailable
+	}
+	return ""
+}
+
+func (m *RollingStrategy) GetMaxSurge() string {
+	if m != nil {
+		return m.MaxSurge
+	}
+	return ""
+}
+
+func (m *RollingStrategy) GetBatchDelay() int64 {
+	if m != nil {
+		return m.BatchDelay
+	}
+	return 0
+}
+
+// models fields/ManifestOverride
+type ManifestOverride struct {
+	NodeSelector string `protobuf:"bytes,1,opt,name=node_selector,json=nodeSelector,proto3" json:"node_selector,omitempty"`
+	// the JSON encoding of a fields/ManifestPatch
+	Patch                string   `protobuf:"bytes,2,opt,name=patch,proto3" json:"patch,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ManifestOverride) Reset()         { *m = ManifestOverride{} }
+func (m *ManifestOverride) String() string { return proto.CompactTextString(m) }
+func (*ManifestOverride) ProtoMessage()    {}
+func (*ManifestOverride) Descriptor() ([]byte, []int) {
+	return fileDescriptor_daemonsetstore_4f2a0599aaff5bea, []int{2}
+}
+func (m *ManifestOverride) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ManifestOverride.Unmarshal(m, b)
+}
+func (m *ManifestOverride) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ManifestOverride.Marshal(b, m, deterministic)
+}
+func (dst *ManifestOverride) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ManifestOverride.Merge(dst, src)
+}
+func (m *ManifestOverride) XXX_Size() int {
+	return xxx_messageInfo_ManifestOverride.Size(m)
+}
+func (m *ManifestOverride) XXX_DiscardUnknown() {
+	xxx_messageInfo_ManifestOverride.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ManifestOverride proto.InternalMessageInfo
+
+func (m *ManifestOverride) GetNodeSelector() string {
+	if m != nil {
+		return m.NodeSelector
+	}
+	return ""
+}
+
+func (m *ManifestOverride) GetPatch() string {
+	if m != nil {
+		return m.Patch
+	}
+	return ""
+}
+
+type ListDaemonSetsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListDaemonSetsRequest) Reset()         { *m = ListDaemonSetsRequest{} }
+func (m *ListDaemonSetsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListDaemonSetsRequest) ProtoMessage()    {}
+func (*ListDaemonSetsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_daemonsetstore_4f2a0599aaff5bea, []int{3}
+}
+func (m *ListDaemonSetsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListDaemonSetsRequest.Unmarshal(m, b)
+}
+func (m *ListDaemonSetsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListDaemonSetsRequest.Marshal(b, m, deterministic)
+}
+func (dst *ListDaemonSetsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListDaemonSetsRequest.Merge(dst, src)
+}
+func (m *ListDaemonSetsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListDaemonSetsRequest.Size(m)
+}
+func (m *ListDaemonSetsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListDaemonSetsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListDaemonSetsRequest proto.InternalMessageInfo
+
+type ListDaemonSetsResponse struct {
+	DaemonSets           []*DaemonSet `protobuf:"bytes,1,rep,name=daemon_sets,json=daemonSets,proto3" json:"daemon_sets,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *ListDaemonSetsResponse) Reset()         { *m = ListDaemonSetsResponse{} }
+func (m *ListDaemonSetsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListDaemonSetsResponse) ProtoMessage()    {}
+func (*ListDaemonSetsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_daemonsetstore_4f2a0599aaff5bea, []int{4}
+}
+func (m *ListDaemonSetsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListDaemonSetsResponse.Unmarshal(m, b)
+}
+func (m *ListDaemonSetsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListDaemonSetsResponse.Marshal(b, m, deterministic)
+}
+func (dst *ListDaemonSetsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListDaemonSetsResponse.Merge(dst, src)
+}
+func (m *ListDaemonSetsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListDaemonSetsResponse.Size(m)
+}
+func (m *ListDaemonSetsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListDaemonSetsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListDaemonSetsResponse proto.InternalMessageInfo
+
+func (m *ListDaemonSetsResponse) GetDaemonSets() []*DaemonSet {
+	if m != nil {
+		return m.DaemonSets
+	}
+	return nil
+}
+
+type DisableDaemonSetRequest struct {
+	DaemonSetId          string   `protobuf:"bytes,1,opt,name=daemon_set_id,json=daemonSetId,proto3" json:"daemon_set_id,omitempty"`
+	User                 string   `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DisableDaemonSetRequest) Reset()         { *m = DisableDaemonSetRequest{} }
+func (m *DisableDaemonSetRequest) String() string { return proto.CompactTextString(m) }
+func (*DisableDaemonSetRequest) ProtoMessage()    {}
+func (*DisableDaemonSetRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_daemonsetstore_4f2a0599aaff5bea, []int{5}
+}
+func (m *DisableDaemonSetRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DisableDaemonSetRequest.Unmarshal(m, b)
+}
+func (m *DisableDaemonSetRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DisableDaemonSetRequest.Marshal(b, m, deterministic)
+}
+func (dst *DisableDaemonSetRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DisableDaemonSetRequest.Merge(dst, src)
+}
+func (m *DisableDaemonSetRequest) XXX_Size() int {
+	return xxx_messageInfo_DisableDaemonSetRequest.Size(m)
+}
+func (m *DisableDaemonSetRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DisableDaemonSetRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DisableDaemonSetRequest proto.InternalMessageInfo
+
+func (m *DisableDaemonSetRequest) GetDaemonSetId() string {
+	if m != nil {
+		return m.DaemonSetId
+	}
+	return ""
+}
+
+func (m *DisableDaemonSetRequest) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+type DisableDaemonSetResponse struct {
+	DaemonSet            *DaemonSet `protobuf:"bytes,1,opt,name=daemon_set,json=daemonSet,proto3" json:"daemon_set,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *DisableDaemonSetResponse) Reset()         { *m = DisableDaemonSetResponse{} }
+func (m *DisableDaemonSetResponse) String() string { return proto.CompactTextString(m) }
+func (*DisableDaemonSetResponse) ProtoMessage()    {}
+func (*DisableDaemonSetResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_daemonsetstore_4f2a0599aaff5bea, []int{6}
+}
+func (m *DisableDaemonSetResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DisableDaemonSetResponse.Unmarshal(m, b)
+}
+func (m *DisableDaemonSetResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DisableDaemonSetResponse.Marshal(b, m, deterministic)
+}
+func (dst *DisableDaemonSetResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DisableDaemonSetResponse.Merge(dst, src)
+}
+func (m *DisableDaemonSetResponse) XXX_Size() int {
+	return xxx_messageInfo_DisableDaemonSetResponse.Size(m)
+}
+func (m *DisableDaemonSetResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DisableDaemonSetResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DisableDaemonSetResponse proto.InternalMessageInfo
+
+func (m *DisableDaemonSetResponse) GetDaemonSet() *DaemonSet {
+	if m != nil {
+		return m.DaemonSet
+	}
+	return nil
+}
+
+type WatchDaemonSetsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchDaemonSetsRequest) Reset()         { *m = WatchDaemonSetsRequest{} }
+func (m *WatchDaemonSetsRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchDaemonSetsRequest) ProtoMessage()    {}
+func (*WatchDaemonSetsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_daemonsetstore_4f2a0599aaff5bea, []int{7}
+}
+func (m *WatchDaemonSetsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchDaemonSetsRequest.Unmarshal(m, b)
+}
+func (m *WatchDaemonSetsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchDaemonSetsRequest.Marshal(b, m, deterministic)
+}
+func (dst *WatchDaemonSetsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchDaemonSetsRequest.Merge(dst, src)
+}
+func (m *WatchDaemonSetsRequest) XXX_Size() int {
+	return xxx_messageInfo_WatchDaemonSetsRequest.Size(m)
+}
+func (m *WatchDaemonSetsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchDaemonSetsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchDaemonSetsRequest proto.InternalMessageInfo
+
+// models dsstore.WatchedDaemonSets
+type WatchDaemonSetsResponse struct {
+	Created              []*DaemonSet `protobuf:"bytes,1,rep,name=created,proto3" json:"created,omitempty"`
+	Updated              []*DaemonSet `protobuf:"bytes,2,rep,name=updated,proto3" json:"updated,omitempty"`
+	Deleted              []*DaemonSet `protobuf:"bytes,3,rep,name=deleted,proto3" json:"deleted,omitempty"`
+	Error                string       `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *WatchDaemonSetsResponse) Reset()         { *m = WatchDaemonSetsResponse{} }
+func (m *WatchDaemonSetsResponse) String() string { return proto.CompactTextString(m) }
+func (*WatchDaemonSetsResponse) ProtoMessage()    {}
+func (*WatchDaemonSetsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_daemonsetstore_4f2a0599aaff5bea, []int{8}
+}
+func (m *WatchDaemonSetsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchDaemonSetsResponse.Unmarshal(m, b)
+}
+func (m *WatchDaemonSetsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchDaemonSetsResponse.Marshal(b, m, deterministic)
+}
+func (dst *WatchDaemonSetsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchDaemonSetsResponse.Merge(dst, src)
+}
+func (m *WatchDaemonSetsResponse) XXX_Size() int {
+	return xxx_messageInfo_WatchDaemonSetsResponse.Size(m)
+}
+func (m *WatchDaemonSetsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchDaemonSetsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchDaemonSetsResponse proto.InternalMessageInfo
+
+func (m *WatchDaemonSetsResponse) GetCreated() []*DaemonSet {
+	if m != nil {
+		return m.Created
+	}
+	return nil
+}
+
+func (m *WatchDaemonSetsResponse) GetUpdated() []*DaemonSet {
+	if m != nil {
+		return m.Updated
+	}
+	return nil
+}
+
+func (m *WatchDaemonSetsResponse) GetDeleted() []*DaemonSet {
+	if m != nil {
+		return m.Deleted
+	}
+	return nil
+}
+
+func (m *WatchDaemonSetsResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type CreateDaemonSetRequest struct {
+	Manifest     string `protobuf:"bytes,1,opt,name=manifest,proto3" json:"manifest,omitempty"`
+	MinHealth    int64  `protobuf:"varint,2,opt,name=min_health,json=minHealth,proto3" json:"min_health,omitempty"`
+	Name         string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	NodeSelector string `protobuf:"bytes,4,opt,name=node_selector,json=nodeSelector,proto3" json:"node_selector,omitempty"`
+	PodId        string `protobuf:"bytes,5,opt,name=pod_id,json=podId,proto3" json:"pod_id,omitempty"`
+	// expressed in nanoseconds (matches time.Duration)
+	Timeout              int64    `protobuf:"varint,6,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	User                 string   `protobuf:"bytes,7,opt,name=user,proto3" json:"user,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateDaemonSetRequest) Reset()         { *m = CreateDaemonSetRequest{} }
+func (m *CreateDaemonSetRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateDaemonSetRequest) ProtoMessage()    {}
+func (*CreateDaemonSetRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_daemonsetstore_4f2a0599aaff5bea, []int{9}
+}
+func (m *CreateDaemonSetRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateDaemonSetRequest.Unmarshal(m, b)
+}
+func (m *CreateDaemonSetRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateDaemonSetRequest.Marshal(b, m, deterministic)
+}
+func (dst *CreateDaemonSetRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateDaemonSetRequest.Merge(dst, src)
+}
+func (m *CreateDaemonSetRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateDaemonSetRequest.Size(m)
+}
+func (m *CreateDaemonSetRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateDaemonSetRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateDaemonSetRequest proto.InternalMessageInfo
+
+func (m *CreateDaemonSetRequest) GetManifest() string {
+	if m != nil {
+		return m.Manifest
+	}
+	return ""
+}
+
+func (m *CreateDaemonSetRequest) GetMinHealth() int64 {
+	if m != nil {
+		return m.MinHealth
+	}
+	return 0
+}
+
+func (m *CreateDaemonSetRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *CreateDaemonSetRequest) GetNodeSelector() string {
+	if m != nil {
+		return m.NodeSelector
+	}
+	return ""
+}
+
+func (m *CreateDaemonSetRequest) GetPodId() string {
+	if m != nil {
+		return m.PodId
+	}
+	return ""
+}
+
+func (m *CreateDaemonSetRequest) GetTimeout() int64 {
+	if m != nil {
+		return m.Timeout
+	}
+	return 0
+}
+
+func (m *CreateDaemonSetRequest) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+type CreateDaemonSetResponse struct {
+	DaemonSet            *DaemonSet `protobuf:"bytes,1,opt,name=daemon_set,json=daemonSet,proto3" json:"daemon_set,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *CreateDaemonSetResponse) Reset()         { *m = CreateDaemonSetResponse{} }
+func (m *CreateDaemonSetResponse) String() string { return proto.CompactTextString(m) }
+func (*CreateDaemonSetResponse) ProtoMessage()    {}
+func (*CreateDaemonSetResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_daemonsetstore_4f2a0599aaff5bea, []int{10}
+}
+func (m *CreateDaemonSetResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateDaemonSetResponse.Unmarshal(m, b)
+}
+func (m *CreateDaemonSetResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateDaemonSetResponse.Marshal(b, m, deterministic)
+}
+func (dst *CreateDaemonSetResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateDaemonSetResponse.Merge(dst, src)
+}
+func (m *CreateDaemonSetResponse) XXX_Size() int {
+	return xxx_messageInfo_CreateDaemonSetResponse.Size(m)
+}
+func (m *CreateDaemonSetResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateDaemonSetResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateDaemonSetResponse proto.InternalMessageInfo
+
+func (m *CreateDaemonSetResponse) GetDaemonSet() *DaemonSet {
+	if m != nil {
+		return m.DaemonSet
+	}
+	return nil
+}
+
+type EnableDaemonSetRequest struct {
+	DaemonSetId          string   `protobuf:"bytes,1,opt,name=daemon_set_id,json=daemonSetId,proto3" json:"daemon_set_id,omitempty"`
+	User                 string   `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EnableDaemonSetRequest) Reset()         { *m = EnableDaemonSetRequest{} }
+func (m *EnableDaemonSetRequest) String() string { return proto.CompactTextString(m) }
+func (*EnableDaemonSetRequest) ProtoMessage()    {}
+func (*EnableDaemonSetRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_daemonsetstore_4f2a0599aaff5bea, []int{11}
+}
+func (m *EnableDaemonSetRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EnableDaemonSetRequest.Unmarshal(m, b)
+}
+func (m *EnableDaemonSetRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EnableDaemonSetRequest.Marshal(b, m, deterministic)
+}
+func (dst *EnableDaemonSetRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EnableDaemonSetRequest.Merge(dst, src)
+}
+func (m *EnableDaemonSetRequest) XXX_Size() int {
+	return xxx_messageInfo_EnableDaemonSetRequest.Size(m)
+}
+func (m *EnableDaemonSetRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_EnableDaemonSetRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EnableDaemonSetRequest proto.InternalMessageInfo
+
+func (m *EnableDaemonSetRequest) GetDaemonSetId() string {
+	if m != nil {
+		return m.DaemonSetId
+	}
+	return ""
+}
+
+func (m *EnableDaemonSetRequest) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+type EnableDaemonSetResponse struct {
+	DaemonSet            *DaemonSet `protobuf:"bytes,1,opt,name=daemon_set,json=daemonSet,proto3" json:"daemon_set,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *EnableDaemonSetResponse) Reset()         { *m = EnableDaemonSetResponse{} }
+func (m *EnableDaemonSetResponse) String() string { return proto.CompactTextString(m) }
+func (*EnableDaemonSetResponse) ProtoMessage()    {}
+func (*EnableDaemonSetResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_daemonsetstore_4f2a0599aaff5bea, []int{12}
+}
+func (m *EnableDaemonSetResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EnableDaemonSetResponse.Unmarshal(m, b)
+}
+func (m *EnableDaemonSetResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EnableDaemonSetResponse.Marshal(b, m, deterministic)
+}
+func (dst *EnableDaemonSetResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EnableDaemonSetResponse.Merge(dst, src)
+}
+func (m *EnableDaemonSetResponse) XXX_Size() int {
+	return xxx_messageInfo_EnableDaemonSetResponse.Size(m)
+}
+func (m *EnableDaemonSetResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_EnableDaemonSetResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EnableDaemonSetResponse proto.InternalMessageInfo
+
+func (m *EnableDaemonSetResponse) GetDaemonSet() *DaemonSet {
+	if m != nil {
+		return m.DaemonSet
+	}
+	return nil
+}
+
+type DeleteDaemonSetRequest struct {
+	DaemonSetId          string   `protobuf:"bytes,1,opt,name=daemon_set_id,json=daemonSetId,proto3" json:"daemon_set_id,omitempty"`
+	User                 string   `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteDaemonSetRequest) Reset()         { *m = DeleteDaemonSetRequest{} }
+func (m *DeleteDaemonSetRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteDaemonSetRequest) ProtoMessage()    {}
+func (*DeleteDaemonSetRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_daemonsetstore_4f2a0599aaff5bea, []int{13}
+}
+func (m *DeleteDaemonSetRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteDaemonSetRequest.Unmarshal(m, b)
+}
+func (m *DeleteDaemonSetRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteDaemonSetRequest.Marshal(b, m, deterministic)
+}
+func (dst *DeleteDaemonSetRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteDaemonSetRequest.Merge(dst, src)
+}
+func (m *DeleteDaemonSetRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteDaemonSetRequest.Size(m)
+}
+func (m *DeleteDaemonSetRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteDaemonSetRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteDaemonSetRequest proto.InternalMessageInfo
+
+func (m *DeleteDaemonSetRequest) GetDaemonSetId() string {
+	if m != nil {
+		return m.DaemonSetId
+	}
+	return ""
+}
+
+func (m *DeleteDaemonSetRequest) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+type DeleteDaemonSetResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteDaemonSetResponse) Reset()         { *m = DeleteDaemonSetResponse{} }
+func (m *DeleteDaemonSetResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteDaemonSetResponse) ProtoMessage()    {}
+func (*DeleteDaemonSetResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_daemonsetstore_4f2a0599aaff5bea, []int{14}
+}
+func (m *DeleteDaemonSetResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteDaemonSetResponse.Unmarshal(m, b)
+}
+func (m *DeleteDaemonSetResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteDaemonSetResponse.Marshal(b, m, deterministic)
+}
+func (dst *DeleteDaemonSetResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteDaemonSetResponse.Merge(dst, src)
+}
+func (m *DeleteDaemonSetResponse) XXX_Size() int {
+	return xxx_messageInfo_DeleteDaemonSetResponse.Size(m)
+}
+func (m *DeleteDaemonSetResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteDaemonSetResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteDaemonSetResponse proto.InternalMessageInfo
+
+type UpdateManifestRequest struct {
+	DaemonSetId          string   `protobuf:"bytes,1,opt,name=daemon_set_id,json=daemonSetId,proto3" json:"daemon_set_id,omitempty"`
+	Manifest             string   `protobuf:"bytes,2,opt,name=manifest,proto3" json:"manifest,omitempty"`
+	User                 string   `protobuf:"bytes,3,opt,name=user,proto3" json:"user,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpdateManifestRequest) Reset()         { *m = UpdateManifestRequest{} }
+func (m *UpdateManifestRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateManifestRequest) ProtoMessage()    {}
+func (*UpdateManifestRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_daemonsetstore_4f2a0599aaff5bea, []int{15}
+}
+func (m *UpdateManifestRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateManifestRequest.Unmarshal(m, b)
+}
+func (m *UpdateManifestRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateManifestRequest.Marshal(b, m, deterministic)
+}
+func (dst *UpdateManifestRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateManifestRequest.Merge(dst, src)
+}
+func (m *UpdateManifestRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateManifestRequest.Size(m)
+}
+func (m *UpdateManifestRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateManifestRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateManifestRequest proto.InternalMessageInfo
+
+func (m *UpdateManifestRequest) GetDaemonSetId() string {
+	if m != nil {
+		return m.DaemonSetId
+	}
+	return ""
+}
+
+func (m *UpdateManifestRequest) GetManifest() string {
+	if m != nil {
+		return m.Manifest
+	}
+	return ""
+}
+
+func (m *UpdateManifestRequest) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+type UpdateManifestResponse struct {
+	DaemonSet            *DaemonSet `protobuf:"bytes,1,opt,name=daemon_set,json=daemonSet,proto3" json:"daemon_set,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *UpdateManifestResponse) Reset()         { *m = UpdateManifestResponse{} }
+func (m *UpdateManifestResponse) String() string { return proto.CompactTextString(m) }
+func (*UpdateManifestResponse) ProtoMessage()    {}
+func (*UpdateManifestResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_daemonsetstore_4f2a0599aaff5bea, []int{16}
+}
+func (m *UpdateManifestResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateManifestResponse.Unmarshal(m, b)
+}
+func (m *UpdateManifestResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateManifestResponse.Marshal(b, m, deterministic)
+}
+func (dst *UpdateManifestResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateManifestResponse.Merge(dst, src)
+}
+func (m *UpdateManifestResponse) XXX_Size() int {
+	return xxx_messageInfo_UpdateManifestResponse.Size(m)
+}
+func (m *UpdateManifestResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateManifestResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateManifestResponse proto.InternalMessageInfo
+
+func (m *UpdateManifestResponse) GetDaemonSet() *DaemonSet {
+	if m != nil {
+		return m.DaemonSet
+	}
+	return nil
+}
+
+type UpdateNodeSelectorRequest struct {
+	DaemonSetId          string   `protobuf:"bytes,1,opt,name=daemon_set_id,json=daemonSetId,proto3" json:"daemon_set_id,omitempty"`
+	NodeSelector         string   `protobuf:"bytes,2,opt,name=node_selector,json=nodeSelector,proto3" json:"node_selector,omitempty"`
+	User                 string   `protobuf:"bytes,3,opt,name=user,proto3" json:"user,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpdateNodeSelectorRequest) Reset()         { *m = UpdateNodeSelectorRequest{} }
+func (m *UpdateNodeSelectorRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateNodeSelectorRequest) ProtoMessage()    {}
+func (*UpdateNodeSelectorRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_daemonsetstore_4f2a0599aaff5bea, []int{17}
+}
+func (m *UpdateNodeSelectorRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateNodeSelectorRequest.Unmarshal(m, b)
+}
+func (m *UpdateNodeSelectorRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateNodeSelectorRequest.Marshal(b, m, deterministic)
+}
+func (dst *UpdateNodeSelectorRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateNodeSelectorRequest.Merge(dst, src)
+}
+func (m *UpdateNodeSelectorRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateNodeSelectorRequest.Size(m)
+}
+func (m *UpdateNodeSelectorRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateNodeSelectorRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateNodeSelectorRequest proto.InternalMessageInfo
+
+func (m *UpdateNodeSelectorRequest) GetDaemonSetId() string {
+	if m != nil {
+		return m.DaemonSetId
+	}
+	return ""
+}
+
+func (m *UpdateNodeSelectorRequest) GetNodeSelector() string {
+	if m != nil {
+		return m.NodeSelector
+	}
+	return ""
+}
+
+func (m *UpdateNodeSelectorRequest) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+type UpdateNodeSelectorResponse struct {
+	DaemonSet            *DaemonSet `protobuf:"bytes,1,opt,name=daemon_set,json=daemonSet,proto3" json:"daemon_set,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *UpdateNodeSelectorResponse) Reset()         { *m = UpdateNodeSelectorResponse{} }
+func (m *UpdateNodeSelectorResponse) String() string { return proto.CompactTextString(m) }
+func (*UpdateNodeSelectorResponse) ProtoMessage()    {}
+func (*UpdateNodeSelectorResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_daemonsetstore_4f2a0599aaff5bea, []int{18}
+}
+func (m *UpdateNodeSelectorResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateNodeSelectorResponse.Unmarshal(m, b)
+}
+func (m *UpdateNodeSelectorResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateNodeSelectorResponse.Marshal(b, m, deterministic)
+}
+func (dst *UpdateNodeSelectorResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateNodeSelectorResponse.Merge(dst, src)
+}
+func (m *UpdateNodeSelectorResponse) XXX_Size() int {
+	return xxx_messageInfo_UpdateNodeSelectorResponse.Size(m)
+}
+func (m *UpdateNodeSelectorResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateNodeSelectorResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateNodeSelectorResponse proto.InternalMessageInfo
+
+func (m *UpdateNodeSelectorResponse) GetDaemonSet() *DaemonSet {
+	if m != nil {
+		return m.DaemonSet
+	}
+	return nil
+}
+
+type UpdateMinHealthRequest struct {
+	DaemonSetId          string   `protobuf:"bytes,1,opt,name=daemon_set_id,json=daemonSetId,proto3" json:"daemon_set_id,omitempty"`
+	MinHealth            int64    `protobuf:"varint,2,opt,name=min_health,json=minHealth,proto3" json:"min_health,omitempty"`
+	User                 string   `protobuf:"bytes,3,opt,name=user,proto3" json:"user,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpdateMinHealthRequest) Reset()         { *m = UpdateMinHealthRequest{} }
+func (m *UpdateMinHealthRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateMinHealthRequest) ProtoMessage()    {}
+func (*UpdateMinHealthRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_daemonsetstore_4f2a0599aaff5bea, []int{19}
+}
+func (m *UpdateMinHealthRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateMinHealthRequest.Unmarshal(m, b)
+}
+func (m *UpdateMinHealthRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateMinHealthRequest.Marshal(b, m, deterministic)
+}
+func (dst *UpdateMinHealthRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateMinHealthRequest.Merge(dst, src)
+}
+func (m *UpdateMinHealthRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateMinHealthRequest.Size(m)
+}
+func (m *UpdateMinHealthRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateMinHealthRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateMinHealthRequest proto.InternalMessageInfo
+
+func (m *UpdateMinHealthRequest) GetDaemonSetId() string {
+	if m != nil {
+		return m.DaemonSetId
+	}
+	return ""
+}
+
+func (m *UpdateMinHealthRequest) GetMinHealth() int64 {
+	if m != nil {
+		return m.MinHealth
+	}
+	return 0
+}
+
+func (m *UpdateMinHealthRequest) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+type UpdateMinHealthResponse struct {
+	DaemonSet            *DaemonSet `protobuf:"bytes,1,opt,name=daemon_set,json=daemonSet,proto3" json:"daemon_set,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *UpdateMinHealthResponse) Reset()         { *m = UpdateMinHealthResponse{} }
+func (m *UpdateMinHealthResponse) String() string { return proto.CompactTextString(m) }
+func (*UpdateMinHealthResponse) ProtoMessage()    {}
+func (*UpdateMinHealthResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_daemonsetstore_4f2a0599aaff5bea, []int{20}
+}
+func (m *UpdateMinHealthResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateMinHealthResponse.Unmarshal(m, b)
+}
+func (m *UpdateMinHealthResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateMinHealthResponse.Marshal(b, m, deterministic)
+}
+func (dst *UpdateMinHealthResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateMinHealthResponse.Merge(dst, src)
+}
+func (m *UpdateMinHealthResponse) XXX_Size() int {
+	return xxx_messageInfo_UpdateMinHealthResponse.Size(m)
+}
+func (m *UpdateMinHealthResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateMinHealthResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateMinHealthResponse proto.InternalMessageInfo
+
+func (m *UpdateMinHealthResponse) GetDaemonSet() *DaemonSet {
+	if m != nil {
+		return m.DaemonSet
+	}
+	return nil
+}
+
+type UpdateTimeoutRequest struct {
+	DaemonSetId string `protobuf:"bytes,1,opt,name=daemon_set_id,json=daemonSetId,proto3" json:"daemon_set_id,omitempty"`
+	// expressed in nanoseconds (matches time.Duration)
+	Timeout              int64    `protobuf:"varint,2,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	User                 string   `protobuf:"bytes,3,opt,name=user,proto3" json:"user,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpdateTimeoutRequest) Reset()         { *m = UpdateTimeoutRequest{} }
+func (m *UpdateTimeoutRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateTimeoutRequest) ProtoMessage()    {}
+func (*UpdateTimeoutRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_daemonsetstore_4f2a0599aaff5bea, []int{21}
+}
+func (m *UpdateTimeoutRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateTimeoutRequest.Unmarshal(m, b)
+}
+func (m *UpdateTimeoutRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateTimeoutRequest.Marshal(b, m, deterministic)
+}
+func (dst *UpdateTimeoutRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateTimeoutRequest.Merge(dst, src)
+}
+func (m *UpdateTimeoutRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateTimeoutRequest.Size(m)
+}
+func (m *UpdateTimeoutRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateTimeoutRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateTimeoutRequest proto.InternalMessageInfo
+
+func (m *UpdateTimeoutRequest) GetDaemonSetId() string {
+	if m != nil {
+		return m.DaemonSetId
+	}
+	return ""
+}
+
+func (m *UpdateTimeoutRequest) GetTimeout() int64 {
+	if m != nil {
+		return m.Timeout
+	}
+	return 0
+}
+
+func (m *UpdateTimeoutRequest) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+type UpdateTimeoutResponse struct {
+	DaemonSet            *DaemonSet `protobuf:"bytes,1,opt,name=daemon_set,json=daemonSet,proto3" json:"daemon_set,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *UpdateTimeoutResponse) Reset()         { *m = UpdateTimeoutResponse{} }
+func (m *UpdateTimeoutResponse) String() string { return proto.CompactTextString(m) }
+func (*UpdateTimeoutResponse) ProtoMessage()    {}
+func (*UpdateTimeoutResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_daemonsetstore_4f2a0599aaff5bea, []int{22}
+}
+func (m *UpdateTimeoutResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateTimeoutResponse.Unmarshal(m, b)
+}
+func (m *UpdateTimeoutResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateTimeoutResponse.Marshal(b, m, deterministic)
+}
+func (dst *UpdateTimeoutResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateTimeoutResponse.Merge(dst, src)
+}
+func (m *UpdateTimeoutResponse) XXX_Size() int {
+	return xxx_messageInfo_UpdateTimeoutResponse.Size(m)
+}
+func (m *UpdateTimeoutResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateTimeoutResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateTimeoutResponse proto.InternalMessageInfo
+
+func (m *UpdateTimeoutResponse) GetDaemonSet() *DaemonSet {
+	if m != nil {
+		return m.DaemonSet
+	}
+	return nil
+}
+
+type GetDaemonSetRequest struct {
+	DaemonSetId          string   `protobuf:"bytes,1,opt,name=daemon_set_id,json=daemonSetId,proto3" json:"daemon_set_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetDaemonSetRequest) Reset()         { *m = GetDaemonSetRequest{} }
+func (m *GetDaemonSetRequest) String() string { return proto.CompactTextString(m) }
+func (*GetDaemonSetRequest) ProtoMessage()    {}
+func (*GetDaemonSetRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_daemonsetstore_4f2a0599aaff5bea, []int{23}
+}
+func (m *GetDaemonSetRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetDaemonSetRequest.Unmarshal(m, b)
+}
+func (m *GetDaemonSetRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetDaemonSetRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetDaemonSetRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetDaemonSetRequest.Merge(dst, src)
+}
+func (m *GetDaemonSetRequest) XXX_Size() int {
+	return xxx_messageInfo_GetDaemonSetRequest.Size(m)
+}
+func (m *GetDaemonSetRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetDaemonSetRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetDaemonSetRequest proto.InternalMessageInfo
+
+func (m *GetDaemonSetRequest) GetDaemonSetId() string {
+	if m != nil {
+		return m.DaemonSetId
+	}
+	return ""
+}
+
+type GetDaemonSetResponse struct {
+	DaemonSet            *DaemonSet `protobuf:"bytes,1,opt,name=daemon_set,json=daemonSet,proto3" json:"daemon_set,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *GetDaemonSetResponse) Reset()         { *m = GetDaemonSetResponse{} }
+func (m *GetDaemonSetResponse) String() string { return proto.CompactTextString(m) }
+func (*GetDaemonSetResponse) ProtoMessage()    {}
+func (*GetDaemonSetResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_daemonsetstore_4f2a0599aaff5bea, []int{24}
+}
+func (m *GetDaemonSetResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetDaemonSetResponse.Unmarshal(m, b)
+}
+func (m *GetDaemonSetResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetDaemonSetResponse.Marshal(b, m, deterministic)
+}
+func (dst *GetDaemonSetResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetDaemonSetResponse.Merge(dst, src)
+}
+func (m *GetDaemonSetResponse) XXX_Size() int {
+	return xxx_messageInfo_GetDaemonSetResponse.Size(m)
+}
+func (m *GetDaemonSetResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetDaemonSetResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetDaemonSetResponse proto.InternalMessageInfo
+
+func (m *GetDaemonSetResponse) GetDaemonSet() *DaemonSet {
+	if m != nil {
+		return m.DaemonSet
+	}
+	return nil
+}
+
+type GetDaemonSetStatusRequest struct {
+	DaemonSetId          string   `protobuf:"bytes,1,opt,name=daemon_set_id,json=daemonSetId,proto3" json:"daemon_set_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetDaemonSetStatusRequest) Reset()         { *m = GetDaemonSetStatusRequest{} }
+func (m *GetDaemonSetStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*GetDaemonSetStatusRequest) ProtoMessage()    {}
+func (*GetDaemonSetStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_daemonsetstore_4f2a0599aaff5bea, []int{25}
+}
+func (m *GetDaemonSetStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetDaemonSetStatusRequest.Unmarshal(m, b)
+}
+func (m *GetDaemonSetStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetDaemonSetStatusRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetDaemonSetStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetDaemonSetStatusRequest.Merge(dst, src)
+}
+func (m *GetDaemonSetStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_GetDaemonSetStatusRequest.Size(m)
+}
+func (m *GetDaemonSetStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetDaemonSetStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetDaemonSetStatusRequest proto.InternalMessageInfo
+
+func (m *GetDaemonSetStatusRequest) GetDaemonSetId() string {
+	if m != nil {
+		return m.DaemonSetId
+	}
+	return ""
+}
+
+// models daemonsetstatus.Status
+type DaemonSetStatus struct {
+	ManifestSha           string         `protobuf:"bytes,1,opt,name=manifest_sha,json=manifestSha,proto3" json:"manifest_sha,omitempty"`
+	NodesDeployed         int64          `protobuf:"varint,2,opt,name=nodes_deployed,json=nodesDeployed,proto3" json:"nodes_deployed,omitempty"`
+	ReplicationInProgress bool           `protobuf:"varint,3,opt,name=replication_in_progress,json=replicationInProgress,proto3" json:"replication_in_progress,omitempty"`
+	Rolling               *RollingStatus `protobuf:"bytes,4,opt,name=rolling,proto3" json:"rolling,omitempty"`
+	FailureBudgetExceeded bool           `protobuf:"varint,5,opt,name=failure_budget_exceeded,json=failureBudgetExceeded,proto3" json:"failure_budget_exceeded,omitempty"`
+	FailedNodes           []string       `protobuf:"bytes,6,rep,name=failed_nodes,json=failedNodes,proto3" json:"failed_nodes,omitempty"`
+	Nodes                 *NodeStatus    `protobuf:"bytes,7,opt,name=nodes,proto3" json:"nodes,omitempty"`
+	ContendsWith          string         `protobuf:"bytes,8,opt,name=contends_with,json=contendsWith,proto3" json:"contends_with,omitempty"`
+	XXX_NoUnkeyedLiteral  struct{}       `json:"-"`
+	XXX_unrecognized      []byte         `json:"-"`
+	XXX_sizecache         int32          `json:"-"`
+}
+
+func (m *DaemonSetStatus) Reset()         { *m = DaemonSetStatus{} }
+func (m *DaemonSetStatus) String() string { return proto.CompactTextString(m) }
+func (*DaemonSetStatus) ProtoMessage()    {}
+func (*DaemonSetStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_daemonsetstore_4f2a0599aaff5bea, []int{26}
+}
+func (m *DaemonSetStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DaemonSetStatus.Unmarshal(m, b)
+}
+func (m *DaemonSetStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DaemonSetStatus.Marshal(b, m, deterministic)
+}
+func (dst *DaemonSetStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DaemonSetStatus.Merge(dst, src)
+}
+func (m *DaemonSetStatus) XXX_Size() int {
+	return xxx_messageInfo_DaemonSetStatus.Size(m)
+}
+func (m *DaemonSetStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_DaemonSetStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DaemonSetStatus proto.InternalMessageInfo
+
+func (m *DaemonSetStatus) GetManifestSha() string {
+	if m != nil {
+		return m.ManifestSha
+	}
+	return ""
+}
+
+func (m *DaemonSetStatus) GetNodesDeployed() int64 {
+	if m != nil {
+		return m.NodesDeployed
+	}
+	return 0
+}
+
+func (m *DaemonSetStatus) GetReplicationInProgress() bool {
+	if m != nil {
+		return m.ReplicationInProgress
+	}
+	return false
+}
+
+func (m *DaemonSetStatus) GetRolling() *RollingStatus {
+	if m != nil {
+		return m.Rolling
+	}
+	return nil
+}
+
+func (m *DaemonSetStatus) GetFailureBudgetExceeded() bool {
+	if m != nil {
+		return m.FailureBudgetExceeded
+	}
+	return false
+}
+
+func (m *DaemonSetStatus) GetFailedNodes() []string {
+	if m != nil {
+		return m.FailedNodes
+	}
+	return nil
+}
+
+func (m *DaemonSetStatus) GetNodes() *NodeStatus {
+	if m != nil {
+		return m.Nodes
+	}
+	return nil
+}
+
+func (m *DaemonSetStatus) GetContendsWith() string {
+	if m != nil {
+		return m.ContendsWith
+	}
+	return ""
+}
+
+// models daemonsetstatus.RollingStatus
+type RollingStatus struct {
+	BatchesCompleted int64  `protobuf:"varint,1,opt,name=batches_completed,json=batchesCompleted,proto3" json:"batches_completed,omitempty"`
+	BatchSize        int64  `protobuf:"varint,2,opt,name=batch_size,json=batchSize,proto3" json:"batch_size,omitempty"`
+	NodesRemaining   int64  `protobuf:"varint,3,opt,name=nodes_remaining,json=nodesRemaining,proto3" json:"nodes_remaining,omitempty"`
+	Unavailable      int64  `protobuf:"varint,4,opt,name=unavailable,proto3" json:"unavailable,omitempty"`
+	BlockingReason   string `protobuf:"bytes,5,opt,name=blocking_reason,json=blockingReason,proto3" json:"blocking_reason,omitempty"`
+	// expressed in nanoseconds since the unix epoch, 0 if unset
+	LastBatchTime        int64    `protobuf:"varint,6,opt,name=last_batch_time,json=lastBatchTime,proto3" json:"last_batch_time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RollingStatus) Reset()         { *m = RollingStatus{} }
+func (m *RollingStatus) String() string { return proto.CompactTextString(m) }
+func (*RollingStatus) ProtoMessage()    {}
+func (*RollingStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_daemonsetstore_4f2a0599aaff5bea, []int{27}
+}
+func (m *RollingStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RollingStatus.Unmarshal(m, b)
+}
+func (m *RollingStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RollingStatus.Marshal(b, m, deterministic)
+}
+func (dst *RollingStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RollingStatus.Merge(dst, src)
+}
+func (m *RollingStatus) XXX_Size() int {
+	return xxx_messageInfo_RollingStatus.Size(m)
+}
+func (m *RollingStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_RollingStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RollingStatus proto.InternalMessageInfo
+
+func (m *RollingStatus) GetBatchesCompleted() int64 {
+	if m != nil {
+		return m.BatchesCompleted
+	}
+	return 0
+}
+
+func (m *RollingStatus) GetBatchSize() int64 {
+	if m != nil {
+		return m.BatchSize
+	}
+	return 0
+}
+
+func (m *RollingStatus) GetNodesRemaining() int64 {
 	if m != nil {
-		return m.Name
+		return m.NodesRemaining
 	}
-	return ""
+	return 0
 }
 
-func (m *DaemonSet) GetNodeSelector() string {
+func (m *RollingStatus) GetUnavailable() int64 {
 	if m != nil {
-		return m.NodeSelector
+		return m.Unavailable
 	}
-	return ""
+	return 0
 }
 
-func (m *DaemonSet) GetPodId() string {
+func (m *RollingStatus) GetBlockingReason() string {
 	if m != nil {
-		return m.PodId
+		return m.BlockingReason
 	}
 	return ""
 }
 
-func (m *DaemonSet) GetTimeout() int64 {
+func (m *RollingStatus) GetLastBatchTime() int64 {
 	if m != nil {
-		return m.Timeout
+		return m.LastBatchTime
 	}
 	return 0
 }
 
-type ListDaemonSetsRequest struct {
+// models daemonsetstatus.NodeStatus
+type NodeStatus struct {
+	Eligible             int64    `protobuf:"varint,1,opt,name=eligible,proto3" json:"eligible,omitempty"`
+	Scheduled            int64    `protobuf:"varint,2,opt,name=scheduled,proto3" json:"scheduled,omitempty"`
+	Current              int64    `protobuf:"varint,3,opt,name=current,proto3" json:"current,omitempty"`
+	Healthy              int64    `protobuf:"varint,4,opt,name=healthy,proto3" json:"healthy,omitempty"`
+	Unhealthy            int64    `protobuf:"varint,5,opt,name=unhealthy,proto3" json:"unhealthy,omitempty"`
+	Pending              int64    `protobuf:"varint,6,opt,name=pending,proto3" json:"pending,omitempty"`
+	OldSha               []string `protobuf:"bytes,7,rep,name=old_sha,json=oldSha,proto3" json:"old_sha,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *ListDaemonSetsRequest) Reset()                    { *m = ListDaemonSetsRequest{} }
-func (m *ListDaemonSetsRequest) String() string            { return proto.CompactTextString(m) }
-func (*ListDaemonSetsRequest) ProtoMessage()               {}
-func (*ListDaemonSetsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
-
-type ListDaemonSetsResponse struct {
-	DaemonSets []*DaemonSet `protobuf:"bytes,1,rep,name=daemon_sets,json=daemonSets" json:"daemon_sets,omitempty"`
+func (m *NodeStatus) Reset()         { *m = NodeStatus{} }
+func (m *NodeStatus) String() string { return proto.CompactTextString(m) }
+func (*NodeStatus) ProtoMessage()    {}
+func (*NodeStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_daemonsetstore_4f2a0599aaff5bea, []int{28}
 }
-
-func (m *ListDaemonSetsResponse) Reset()                    { *m = ListDaemonSetsResponse{} }
-func (m *ListDaemonSetsResponse) String() string            { return proto.CompactTextString(m) }
-func (*ListDaemonSetsResponse) ProtoMessage()               {}
-func (*ListDaemonSetsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
-
-func (m *ListDaemonSetsResponse) GetDaemonSets() []*DaemonSet {
-	if m != nil {
-		return m.DaemonSets
-	}
-	return nil
+func (m *NodeStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeStatus.Unmarshal(m, b)
 }
-
-type DisableDaemonSetRequest struct {
-	DaemonSetId string `protobuf:"bytes,1,opt,name=daemon_set_id,json=daemonSetId" json:"daemon_set_id,omitempty"`
+func (m *NodeStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeStatus.Marshal(b, m, deterministic)
 }
-
-func (m *DisableDaemonSetRequest) Reset()                    { *m = DisableDaemonSetRequest{} }
-func (m *DisableDaemonSetRequest) String() string            { return proto.CompactTextString(m) }
-func (*DisableDaemonSetRequest) ProtoMessage()               {}
-func (*DisableDaemonSetRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
-
-func (m *DisableDaemonSetRequest) GetDaemonSetId() string {
-	if m != nil {
-		return m.DaemonSetId
-	}
-	return ""
+func (dst *NodeStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeStatus.Merge(dst, src)
 }
-
-type DisableDaemonSetResponse struct {
-	DaemonSet *DaemonSet `protobuf:"bytes,1,opt,name=daemon_set,json=daemonSet" json:"daemon_set,omitempty"`
+func (m *NodeStatus) XXX_Size() int {
+	return xxx_messageInfo_NodeStatus.Size(m)
+}
+func (m *NodeStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeStatus.DiscardUnknown(m)
 }
 
-func (m *DisableDaemonSetResponse) Reset()                    { *m = DisableDaemonSetResponse{} }
-func (m *DisableDaemonSetResponse) String() string            { return proto.CompactTextString(m) }
-func (*DisableDaemonSetResponse) ProtoMessage()               {}
-func (*DisableDaemonSetResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+var xxx_messageInfo_NodeStatus proto.InternalMessageInfo
 
-func (m *DisableDaemonSetResponse) GetDaemonSet() *DaemonSet {
+func (m *NodeStatus) GetEligible() int64 {
 	if m != nil {
-		return m.DaemonSet
+		return m.Eligible
 	}
-	return nil
+	return 0
 }
 
-type WatchDaemonSetsRequest struct {
+func (m *NodeStatus) GetScheduled() int64 {
+	if m != nil {
+		return m.Scheduled
+	}
+	return 0
 }
 
-func (m *WatchDaemonSetsRequest) Reset()                    { *m = WatchDaemonSetsRequest{} }
-func (m *WatchDaemonSetsRequest) String() string            { return proto.CompactTextString(m) }
-func (*WatchDaemonSetsRequest) ProtoMessage()               {}
-func (*WatchDaemonSetsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
-
-// models dsstore.WatchedDaemonSets
-type WatchDaemonSetsResponse struct {
-	Created []*DaemonSet `protobuf:"bytes,1,rep,name=created" json:"created,omitempty"`
-	Updated []*DaemonSet `protobuf:"bytes,2,rep,name=updated" json:"updated,omitempty"`
-	Deleted []*DaemonSet `protobuf:"bytes,3,rep,name=deleted" json:"deleted,omitempty"`
-	Error   string       `protobuf:"bytes,4,opt,name=error" json:"error,omitempty"`
+func (m *NodeStatus) GetCurrent() int64 {
+	if m != nil {
+		return m.Current
+	}
+	return 0
 }
 
-func (m *WatchDaemonSetsResponse) Reset()                    { *m = WatchDaemonSetsResponse{} }
-func (m *WatchDaemonSetsResponse) String() string            { return proto.CompactTextString(m) }
-func (*WatchDaemonSetsResponse) ProtoMessage()               {}
-func (*WatchDaemonSetsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
-
-func (m *WatchDaemonSetsResponse) GetCreated() []*DaemonSet {
+func (m *NodeStatus) GetHealthy() int64 {
 	if m != nil {
-		return m.Created
+		return m.Healthy
 	}
-	return nil
+	return 0
 }
 
-func (m *WatchDaemonSetsResponse) GetUpdated() []*DaemonSet {
+func (m *NodeStatus) GetUnhealthy() int64 {
 	if m != nil {
-		return m.Updated
+		return m.Unhealthy
 	}
-	return nil
+	return 0
 }
 
-func (m *WatchDaemonSetsResponse) GetDeleted() []*DaemonSet {
+func (m *NodeStatus) GetPending() int64 {
 	if m != nil {
-		return m.Deleted
+		return m.Pending
 	}
-	return nil
+	return 0
 }
 
-func (m *WatchDaemonSetsResponse) GetError() string {
+func (m *NodeStatus) GetOldSha() []string {
 	if m != nil {
-		return m.Error
+		return m.OldSha
 	}
-	return ""
+	return nil
 }
 
 func init() {
 	proto.RegisterType((*DaemonSet)(nil), "daemonsetstore.DaemonSet")
+	proto.RegisterType((*RollingStrategy)(nil), "daemonsetstore.RollingStrategy")
+	proto.RegisterType((*ManifestOverride)(nil), "daemonsetstore.ManifestOverride")
 	proto.RegisterType((*ListDaemonSetsRequest)(nil), "daemonsetstore.ListDaemonSetsRequest")
 	proto.RegisterType((*ListDaemonSetsResponse)(nil), "daemonsetstore.ListDaemonSetsResponse")
 	proto.RegisterType((*DisableDaemonSetRequest)(nil), "daemonsetstore.DisableDaemonSetRequest")
 	proto.RegisterType((*DisableDaemonSetResponse)(nil), "daemonsetstore.DisableDaemonSetResponse")
 	proto.RegisterType((*WatchDaemonSetsRequest)(nil), "daemonsetstore.WatchDaemonSetsRequest")
 	proto.RegisterType((*WatchDaemonSetsResponse)(nil), "daemonsetstore.WatchDaemonSetsResponse")
+	proto.RegisterType((*CreateDaemonSetRequest)(nil), "daemonsetstore.CreateDaemonSetRequest")
+	proto.RegisterType((*CreateDaemonSetResponse)(nil), "daemonsetstore.CreateDaemonSetResponse")
+	proto.RegisterType((*EnableDaemonSetRequest)(nil), "daemonsetstore.EnableDaemonSetRequest")
+	proto.RegisterType((*EnableDaemonSetResponse)(nil), "daemonsetstore.EnableDaemonSetResponse")
+	proto.RegisterType((*DeleteDaemonSetRequest)(nil), "daemonsetstore.DeleteDaemonSetRequest")
+	proto.RegisterType((*DeleteDaemonSetResponse)(nil), "daemonsetstore.DeleteDaemonSetResponse")
+	proto.RegisterType((*UpdateManifestRequest)(nil), "daemonsetstore.UpdateManifestRequest")
+	proto.RegisterType((*UpdateManifestResponse)(nil), "daemonsetstore.UpdateManifestResponse")
+	proto.RegisterType((*UpdateNodeSelectorRequest)(nil), "daemonsetstore.UpdateNodeSelectorRequest")
+	proto.RegisterType((*UpdateNodeSelectorResponse)(nil), "daemonsetstore.UpdateNodeSelectorResponse")
+	proto.RegisterType((*UpdateMinHealthRequest)(nil), "daemonsetstore.UpdateMinHealthRequest")
+	proto.RegisterType((*UpdateMinHealthResponse)(nil), "daemonsetstore.UpdateMinHealthResponse")
+	proto.RegisterType((*UpdateTimeoutRequest)(nil), "daemonsetstore.UpdateTimeoutRequest")
+	proto.RegisterType((*UpdateTimeoutResponse)(nil), "daemonsetstore.UpdateTimeoutResponse")
+	proto.RegisterType((*GetDaemonSetRequest)(nil), "daemonsetstore.GetDaemonSetRequest")
+	proto.RegisterType((*GetDaemonSetResponse)(nil), "daemonsetstore.GetDaemonSetResponse")
+	proto.RegisterType((*GetDaemonSetStatusRequest)(nil), "daemonsetstore.GetDaemonSetStatusRequest")
+	proto.RegisterType((*DaemonSetStatus)(nil), "daemonsetstore.DaemonSetStatus")
+	proto.RegisterType((*RollingStatus)(nil), "daemonsetstore.RollingStatus")
+	proto.RegisterType((*NodeStatus)(nil), "daemonsetstore.NodeStatus")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -236,12 +1563,23 @@ var _ grpc.ClientConn
 // is compatible with the grpc package it is being compiled against.
 const _ = grpc.SupportPackageIsVersion4
 
-// Client API for P2DaemonSetStore service
-
+// P2DaemonSetStoreClient is the client API for P2DaemonSetStore service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type P2DaemonSetStoreClient interface {
 	ListDaemonSets(ctx context.Context, in *ListDaemonSetsRequest, opts ...grpc.CallOption) (*ListDaemonSetsResponse, error)
 	DisableDaemonSet(ctx context.Context, in *DisableDaemonSetRequest, opts ...grpc.CallOption) (*DisableDaemonSetResponse, error)
 	WatchDaemonSets(ctx context.Context, in *WatchDaemonSetsRequest, opts ...grpc.CallOption) (P2DaemonSetStore_WatchDaemonSetsClient, error)
+	// The following RPCs write audit log records attributed to the passed user
+	CreateDaemonSet(ctx context.Context, in *CreateDaemonSetRequest, opts ...grpc.CallOption) (*CreateDaemonSetResponse, error)
+	EnableDaemonSet(ctx context.Context, in *EnableDaemonSetRequest, opts ...grpc.CallOption) (*EnableDaemonSetResponse, error)
+	DeleteDaemonSet(ctx context.Context, in *DeleteDaemonSetRequest, opts ...grpc.CallOption) (*DeleteDaemonSetResponse, error)
+	UpdateManifest(ctx context.Context, in *UpdateManifestRequest, opts ...grpc.CallOption) (*UpdateManifestResponse, error)
+	UpdateNodeSelector(ctx context.Context, in *UpdateNodeSelectorRequest, opts ...grpc.CallOption) (*UpdateNodeSelectorResponse, error)
+	UpdateMinHealth(ctx context.Context, in *UpdateMinHealthRequest, opts ...grpc.CallOption) (*UpdateMinHealthResponse, error)
+	UpdateTimeout(ctx context.Context, in *UpdateTimeoutRequest, opts ...grpc.CallOption) (*UpdateTimeoutResponse, error)
+	GetDaemonSet(ctx context.Context, in *GetDaemonSetRequest, opts ...grpc.CallOption) (*GetDaemonSetResponse, error)
+	GetDaemonSetStatus(ctx context.Context, in *GetDaemonSetStatusRequest, opts ...grpc.CallOption) (*DaemonSetStatus, error)
 }
 
 type p2DaemonSetStoreClient struct {
@@ -254,7 +1592,7 @@ func NewP2DaemonSetStoreClient(cc *grpc.ClientConn) P2DaemonSetStoreClient {
 
 func (c *p2DaemonSetStoreClient) ListDaemonSets(ctx context.Context, in *ListDaemonSetsRequest, opts ...grpc.CallOption) (*ListDaemonSetsResponse, error) {
 	out := new(ListDaemonSetsResponse)
-	err := grpc.Invoke(ctx, "/daemonsetstore.P2DaemonSetStore/ListDaemonSets", in, out, c.cc, opts...)
+	err := c.cc.Invoke(ctx, "/daemonsetstore.P2DaemonSetStore/ListDaemonSets", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -263,7 +1601,7 @@ func (c *p2DaemonSetStoreClient) ListDaemonSets(ctx context.Context, in *ListDae
 
 func (c *p2DaemonSetStoreClient) DisableDaemonSet(ctx context.Context, in *DisableDaemonSetRequest, opts ...grpc.CallOption) (*DisableDaemonSetResponse, error) {
 	out := new(DisableDaemonSetResponse)
-	err := grpc.Invoke(ctx, "/daemonsetstore.P2DaemonSetStore/DisableDaemonSet", in, out, c.cc, opts...)
+	err := c.cc.Invoke(ctx, "/daemonsetstore.P2DaemonSetStore/DisableDaemonSet", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -271,7 +1609,7 @@ func (c *p2DaemonSetStoreClient) DisableDaemonSet(ctx context.Context, in *Disab
 }
 
 func (c *p2DaemonSetStoreClient) WatchDaemonSets(ctx context.Context, in *WatchDaemonSetsRequest, opts ...grpc.CallOption) (P2DaemonSetStore_WatchDaemonSetsClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_P2DaemonSetStore_serviceDesc.Streams[0], c.cc, "/daemonsetstore.P2DaemonSetStore/WatchDaemonSets", opts...)
+	stream, err := c.cc.NewStream(ctx, &_P2DaemonSetStore_serviceDesc.Streams[0], "/daemonsetstore.P2DaemonSetStore/WatchDaemonSets", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -302,12 +1640,102 @@ func (x *p2DaemonSetStoreWatchDaemonSetsClient) Recv() (*WatchDaemonSetsResponse
 	return m, nil
 }
 
-// Server API for P2DaemonSetStore service
+func (c *p2DaemonSetStoreClient) CreateDaemonSet(ctx context.Context, in *CreateDaemonSetRequest, opts ...grpc.CallOption) (*CreateDaemonSetResponse, error) {
+	out := new(CreateDaemonSetResponse)
+	err := c.cc.Invoke(ctx, "/daemonsetstore.P2DaemonSetStore/CreateDaemonSet", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *p2DaemonSetStoreClient) EnableDaemonSet(ctx context.Context, in *EnableDaemonSetRequest, opts ...grpc.CallOption) (*EnableDaemonSetResponse, error) {
+	out := new(EnableDaemonSetResponse)
+	err := c.cc.Invoke(ctx, "/daemonsetstore.P2DaemonSetStore/EnableDaemonSet", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *p2DaemonSetStoreClient) DeleteDaemonSet(ctx context.Context, in *DeleteDaemonSetRequest, opts ...grpc.CallOption) (*DeleteDaemonSetResponse, error) {
+	out := new(DeleteDaemonSetResponse)
+	err := c.cc.Invoke(ctx, "/daemonsetstore.P2DaemonSetStore/DeleteDaemonSet", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *p2DaemonSetStoreClient) UpdateManifest(ctx context.Context, in *UpdateManifestRequest, opts ...grpc.CallOption) (*UpdateManifestResponse, error) {
+	out := new(UpdateManifestResponse)
+	err := c.cc.Invoke(ctx, "/daemonsetstore.P2DaemonSetStore/UpdateManifest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *p2DaemonSetStoreClient) UpdateNodeSelector(ctx context.Context, in *UpdateNodeSelectorRequest, opts ...grpc.CallOption) (*UpdateNodeSelectorResponse, error) {
+	out := new(UpdateNodeSelectorResponse)
+	err := c.cc.Invoke(ctx, "/daemonsetstore.P2DaemonSetStore/UpdateNodeSelector", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *p2DaemonSetStoreClient) UpdateMinHealth(ctx context.Context, in *UpdateMinHealthRequest, opts ...grpc.CallOption) (*UpdateMinHealthResponse, error) {
+	out := new(UpdateMinHealthResponse)
+	err := c.cc.Invoke(ctx, "/daemonsetstore.P2DaemonSetStore/UpdateMinHealth", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *p2DaemonSetStoreClient) UpdateTimeout(ctx context.Context, in *UpdateTimeoutRequest, opts ...grpc.CallOption) (*UpdateTimeoutResponse, error) {
+	out := new(UpdateTimeoutResponse)
+	err := c.cc.Invoke(ctx, "/daemonsetstore.P2DaemonSetStore/UpdateTimeout", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *p2DaemonSetStoreClient) GetDaemonSet(ctx context.Context, in *GetDaemonSetRequest, opts ...grpc.CallOption) (*GetDaemonSetResponse, error) {
+	out := new(GetDaemonSetResponse)
+	err := c.cc.Invoke(ctx, "/daemonsetstore.P2DaemonSetStore/GetDaemonSet", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *p2DaemonSetStoreClient) GetDaemonSetStatus(ctx context.Context, in *GetDaemonSetStatusRequest, opts ...grpc.CallOption) (*DaemonSetStatus, error) {
+	out := new(DaemonSetStatus)
+	err := c.cc.Invoke(ctx, "/daemonsetstore.P2DaemonSetStore/GetDaemonSetStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
 
+// P2DaemonSetStoreServer is the server API for P2DaemonSetStore service.
 type P2DaemonSetStoreServer interface {
 	ListDaemonSets(context.Context, *ListDaemonSetsRequest) (*ListDaemonSetsResponse, error)
 	DisableDaemonSet(context.Context, *DisableDaemonSetRequest) (*DisableDaemonSetResponse, error)
 	WatchDaemonSets(*WatchDaemonSetsRequest, P2DaemonSetStore_WatchDaemonSetsServer) error
+	// The following RPCs write audit log records attributed to the passed user
+	CreateDaemonSet(context.Context, *CreateDaemonSetRequest) (*CreateDaemonSetResponse, error)
+	EnableDaemonSet(context.Context, *EnableDaemonSetRequest) (*EnableDaemonSetResponse, error)
+	DeleteDaemonSet(context.Context, *DeleteDaemonSetRequest) (*DeleteDaemonSetResponse, error)
+	UpdateManifest(context.Context, *UpdateManifestRequest) (*UpdateManifestResponse, error)
+	UpdateNodeSelector(context.Context, *UpdateNodeSelectorRequest) (*UpdateNodeSelectorResponse, error)
+	UpdateMinHealth(context.Context, *UpdateMinHealthRequest) (*UpdateMinHealthResponse, error)
+	UpdateTimeout(context.Context, *UpdateTimeoutRequest) (*UpdateTimeoutResponse, error)
+	GetDaemonSet(context.Context, *GetDaemonSetRequest) (*GetDaemonSetResponse, error)
+	GetDaemonSetStatus(context.Context, *GetDaemonSetStatusRequest) (*DaemonSetStatus, error)
 }
 
 func RegisterP2DaemonSetStoreServer(s *grpc.Server, srv P2DaemonSetStoreServer) {
@@ -371,6 +1799,168 @@ func (x *p2DaemonSetStoreWatchDaemonSetsServer) Send(m *WatchDaemonSetsResponse)
 	return x.ServerStream.SendMsg(m)
 }
 
+func _P2DaemonSetStore_CreateDaemonSet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateDaemonSetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(P2DaemonSetStoreServer).CreateDaemonSet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/daemonsetstore.P2DaemonSetStore/CreateDaemonSet",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(P2DaemonSetStoreServer).CreateDaemonSet(ctx, req.(*CreateDaemonSetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _P2DaemonSetStore_EnableDaemonSet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnableDaemonSetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(P2DaemonSetStoreServer).EnableDaemonSet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/daemonsetstore.P2DaemonSetStore/EnableDaemonSet",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(P2DaemonSetStoreServer).EnableDaemonSet(ctx, req.(*EnableDaemonSetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _P2DaemonSetStore_DeleteDaemonSet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteDaemonSetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(P2DaemonSetStoreServer).DeleteDaemonSet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/daemonsetstore.P2DaemonSetStore/DeleteDaemonSet",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(P2DaemonSetStoreServer).DeleteDaemonSet(ctx, req.(*DeleteDaemonSetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _P2DaemonSetStore_UpdateManifest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateManifestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(P2DaemonSetStoreServer).UpdateManifest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/daemonsetstore.P2DaemonSetStore/UpdateManifest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(P2DaemonSetStoreServer).UpdateManifest(ctx, req.(*UpdateManifestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _P2DaemonSetStore_UpdateNodeSelector_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateNodeSelectorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(P2DaemonSetStoreServer).UpdateNodeSelector(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/daemonsetstore.P2DaemonSetStore/UpdateNodeSelector",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(P2DaemonSetStoreServer).UpdateNodeSelector(ctx, req.(*UpdateNodeSelectorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _P2DaemonSetStore_UpdateMinHealth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateMinHealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(P2DaemonSetStoreServer).UpdateMinHealth(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/daemonsetstore.P2DaemonSetStore/UpdateMinHealth",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(P2DaemonSetStoreServer).UpdateMinHealth(ctx, req.(*UpdateMinHealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _P2DaemonSetStore_UpdateTimeout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateTimeoutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(P2DaemonSetStoreServer).UpdateTimeout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/daemonsetstore.P2DaemonSetStore/UpdateTimeout",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(P2DaemonSetStoreServer).UpdateTimeout(ctx, req.(*UpdateTimeoutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _P2DaemonSetStore_GetDaemonSet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDaemonSetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(P2DaemonSetStoreServer).GetDaemonSet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/daemonsetstore.P2DaemonSetStore/GetDaemonSet",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(P2DaemonSetStoreServer).GetDaemonSet(ctx, req.(*GetDaemonSetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _P2DaemonSetStore_GetDaemonSetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDaemonSetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(P2DaemonSetStoreServer).GetDaemonSetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/daemonsetstore.P2DaemonSetStore/GetDaemonSetStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(P2DaemonSetStoreServer).GetDaemonSetStatus(ctx, req.(*GetDaemonSetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _P2DaemonSetStore_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "daemonsetstore.P2DaemonSetStore",
 	HandlerType: (*P2DaemonSetStoreServer)(nil),
@@ -383,6 +1973,42 @@ var _P2DaemonSetStore_serviceDesc = grpc.ServiceDesc{
 			MethodName: "DisableDaemonSet",
 			Handler:    _P2DaemonSetStore_DisableDaemonSet_Handler,
 		},
+		{
+			MethodName: "CreateDaemonSet",
+			Handler:    _P2DaemonSetStore_CreateDaemonSet_Handler,
+		},
+		{
+			MethodName: "EnableDaemonSet",
+			Handler:    _P2DaemonSetStore_EnableDaemonSet_Handler,
+		},
+		{
+			MethodName: "DeleteDaemonSet",
+			Handler:    _P2DaemonSetStore_DeleteDaemonSet_Handler,
+		},
+		{
+			MethodName: "UpdateManifest",
+			Handler:    _P2DaemonSetStore_UpdateManifest_Handler,
+		},
+		{
+			MethodName: "UpdateNodeSelector",
+			Handler:    _P2DaemonSetStore_UpdateNodeSelector_Handler,
+		},
+		{
+			MethodName: "UpdateMinHealth",
+			Handler:    _P2DaemonSetStore_UpdateMinHealth_Handler,
+		},
+		{
+			MethodName: "UpdateTimeout",
+			Handler:    _P2DaemonSetStore_UpdateTimeout_Handler,
+		},
+		{
+			MethodName: "GetDaemonSet",
+			Handler:    _P2DaemonSetStore_GetDaemonSet_Handler,
+		},
+		{
+			MethodName: "GetDaemonSetStatus",
+			Handler:    _P2DaemonSetStore_GetDaemonSetStatus_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -395,38 +2021,94 @@ var _P2DaemonSetStore_serviceDesc = grpc.ServiceDesc{
 }
 
 func init() {
-	proto.RegisterFile("pkg/grpc/daemonsetstore/protos/daemonsetstore.proto", fileDescriptor0)
-}
-
-var fileDescriptor0 = []byte{
-	// 454 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x84, 0x94, 0xcf, 0x6e, 0xd3, 0x40,
-	0x10, 0xc6, 0xb1, 0xd3, 0xfc, 0xf1, 0x84, 0x86, 0x6a, 0x44, 0x9b, 0x25, 0x12, 0x52, 0x64, 0x04,
-	0xf5, 0xa9, 0x41, 0xc9, 0x05, 0x21, 0x71, 0xeb, 0x81, 0x4a, 0x1c, 0x90, 0x53, 0x89, 0xa3, 0xe5,
-	0x66, 0xa7, 0xc9, 0x0a, 0xdb, 0x6b, 0xbc, 0x9b, 0x57, 0xe4, 0xc8, 0x13, 0xf0, 0x30, 0xc8, 0x6b,
-	0x67, 0xa3, 0xd8, 0x11, 0xbe, 0x65, 0xbe, 0xf9, 0x7e, 0x3b, 0xeb, 0x6f, 0x56, 0x81, 0x55, 0xfe,
-	0x73, 0xbb, 0xd8, 0x16, 0xf9, 0x66, 0xc1, 0x63, 0x4a, 0x65, 0xa6, 0x48, 0x2b, 0x2d, 0x0b, 0x5a,
-	0xe4, 0x85, 0xd4, 0x52, 0x35, 0xd4, 0x3b, 0xa3, 0xe2, 0xe4, 0x54, 0xf5, 0xff, 0x3a, 0xe0, 0xdd,
-	0x1b, 0x69, 0x4d, 0x1a, 0x27, 0xe0, 0x0a, 0xce, 0x9c, 0xb9, 0x13, 0x78, 0xa1, 0x2b, 0x38, 0xce,
-	0x60, 0xc4, 0x85, 0x8a, 0x9f, 0x12, 0xe2, 0xcc, 0x9d, 0x3b, 0xc1, 0x28, 0xb4, 0x75, 0xd9, 0x4b,
-	0xe3, 0x4c, 0x3c, 0x93, 0xd2, 0xac, 0x67, 0x08, 0x5b, 0xe3, 0x5b, 0x80, 0x54, 0x64, 0xd1, 0x8e,
-	0xe2, 0x44, 0xef, 0xd8, 0xc5, 0xdc, 0x09, 0x7a, 0xa1, 0x97, 0x8a, 0xec, 0xab, 0x11, 0x10, 0xe1,
-	0x22, 0x8b, 0x53, 0x62, 0x7d, 0x83, 0x99, 0xdf, 0xf8, 0x0e, 0x2e, 0x33, 0xc9, 0x29, 0x52, 0x94,
-	0xd0, 0x46, 0xcb, 0x82, 0x0d, 0x4c, 0xf3, 0x65, 0x29, 0xae, 0x6b, 0x0d, 0xaf, 0x61, 0x90, 0x4b,
-	0x1e, 0x09, 0xce, 0x86, 0xa6, 0xdb, 0xcf, 0x25, 0x7f, 0xe0, 0xc8, 0x60, 0xa8, 0x45, 0x4a, 0x72,
-	0xaf, 0xd9, 0xc8, 0xcc, 0x3a, 0x94, 0xfe, 0x14, 0xae, 0xbf, 0x09, 0xa5, 0xed, 0x17, 0xaa, 0x90,
-	0x7e, 0xed, 0x49, 0x69, 0xff, 0x11, 0x6e, 0x9a, 0x0d, 0x95, 0x97, 0xb9, 0xe0, 0x67, 0x18, 0x57,
-	0x19, 0x45, 0x65, 0x48, 0xcc, 0x99, 0xf7, 0x82, 0xf1, 0xf2, 0xcd, 0x5d, 0x23, 0x4d, 0x0b, 0x86,
-	0xc0, 0xed, 0x19, 0xfe, 0x17, 0x98, 0xde, 0x57, 0xf9, 0x1c, 0xfb, 0xd5, 0x40, 0xf4, 0xe1, 0xf2,
-	0x78, 0x6c, 0x64, 0x53, 0x1e, 0x5b, 0xfa, 0x81, 0xfb, 0x8f, 0xc0, 0xda, 0x78, 0x7d, 0xad, 0x4f,
-	0x00, 0x47, 0xde, 0xc0, 0xff, 0xbd, 0x95, 0x67, 0xcf, 0xf5, 0x19, 0xdc, 0xfc, 0x88, 0xf5, 0x66,
-	0xd7, 0x0e, 0xe1, 0x8f, 0x03, 0xd3, 0x56, 0xab, 0x9e, 0xb7, 0x82, 0xe1, 0xa6, 0xa0, 0x58, 0x13,
-	0xef, 0x8e, 0xe0, 0xe0, 0x2c, 0xa1, 0x7d, 0xce, 0x0d, 0xe4, 0x76, 0x42, 0xb5, 0xb3, 0x84, 0x38,
-	0x25, 0x54, 0x42, 0xbd, 0x4e, 0xa8, 0x76, 0xe2, 0x6b, 0xe8, 0x53, 0x51, 0xc8, 0xc2, 0x3c, 0x2e,
-	0x2f, 0xac, 0x8a, 0xe5, 0x6f, 0x17, 0xae, 0xbe, 0x2f, 0xad, 0x7d, 0x5d, 0xd2, 0x18, 0xc3, 0xe4,
-	0x74, 0xd5, 0xf8, 0xbe, 0x39, 0xe0, 0xec, 0x1b, 0x99, 0x7d, 0xe8, 0xb2, 0x55, 0x51, 0xf9, 0x2f,
-	0x70, 0x0b, 0x57, 0xcd, 0xc5, 0xe1, 0x6d, 0xeb, 0x2b, 0xce, 0xbf, 0x8c, 0x59, 0xd0, 0x6d, 0xb4,
-	0x83, 0x9e, 0xe1, 0x55, 0x63, 0x61, 0xd8, 0xba, 0xe5, 0xf9, 0x65, 0xcf, 0x6e, 0x3b, 0x7d, 0x87,
-	0x29, 0x1f, 0x9d, 0xa7, 0x81, 0xf9, 0xb7, 0x58, 0xfd, 0x0b, 0x00, 0x00, 0xff, 0xff, 0x7f, 0x24,
-	0x1b, 0x55, 0x64, 0x04, 0x00, 0x00,
+	proto.RegisterFile("pkg/grpc/daemonsetstore/protos/daemonsetstore.proto", fileDescriptor_daemonsetstore_4f2a0599aaff5bea)
+}
+
+var fileDescriptor_daemonsetstore_4f2a0599aaff5bea = []byte{
+	// 1358 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x58, 0x6d, 0x6f, 0xdb, 0xd4,
+	0x17, 0x97, 0x93, 0xb5, 0x69, 0x4e, 0xda, 0xa4, 0xff, 0xfb, 0x5f, 0x1b, 0xd7, 0x30, 0x2d, 0x78,
+	0x0f, 0x29, 0x20, 0x6d, 0x53, 0x27, 0xf1, 0xf4, 0x02, 0xa4, 0xad, 0x13, 0x0c, 0x31, 0xe8, 0x9c,
+	0x8e, 0x09, 0x09, 0xc9, 0x38, 0xb9, 0x67, 0x89, 0x55, 0xc7, 0x0e, 0xf7, 0xde, 0x8c, 0x76, 0xdf,
+	0x83, 0x0f, 0xc3, 0x67, 0x40, 0x42, 0xe2, 0xd3, 0xf0, 0x0e, 0xa1, 0xfb, 0xe0, 0xc4, 0xb1, 0xdd,
+	0x9a, 0xd2, 0xbd, 0xcb, 0xfd, 0x9d, 0xe7, 0x73, 0xcf, 0xb9, 0xfe, 0xb5, 0xf0, 0x70, 0x76, 0x32,
+	0xbe, 0x3f, 0x66, 0xb3, 0xd1, 0x7d, 0x1a, 0xe0, 0x34, 0x89, 0x39, 0x0a, 0x2e, 0x12, 0x86, 0xf7,
+	0x67, 0x2c, 0x11, 0x09, 0xcf, 0xa1, 0xf7, 0x14, 0x4a, 0xda, 0xab, 0xa8, 0xfb, 0x6b, 0x1d, 0x9a,
+	0x87, 0x0a, 0x1a, 0xa0, 0x20, 0x6d, 0xa8, 0x85, 0xd4, 0xb6, 0x7a, 0xd6, 0x7e, 0xd3, 0xab, 0x85,
+	0x94, 0x38, 0xb0, 0x41, 0x43, 0x1e, 0x0c, 0x23, 0xa4, 0x76, 0xad, 0x67, 0xed, 0x6f, 0x78, 0x8b,
+	0xb3, 0x94, 0x4d, 0x83, 0x38, 0x7c, 0x85, 0x5c, 0xd8, 0x75, 0x65, 0xb1, 0x38, 0x93, 0x1b, 0x00,
+	0xd3, 0x30, 0xf6, 0x27, 0x18, 0x44, 0x62, 0x62, 0x5f, 0xeb, 0x59, 0xfb, 0x75, 0xaf, 0x39, 0x0d,
+	0xe3, 0xaf, 0x14, 0x40, 0x08, 0x5c, 0x8b, 0x83, 0x29, 0xda, 0x6b, 0xca, 0x4c, 0xfd, 0x26, 0xb7,
+	0x60, 0x2b, 0x4e, 0x28, 0xfa, 0x1c, 0x23, 0x1c, 0x89, 0x84, 0xd9, 0xeb, 0x4a, 0xb8, 0x29, 0xc1,
+	0x81, 0xc1, 0xc8, 0x0e, 0xac, 0xcf, 0x12, 0xea, 0x87, 0xd4, 0x6e, 0x28, 0xe9, 0xda, 0x2c, 0xa1,
+	0x4f, 0x29, 0xb1, 0xa1, 0x21, 0xc2, 0x29, 0x26, 0x73, 0x61, 0x6f, 0xa8, 0x58, 0xe9, 0x91, 0x7c,
+	0x0d, 0xdb, 0x2c, 0x89, 0xa2, 0x30, 0x1e, 0xfb, 0x5c, 0xb0, 0x40, 0xe0, 0xf8, 0xcc, 0x6e, 0xf6,
+	0xac, 0xfd, 0xd6, 0xc1, 0xcd, 0x7b, 0xb9, 0xfe, 0x78, 0x5a, 0x6f, 0x60, 0xd4, 0xbc, 0x0e, 0x5b,
+	0x05, 0xc8, 0x87, 0xf0, 0xbf, 0x57, 0x41, 0x18, 0xcd, 0x19, 0xfa, 0x62, 0xc2, 0x90, 0x4f, 0x92,
+	0x88, 0xda, 0xa0, 0xf2, 0xd8, 0x36, 0x82, 0xe3, 0x14, 0x27, 0x9f, 0x43, 0x33, 0x79, 0x8d, 0x8c,
+	0x85, 0x14, 0xb9, 0xdd, 0xea, 0xd5, 0xf7, 0x5b, 0x07, 0xbd, 0x7c, 0xc4, 0x67, 0xa6, 0x5d, 0xdf,
+	0x19, 0x45, 0x6f, 0x69, 0xe2, 0x9e, 0x42, 0x27, 0x97, 0x10, 0xe9, 0x43, 0x67, 0x1a, 0x9c, 0xfa,
+	0xf3, 0x38, 0x78, 0x1d, 0x84, 0x91, 0xbc, 0x04, 0x73, 0x53, 0xed, 0x69, 0x70, 0xfa, 0x62, 0x89,
+	0x92, 0x77, 0xa0, 0x29, 0x15, 0xf9, 0x9c, 0x8d, 0xd1, 0xae, 0xa5, 0x57, 0x73, 0x3a, 0x90, 0x67,
+	0x72, 0x13, 0x5a, 0xc3, 0x40, 0x8c, 0x26, 0x3e, 0xc5, 0x28, 0x38, 0x53, 0x37, 0x57, 0xf7, 0x40,
+	0x41, 0x87, 0x12, 0x71, 0x9f, 0xc1, 0x76, 0x3e, 0xb1, 0xe2, 0xe5, 0x58, 0x25, 0x97, 0x73, 0x1d,
+	0xd6, 0x66, 0xd2, 0x8d, 0x09, 0xa9, 0x0f, 0x6e, 0x17, 0x76, 0xbe, 0x09, 0xb9, 0x58, 0xcc, 0x18,
+	0xf7, 0xf0, 0xe7, 0x39, 0x72, 0xe1, 0x1e, 0xc3, 0x6e, 0x5e, 0xc0, 0x67, 0xb2, 0x3b, 0xe4, 0x33,
+	0x68, 0xe9, 0x4e, 0xf9, 0xb2, 0x55, 0xb6, 0xa5, 0xba, 0xb7, 0x97, 0xef, 0xde, 0xc2, 0xd0, 0x03,
+	0xba, 0xf0, 0xe1, 0x3e, 0x87, 0xee, 0xa1, 0x9e, 0xd0, 0xa5, 0x5c, 0x07, 0x24, 0x2e, 0x6c, 0x2d,
+	0xdd, 0xfa, 0x8b, 0x39, 0x6f, 0x2d, 0xac, 0x9f, 0x52, 0x39, 0x99, 0x73, 0x8e, 0xcc, 0x94, 0xa0,
+	0x7e, 0xbb, 0xc7, 0x60, 0x17, 0x5d, 0x9a, 0x54, 0x3f, 0x01, 0x58, 0xfa, 0x54, 0x0e, 0x2f, 0xcc,
+	0xb4, 0xb9, 0x88, 0xe5, 0xda, 0xb0, 0xfb, 0x52, 0x35, 0xbd, 0xd0, 0x98, 0x3f, 0x2c, 0xe8, 0x16,
+	0x44, 0x26, 0xde, 0x43, 0x68, 0x8c, 0x18, 0x06, 0x02, 0x69, 0x75, 0x5b, 0x52, 0x4d, 0x69, 0x34,
+	0x9f, 0x51, 0x65, 0x54, 0xab, 0x34, 0x32, 0x9a, 0xd2, 0x88, 0x62, 0x84, 0xd2, 0xa8, 0x5e, 0x69,
+	0x64, 0x34, 0xe5, 0x08, 0x20, 0x63, 0x09, 0x53, 0x2b, 0xdf, 0xf4, 0xf4, 0xc1, 0xfd, 0xd3, 0x82,
+	0xdd, 0xc7, 0x2a, 0x97, 0xc2, 0x9d, 0x64, 0x1f, 0x11, 0xeb, 0xc2, 0x47, 0xa4, 0x76, 0xde, 0x23,
+	0x52, 0xbf, 0xe8, 0x11, 0xb9, 0x76, 0xe1, 0x23, 0xb2, 0x76, 0xce, 0x23, 0xb2, 0xbe, 0xfa, 0x88,
+	0xa4, 0x43, 0xd1, 0xc8, 0x0c, 0xc5, 0x00, 0xba, 0x85, 0x92, 0xae, 0x3c, 0x13, 0x47, 0xb0, 0xfb,
+	0x24, 0x7e, 0xab, 0xb3, 0x3b, 0x80, 0x6e, 0xc1, 0xe3, 0xdb, 0x48, 0xf3, 0x50, 0x5d, 0xf8, 0x5b,
+	0x4b, 0x73, 0x0f, 0xba, 0x05, 0x8f, 0x3a, 0x4d, 0xf7, 0x04, 0x76, 0x5e, 0xa8, 0x91, 0x4c, 0x1f,
+	0xa5, 0xcb, 0xc4, 0xca, 0x8e, 0x57, 0x2d, 0x37, 0x5e, 0x69, 0x1e, 0xf5, 0x4c, 0x1e, 0x1e, 0xec,
+	0xe6, 0x83, 0x5d, 0xb9, 0x5b, 0xa7, 0xb0, 0xa7, 0x7d, 0x7e, 0x9b, 0x19, 0xc2, 0xcb, 0x14, 0x51,
+	0x18, 0xea, 0x5a, 0xc9, 0x50, 0x97, 0x55, 0xf3, 0x3d, 0x38, 0x65, 0x91, 0xaf, 0x5c, 0x51, 0xb2,
+	0xe8, 0x52, 0xba, 0x8c, 0x97, 0x29, 0xa7, 0x7a, 0xad, 0x0b, 0x85, 0x0c, 0xa0, 0x5b, 0x08, 0x78,
+	0xe5, 0x2a, 0x26, 0x70, 0x5d, 0x3b, 0x3d, 0xd6, 0x6b, 0x7e, 0x99, 0x1a, 0x32, 0x6f, 0x45, 0xad,
+	0xfc, 0xad, 0xc8, 0xa6, 0xff, 0x1c, 0x76, 0x72, 0x91, 0xae, 0x9c, 0xfc, 0xa7, 0xf0, 0xff, 0x2f,
+	0x51, 0xfc, 0x97, 0xfd, 0x73, 0x8f, 0xe0, 0xfa, 0xaa, 0xe9, 0x95, 0x93, 0xf9, 0x02, 0xf6, 0xb2,
+	0x1e, 0x07, 0x22, 0x10, 0x73, 0x7e, 0x99, 0x94, 0xfe, 0xae, 0x41, 0x27, 0x67, 0x4e, 0xde, 0x83,
+	0xcd, 0x74, 0x55, 0x7d, 0x3e, 0x09, 0x52, 0xb3, 0x14, 0x1b, 0x4c, 0x02, 0x72, 0x07, 0xda, 0x72,
+	0x07, 0xb8, 0x4f, 0x71, 0x16, 0x25, 0x67, 0x86, 0xa3, 0xd6, 0x3d, 0xb5, 0x2e, 0xfc, 0xd0, 0x80,
+	0xe4, 0x23, 0xe8, 0x32, 0x9c, 0x45, 0xe1, 0x28, 0x10, 0x61, 0x12, 0xfb, 0x61, 0xec, 0xcf, 0x58,
+	0x32, 0x66, 0xc8, 0xb9, 0xba, 0xa5, 0x0d, 0x6f, 0x27, 0x23, 0x7e, 0x1a, 0x1f, 0x19, 0x21, 0xf9,
+	0x18, 0x1a, 0x86, 0x02, 0xaa, 0xcf, 0x48, 0xeb, 0xe0, 0xc6, 0xb9, 0x94, 0x51, 0x15, 0x9c, 0x6a,
+	0xcb, 0x80, 0x29, 0x51, 0x1c, 0xce, 0xe9, 0x18, 0x85, 0x8f, 0xa7, 0x23, 0x44, 0x8a, 0xfa, 0x8b,
+	0xb3, 0xe1, 0xed, 0x18, 0xf1, 0x23, 0x25, 0x7d, 0x62, 0x84, 0xb2, 0x64, 0x29, 0x40, 0xea, 0xab,
+	0x02, 0xec, 0xf5, 0x5e, 0x5d, 0x96, 0xac, 0x31, 0xb9, 0xc3, 0x9c, 0x3c, 0x80, 0x35, 0x2d, 0x6b,
+	0xa8, 0x8c, 0x9c, 0x7c, 0x46, 0x6a, 0xd3, 0x75, 0x3a, 0x5a, 0x51, 0xbe, 0x1e, 0xa3, 0x24, 0x16,
+	0x18, 0x53, 0xee, 0xff, 0x12, 0x8a, 0x89, 0x62, 0xc8, 0x4d, 0x6f, 0x33, 0x05, 0x5f, 0x86, 0x62,
+	0xe2, 0xfe, 0x65, 0xc1, 0xd6, 0x4a, 0x31, 0x92, 0xec, 0x2a, 0x4e, 0x88, 0xdc, 0x1f, 0x25, 0xd3,
+	0x99, 0x26, 0x02, 0x96, 0x6a, 0xef, 0xb6, 0x11, 0x3c, 0x4e, 0x71, 0xb9, 0xd2, 0x0a, 0xf3, 0x79,
+	0xf8, 0x06, 0xd3, 0x95, 0x56, 0xc8, 0x20, 0x7c, 0x83, 0x92, 0xb8, 0xea, 0x7b, 0x62, 0x38, 0x0d,
+	0xc2, 0x58, 0x36, 0x54, 0xd3, 0x4e, 0x7d, 0x7d, 0x5e, 0x8a, 0x92, 0x1e, 0xb4, 0xb2, 0xec, 0x56,
+	0xff, 0xdd, 0x90, 0x85, 0xa4, 0xab, 0x61, 0x94, 0x8c, 0x4e, 0x24, 0xa1, 0x67, 0x18, 0xf0, 0x24,
+	0x36, 0x1f, 0xf1, 0x76, 0x0a, 0x7b, 0x0a, 0x25, 0x77, 0xa1, 0x13, 0x05, 0x5c, 0xf8, 0x3a, 0x2f,
+	0xb9, 0x9d, 0xe6, 0xab, 0xbe, 0x25, 0xe1, 0x47, 0x12, 0x95, 0xdb, 0xe8, 0xfe, 0x6e, 0x01, 0x2c,
+	0x9b, 0x26, 0x3f, 0x18, 0x18, 0x85, 0xe3, 0x30, 0x25, 0xd7, 0x75, 0x6f, 0x71, 0x26, 0xef, 0x42,
+	0x93, 0x8f, 0x26, 0x48, 0xe7, 0xd1, 0x62, 0xd2, 0x96, 0x80, 0x7c, 0x12, 0x46, 0x73, 0xc6, 0x30,
+	0x16, 0xa6, 0xb8, 0xf4, 0x28, 0x25, 0xfa, 0xb1, 0x3b, 0x33, 0x15, 0xa5, 0x47, 0xe9, 0x71, 0x1e,
+	0xa7, 0xb2, 0x35, 0xed, 0x71, 0x01, 0x48, 0xbb, 0x19, 0xc6, 0x54, 0xb6, 0xcb, 0x10, 0x12, 0x73,
+	0x24, 0x5d, 0x68, 0x24, 0x11, 0x55, 0x6b, 0xd1, 0x50, 0x33, 0xb2, 0x9e, 0x44, 0x74, 0x30, 0x09,
+	0x0e, 0x7e, 0x6b, 0xc2, 0xf6, 0xd1, 0x41, 0x66, 0x95, 0x12, 0x86, 0xc4, 0x87, 0xf6, 0x2a, 0xd1,
+	0x26, 0x77, 0xf2, 0x63, 0x53, 0xca, 0xd0, 0x9d, 0xbb, 0x55, 0x6a, 0xe6, 0xe5, 0x40, 0xd8, 0xce,
+	0x13, 0x64, 0xd2, 0x2f, 0xbc, 0x1c, 0xe5, 0xac, 0xdc, 0xd9, 0xaf, 0x56, 0x34, 0x61, 0x28, 0x74,
+	0x72, 0xb4, 0x98, 0x14, 0x32, 0x2c, 0xa7, 0xd4, 0x4e, 0xbf, 0x52, 0x4f, 0xc7, 0x78, 0x60, 0x91,
+	0x21, 0x74, 0x72, 0xc4, 0xae, 0x18, 0xa5, 0x9c, 0xcc, 0x3a, 0xfd, 0x4a, 0x3d, 0x53, 0xc9, 0x10,
+	0x3a, 0x39, 0x56, 0x56, 0x8c, 0x51, 0x4e, 0x04, 0x9d, 0x7e, 0xa5, 0xde, 0x32, 0x46, 0x8e, 0x52,
+	0x15, 0x63, 0x94, 0xb3, 0x38, 0xa7, 0x5f, 0xa9, 0x67, 0x62, 0xf8, 0xd0, 0x5e, 0xa5, 0x4b, 0xc5,
+	0xc9, 0x2a, 0xe5, 0x6e, 0xce, 0xdd, 0x2a, 0x35, 0x13, 0xe0, 0x04, 0x48, 0x91, 0xc1, 0x90, 0xf7,
+	0xcb, 0xad, 0x4b, 0xf8, 0x95, 0xf3, 0xc1, 0xbf, 0x51, 0x5d, 0x76, 0x2c, 0xc7, 0x32, 0xc8, 0x79,
+	0x79, 0xe6, 0x78, 0x8f, 0xd3, 0xaf, 0xd4, 0x33, 0x31, 0x7e, 0x84, 0xad, 0x15, 0x2a, 0x40, 0x6e,
+	0x97, 0x5b, 0xae, 0x72, 0x12, 0xe7, 0x4e, 0x85, 0x96, 0xf1, 0xfe, 0x03, 0x6c, 0x66, 0x3f, 0xc4,
+	0xe4, 0x56, 0xde, 0xac, 0x84, 0x33, 0x38, 0xb7, 0x2f, 0x56, 0x32, 0xae, 0x7f, 0x02, 0x52, 0xfc,
+	0xc6, 0x17, 0x6f, 0xe2, 0x5c, 0x1e, 0xe0, 0xdc, 0x3c, 0x97, 0x4a, 0x68, 0xbd, 0xe1, 0xba, 0xfa,
+	0x07, 0xd5, 0xc3, 0x7f, 0x06, 0x00, 0x4b, 0xe9, 0x87, 0xa5, 0xd7, 0x12, 0x00, 0x00,
 }
@@ -0,0 +1,274 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	klabels "k8s.io/kubernetes/pkg/labels"
+
+	"github.com/square/p2/pkg/grpc/pcstore"
+	pcstore_protos "github.com/square/p2/pkg/grpc/pcstore/protos"
+	"github.com/square/p2/pkg/logging"
+	"github.com/square/p2/pkg/pc/fields"
+	rc_fields "github.com/square/p2/pkg/rc/fields"
+	consul_pcstore "github.com/square/p2/pkg/store/consul/pcstore"
+	"github.com/square/p2/pkg/types"
+	"github.com/square/p2/pkg/util"
+)
+
+type Client struct {
+	client pcstore_protos.P2PodClusterStoreClient
+	logger logging.Logger
+}
+
+func New(conn *grpc.ClientConn, logger logging.Logger) Client {
+	return Client{
+		client: pcstore_protos.NewP2PodClusterStoreClient(conn),
+		logger: logger,
+	}
+}
+
+// Create creates a pod cluster. If podSelector is nil the pod cluster selects
+// the pods labeled with its pod ID, availability zone and cluster name.
+func (c Client) Create(
+	ctx context.Context,
+	podID types.PodID,
+	availabilityZone fields.AvailabilityZone,
+	clusterName fields.ClusterName,
+	podSelector klabels.Selector,
+	annotations fields.Annotations,
+	allocationStrategy rc_fields.Strategy,
+	minHealthPercentage fields.MinHealthPercentage,
+) (fields.PodCluster, error) {
+	annotationsJSON, err := json.Marshal(annotations)
+	if err != nil {
+		return fields.PodCluster{}, util.Errorf("could not marshal annotations as JSON: %s", err)
+	}
+	var selector string
+	if podSelector != nil {
+		selector = podSelector.String()
+	}
+
+	resp, err := c.client.CreatePodCluster(ctx, &pcstore_protos.CreatePodClusterRequest{
+		PodId:               podID.String(),
+		AvailabilityZone:    availabilityZone.String(),
+		ClusterName:         clusterName.String(),
+		PodSelector:         selector,
+		Annotations:         string(annotationsJSON),
+		AllocationStrategy:  allocationStrategy.String(),
+		MinHealthPercentage: int64(minHealthPercentage),
+	})
+	if err != nil {
+		return fields.PodCluster{}, util.Errorf("create pod cluster grpc failed: %s", err)
+	}
+	return updatedPC("creating", resp.PodCluster)
+}
+
+func (c Client) Get(ctx context.Context, id fields.ID) (fields.PodCluster, error) {
+	resp, err := c.client.GetPodCluster(ctx, &pcstore_protos.GetPodClusterRequest{
+		PodClusterId: id.String(),
+	})
+	if err != nil {
+		return fields.PodCluster{}, util.Errorf("get pod cluster grpc for %s failed: %s", id, err)
+	}
+
+	pc, err := pcstore.ProtoToPodCluster(resp.PodCluster)
+	if err != nil {
+		return fields.PodCluster{}, util.Errorf("get pod cluster grpc for %s failed: %s", id, err)
+	}
+	return pc, nil
+}
+
+func (c Client) List(ctx context.Context) ([]fields.PodCluster, error) {
+	resp, err := c.client.ListPodClusters(ctx, &pcstore_protos.ListPodClustersRequest{})
+	if err != nil {
+		return nil, util.Errorf("list pod clusters grpc failed: %s", err)
+	}
+
+	pcs, err := protosToPodClusters(resp.PodClusters)
+	if err != nil {
+		return nil, util.Errorf("list pod clusters grpc failed: %s", err)
+	}
+	return pcs, nil
+}
+
+func (c Client) Delete(ctx context.Context, id fields.ID) error {
+	_, err := c.client.DeletePodCluster(ctx, &pcstore_protos.DeletePodClusterRequest{
+		PodClusterId: id.String(),
+	})
+	if err != nil {
+		return util.Errorf("delete pod cluster grpc for %s failed: %s", id, err)
+	}
+	return nil
+}
+
+// Watch sends every pod cluster on the returned channel when called and each
+// time any of them changes, until ctx is canceled. Like the consul store's
+// Watch, errors reading the pod clusters are sent in place of them.
+func (c Client) Watch(ctx context.Context) <-chan consul_pcstore.WatchedPodClusters {
+	outCh := make(chan consul_pcstore.WatchedPodClusters)
+
+	go func() {
+		defer close(outCh)
+
+		stream, err := c.client.WatchPodClusters(ctx, &pcstore_protos.WatchPodClustersRequest{})
+		if err != nil {
+			c.sendWatched(ctx, outCh, consul_pcstore.WatchedPodClusters{
+				Err: util.Errorf("watch pod clusters grpc failed: %s", err),
+			})
+			return
+		}
+
+		for {
+			resp, err := stream.Recv()
+			if grpc.Code(err) == codes.Canceled {
+				c.logger.Infoln("pcstore grpc client: terminating Watch()")
+				return
+			} else if err != nil {
+				c.sendWatched(ctx, outCh, consul_pcstore.WatchedPodClusters{
+					Err: util.Errorf("watch pod clusters grpc failed: %s", err),
+				})
+				return
+			}
+
+			var watched consul_pcstore.WatchedPodClusters
+			if resp.Error != "" {
+				watched.Err = util.Errorf("%s", resp.Error)
+			} else {
+				pcs, err := protosToPodClusters(resp.PodClusters)
+				if err != nil {
+					watched.Err = err
+				}
+				for i := range pcs {
+					watched.Clusters = append(watched.Clusters, &pcs[i])
+				}
+			}
+
+			if !c.sendWatched(ctx, outCh, watched) {
+				return
+			}
+		}
+	}()
+
+	return outCh
+}
+
+func (c Client) sendWatched(ctx context.Context, outCh chan<- consul_pcstore.WatchedPodClusters, watched consul_pcstore.WatchedPodClusters) bool {
+	select {
+	case outCh <- watched:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (c Client) UpdateAnnotations(ctx context.Context, id fields.ID, annotations fields.Annotations) (fields.PodCluster, error) {
+	annotationsJSON, err := json.Marshal(annotations)
+	if err != nil {
+		return fields.PodCluster{}, util.Errorf("could not marshal annotations as JSON: %s", err)
+	}
+
+	resp, err := c.client.UpdateAnnotations(ctx, &pcstore_protos.UpdateAnnotationsRequest{
+		PodClusterId: id.String(),
+		Annotations:  string(annotationsJSON),
+	})
+	if err != nil {
+		return fields.PodCluster{}, util.Errorf("update annotations grpc for %s failed: %s", id, err)
+	}
+	return updatedPC("updating", resp.PodCluster)
+}
+
+func (c Client) UpdatePodSelector(ctx context.Context, id fields.ID, podSelector klabels.Selector) (fields.PodCluster, error) {
+	resp, err := c.client.UpdatePodSelector(ctx, &pcstore_protos.UpdatePodSelectorRequest{
+		PodClusterId: id.String(),
+		PodSelector:  podSelector.String(),
+	})
+	if err != nil {
+		return fields.PodCluster{}, util.Errorf("update pod selector grpc for %s failed: %s", id, err)
+	}
+	return updatedPC("updating", resp.PodCluster)
+}
+
+func (c Client) UpdateAllocationStrategy(ctx context.Context, id fields.ID, strategy rc_fields.Strategy) (fields.PodCluster, error) {
+	resp, err := c.client.UpdateAllocationStrategy(ctx, &pcstore_protos.UpdateAllocationStrategyRequest{
+		PodClusterId:       id.String(),
+		AllocationStrategy: strategy.String(),
+	})
+	if err != nil {
+		return fields.PodCluster{}, util.Errorf("update allocation strategy grpc for %s failed: %s", id, err)
+	}
+	return updatedPC("updating", resp.PodCluster)
+}
+
+// WatchAndSync calls the syncer's functions as the consul store's
+// WatchAndSync would, with the watch running on the server. Unlike the
+// consul store's it calls them one at a time, and doesn't retry a
+// SyncCluster that fails. It returns when ctx is canceled or the stream
+// fails.
+func (c Client) WatchAndSync(ctx context.Context, syncer consul_pcstore.ConcreteSyncer) error {
+	initial, err := syncer.GetInitialClusters()
+	if err != nil {
+		return err
+	}
+	initialIDs := make([]string, len(initial))
+	for i, id := range initial {
+		initialIDs[i] = id.String()
+	}
+
+	stream, err := c.client.WatchAndSync(ctx, &pcstore_protos.WatchAndSyncRequest{
+		InitialPodClusterIds: initialIDs,
+	})
+	if err != nil {
+		return util.Errorf("watch and sync grpc failed: %s", err)
+	}
+
+	for {
+		event, err := stream.Recv()
+		if grpc.Code(err) == codes.Canceled {
+			c.logger.Infoln("pcstore grpc client: terminating WatchAndSync()")
+			return nil
+		} else if err != nil {
+			return util.Errorf("watch and sync grpc failed: %s", err)
+		}
+
+		if event.DeletedPodClusterId != "" {
+			err = syncer.DeleteCluster(fields.ID(event.DeletedPodClusterId))
+			if err != nil {
+				c.logger.WithError(err).Errorf("Deletion of cluster %s failed", event.DeletedPodClusterId)
+			}
+			continue
+		}
+
+		pc, err := pcstore.ProtoToPodCluster(event.PodCluster)
+		if err != nil {
+			c.logger.WithError(err).Errorln("Could not convert synced pod cluster from proto type")
+			continue
+		}
+		err = syncer.SyncCluster(&pc, pcstore.ProtoToLabeledPods(event.Pods))
+		if err != nil {
+			c.logger.WithError(err).Errorf("Failed to SyncCluster on %s", pc.ID)
+		}
+	}
+}
+
+func updatedPC(action string, pcProto *pcstore_protos.PodCluster) (fields.PodCluster, error) {
+	pc, err := pcstore.ProtoToPodCluster(pcProto)
+	if err != nil {
+		return fields.PodCluster{}, util.Errorf("%s succeeded but could not convert from grpc proto type to PodCluster: %s", action, err)
+	}
+	return pc, nil
+}
+
+func protosToPodClusters(protos []*pcstore_protos.PodCluster) ([]fields.PodCluster, error) {
+	out := make([]fields.PodCluster, len(protos))
+	for i, proto := range protos {
+		var err error
+		out[i], err = pcstore.ProtoToPodCluster(proto)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,470 @@
+package pcstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	klabels "k8s.io/kubernetes/pkg/labels"
+
+	pcstore_protos "github.com/square/p2/pkg/grpc/pcstore/protos"
+	"github.com/square/p2/pkg/labels"
+	"github.com/square/p2/pkg/pc/fields"
+	rc_fields "github.com/square/p2/pkg/rc/fields"
+	"github.com/square/p2/pkg/store/consul"
+	"github.com/square/p2/pkg/store/consul/pcstore"
+	"github.com/square/p2/pkg/types"
+	"github.com/square/p2/pkg/util"
+)
+
+type PodClusterStore interface {
+	Create(
+		podID types.PodID,
+		availabilityZone fields.AvailabilityZone,
+		clusterName fields.ClusterName,
+		podSelector klabels.Selector,
+		annotations fields.Annotations,
+		allocationStrategy rc_fields.Strategy,
+		minHealthPercentage fields.MinHealthPercentage,
+		session pcstore.Session,
+	) (fields.PodCluster, error)
+	Get(id fields.ID) (fields.PodCluster, error)
+	List() ([]fields.PodCluster, error)
+	Delete(id fields.ID) error
+	MutatePC(id fields.ID, mutator func(fields.PodCluster) (fields.PodCluster, error)) (fields.PodCluster, error)
+	Watch(quit <-chan struct{}) <-chan pcstore.WatchedPodClusters
+	WatchAndSync(syncer pcstore.ConcreteSyncer, quit <-chan struct{}) error
+}
+
+// Sessioner creates the consul sessions that hold the lock taken while a pod
+// cluster is created
+type Sessioner interface {
+	NewSession(name string, renewalCh <-chan time.Time) (consul.Session, chan error, error)
+}
+
+type Store struct {
+	pcStore   PodClusterStore
+	sessioner Sessioner
+}
+
+func NewServer(pcStore PodClusterStore, sessioner Sessioner) Store {
+	return Store{
+		pcStore:   pcStore,
+		sessioner: sessioner,
+	}
+}
+
+var _ pcstore_protos.P2PodClusterStoreServer = Store{}
+
+func (s Store) CreatePodCluster(_ context.Context, req *pcstore_protos.CreatePodClusterRequest) (*pcstore_protos.CreatePodClusterResponse, error) {
+	if req.PodId == "" || req.AvailabilityZone == "" || req.ClusterName == "" {
+		return nil, grpc.Errorf(codes.InvalidArgument, "pod_id, availability_zone and cluster_name must be set")
+	}
+	if req.MinHealthPercentage < 0 || req.MinHealthPercentage > 100 {
+		return nil, grpc.Errorf(codes.InvalidArgument, "min_health_percentage must be between 0 and 100")
+	}
+	podID := types.PodID(req.PodId)
+	availabilityZone := fields.AvailabilityZone(req.AvailabilityZone)
+	clusterName := fields.ClusterName(req.ClusterName)
+
+	podSelector := defaultSelector(availabilityZone, clusterName, podID)
+	if req.PodSelector != "" {
+		var err error
+		podSelector, err = parseSelector(req.PodSelector)
+		if err != nil {
+			return nil, err
+		}
+	}
+	annotations, err := parseAnnotations(req.Annotations)
+	if err != nil {
+		return nil, err
+	}
+	strategy, err := parseStrategy(req.AllocationStrategy)
+	if err != nil {
+		return nil, err
+	}
+
+	session, _, err := s.sessioner.NewSession(fmt.Sprintf("pcstore-grpc-create-%s-%s-%s", podID, availabilityZone, clusterName), nil)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Unavailable, "could not create session to lock pod cluster creation: %s", err)
+	}
+	defer func() {
+		_ = session.Destroy()
+	}()
+
+	pc, err := s.pcStore.Create(
+		podID,
+		availabilityZone,
+		clusterName,
+		podSelector,
+		annotations,
+		strategy,
+		fields.MinHealthPercentage(req.MinHealthPercentage),
+		session,
+	)
+	switch {
+	case pcstore.IsAlreadyExists(err):
+		return nil, grpc.Errorf(codes.AlreadyExists, "pod cluster %s already exists for (%s, %s, %s)", pc.ID, podID, availabilityZone, clusterName)
+	case consul.IsAlreadyLocked(err):
+		return nil, grpc.Errorf(codes.Aborted, "pod cluster (%s, %s, %s) is already being created", podID, availabilityZone, clusterName)
+	case err != nil:
+		return nil, grpc.Errorf(codes.Unavailable, "could not create pod cluster: %s", err)
+	}
+
+	pcProto, err := PodClusterToProto(pc)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Unavailable, "create succeeded, but could not convert pod cluster to proto type: %s", err)
+	}
+	return &pcstore_protos.CreatePodClusterResponse{
+		PodCluster: pcProto,
+	}, nil
+}
+
+func (s Store) GetPodCluster(_ context.Context, req *pcstore_protos.GetPodClusterRequest) (*pcstore_protos.GetPodClusterResponse, error) {
+	if req.PodClusterId == "" {
+		return nil, grpc.Errorf(codes.InvalidArgument, "pod_cluster_id must be set")
+	}
+	id := fields.ID(req.PodClusterId)
+
+	pc, err := s.pcStore.Get(id)
+	if err != nil {
+		return nil, convertPCStoreError(id, "get", err)
+	}
+
+	pcProto, err := PodClusterToProto(pc)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Unavailable, err.Error())
+	}
+	return &pcstore_protos.GetPodClusterResponse{
+		PodCluster: pcProto,
+	}, nil
+}
+
+func (s Store) ListPodClusters(_ context.Context, _ *pcstore_protos.ListPodClustersRequest) (*pcstore_protos.ListPodClustersResponse, error) {
+	pcs, err := s.pcStore.List()
+	if err != nil {
+		return nil, grpc.Errorf(codes.Unavailable, "could not list pod clusters: %s", err)
+	}
+
+	pcProtos, err := podClustersToProto(pcs)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Unavailable, err.Error())
+	}
+	return &pcstore_protos.ListPodClustersResponse{
+		PodClusters: pcProtos,
+	}, nil
+}
+
+func (s Store) DeletePodCluster(_ context.Context, req *pcstore_protos.DeletePodClusterRequest) (*pcstore_protos.DeletePodClusterResponse, error) {
+	if req.PodClusterId == "" {
+		return nil, grpc.Errorf(codes.InvalidArgument, "pod_cluster_id must be set")
+	}
+	id := fields.ID(req.PodClusterId)
+
+	// The store's delete succeeds whether or not the pod cluster exists
+	_, err := s.pcStore.Get(id)
+	if err != nil {
+		return nil, convertPCStoreError(id, "delete", err)
+	}
+
+	err = s.pcStore.Delete(id)
+	if err != nil {
+		return nil, convertPCStoreError(id, "delete", err)
+	}
+	return &pcstore_protos.DeletePodClusterResponse{}, nil
+}
+
+func (s Store) WatchPodClusters(_ *pcstore_protos.WatchPodClustersRequest, stream pcstore_protos.P2PodClusterStore_WatchPodClustersServer) error {
+	clientCancel := stream.Context().Done()
+	watched := s.pcStore.Watch(clientCancel)
+	for {
+		select {
+		case <-clientCancel:
+			return nil
+		case result := <-watched:
+			resp := &pcstore_protos.WatchPodClustersResponse{}
+			if result.Err != nil {
+				resp.Error = result.Err.Error()
+			} else {
+				pcs := make([]fields.PodCluster, len(result.Clusters))
+				for i, pc := range result.Clusters {
+					pcs[i] = *pc
+				}
+				var err error
+				resp.PodClusters, err = podClustersToProto(pcs)
+				if err != nil {
+					return grpc.Errorf(codes.Unavailable, err.Error())
+				}
+			}
+
+			err := stream.Send(resp)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s Store) UpdateAnnotations(_ context.Context, req *pcstore_protos.UpdateAnnotationsRequest) (*pcstore_protos.UpdatePodClusterResponse, error) {
+	annotations, err := parseAnnotations(req.Annotations)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.mutate(req.PodClusterId, "update the annotations of", func(pc fields.PodCluster) (fields.PodCluster, error) {
+		pc.Annotations = annotations
+		return pc, nil
+	})
+}
+
+func (s Store) UpdatePodSelector(_ context.Context, req *pcstore_protos.UpdatePodSelectorRequest) (*pcstore_protos.UpdatePodClusterResponse, error) {
+	podSelector, err := parseSelector(req.PodSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.mutate(req.PodClusterId, "update the pod selector of", func(pc fields.PodCluster) (fields.PodCluster, error) {
+		pc.PodSelector = podSelector
+		return pc, nil
+	})
+}
+
+func (s Store) UpdateAllocationStrategy(_ context.Context, req *pcstore_protos.UpdateAllocationStrategyRequest) (*pcstore_protos.UpdatePodClusterResponse, error) {
+	strategy, err := parseStrategy(req.AllocationStrategy)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.mutate(req.PodClusterId, "update the allocation strategy of", func(pc fields.PodCluster) (fields.PodCluster, error) {
+		pc.AllocationStrategy = strategy
+		return pc, nil
+	})
+}
+
+func (s Store) mutate(
+	pcID string,
+	action string,
+	mutator func(fields.PodCluster) (fields.PodCluster, error),
+) (*pcstore_protos.UpdatePodClusterResponse, error) {
+	if pcID == "" {
+		return nil, grpc.Errorf(codes.InvalidArgument, "pod_cluster_id must be set")
+	}
+	id := fields.ID(pcID)
+
+	pc, err := s.pcStore.MutatePC(id, mutator)
+	if err != nil {
+		return nil, convertPCStoreError(id, action, err)
+	}
+
+	pcProto, err := PodClusterToProto(pc)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Unavailable, "update succeeded, but could not convert pod cluster to proto type: %s", err)
+	}
+	return &pcstore_protos.UpdatePodClusterResponse{
+		PodCluster: pcProto,
+	}, nil
+}
+
+func (s Store) WatchAndSync(req *pcstore_protos.WatchAndSyncRequest, stream pcstore_protos.P2PodClusterStore_WatchAndSyncServer) error {
+	clientCancel := stream.Context().Done()
+	syncer := streamSyncer{
+		initial: make([]fields.ID, len(req.InitialPodClusterIds)),
+		events:  make(chan *pcstore_protos.SyncEvent),
+		quit:    clientCancel,
+	}
+	for i, id := range req.InitialPodClusterIds {
+		syncer.initial[i] = fields.ID(id)
+	}
+
+	syncErr := make(chan error, 1)
+	go func() {
+		syncErr <- s.pcStore.WatchAndSync(syncer, clientCancel)
+	}()
+
+	for {
+		select {
+		case <-clientCancel:
+			return nil
+		case event := <-syncer.events:
+			err := stream.Send(event)
+			if err != nil {
+				return err
+			}
+		case err := <-syncErr:
+			if err != nil {
+				return grpc.Errorf(codes.Unavailable, "could not watch pod clusters: %s", err)
+			}
+			return nil
+		}
+	}
+}
+
+// streamSyncer is the pcstore.ConcreteSyncer behind the WatchAndSync RPC. It
+// hands each call to the RPC as an event, to be synced by the client.
+type streamSyncer struct {
+	initial []fields.ID
+	events  chan *pcstore_protos.SyncEvent
+	quit    <-chan struct{}
+}
+
+var _ pcstore.ConcreteSyncer = streamSyncer{}
+
+func (s streamSyncer) SyncCluster(pc *fields.PodCluster, pods []labels.Labeled) error {
+	pcProto, err := PodClusterToProto(*pc)
+	if err != nil {
+		return err
+	}
+	return s.send(&pcstore_protos.SyncEvent{
+		PodCluster: pcProto,
+		Pods:       LabeledPodsToProto(pods),
+	})
+}
+
+func (s streamSyncer) DeleteCluster(id fields.ID) error {
+	return s.send(&pcstore_protos.SyncEvent{
+		DeletedPodClusterId: id.String(),
+	})
+}
+
+func (s streamSyncer) GetInitialClusters() ([]fields.ID, error) {
+	return s.initial, nil
+}
+
+func (s streamSyncer) Type() pcstore.ConcreteSyncerType {
+	return "grpc_stream"
+}
+
+func (s streamSyncer) send(event *pcstore_protos.SyncEvent) error {
+	select {
+	case s.events <- event:
+		return nil
+	case <-s.quit:
+		return util.Errorf("the sync stream was closed")
+	}
+}
+
+func convertPCStoreError(id fields.ID, action string, err error) error {
+	if pcstore.IsNotExist(err) {
+		return grpc.Errorf(codes.NotFound, "no pod cluster with id %s was found", id)
+	}
+	return grpc.Errorf(codes.Unavailable, "could not %s pod cluster %s: %s", action, id, err)
+}
+
+func defaultSelector(az fields.AvailabilityZone, cn fields.ClusterName, podID types.PodID) klabels.Selector {
+	return klabels.Everything().
+		Add(fields.PodIDLabel, klabels.EqualsOperator, []string{podID.String()}).
+		Add(fields.AvailabilityZoneLabel, klabels.EqualsOperator, []string{az.String()}).
+		Add(fields.ClusterNameLabel, klabels.EqualsOperator, []string{cn.String()})
+}
+
+func parseSelector(selector string) (klabels.Selector, error) {
+	podSelector, err := klabels.Parse(selector)
+	if err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, "could not parse pod selector: %s", err)
+	}
+	return podSelector, nil
+}
+
+func parseAnnotations(annotations string) (fields.Annotations, error) {
+	parsed := fields.Annotations{}
+	if annotations == "" {
+		return parsed, nil
+	}
+	err := json.Unmarshal([]byte(annotations), &parsed)
+	if err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, "annotations must be a JSON object: %s", err)
+	}
+	return parsed, nil
+}
+
+func parseStrategy(strategy string) (rc_fields.Strategy, error) {
+	switch rc_fields.Strategy(strategy) {
+	case rc_fields.StaticStrategy, rc_fields.DynamicStrategy:
+		return rc_fields.Strategy(strategy), nil
+	default:
+		return "", grpc.Errorf(codes.InvalidArgument, "allocation_strategy must be %s or %s", rc_fields.StaticStrategy, rc_fields.DynamicStrategy)
+	}
+}
+
+func podClustersToProto(pcs []fields.PodCluster) ([]*pcstore_protos.PodCluster, error) {
+	out := make([]*pcstore_protos.PodCluster, len(pcs))
+	for i, pc := range pcs {
+		var err error
+		out[i], err = PodClusterToProto(pc)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func PodClusterToProto(pc fields.PodCluster) (*pcstore_protos.PodCluster, error) {
+	rawPC := pc.ToRaw()
+	annotations, err := json.Marshal(rawPC.Annotations)
+	if err != nil {
+		return nil, util.Errorf("could not marshal annotations of pod cluster %s as JSON: %s", pc.ID, err)
+	}
+
+	return &pcstore_protos.PodCluster{
+		Id:                  rawPC.ID.String(),
+		PodId:               rawPC.PodID.String(),
+		AvailabilityZone:    rawPC.AvailabilityZone.String(),
+		Name:                rawPC.Name.String(),
+		PodSelector:         rawPC.PodSelector,
+		Annotations:         string(annotations),
+		AllocationStrategy:  rawPC.AllocationStrategy.String(),
+		MinHealthPercentage: int64(rawPC.MinHealthPercentage),
+	}, nil
+}
+
+func ProtoToPodCluster(proto *pcstore_protos.PodCluster) (fields.PodCluster, error) {
+	podSelector, err := klabels.Parse(proto.PodSelector)
+	if err != nil {
+		return fields.PodCluster{}, err
+	}
+
+	var annotations fields.Annotations
+	if proto.Annotations != "" {
+		err = json.Unmarshal([]byte(proto.Annotations), &annotations)
+		if err != nil {
+			return fields.PodCluster{}, err
+		}
+	}
+
+	return fields.PodCluster{
+		ID:                  fields.ID(proto.Id),
+		PodID:               types.PodID(proto.PodId),
+		AvailabilityZone:    fields.AvailabilityZone(proto.AvailabilityZone),
+		Name:                fields.ClusterName(proto.Name),
+		PodSelector:         podSelector,
+		Annotations:         annotations,
+		AllocationStrategy:  rc_fields.Strategy(proto.AllocationStrategy),
+		MinHealthPercentage: fields.MinHealthPercentage(proto.MinHealthPercentage),
+	}, nil
+}
+
+func LabeledPodsToProto(pods []labels.Labeled) []*pcstore_protos.LabeledPod {
+	out := make([]*pcstore_protos.LabeledPod, len(pods))
+	for i, pod := range pods {
+		out[i] = &pcstore_protos.LabeledPod{
+			Id:     pod.ID,
+			Labels: pod.Labels,
+		}
+	}
+	return out
+}
+
+func ProtoToLabeledPods(protos []*pcstore_protos.LabeledPod) []labels.Labeled {
+	out := make([]labels.Labeled, len(protos))
+	for i, proto := range protos {
+		out[i] = labels.Labeled{
+			LabelType: labels.POD,
+			ID:        proto.Id,
+			Labels:    klabels.Set(proto.Labels),
+		}
+	}
+	return out
+}
@@ -0,0 +1,316 @@
+// +build !race
+
+package pcstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	klabels "k8s.io/kubernetes/pkg/labels"
+
+	pcstore_protos "github.com/square/p2/pkg/grpc/pcstore/protos"
+	"github.com/square/p2/pkg/grpc/testutil"
+	"github.com/square/p2/pkg/labels"
+	"github.com/square/p2/pkg/logging"
+	"github.com/square/p2/pkg/pc/fields"
+	rc_fields "github.com/square/p2/pkg/rc/fields"
+	"github.com/square/p2/pkg/store/consul"
+	"github.com/square/p2/pkg/store/consul/consulutil"
+	"github.com/square/p2/pkg/store/consul/pcstore"
+)
+
+func newTestServer(fixture consulutil.Fixture) (Store, labels.Applicator) {
+	applicator := labels.NewFakeApplicator()
+	server := NewServer(
+		pcstore.NewConsul(fixture.Client, applicator, 0, applicator, &logging.DefaultLogger),
+		consul.NewConsulStore(fixture.Client),
+	)
+	return server, applicator
+}
+
+func createTestPodCluster(t *testing.T, server Store) *pcstore_protos.PodCluster {
+	resp, err := server.CreatePodCluster(context.Background(), &pcstore_protos.CreatePodClusterRequest{
+		PodId:               "slug",
+		AvailabilityZone:    "us-west",
+		ClusterName:         "production",
+		Annotations:         `{"owner":"payments"}`,
+		AllocationStrategy:  rc_fields.StaticStrategy.String(),
+		MinHealthPercentage: 80,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating pod cluster: %s", err)
+	}
+	return resp.PodCluster
+}
+
+func TestCreateGetListDeletePodCluster(t *testing.T) {
+	fixture := consulutil.NewFixture(t)
+	defer fixture.Stop()
+	server, _ := newTestServer(fixture)
+	ctx := context.Background()
+
+	created := createTestPodCluster(t, server)
+	if created.Id == "" {
+		t.Fatal("expected the created pod cluster to have an ID")
+	}
+	expectedSelector := defaultSelector("us-west", "production", "slug").String()
+	if created.PodSelector != expectedSelector {
+		t.Errorf("expected the default pod selector %q, got %q", expectedSelector, created.PodSelector)
+	}
+
+	_, err := server.CreatePodCluster(ctx, &pcstore_protos.CreatePodClusterRequest{
+		PodId:              "slug",
+		AvailabilityZone:   "us-west",
+		ClusterName:        "production",
+		AllocationStrategy: rc_fields.StaticStrategy.String(),
+	})
+	if grpc.Code(err) != codes.AlreadyExists {
+		t.Errorf("expected AlreadyExists creating a duplicate pod cluster, got %s", err)
+	}
+
+	getResp, err := server.GetPodCluster(ctx, &pcstore_protos.GetPodClusterRequest{PodClusterId: created.Id})
+	if err != nil {
+		t.Fatalf("unexpected error getting pod cluster: %s", err)
+	}
+	pc, err := ProtoToPodCluster(getResp.PodCluster)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pc.PodID != "slug" || pc.MinHealthPercentage != 80 || pc.AllocationStrategy != rc_fields.StaticStrategy {
+		t.Errorf("pod cluster did not survive the round trip: %+v", pc)
+	}
+	if pc.Annotations["owner"] != "payments" {
+		t.Errorf("expected an owner annotation of payments, got %v", pc.Annotations)
+	}
+
+	listResp, err := server.ListPodClusters(ctx, &pcstore_protos.ListPodClustersRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error listing pod clusters: %s", err)
+	}
+	if len(listResp.PodClusters) != 1 || listResp.PodClusters[0].Id != created.Id {
+		t.Errorf("expected the created pod cluster to be listed, got %+v", listResp.PodClusters)
+	}
+
+	_, err = server.DeletePodCluster(ctx, &pcstore_protos.DeletePodClusterRequest{PodClusterId: created.Id})
+	if err != nil {
+		t.Fatalf("unexpected error deleting pod cluster: %s", err)
+	}
+	_, err = server.GetPodCluster(ctx, &pcstore_protos.GetPodClusterRequest{PodClusterId: created.Id})
+	if grpc.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound getting a deleted pod cluster, got %s", err)
+	}
+	_, err = server.DeletePodCluster(ctx, &pcstore_protos.DeletePodClusterRequest{PodClusterId: created.Id})
+	if grpc.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound deleting a deleted pod cluster, got %s", err)
+	}
+}
+
+func TestCreatePodClusterInvalid(t *testing.T) {
+	fixture := consulutil.NewFixture(t)
+	defer fixture.Stop()
+	server, _ := newTestServer(fixture)
+
+	valid := pcstore_protos.CreatePodClusterRequest{
+		PodId:              "slug",
+		AvailabilityZone:   "us-west",
+		ClusterName:        "production",
+		AllocationStrategy: rc_fields.StaticStrategy.String(),
+	}
+	for name, mutate := range map[string]func(*pcstore_protos.CreatePodClusterRequest){
+		"no pod ID":           func(req *pcstore_protos.CreatePodClusterRequest) { req.PodId = "" },
+		"bad selector":        func(req *pcstore_protos.CreatePodClusterRequest) { req.PodSelector = "a in (" },
+		"bad annotations":     func(req *pcstore_protos.CreatePodClusterRequest) { req.Annotations = "[1, 2]" },
+		"unknown strategy":    func(req *pcstore_protos.CreatePodClusterRequest) { req.AllocationStrategy = "random" },
+		"min health too high": func(req *pcstore_protos.CreatePodClusterRequest) { req.MinHealthPercentage = 101 },
+	} {
+		req := valid
+		mutate(&req)
+		_, err := server.CreatePodCluster(context.Background(), &req)
+		if grpc.Code(err) != codes.InvalidArgument {
+			t.Errorf("%s: expected InvalidArgument, got %s", name, err)
+		}
+	}
+}
+
+func TestUpdatePodCluster(t *testing.T) {
+	fixture := consulutil.NewFixture(t)
+	defer fixture.Stop()
+	server, _ := newTestServer(fixture)
+	created := createTestPodCluster(t, server)
+	ctx := context.Background()
+
+	resp, err := server.UpdateAnnotations(ctx, &pcstore_protos.UpdateAnnotationsRequest{
+		PodClusterId: created.Id,
+		Annotations:  `{"owner":"billing"}`,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error updating annotations: %s", err)
+	}
+	if resp.PodCluster.Annotations != `{"owner":"billing"}` {
+		t.Errorf("expected the annotations to be replaced, got %s", resp.PodCluster.Annotations)
+	}
+
+	resp, err = server.UpdatePodSelector(ctx, &pcstore_protos.UpdatePodSelectorRequest{
+		PodClusterId: created.Id,
+		PodSelector:  "app=slug",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error updating pod selector: %s", err)
+	}
+	if resp.PodCluster.PodSelector != "app=slug" {
+		t.Errorf("expected a pod selector of app=slug, got %s", resp.PodCluster.PodSelector)
+	}
+
+	resp, err = server.UpdateAllocationStrategy(ctx, &pcstore_protos.UpdateAllocationStrategyRequest{
+		PodClusterId:       created.Id,
+		AllocationStrategy: rc_fields.DynamicStrategy.String(),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error updating allocation strategy: %s", err)
+	}
+	if resp.PodCluster.AllocationStrategy != rc_fields.DynamicStrategy.String() {
+		t.Errorf("expected a dynamic strategy, got %s", resp.PodCluster.AllocationStrategy)
+	}
+
+	getResp, err := server.GetPodCluster(ctx, &pcstore_protos.GetPodClusterRequest{PodClusterId: created.Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if getResp.PodCluster.PodSelector != "app=slug" || getResp.PodCluster.AllocationStrategy != rc_fields.DynamicStrategy.String() {
+		t.Errorf("expected the updates to be stored, got %+v", getResp.PodCluster)
+	}
+
+	_, err = server.UpdateAllocationStrategy(ctx, &pcstore_protos.UpdateAllocationStrategyRequest{
+		PodClusterId:       "nonexistent",
+		AllocationStrategy: rc_fields.DynamicStrategy.String(),
+	})
+	if grpc.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound updating a missing pod cluster, got %s", err)
+	}
+	_, err = server.UpdatePodSelector(ctx, &pcstore_protos.UpdatePodSelectorRequest{
+		PodClusterId: created.Id,
+		PodSelector:  "a in (",
+	})
+	if grpc.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument for a bad pod selector, got %s", err)
+	}
+}
+
+type fakeWatchAndSyncServer struct {
+	*testutil.FakeServerStream
+	events chan *pcstore_protos.SyncEvent
+}
+
+func (f fakeWatchAndSyncServer) Send(event *pcstore_protos.SyncEvent) error {
+	f.events <- event
+	return nil
+}
+
+func TestWatchAndSync(t *testing.T) {
+	fixture := consulutil.NewFixture(t)
+	defer fixture.Stop()
+	server, applicator := newTestServer(fixture)
+	created := createTestPodCluster(t, server)
+
+	podLabels := klabels.Set{
+		fields.PodIDLabel:            "slug",
+		fields.AvailabilityZoneLabel: "us-west",
+		fields.ClusterNameLabel:      "production",
+	}
+	err := applicator.SetLabels(labels.POD, "node1/slug", podLabels)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := fakeWatchAndSyncServer{
+		FakeServerStream: testutil.NewFakeServerStream(ctx),
+		events:           make(chan *pcstore_protos.SyncEvent),
+	}
+	watchErr := make(chan error)
+	go func() {
+		watchErr <- server.WatchAndSync(&pcstore_protos.WatchAndSyncRequest{}, stream)
+	}()
+
+	expectEvent := func(description string, matches func(*pcstore_protos.SyncEvent) bool) {
+		timeout := time.After(5 * time.Second)
+		for {
+			select {
+			case event := <-stream.events:
+				if matches(event) {
+					return
+				}
+			case err := <-watchErr:
+				t.Fatalf("watch ended early: %s", err)
+			case <-timeout:
+				t.Fatalf("timed out waiting for %s", description)
+			}
+		}
+	}
+	expectEvent("the pod cluster to be synced with its pod", func(event *pcstore_protos.SyncEvent) bool {
+		return event.PodCluster != nil &&
+			event.PodCluster.Id == created.Id &&
+			len(event.Pods) == 1 &&
+			event.Pods[0].Id == "node1/slug" &&
+			event.Pods[0].Labels[fields.ClusterNameLabel] == "production"
+	})
+
+	// Keep a second pod cluster around, since the store doesn't sync an
+	// empty watch result
+	_, err = server.CreatePodCluster(ctx, &pcstore_protos.CreatePodClusterRequest{
+		PodId:              "other",
+		AvailabilityZone:   "us-west",
+		ClusterName:        "production",
+		AllocationStrategy: rc_fields.StaticStrategy.String(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = server.DeletePodCluster(ctx, &pcstore_protos.DeletePodClusterRequest{PodClusterId: created.Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectEvent("the pod cluster to be deleted", func(event *pcstore_protos.SyncEvent) bool {
+		return event.DeletedPodClusterId == created.Id
+	})
+
+	cancel()
+	select {
+	case err := <-watchErr:
+		if err != nil {
+			t.Errorf("expected the watch to end cleanly when canceled, got %s", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("watch did not end when canceled")
+	}
+}
+
+func TestProtoPodClusterRoundTrip(t *testing.T) {
+	selector := klabels.Everything().Add("app", klabels.InOperator, []string{"a", "b"})
+	pc := fields.PodCluster{
+		ID:                  "abc",
+		PodID:               "slug",
+		AvailabilityZone:    "us-west",
+		Name:                "production",
+		PodSelector:         selector,
+		Annotations:         fields.Annotations{"owner": "payments"},
+		AllocationStrategy:  rc_fields.DynamicStrategy,
+		MinHealthPercentage: 50,
+	}
+
+	pcProto, err := PodClusterToProto(pc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ProtoToPodCluster(pcProto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pc.Equals(&got) || got.MinHealthPercentage != 50 {
+		t.Errorf("pod cluster did not survive the round trip: expected %+v, got %+v", pc, got)
+	}
+}
@@ -0,0 +1,1340 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: pcstore.proto
+
+package pcstore
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+// models pc/fields.PodCluster
+type PodCluster struct {
+	Id               string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	PodId            string `protobuf:"bytes,2,opt,name=pod_id,json=podId,proto3" json:"pod_id,omitempty"`
+	AvailabilityZone string `protobuf:"bytes,3,opt,name=availability_zone,json=availabilityZone,proto3" json:"availability_zone,omitempty"`
+	Name             string `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	PodSelector      string `protobuf:"bytes,5,opt,name=pod_selector,json=podSelector,proto3" json:"pod_selector,omitempty"`
+	// JSON object
+	Annotations          string   `protobuf:"bytes,6,opt,name=annotations,proto3" json:"annotations,omitempty"`
+	AllocationStrategy   string   `protobuf:"bytes,7,opt,name=allocation_strategy,json=allocationStrategy,proto3" json:"allocation_strategy,omitempty"`
+	MinHealthPercentage  int64    `protobuf:"varint,8,opt,name=min_health_percentage,json=minHealthPercentage,proto3" json:"min_health_percentage,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PodCluster) Reset()         { *m = PodCluster{} }
+func (m *PodCluster) String() string { return proto.CompactTextString(m) }
+func (*PodCluster) ProtoMessage()    {}
+func (*PodCluster) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pcstore_677b56cb25aa5108, []int{0}
+}
+func (m *PodCluster) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PodCluster.Unmarshal(m, b)
+}
+func (m *PodCluster) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PodCluster.Marshal(b, m, deterministic)
+}
+func (dst *PodCluster) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PodCluster.Merge(dst, src)
+}
+func (m *PodCluster) XXX_Size() int {
+	return xxx_messageInfo_PodCluster.Size(m)
+}
+func (m *PodCluster) XXX_DiscardUnknown() {
+	xxx_messageInfo_PodCluster.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PodCluster proto.InternalMessageInfo
+
+func (m *PodCluster) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *PodCluster) GetPodId() string {
+	if m != nil {
+		return m.PodId
+	}
+	return ""
+}
+
+func (m *PodCluster) GetAvailabilityZone() string {
+	if m != nil {
+		return m.AvailabilityZone
+	}
+	return ""
+}
+
+func (m *PodCluster) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *PodCluster) GetPodSelector() string {
+	if m != nil {
+		return m.PodSelector
+	}
+	return ""
+}
+
+func (m *PodCluster) GetAnnotations() string {
+	if m != nil {
+		return m.Annotations
+	}
+	return ""
+}
+
+func (m *PodCluster) GetAllocationStrategy() string {
+	if m != nil {
+		return m.AllocationStrategy
+	}
+	return ""
+}
+
+func (m *PodCluster) GetMinHealthPercentage() int64 {
+	if m != nil {
+		return m.MinHealthPercentage
+	}
+	return 0
+}
+
+// models labels.Labeled for a pod, whose ID is <node>/<pod id>
+type LabeledPod struct {
+	Id                   string            `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Labels               map[string]string `protobuf:"bytes,2,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *LabeledPod) Reset()         { *m = LabeledPod{} }
+func (m *LabeledPod) String() string { return proto.CompactTextString(m) }
+func (*LabeledPod) ProtoMessage()    {}
+func (*LabeledPod) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pcstore_677b56cb25aa5108, []int{1}
+}
+func (m *LabeledPod) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LabeledPod.Unmarshal(m, b)
+}
+func (m *LabeledPod) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LabeledPod.Marshal(b, m, deterministic)
+}
+func (dst *LabeledPod) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LabeledPod.Merge(dst, src)
+}
+func (m *LabeledPod) XXX_Size() int {
+	return xxx_messageInfo_LabeledPod.Size(m)
+}
+func (m *LabeledPod) XXX_DiscardUnknown() {
+	xxx_messageInfo_LabeledPod.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LabeledPod proto.InternalMessageInfo
+
+func (m *LabeledPod) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *LabeledPod) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
+type CreatePodClusterRequest struct {
+	PodId            string `protobuf:"bytes,1,opt,name=pod_id,json=podId,proto3" json:"pod_id,omitempty"`
+	AvailabilityZone string `protobuf:"bytes,2,opt,name=availability_zone,json=availabilityZone,proto3" json:"availability_zone,omitempty"`
+	ClusterName      string `protobuf:"bytes,3,opt,name=cluster_name,json=clusterName,proto3" json:"cluster_name,omitempty"`
+	// defaults to selecting the pods labeled with the pod ID, availability zone
+	// and cluster name
+	PodSelector string `protobuf:"bytes,4,opt,name=pod_selector,json=podSelector,proto3" json:"pod_selector,omitempty"`
+	// JSON object
+	Annotations          string   `protobuf:"bytes,5,opt,name=annotations,proto3" json:"annotations,omitempty"`
+	AllocationStrategy   string   `protobuf:"bytes,6,opt,name=allocation_strategy,json=allocationStrategy,proto3" json:"allocation_strategy,omitempty"`
+	MinHealthPercentage  int64    `protobuf:"varint,7,opt,name=min_health_percentage,json=minHealthPercentage,proto3" json:"min_health_percentage,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreatePodClusterRequest) Reset()         { *m = CreatePodClusterRequest{} }
+func (m *CreatePodClusterRequest) String() string { return proto.CompactTextString(m) }
+func (*CreatePodClusterRequest) ProtoMessage()    {}
+func (*CreatePodClusterRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pcstore_677b56cb25aa5108, []int{2}
+}
+func (m *CreatePodClusterRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreatePodClusterRequest.Unmarshal(m, b)
+}
+func (m *CreatePodClusterRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreatePodClusterRequest.Marshal(b, m, deterministic)
+}
+func (dst *CreatePodClusterRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreatePodClusterRequest.Merge(dst, src)
+}
+func (m *CreatePodClusterRequest) XXX_Size() int {
+	return xxx_messageInfo_CreatePodClusterRequest.Size(m)
+}
+func (m *CreatePodClusterRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreatePodClusterRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreatePodClusterRequest proto.InternalMessageInfo
+
+func (m *CreatePodClusterRequest) GetPodId() string {
+	if m != nil {
+		return m.PodId
+	}
+	return ""
+}
+
+func (m *CreatePodClusterRequest) GetAvailabilityZone() string {
+	if m != nil {
+		return m.AvailabilityZone
+	}
+	return ""
+}
+
+func (m *CreatePodClusterRequest) GetClusterName() string {
+	if m != nil {
+		return m.ClusterName
+	}
+	return ""
+}
+
+func (m *CreatePodClusterRequest) GetPodSelector() string {
+	if m != nil {
+		return m.PodSelector
+	}
+	return ""
+}
+
+func (m *CreatePodClusterRequest) GetAnnotations() string {
+	if m != nil {
+		return m.Annotations
+	}
+	return ""
+}
+
+func (m *CreatePodClusterRequest) GetAllocationStrategy() string {
+	if m != nil {
+		return m.AllocationStrategy
+	}
+	return ""
+}
+
+func (m *CreatePodClusterRequest) GetMinHealthPercentage() int64 {
+	if m != nil {
+		return m.MinHealthPercentage
+	}
+	return 0
+}
+
+type CreatePodClusterResponse struct {
+	PodCluster           *PodCluster `protobuf:"bytes,1,opt,name=pod_cluster,json=podCluster,proto3" json:"pod_cluster,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *CreatePodClusterResponse) Reset()         { *m = CreatePodClusterResponse{} }
+func (m *CreatePodClusterResponse) String() string { return proto.CompactTextString(m) }
+func (*CreatePodClusterResponse) ProtoMessage()    {}
+func (*CreatePodClusterResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pcstore_677b56cb25aa5108, []int{3}
+}
+func (m *CreatePodClusterResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreatePodClusterResponse.Unmarshal(m, b)
+}
+func (m *CreatePodClusterResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreatePodClusterResponse.Marshal(b, m, deterministic)
+}
+func (dst *CreatePodClusterResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreatePodClusterResponse.Merge(dst, src)
+}
+func (m *CreatePodClusterResponse) XXX_Size() int {
+	return xxx_messageInfo_CreatePodClusterResponse.Size(m)
+}
+func (m *CreatePodClusterResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreatePodClusterResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreatePodClusterResponse proto.InternalMessageInfo
+
+func (m *CreatePodClusterResponse) GetPodCluster() *PodCluster {
+	if m != nil {
+		return m.PodCluster
+	}
+	return nil
+}
+
+type GetPodClusterRequest struct {
+	PodClusterId         string   `protobuf:"bytes,1,opt,name=pod_cluster_id,json=podClusterId,proto3" json:"pod_cluster_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetPodClusterRequest) Reset()         { *m = GetPodClusterRequest{} }
+func (m *GetPodClusterRequest) String() string { return proto.CompactTextString(m) }
+func (*GetPodClusterRequest) ProtoMessage()    {}
+func (*GetPodClusterRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pcstore_677b56cb25aa5108, []int{4}
+}
+func (m *GetPodClusterRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetPodClusterRequest.Unmarshal(m, b)
+}
+func (m *GetPodClusterRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetPodClusterRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetPodClusterRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetPodClusterRequest.Merge(dst, src)
+}
+func (m *GetPodClusterRequest) XXX_Size() int {
+	return xxx_messageInfo_GetPodClusterRequest.Size(m)
+}
+func (m *GetPodClusterRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetPodClusterRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetPodClusterRequest proto.InternalMessageInfo
+
+func (m *GetPodClusterRequest) GetPodClusterId() string {
+	if m != nil {
+		return m.PodClusterId
+	}
+	return ""
+}
+
+type GetPodClusterResponse struct {
+	PodCluster           *PodCluster `protobuf:"bytes,1,opt,name=pod_cluster,json=podCluster,proto3" json:"pod_cluster,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *GetPodClusterResponse) Reset()         { *m = GetPodClusterResponse{} }
+func (m *GetPodClusterResponse) String() string { return proto.CompactTextString(m) }
+func (*GetPodClusterResponse) ProtoMessage()    {}
+func (*GetPodClusterResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pcstore_677b56cb25aa5108, []int{5}
+}
+func (m *GetPodClusterResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetPodClusterResponse.Unmarshal(m, b)
+}
+func (m *GetPodClusterResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetPodClusterResponse.Marshal(b, m, deterministic)
+}
+func (dst *GetPodClusterResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetPodClusterResponse.Merge(dst, src)
+}
+func (m *GetPodClusterResponse) XXX_Size() int {
+	return xxx_messageInfo_GetPodClusterResponse.Size(m)
+}
+func (m *GetPodClusterResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetPodClusterResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetPodClusterResponse proto.InternalMessageInfo
+
+func (m *GetPodClusterResponse) GetPodCluster() *PodCluster {
+	if m != nil {
+		return m.PodCluster
+	}
+	return nil
+}
+
+type ListPodClustersRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListPodClustersRequest) Reset()         { *m = ListPodClustersRequest{} }
+func (m *ListPodClustersRequest) String() string { return proto.CompactTextString(m) }
+func (*ListPodClustersRequest) ProtoMessage()    {}
+func (*ListPodClustersRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pcstore_677b56cb25aa5108, []int{6}
+}
+func (m *ListPodClustersRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListPodClustersRequest.Unmarshal(m, b)
+}
+func (m *ListPodClustersRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListPodClustersRequest.Marshal(b, m, deterministic)
+}
+func (dst *ListPodClustersRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListPodClustersRequest.Merge(dst, src)
+}
+func (m *ListPodClustersRequest) XXX_Size() int {
+	return xxx_messageInfo_ListPodClustersRequest.Size(m)
+}
+func (m *ListPodClustersRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListPodClustersRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListPodClustersRequest proto.InternalMessageInfo
+
+type ListPodClustersResponse struct {
+	PodClusters          []*PodCluster `protobuf:"bytes,1,rep,name=pod_clusters,json=podClusters,proto3" json:"pod_clusters,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *ListPodClustersResponse) Reset()         { *m = ListPodClustersResponse{} }
+func (m *ListPodClustersResponse) String() string { return proto.CompactTextString(m) }
+func (*ListPodClustersResponse) ProtoMessage()    {}
+func (*ListPodClustersResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pcstore_677b56cb25aa5108, []int{7}
+}
+func (m *ListPodClustersResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListPodClustersResponse.Unmarshal(m, b)
+}
+func (m *ListPodClustersResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListPodClustersResponse.Marshal(b, m, deterministic)
+}
+func (dst *ListPodClustersResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListPodClustersResponse.Merge(dst, src)
+}
+func (m *ListPodClustersResponse) XXX_Size() int {
+	return xxx_messageInfo_ListPodClustersResponse.Size(m)
+}
+func (m *ListPodClustersResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListPodClustersResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListPodClustersResponse proto.InternalMessageInfo
+
+func (m *ListPodClustersResponse) GetPodClusters() []*PodCluster {
+	if m != nil {
+		return m.PodClusters
+	}
+	return nil
+}
+
+type DeletePodClusterRequest struct {
+	PodClusterId         string   `protobuf:"bytes,1,opt,name=pod_cluster_id,json=podClusterId,proto3" json:"pod_cluster_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeletePodClusterRequest) Reset()         { *m = DeletePodClusterRequest{} }
+func (m *DeletePodClusterRequest) String() string { return proto.CompactTextString(m) }
+func (*DeletePodClusterRequest) ProtoMessage()    {}
+func (*DeletePodClusterRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pcstore_677b56cb25aa5108, []int{8}
+}
+func (m *DeletePodClusterRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeletePodClusterRequest.Unmarshal(m, b)
+}
+func (m *DeletePodClusterRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeletePodClusterRequest.Marshal(b, m, deterministic)
+}
+func (dst *DeletePodClusterRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeletePodClusterRequest.Merge(dst, src)
+}
+func (m *DeletePodClusterRequest) XXX_Size() int {
+	return xxx_messageInfo_DeletePodClusterRequest.Size(m)
+}
+func (m *DeletePodClusterRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeletePodClusterRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeletePodClusterRequest proto.InternalMessageInfo
+
+func (m *DeletePodClusterRequest) GetPodClusterId() string {
+	if m != nil {
+		return m.PodClusterId
+	}
+	return ""
+}
+
+type DeletePodClusterResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeletePodClusterResponse) Reset()         { *m = DeletePodClusterResponse{} }
+func (m *DeletePodClusterResponse) String() string { return proto.CompactTextString(m) }
+func (*DeletePodClusterResponse) ProtoMessage()    {}
+func (*DeletePodClusterResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pcstore_677b56cb25aa5108, []int{9}
+}
+func (m *DeletePodClusterResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeletePodClusterResponse.Unmarshal(m, b)
+}
+func (m *DeletePodClusterResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeletePodClusterResponse.Marshal(b, m, deterministic)
+}
+func (dst *DeletePodClusterResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeletePodClusterResponse.Merge(dst, src)
+}
+func (m *DeletePodClusterResponse) XXX_Size() int {
+	return xxx_messageInfo_DeletePodClusterResponse.Size(m)
+}
+func (m *DeletePodClusterResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeletePodClusterResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeletePodClusterResponse proto.InternalMessageInfo
+
+type WatchPodClustersRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchPodClustersRequest) Reset()         { *m = WatchPodClustersRequest{} }
+func (m *WatchPodClustersRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchPodClustersRequest) ProtoMessage()    {}
+func (*WatchPodClustersRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pcstore_677b56cb25aa5108, []int{10}
+}
+func (m *WatchPodClustersRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchPodClustersRequest.Unmarshal(m, b)
+}
+func (m *WatchPodClustersRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchPodClustersRequest.Marshal(b, m, deterministic)
+}
+func (dst *WatchPodClustersRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchPodClustersRequest.Merge(dst, src)
+}
+func (m *WatchPodClustersRequest) XXX_Size() int {
+	return xxx_messageInfo_WatchPodClustersRequest.Size(m)
+}
+func (m *WatchPodClustersRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchPodClustersRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchPodClustersRequest proto.InternalMessageInfo
+
+type WatchPodClustersResponse struct {
+	PodClusters []*PodCluster `protobuf:"bytes,1,rep,name=pod_clusters,json=podClusters,proto3" json:"pod_clusters,omitempty"`
+	// set if the pod clusters couldn't be read, in which case pod_clusters is
+	// empty and the watch carries on
+	Error                string   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchPodClustersResponse) Reset()         { *m = WatchPodClustersResponse{} }
+func (m *WatchPodClustersResponse) String() string { return proto.CompactTextString(m) }
+func (*WatchPodClustersResponse) ProtoMessage()    {}
+func (*WatchPodClustersResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pcstore_677b56cb25aa5108, []int{11}
+}
+func (m *WatchPodClustersResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchPodClustersResponse.Unmarshal(m, b)
+}
+func (m *WatchPodClustersResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchPodClustersResponse.Marshal(b, m, deterministic)
+}
+func (dst *WatchPodClustersResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchPodClustersResponse.Merge(dst, src)
+}
+func (m *WatchPodClustersResponse) XXX_Size() int {
+	return xxx_messageInfo_WatchPodClustersResponse.Size(m)
+}
+func (m *WatchPodClustersResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchPodClustersResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchPodClustersResponse proto.InternalMessageInfo
+
+func (m *WatchPodClustersResponse) GetPodClusters() []*PodCluster {
+	if m != nil {
+		return m.PodClusters
+	}
+	return nil
+}
+
+func (m *WatchPodClustersResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type UpdateAnnotationsRequest struct {
+	PodClusterId string `protobuf:"bytes,1,opt,name=pod_cluster_id,json=podClusterId,proto3" json:"pod_cluster_id,omitempty"`
+	// JSON object, replaces the existing annotations
+	Annotations          string   `protobuf:"bytes,2,opt,name=annotations,proto3" json:"annotations,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpdateAnnotationsRequest) Reset()         { *m = UpdateAnnotationsRequest{} }
+func (m *UpdateAnnotationsRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateAnnotationsRequest) ProtoMessage()    {}
+func (*UpdateAnnotationsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pcstore_677b56cb25aa5108, []int{12}
+}
+func (m *UpdateAnnotationsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateAnnotationsRequest.Unmarshal(m, b)
+}
+func (m *UpdateAnnotationsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateAnnotationsRequest.Marshal(b, m, deterministic)
+}
+func (dst *UpdateAnnotationsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateAnnotationsRequest.Merge(dst, src)
+}
+func (m *UpdateAnnotationsRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateAnnotationsRequest.Size(m)
+}
+func (m *UpdateAnnotationsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateAnnotationsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateAnnotationsRequest proto.InternalMessageInfo
+
+func (m *UpdateAnnotationsRequest) GetPodClusterId() string {
+	if m != nil {
+		return m.PodClusterId
+	}
+	return ""
+}
+
+func (m *UpdateAnnotationsRequest) GetAnnotations() string {
+	if m != nil {
+		return m.Annotations
+	}
+	return ""
+}
+
+type UpdatePodSelectorRequest struct {
+	PodClusterId         string   `protobuf:"bytes,1,opt,name=pod_cluster_id,json=podClusterId,proto3" json:"pod_cluster_id,omitempty"`
+	PodSelector          string   `protobuf:"bytes,2,opt,name=pod_selector,json=podSelector,proto3" json:"pod_selector,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpdatePodSelectorRequest) Reset()         { *m = UpdatePodSelectorRequest{} }
+func (m *UpdatePodSelectorRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdatePodSelectorRequest) ProtoMessage()    {}
+func (*UpdatePodSelectorRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pcstore_677b56cb25aa5108, []int{13}
+}
+func (m *UpdatePodSelectorRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdatePodSelectorRequest.Unmarshal(m, b)
+}
+func (m *UpdatePodSelectorRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdatePodSelectorRequest.Marshal(b, m, deterministic)
+}
+func (dst *UpdatePodSelectorRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdatePodSelectorRequest.Merge(dst, src)
+}
+func (m *UpdatePodSelectorRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdatePodSelectorRequest.Size(m)
+}
+func (m *UpdatePodSelectorRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdatePodSelectorRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdatePodSelectorRequest proto.InternalMessageInfo
+
+func (m *UpdatePodSelectorRequest) GetPodClusterId() string {
+	if m != nil {
+		return m.PodClusterId
+	}
+	return ""
+}
+
+func (m *UpdatePodSelectorRequest) GetPodSelector() string {
+	if m != nil {
+		return m.PodSelector
+	}
+	return ""
+}
+
+type UpdateAllocationStrategyRequest struct {
+	PodClusterId         string   `protobuf:"bytes,1,opt,name=pod_cluster_id,json=podClusterId,proto3" json:"pod_cluster_id,omitempty"`
+	AllocationStrategy   string   `protobuf:"bytes,2,opt,name=allocation_strategy,json=allocationStrategy,proto3" json:"allocation_strategy,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpdateAllocationStrategyRequest) Reset()         { *m = UpdateAllocationStrategyRequest{} }
+func (m *UpdateAllocationStrategyRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateAllocationStrategyRequest) ProtoMessage()    {}
+func (*UpdateAllocationStrategyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pcstore_677b56cb25aa5108, []int{14}
+}
+func (m *UpdateAllocationStrategyRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateAllocationStrategyRequest.Unmarshal(m, b)
+}
+func (m *UpdateAllocationStrategyRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateAllocationStrategyRequest.Marshal(b, m, deterministic)
+}
+func (dst *UpdateAllocationStrategyRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateAllocationStrategyRequest.Merge(dst, src)
+}
+func (m *UpdateAllocationStrategyRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateAllocationStrategyRequest.Size(m)
+}
+func (m *UpdateAllocationStrategyRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateAllocationStrategyRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateAllocationStrategyRequest proto.InternalMessageInfo
+
+func (m *UpdateAllocationStrategyRequest) GetPodClusterId() string {
+	if m != nil {
+		return m.PodClusterId
+	}
+	return ""
+}
+
+func (m *UpdateAllocationStrategyRequest) GetAllocationStrategy() string {
+	if m != nil {
+		return m.AllocationStrategy
+	}
+	return ""
+}
+
+type UpdatePodClusterResponse struct {
+	PodCluster           *PodCluster `protobuf:"bytes,1,opt,name=pod_cluster,json=podCluster,proto3" json:"pod_cluster,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *UpdatePodClusterResponse) Reset()         { *m = UpdatePodClusterResponse{} }
+func (m *UpdatePodClusterResponse) String() string { return proto.CompactTextString(m) }
+func (*UpdatePodClusterResponse) ProtoMessage()    {}
+func (*UpdatePodClusterResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pcstore_677b56cb25aa5108, []int{15}
+}
+func (m *UpdatePodClusterResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdatePodClusterResponse.Unmarshal(m, b)
+}
+func (m *UpdatePodClusterResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdatePodClusterResponse.Marshal(b, m, deterministic)
+}
+func (dst *UpdatePodClusterResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdatePodClusterResponse.Merge(dst, src)
+}
+func (m *UpdatePodClusterResponse) XXX_Size() int {
+	return xxx_messageInfo_UpdatePodClusterResponse.Size(m)
+}
+func (m *UpdatePodClusterResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdatePodClusterResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdatePodClusterResponse proto.InternalMessageInfo
+
+func (m *UpdatePodClusterResponse) GetPodCluster() *PodCluster {
+	if m != nil {
+		return m.PodCluster
+	}
+	return nil
+}
+
+type WatchAndSyncRequest struct {
+	// the pod clusters the caller last synced, as returned by
+	// ConcreteSyncer.GetInitialClusters. Any that no longer exist are sent
+	// as deleted
+	InitialPodClusterIds []string `protobuf:"bytes,1,rep,name=initial_pod_cluster_ids,json=initialPodClusterIds,proto3" json:"initial_pod_cluster_ids,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchAndSyncRequest) Reset()         { *m = WatchAndSyncRequest{} }
+func (m *WatchAndSyncRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchAndSyncRequest) ProtoMessage()    {}
+func (*WatchAndSyncRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pcstore_677b56cb25aa5108, []int{16}
+}
+func (m *WatchAndSyncRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchAndSyncRequest.Unmarshal(m, b)
+}
+func (m *WatchAndSyncRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchAndSyncRequest.Marshal(b, m, deterministic)
+}
+func (dst *WatchAndSyncRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchAndSyncRequest.Merge(dst, src)
+}
+func (m *WatchAndSyncRequest) XXX_Size() int {
+	return xxx_messageInfo_WatchAndSyncRequest.Size(m)
+}
+func (m *WatchAndSyncRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchAndSyncRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchAndSyncRequest proto.InternalMessageInfo
+
+func (m *WatchAndSyncRequest) GetInitialPodClusterIds() []string {
+	if m != nil {
+		return m.InitialPodClusterIds
+	}
+	return nil
+}
+
+type SyncEvent struct {
+	// set when the pod cluster should be synced
+	PodCluster *PodCluster   `protobuf:"bytes,1,opt,name=pod_cluster,json=podCluster,proto3" json:"pod_cluster,omitempty"`
+	Pods       []*LabeledPod `protobuf:"bytes,2,rep,name=pods,proto3" json:"pods,omitempty"`
+	// set instead when the pod cluster was deleted
+	DeletedPodClusterId  string   `protobuf:"bytes,3,opt,name=deleted_pod_cluster_id,json=deletedPodClusterId,proto3" json:"deleted_pod_cluster_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SyncEvent) Reset()         { *m = SyncEvent{} }
+func (m *SyncEvent) String() string { return proto.CompactTextString(m) }
+func (*SyncEvent) ProtoMessage()    {}
+func (*SyncEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pcstore_677b56cb25aa5108, []int{17}
+}
+func (m *SyncEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SyncEvent.Unmarshal(m, b)
+}
+func (m *SyncEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SyncEvent.Marshal(b, m, deterministic)
+}
+func (dst *SyncEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SyncEvent.Merge(dst, src)
+}
+func (m *SyncEvent) XXX_Size() int {
+	return xxx_messageInfo_SyncEvent.Size(m)
+}
+func (m *SyncEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_SyncEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SyncEvent proto.InternalMessageInfo
+
+func (m *SyncEvent) GetPodCluster() *PodCluster {
+	if m != nil {
+		return m.PodCluster
+	}
+	return nil
+}
+
+func (m *SyncEvent) GetPods() []*LabeledPod {
+	if m != nil {
+		return m.Pods
+	}
+	return nil
+}
+
+func (m *SyncEvent) GetDeletedPodClusterId() string {
+	if m != nil {
+		return m.DeletedPodClusterId
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*PodCluster)(nil), "pcstore.PodCluster")
+	proto.RegisterType((*LabeledPod)(nil), "pcstore.LabeledPod")
+	proto.RegisterMapType((map[string]string)(nil), "pcstore.LabeledPod.LabelsEntry")
+	proto.RegisterType((*CreatePodClusterRequest)(nil), "pcstore.CreatePodClusterRequest")
+	proto.RegisterType((*CreatePodClusterResponse)(nil), "pcstore.CreatePodClusterResponse")
+	proto.RegisterType((*GetPodClusterRequest)(nil), "pcstore.GetPodClusterRequest")
+	proto.RegisterType((*GetPodClusterResponse)(nil), "pcstore.GetPodClusterResponse")
+	proto.RegisterType((*ListPodClustersRequest)(nil), "pcstore.ListPodClustersRequest")
+	proto.RegisterType((*ListPodClustersResponse)(nil), "pcstore.ListPodClustersResponse")
+	proto.RegisterType((*DeletePodClusterRequest)(nil), "pcstore.DeletePodClusterRequest")
+	proto.RegisterType((*DeletePodClusterResponse)(nil), "pcstore.DeletePodClusterResponse")
+	proto.RegisterType((*WatchPodClustersRequest)(nil), "pcstore.WatchPodClustersRequest")
+	proto.RegisterType((*WatchPodClustersResponse)(nil), "pcstore.WatchPodClustersResponse")
+	proto.RegisterType((*UpdateAnnotationsRequest)(nil), "pcstore.UpdateAnnotationsRequest")
+	proto.RegisterType((*UpdatePodSelectorRequest)(nil), "pcstore.UpdatePodSelectorRequest")
+	proto.RegisterType((*UpdateAllocationStrategyRequest)(nil), "pcstore.UpdateAllocationStrategyRequest")
+	proto.RegisterType((*UpdatePodClusterResponse)(nil), "pcstore.UpdatePodClusterResponse")
+	proto.RegisterType((*WatchAndSyncRequest)(nil), "pcstore.WatchAndSyncRequest")
+	proto.RegisterType((*SyncEvent)(nil), "pcstore.SyncEvent")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// P2PodClusterStoreClient is the client API for P2PodClusterStore service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type P2PodClusterStoreClient interface {
+	CreatePodCluster(ctx context.Context, in *CreatePodClusterRequest, opts ...grpc.CallOption) (*CreatePodClusterResponse, error)
+	GetPodCluster(ctx context.Context, in *GetPodClusterRequest, opts ...grpc.CallOption) (*GetPodClusterResponse, error)
+	ListPodClusters(ctx context.Context, in *ListPodClustersRequest, opts ...grpc.CallOption) (*ListPodClustersResponse, error)
+	DeletePodCluster(ctx context.Context, in *DeletePodClusterRequest, opts ...grpc.CallOption) (*DeletePodClusterResponse, error)
+	// Sends every pod cluster when the call is made and again each time any
+	// of them changes
+	WatchPodClusters(ctx context.Context, in *WatchPodClustersRequest, opts ...grpc.CallOption) (P2PodClusterStore_WatchPodClustersClient, error)
+	UpdateAnnotations(ctx context.Context, in *UpdateAnnotationsRequest, opts ...grpc.CallOption) (*UpdatePodClusterResponse, error)
+	UpdatePodSelector(ctx context.Context, in *UpdatePodSelectorRequest, opts ...grpc.CallOption) (*UpdatePodClusterResponse, error)
+	UpdateAllocationStrategy(ctx context.Context, in *UpdateAllocationStrategyRequest, opts ...grpc.CallOption) (*UpdatePodClusterResponse, error)
+	// Sends an event each time a pod cluster or the set of pods it selects
+	// changes, and each time a pod cluster is deleted. This mirrors the calls
+	// pcstore.WatchAndSync makes to a ConcreteSyncer, so a syncer can run on
+	// the other end of the stream
+	WatchAndSync(ctx context.Context, in *WatchAndSyncRequest, opts ...grpc.CallOption) (P2PodClusterStore_WatchAndSyncClient, error)
+}
+
+type p2PodClusterStoreClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewP2PodClusterStoreClient(cc *grpc.ClientConn) P2PodClusterStoreClient {
+	return &p2PodClusterStoreClient{cc}
+}
+
+func (c *p2PodClusterStoreClient) CreatePodCluster(ctx context.Context, in *CreatePodClusterRequest, opts ...grpc.CallOption) (*CreatePodClusterResponse, error) {
+	out := new(CreatePodClusterResponse)
+	err := c.cc.Invoke(ctx, "/pcstore.P2PodClusterStore/CreatePodCluster", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *p2PodClusterStoreClient) GetPodCluster(ctx context.Context, in *GetPodClusterRequest, opts ...grpc.CallOption) (*GetPodClusterResponse, error) {
+	out := new(GetPodClusterResponse)
+	err := c.cc.Invoke(ctx, "/pcstore.P2PodClusterStore/GetPodCluster", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *p2PodClusterStoreClient) ListPodClusters(ctx context.Context, in *ListPodClustersRequest, opts ...grpc.CallOption) (*ListPodClustersResponse, error) {
+	out := new(ListPodClustersResponse)
+	err := c.cc.Invoke(ctx, "/pcstore.P2PodClusterStore/ListPodClusters", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *p2PodClusterStoreClient) DeletePodCluster(ctx context.Context, in *DeletePodClusterRequest, opts ...grpc.CallOption) (*DeletePodClusterResponse, error) {
+	out := new(DeletePodClusterResponse)
+	err := c.cc.Invoke(ctx, "/pcstore.P2PodClusterStore/DeletePodCluster", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *p2PodClusterStoreClient) WatchPodClusters(ctx context.Context, in *WatchPodClustersRequest, opts ...grpc.CallOption) (P2PodClusterStore_WatchPodClustersClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_P2PodClusterStore_serviceDesc.Streams[0], "/pcstore.P2PodClusterStore/WatchPodClusters", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &p2PodClusterStoreWatchPodClustersClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type P2PodClusterStore_WatchPodClustersClient interface {
+	Recv() (*WatchPodClustersResponse, error)
+	grpc.ClientStream
+}
+
+type p2PodClusterStoreWatchPodClustersClient struct {
+	grpc.ClientStream
+}
+
+func (x *p2PodClusterStoreWatchPodClustersClient) Recv() (*WatchPodClustersResponse, error) {
+	m := new(WatchPodClustersResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *p2PodClusterStoreClient) UpdateAnnotations(ctx context.Context, in *UpdateAnnotationsRequest, opts ...grpc.CallOption) (*UpdatePodClusterResponse, error) {
+	out := new(UpdatePodClusterResponse)
+	err := c.cc.Invoke(ctx, "/pcstore.P2PodClusterStore/UpdateAnnotations", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *p2PodClusterStoreClient) UpdatePodSelector(ctx context.Context, in *UpdatePodSelectorRequest, opts ...grpc.CallOption) (*UpdatePodClusterResponse, error) {
+	out := new(UpdatePodClusterResponse)
+	err := c.cc.Invoke(ctx, "/pcstore.P2PodClusterStore/UpdatePodSelector", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *p2PodClusterStoreClient) UpdateAllocationStrategy(ctx context.Context, in *UpdateAllocationStrategyRequest, opts ...grpc.CallOption) (*UpdatePodClusterResponse, error) {
+	out := new(UpdatePodClusterResponse)
+	err := c.cc.Invoke(ctx, "/pcstore.P2PodClusterStore/UpdateAllocationStrategy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *p2PodClusterStoreClient) WatchAndSync(ctx context.Context, in *WatchAndSyncRequest, opts ...grpc.CallOption) (P2PodClusterStore_WatchAndSyncClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_P2PodClusterStore_serviceDesc.Streams[1], "/pcstore.P2PodClusterStore/WatchAndSync", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &p2PodClusterStoreWatchAndSyncClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type P2PodClusterStore_WatchAndSyncClient interface {
+	Recv() (*SyncEvent, error)
+	grpc.ClientStream
+}
+
+type p2PodClusterStoreWatchAndSyncClient struct {
+	grpc.ClientStream
+}
+
+func (x *p2PodClusterStoreWatchAndSyncClient) Recv() (*SyncEvent, error) {
+	m := new(SyncEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// P2PodClusterStoreServer is the server API for P2PodClusterStore service.
+type P2PodClusterStoreServer interface {
+	CreatePodCluster(context.Context, *CreatePodClusterRequest) (*CreatePodClusterResponse, error)
+	GetPodCluster(context.Context, *GetPodClusterRequest) (*GetPodClusterResponse, error)
+	ListPodClusters(context.Context, *ListPodClustersRequest) (*ListPodClustersResponse, error)
+	DeletePodCluster(context.Context, *DeletePodClusterRequest) (*DeletePodClusterResponse, error)
+	// Sends every pod cluster when the call is made and again each time any
+	// of them changes
+	WatchPodClusters(*WatchPodClustersRequest, P2PodClusterStore_WatchPodClustersServer) error
+	UpdateAnnotations(context.Context, *UpdateAnnotationsRequest) (*UpdatePodClusterResponse, error)
+	UpdatePodSelector(context.Context, *UpdatePodSelectorRequest) (*UpdatePodClusterResponse, error)
+	UpdateAllocationStrategy(context.Context, *UpdateAllocationStrategyRequest) (*UpdatePodClusterResponse, error)
+	// Sends an event each time a pod cluster or the set of pods it selects
+	// changes, and each time a pod cluster is deleted. This mirrors the calls
+	// pcstore.WatchAndSync makes to a ConcreteSyncer, so a syncer can run on
+	// the other end of the stream
+	WatchAndSync(*WatchAndSyncRequest, P2PodClusterStore_WatchAndSyncServer) error
+}
+
+func RegisterP2PodClusterStoreServer(s *grpc.Server, srv P2PodClusterStoreServer) {
+	s.RegisterService(&_P2PodClusterStore_serviceDesc, srv)
+}
+
+func _P2PodClusterStore_CreatePodCluster_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePodClusterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(P2PodClusterStoreServer).CreatePodCluster(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pcstore.P2PodClusterStore/CreatePodCluster",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(P2PodClusterStoreServer).CreatePodCluster(ctx, req.(*CreatePodClusterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _P2PodClusterStore_GetPodCluster_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPodClusterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(P2PodClusterStoreServer).GetPodCluster(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pcstore.P2PodClusterStore/GetPodCluster",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(P2PodClusterStoreServer).GetPodCluster(ctx, req.(*GetPodClusterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _P2PodClusterStore_ListPodClusters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPodClustersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(P2PodClusterStoreServer).ListPodClusters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pcstore.P2PodClusterStore/ListPodClusters",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(P2PodClusterStoreServer).ListPodClusters(ctx, req.(*ListPodClustersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _P2PodClusterStore_DeletePodCluster_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeletePodClusterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(P2PodClusterStoreServer).DeletePodCluster(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pcstore.P2PodClusterStore/DeletePodCluster",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(P2PodClusterStoreServer).DeletePodCluster(ctx, req.(*DeletePodClusterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _P2PodClusterStore_WatchPodClusters_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchPodClustersRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(P2PodClusterStoreServer).WatchPodClusters(m, &p2PodClusterStoreWatchPodClustersServer{stream})
+}
+
+type P2PodClusterStore_WatchPodClustersServer interface {
+	Send(*WatchPodClustersResponse) error
+	grpc.ServerStream
+}
+
+type p2PodClusterStoreWatchPodClustersServer struct {
+	grpc.ServerStream
+}
+
+func (x *p2PodClusterStoreWatchPodClustersServer) Send(m *WatchPodClustersResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _P2PodClusterStore_UpdateAnnotations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateAnnotationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(P2PodClusterStoreServer).UpdateAnnotations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pcstore.P2PodClusterStore/UpdateAnnotations",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(P2PodClusterStoreServer).UpdateAnnotations(ctx, req.(*UpdateAnnotationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _P2PodClusterStore_UpdatePodSelector_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdatePodSelectorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(P2PodClusterStoreServer).UpdatePodSelector(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pcstore.P2PodClusterStore/UpdatePodSelector",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(P2PodClusterStoreServer).UpdatePodSelector(ctx, req.(*UpdatePodSelectorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _P2PodClusterStore_UpdateAllocationStrategy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateAllocationStrategyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(P2PodClusterStoreServer).UpdateAllocationStrategy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pcstore.P2PodClusterStore/UpdateAllocationStrategy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(P2PodClusterStoreServer).UpdateAllocationStrategy(ctx, req.(*UpdateAllocationStrategyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _P2PodClusterStore_WatchAndSync_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchAndSyncRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(P2PodClusterStoreServer).WatchAndSync(m, &p2PodClusterStoreWatchAndSyncServer{stream})
+}
+
+type P2PodClusterStore_WatchAndSyncServer interface {
+	Send(*SyncEvent) error
+	grpc.ServerStream
+}
+
+type p2PodClusterStoreWatchAndSyncServer struct {
+	grpc.ServerStream
+}
+
+func (x *p2PodClusterStoreWatchAndSyncServer) Send(m *SyncEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _P2PodClusterStore_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "pcstore.P2PodClusterStore",
+	HandlerType: (*P2PodClusterStoreServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreatePodCluster",
+			Handler:    _P2PodClusterStore_CreatePodCluster_Handler,
+		},
+		{
+			MethodName: "GetPodCluster",
+			Handler:    _P2PodClusterStore_GetPodCluster_Handler,
+		},
+		{
+			MethodName: "ListPodClusters",
+			Handler:    _P2PodClusterStore_ListPodClusters_Handler,
+		},
+		{
+			MethodName: "DeletePodCluster",
+			Handler:    _P2PodClusterStore_DeletePodCluster_Handler,
+		},
+		{
+			MethodName: "UpdateAnnotations",
+			Handler:    _P2PodClusterStore_UpdateAnnotations_Handler,
+		},
+		{
+			MethodName: "UpdatePodSelector",
+			Handler:    _P2PodClusterStore_UpdatePodSelector_Handler,
+		},
+		{
+			MethodName: "UpdateAllocationStrategy",
+			Handler:    _P2PodClusterStore_UpdateAllocationStrategy_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchPodClusters",
+			Handler:       _P2PodClusterStore_WatchPodClusters_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchAndSync",
+			Handler:       _P2PodClusterStore_WatchAndSync_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "pcstore.proto",
+}
+
+func init() { proto.RegisterFile("pcstore.proto", fileDescriptor_pcstore_677b56cb25aa5108) }
+
+var fileDescriptor_pcstore_677b56cb25aa5108 = []byte{
+	// 810 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0x6d, 0x4f, 0xdb, 0x3a,
+	0x14, 0x56, 0xd2, 0x17, 0x2e, 0xa7, 0x85, 0xdb, 0xba, 0x40, 0x73, 0xa3, 0x7b, 0x2f, 0x6d, 0x34,
+	0x69, 0x95, 0x26, 0x31, 0x54, 0xf6, 0xae, 0x49, 0x13, 0x63, 0x68, 0x43, 0x62, 0xa8, 0x6b, 0x37,
+	0x21, 0xf6, 0x25, 0x72, 0x63, 0x8b, 0x46, 0x0b, 0x71, 0x96, 0x18, 0xb4, 0xee, 0x57, 0xec, 0x17,
+	0x6c, 0x9f, 0xb7, 0x5f, 0x39, 0xc5, 0x75, 0x93, 0x34, 0x69, 0x80, 0xc2, 0xbe, 0xd9, 0x3e, 0xc7,
+	0xcf, 0x79, 0x79, 0x7c, 0x9e, 0x04, 0x56, 0x3c, 0x2b, 0xe0, 0xcc, 0xa7, 0x5b, 0x9e, 0xcf, 0x38,
+	0x43, 0x4b, 0x72, 0x6b, 0x7c, 0x57, 0x01, 0x7a, 0x8c, 0xec, 0x39, 0xe7, 0x01, 0xa7, 0x3e, 0x5a,
+	0x05, 0xd5, 0x26, 0x9a, 0xd2, 0x52, 0x3a, 0xcb, 0x7d, 0xd5, 0x26, 0x68, 0x1d, 0xca, 0x1e, 0x23,
+	0xa6, 0x4d, 0x34, 0x55, 0x9c, 0x95, 0x3c, 0x46, 0x0e, 0x08, 0xba, 0x07, 0x75, 0x7c, 0x81, 0x6d,
+	0x07, 0x0f, 0x6d, 0xc7, 0xe6, 0x63, 0xf3, 0x2b, 0x73, 0xa9, 0x56, 0x10, 0x1e, 0xb5, 0xa4, 0xe1,
+	0x23, 0x73, 0x29, 0x42, 0x50, 0x74, 0xf1, 0x19, 0xd5, 0x8a, 0xc2, 0x2e, 0xd6, 0xa8, 0x0d, 0xd5,
+	0x10, 0x37, 0xa0, 0x0e, 0xb5, 0x38, 0xf3, 0xb5, 0x92, 0xb0, 0x55, 0x3c, 0x46, 0x06, 0xf2, 0x08,
+	0xb5, 0xa0, 0x82, 0x5d, 0x97, 0x71, 0xcc, 0x6d, 0xe6, 0x06, 0x5a, 0x79, 0xe2, 0x91, 0x38, 0x42,
+	0xf7, 0xa1, 0x81, 0x1d, 0x87, 0x59, 0x62, 0x6b, 0x06, 0xdc, 0xc7, 0x9c, 0x9e, 0x8e, 0xb5, 0x25,
+	0xe1, 0x89, 0x62, 0xd3, 0x40, 0x5a, 0x50, 0x17, 0xd6, 0xcf, 0x6c, 0xd7, 0x1c, 0x51, 0xec, 0xf0,
+	0x91, 0xe9, 0x51, 0xdf, 0xa2, 0x2e, 0xc7, 0xa7, 0x54, 0xfb, 0xab, 0xa5, 0x74, 0x0a, 0xfd, 0xc6,
+	0x99, 0xed, 0xbe, 0x11, 0xb6, 0x5e, 0x64, 0x32, 0xbe, 0x29, 0x00, 0x87, 0x78, 0x48, 0x1d, 0x4a,
+	0x7a, 0x8c, 0x64, 0x1a, 0xf4, 0x18, 0xca, 0x4e, 0x68, 0x0d, 0x34, 0xb5, 0x55, 0xe8, 0x54, 0xba,
+	0x9b, 0x5b, 0xd3, 0x46, 0xc7, 0x97, 0x26, 0xcb, 0x60, 0xdf, 0xe5, 0xfe, 0xb8, 0x2f, 0xdd, 0xf5,
+	0xa7, 0x50, 0x49, 0x1c, 0xa3, 0x1a, 0x14, 0x3e, 0xd1, 0xb1, 0x04, 0x0e, 0x97, 0x68, 0x0d, 0x4a,
+	0x17, 0xd8, 0x39, 0xa7, 0xd3, 0xce, 0x8b, 0xcd, 0x33, 0xf5, 0x89, 0x62, 0xfc, 0x52, 0xa1, 0xb9,
+	0xe7, 0x53, 0xcc, 0x69, 0xcc, 0x5c, 0x9f, 0x7e, 0x3e, 0xa7, 0x01, 0x4f, 0x10, 0xa6, 0x5c, 0x49,
+	0x98, 0x9a, 0x43, 0x58, 0x1b, 0xaa, 0xd6, 0x04, 0xd5, 0x14, 0xc4, 0x4d, 0x88, 0xad, 0xc8, 0xb3,
+	0xa3, 0x79, 0xfc, 0x15, 0xaf, 0xe4, 0xaf, 0x74, 0x6d, 0xfe, 0xca, 0x8b, 0xf3, 0xb7, 0x94, 0xcf,
+	0x5f, 0x0f, 0xb4, 0x6c, 0xaf, 0x02, 0x8f, 0xb9, 0x01, 0x45, 0x0f, 0x20, 0xcc, 0xd8, 0x94, 0x85,
+	0x89, 0x8e, 0x55, 0xba, 0x8d, 0x88, 0xc1, 0xc4, 0x0d, 0xf0, 0xa2, 0xb5, 0xf1, 0x1c, 0xd6, 0x5e,
+	0x53, 0x9e, 0x6d, 0xfd, 0x1d, 0x58, 0x4d, 0xa0, 0xc5, 0x14, 0x54, 0xe3, 0xbb, 0x07, 0xc4, 0x78,
+	0x0b, 0xeb, 0xa9, 0xdb, 0xb7, 0x4a, 0x46, 0x83, 0x8d, 0x43, 0x3b, 0x48, 0xe0, 0x05, 0x32, 0x1d,
+	0xe3, 0x1d, 0x34, 0x33, 0x16, 0x19, 0xea, 0x11, 0x54, 0x13, 0xa1, 0x02, 0x4d, 0x69, 0x15, 0xf2,
+	0x62, 0x55, 0xe2, 0x58, 0x81, 0xf1, 0x02, 0x9a, 0xaf, 0xa8, 0x43, 0x39, 0xbd, 0x69, 0xf1, 0x3a,
+	0x68, 0x59, 0x80, 0x49, 0x52, 0xc6, 0x3f, 0xd0, 0x3c, 0xc6, 0xdc, 0x1a, 0xcd, 0x29, 0x65, 0x04,
+	0x5a, 0xd6, 0x74, 0xbb, 0x5a, 0xc2, 0xf1, 0xa2, 0xbe, 0xcf, 0xfc, 0xe9, 0x78, 0x89, 0x8d, 0x31,
+	0x04, 0xed, 0x83, 0x47, 0x30, 0xa7, 0xbb, 0xf1, 0x3b, 0x5d, 0xa8, 0xc4, 0xf4, 0xb3, 0x57, 0x33,
+	0xcf, 0xde, 0xb0, 0xa6, 0x31, 0x7a, 0xf1, 0xb4, 0x2c, 0x16, 0x23, 0x3d, 0x7d, 0x6a, 0x66, 0xfa,
+	0x8c, 0x2f, 0xb0, 0x29, 0x0b, 0xc9, 0x8c, 0xd1, 0x62, 0xb1, 0x72, 0x86, 0x54, 0xcd, 0x1b, 0xd2,
+	0x70, 0xe0, 0xa2, 0xf2, 0xfe, 0xcc, 0x1b, 0x3f, 0x84, 0x86, 0xa0, 0x7f, 0xd7, 0x25, 0x83, 0xb1,
+	0x6b, 0x4d, 0xf3, 0x7f, 0x08, 0x4d, 0xdb, 0xb5, 0xb9, 0x8d, 0x1d, 0x73, 0xb6, 0x8e, 0xc9, 0x23,
+	0x58, 0xee, 0xaf, 0x49, 0x73, 0x2f, 0x51, 0x4f, 0x60, 0xfc, 0x50, 0x60, 0x39, 0x84, 0xd9, 0xbf,
+	0xa0, 0x2e, 0xbf, 0x59, 0x46, 0xe8, 0x2e, 0x14, 0x3d, 0x46, 0xa6, 0x9a, 0xdf, 0x98, 0xa3, 0xf9,
+	0x7d, 0xe1, 0x80, 0x76, 0x60, 0x83, 0x88, 0x07, 0x4f, 0x52, 0x39, 0x4a, 0x51, 0x6d, 0x48, 0x6b,
+	0x32, 0xc5, 0xee, 0xcf, 0x32, 0xd4, 0x7b, 0xdd, 0xf8, 0x68, 0x10, 0x62, 0xa3, 0x63, 0xa8, 0xa5,
+	0x85, 0x0c, 0xb5, 0xa2, 0xc8, 0x39, 0xdf, 0x03, 0xbd, 0x7d, 0x89, 0x87, 0x24, 0xe5, 0x08, 0x56,
+	0x66, 0x14, 0x09, 0xfd, 0x17, 0xdd, 0x99, 0xa7, 0x73, 0xfa, 0xff, 0x79, 0x66, 0x89, 0xf7, 0x1e,
+	0xfe, 0x4e, 0x09, 0x0f, 0x4a, 0x7c, 0x15, 0xe7, 0x8a, 0x95, 0xde, 0xca, 0x77, 0x90, 0xa8, 0xc7,
+	0x50, 0x4b, 0x4b, 0x47, 0xa2, 0xfc, 0x1c, 0x59, 0xd2, 0xdb, 0x97, 0x78, 0x48, 0xe0, 0x13, 0xa8,
+	0xa5, 0xc5, 0x25, 0x01, 0x9c, 0x23, 0x49, 0x7a, 0xfb, 0x12, 0x8f, 0x09, 0xf0, 0xb6, 0x82, 0x4e,
+	0xa0, 0x9e, 0x51, 0x13, 0x14, 0xdf, 0xcc, 0x53, 0x1a, 0x3d, 0xed, 0x32, 0x37, 0xeb, 0x7a, 0x46,
+	0x44, 0xd0, 0x9c, 0x7b, 0x29, 0x81, 0xb9, 0x0e, 0xf4, 0x69, 0xa4, 0x81, 0xd9, 0x2f, 0x70, 0x27,
+	0x9d, 0x7c, 0x9e, 0xba, 0x5c, 0x27, 0xd0, 0x4b, 0xa8, 0x26, 0xe7, 0x1a, 0xfd, 0x3b, 0xdb, 0xd3,
+	0xd9, 0x71, 0xd7, 0x51, 0x64, 0x8d, 0xa6, 0x77, 0x5b, 0x19, 0x96, 0xc5, 0xff, 0xec, 0xce, 0xef,
+	0x01, 0x00, 0xf2, 0x1e, 0xf9, 0x96, 0xe0, 0x0a, 0x00, 0x00,
+}
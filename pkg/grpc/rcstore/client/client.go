@@ -0,0 +1,371 @@
+package client
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
+	klabels "k8s.io/kubernetes/pkg/labels"
+
+	"github.com/square/p2/pkg/grpc/rcstore"
+	rcstore_protos "github.com/square/p2/pkg/grpc/rcstore/protos"
+	"github.com/square/p2/pkg/logging"
+	"github.com/square/p2/pkg/manifest"
+	pc_fields "github.com/square/p2/pkg/pc/fields"
+	"github.com/square/p2/pkg/rc/fields"
+	roll_fields "github.com/square/p2/pkg/roll/fields"
+	"github.com/square/p2/pkg/store/consul/statusstore/rcstatus"
+	"github.com/square/p2/pkg/store/consul/statusstore/rollstatus"
+	"github.com/square/p2/pkg/util"
+)
+
+type Client struct {
+	client rcstore_protos.P2RCStoreClient
+	logger logging.Logger
+}
+
+func New(conn *grpc.ClientConn, logger logging.Logger) Client {
+	return Client{
+		client: rcstore_protos.NewP2RCStoreClient(conn),
+		logger: logger,
+	}
+}
+
+func (c Client) GetStatus(ctx context.Context, rcID fields.ID) (rcstatus.Status, error) {
+	resp, err := c.client.GetStatus(ctx, &rcstore_protos.GetStatusRequest{
+		RcId: rcID.String(),
+	})
+	if err != nil {
+		return rcstatus.Status{}, util.Errorf("get status grpc for %s failed: %s", rcID, err)
+	}
+	return rcstore.ProtoToRCStatus(resp), nil
+}
+
+// WatchStatus sends the RC's status on the returned channel when called and
+// each time it changes, until ctx is canceled or an error occurs. Either way
+// the channel is closed, and any error is then available from the error
+// channel.
+func (c Client) WatchStatus(ctx context.Context, rcID fields.ID) (<-chan rcstatus.Status, <-chan error) {
+	outCh := make(chan rcstatus.Status)
+	errCh := make(chan error, 1)
+
+	go func() {
+		defer close(outCh)
+		defer close(errCh)
+
+		stream, err := c.client.WatchStatus(ctx, &rcstore_protos.WatchStatusRequest{
+			RcId: rcID.String(),
+		})
+		if err != nil {
+			errCh <- util.Errorf("watch status grpc for %s failed: %s", rcID, err)
+			return
+		}
+
+		for {
+			resp, err := stream.Recv()
+			if grpc.Code(err) == codes.Canceled {
+				c.logger.Infoln("rcstore grpc client: terminating WatchStatus()")
+				return
+			} else if err != nil {
+				errCh <- util.Errorf("watch status grpc for %s failed: %s", rcID, err)
+				return
+			}
+
+			select {
+			case outCh <- rcstore.ProtoToRCStatus(resp):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return outCh, errCh
+}
+
+// Create creates an RC with the given manifest and node selector. The cluster
+// name and availability zone are added to podLabels, and rcLabels are applied
+// to the RC itself. author is recorded as the author of the RC's first
+// revision.
+func (c Client) Create(
+	ctx context.Context,
+	man manifest.Manifest,
+	nodeSelector klabels.Selector,
+	availabilityZone pc_fields.AvailabilityZone,
+	clusterName pc_fields.ClusterName,
+	podLabels klabels.Set,
+	rcLabels klabels.Set,
+	allocationStrategy fields.Strategy,
+	author string,
+) (fields.RC, error) {
+	manifestBytes, err := man.Marshal()
+	if err != nil {
+		return fields.RC{}, util.Errorf("could not marshal manifest: %s", err)
+	}
+
+	resp, err := c.client.CreateRC(ctx, &rcstore_protos.CreateRCRequest{
+		Manifest:           string(manifestBytes),
+		NodeSelector:       nodeSelector.String(),
+		AvailabilityZone:   availabilityZone.String(),
+		ClusterName:        clusterName.String(),
+		PodLabels:          podLabels,
+		RcLabels:           rcLabels,
+		AllocationStrategy: string(allocationStrategy),
+		Author:             author,
+	})
+	if err != nil {
+		return fields.RC{}, util.Errorf("create rc grpc failed: %s", err)
+	}
+
+	rc, err := rcstore.ProtoToRC(resp.Rc)
+	if err != nil {
+		return fields.RC{}, util.Errorf("creating succeeded but could not convert from grpc proto type to RC: %s", err)
+	}
+	return rc, nil
+}
+
+func (c Client) Get(ctx context.Context, rcID fields.ID) (fields.RC, error) {
+	resp, err := c.client.GetRC(ctx, &rcstore_protos.GetRCRequest{
+		RcId: rcID.String(),
+	})
+	if err != nil {
+		return fields.RC{}, util.Errorf("get rc grpc for %s failed: %s", rcID, err)
+	}
+
+	rc, err := rcstore.ProtoToRC(resp.Rc)
+	if err != nil {
+		return fields.RC{}, util.Errorf("get rc grpc for %s failed: %s", rcID, err)
+	}
+	return rc, nil
+}
+
+func (c Client) List(ctx context.Context) ([]fields.RC, error) {
+	resp, err := c.client.ListRCs(ctx, &rcstore_protos.ListRCsRequest{})
+	if err != nil {
+		return nil, util.Errorf("list rcs grpc failed: %s", err)
+	}
+
+	out := make([]fields.RC, len(resp.Rcs))
+	for i, rcProto := range resp.Rcs {
+		out[i], err = rcstore.ProtoToRC(rcProto)
+		if err != nil {
+			return nil, util.Errorf("list rcs grpc failed: %s", err)
+		}
+	}
+	return out, nil
+}
+
+// Watch sends the RC on the returned channel when called and each time it
+// changes, until ctx is canceled or an error occurs. The channels behave like
+// those returned by WatchStatus.
+func (c Client) Watch(ctx context.Context, rcID fields.ID) (<-chan fields.RC, <-chan error) {
+	outCh := make(chan fields.RC)
+	errCh := make(chan error, 1)
+
+	go func() {
+		defer close(outCh)
+		defer close(errCh)
+
+		stream, err := c.client.WatchRC(ctx, &rcstore_protos.WatchRCRequest{
+			RcId: rcID.String(),
+		})
+		if err != nil {
+			errCh <- util.Errorf("watch rc grpc for %s failed: %s", rcID, err)
+			return
+		}
+
+		for {
+			resp, err := stream.Recv()
+			if grpc.Code(err) == codes.Canceled {
+				c.logger.Infoln("rcstore grpc client: terminating Watch()")
+				return
+			} else if err != nil {
+				errCh <- util.Errorf("watch rc grpc for %s failed: %s", rcID, err)
+				return
+			}
+
+			rc, err := rcstore.ProtoToRC(resp.Rc)
+			if err != nil {
+				errCh <- util.Errorf("watch rc grpc for %s failed: %s", rcID, err)
+				return
+			}
+
+			select {
+			case outCh <- rc:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return outCh, errCh
+}
+
+func (c Client) SetDesiredReplicas(ctx context.Context, rcID fields.ID, n int) error {
+	_, err := c.client.SetDesiredReplicas(ctx, &rcstore_protos.SetDesiredReplicasRequest{
+		RcId:     rcID.String(),
+		Replicas: int64(n),
+	})
+	if err != nil {
+		return util.Errorf("set desired replicas grpc for %s failed: %s", rcID, err)
+	}
+	return nil
+}
+
+func (c Client) Enable(ctx context.Context, rcID fields.ID) error {
+	_, err := c.client.EnableRC(ctx, &rcstore_protos.EnableRCRequest{
+		RcId: rcID.String(),
+	})
+	if err != nil {
+		return util.Errorf("enable rc grpc for %s failed: %s", rcID, err)
+	}
+	return nil
+}
+
+func (c Client) Disable(ctx context.Context, rcID fields.ID) error {
+	_, err := c.client.DisableRC(ctx, &rcstore_protos.DisableRCRequest{
+		RcId: rcID.String(),
+	})
+	if err != nil {
+		return util.Errorf("disable rc grpc for %s failed: %s", rcID, err)
+	}
+	return nil
+}
+
+// Delete deletes the RC. Unless force is set, this fails if the RC still has
+// desired replicas.
+func (c Client) Delete(ctx context.Context, rcID fields.ID, force bool) error {
+	_, err := c.client.DeleteRC(ctx, &rcstore_protos.DeleteRCRequest{
+		RcId:  rcID.String(),
+		Force: force,
+	})
+	if err != nil {
+		return util.Errorf("delete rc grpc for %s failed: %s", rcID, err)
+	}
+	return nil
+}
+
+// ScheduleRollingUpdate creates a rolling update between two existing RCs
+func (c Client) ScheduleRollingUpdate(ctx context.Context, update roll_fields.Update) (roll_fields.Update, error) {
+	resp, err := c.client.ScheduleRollingUpdate(ctx, &rcstore_protos.ScheduleRollingUpdateRequest{
+		RollingUpdate: rcstore.RollingUpdateToProto(update),
+	})
+	if err != nil {
+		return roll_fields.Update{}, util.Errorf("schedule rolling update grpc for %s failed: %s", update.ID(), err)
+	}
+	return rcstore.ProtoToRollingUpdate(resp.RollingUpdate), nil
+}
+
+func (c Client) ListRollingUpdates(ctx context.Context) ([]roll_fields.Update, error) {
+	resp, err := c.client.ListRollingUpdates(ctx, &rcstore_protos.ListRollingUpdatesRequest{})
+	if err != nil {
+		return nil, util.Errorf("list rolling updates grpc failed: %s", err)
+	}
+	return protosToRollingUpdates(resp.RollingUpdates), nil
+}
+
+func (c Client) DeleteRollingUpdate(ctx context.Context, rollID roll_fields.ID) error {
+	_, err := c.client.DeleteRollingUpdate(ctx, &rcstore_protos.DeleteRollingUpdateRequest{
+		RollId: rollID.String(),
+	})
+	if err != nil {
+		return util.Errorf("delete rolling update grpc for %s failed: %s", rollID, err)
+	}
+	return nil
+}
+
+// WatchRollingUpdates sends every rolling update on the returned channel when
+// called and each time any of them changes, until ctx is canceled or an error
+// occurs. The channels behave like those returned by WatchStatus.
+func (c Client) WatchRollingUpdates(ctx context.Context) (<-chan []roll_fields.Update, <-chan error) {
+	outCh := make(chan []roll_fields.Update)
+	errCh := make(chan error, 1)
+
+	go func() {
+		defer close(outCh)
+		defer close(errCh)
+
+		stream, err := c.client.WatchRollingUpdates(ctx, &rcstore_protos.WatchRollingUpdatesRequest{})
+		if err != nil {
+			errCh <- util.Errorf("watch rolling updates grpc failed: %s", err)
+			return
+		}
+
+		for {
+			resp, err := stream.Recv()
+			if grpc.Code(err) == codes.Canceled {
+				c.logger.Infoln("rcstore grpc client: terminating WatchRollingUpdates()")
+				return
+			} else if err != nil {
+				errCh <- util.Errorf("watch rolling updates grpc failed: %s", err)
+				return
+			}
+
+			select {
+			case outCh <- protosToRollingUpdates(resp.RollingUpdates):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return outCh, errCh
+}
+
+func (c Client) GetRollStatus(ctx context.Context, rollID roll_fields.ID) (rollstatus.Status, error) {
+	resp, err := c.client.GetRollStatus(ctx, &rcstore_protos.GetRollStatusRequest{
+		RollId: rollID.String(),
+	})
+	if err != nil {
+		return rollstatus.Status{}, util.Errorf("get roll status grpc for %s failed: %s", rollID, err)
+	}
+	return rcstore.ProtoToRollStatus(resp), nil
+}
+
+// WatchRollStatus sends the rolling update's status on the returned channel
+// when called and each time it changes, until ctx is canceled or an error
+// occurs. The channels behave like those returned by WatchStatus.
+func (c Client) WatchRollStatus(ctx context.Context, rollID roll_fields.ID) (<-chan rollstatus.Status, <-chan error) {
+	outCh := make(chan rollstatus.Status)
+	errCh := make(chan error, 1)
+
+	go func() {
+		defer close(outCh)
+		defer close(errCh)
+
+		stream, err := c.client.WatchRollStatus(ctx, &rcstore_protos.WatchRollStatusRequest{
+			RollId: rollID.String(),
+		})
+		if err != nil {
+			errCh <- util.Errorf("watch roll status grpc for %s failed: %s", rollID, err)
+			return
+		}
+
+		for {
+			resp, err := stream.Recv()
+			if grpc.Code(err) == codes.Canceled {
+				c.logger.Infoln("rcstore grpc client: terminating WatchRollStatus()")
+				return
+			} else if err != nil {
+				errCh <- util.Errorf("watch roll status grpc for %s failed: %s", rollID, err)
+				return
+			}
+
+			select {
+			case outCh <- rcstore.ProtoToRollStatus(resp):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return outCh, errCh
+}
+
+func protosToRollingUpdates(protos []*rcstore_protos.RollingUpdate) []roll_fields.Update {
+	out := make([]roll_fields.Update, len(protos))
+	for i, proto := range protos {
+		out[i] = rcstore.ProtoToRollingUpdate(proto)
+	}
+	return out
+}
@@ -0,0 +1,3016 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: rcstore.proto
+
+package rcstore
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type GetStatusRequest struct {
+	RcId                 string   `protobuf:"bytes,1,opt,name=rc_id,json=rcId,proto3" json:"rc_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetStatusRequest) Reset()         { *m = GetStatusRequest{} }
+func (m *GetStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*GetStatusRequest) ProtoMessage()    {}
+func (*GetStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rcstore_2aafd1c6eb6122ec, []int{0}
+}
+func (m *GetStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetStatusRequest.Unmarshal(m, b)
+}
+func (m *GetStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetStatusRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetStatusRequest.Merge(dst, src)
+}
+func (m *GetStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_GetStatusRequest.Size(m)
+}
+func (m *GetStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetStatusRequest proto.InternalMessageInfo
+
+func (m *GetStatusRequest) GetRcId() string {
+	if m != nil {
+		return m.RcId
+	}
+	return ""
+}
+
+type WatchStatusRequest struct {
+	RcId                 string   `protobuf:"bytes,1,opt,name=rc_id,json=rcId,proto3" json:"rc_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchStatusRequest) Reset()         { *m = WatchStatusRequest{} }
+func (m *WatchStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchStatusRequest) ProtoMessage()    {}
+func (*WatchStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rcstore_2aafd1c6eb6122ec, []int{1}
+}
+func (m *WatchStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchStatusRequest.Unmarshal(m, b)
+}
+func (m *WatchStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchStatusRequest.Marshal(b, m, deterministic)
+}
+func (dst *WatchStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchStatusRequest.Merge(dst, src)
+}
+func (m *WatchStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_WatchStatusRequest.Size(m)
+}
+func (m *WatchStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchStatusRequest proto.InternalMessageInfo
+
+func (m *WatchStatusRequest) GetRcId() string {
+	if m != nil {
+		return m.RcId
+	}
+	return ""
+}
+
+// models rcstatus.Status. Times are expressed in nanoseconds since the unix
+// epoch, and are 0 when unset
+type RCStatus struct {
+	ReplicasDesired           int64              `protobuf:"varint,1,opt,name=replicas_desired,json=replicasDesired,proto3" json:"replicas_desired,omitempty"`
+	ReplicasScheduled         int64              `protobuf:"varint,2,opt,name=replicas_scheduled,json=replicasScheduled,proto3" json:"replicas_scheduled,omitempty"`
+	ReplicasCurrent           int64              `protobuf:"varint,3,opt,name=replicas_current,json=replicasCurrent,proto3" json:"replicas_current,omitempty"`
+	ReplicasHealthy           int64              `protobuf:"varint,4,opt,name=replicas_healthy,json=replicasHealthy,proto3" json:"replicas_healthy,omitempty"`
+	ReplicasUnhealthy         int64              `protobuf:"varint,5,opt,name=replicas_unhealthy,json=replicasUnhealthy,proto3" json:"replicas_unhealthy,omitempty"`
+	IneligibleNodes           []string           `protobuf:"bytes,6,rep,name=ineligible_nodes,json=ineligibleNodes,proto3" json:"ineligible_nodes,omitempty"`
+	Conditions                []*Condition       `protobuf:"bytes,7,rep,name=conditions,proto3" json:"conditions,omitempty"`
+	LastError                 string             `protobuf:"bytes,8,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	LastErrorTime             int64              `protobuf:"varint,9,opt,name=last_error_time,json=lastErrorTime,proto3" json:"last_error_time,omitempty"`
+	LastUpdateTime            int64              `protobuf:"varint,10,opt,name=last_update_time,json=lastUpdateTime,proto3" json:"last_update_time,omitempty"`
+	MissingArtifacts          []*MissingArtifact `protobuf:"bytes,11,rep,name=missing_artifacts,json=missingArtifacts,proto3" json:"missing_artifacts,omitempty"`
+	MissingArtifactsCheckTime int64              `protobuf:"varint,12,opt,name=missing_artifacts_check_time,json=missingArtifactsCheckTime,proto3" json:"missing_artifacts_check_time,omitempty"`
+	XXX_NoUnkeyedLiteral      struct{}           `json:"-"`
+	XXX_unrecognized          []byte             `json:"-"`
+	XXX_sizecache             int32              `json:"-"`
+}
+
+func (m *RCStatus) Reset()         { *m = RCStatus{} }
+func (m *RCStatus) String() string { return proto.CompactTextString(m) }
+func (*RCStatus) ProtoMessage()    {}
+func (*RCStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rcstore_2aafd1c6eb6122ec, []int{2}
+}
+func (m *RCStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RCStatus.Unmarshal(m, b)
+}
+func (m *RCStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RCStatus.Marshal(b, m, deterministic)
+}
+func (dst *RCStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RCStatus.Merge(dst, src)
+}
+func (m *RCStatus) XXX_Size() int {
+	return xxx_messageInfo_RCStatus.Size(m)
+}
+func (m *RCStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_RCStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RCStatus proto.InternalMessageInfo
+
+func (m *RCStatus) GetReplicasDesired() int64 {
+	if m != nil {
+		return m.ReplicasDesired
+	}
+	return 0
+}
+
+func (m *RCStatus) GetReplicasScheduled() int64 {
+	if m != nil {
+		return m.ReplicasScheduled
+	}
+	return 0
+}
+
+func (m *RCStatus) GetReplicasCurrent() int64 {
+	if m != nil {
+		return m.ReplicasCurrent
+	}
+	return 0
+}
+
+func (m *RCStatus) GetReplicasHealthy() int64 {
+	if m != nil {
+		return m.ReplicasHealthy
+	}
+	return 0
+}
+
+func (m *RCStatus) GetReplicasUnhealthy() int64 {
+	if m != nil {
+		return m.ReplicasUnhealthy
+	}
+	return 0
+}
+
+func (m *RCStatus) GetIneligibleNodes() []string {
+	if m != nil {
+		return m.IneligibleNodes
+	}
+	return nil
+}
+
+func (m *RCStatus) GetConditions() []*Condition {
+	if m != nil {
+		return m.Conditions
+	}
+	return nil
+}
+
+func (m *RCStatus) GetLastError() string {
+	if m != nil {
+		return m.LastError
+	}
+	return ""
+}
+
+func (m *RCStatus) GetLastErrorTime() int64 {
+	if m != nil {
+		return m.LastErrorTime
+	}
+	return 0
+}
+
+func (m *RCStatus) GetLastUpdateTime() int64 {
+	if m != nil {
+		return m.LastUpdateTime
+	}
+	return 0
+}
+
+func (m *RCStatus) GetMissingArtifacts() []*MissingArtifact {
+	if m != nil {
+		return m.MissingArtifacts
+	}
+	return nil
+}
+
+func (m *RCStatus) GetMissingArtifactsCheckTime() int64 {
+	if m != nil {
+		return m.MissingArtifactsCheckTime
+	}
+	return 0
+}
+
+type Condition struct {
+	Type                 string   `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Status               bool     `protobuf:"varint,2,opt,name=status,proto3" json:"status,omitempty"`
+	Message              string   `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	LastTransitionTime   int64    `protobuf:"varint,4,opt,name=last_transition_time,json=lastTransitionTime,proto3" json:"last_transition_time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Condition) Reset()         { *m = Condition{} }
+func (m *Condition) String() string { return proto.CompactTextString(m) }
+func (*Condition) ProtoMessage()    {}
+func (*Condition) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rcstore_2aafd1c6eb6122ec, []int{3}
+}
+func (m *Condition) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Condition.Unmarshal(m, b)
+}
+func (m *Condition) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Condition.Marshal(b, m, deterministic)
+}
+func (dst *Condition) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Condition.Merge(dst, src)
+}
+func (m *Condition) XXX_Size() int {
+	return xxx_messageInfo_Condition.Size(m)
+}
+func (m *Condition) XXX_DiscardUnknown() {
+	xxx_messageInfo_Condition.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Condition proto.InternalMessageInfo
+
+func (m *Condition) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *Condition) GetStatus() bool {
+	if m != nil {
+		return m.Status
+	}
+	return false
+}
+
+func (m *Condition) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *Condition) GetLastTransitionTime() int64 {
+	if m != nil {
+		return m.LastTransitionTime
+	}
+	return 0
+}
+
+type MissingArtifact struct {
+	LaunchableId         string   `protobuf:"bytes,1,opt,name=launchable_id,json=launchableId,proto3" json:"launchable_id,omitempty"`
+	Url                  string   `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MissingArtifact) Reset()         { *m = MissingArtifact{} }
+func (m *MissingArtifact) String() string { return proto.CompactTextString(m) }
+func (*MissingArtifact) ProtoMessage()    {}
+func (*MissingArtifact) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rcstore_2aafd1c6eb6122ec, []int{4}
+}
+func (m *MissingArtifact) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MissingArtifact.Unmarshal(m, b)
+}
+func (m *MissingArtifact) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MissingArtifact.Marshal(b, m, deterministic)
+}
+func (dst *MissingArtifact) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MissingArtifact.Merge(dst, src)
+}
+func (m *MissingArtifact) XXX_Size() int {
+	return xxx_messageInfo_MissingArtifact.Size(m)
+}
+func (m *MissingArtifact) XXX_DiscardUnknown() {
+	xxx_messageInfo_MissingArtifact.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MissingArtifact proto.InternalMessageInfo
+
+func (m *MissingArtifact) GetLaunchableId() string {
+	if m != nil {
+		return m.LaunchableId
+	}
+	return ""
+}
+
+func (m *MissingArtifact) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+// models rc/fields.RC
+type RC struct {
+	Id                 string              `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Manifest           string              `protobuf:"bytes,2,opt,name=manifest,proto3" json:"manifest,omitempty"`
+	NodeSelector       string              `protobuf:"bytes,3,opt,name=node_selector,json=nodeSelector,proto3" json:"node_selector,omitempty"`
+	PodLabels          map[string]string   `protobuf:"bytes,4,rep,name=pod_labels,json=podLabels,proto3" json:"pod_labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	ReplicasDesired    int64               `protobuf:"varint,5,opt,name=replicas_desired,json=replicasDesired,proto3" json:"replicas_desired,omitempty"`
+	Disabled           bool                `protobuf:"varint,6,opt,name=disabled,proto3" json:"disabled,omitempty"`
+	AllocationStrategy string              `protobuf:"bytes,7,opt,name=allocation_strategy,json=allocationStrategy,proto3" json:"allocation_strategy,omitempty"`
+	SpreadConstraints  []*SpreadConstraint `protobuf:"bytes,8,rep,name=spread_constraints,json=spreadConstraints,proto3" json:"spread_constraints,omitempty"`
+	// unset if the RC isn't autoscaled
+	Autoscale *AutoscalePolicy `protobuf:"bytes,9,opt,name=autoscale,proto3" json:"autoscale,omitempty"`
+	// unset if the RC doesn't filter the zones it schedules on or
+	// unschedules from first
+	ScheduleZones        *ZoneFilter `protobuf:"bytes,10,opt,name=schedule_zones,json=scheduleZones,proto3" json:"schedule_zones,omitempty"`
+	UnscheduleZones      *ZoneFilter `protobuf:"bytes,11,opt,name=unschedule_zones,json=unscheduleZones,proto3" json:"unschedule_zones,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *RC) Reset()         { *m = RC{} }
+func (m *RC) String() string { return proto.CompactTextString(m) }
+func (*RC) ProtoMessage()    {}
+func (*RC) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rcstore_2aafd1c6eb6122ec, []int{5}
+}
+func (m *RC) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RC.Unmarshal(m, b)
+}
+func (m *RC) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RC.Marshal(b, m, deterministic)
+}
+func (dst *RC) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RC.Merge(dst, src)
+}
+func (m *RC) XXX_Size() int {
+	return xxx_messageInfo_RC.Size(m)
+}
+func (m *RC) XXX_DiscardUnknown() {
+	xxx_messageInfo_RC.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RC proto.InternalMessageInfo
+
+func (m *RC) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *RC) GetManifest() string {
+	if m != nil {
+		return m.Manifest
+	}
+	return ""
+}
+
+func (m *RC) GetNodeSelector() string {
+	if m != nil {
+		return m.NodeSelector
+	}
+	return ""
+}
+
+func (m *RC) GetPodLabels() map[string]string {
+	if m != nil {
+		return m.PodLabels
+	}
+	return nil
+}
+
+func (m *RC) GetReplicasDesired() int64 {
+	if m != nil {
+		return m.ReplicasDesired
+	}
+	return 0
+}
+
+func (m *RC) GetDisabled() bool {
+	if m != nil {
+		return m.Disabled
+	}
+	return false
+}
+
+func (m *RC) GetAllocationStrategy() string {
+	if m != nil {
+		return m.AllocationStrategy
+	}
+	return ""
+}
+
+func (m *RC) GetSpreadConstraints() []*SpreadConstraint {
+	if m != nil {
+		return m.SpreadConstraints
+	}
+	return nil
+}
+
+func (m *RC) GetAutoscale() *AutoscalePolicy {
+	if m != nil {
+		return m.Autoscale
+	}
+	return nil
+}
+
+func (m *RC) GetScheduleZones() *ZoneFilter {
+	if m != nil {
+		return m.ScheduleZones
+	}
+	return nil
+}
+
+func (m *RC) GetUnscheduleZones() *ZoneFilter {
+	if m != nil {
+		return m.UnscheduleZones
+	}
+	return nil
+}
+
+type SpreadConstraint struct {
+	TopologyKey          string   `protobuf:"bytes,1,opt,name=topology_key,json=topologyKey,proto3" json:"topology_key,omitempty"`
+	MaxSkew              int64    `protobuf:"varint,2,opt,name=max_skew,json=maxSkew,proto3" json:"max_skew,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SpreadConstraint) Reset()         { *m = SpreadConstraint{} }
+func (m *SpreadConstraint) String() string { return proto.CompactTextString(m) }
+func (*SpreadConstraint) ProtoMessage()    {}
+func (*SpreadConstraint) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rcstore_2aafd1c6eb6122ec, []int{6}
+}
+func (m *SpreadConstraint) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SpreadConstraint.Unmarshal(m, b)
+}
+func (m *SpreadConstraint) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SpreadConstraint.Marshal(b, m, deterministic)
+}
+func (dst *SpreadConstraint) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SpreadConstraint.Merge(dst, src)
+}
+func (m *SpreadConstraint) XXX_Size() int {
+	return xxx_messageInfo_SpreadConstraint.Size(m)
+}
+func (m *SpreadConstraint) XXX_DiscardUnknown() {
+	xxx_messageInfo_SpreadConstraint.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SpreadConstraint proto.InternalMessageInfo
+
+func (m *SpreadConstraint) GetTopologyKey() string {
+	if m != nil {
+		return m.TopologyKey
+	}
+	return ""
+}
+
+func (m *SpreadConstraint) GetMaxSkew() int64 {
+	if m != nil {
+		return m.MaxSkew
+	}
+	return 0
+}
+
+type AutoscalePolicy struct {
+	MinReplicas int64   `protobuf:"varint,1,opt,name=min_replicas,json=minReplicas,proto3" json:"min_replicas,omitempty"`
+	MaxReplicas int64   `protobuf:"varint,2,opt,name=max_replicas,json=maxReplicas,proto3" json:"max_replicas,omitempty"`
+	Target      float64 `protobuf:"fixed64,3,opt,name=target,proto3" json:"target,omitempty"`
+	// expressed in nanoseconds (matches time.Duration)
+	Cooldown             int64         `protobuf:"varint,4,opt,name=cooldown,proto3" json:"cooldown,omitempty"`
+	Metric               *MetricSource `protobuf:"bytes,5,opt,name=metric,proto3" json:"metric,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *AutoscalePolicy) Reset()         { *m = AutoscalePolicy{} }
+func (m *AutoscalePolicy) String() string { return proto.CompactTextString(m) }
+func (*AutoscalePolicy) ProtoMessage()    {}
+func (*AutoscalePolicy) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rcstore_2aafd1c6eb6122ec, []int{7}
+}
+func (m *AutoscalePolicy) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AutoscalePolicy.Unmarshal(m, b)
+}
+func (m *AutoscalePolicy) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AutoscalePolicy.Marshal(b, m, deterministic)
+}
+func (dst *AutoscalePolicy) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AutoscalePolicy.Merge(dst, src)
+}
+func (m *AutoscalePolicy) XXX_Size() int {
+	return xxx_messageInfo_AutoscalePolicy.Size(m)
+}
+func (m *AutoscalePolicy) XXX_DiscardUnknown() {
+	xxx_messageInfo_AutoscalePolicy.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AutoscalePolicy proto.InternalMessageInfo
+
+func (m *AutoscalePolicy) GetMinReplicas() int64 {
+	if m != nil {
+		return m.MinReplicas
+	}
+	return 0
+}
+
+func (m *AutoscalePolicy) GetMaxReplicas() int64 {
+	if m != nil {
+		return m.MaxReplicas
+	}
+	return 0
+}
+
+func (m *AutoscalePolicy) GetTarget() float64 {
+	if m != nil {
+		return m.Target
+	}
+	return 0
+}
+
+func (m *AutoscalePolicy) GetCooldown() int64 {
+	if m != nil {
+		return m.Cooldown
+	}
+	return 0
+}
+
+func (m *AutoscalePolicy) GetMetric() *MetricSource {
+	if m != nil {
+		return m.Metric
+	}
+	return nil
+}
+
+type MetricSource struct {
+	Type                 string   `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Url                  string   `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Path                 string   `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`
+	Port                 int64    `protobuf:"varint,4,opt,name=port,proto3" json:"port,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MetricSource) Reset()         { *m = MetricSource{} }
+func (m *MetricSource) String() string { return proto.CompactTextString(m) }
+func (*MetricSource) ProtoMessage()    {}
+func (*MetricSource) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rcstore_2aafd1c6eb6122ec, []int{8}
+}
+func (m *MetricSource) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MetricSource.Unmarshal(m, b)
+}
+func (m *MetricSource) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MetricSource.Marshal(b, m, deterministic)
+}
+func (dst *MetricSource) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MetricSource.Merge(dst, src)
+}
+func (m *MetricSource) XXX_Size() int {
+	return xxx_messageInfo_MetricSource.Size(m)
+}
+func (m *MetricSource) XXX_DiscardUnknown() {
+	xxx_messageInfo_MetricSource.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MetricSource proto.InternalMessageInfo
+
+func (m *MetricSource) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *MetricSource) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *MetricSource) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *MetricSource) GetPort() int64 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
+}
+
+type ZoneFilter struct {
+	Label                string   `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
+	Values               []string `protobuf:"bytes,2,rep,name=values,proto3" json:"values,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ZoneFilter) Reset()         { *m = ZoneFilter{} }
+func (m *ZoneFilter) String() string { return proto.CompactTextString(m) }
+func (*ZoneFilter) ProtoMessage()    {}
+func (*ZoneFilter) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rcstore_2aafd1c6eb6122ec, []int{9}
+}
+func (m *ZoneFilter) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ZoneFilter.Unmarshal(m, b)
+}
+func (m *ZoneFilter) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ZoneFilter.Marshal(b, m, deterministic)
+}
+func (dst *ZoneFilter) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ZoneFilter.Merge(dst, src)
+}
+func (m *ZoneFilter) XXX_Size() int {
+	return xxx_messageInfo_ZoneFilter.Size(m)
+}
+func (m *ZoneFilter) XXX_DiscardUnknown() {
+	xxx_messageInfo_ZoneFilter.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ZoneFilter proto.InternalMessageInfo
+
+func (m *ZoneFilter) GetLabel() string {
+	if m != nil {
+		return m.Label
+	}
+	return ""
+}
+
+func (m *ZoneFilter) GetValues() []string {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+type CreateRCRequest struct {
+	Manifest         string            `protobuf:"bytes,1,opt,name=manifest,proto3" json:"manifest,omitempty"`
+	NodeSelector     string            `protobuf:"bytes,2,opt,name=node_selector,json=nodeSelector,proto3" json:"node_selector,omitempty"`
+	AvailabilityZone string            `protobuf:"bytes,3,opt,name=availability_zone,json=availabilityZone,proto3" json:"availability_zone,omitempty"`
+	ClusterName      string            `protobuf:"bytes,4,opt,name=cluster_name,json=clusterName,proto3" json:"cluster_name,omitempty"`
+	PodLabels        map[string]string `protobuf:"bytes,5,rep,name=pod_labels,json=podLabels,proto3" json:"pod_labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// labels applied to the RC itself
+	RcLabels           map[string]string `protobuf:"bytes,6,rep,name=rc_labels,json=rcLabels,proto3" json:"rc_labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	AllocationStrategy string            `protobuf:"bytes,7,opt,name=allocation_strategy,json=allocationStrategy,proto3" json:"allocation_strategy,omitempty"`
+	// recorded as the author of the RC's first revision
+	Author               string   `protobuf:"bytes,8,opt,name=author,proto3" json:"author,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateRCRequest) Reset()         { *m = CreateRCRequest{} }
+func (m *CreateRCRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateRCRequest) ProtoMessage()    {}
+func (*CreateRCRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rcstore_2aafd1c6eb6122ec, []int{10}
+}
+func (m *CreateRCRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateRCRequest.Unmarshal(m, b)
+}
+func (m *CreateRCRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateRCRequest.Marshal(b, m, deterministic)
+}
+func (dst *CreateRCRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateRCRequest.Merge(dst, src)
+}
+func (m *CreateRCRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateRCRequest.Size(m)
+}
+func (m *CreateRCRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateRCRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateRCRequest proto.InternalMessageInfo
+
+func (m *CreateRCRequest) GetManifest() string {
+	if m != nil {
+		return m.Manifest
+	}
+	return ""
+}
+
+func (m *CreateRCRequest) GetNodeSelector() string {
+	if m != nil {
+		return m.NodeSelector
+	}
+	return ""
+}
+
+func (m *CreateRCRequest) GetAvailabilityZone() string {
+	if m != nil {
+		return m.AvailabilityZone
+	}
+	return ""
+}
+
+func (m *CreateRCRequest) GetClusterName() string {
+	if m != nil {
+		return m.ClusterName
+	}
+	return ""
+}
+
+func (m *CreateRCRequest) GetPodLabels() map[string]string {
+	if m != nil {
+		return m.PodLabels
+	}
+	return nil
+}
+
+func (m *CreateRCRequest) GetRcLabels() map[string]string {
+	if m != nil {
+		return m.RcLabels
+	}
+	return nil
+}
+
+func (m *CreateRCRequest) GetAllocationStrategy() string {
+	if m != nil {
+		return m.AllocationStrategy
+	}
+	return ""
+}
+
+func (m *CreateRCRequest) GetAuthor() string {
+	if m != nil {
+		return m.Author
+	}
+	return ""
+}
+
+type CreateRCResponse struct {
+	Rc                   *RC      `protobuf:"bytes,1,opt,name=rc,proto3" json:"rc,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateRCResponse) Reset()         { *m = CreateRCResponse{} }
+func (m *CreateRCResponse) String() string { return proto.CompactTextString(m) }
+func (*CreateRCResponse) ProtoMessage()    {}
+func (*CreateRCResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rcstore_2aafd1c6eb6122ec, []int{11}
+}
+func (m *CreateRCResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateRCResponse.Unmarshal(m, b)
+}
+func (m *CreateRCResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateRCResponse.Marshal(b, m, deterministic)
+}
+func (dst *CreateRCResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateRCResponse.Merge(dst, src)
+}
+func (m *CreateRCResponse) XXX_Size() int {
+	return xxx_messageInfo_CreateRCResponse.Size(m)
+}
+func (m *CreateRCResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateRCResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateRCResponse proto.InternalMessageInfo
+
+func (m *CreateRCResponse) GetRc() *RC {
+	if m != nil {
+		return m.Rc
+	}
+	return nil
+}
+
+type GetRCRequest struct {
+	RcId                 string   `protobuf:"bytes,1,opt,name=rc_id,json=rcId,proto3" json:"rc_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetRCRequest) Reset()         { *m = GetRCRequest{} }
+func (m *GetRCRequest) String() string { return proto.CompactTextString(m) }
+func (*GetRCRequest) ProtoMessage()    {}
+func (*GetRCRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rcstore_2aafd1c6eb6122ec, []int{12}
+}
+func (m *GetRCRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetRCRequest.Unmarshal(m, b)
+}
+func (m *GetRCRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetRCRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetRCRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetRCRequest.Merge(dst, src)
+}
+func (m *GetRCRequest) XXX_Size() int {
+	return xxx_messageInfo_GetRCRequest.Size(m)
+}
+func (m *GetRCRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetRCRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetRCRequest proto.InternalMessageInfo
+
+func (m *GetRCRequest) GetRcId() string {
+	if m != nil {
+		return m.RcId
+	}
+	return ""
+}
+
+type GetRCResponse struct {
+	Rc                   *RC      `protobuf:"bytes,1,opt,name=rc,proto3" json:"rc,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetRCResponse) Reset()         { *m = GetRCResponse{} }
+func (m *GetRCResponse) String() string { return proto.CompactTextString(m) }
+func (*GetRCResponse) ProtoMessage()    {}
+func (*GetRCResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rcstore_2aafd1c6eb6122ec, []int{13}
+}
+func (m *GetRCResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetRCResponse.Unmarshal(m, b)
+}
+func (m *GetRCResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetRCResponse.Marshal(b, m, deterministic)
+}
+func (dst *GetRCResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetRCResponse.Merge(dst, src)
+}
+func (m *GetRCResponse) XXX_Size() int {
+	return xxx_messageInfo_GetRCResponse.Size(m)
+}
+func (m *GetRCResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetRCResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetRCResponse proto.InternalMessageInfo
+
+func (m *GetRCResponse) GetRc() *RC {
+	if m != nil {
+		return m.Rc
+	}
+	return nil
+}
+
+type ListRCsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListRCsRequest) Reset()         { *m = ListRCsRequest{} }
+func (m *ListRCsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListRCsRequest) ProtoMessage()    {}
+func (*ListRCsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rcstore_2aafd1c6eb6122ec, []int{14}
+}
+func (m *ListRCsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListRCsRequest.Unmarshal(m, b)
+}
+func (m *ListRCsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListRCsRequest.Marshal(b, m, deterministic)
+}
+func (dst *ListRCsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListRCsRequest.Merge(dst, src)
+}
+func (m *ListRCsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListRCsRequest.Size(m)
+}
+func (m *ListRCsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListRCsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListRCsRequest proto.InternalMessageInfo
+
+type ListRCsResponse struct {
+	Rcs                  []*RC    `protobuf:"bytes,1,rep,name=rcs,proto3" json:"rcs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListRCsResponse) Reset()         { *m = ListRCsResponse{} }
+func (m *ListRCsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListRCsResponse) ProtoMessage()    {}
+func (*ListRCsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rcstore_2aafd1c6eb6122ec, []int{15}
+}
+func (m *ListRCsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListRCsResponse.Unmarshal(m, b)
+}
+func (m *ListRCsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListRCsResponse.Marshal(b, m, deterministic)
+}
+func (dst *ListRCsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListRCsResponse.Merge(dst, src)
+}
+func (m *ListRCsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListRCsResponse.Size(m)
+}
+func (m *ListRCsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListRCsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListRCsResponse proto.InternalMessageInfo
+
+func (m *ListRCsResponse) GetRcs() []*RC {
+	if m != nil {
+		return m.Rcs
+	}
+	return nil
+}
+
+type WatchRCRequest struct {
+	RcId                 string   `protobuf:"bytes,1,opt,name=rc_id,json=rcId,proto3" json:"rc_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchRCRequest) Reset()         { *m = WatchRCRequest{} }
+func (m *WatchRCRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchRCRequest) ProtoMessage()    {}
+func (*WatchRCRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rcstore_2aafd1c6eb6122ec, []int{16}
+}
+func (m *WatchRCRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchRCRequest.Unmarshal(m, b)
+}
+func (m *WatchRCRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchRCRequest.Marshal(b, m, deterministic)
+}
+func (dst *WatchRCRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchRCRequest.Merge(dst, src)
+}
+func (m *WatchRCRequest) XXX_Size() int {
+	return xxx_messageInfo_WatchRCRequest.Size(m)
+}
+func (m *WatchRCRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchRCRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchRCRequest proto.InternalMessageInfo
+
+func (m *WatchRCRequest) GetRcId() string {
+	if m != nil {
+		return m.RcId
+	}
+	return ""
+}
+
+type WatchRCResponse struct {
+	Rc                   *RC      `protobuf:"bytes,1,opt,name=rc,proto3" json:"rc,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchRCResponse) Reset()         { *m = WatchRCResponse{} }
+func (m *WatchRCResponse) String() string { return proto.CompactTextString(m) }
+func (*WatchRCResponse) ProtoMessage()    {}
+func (*WatchRCResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rcstore_2aafd1c6eb6122ec, []int{17}
+}
+func (m *WatchRCResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchRCResponse.Unmarshal(m, b)
+}
+func (m *WatchRCResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchRCResponse.Marshal(b, m, deterministic)
+}
+func (dst *WatchRCResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchRCResponse.Merge(dst, src)
+}
+func (m *WatchRCResponse) XXX_Size() int {
+	return xxx_messageInfo_WatchRCResponse.Size(m)
+}
+func (m *WatchRCResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchRCResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchRCResponse proto.InternalMessageInfo
+
+func (m *WatchRCResponse) GetRc() *RC {
+	if m != nil {
+		return m.Rc
+	}
+	return nil
+}
+
+type SetDesiredReplicasRequest struct {
+	RcId                 string   `protobuf:"bytes,1,opt,name=rc_id,json=rcId,proto3" json:"rc_id,omitempty"`
+	Replicas             int64    `protobuf:"varint,2,opt,name=replicas,proto3" json:"replicas,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetDesiredReplicasRequest) Reset()         { *m = SetDesiredReplicasRequest{} }
+func (m *SetDesiredReplicasRequest) String() string { return proto.CompactTextString(m) }
+func (*SetDesiredReplicasRequest) ProtoMessage()    {}
+func (*SetDesiredReplicasRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rcstore_2aafd1c6eb6122ec, []int{18}
+}
+func (m *SetDesiredReplicasRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetDesiredReplicasRequest.Unmarshal(m, b)
+}
+func (m *SetDesiredReplicasRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetDesiredReplicasRequest.Marshal(b, m, deterministic)
+}
+func (dst *SetDesiredReplicasRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetDesiredReplicasRequest.Merge(dst, src)
+}
+func (m *SetDesiredReplicasRequest) XXX_Size() int {
+	return xxx_messageInfo_SetDesiredReplicasRequest.Size(m)
+}
+func (m *SetDesiredReplicasRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetDesiredReplicasRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetDesiredReplicasRequest proto.InternalMessageInfo
+
+func (m *SetDesiredReplicasRequest) GetRcId() string {
+	if m != nil {
+		return m.RcId
+	}
+	return ""
+}
+
+func (m *SetDesiredReplicasRequest) GetReplicas() int64 {
+	if m != nil {
+		return m.Replicas
+	}
+	return 0
+}
+
+type SetDesiredReplicasResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetDesiredReplicasResponse) Reset()         { *m = SetDesiredReplicasResponse{} }
+func (m *SetDesiredReplicasResponse) String() string { return proto.CompactTextString(m) }
+func (*SetDesiredReplicasResponse) ProtoMessage()    {}
+func (*SetDesiredReplicasResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rcstore_2aafd1c6eb6122ec, []int{19}
+}
+func (m *SetDesiredReplicasResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetDesiredReplicasResponse.Unmarshal(m, b)
+}
+func (m *SetDesiredReplicasResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetDesiredReplicasResponse.Marshal(b, m, deterministic)
+}
+func (dst *SetDesiredReplicasResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetDesiredReplicasResponse.Merge(dst, src)
+}
+func (m *SetDesiredReplicasResponse) XXX_Size() int {
+	return xxx_messageInfo_SetDesiredReplicasResponse.Size(m)
+}
+func (m *SetDesiredReplicasResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetDesiredReplicasResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetDesiredReplicasResponse proto.InternalMessageInfo
+
+type EnableRCRequest struct {
+	RcId                 string   `protobuf:"bytes,1,opt,name=rc_id,json=rcId,proto3" json:"rc_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EnableRCRequest) Reset()         { *m = EnableRCRequest{} }
+func (m *EnableRCRequest) String() string { return proto.CompactTextString(m) }
+func (*EnableRCRequest) ProtoMessage()    {}
+func (*EnableRCRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rcstore_2aafd1c6eb6122ec, []int{20}
+}
+func (m *EnableRCRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EnableRCRequest.Unmarshal(m, b)
+}
+func (m *EnableRCRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EnableRCRequest.Marshal(b, m, deterministic)
+}
+func (dst *EnableRCRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EnableRCRequest.Merge(dst, src)
+}
+func (m *EnableRCRequest) XXX_Size() int {
+	return xxx_messageInfo_EnableRCRequest.Size(m)
+}
+func (m *EnableRCRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_EnableRCRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EnableRCRequest proto.InternalMessageInfo
+
+func (m *EnableRCRequest) GetRcId() string {
+	if m != nil {
+		return m.RcId
+	}
+	return ""
+}
+
+type EnableRCResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EnableRCResponse) Reset()         { *m = EnableRCResponse{} }
+func (m *EnableRCResponse) String() string { return proto.CompactTextString(m) }
+func (*EnableRCResponse) ProtoMessage()    {}
+func (*EnableRCResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rcstore_2aafd1c6eb6122ec, []int{21}
+}
+func (m *EnableRCResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EnableRCResponse.Unmarshal(m, b)
+}
+func (m *EnableRCResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EnableRCResponse.Marshal(b, m, deterministic)
+}
+func (dst *EnableRCResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EnableRCResponse.Merge(dst, src)
+}
+func (m *EnableRCResponse) XXX_Size() int {
+	return xxx_messageInfo_EnableRCResponse.Size(m)
+}
+func (m *EnableRCResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_EnableRCResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EnableRCResponse proto.InternalMessageInfo
+
+type DisableRCRequest struct {
+	RcId                 string   `protobuf:"bytes,1,opt,name=rc_id,json=rcId,proto3" json:"rc_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DisableRCRequest) Reset()         { *m = DisableRCRequest{} }
+func (m *DisableRCRequest) String() string { return proto.CompactTextString(m) }
+func (*DisableRCRequest) ProtoMessage()    {}
+func (*DisableRCRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rcstore_2aafd1c6eb6122ec, []int{22}
+}
+func (m *DisableRCRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DisableRCRequest.Unmarshal(m, b)
+}
+func (m *DisableRCRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DisableRCRequest.Marshal(b, m, deterministic)
+}
+func (dst *DisableRCRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DisableRCRequest.Merge(dst, src)
+}
+func (m *DisableRCRequest) XXX_Size() int {
+	return xxx_messageInfo_DisableRCRequest.Size(m)
+}
+func (m *DisableRCRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DisableRCRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DisableRCRequest proto.InternalMessageInfo
+
+func (m *DisableRCRequest) GetRcId() string {
+	if m != nil {
+		return m.RcId
+	}
+	return ""
+}
+
+type DisableRCResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DisableRCResponse) Reset()         { *m = DisableRCResponse{} }
+func (m *DisableRCResponse) String() string { return proto.CompactTextString(m) }
+func (*DisableRCResponse) ProtoMessage()    {}
+func (*DisableRCResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rcstore_2aafd1c6eb6122ec, []int{23}
+}
+func (m *DisableRCResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DisableRCResponse.Unmarshal(m, b)
+}
+func (m *DisableRCResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DisableRCResponse.Marshal(b, m, deterministic)
+}
+func (dst *DisableRCResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DisableRCResponse.Merge(dst, src)
+}
+func (m *DisableRCResponse) XXX_Size() int {
+	return xxx_messageInfo_DisableRCResponse.Size(m)
+}
+func (m *DisableRCResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DisableRCResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DisableRCResponse proto.InternalMessageInfo
+
+type DeleteRCRequest struct {
+	RcId string `protobuf:"bytes,1,opt,name=rc_id,json=rcId,proto3" json:"rc_id,omitempty"`
+	// delete the RC even if it still has replicas
+	Force                bool     `protobuf:"varint,2,opt,name=force,proto3" json:"force,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteRCRequest) Reset()         { *m = DeleteRCRequest{} }
+func (m *DeleteRCRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteRCRequest) ProtoMessage()    {}
+func (*DeleteRCRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rcstore_2aafd1c6eb6122ec, []int{24}
+}
+func (m *DeleteRCRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteRCRequest.Unmarshal(m, b)
+}
+func (m *DeleteRCRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteRCRequest.Marshal(b, m, deterministic)
+}
+func (dst *DeleteRCRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteRCRequest.Merge(dst, src)
+}
+func (m *DeleteRCRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteRCRequest.Size(m)
+}
+func (m *DeleteRCRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteRCRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteRCRequest proto.InternalMessageInfo
+
+func (m *DeleteRCRequest) GetRcId() string {
+	if m != nil {
+		return m.RcId
+	}
+	return ""
+}
+
+func (m *DeleteRCRequest) GetForce() bool {
+	if m != nil {
+		return m.Force
+	}
+	return false
+}
+
+type DeleteRCResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteRCResponse) Reset()         { *m = DeleteRCResponse{} }
+func (m *DeleteRCResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteRCResponse) ProtoMessage()    {}
+func (*DeleteRCResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rcstore_2aafd1c6eb6122ec, []int{25}
+}
+func (m *DeleteRCResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteRCResponse.Unmarshal(m, b)
+}
+func (m *DeleteRCResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteRCResponse.Marshal(b, m, deterministic)
+}
+func (dst *DeleteRCResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteRCResponse.Merge(dst, src)
+}
+func (m *DeleteRCResponse) XXX_Size() int {
+	return xxx_messageInfo_DeleteRCResponse.Size(m)
+}
+func (m *DeleteRCResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteRCResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteRCResponse proto.InternalMessageInfo
+
+// models roll/fields.Update. Durations are expressed in nanoseconds (matches
+// time.Duration)
+type RollingUpdate struct {
+	OldRcId         string `protobuf:"bytes,1,opt,name=old_rc_id,json=oldRcId,proto3" json:"old_rc_id,omitempty"`
+	NewRcId         string `protobuf:"bytes,2,opt,name=new_rc_id,json=newRcId,proto3" json:"new_rc_id,omitempty"`
+	DesiredReplicas int64  `protobuf:"varint,3,opt,name=desired_replicas,json=desiredReplicas,proto3" json:"desired_replicas,omitempty"`
+	MinimumReplicas int64  `protobuf:"varint,4,opt,name=minimum_replicas,json=minimumReplicas,proto3" json:"minimum_replicas,omitempty"`
+	LeaveOld        bool   `protobuf:"varint,5,opt,name=leave_old,json=leaveOld,proto3" json:"leave_old,omitempty"`
+	RollDelay       int64  `protobuf:"varint,6,opt,name=roll_delay,json=rollDelay,proto3" json:"roll_delay,omitempty"`
+	CanaryReplicas  int64  `protobuf:"varint,7,opt,name=canary_replicas,json=canaryReplicas,proto3" json:"canary_replicas,omitempty"`
+	CanaryBake      int64  `protobuf:"varint,8,opt,name=canary_bake,json=canaryBake,proto3" json:"canary_bake,omitempty"`
+	// unset if the update has no failure policy
+	FailurePolicy *FailurePolicy `protobuf:"bytes,9,opt,name=failure_policy,json=failurePolicy,proto3" json:"failure_policy,omitempty"`
+	Paused        bool           `protobuf:"varint,10,opt,name=paused,proto3" json:"paused,omitempty"`
+	PauseReason   string         `protobuf:"bytes,11,opt,name=pause_reason,json=pauseReason,proto3" json:"pause_reason,omitempty"`
+	PausedBy      string         `protobuf:"bytes,12,opt,name=paused_by,json=pausedBy,proto3" json:"paused_by,omitempty"`
+	// unset if the update isn't sequenced by zone
+	ZoneSequence         *ZoneSequence `protobuf:"bytes,13,opt,name=zone_sequence,json=zoneSequence,proto3" json:"zone_sequence,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *RollingUpdate) Reset()         { *m = RollingUpdate{} }
+func (m *RollingUpdate) String() string { return proto.CompactTextString(m) }
+func (*RollingUpdate) ProtoMessage()    {}
+func (*RollingUpdate) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rcstore_2aafd1c6eb6122ec, []int{26}
+}
+func (m *RollingUpdate) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RollingUpdate.Unmarshal(m, b)
+}
+func (m *RollingUpdate) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RollingUpdate.Marshal(b, m, deterministic)
+}
+func (dst *RollingUpdate) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RollingUpdate.Merge(dst, src)
+}
+func (m *RollingUpdate) XXX_Size() int {
+	return xxx_messageInfo_RollingUpdate.Size(m)
+}
+func (m *RollingUpdate) XXX_DiscardUnknown() {
+	xxx_messageInfo_RollingUpdate.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RollingUpdate proto.InternalMessageInfo
+
+func (m *RollingUpdate) GetOldRcId() string {
+	if m != nil {
+		return m.OldRcId
+	}
+	return ""
+}
+
+func (m *RollingUpdate) GetNewRcId() string {
+	if m != nil {
+		return m.NewRcId
+	}
+	return ""
+}
+
+func (m *RollingUpdate) GetDesiredReplicas() int64 {
+	if m != nil {
+		return m.DesiredReplicas
+	}
+	return 0
+}
+
+func (m *RollingUpdate) GetMinimumReplicas() int64 {
+	if m != nil {
+		return m.MinimumReplicas
+	}
+	return 0
+}
+
+func (m *RollingUpdate) GetLeaveOld() bool {
+	if m != nil {
+		return m.LeaveOld
+	}
+	return false
+}
+
+func (m *RollingUpdate) GetRollDelay() int64 {
+	if m != nil {
+		return m.RollDelay
+	}
+	return 0
+}
+
+func (m *RollingUpdate) GetCanaryReplicas() int64 {
+	if m != nil {
+		return m.CanaryReplicas
+	}
+	return 0
+}
+
+func (m *RollingUpdate) GetCanaryBake() int64 {
+	if m != nil {
+		return m.CanaryBake
+	}
+	return 0
+}
+
+func (m *RollingUpdate) GetFailurePolicy() *FailurePolicy {
+	if m != nil {
+		return m.FailurePolicy
+	}
+	return nil
+}
+
+func (m *RollingUpdate) GetPaused() bool {
+	if m != nil {
+		return m.Paused
+	}
+	return false
+}
+
+func (m *RollingUpdate) GetPauseReason() string {
+	if m != nil {
+		return m.PauseReason
+	}
+	return ""
+}
+
+func (m *RollingUpdate) GetPausedBy() string {
+	if m != nil {
+		return m.PausedBy
+	}
+	return ""
+}
+
+func (m *RollingUpdate) GetZoneSequence() *ZoneSequence {
+	if m != nil {
+		return m.ZoneSequence
+	}
+	return nil
+}
+
+type FailurePolicy struct {
+	ProgressDeadline int64 `protobuf:"varint,1,opt,name=progress_deadline,json=progressDeadline,proto3" json:"progress_deadline,omitempty"`
+	// unset if there is no limit
+	MaxUnhealthy         *Limit   `protobuf:"bytes,2,opt,name=max_unhealthy,json=maxUnhealthy,proto3" json:"max_unhealthy,omitempty"`
+	MaxUnhealthyDuration int64    `protobuf:"varint,3,opt,name=max_unhealthy_duration,json=maxUnhealthyDuration,proto3" json:"max_unhealthy_duration,omitempty"`
+	Rollback             bool     `protobuf:"varint,4,opt,name=rollback,proto3" json:"rollback,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FailurePolicy) Reset()         { *m = FailurePolicy{} }
+func (m *FailurePolicy) String() string { return proto.CompactTextString(m) }
+func (*FailurePolicy) ProtoMessage()    {}
+func (*FailurePolicy) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rcstore_2aafd1c6eb6122ec, []int{27}
+}
+func (m *FailurePolicy) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FailurePolicy.Unmarshal(m, b)
+}
+func (m *FailurePolicy) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FailurePolicy.Marshal(b, m, deterministic)
+}
+func (dst *FailurePolicy) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FailurePolicy.Merge(dst, src)
+}
+func (m *FailurePolicy) XXX_Size() int {
+	return xxx_messageInfo_FailurePolicy.Size(m)
+}
+func (m *FailurePolicy) XXX_DiscardUnknown() {
+	xxx_messageInfo_FailurePolicy.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FailurePolicy proto.InternalMessageInfo
+
+func (m *FailurePolicy) GetProgressDeadline() int64 {
+	if m != nil {
+		return m.ProgressDeadline
+	}
+	return 0
+}
+
+func (m *FailurePolicy) GetMaxUnhealthy() *Limit {
+	if m != nil {
+		return m.MaxUnhealthy
+	}
+	return nil
+}
+
+func (m *FailurePolicy) GetMaxUnhealthyDuration() int64 {
+	if m != nil {
+		return m.MaxUnhealthyDuration
+	}
+	return 0
+}
+
+func (m *FailurePolicy) GetRollback() bool {
+	if m != nil {
+		return m.Rollback
+	}
+	return false
+}
+
+// distinguishes a limit of zero from no limit
+type Limit struct {
+	Value                int64    `protobuf:"varint,1,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Limit) Reset()         { *m = Limit{} }
+func (m *Limit) String() string { return proto.CompactTextString(m) }
+func (*Limit) ProtoMessage()    {}
+func (*Limit) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rcstore_2aafd1c6eb6122ec, []int{28}
+}
+func (m *Limit) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Limit.Unmarshal(m, b)
+}
+func (m *Limit) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Limit.Marshal(b, m, deterministic)
+}
+func (dst *Limit) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Limit.Merge(dst, src)
+}
+func (m *Limit) XXX_Size() int {
+	return xxx_messageInfo_Limit.Size(m)
+}
+func (m *Limit) XXX_DiscardUnknown() {
+	xxx_messageInfo_Limit.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Limit proto.InternalMessageInfo
+
+func (m *Limit) GetValue() int64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+type ZoneSequence struct {
+	Label                string   `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
+	Order                []string `protobuf:"bytes,2,rep,name=order,proto3" json:"order,omitempty"`
+	Pause                int64    `protobuf:"varint,3,opt,name=pause,proto3" json:"pause,omitempty"`
+	RequireHealthy       bool     `protobuf:"varint,4,opt,name=require_healthy,json=requireHealthy,proto3" json:"require_healthy,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ZoneSequence) Reset()         { *m = ZoneSequence{} }
+func (m *ZoneSequence) String() string { return proto.CompactTextString(m) }
+func (*ZoneSequence) ProtoMessage()    {}
+func (*ZoneSequence) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rcstore_2aafd1c6eb6122ec, []int{29}
+}
+func (m *ZoneSequence) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ZoneSequence.Unmarshal(m, b)
+}
+func (m *ZoneSequence) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ZoneSequence.Marshal(b, m, deterministic)
+}
+func (dst *ZoneSequence) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ZoneSequence.Merge(dst, src)
+}
+func (m *ZoneSequence) XXX_Size() int {
+	return xxx_messageInfo_ZoneSequence.Size(m)
+}
+func (m *ZoneSequence) XXX_DiscardUnknown() {
+	xxx_messageInfo_ZoneSequence.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ZoneSequence proto.InternalMessageInfo
+
+func (m *ZoneSequence) GetLabel() string {
+	if m != nil {
+		return m.Label
+	}
+	return ""
+}
+
+func (m *ZoneSequence) GetOrder() []string {
+	if m != nil {
+		return m.Order
+	}
+	return nil
+}
+
+func (m *ZoneSequence) GetPause() int64 {
+	if m != nil {
+		return m.Pause
+	}
+	return 0
+}
+
+func (m *ZoneSequence) GetRequireHealthy() bool {
+	if m != nil {
+		return m.RequireHealthy
+	}
+	return false
+}
+
+type ScheduleRollingUpdateRequest struct {
+	RollingUpdate        *RollingUpdate `protobuf:"bytes,1,opt,name=rolling_update,json=rollingUpdate,proto3" json:"rolling_update,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *ScheduleRollingUpdateRequest) Reset()         { *m = ScheduleRollingUpdateRequest{} }
+func (m *ScheduleRollingUpdateRequest) String() string { return proto.CompactTextString(m) }
+func (*ScheduleRollingUpdateRequest) ProtoMessage()    {}
+func (*ScheduleRollingUpdateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rcstore_2aafd1c6eb6122ec, []int{30}
+}
+func (m *ScheduleRollingUpdateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ScheduleRollingUpdateRequest.Unmarshal(m, b)
+}
+func (m *ScheduleRollingUpdateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ScheduleRollingUpdateRequest.Marshal(b, m, deterministic)
+}
+func (dst *ScheduleRollingUpdateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ScheduleRollingUpdateRequest.Merge(dst, src)
+}
+func (m *ScheduleRollingUpdateRequest) XXX_Size() int {
+	return xxx_messageInfo_ScheduleRollingUpdateRequest.Size(m)
+}
+func (m *ScheduleRollingUpdateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ScheduleRollingUpdateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ScheduleRollingUpdateRequest proto.InternalMessageInfo
+
+func (m *ScheduleRollingUpdateRequest) GetRollingUpdate() *RollingUpdate {
+	if m != nil {
+		return m.RollingUpdate
+	}
+	return nil
+}
+
+type ScheduleRollingUpdateResponse struct {
+	RollingUpdate        *RollingUpdate `protobuf:"bytes,1,opt,name=rolling_update,json=rollingUpdate,proto3" json:"rolling_update,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *ScheduleRollingUpdateResponse) Reset()         { *m = ScheduleRollingUpdateResponse{} }
+func (m *ScheduleRollingUpdateResponse) String() string { return proto.CompactTextString(m) }
+func (*ScheduleRollingUpdateResponse) ProtoMessage()    {}
+func (*ScheduleRollingUpdateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rcstore_2aafd1c6eb6122ec, []int{31}
+}
+func (m *ScheduleRollingUpdateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ScheduleRollingUpdateResponse.Unmarshal(m, b)
+}
+func (m *ScheduleRollingUpdateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ScheduleRollingUpdateResponse.Marshal(b, m, deterministic)
+}
+func (dst *ScheduleRollingUpdateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ScheduleRollingUpdateResponse.Merge(dst, src)
+}
+func (m *ScheduleRollingUpdateResponse) XXX_Size() int {
+	return xxx_messageInfo_ScheduleRollingUpdateResponse.Size(m)
+}
+func (m *ScheduleRollingUpdateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ScheduleRollingUpdateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ScheduleRollingUpdateResponse proto.InternalMessageInfo
+
+func (m *ScheduleRollingUpdateResponse) GetRollingUpdate() *RollingUpdate {
+	if m != nil {
+		return m.RollingUpdate
+	}
+	return nil
+}
+
+type ListRollingUpdatesRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListRollingUpdatesRequest) Reset()         { *m = ListRollingUpdatesRequest{} }
+func (m *ListRollingUpdatesRequest) String() string { return proto.CompactTextString(m) }
+func (*ListRollingUpdatesRequest) ProtoMessage()    {}
+func (*ListRollingUpdatesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rcstore_2aafd1c6eb6122ec, []int{32}
+}
+func (m *ListRollingUpdatesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListRollingUpdatesRequest.Unmarshal(m, b)
+}
+func (m *ListRollingUpdatesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListRollingUpdatesRequest.Marshal(b, m, deterministic)
+}
+func (dst *ListRollingUpdatesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListRollingUpdatesRequest.Merge(dst, src)
+}
+func (m *ListRollingUpdatesRequest) XXX_Size() int {
+	return xxx_messageInfo_ListRollingUpdatesRequest.Size(m)
+}
+func (m *ListRollingUpdatesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListRollingUpdatesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListRollingUpdatesRequest proto.InternalMessageInfo
+
+type ListRollingUpdatesResponse struct {
+	RollingUpdates       []*RollingUpdate `protobuf:"bytes,1,rep,name=rolling_updates,json=rollingUpdates,proto3" json:"rolling_updates,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *ListRollingUpdatesResponse) Reset()         { *m = ListRollingUpdatesResponse{} }
+func (m *ListRollingUpdatesResponse) String() string { return proto.CompactTextString(m) }
+func (*ListRollingUpdatesResponse) ProtoMessage()    {}
+func (*ListRollingUpdatesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rcstore_2aafd1c6eb6122ec, []int{33}
+}
+func (m *ListRollingUpdatesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListRollingUpdatesResponse.Unmarshal(m, b)
+}
+func (m *ListRollingUpdatesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListRollingUpdatesResponse.Marshal(b, m, deterministic)
+}
+func (dst *ListRollingUpdatesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListRollingUpdatesResponse.Merge(dst, src)
+}
+func (m *ListRollingUpdatesResponse) XXX_Size() int {
+	return xxx_messageInfo_ListRollingUpdatesResponse.Size(m)
+}
+func (m *ListRollingUpdatesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListRollingUpdatesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListRollingUpdatesResponse proto.InternalMessageInfo
+
+func (m *ListRollingUpdatesResponse) GetRollingUpdates() []*RollingUpdate {
+	if m != nil {
+		return m.RollingUpdates
+	}
+	return nil
+}
+
+type DeleteRollingUpdateRequest struct {
+	// a rolling update's ID is the ID of its new RC
+	RollId               string   `protobuf:"bytes,1,opt,name=roll_id,json=rollId,proto3" json:"roll_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteRollingUpdateRequest) Reset()         { *m = DeleteRollingUpdateRequest{} }
+func (m *DeleteRollingUpdateRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteRollingUpdateRequest) ProtoMessage()    {}
+func (*DeleteRollingUpdateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rcstore_2aafd1c6eb6122ec, []int{34}
+}
+func (m *DeleteRollingUpdateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteRollingUpdateRequest.Unmarshal(m, b)
+}
+func (m *DeleteRollingUpdateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteRollingUpdateRequest.Marshal(b, m, deterministic)
+}
+func (dst *DeleteRollingUpdateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteRollingUpdateRequest.Merge(dst, src)
+}
+func (m *DeleteRollingUpdateRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteRollingUpdateRequest.Size(m)
+}
+func (m *DeleteRollingUpdateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteRollingUpdateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteRollingUpdateRequest proto.InternalMessageInfo
+
+func (m *DeleteRollingUpdateRequest) GetRollId() string {
+	if m != nil {
+		return m.RollId
+	}
+	return ""
+}
+
+type DeleteRollingUpdateResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteRollingUpdateResponse) Reset()         { *m = DeleteRollingUpdateResponse{} }
+func (m *DeleteRollingUpdateResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteRollingUpdateResponse) ProtoMessage()    {}
+func (*DeleteRollingUpdateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rcstore_2aafd1c6eb6122ec, []int{35}
+}
+func (m *DeleteRollingUpdateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteRollingUpdateResponse.Unmarshal(m, b)
+}
+func (m *DeleteRollingUpdateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteRollingUpdateResponse.Marshal(b, m, deterministic)
+}
+func (dst *DeleteRollingUpdateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteRollingUpdateResponse.Merge(dst, src)
+}
+func (m *DeleteRollingUpdateResponse) XXX_Size() int {
+	return xxx_messageInfo_DeleteRollingUpdateResponse.Size(m)
+}
+func (m *DeleteRollingUpdateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteRollingUpdateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteRollingUpdateResponse proto.InternalMessageInfo
+
+type WatchRollingUpdatesRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchRollingUpdatesRequest) Reset()         { *m = WatchRollingUpdatesRequest{} }
+func (m *WatchRollingUpdatesRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchRollingUpdatesRequest) ProtoMessage()    {}
+func (*WatchRollingUpdatesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rcstore_2aafd1c6eb6122ec, []int{36}
+}
+func (m *WatchRollingUpdatesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchRollingUpdatesRequest.Unmarshal(m, b)
+}
+func (m *WatchRollingUpdatesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchRollingUpdatesRequest.Marshal(b, m, deterministic)
+}
+func (dst *WatchRollingUpdatesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchRollingUpdatesRequest.Merge(dst, src)
+}
+func (m *WatchRollingUpdatesRequest) XXX_Size() int {
+	return xxx_messageInfo_WatchRollingUpdatesRequest.Size(m)
+}
+func (m *WatchRollingUpdatesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchRollingUpdatesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchRollingUpdatesRequest proto.InternalMessageInfo
+
+type WatchRollingUpdatesResponse struct {
+	RollingUpdates       []*RollingUpdate `protobuf:"bytes,1,rep,name=rolling_updates,json=rollingUpdates,proto3" json:"rolling_updates,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *WatchRollingUpdatesResponse) Reset()         { *m = WatchRollingUpdatesResponse{} }
+func (m *WatchRollingUpdatesResponse) String() string { return proto.CompactTextString(m) }
+func (*WatchRollingUpdatesResponse) ProtoMessage()    {}
+func (*WatchRollingUpdatesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rcstore_2aafd1c6eb6122ec, []int{37}
+}
+func (m *WatchRollingUpdatesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchRollingUpdatesResponse.Unmarshal(m, b)
+}
+func (m *WatchRollingUpdatesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchRollingUpdatesResponse.Marshal(b, m, deterministic)
+}
+func (dst *WatchRollingUpdatesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchRollingUpdatesResponse.Merge(dst, src)
+}
+func (m *WatchRollingUpdatesResponse) XXX_Size() int {
+	return xxx_messageInfo_WatchRollingUpdatesResponse.Size(m)
+}
+func (m *WatchRollingUpdatesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchRollingUpdatesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchRollingUpdatesResponse proto.InternalMessageInfo
+
+func (m *WatchRollingUpdatesResponse) GetRollingUpdates() []*RollingUpdate {
+	if m != nil {
+		return m.RollingUpdates
+	}
+	return nil
+}
+
+type GetRollStatusRequest struct {
+	RollId               string   `protobuf:"bytes,1,opt,name=roll_id,json=rollId,proto3" json:"roll_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetRollStatusRequest) Reset()         { *m = GetRollStatusRequest{} }
+func (m *GetRollStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*GetRollStatusRequest) ProtoMessage()    {}
+func (*GetRollStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rcstore_2aafd1c6eb6122ec, []int{38}
+}
+func (m *GetRollStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetRollStatusRequest.Unmarshal(m, b)
+}
+func (m *GetRollStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetRollStatusRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetRollStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetRollStatusRequest.Merge(dst, src)
+}
+func (m *GetRollStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_GetRollStatusRequest.Size(m)
+}
+func (m *GetRollStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetRollStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetRollStatusRequest proto.InternalMessageInfo
+
+func (m *GetRollStatusRequest) GetRollId() string {
+	if m != nil {
+		return m.RollId
+	}
+	return ""
+}
+
+type WatchRollStatusRequest struct {
+	RollId               string   `protobuf:"bytes,1,opt,name=roll_id,json=rollId,proto3" json:"roll_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchRollStatusRequest) Reset()         { *m = WatchRollStatusRequest{} }
+func (m *WatchRollStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchRollStatusRequest) ProtoMessage()    {}
+func (*WatchRollStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rcstore_2aafd1c6eb6122ec, []int{39}
+}
+func (m *WatchRollStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchRollStatusRequest.Unmarshal(m, b)
+}
+func (m *WatchRollStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchRollStatusRequest.Marshal(b, m, deterministic)
+}
+func (dst *WatchRollStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchRollStatusRequest.Merge(dst, src)
+}
+func (m *WatchRollStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_WatchRollStatusRequest.Size(m)
+}
+func (m *WatchRollStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchRollStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchRollStatusRequest proto.InternalMessageInfo
+
+func (m *WatchRollStatusRequest) GetRollId() string {
+	if m != nil {
+		return m.RollId
+	}
+	return ""
+}
+
+// models rollstatus.Status. Times are expressed in nanoseconds since the unix
+// epoch, and are 0 when unset
+type RollStatus struct {
+	OldRc                *RollCounts `protobuf:"bytes,1,opt,name=old_rc,json=oldRc,proto3" json:"old_rc,omitempty"`
+	NewRc                *RollCounts `protobuf:"bytes,2,opt,name=new_rc,json=newRc,proto3" json:"new_rc,omitempty"`
+	Step                 string      `protobuf:"bytes,3,opt,name=step,proto3" json:"step,omitempty"`
+	BlockingReason       string      `protobuf:"bytes,4,opt,name=blocking_reason,json=blockingReason,proto3" json:"blocking_reason,omitempty"`
+	StartTime            int64       `protobuf:"varint,5,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	LastProgressTime     int64       `protobuf:"varint,6,opt,name=last_progress_time,json=lastProgressTime,proto3" json:"last_progress_time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *RollStatus) Reset()         { *m = RollStatus{} }
+func (m *RollStatus) String() string { return proto.CompactTextString(m) }
+func (*RollStatus) ProtoMessage()    {}
+func (*RollStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rcstore_2aafd1c6eb6122ec, []int{40}
+}
+func (m *RollStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RollStatus.Unmarshal(m, b)
+}
+func (m *RollStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RollStatus.Marshal(b, m, deterministic)
+}
+func (dst *RollStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RollStatus.Merge(dst, src)
+}
+func (m *RollStatus) XXX_Size() int {
+	return xxx_messageInfo_RollStatus.Size(m)
+}
+func (m *RollStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_RollStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RollStatus proto.InternalMessageInfo
+
+func (m *RollStatus) GetOldRc() *RollCounts {
+	if m != nil {
+		return m.OldRc
+	}
+	return nil
+}
+
+func (m *RollStatus) GetNewRc() *RollCounts {
+	if m != nil {
+		return m.NewRc
+	}
+	return nil
+}
+
+func (m *RollStatus) GetStep() string {
+	if m != nil {
+		return m.Step
+	}
+	return ""
+}
+
+func (m *RollStatus) GetBlockingReason() string {
+	if m != nil {
+		return m.BlockingReason
+	}
+	return ""
+}
+
+func (m *RollStatus) GetStartTime() int64 {
+	if m != nil {
+		return m.StartTime
+	}
+	return 0
+}
+
+func (m *RollStatus) GetLastProgressTime() int64 {
+	if m != nil {
+		return m.LastProgressTime
+	}
+	return 0
+}
+
+// models rollstatus.Counts
+type RollCounts struct {
+	Desired              int64    `protobuf:"varint,1,opt,name=desired,proto3" json:"desired,omitempty"`
+	Current              int64    `protobuf:"varint,2,opt,name=current,proto3" json:"current,omitempty"`
+	Real                 int64    `protobuf:"varint,3,opt,name=real,proto3" json:"real,omitempty"`
+	Healthy              int64    `protobuf:"varint,4,opt,name=healthy,proto3" json:"healthy,omitempty"`
+	Unhealthy            int64    `protobuf:"varint,5,opt,name=unhealthy,proto3" json:"unhealthy,omitempty"`
+	Unknown              int64    `protobuf:"varint,6,opt,name=unknown,proto3" json:"unknown,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RollCounts) Reset()         { *m = RollCounts{} }
+func (m *RollCounts) String() string { return proto.CompactTextString(m) }
+func (*RollCounts) ProtoMessage()    {}
+func (*RollCounts) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rcstore_2aafd1c6eb6122ec, []int{41}
+}
+func (m *RollCounts) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RollCounts.Unmarshal(m, b)
+}
+func (m *RollCounts) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RollCounts.Marshal(b, m, deterministic)
+}
+func (dst *RollCounts) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RollCounts.Merge(dst, src)
+}
+func (m *RollCounts) XXX_Size() int {
+	return xxx_messageInfo_RollCounts.Size(m)
+}
+func (m *RollCounts) XXX_DiscardUnknown() {
+	xxx_messageInfo_RollCounts.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RollCounts proto.InternalMessageInfo
+
+func (m *RollCounts) GetDesired() int64 {
+	if m != nil {
+		return m.Desired
+	}
+	return 0
+}
+
+func (m *RollCounts) GetCurrent() int64 {
+	if m != nil {
+		return m.Current
+	}
+	return 0
+}
+
+func (m *RollCounts) GetReal() int64 {
+	if m != nil {
+		return m.Real
+	}
+	return 0
+}
+
+func (m *RollCounts) GetHealthy() int64 {
+	if m != nil {
+		return m.Healthy
+	}
+	return 0
+}
+
+func (m *RollCounts) GetUnhealthy() int64 {
+	if m != nil {
+		return m.Unhealthy
+	}
+	return 0
+}
+
+func (m *RollCounts) GetUnknown() int64 {
+	if m != nil {
+		return m.Unknown
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*GetStatusRequest)(nil), "rcstore.GetStatusRequest")
+	proto.RegisterType((*WatchStatusRequest)(nil), "rcstore.WatchStatusRequest")
+	proto.RegisterType((*RCStatus)(nil), "rcstore.RCStatus")
+	proto.RegisterType((*Condition)(nil), "rcstore.Condition")
+	proto.RegisterType((*MissingArtifact)(nil), "rcstore.MissingArtifact")
+	proto.RegisterType((*RC)(nil), "rcstore.RC")
+	proto.RegisterMapType((map[string]string)(nil), "rcstore.RC.PodLabelsEntry")
+	proto.RegisterType((*SpreadConstraint)(nil), "rcstore.SpreadConstraint")
+	proto.RegisterType((*AutoscalePolicy)(nil), "rcstore.AutoscalePolicy")
+	proto.RegisterType((*MetricSource)(nil), "rcstore.MetricSource")
+	proto.RegisterType((*ZoneFilter)(nil), "rcstore.ZoneFilter")
+	proto.RegisterType((*CreateRCRequest)(nil), "rcstore.CreateRCRequest")
+	proto.RegisterMapType((map[string]string)(nil), "rcstore.CreateRCRequest.PodLabelsEntry")
+	proto.RegisterMapType((map[string]string)(nil), "rcstore.CreateRCRequest.RcLabelsEntry")
+	proto.RegisterType((*CreateRCResponse)(nil), "rcstore.CreateRCResponse")
+	proto.RegisterType((*GetRCRequest)(nil), "rcstore.GetRCRequest")
+	proto.RegisterType((*GetRCResponse)(nil), "rcstore.GetRCResponse")
+	proto.RegisterType((*ListRCsRequest)(nil), "rcstore.ListRCsRequest")
+	proto.RegisterType((*ListRCsResponse)(nil), "rcstore.ListRCsResponse")
+	proto.RegisterType((*WatchRCRequest)(nil), "rcstore.WatchRCRequest")
+	proto.RegisterType((*WatchRCResponse)(nil), "rcstore.WatchRCResponse")
+	proto.RegisterType((*SetDesiredReplicasRequest)(nil), "rcstore.SetDesiredReplicasRequest")
+	proto.RegisterType((*SetDesiredReplicasResponse)(nil), "rcstore.SetDesiredReplicasResponse")
+	proto.RegisterType((*EnableRCRequest)(nil), "rcstore.EnableRCRequest")
+	proto.RegisterType((*EnableRCResponse)(nil), "rcstore.EnableRCResponse")
+	proto.RegisterType((*DisableRCRequest)(nil), "rcstore.DisableRCRequest")
+	proto.RegisterType((*DisableRCResponse)(nil), "rcstore.DisableRCResponse")
+	proto.RegisterType((*DeleteRCRequest)(nil), "rcstore.DeleteRCRequest")
+	proto.RegisterType((*DeleteRCResponse)(nil), "rcstore.DeleteRCResponse")
+	proto.RegisterType((*RollingUpdate)(nil), "rcstore.RollingUpdate")
+	proto.RegisterType((*FailurePolicy)(nil), "rcstore.FailurePolicy")
+	proto.RegisterType((*Limit)(nil), "rcstore.Limit")
+	proto.RegisterType((*ZoneSequence)(nil), "rcstore.ZoneSequence")
+	proto.RegisterType((*ScheduleRollingUpdateRequest)(nil), "rcstore.ScheduleRollingUpdateRequest")
+	proto.RegisterType((*ScheduleRollingUpdateResponse)(nil), "rcstore.ScheduleRollingUpdateResponse")
+	proto.RegisterType((*ListRollingUpdatesRequest)(nil), "rcstore.ListRollingUpdatesRequest")
+	proto.RegisterType((*ListRollingUpdatesResponse)(nil), "rcstore.ListRollingUpdatesResponse")
+	proto.RegisterType((*DeleteRollingUpdateRequest)(nil), "rcstore.DeleteRollingUpdateRequest")
+	proto.RegisterType((*DeleteRollingUpdateResponse)(nil), "rcstore.DeleteRollingUpdateResponse")
+	proto.RegisterType((*WatchRollingUpdatesRequest)(nil), "rcstore.WatchRollingUpdatesRequest")
+	proto.RegisterType((*WatchRollingUpdatesResponse)(nil), "rcstore.WatchRollingUpdatesResponse")
+	proto.RegisterType((*GetRollStatusRequest)(nil), "rcstore.GetRollStatusRequest")
+	proto.RegisterType((*WatchRollStatusRequest)(nil), "rcstore.WatchRollStatusRequest")
+	proto.RegisterType((*RollStatus)(nil), "rcstore.RollStatus")
+	proto.RegisterType((*RollCounts)(nil), "rcstore.RollCounts")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// P2RCStoreClient is the client API for P2RCStore service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type P2RCStoreClient interface {
+	GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*RCStatus, error)
+	// Sends the status when the call is made and again each time it changes
+	WatchStatus(ctx context.Context, in *WatchStatusRequest, opts ...grpc.CallOption) (P2RCStore_WatchStatusClient, error)
+	CreateRC(ctx context.Context, in *CreateRCRequest, opts ...grpc.CallOption) (*CreateRCResponse, error)
+	GetRC(ctx context.Context, in *GetRCRequest, opts ...grpc.CallOption) (*GetRCResponse, error)
+	ListRCs(ctx context.Context, in *ListRCsRequest, opts ...grpc.CallOption) (*ListRCsResponse, error)
+	// Sends the RC when the call is made and again each time it changes
+	WatchRC(ctx context.Context, in *WatchRCRequest, opts ...grpc.CallOption) (P2RCStore_WatchRCClient, error)
+	SetDesiredReplicas(ctx context.Context, in *SetDesiredReplicasRequest, opts ...grpc.CallOption) (*SetDesiredReplicasResponse, error)
+	EnableRC(ctx context.Context, in *EnableRCRequest, opts ...grpc.CallOption) (*EnableRCResponse, error)
+	DisableRC(ctx context.Context, in *DisableRCRequest, opts ...grpc.CallOption) (*DisableRCResponse, error)
+	DeleteRC(ctx context.Context, in *DeleteRCRequest, opts ...grpc.CallOption) (*DeleteRCResponse, error)
+	ScheduleRollingUpdate(ctx context.Context, in *ScheduleRollingUpdateRequest, opts ...grpc.CallOption) (*ScheduleRollingUpdateResponse, error)
+	ListRollingUpdates(ctx context.Context, in *ListRollingUpdatesRequest, opts ...grpc.CallOption) (*ListRollingUpdatesResponse, error)
+	DeleteRollingUpdate(ctx context.Context, in *DeleteRollingUpdateRequest, opts ...grpc.CallOption) (*DeleteRollingUpdateResponse, error)
+	// Sends every rolling update when the call is made and again each time
+	// any of them changes
+	WatchRollingUpdates(ctx context.Context, in *WatchRollingUpdatesRequest, opts ...grpc.CallOption) (P2RCStore_WatchRollingUpdatesClient, error)
+	GetRollStatus(ctx context.Context, in *GetRollStatusRequest, opts ...grpc.CallOption) (*RollStatus, error)
+	// Sends the status when the call is made and again each time it changes
+	WatchRollStatus(ctx context.Context, in *WatchRollStatusRequest, opts ...grpc.CallOption) (P2RCStore_WatchRollStatusClient, error)
+}
+
+type p2RCStoreClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewP2RCStoreClient(cc *grpc.ClientConn) P2RCStoreClient {
+	return &p2RCStoreClient{cc}
+}
+
+func (c *p2RCStoreClient) GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*RCStatus, error) {
+	out := new(RCStatus)
+	err := c.cc.Invoke(ctx, "/rcstore.P2RCStore/GetStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *p2RCStoreClient) WatchStatus(ctx context.Context, in *WatchStatusRequest, opts ...grpc.CallOption) (P2RCStore_WatchStatusClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_P2RCStore_serviceDesc.Streams[0], "/rcstore.P2RCStore/WatchStatus", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &p2RCStoreWatchStatusClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type P2RCStore_WatchStatusClient interface {
+	Recv() (*RCStatus, error)
+	grpc.ClientStream
+}
+
+type p2RCStoreWatchStatusClient struct {
+	grpc.ClientStream
+}
+
+func (x *p2RCStoreWatchStatusClient) Recv() (*RCStatus, error) {
+	m := new(RCStatus)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *p2RCStoreClient) CreateRC(ctx context.Context, in *CreateRCRequest, opts ...grpc.CallOption) (*CreateRCResponse, error) {
+	out := new(CreateRCResponse)
+	err := c.cc.Invoke(ctx, "/rcstore.P2RCStore/CreateRC", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *p2RCStoreClient) GetRC(ctx context.Context, in *GetRCRequest, opts ...grpc.CallOption) (*GetRCResponse, error) {
+	out := new(GetRCResponse)
+	err := c.cc.Invoke(ctx, "/rcstore.P2RCStore/GetRC", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *p2RCStoreClient) ListRCs(ctx context.Context, in *ListRCsRequest, opts ...grpc.CallOption) (*ListRCsResponse, error) {
+	out := new(ListRCsResponse)
+	err := c.cc.Invoke(ctx, "/rcstore.P2RCStore/ListRCs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *p2RCStoreClient) WatchRC(ctx context.Context, in *WatchRCRequest, opts ...grpc.CallOption) (P2RCStore_WatchRCClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_P2RCStore_serviceDesc.Streams[1], "/rcstore.P2RCStore/WatchRC", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &p2RCStoreWatchRCClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type P2RCStore_WatchRCClient interface {
+	Recv() (*WatchRCResponse, error)
+	grpc.ClientStream
+}
+
+type p2RCStoreWatchRCClient struct {
+	grpc.ClientStream
+}
+
+func (x *p2RCStoreWatchRCClient) Recv() (*WatchRCResponse, error) {
+	m := new(WatchRCResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *p2RCStoreClient) SetDesiredReplicas(ctx context.Context, in *SetDesiredReplicasRequest, opts ...grpc.CallOption) (*SetDesiredReplicasResponse, error) {
+	out := new(SetDesiredReplicasResponse)
+	err := c.cc.Invoke(ctx, "/rcstore.P2RCStore/SetDesiredReplicas", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *p2RCStoreClient) EnableRC(ctx context.Context, in *EnableRCRequest, opts ...grpc.CallOption) (*EnableRCResponse, error) {
+	out := new(EnableRCResponse)
+	err := c.cc.Invoke(ctx, "/rcstore.P2RCStore/EnableRC", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *p2RCStoreClient) DisableRC(ctx context.Context, in *DisableRCRequest, opts ...grpc.CallOption) (*DisableRCResponse, error) {
+	out := new(DisableRCResponse)
+	err := c.cc.Invoke(ctx, "/rcstore.P2RCStore/DisableRC", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *p2RCStoreClient) DeleteRC(ctx context.Context, in *DeleteRCRequest, opts ...grpc.CallOption) (*DeleteRCResponse, error) {
+	out := new(DeleteRCResponse)
+	err := c.cc.Invoke(ctx, "/rcstore.P2RCStore/DeleteRC", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *p2RCStoreClient) ScheduleRollingUpdate(ctx context.Context, in *ScheduleRollingUpdateRequest, opts ...grpc.CallOption) (*ScheduleRollingUpdateResponse, error) {
+	out := new(ScheduleRollingUpdateResponse)
+	err := c.cc.Invoke(ctx, "/rcstore.P2RCStore/ScheduleRollingUpdate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *p2RCStoreClient) ListRollingUpdates(ctx context.Context, in *ListRollingUpdatesRequest, opts ...grpc.CallOption) (*ListRollingUpdatesResponse, error) {
+	out := new(ListRollingUpdatesResponse)
+	err := c.cc.Invoke(ctx, "/rcstore.P2RCStore/ListRollingUpdates", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *p2RCStoreClient) DeleteRollingUpdate(ctx context.Context, in *DeleteRollingUpdateRequest, opts ...grpc.CallOption) (*DeleteRollingUpdateResponse, error) {
+	out := new(DeleteRollingUpdateResponse)
+	err := c.cc.Invoke(ctx, "/rcstore.P2RCStore/DeleteRollingUpdate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *p2RCStoreClient) WatchRollingUpdates(ctx context.Context, in *WatchRollingUpdatesRequest, opts ...grpc.CallOption) (P2RCStore_WatchRollingUpdatesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_P2RCStore_serviceDesc.Streams[2], "/rcstore.P2RCStore/WatchRollingUpdates", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &p2RCStoreWatchRollingUpdatesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type P2RCStore_WatchRollingUpdatesClient interface {
+	Recv() (*WatchRollingUpdatesResponse, error)
+	grpc.ClientStream
+}
+
+type p2RCStoreWatchRollingUpdatesClient struct {
+	grpc.ClientStream
+}
+
+func (x *p2RCStoreWatchRollingUpdatesClient) Recv() (*WatchRollingUpdatesResponse, error) {
+	m := new(WatchRollingUpdatesResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *p2RCStoreClient) GetRollStatus(ctx context.Context, in *GetRollStatusRequest, opts ...grpc.CallOption) (*RollStatus, error) {
+	out := new(RollStatus)
+	err := c.cc.Invoke(ctx, "/rcstore.P2RCStore/GetRollStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *p2RCStoreClient) WatchRollStatus(ctx context.Context, in *WatchRollStatusRequest, opts ...grpc.CallOption) (P2RCStore_WatchRollStatusClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_P2RCStore_serviceDesc.Streams[3], "/rcstore.P2RCStore/WatchRollStatus", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &p2RCStoreWatchRollStatusClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type P2RCStore_WatchRollStatusClient interface {
+	Recv() (*RollStatus, error)
+	grpc.ClientStream
+}
+
+type p2RCStoreWatchRollStatusClient struct {
+	grpc.ClientStream
+}
+
+func (x *p2RCStoreWatchRollStatusClient) Recv() (*RollStatus, error) {
+	m := new(RollStatus)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// P2RCStoreServer is the server API for P2RCStore service.
+type P2RCStoreServer interface {
+	GetStatus(context.Context, *GetStatusRequest) (*RCStatus, error)
+	// Sends the status when the call is made and again each time it changes
+	WatchStatus(*WatchStatusRequest, P2RCStore_WatchStatusServer) error
+	CreateRC(context.Context, *CreateRCRequest) (*CreateRCResponse, error)
+	GetRC(context.Context, *GetRCRequest) (*GetRCResponse, error)
+	ListRCs(context.Context, *ListRCsRequest) (*ListRCsResponse, error)
+	// Sends the RC when the call is made and again each time it changes
+	WatchRC(*WatchRCRequest, P2RCStore_WatchRCServer) error
+	SetDesiredReplicas(context.Context, *SetDesiredReplicasRequest) (*SetDesiredReplicasResponse, error)
+	EnableRC(context.Context, *EnableRCRequest) (*EnableRCResponse, error)
+	DisableRC(context.Context, *DisableRCRequest) (*DisableRCResponse, error)
+	DeleteRC(context.Context, *DeleteRCRequest) (*DeleteRCResponse, error)
+	ScheduleRollingUpdate(context.Context, *ScheduleRollingUpdateRequest) (*ScheduleRollingUpdateResponse, error)
+	ListRollingUpdates(context.Context, *ListRollingUpdatesRequest) (*ListRollingUpdatesResponse, error)
+	DeleteRollingUpdate(context.Context, *DeleteRollingUpdateRequest) (*DeleteRollingUpdateResponse, error)
+	// Sends every rolling update when the call is made and again each time
+	// any of them changes
+	WatchRollingUpdates(*WatchRollingUpdatesRequest, P2RCStore_WatchRollingUpdatesServer) error
+	GetRollStatus(context.Context, *GetRollStatusRequest) (*RollStatus, error)
+	// Sends the status when the call is made and again each time it changes
+	WatchRollStatus(*WatchRollStatusRequest, P2RCStore_WatchRollStatusServer) error
+}
+
+func RegisterP2RCStoreServer(s *grpc.Server, srv P2RCStoreServer) {
+	s.RegisterService(&_P2RCStore_serviceDesc, srv)
+}
+
+func _P2RCStore_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(P2RCStoreServer).GetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rcstore.P2RCStore/GetStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(P2RCStoreServer).GetStatus(ctx, req.(*GetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _P2RCStore_WatchStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchStatusRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(P2RCStoreServer).WatchStatus(m, &p2RCStoreWatchStatusServer{stream})
+}
+
+type P2RCStore_WatchStatusServer interface {
+	Send(*RCStatus) error
+	grpc.ServerStream
+}
+
+type p2RCStoreWatchStatusServer struct {
+	grpc.ServerStream
+}
+
+func (x *p2RCStoreWatchStatusServer) Send(m *RCStatus) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _P2RCStore_CreateRC_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateRCRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(P2RCStoreServer).CreateRC(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rcstore.P2RCStore/CreateRC",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(P2RCStoreServer).CreateRC(ctx, req.(*CreateRCRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _P2RCStore_GetRC_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRCRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(P2RCStoreServer).GetRC(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rcstore.P2RCStore/GetRC",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(P2RCStoreServer).GetRC(ctx, req.(*GetRCRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _P2RCStore_ListRCs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRCsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(P2RCStoreServer).ListRCs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rcstore.P2RCStore/ListRCs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(P2RCStoreServer).ListRCs(ctx, req.(*ListRCsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _P2RCStore_WatchRC_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRCRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(P2RCStoreServer).WatchRC(m, &p2RCStoreWatchRCServer{stream})
+}
+
+type P2RCStore_WatchRCServer interface {
+	Send(*WatchRCResponse) error
+	grpc.ServerStream
+}
+
+type p2RCStoreWatchRCServer struct {
+	grpc.ServerStream
+}
+
+func (x *p2RCStoreWatchRCServer) Send(m *WatchRCResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _P2RCStore_SetDesiredReplicas_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetDesiredReplicasRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(P2RCStoreServer).SetDesiredReplicas(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rcstore.P2RCStore/SetDesiredReplicas",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(P2RCStoreServer).SetDesiredReplicas(ctx, req.(*SetDesiredReplicasRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _P2RCStore_EnableRC_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnableRCRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(P2RCStoreServer).EnableRC(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rcstore.P2RCStore/EnableRC",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(P2RCStoreServer).EnableRC(ctx, req.(*EnableRCRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _P2RCStore_DisableRC_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisableRCRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(P2RCStoreServer).DisableRC(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rcstore.P2RCStore/DisableRC",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(P2RCStoreServer).DisableRC(ctx, req.(*DisableRCRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _P2RCStore_DeleteRC_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRCRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(P2RCStoreServer).DeleteRC(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rcstore.P2RCStore/DeleteRC",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(P2RCStoreServer).DeleteRC(ctx, req.(*DeleteRCRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _P2RCStore_ScheduleRollingUpdate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScheduleRollingUpdateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(P2RCStoreServer).ScheduleRollingUpdate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rcstore.P2RCStore/ScheduleRollingUpdate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(P2RCStoreServer).ScheduleRollingUpdate(ctx, req.(*ScheduleRollingUpdateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _P2RCStore_ListRollingUpdates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRollingUpdatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(P2RCStoreServer).ListRollingUpdates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rcstore.P2RCStore/ListRollingUpdates",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(P2RCStoreServer).ListRollingUpdates(ctx, req.(*ListRollingUpdatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _P2RCStore_DeleteRollingUpdate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRollingUpdateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(P2RCStoreServer).DeleteRollingUpdate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rcstore.P2RCStore/DeleteRollingUpdate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(P2RCStoreServer).DeleteRollingUpdate(ctx, req.(*DeleteRollingUpdateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _P2RCStore_WatchRollingUpdates_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRollingUpdatesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(P2RCStoreServer).WatchRollingUpdates(m, &p2RCStoreWatchRollingUpdatesServer{stream})
+}
+
+type P2RCStore_WatchRollingUpdatesServer interface {
+	Send(*WatchRollingUpdatesResponse) error
+	grpc.ServerStream
+}
+
+type p2RCStoreWatchRollingUpdatesServer struct {
+	grpc.ServerStream
+}
+
+func (x *p2RCStoreWatchRollingUpdatesServer) Send(m *WatchRollingUpdatesResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _P2RCStore_GetRollStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRollStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(P2RCStoreServer).GetRollStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rcstore.P2RCStore/GetRollStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(P2RCStoreServer).GetRollStatus(ctx, req.(*GetRollStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _P2RCStore_WatchRollStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRollStatusRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(P2RCStoreServer).WatchRollStatus(m, &p2RCStoreWatchRollStatusServer{stream})
+}
+
+type P2RCStore_WatchRollStatusServer interface {
+	Send(*RollStatus) error
+	grpc.ServerStream
+}
+
+type p2RCStoreWatchRollStatusServer struct {
+	grpc.ServerStream
+}
+
+func (x *p2RCStoreWatchRollStatusServer) Send(m *RollStatus) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _P2RCStore_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "rcstore.P2RCStore",
+	HandlerType: (*P2RCStoreServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetStatus",
+			Handler:    _P2RCStore_GetStatus_Handler,
+		},
+		{
+			MethodName: "CreateRC",
+			Handler:    _P2RCStore_CreateRC_Handler,
+		},
+		{
+			MethodName: "GetRC",
+			Handler:    _P2RCStore_GetRC_Handler,
+		},
+		{
+			MethodName: "ListRCs",
+			Handler:    _P2RCStore_ListRCs_Handler,
+		},
+		{
+			MethodName: "SetDesiredReplicas",
+			Handler:    _P2RCStore_SetDesiredReplicas_Handler,
+		},
+		{
+			MethodName: "EnableRC",
+			Handler:    _P2RCStore_EnableRC_Handler,
+		},
+		{
+			MethodName: "DisableRC",
+			Handler:    _P2RCStore_DisableRC_Handler,
+		},
+		{
+			MethodName: "DeleteRC",
+			Handler:    _P2RCStore_DeleteRC_Handler,
+		},
+		{
+			MethodName: "ScheduleRollingUpdate",
+			Handler:    _P2RCStore_ScheduleRollingUpdate_Handler,
+		},
+		{
+			MethodName: "ListRollingUpdates",
+			Handler:    _P2RCStore_ListRollingUpdates_Handler,
+		},
+		{
+			MethodName: "DeleteRollingUpdate",
+			Handler:    _P2RCStore_DeleteRollingUpdate_Handler,
+		},
+		{
+			MethodName: "GetRollStatus",
+			Handler:    _P2RCStore_GetRollStatus_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchStatus",
+			Handler:       _P2RCStore_WatchStatus_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchRC",
+			Handler:       _P2RCStore_WatchRC_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchRollingUpdates",
+			Handler:       _P2RCStore_WatchRollingUpdates_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchRollStatus",
+			Handler:       _P2RCStore_WatchRollStatus_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "rcstore.proto",
+}
+
+func init() { proto.RegisterFile("rcstore.proto", fileDescriptor_rcstore_2aafd1c6eb6122ec) }
+
+var fileDescriptor_rcstore_2aafd1c6eb6122ec = []byte{
+	// 2097 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x58, 0x5b, 0x6f, 0x1b, 0xc7,
+	0xf5, 0x07, 0x49, 0x89, 0xe4, 0x1e, 0x8a, 0x17, 0x8d, 0x6c, 0x65, 0xb5, 0xb2, 0x10, 0xff, 0xd7,
+	0x89, 0x2f, 0xff, 0x26, 0xb6, 0xab, 0xb4, 0x41, 0xe3, 0x3a, 0x31, 0x62, 0xca, 0x8e, 0x8d, 0xba,
+	0xa9, 0x30, 0x4a, 0x50, 0xa0, 0xa8, 0xb3, 0x1d, 0xed, 0x8e, 0xa4, 0x85, 0x86, 0xbb, 0xcc, 0xcc,
+	0xac, 0x65, 0xe6, 0xb5, 0xdf, 0xa5, 0x1f, 0xa2, 0x4f, 0x2d, 0xda, 0xcf, 0xd3, 0x97, 0x02, 0x7d,
+	0x2e, 0xe6, 0xb2, 0x37, 0x8a, 0xa4, 0xdc, 0xa6, 0x6f, 0x7b, 0xce, 0xf9, 0x9d, 0x33, 0x67, 0x66,
+	0xcf, 0x6d, 0x06, 0xfa, 0x3c, 0x14, 0x32, 0xe5, 0xf4, 0xfe, 0x94, 0xa7, 0x32, 0x45, 0x1d, 0x4b,
+	0xfa, 0x77, 0x60, 0xf4, 0x15, 0x95, 0x47, 0x92, 0xc8, 0x4c, 0x60, 0xfa, 0x7d, 0x46, 0x85, 0x44,
+	0x5b, 0xb0, 0xce, 0xc3, 0x20, 0x8e, 0xdc, 0xc6, 0xcd, 0xc6, 0x5d, 0x07, 0xaf, 0xf1, 0xf0, 0x65,
+	0xe4, 0xdf, 0x03, 0xf4, 0x5b, 0x22, 0xc3, 0xb3, 0x77, 0x80, 0xfe, 0x75, 0x0d, 0xba, 0x78, 0x6c,
+	0x80, 0xe8, 0x1e, 0x8c, 0x38, 0x9d, 0xb2, 0x38, 0x24, 0x22, 0x88, 0xa8, 0x88, 0x39, 0x35, 0xe0,
+	0x16, 0x1e, 0xe6, 0xfc, 0x03, 0xc3, 0x46, 0x1f, 0x03, 0x2a, 0xa0, 0x22, 0x3c, 0xa3, 0x51, 0xc6,
+	0x68, 0xe4, 0x36, 0x35, 0x78, 0x33, 0x97, 0x1c, 0xe5, 0x82, 0x9a, 0xe5, 0x30, 0xe3, 0x9c, 0x26,
+	0xd2, 0x6d, 0xd5, 0x2d, 0x8f, 0x0d, 0xbb, 0x06, 0x3d, 0xa3, 0x84, 0xc9, 0xb3, 0x99, 0xbb, 0x56,
+	0x87, 0xbe, 0x30, 0xec, 0x9a, 0x13, 0x59, 0x92, 0x83, 0xd7, 0xeb, 0x4e, 0x7c, 0x9b, 0x0b, 0x94,
+	0xe5, 0x38, 0xa1, 0x2c, 0x3e, 0x8d, 0x8f, 0x19, 0x0d, 0x92, 0x34, 0xa2, 0xc2, 0x6d, 0xdf, 0x6c,
+	0xdd, 0x75, 0xf0, 0xb0, 0xe4, 0x7f, 0xad, 0xd8, 0x68, 0x1f, 0x20, 0x4c, 0x93, 0x28, 0x96, 0x71,
+	0x9a, 0x08, 0xb7, 0x73, 0xb3, 0x75, 0xb7, 0xb7, 0x8f, 0xee, 0xe7, 0xff, 0x65, 0x9c, 0x8b, 0x70,
+	0x05, 0x85, 0xf6, 0x00, 0x18, 0x11, 0x32, 0xa0, 0x9c, 0xa7, 0xdc, 0xed, 0xea, 0x43, 0x76, 0x14,
+	0xe7, 0x99, 0x62, 0xa0, 0xdb, 0x30, 0x2c, 0xc5, 0x81, 0x8c, 0x27, 0xd4, 0x75, 0xb4, 0xa7, 0xfd,
+	0x02, 0xf3, 0x4d, 0x3c, 0xa1, 0xe8, 0x2e, 0x8c, 0x34, 0x2e, 0x9b, 0x46, 0x44, 0x52, 0x03, 0x04,
+	0x0d, 0x1c, 0x28, 0xfe, 0xb7, 0x9a, 0xad, 0x91, 0xcf, 0x60, 0x73, 0x12, 0x0b, 0x11, 0x27, 0xa7,
+	0x01, 0xe1, 0x32, 0x3e, 0x21, 0xa1, 0x14, 0x6e, 0x4f, 0xfb, 0xea, 0x16, 0xbe, 0xfe, 0xda, 0x20,
+	0xbe, 0xb4, 0x00, 0x3c, 0x9a, 0xd4, 0x19, 0x02, 0x3d, 0x81, 0x1b, 0x97, 0xcc, 0x04, 0xe1, 0x19,
+	0x0d, 0xcf, 0xcd, 0xe2, 0x1b, 0x7a, 0xf1, 0x9d, 0x79, 0xbd, 0xb1, 0x42, 0x28, 0x3f, 0xfc, 0x3f,
+	0x36, 0xc0, 0x29, 0x8e, 0x04, 0x21, 0x58, 0x93, 0xb3, 0x29, 0xcd, 0xa3, 0x4c, 0x7d, 0xa3, 0x6d,
+	0x68, 0x0b, 0x1d, 0x62, 0x3a, 0x42, 0xba, 0xd8, 0x52, 0xc8, 0x85, 0xce, 0x84, 0x0a, 0x41, 0x4e,
+	0xa9, 0x8e, 0x06, 0x07, 0xe7, 0x24, 0x7a, 0x08, 0xd7, 0xf4, 0x29, 0x48, 0x4e, 0x12, 0xa1, 0x0d,
+	0x1b, 0x67, 0x4c, 0x24, 0x20, 0x25, 0xfb, 0xa6, 0x10, 0x69, 0x2f, 0x5e, 0xc0, 0x70, 0x6e, 0xaf,
+	0xe8, 0x16, 0xf4, 0x19, 0xc9, 0x92, 0xf0, 0x8c, 0xa8, 0x1f, 0x5e, 0x44, 0xfe, 0x46, 0xc9, 0x7c,
+	0x19, 0xa1, 0x11, 0xb4, 0x32, 0xce, 0xb4, 0x63, 0x0e, 0x56, 0x9f, 0xfe, 0xdf, 0xd7, 0xa0, 0x89,
+	0xc7, 0x68, 0x00, 0xcd, 0x42, 0xa5, 0x19, 0x47, 0xc8, 0x83, 0xee, 0x84, 0x24, 0xf1, 0x09, 0x15,
+	0xd2, 0xa2, 0x0b, 0x5a, 0xad, 0xa4, 0xe2, 0x29, 0x10, 0x94, 0xd1, 0x50, 0xa6, 0xdc, 0x6e, 0x67,
+	0x43, 0x31, 0x8f, 0x2c, 0x0f, 0x7d, 0x06, 0x30, 0x4d, 0xa3, 0x80, 0x91, 0x63, 0xca, 0x84, 0xbb,
+	0xa6, 0x7f, 0x94, 0x57, 0xfc, 0x28, 0x3c, 0xbe, 0x7f, 0x98, 0x46, 0xaf, 0xb4, 0xf0, 0x59, 0x22,
+	0xf9, 0x0c, 0x3b, 0xd3, 0x9c, 0x5e, 0x98, 0x99, 0xeb, 0x8b, 0x33, 0xd3, 0x83, 0x6e, 0x14, 0x0b,
+	0xb5, 0xb9, 0xc8, 0x6d, 0xeb, 0xd3, 0x2e, 0x68, 0xf4, 0x00, 0xb6, 0x08, 0x63, 0x69, 0x48, 0xf4,
+	0x81, 0x0a, 0xc9, 0x89, 0xa4, 0xa7, 0x33, 0xb7, 0xa3, 0x9d, 0x45, 0xa5, 0xe8, 0xc8, 0x4a, 0xd0,
+	0x0b, 0x40, 0x62, 0xca, 0x29, 0x89, 0x82, 0x30, 0x4d, 0x14, 0x3e, 0x4e, 0xa4, 0x70, 0xbb, 0xda,
+	0xf5, 0x9d, 0xc2, 0xf5, 0x23, 0x0d, 0x19, 0x17, 0x08, 0xbc, 0x29, 0xe6, 0x38, 0x02, 0x7d, 0x0a,
+	0x0e, 0xc9, 0x64, 0x2a, 0x42, 0xc2, 0x4c, 0xe0, 0x57, 0x83, 0xf4, 0xcb, 0x5c, 0x72, 0x98, 0xb2,
+	0x38, 0x9c, 0xe1, 0x12, 0x8a, 0x1e, 0xc1, 0x20, 0xaf, 0x2f, 0xc1, 0x0f, 0x69, 0x42, 0x85, 0x4e,
+	0x86, 0xde, 0xfe, 0x56, 0xa1, 0xfc, 0xbb, 0x34, 0xa1, 0xcf, 0x63, 0x26, 0x29, 0xc7, 0xfd, 0x1c,
+	0xaa, 0x78, 0x02, 0x7d, 0x01, 0xa3, 0x2c, 0x99, 0xd3, 0xee, 0x2d, 0xd7, 0x1e, 0x96, 0x60, 0xad,
+	0xef, 0x3d, 0x86, 0x41, 0xfd, 0x97, 0xa8, 0x60, 0x39, 0xa7, 0x33, 0x1b, 0x14, 0xea, 0x13, 0x5d,
+	0x83, 0xf5, 0x37, 0x84, 0x65, 0xd4, 0x86, 0x84, 0x21, 0x1e, 0x35, 0x7f, 0xd1, 0xf0, 0x0f, 0x61,
+	0x34, 0x7f, 0x30, 0xe8, 0xff, 0x60, 0x43, 0xa6, 0xd3, 0x94, 0xa5, 0xa7, 0xb3, 0xa0, 0x34, 0xd4,
+	0xcb, 0x79, 0xbf, 0xa2, 0x33, 0xb4, 0xa3, 0xc2, 0xec, 0x6d, 0x20, 0xce, 0xe9, 0x85, 0xad, 0xa7,
+	0x9d, 0x09, 0x79, 0x7b, 0x74, 0x4e, 0x2f, 0xfc, 0x3f, 0x37, 0x60, 0x38, 0x77, 0x54, 0xca, 0xe2,
+	0x24, 0x4e, 0x82, 0x3c, 0x0a, 0x6c, 0xbd, 0xee, 0x4d, 0xe2, 0x04, 0x5b, 0x96, 0x86, 0x90, 0xb7,
+	0x25, 0xa4, 0x69, 0x21, 0xe4, 0x6d, 0x01, 0xd9, 0x86, 0xb6, 0x24, 0xfc, 0x94, 0x9a, 0xaa, 0xdc,
+	0xc0, 0x96, 0x52, 0xc1, 0x14, 0xa6, 0x29, 0x8b, 0xd2, 0x8b, 0xc4, 0xa6, 0x5e, 0x41, 0xa3, 0x8f,
+	0xa1, 0x3d, 0xa1, 0x92, 0xc7, 0xa1, 0x8e, 0xc4, 0xde, 0xfe, 0xf5, 0xb2, 0xe6, 0x68, 0xf6, 0x51,
+	0x9a, 0xf1, 0x90, 0x62, 0x0b, 0xf2, 0x7f, 0x0f, 0x1b, 0x55, 0xfe, 0xc2, 0x3a, 0x71, 0x29, 0x17,
+	0x15, 0x6a, 0x4a, 0xe4, 0x99, 0xcd, 0x27, 0xfd, 0xad, 0x79, 0x29, 0x97, 0xd6, 0x21, 0xfd, 0xed,
+	0x3f, 0x02, 0x28, 0xff, 0xa4, 0xfa, 0x29, 0x3a, 0xcb, 0xac, 0x71, 0x43, 0xa8, 0x4d, 0xea, 0xbf,
+	0xa3, 0x4e, 0x40, 0x55, 0x7d, 0x4b, 0xf9, 0xff, 0x6c, 0xc1, 0x70, 0xcc, 0x29, 0x91, 0x14, 0x8f,
+	0xf3, 0x66, 0x59, 0x4d, 0xf6, 0xc6, 0x55, 0xc9, 0xde, 0x5c, 0x90, 0xec, 0x3f, 0x81, 0x4d, 0xf2,
+	0x86, 0xc4, 0x8c, 0x1c, 0xc7, 0x2c, 0x96, 0x33, 0x1d, 0x7d, 0x76, 0x17, 0xa3, 0xaa, 0x40, 0x79,
+	0xad, 0xfe, 0x50, 0xc8, 0x32, 0x21, 0x29, 0x0f, 0x12, 0x62, 0xab, 0x9c, 0x83, 0x7b, 0x96, 0xf7,
+	0x35, 0x99, 0x50, 0xf4, 0xbc, 0x56, 0x3c, 0xd6, 0x75, 0x06, 0xde, 0x29, 0x3b, 0x52, 0xdd, 0xfd,
+	0x15, 0x95, 0x64, 0x0c, 0x0e, 0x0f, 0x73, 0x33, 0x6d, 0x6d, 0xe6, 0xf6, 0x52, 0x33, 0x38, 0xac,
+	0x5a, 0xe9, 0x72, 0x4b, 0xfe, 0xe7, 0x75, 0x64, 0x1b, 0xda, 0x24, 0x93, 0x67, 0x45, 0x5f, 0xb4,
+	0xd4, 0x8f, 0xcb, 0x30, 0xef, 0x97, 0xd0, 0xc7, 0xe1, 0x7f, 0xa9, 0xec, 0x3f, 0x80, 0x51, 0xb9,
+	0x5d, 0x31, 0x4d, 0x13, 0x41, 0xd1, 0x2e, 0x34, 0x79, 0xa8, 0xd5, 0x7b, 0xfb, 0xbd, 0x4a, 0x65,
+	0xc6, 0x4d, 0x1e, 0xfa, 0xb7, 0x60, 0xe3, 0x2b, 0x2a, 0xf1, 0x78, 0xe5, 0x3c, 0xf5, 0x11, 0xf4,
+	0x2d, 0xe8, 0x5d, 0x4c, 0x8e, 0x60, 0xf0, 0x2a, 0x16, 0x12, 0x8f, 0xf3, 0x21, 0xcd, 0x7f, 0x08,
+	0xc3, 0x82, 0x63, 0x2d, 0xec, 0x41, 0x8b, 0x87, 0x2a, 0xb1, 0x5b, 0xf3, 0x26, 0x14, 0xdf, 0xff,
+	0x10, 0x06, 0x7a, 0xd8, 0xbb, 0xc2, 0xb1, 0xfb, 0x30, 0x2c, 0x60, 0xef, 0xe2, 0xda, 0x2b, 0xd8,
+	0x39, 0xa2, 0xd2, 0x36, 0x95, 0xbc, 0x4e, 0xac, 0x5a, 0x41, 0xa5, 0xcc, 0x5c, 0x89, 0x29, 0x68,
+	0xff, 0x06, 0x78, 0x8b, 0xac, 0x19, 0x47, 0xfc, 0xdb, 0x30, 0x7c, 0x96, 0xa8, 0x0e, 0x75, 0xc5,
+	0x1e, 0x10, 0x8c, 0x4a, 0x9c, 0xd5, 0xbd, 0x03, 0xa3, 0x83, 0x58, 0xe4, 0xcc, 0x15, 0xca, 0x5b,
+	0xb0, 0x59, 0x01, 0x5a, 0xed, 0xc7, 0x30, 0x3c, 0xa0, 0x8c, 0xca, 0x2b, 0x94, 0x55, 0x18, 0x9d,
+	0xa4, 0x3c, 0xa4, 0x76, 0x7e, 0x31, 0x84, 0xf2, 0xa7, 0xd4, 0xb6, 0x16, 0xff, 0xd5, 0x82, 0x3e,
+	0x4e, 0x19, 0x8b, 0x93, 0x53, 0x33, 0xaa, 0x21, 0x0f, 0x9c, 0x94, 0x45, 0x41, 0xd5, 0x68, 0x27,
+	0x65, 0x11, 0x36, 0x67, 0xe6, 0x24, 0xf4, 0xc2, 0xca, 0x4c, 0x88, 0x76, 0x12, 0x7a, 0xa1, 0x65,
+	0xf7, 0x60, 0x64, 0x5b, 0x7d, 0x59, 0xba, 0xed, 0xcc, 0x1c, 0xd5, 0x0f, 0x52, 0x41, 0x27, 0x71,
+	0x12, 0x4f, 0xb2, 0x49, 0x09, 0xb5, 0x33, 0xb3, 0xe5, 0x17, 0xd0, 0x5d, 0x70, 0x18, 0x25, 0x6f,
+	0x68, 0x90, 0x32, 0x33, 0x42, 0x74, 0x71, 0x57, 0x33, 0x7e, 0xc3, 0x22, 0x35, 0xc2, 0xf2, 0x94,
+	0xb1, 0x20, 0xa2, 0x8c, 0xcc, 0xf4, 0xf4, 0xd0, 0xc2, 0x8e, 0xe2, 0x1c, 0x28, 0x06, 0xba, 0x03,
+	0xc3, 0x90, 0x24, 0x84, 0xcf, 0xca, 0x55, 0x3a, 0x66, 0x32, 0x35, 0xec, 0x62, 0x91, 0xf7, 0xa1,
+	0x67, 0x81, 0xc7, 0xe4, 0x9c, 0xea, 0x9c, 0x6f, 0x61, 0x30, 0xac, 0xa7, 0xe4, 0x9c, 0xa2, 0xcf,
+	0x61, 0x70, 0x42, 0x62, 0x96, 0x71, 0x1a, 0x4c, 0x75, 0x1f, 0xb3, 0x23, 0xc1, 0x76, 0x11, 0x86,
+	0xcf, 0x8d, 0xd8, 0x0e, 0x04, 0xfd, 0x93, 0x2a, 0xa9, 0xca, 0xc9, 0x94, 0x64, 0x82, 0x46, 0x7a,
+	0x18, 0xe8, 0x62, 0x4b, 0xa9, 0x3a, 0xaa, 0xbf, 0x02, 0x4e, 0x89, 0x48, 0x13, 0xdd, 0xec, 0x1d,
+	0xdc, 0xd3, 0x3c, 0xac, 0x59, 0x6a, 0xff, 0x06, 0x1c, 0x1c, 0xcf, 0xf4, 0x68, 0xeb, 0xe0, 0xae,
+	0x61, 0x3c, 0x9d, 0xa1, 0x47, 0xd0, 0x57, 0x75, 0x3a, 0x10, 0x2a, 0x16, 0x92, 0x90, 0xba, 0xfd,
+	0xb9, 0xce, 0xa6, 0xaa, 0xf5, 0x91, 0x15, 0xe2, 0x8d, 0x1f, 0x2a, 0x94, 0xff, 0xb7, 0x06, 0xf4,
+	0x6b, 0x4e, 0xab, 0x16, 0x30, 0xe5, 0xe9, 0x29, 0xa7, 0x42, 0x0d, 0x6d, 0x24, 0x62, 0x71, 0x42,
+	0x6d, 0x7f, 0x1e, 0xe5, 0x82, 0x03, 0xcb, 0x47, 0x9f, 0x40, 0x5f, 0x35, 0xe9, 0xf2, 0x1a, 0xd3,
+	0xd4, 0x4b, 0x0f, 0x8a, 0xa5, 0x5f, 0xc5, 0x93, 0x58, 0x62, 0xd5, 0xc9, 0xcb, 0x1b, 0xcd, 0xcf,
+	0x60, 0xbb, 0xa6, 0x14, 0x44, 0x19, 0xd7, 0x85, 0xd7, 0x06, 0xca, 0xb5, 0x2a, 0xfa, 0xc0, 0xca,
+	0x74, 0xa2, 0xa6, 0x8c, 0x1d, 0x93, 0xf0, 0x5c, 0x47, 0x49, 0x17, 0x17, 0xb4, 0xbf, 0x07, 0xeb,
+	0x7a, 0xa1, 0xb2, 0x70, 0x1a, 0x87, 0x0d, 0xe1, 0xcf, 0x60, 0xa3, 0x7a, 0x04, 0x4b, 0x1a, 0xed,
+	0x35, 0x58, 0x4f, 0x79, 0x44, 0xb9, 0xed, 0xb3, 0x86, 0x50, 0x5c, 0x7d, 0xd0, 0xd6, 0x37, 0x43,
+	0xa8, 0x98, 0xe2, 0xf4, 0xfb, 0x2c, 0xe6, 0xb4, 0x76, 0xdb, 0xeb, 0xe2, 0x81, 0x65, 0xdb, 0xcb,
+	0x9e, 0xff, 0x1a, 0x6e, 0xe4, 0xf7, 0xc9, 0x5a, 0x7e, 0xe5, 0x79, 0xfb, 0x39, 0x0c, 0xb8, 0xe1,
+	0xdb, 0xab, 0x93, 0xdb, 0x98, 0x0b, 0xa9, 0xba, 0x5a, 0x9f, 0x57, 0x49, 0xff, 0x3b, 0xd8, 0x5b,
+	0x62, 0xde, 0x56, 0xcb, 0x1f, 0x69, 0x7f, 0x17, 0x76, 0x74, 0x61, 0xaf, 0x32, 0x8b, 0xaa, 0xff,
+	0x1a, 0xbc, 0x45, 0x42, 0xbb, 0xf2, 0x13, 0x18, 0xd6, 0x57, 0xce, 0x9b, 0xc1, 0xb2, 0xa5, 0x07,
+	0xb5, 0xa5, 0x85, 0xff, 0x73, 0xf0, 0x6c, 0x9d, 0x5a, 0x74, 0x70, 0xef, 0x41, 0x47, 0x27, 0x7d,
+	0x51, 0x9d, 0xda, 0x8a, 0x7c, 0x19, 0xf9, 0x7b, 0xb0, 0xbb, 0x50, 0xcd, 0x56, 0xba, 0x1b, 0xe0,
+	0x99, 0x8e, 0xb2, 0x70, 0x4b, 0xdf, 0xc1, 0xee, 0x42, 0xe9, 0xff, 0x6a, 0x4f, 0x0f, 0xe0, 0x9a,
+	0x6a, 0xb4, 0x29, 0x63, 0xf5, 0x57, 0x8e, 0xa5, 0xbb, 0xf9, 0x29, 0x6c, 0x17, 0x0e, 0xbd, 0xa3,
+	0xca, 0x3f, 0x1a, 0x00, 0x25, 0x1c, 0xfd, 0x3f, 0xb4, 0x4d, 0x21, 0x77, 0x1b, 0x73, 0x97, 0x08,
+	0x05, 0x1a, 0xa7, 0x59, 0x22, 0x05, 0x5e, 0xd7, 0xa5, 0x5d, 0x61, 0x4d, 0x61, 0x77, 0x9b, 0x2b,
+	0xb0, 0xba, 0xd4, 0xab, 0x79, 0x56, 0x48, 0x3a, 0xcd, 0x67, 0x5c, 0xf5, 0xad, 0xd2, 0xe2, 0x98,
+	0xa5, 0xe1, 0xb9, 0x3a, 0x20, 0x5b, 0xcc, 0xcc, 0x50, 0x38, 0xc8, 0xd9, 0xb6, 0x9e, 0xed, 0x01,
+	0x08, 0x49, 0xb8, 0x34, 0xd7, 0x63, 0x73, 0x27, 0x74, 0x34, 0x47, 0xbf, 0x11, 0x7c, 0x04, 0xfa,
+	0xae, 0x1c, 0x14, 0x85, 0x48, 0xc3, 0x4c, 0x65, 0xd7, 0xef, 0x0c, 0x87, 0x56, 0xa0, 0xef, 0xd0,
+	0x7f, 0xb2, 0x1b, 0x36, 0xfe, 0xa9, 0xeb, 0x79, 0xfd, 0x19, 0x28, 0x27, 0x95, 0x24, 0x7f, 0xc6,
+	0xb1, 0x77, 0x14, 0x4b, 0xaa, 0xcd, 0x70, 0x4a, 0x98, 0x4d, 0x72, 0xfd, 0xad, 0xd0, 0xf5, 0x97,
+	0x9c, 0x9c, 0x44, 0x37, 0xc0, 0x99, 0x7f, 0xb8, 0x29, 0x19, 0x4a, 0x2f, 0x4b, 0xce, 0x13, 0x75,
+	0xf9, 0x30, 0x1e, 0xe7, 0xe4, 0xfe, 0x5f, 0x1c, 0x70, 0x0e, 0xf7, 0xd5, 0xc3, 0x55, 0xca, 0x29,
+	0xfa, 0x0c, 0x9c, 0xe2, 0x61, 0x0c, 0x95, 0xd7, 0xd2, 0xf9, 0xc7, 0x32, 0x6f, 0xb3, 0x32, 0xe4,
+	0x58, 0xf4, 0x13, 0xe8, 0x55, 0x9e, 0xca, 0xd0, 0x6e, 0x81, 0xb8, 0xfc, 0x80, 0xb6, 0x40, 0xfd,
+	0x61, 0x03, 0x3d, 0x81, 0x6e, 0x3e, 0x46, 0x22, 0x77, 0xd9, 0x20, 0xed, 0xed, 0x2c, 0x90, 0xd8,
+	0x4c, 0xf8, 0x14, 0xd6, 0xf5, 0xc4, 0x88, 0xae, 0x57, 0x1d, 0x2f, 0x55, 0xb7, 0xe7, 0xd9, 0x56,
+	0xef, 0x31, 0x74, 0xec, 0xa4, 0x88, 0xde, 0xab, 0x34, 0x89, 0xea, 0x34, 0xe9, 0xb9, 0x97, 0x05,
+	0x56, 0xfb, 0x0b, 0xe8, 0xd8, 0x71, 0xb0, 0xa2, 0x5d, 0x9f, 0x23, 0x3d, 0xf7, 0xb2, 0xc0, 0x68,
+	0x3f, 0x6c, 0xa0, 0xd7, 0x80, 0x2e, 0x0f, 0x74, 0xc8, 0x2f, 0x34, 0x96, 0xce, 0x8e, 0xde, 0xad,
+	0x95, 0x98, 0xa2, 0x3c, 0x74, 0xf3, 0x49, 0xaf, 0x72, 0xaa, 0x73, 0x43, 0xa2, 0xb7, 0xb3, 0x40,
+	0x62, 0x0d, 0x3c, 0x05, 0xa7, 0x98, 0xf6, 0x2a, 0x21, 0x31, 0x3f, 0x2a, 0x7a, 0xde, 0x22, 0x51,
+	0xe9, 0x44, 0x3e, 0xde, 0x55, 0x9c, 0x98, 0x9b, 0x17, 0xbd, 0x9d, 0x05, 0x12, 0x6b, 0xe0, 0x04,
+	0xae, 0x2f, 0xec, 0x29, 0xe8, 0xc3, 0xf2, 0x0c, 0x56, 0xb4, 0x34, 0xef, 0xf6, 0x55, 0x30, 0xbb,
+	0xce, 0x6b, 0x40, 0x97, 0xdb, 0x47, 0xe5, 0x67, 0x2c, 0x6d, 0x3c, 0xde, 0xad, 0x95, 0x18, 0x6b,
+	0xfe, 0x0f, 0xb0, 0xb5, 0xa0, 0x0f, 0xa0, 0x5b, 0xf3, 0x1b, 0x5f, 0xb4, 0x85, 0x0f, 0x56, 0x83,
+	0xec, 0x0a, 0xc7, 0xb0, 0xb5, 0xa0, 0x59, 0x54, 0x56, 0x58, 0xde, 0x68, 0xbc, 0x0f, 0x56, 0x83,
+	0x8a, 0x88, 0x1d, 0x9b, 0x9b, 0x59, 0x59, 0xce, 0xf7, 0x6a, 0x89, 0x35, 0xdf, 0x15, 0xbc, 0x7a,
+	0xc5, 0xb6, 0x3a, 0x2f, 0xf3, 0x5b, 0x54, 0xc9, 0x7a, 0xff, 0xf2, 0xfa, 0x57, 0x1b, 0x7a, 0xd8,
+	0x38, 0x6e, 0xeb, 0xd7, 0xfd, 0x4f, 0xfe, 0x3d, 0x00, 0x4a, 0x2d, 0x9a, 0x53, 0xee, 0x17, 0x00,
+	0x00,
+}
@@ -0,0 +1,596 @@
+package rcstore
+
+import (
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	klabels "k8s.io/kubernetes/pkg/labels"
+
+	rcstore_protos "github.com/square/p2/pkg/grpc/rcstore/protos"
+	"github.com/square/p2/pkg/manifest"
+	pc_fields "github.com/square/p2/pkg/pc/fields"
+	"github.com/square/p2/pkg/rc/fields"
+	"github.com/square/p2/pkg/store/consul/rcstore"
+	"github.com/square/p2/pkg/store/consul/statusstore"
+	"github.com/square/p2/pkg/store/consul/statusstore/rcstatus"
+	"github.com/square/p2/pkg/store/consul/transaction"
+	"github.com/square/p2/pkg/types"
+)
+
+type RCStatusStore interface {
+	Get(rcID fields.ID) (rcstatus.Status, *api.QueryMeta, error)
+	Watch(rcID fields.ID, waitIndex uint64) (rcstatus.Status, *api.QueryMeta, error)
+}
+
+type RCStore interface {
+	CreateTxn(
+		ctx context.Context,
+		manifest manifest.Manifest,
+		nodeSelector klabels.Selector,
+		availabilityZone pc_fields.AvailabilityZone,
+		clusterName pc_fields.ClusterName,
+		podLabels klabels.Set,
+		additionalLabels klabels.Set,
+		allocationStrategy fields.Strategy,
+	) (fields.RC, error)
+	Get(id fields.ID) (fields.RC, error)
+	List() ([]fields.RC, error)
+	Watch(rcID fields.ID, quit <-chan struct{}) (<-chan fields.RC, <-chan error)
+	SetDesiredReplicas(id fields.ID, n int) error
+	Enable(id fields.ID) error
+	Disable(id fields.ID) error
+	Delete(id fields.ID, force bool) error
+}
+
+type Store struct {
+	rcStatusStore   RCStatusStore
+	rcStore         RCStore
+	rollStore       RollStore
+	rollStatusStore RollStatusStore
+	txner           transaction.Txner
+}
+
+func NewServer(
+	rcStatusStore RCStatusStore,
+	rcStore RCStore,
+	rollStore RollStore,
+	rollStatusStore RollStatusStore,
+	txner transaction.Txner,
+) Store {
+	return Store{
+		rcStatusStore:   rcStatusStore,
+		rcStore:         rcStore,
+		rollStore:       rollStore,
+		rollStatusStore: rollStatusStore,
+		txner:           txner,
+	}
+}
+
+var _ rcstore_protos.P2RCStoreServer = Store{}
+
+func (s Store) GetStatus(_ context.Context, req *rcstore_protos.GetStatusRequest) (*rcstore_protos.RCStatus, error) {
+	if req.RcId == "" {
+		return nil, grpc.Errorf(codes.InvalidArgument, "rc_id must be set")
+	}
+
+	status, _, err := s.rcStatusStore.Get(fields.ID(req.RcId))
+	if err != nil {
+		return nil, convertStatusStoreError(fields.ID(req.RcId), err)
+	}
+	return RCStatusToProto(status), nil
+}
+
+type statusResult struct {
+	status rcstatus.Status
+	err    error
+}
+
+func (s Store) WatchStatus(req *rcstore_protos.WatchStatusRequest, stream rcstore_protos.P2RCStore_WatchStatusServer) error {
+	if req.RcId == "" {
+		return grpc.Errorf(codes.InvalidArgument, "rc_id must be set")
+	}
+	rcID := fields.ID(req.RcId)
+
+	// Do one consistent fetch to send the current status. From then on
+	// watch using the index it returned
+	status, queryMeta, err := s.rcStatusStore.Get(rcID)
+	if err != nil {
+		return convertStatusStoreError(rcID, err)
+	}
+	err = stream.Send(RCStatusToProto(status))
+	if err != nil {
+		return err
+	}
+	waitIndex := queryMeta.LastIndex
+
+	clientCancel := stream.Context().Done()
+	resultCh := make(chan statusResult)
+	innerQuit := make(chan struct{})
+	defer close(innerQuit)
+	go func() {
+		defer close(resultCh)
+		for {
+			status, queryMeta, err := s.rcStatusStore.Watch(rcID, waitIndex)
+			if queryMeta != nil {
+				if queryMeta.LastIndex == waitIndex && err == nil {
+					// The watch timed out without a change
+					continue
+				}
+				waitIndex = queryMeta.LastIndex
+			}
+
+			select {
+			case resultCh <- statusResult{status: status, err: err}:
+			case <-innerQuit:
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-clientCancel:
+			return nil
+		case result, ok := <-resultCh:
+			if !ok {
+				return nil
+			}
+			if result.err != nil {
+				return convertStatusStoreError(rcID, result.err)
+			}
+			err := stream.Send(RCStatusToProto(result.status))
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s Store) CreateRC(ctx context.Context, req *rcstore_protos.CreateRCRequest) (*rcstore_protos.CreateRCResponse, error) {
+	if req.AvailabilityZone == "" {
+		return nil, grpc.Errorf(codes.InvalidArgument, "availability_zone must be set")
+	}
+	if req.ClusterName == "" {
+		return nil, grpc.Errorf(codes.InvalidArgument, "cluster_name must be set")
+	}
+
+	man, err := manifest.FromBytes([]byte(req.Manifest))
+	if err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, "could not parse manifest: %s", err)
+	}
+	nodeSelector, err := klabels.Parse(req.NodeSelector)
+	if err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, "could not parse node selector: %s", err)
+	}
+
+	strategy := fields.Strategy(req.AllocationStrategy)
+	switch strategy {
+	case "":
+		strategy = fields.DynamicStrategy
+	case fields.DynamicStrategy, fields.StaticStrategy:
+	default:
+		return nil, grpc.Errorf(codes.InvalidArgument, "unknown allocation strategy %q", req.AllocationStrategy)
+	}
+
+	availabilityZone := pc_fields.AvailabilityZone(req.AvailabilityZone)
+	clusterName := pc_fields.ClusterName(req.ClusterName)
+	podLabels := klabels.Set{}
+	for k, v := range req.PodLabels {
+		podLabels[k] = v
+	}
+	podLabels[types.ClusterNameLabel] = clusterName.String()
+	podLabels[types.AvailabilityZoneLabel] = availabilityZone.String()
+
+	if req.Author != "" {
+		ctx = rcstore.WithAuthor(ctx, req.Author)
+	}
+	trxctx, cancelFunc := transaction.New(ctx)
+	defer cancelFunc()
+	rc, err := s.rcStore.CreateTxn(
+		trxctx,
+		man,
+		nodeSelector,
+		availabilityZone,
+		clusterName,
+		podLabels,
+		klabels.Set(req.RcLabels),
+		strategy,
+	)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Unavailable, "could not create replication controller: %s", err)
+	}
+
+	err = transaction.MustCommit(trxctx, s.txner)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Unavailable, "could not create replication controller: %s", err)
+	}
+
+	rcProto, err := RCToProto(rc)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Unavailable, "create succeeded, but could not convert replication controller to proto type: %s", err)
+	}
+	return &rcstore_protos.CreateRCResponse{
+		Rc: rcProto,
+	}, nil
+}
+
+func (s Store) GetRC(_ context.Context, req *rcstore_protos.GetRCRequest) (*rcstore_protos.GetRCResponse, error) {
+	if req.RcId == "" {
+		return nil, grpc.Errorf(codes.InvalidArgument, "rc_id must be set")
+	}
+	rcID := fields.ID(req.RcId)
+
+	rc, err := s.rcStore.Get(rcID)
+	if err != nil {
+		return nil, convertRCStoreError(rcID, "get", err)
+	}
+
+	rcProto, err := RCToProto(rc)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Unavailable, err.Error())
+	}
+	return &rcstore_protos.GetRCResponse{
+		Rc: rcProto,
+	}, nil
+}
+
+func (s Store) ListRCs(_ context.Context, _ *rcstore_protos.ListRCsRequest) (*rcstore_protos.ListRCsResponse, error) {
+	rcs, err := s.rcStore.List()
+	if err != nil {
+		return nil, grpc.Errorf(codes.Unavailable, "could not list replication controllers: %s", err)
+	}
+
+	resp := &rcstore_protos.ListRCsResponse{
+		Rcs: make([]*rcstore_protos.RC, len(rcs)),
+	}
+	for i, rc := range rcs {
+		resp.Rcs[i], err = RCToProto(rc)
+		if err != nil {
+			return nil, grpc.Errorf(codes.Unavailable, err.Error())
+		}
+	}
+	return resp, nil
+}
+
+func (s Store) WatchRC(req *rcstore_protos.WatchRCRequest, stream rcstore_protos.P2RCStore_WatchRCServer) error {
+	if req.RcId == "" {
+		return grpc.Errorf(codes.InvalidArgument, "rc_id must be set")
+	}
+	rcID := fields.ID(req.RcId)
+
+	// The watch waits for an RC that doesn't exist to be created, which
+	// isn't what a caller that got the ID wrong wants
+	_, err := s.rcStore.Get(rcID)
+	if err != nil {
+		return convertRCStoreError(rcID, "watch", err)
+	}
+
+	clientCancel := stream.Context().Done()
+	rcCh, errCh := s.rcStore.Watch(rcID, clientCancel)
+	for {
+		select {
+		case <-clientCancel:
+			return nil
+		case rc, ok := <-rcCh:
+			if !ok {
+				return nil
+			}
+			rcProto, err := RCToProto(rc)
+			if err != nil {
+				return grpc.Errorf(codes.Unavailable, err.Error())
+			}
+			err = stream.Send(&rcstore_protos.WatchRCResponse{Rc: rcProto})
+			if err != nil {
+				return err
+			}
+		case err, ok := <-errCh:
+			if !ok {
+				return nil
+			}
+			return grpc.Errorf(codes.Unavailable, "could not watch replication controller %s: %s", rcID, err)
+		}
+	}
+}
+
+func (s Store) SetDesiredReplicas(_ context.Context, req *rcstore_protos.SetDesiredReplicasRequest) (*rcstore_protos.SetDesiredReplicasResponse, error) {
+	if req.RcId == "" {
+		return nil, grpc.Errorf(codes.InvalidArgument, "rc_id must be set")
+	}
+	if req.Replicas < 0 {
+		return nil, grpc.Errorf(codes.InvalidArgument, "replicas must not be negative")
+	}
+	rcID := fields.ID(req.RcId)
+
+	err := s.rcStore.SetDesiredReplicas(rcID, int(req.Replicas))
+	if err != nil {
+		return nil, convertRCStoreError(rcID, "set the replica count of", err)
+	}
+	return &rcstore_protos.SetDesiredReplicasResponse{}, nil
+}
+
+func (s Store) EnableRC(_ context.Context, req *rcstore_protos.EnableRCRequest) (*rcstore_protos.EnableRCResponse, error) {
+	if req.RcId == "" {
+		return nil, grpc.Errorf(codes.InvalidArgument, "rc_id must be set")
+	}
+	rcID := fields.ID(req.RcId)
+
+	err := s.rcStore.Enable(rcID)
+	if err != nil {
+		return nil, convertRCStoreError(rcID, "enable", err)
+	}
+	return &rcstore_protos.EnableRCResponse{}, nil
+}
+
+func (s Store) DisableRC(_ context.Context, req *rcstore_protos.DisableRCRequest) (*rcstore_protos.DisableRCResponse, error) {
+	if req.RcId == "" {
+		return nil, grpc.Errorf(codes.InvalidArgument, "rc_id must be set")
+	}
+	rcID := fields.ID(req.RcId)
+
+	err := s.rcStore.Disable(rcID)
+	if err != nil {
+		return nil, convertRCStoreError(rcID, "disable", err)
+	}
+	return &rcstore_protos.DisableRCResponse{}, nil
+}
+
+func (s Store) DeleteRC(_ context.Context, req *rcstore_protos.DeleteRCRequest) (*rcstore_protos.DeleteRCResponse, error) {
+	if req.RcId == "" {
+		return nil, grpc.Errorf(codes.InvalidArgument, "rc_id must be set")
+	}
+	rcID := fields.ID(req.RcId)
+
+	// Check the replica count here so that refusing to delete an RC that
+	// still has replicas can be told apart from the store being unavailable
+	rc, err := s.rcStore.Get(rcID)
+	if err != nil {
+		return nil, convertRCStoreError(rcID, "delete", err)
+	}
+	if !req.Force && rc.ReplicasDesired != 0 {
+		return nil, grpc.Errorf(codes.FailedPrecondition, "replication controller %s has %d desired replicas (must reduce to 0 or force the deletion)", rcID, rc.ReplicasDesired)
+	}
+
+	err = s.rcStore.Delete(rcID, req.Force)
+	if err != nil {
+		return nil, convertRCStoreError(rcID, "delete", err)
+	}
+	return &rcstore_protos.DeleteRCResponse{}, nil
+}
+
+func convertRCStoreError(rcID fields.ID, action string, err error) error {
+	if rcstore.IsNotExist(err) {
+		return grpc.Errorf(codes.NotFound, "no replication controller with id %s was found", rcID)
+	}
+	return grpc.Errorf(codes.Unavailable, "could not %s replication controller %s: %s", action, rcID, err)
+}
+
+func convertStatusStoreError(rcID fields.ID, err error) error {
+	if statusstore.IsNoStatus(err) {
+		return grpc.Errorf(codes.NotFound, "no status found for replication controller %s", rcID)
+	}
+	return grpc.Errorf(codes.Unavailable, "could not fetch status for replication controller %s: %s", rcID, err)
+}
+
+func RCStatusToProto(status rcstatus.Status) *rcstore_protos.RCStatus {
+	ineligible := make([]string, len(status.IneligibleNodes))
+	for i, node := range status.IneligibleNodes {
+		ineligible[i] = node.String()
+	}
+	conditions := make([]*rcstore_protos.Condition, len(status.Conditions))
+	for i, condition := range status.Conditions {
+		conditions[i] = &rcstore_protos.Condition{
+			Type:               string(condition.Type),
+			Status:             condition.Status,
+			Message:            condition.Message,
+			LastTransitionTime: timeToProto(&condition.LastTransitionTime),
+		}
+	}
+	missing := make([]*rcstore_protos.MissingArtifact, len(status.MissingArtifacts))
+	for i, artifact := range status.MissingArtifacts {
+		missing[i] = &rcstore_protos.MissingArtifact{
+			LaunchableId: artifact.LaunchableID,
+			Url:          artifact.URL,
+		}
+	}
+
+	return &rcstore_protos.RCStatus{
+		ReplicasDesired:           int64(status.ReplicasDesired),
+		ReplicasScheduled:         int64(status.ReplicasScheduled),
+		ReplicasCurrent:           int64(status.ReplicasCurrent),
+		ReplicasHealthy:           int64(status.ReplicasHealthy),
+		ReplicasUnhealthy:         int64(status.ReplicasUnhealthy),
+		IneligibleNodes:           ineligible,
+		Conditions:                conditions,
+		LastError:                 status.LastError,
+		LastErrorTime:             timeToProto(status.LastErrorTime),
+		LastUpdateTime:            timeToProto(&status.LastUpdateTime),
+		MissingArtifacts:          missing,
+		MissingArtifactsCheckTime: timeToProto(status.MissingArtifactsCheckTime),
+	}
+}
+
+func ProtoToRCStatus(proto *rcstore_protos.RCStatus) rcstatus.Status {
+	var ineligible []types.NodeName
+	for _, node := range proto.IneligibleNodes {
+		ineligible = append(ineligible, types.NodeName(node))
+	}
+	var conditions []rcstatus.Condition
+	for _, condition := range proto.Conditions {
+		conditions = append(conditions, rcstatus.Condition{
+			Type:               rcstatus.ConditionType(condition.Type),
+			Status:             condition.Status,
+			Message:            condition.Message,
+			LastTransitionTime: protoToTime(condition.LastTransitionTime),
+		})
+	}
+	var missing []rcstatus.MissingArtifact
+	for _, artifact := range proto.MissingArtifacts {
+		missing = append(missing, rcstatus.MissingArtifact{
+			LaunchableID: artifact.LaunchableId,
+			URL:          artifact.Url,
+		})
+	}
+
+	status := rcstatus.Status{
+		ReplicasDesired:   int(proto.ReplicasDesired),
+		ReplicasScheduled: int(proto.ReplicasScheduled),
+		ReplicasCurrent:   int(proto.ReplicasCurrent),
+		ReplicasHealthy:   int(proto.ReplicasHealthy),
+		ReplicasUnhealthy: int(proto.ReplicasUnhealthy),
+		IneligibleNodes:   ineligible,
+		Conditions:        conditions,
+		LastError:         proto.LastError,
+		LastUpdateTime:    protoToTime(proto.LastUpdateTime),
+		MissingArtifacts:  missing,
+	}
+	if proto.LastErrorTime != 0 {
+		t := protoToTime(proto.LastErrorTime)
+		status.LastErrorTime = &t
+	}
+	if proto.MissingArtifactsCheckTime != 0 {
+		t := protoToTime(proto.MissingArtifactsCheckTime)
+		status.MissingArtifactsCheckTime = &t
+	}
+	return status
+}
+
+func RCToProto(rc fields.RC) (*rcstore_protos.RC, error) {
+	rawRC, err := rc.ToRaw()
+	if err != nil {
+		return nil, err
+	}
+
+	spreadConstraints := make([]*rcstore_protos.SpreadConstraint, len(rawRC.SpreadConstraints))
+	for i, constraint := range rawRC.SpreadConstraints {
+		spreadConstraints[i] = &rcstore_protos.SpreadConstraint{
+			TopologyKey: constraint.TopologyKey,
+			MaxSkew:     int64(constraint.MaxSkew),
+		}
+	}
+
+	var autoscale *rcstore_protos.AutoscalePolicy
+	if rawRC.Autoscale != nil {
+		autoscale = &rcstore_protos.AutoscalePolicy{
+			MinReplicas: int64(rawRC.Autoscale.MinReplicas),
+			MaxReplicas: int64(rawRC.Autoscale.MaxReplicas),
+			Target:      rawRC.Autoscale.Target,
+			Cooldown:    int64(rawRC.Autoscale.Cooldown),
+			Metric: &rcstore_protos.MetricSource{
+				Type: string(rawRC.Autoscale.Metric.Type),
+				Url:  rawRC.Autoscale.Metric.URL,
+				Path: rawRC.Autoscale.Metric.Path,
+				Port: int64(rawRC.Autoscale.Metric.Port),
+			},
+		}
+	}
+
+	return &rcstore_protos.RC{
+		Id:                 rawRC.ID.String(),
+		Manifest:           rawRC.Manifest,
+		NodeSelector:       rawRC.NodeSelector,
+		PodLabels:          rawRC.PodLabels,
+		ReplicasDesired:    int64(rc.ReplicasDesired),
+		Disabled:           rawRC.Disabled,
+		AllocationStrategy: string(rawRC.AllocationStrategy),
+		SpreadConstraints:  spreadConstraints,
+		Autoscale:          autoscale,
+		ScheduleZones:      zoneFilterToProto(rawRC.ScheduleZones),
+		UnscheduleZones:    zoneFilterToProto(rawRC.UnscheduleZones),
+	}, nil
+}
+
+func ProtoToRC(proto *rcstore_protos.RC) (fields.RC, error) {
+	var man manifest.Manifest
+	if proto.Manifest != "" {
+		var err error
+		man, err = manifest.FromBytes([]byte(proto.Manifest))
+		if err != nil {
+			return fields.RC{}, err
+		}
+	}
+
+	nodeSelector, err := klabels.Parse(proto.NodeSelector)
+	if err != nil {
+		return fields.RC{}, err
+	}
+
+	var spreadConstraints []fields.SpreadConstraint
+	for _, constraint := range proto.SpreadConstraints {
+		spreadConstraints = append(spreadConstraints, fields.SpreadConstraint{
+			TopologyKey: constraint.TopologyKey,
+			MaxSkew:     int(constraint.MaxSkew),
+		})
+	}
+
+	var autoscale *fields.AutoscalePolicy
+	if proto.Autoscale != nil {
+		autoscale = &fields.AutoscalePolicy{
+			MinReplicas: int(proto.Autoscale.MinReplicas),
+			MaxReplicas: int(proto.Autoscale.MaxReplicas),
+			Target:      proto.Autoscale.Target,
+			Cooldown:    time.Duration(proto.Autoscale.Cooldown),
+		}
+		if metric := proto.Autoscale.Metric; metric != nil {
+			autoscale.Metric = fields.MetricSource{
+				Type: fields.MetricSourceType(metric.Type),
+				URL:  metric.Url,
+				Path: metric.Path,
+				Port: int(metric.Port),
+			}
+		}
+	}
+
+	return fields.RC{
+		ID:                 fields.ID(proto.Id),
+		Manifest:           man,
+		NodeSelector:       nodeSelector,
+		PodLabels:          klabels.Set(proto.PodLabels),
+		ReplicasDesired:    int(proto.ReplicasDesired),
+		Disabled:           proto.Disabled,
+		AllocationStrategy: fields.Strategy(proto.AllocationStrategy),
+		SpreadConstraints:  spreadConstraints,
+		Autoscale:          autoscale,
+		ScheduleZones:      protoToZoneFilter(proto.ScheduleZones),
+		UnscheduleZones:    protoToZoneFilter(proto.UnscheduleZones),
+	}, nil
+}
+
+func zoneFilterToProto(filter *fields.ZoneFilter) *rcstore_protos.ZoneFilter {
+	if filter == nil {
+		return nil
+	}
+	return &rcstore_protos.ZoneFilter{
+		Label:  filter.Label,
+		Values: filter.Values,
+	}
+}
+
+func protoToZoneFilter(proto *rcstore_protos.ZoneFilter) *fields.ZoneFilter {
+	if proto == nil {
+		return nil
+	}
+	return &fields.ZoneFilter{
+		Label:  proto.Label,
+		Values: proto.Values,
+	}
+}
+
+func timeToProto(t *time.Time) int64 {
+	if t == nil || t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+func protoToTime(nanos int64) time.Time {
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
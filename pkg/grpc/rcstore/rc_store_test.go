@@ -0,0 +1,429 @@
+// +build !race
+
+package rcstore
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	klabels "k8s.io/kubernetes/pkg/labels"
+
+	rcstore_protos "github.com/square/p2/pkg/grpc/rcstore/protos"
+	"github.com/square/p2/pkg/grpc/testutil"
+	"github.com/square/p2/pkg/labels"
+	"github.com/square/p2/pkg/logging"
+	"github.com/square/p2/pkg/manifest"
+	"github.com/square/p2/pkg/rc/fields"
+	"github.com/square/p2/pkg/store/consul"
+	"github.com/square/p2/pkg/store/consul/consulutil"
+	"github.com/square/p2/pkg/store/consul/rcstore"
+	"github.com/square/p2/pkg/store/consul/rollstore"
+	"github.com/square/p2/pkg/store/consul/statusstore"
+	"github.com/square/p2/pkg/store/consul/statusstore/rcstatus"
+	"github.com/square/p2/pkg/store/consul/statusstore/rollstatus"
+	"github.com/square/p2/pkg/types"
+)
+
+func TestGetStatus(t *testing.T) {
+	fixture := consulutil.NewFixture(t)
+	defer fixture.Stop()
+	rcStatusStore := rcstatus.NewConsul(statusstore.NewConsul(fixture.Client), consul.RCStatusNamespace)
+	server := NewServer(rcStatusStore, nil, nil, nil, nil)
+
+	_, err := server.GetStatus(context.Background(), &rcstore_protos.GetStatusRequest{RcId: "abc"})
+	if grpc.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound for a missing status, got %s", err)
+	}
+
+	now := time.Now()
+	status := rcstatus.Status{
+		ReplicasDesired:   3,
+		ReplicasScheduled: 2,
+		ReplicasHealthy:   1,
+		ReplicasUnhealthy: 1,
+		IneligibleNodes:   []types.NodeName{"node1"},
+		LastError:         "no nodes",
+		LastErrorTime:     &now,
+		LastUpdateTime:    now,
+		MissingArtifacts:  []rcstatus.MissingArtifact{{LaunchableID: "app", URL: "https://artifacts/app.tar.gz"}},
+	}
+	status.SetCondition(rcstatus.ReplicasMet, false, "2 of 3 desired replicas are scheduled", now)
+	err = rcStatusStore.Set("abc", status)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := server.GetStatus(context.Background(), &rcstore_protos.GetStatusRequest{RcId: "abc"})
+	if err != nil {
+		t.Fatalf("unexpected error getting status: %s", err)
+	}
+	got := ProtoToRCStatus(resp)
+	if got.ReplicasDesired != 3 || got.ReplicasScheduled != 2 || got.ReplicasUnhealthy != 1 {
+		t.Errorf("counts did not survive the round trip: %+v", got)
+	}
+	if len(got.IneligibleNodes) != 1 || got.IneligibleNodes[0] != "node1" {
+		t.Errorf("expected node1 to be ineligible, got %v", got.IneligibleNodes)
+	}
+	if got.LastErrorTime == nil || !got.LastErrorTime.Equal(now) {
+		t.Errorf("expected last error time %s, got %v", now, got.LastErrorTime)
+	}
+	if got.MissingArtifactsCheckTime != nil {
+		t.Errorf("expected no missing artifacts check time, got %s", got.MissingArtifactsCheckTime)
+	}
+	condition, ok := got.Condition(rcstatus.ReplicasMet)
+	if !ok || condition.Status || !condition.LastTransitionTime.Equal(now) {
+		t.Errorf("expected an unmet replicas condition, got %+v", got.Conditions)
+	}
+	if len(got.MissingArtifacts) != 1 || got.MissingArtifacts[0].LaunchableID != "app" {
+		t.Errorf("expected a missing artifact for app, got %+v", got.MissingArtifacts)
+	}
+}
+
+type fakeWatchStatusServer struct {
+	*testutil.FakeServerStream
+	statuses chan *rcstore_protos.RCStatus
+}
+
+func (f fakeWatchStatusServer) Send(status *rcstore_protos.RCStatus) error {
+	f.statuses <- status
+	return nil
+}
+
+func TestWatchStatus(t *testing.T) {
+	fixture := consulutil.NewFixture(t)
+	defer fixture.Stop()
+	rcStatusStore := rcstatus.NewConsul(statusstore.NewConsul(fixture.Client), consul.RCStatusNamespace)
+	server := NewServer(rcStatusStore, nil, nil, nil, nil)
+
+	err := rcStatusStore.Set("abc", rcstatus.Status{ReplicasDesired: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := fakeWatchStatusServer{
+		FakeServerStream: testutil.NewFakeServerStream(ctx),
+		statuses:         make(chan *rcstore_protos.RCStatus),
+	}
+	watchErr := make(chan error)
+	go func() {
+		watchErr <- server.WatchStatus(&rcstore_protos.WatchStatusRequest{RcId: "abc"}, stream)
+	}()
+
+	expectDesired := func(desired int64) {
+		select {
+		case status := <-stream.statuses:
+			if status.ReplicasDesired != desired {
+				t.Fatalf("expected %d desired replicas, got %d", desired, status.ReplicasDesired)
+			}
+		case err := <-watchErr:
+			t.Fatalf("watch ended early: %s", err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for a status")
+		}
+	}
+	expectDesired(1)
+
+	err = rcStatusStore.Set("abc", rcstatus.Status{ReplicasDesired: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectDesired(2)
+
+	cancel()
+	select {
+	case err := <-watchErr:
+		if err != nil {
+			t.Errorf("expected the watch to end cleanly when canceled, got %s", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("watch did not end when canceled")
+	}
+}
+
+func TestCreateAndGetRC(t *testing.T) {
+	fixture := consulutil.NewFixture(t)
+	defer fixture.Stop()
+	server := newTestServer(fixture)
+
+	createResp, err := server.CreateRC(context.Background(), &rcstore_protos.CreateRCRequest{
+		Manifest:         testManifestString(t),
+		NodeSelector:     "size=large",
+		AvailabilityZone: "west",
+		ClusterName:      "some_cluster",
+		PodLabels:        map[string]string{"team": "storage"},
+		Author:           "some_user",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating RC: %s", err)
+	}
+	rc, err := ProtoToRC(createResp.Rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rc.ID == "" || rc.Manifest.ID() != "some_pod" || rc.NodeSelector.String() != "size=large" {
+		t.Errorf("unexpected RC created: %+v", rc)
+	}
+	if rc.AllocationStrategy != fields.DynamicStrategy {
+		t.Errorf("expected the allocation strategy to default to %s, got %s", fields.DynamicStrategy, rc.AllocationStrategy)
+	}
+	if rc.PodLabels["team"] != "storage" || rc.PodLabels[types.ClusterNameLabel] != "some_cluster" || rc.PodLabels[types.AvailabilityZoneLabel] != "west" {
+		t.Errorf("expected pod labels to include the cluster and zone, got %v", rc.PodLabels)
+	}
+
+	getResp, err := server.GetRC(context.Background(), &rcstore_protos.GetRCRequest{RcId: rc.ID.String()})
+	if err != nil {
+		t.Fatalf("unexpected error getting RC: %s", err)
+	}
+	if getResp.Rc.Id != rc.ID.String() || getResp.Rc.Manifest != createResp.Rc.Manifest {
+		t.Errorf("expected to get the created RC, got %+v", getResp.Rc)
+	}
+
+	listResp, err := server.ListRCs(context.Background(), &rcstore_protos.ListRCsRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error listing RCs: %s", err)
+	}
+	if len(listResp.Rcs) != 1 || listResp.Rcs[0].Id != rc.ID.String() {
+		t.Errorf("expected the created RC to be listed, got %+v", listResp.Rcs)
+	}
+
+	_, err = server.GetRC(context.Background(), &rcstore_protos.GetRCRequest{RcId: "nonexistent"})
+	if grpc.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound for a missing RC, got %s", err)
+	}
+}
+
+func TestCreateRCInvalidArgument(t *testing.T) {
+	fixture := consulutil.NewFixture(t)
+	defer fixture.Stop()
+	server := newTestServer(fixture)
+
+	valid := rcstore_protos.CreateRCRequest{
+		Manifest:         testManifestString(t),
+		AvailabilityZone: "west",
+		ClusterName:      "some_cluster",
+	}
+	for name, mutate := range map[string]func(*rcstore_protos.CreateRCRequest){
+		"no zone":          func(req *rcstore_protos.CreateRCRequest) { req.AvailabilityZone = "" },
+		"no cluster":       func(req *rcstore_protos.CreateRCRequest) { req.ClusterName = "" },
+		"bad manifest":     func(req *rcstore_protos.CreateRCRequest) { req.Manifest = "{{{" },
+		"bad selector":     func(req *rcstore_protos.CreateRCRequest) { req.NodeSelector = "a in (" },
+		"unknown strategy": func(req *rcstore_protos.CreateRCRequest) { req.AllocationStrategy = "random" },
+	} {
+		req := valid
+		mutate(&req)
+		_, err := server.CreateRC(context.Background(), &req)
+		if grpc.Code(err) != codes.InvalidArgument {
+			t.Errorf("%s: expected InvalidArgument, got %s", name, err)
+		}
+	}
+}
+
+func TestUpdateAndDeleteRC(t *testing.T) {
+	fixture := consulutil.NewFixture(t)
+	defer fixture.Stop()
+	server := newTestServer(fixture)
+	rcID := createTestRC(t, server)
+	ctx := context.Background()
+
+	_, err := server.SetDesiredReplicas(ctx, &rcstore_protos.SetDesiredReplicasRequest{RcId: rcID.String(), Replicas: 3})
+	if err != nil {
+		t.Fatalf("unexpected error setting replicas: %s", err)
+	}
+	_, err = server.DisableRC(ctx, &rcstore_protos.DisableRCRequest{RcId: rcID.String()})
+	if err != nil {
+		t.Fatalf("unexpected error disabling RC: %s", err)
+	}
+	resp, err := server.GetRC(ctx, &rcstore_protos.GetRCRequest{RcId: rcID.String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Rc.ReplicasDesired != 3 || !resp.Rc.Disabled {
+		t.Errorf("expected a disabled RC with 3 replicas, got %+v", resp.Rc)
+	}
+
+	_, err = server.EnableRC(ctx, &rcstore_protos.EnableRCRequest{RcId: rcID.String()})
+	if err != nil {
+		t.Fatalf("unexpected error enabling RC: %s", err)
+	}
+	resp, err = server.GetRC(ctx, &rcstore_protos.GetRCRequest{RcId: rcID.String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Rc.Disabled {
+		t.Error("expected the RC to be enabled")
+	}
+
+	_, err = server.SetDesiredReplicas(ctx, &rcstore_protos.SetDesiredReplicasRequest{RcId: rcID.String(), Replicas: -1})
+	if grpc.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument for a negative replica count, got %s", err)
+	}
+	_, err = server.EnableRC(ctx, &rcstore_protos.EnableRCRequest{RcId: "nonexistent"})
+	if grpc.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound enabling a missing RC, got %s", err)
+	}
+
+	_, err = server.DeleteRC(ctx, &rcstore_protos.DeleteRCRequest{RcId: rcID.String()})
+	if grpc.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expected FailedPrecondition deleting an RC with replicas, got %s", err)
+	}
+	_, err = server.DeleteRC(ctx, &rcstore_protos.DeleteRCRequest{RcId: rcID.String(), Force: true})
+	if err != nil {
+		t.Fatalf("unexpected error force deleting RC: %s", err)
+	}
+	_, err = server.DeleteRC(ctx, &rcstore_protos.DeleteRCRequest{RcId: rcID.String()})
+	if grpc.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound deleting a deleted RC, got %s", err)
+	}
+}
+
+type fakeWatchRCServer struct {
+	*testutil.FakeServerStream
+	rcs chan *rcstore_protos.RC
+}
+
+func (f fakeWatchRCServer) Send(resp *rcstore_protos.WatchRCResponse) error {
+	f.rcs <- resp.Rc
+	return nil
+}
+
+func TestWatchRC(t *testing.T) {
+	fixture := consulutil.NewFixture(t)
+	defer fixture.Stop()
+	server := newTestServer(fixture)
+	rcID := createTestRC(t, server)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := fakeWatchRCServer{
+		FakeServerStream: testutil.NewFakeServerStream(ctx),
+		rcs:              make(chan *rcstore_protos.RC),
+	}
+	err := server.WatchRC(&rcstore_protos.WatchRCRequest{RcId: "nonexistent"}, stream)
+	if grpc.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound watching a missing RC, got %s", err)
+	}
+
+	watchErr := make(chan error)
+	go func() {
+		watchErr <- server.WatchRC(&rcstore_protos.WatchRCRequest{RcId: rcID.String()}, stream)
+	}()
+
+	expectReplicas := func(replicas int64) {
+		for {
+			select {
+			case rc := <-stream.rcs:
+				if rc.ReplicasDesired == replicas {
+					return
+				}
+			case err := <-watchErr:
+				t.Fatalf("watch ended early: %s", err)
+			case <-time.After(5 * time.Second):
+				t.Fatalf("timed out waiting for an RC with %d replicas", replicas)
+			}
+		}
+	}
+	expectReplicas(0)
+
+	_, err = server.SetDesiredReplicas(ctx, &rcstore_protos.SetDesiredReplicasRequest{RcId: rcID.String(), Replicas: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectReplicas(2)
+
+	cancel()
+	select {
+	case err := <-watchErr:
+		if err != nil {
+			t.Errorf("expected the watch to end cleanly when canceled, got %s", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("watch did not end when canceled")
+	}
+}
+
+func TestProtoRCRoundTrip(t *testing.T) {
+	nodeSelector, err := klabels.Parse("size=large")
+	if err != nil {
+		t.Fatal(err)
+	}
+	builder := manifest.NewBuilder()
+	builder.SetID("some_pod")
+	rc := fields.RC{
+		ID:                 "some_rc",
+		Manifest:           builder.GetManifest(),
+		NodeSelector:       nodeSelector,
+		PodLabels:          klabels.Set{"team": "storage"},
+		ReplicasDesired:    4,
+		AllocationStrategy: fields.StaticStrategy,
+		SpreadConstraints:  []fields.SpreadConstraint{{TopologyKey: "zone", MaxSkew: 1}},
+		Autoscale: &fields.AutoscalePolicy{
+			MinReplicas: 2,
+			MaxReplicas: 8,
+			Target:      0.5,
+			Cooldown:    time.Minute,
+			Metric:      fields.MetricSource{Type: fields.PodMetricSource, Path: "/load", Port: 8080},
+		},
+		ScheduleZones: &fields.ZoneFilter{Label: "zone", Values: []string{"a", "b"}},
+	}
+
+	rcProto, err := RCToProto(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ProtoToRC(rcProto)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gotJSON, err := json.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedJSON, err := json.Marshal(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(gotJSON) != string(expectedJSON) {
+		t.Errorf("RC did not survive the round trip: expected %s, got %s", expectedJSON, gotJSON)
+	}
+}
+
+func newTestServer(fixture consulutil.Fixture) Store {
+	applicator := labels.NewConsulApplicator(fixture.Client, 0, 0)
+	statusStore := statusstore.NewConsul(fixture.Client)
+	return NewServer(
+		rcstatus.NewConsul(statusStore, consul.RCStatusNamespace),
+		rcstore.NewConsul(fixture.Client, applicator, 0),
+		rollstore.NewConsul(fixture.Client, applicator, &logging.DefaultLogger),
+		rollstatus.NewConsul(statusStore, consul.RollStatusNamespace),
+		fixture.Client.KV(),
+	)
+}
+
+func testManifestString(t *testing.T) string {
+	builder := manifest.NewBuilder()
+	builder.SetID("some_pod")
+	bytes, err := builder.GetManifest().Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(bytes)
+}
+
+func createTestRC(t *testing.T, server Store) fields.ID {
+	resp, err := server.CreateRC(context.Background(), &rcstore_protos.CreateRCRequest{
+		Manifest:         testManifestString(t),
+		AvailabilityZone: "west",
+		ClusterName:      "some_cluster",
+	})
+	if err != nil {
+		t.Fatalf("could not create test RC: %s", err)
+	}
+	return fields.ID(resp.Rc.Id)
+}
@@ -0,0 +1,367 @@
+package rcstore
+
+import (
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	klabels "k8s.io/kubernetes/pkg/labels"
+
+	rcstore_protos "github.com/square/p2/pkg/grpc/rcstore/protos"
+	rc_fields "github.com/square/p2/pkg/rc/fields"
+	roll_fields "github.com/square/p2/pkg/roll/fields"
+	"github.com/square/p2/pkg/store/consul"
+	"github.com/square/p2/pkg/store/consul/rollstore"
+	"github.com/square/p2/pkg/store/consul/statusstore"
+	"github.com/square/p2/pkg/store/consul/statusstore/rollstatus"
+	"github.com/square/p2/pkg/store/consul/transaction"
+)
+
+type RollStore interface {
+	Get(id roll_fields.ID) (roll_fields.Update, error)
+	List() ([]roll_fields.Update, error)
+	CreateRollingUpdateFromExistingRCs(ctx context.Context, u roll_fields.Update, newRCLabels klabels.Set, rollLabels klabels.Set) (roll_fields.Update, error)
+	Delete(ctx context.Context, id roll_fields.ID) error
+	Watch(quit <-chan struct{}, jitterWindow time.Duration) (<-chan []roll_fields.Update, <-chan error)
+}
+
+type RollStatusStore interface {
+	Get(id roll_fields.ID) (rollstatus.Status, *api.QueryMeta, error)
+	Watch(id roll_fields.ID, waitIndex uint64) (rollstatus.Status, *api.QueryMeta, error)
+}
+
+func (s Store) ScheduleRollingUpdate(ctx context.Context, req *rcstore_protos.ScheduleRollingUpdateRequest) (*rcstore_protos.ScheduleRollingUpdateResponse, error) {
+	if req.RollingUpdate == nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, "rolling_update must be set")
+	}
+	update := ProtoToRollingUpdate(req.RollingUpdate)
+	if update.OldRC == "" || update.NewRC == "" {
+		return nil, grpc.Errorf(codes.InvalidArgument, "old_rc_id and new_rc_id must be set")
+	}
+	if update.OldRC == update.NewRC {
+		return nil, grpc.Errorf(codes.InvalidArgument, "old_rc_id and new_rc_id must differ")
+	}
+	if update.DesiredReplicas < 0 || update.MinimumReplicas < 0 {
+		return nil, grpc.Errorf(codes.InvalidArgument, "desired_replicas and minimum_replicas must not be negative")
+	}
+	if update.CanaryReplicas < 0 || (update.CanaryReplicas > 0 && update.CanaryReplicas >= update.DesiredReplicas) {
+		return nil, grpc.Errorf(codes.InvalidArgument, "canary_replicas must be positive and less than desired_replicas")
+	}
+
+	// The store locks the RCs without checking that they exist
+	for _, rcID := range []rc_fields.ID{update.OldRC, update.NewRC} {
+		_, err := s.rcStore.Get(rcID)
+		if err != nil {
+			return nil, convertRCStoreError(rcID, "schedule a rolling update for", err)
+		}
+	}
+
+	trxctx, cancelFunc := transaction.New(ctx)
+	defer cancelFunc()
+	update, err := s.rollStore.CreateRollingUpdateFromExistingRCs(trxctx, update, nil, nil)
+	if err != nil {
+		if _, ok := err.(*rollstore.ConflictingRUError); ok {
+			return nil, grpc.Errorf(codes.FailedPrecondition, "could not schedule rolling update: %s", err)
+		}
+		if consul.IsAlreadyLocked(err) {
+			// Another rolling update is being scheduled for one of the
+			// RCs. Retrying will either succeed or find the conflict
+			return nil, grpc.Errorf(codes.Aborted, "could not schedule rolling update: %s", err)
+		}
+		return nil, grpc.Errorf(codes.Unavailable, "could not schedule rolling update: %s", err)
+	}
+
+	err = transaction.MustCommit(trxctx, s.txner)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Unavailable, "could not schedule rolling update: %s", err)
+	}
+
+	return &rcstore_protos.ScheduleRollingUpdateResponse{
+		RollingUpdate: RollingUpdateToProto(update),
+	}, nil
+}
+
+func (s Store) ListRollingUpdates(_ context.Context, _ *rcstore_protos.ListRollingUpdatesRequest) (*rcstore_protos.ListRollingUpdatesResponse, error) {
+	updates, err := s.rollStore.List()
+	if err != nil {
+		return nil, grpc.Errorf(codes.Unavailable, "could not list rolling updates: %s", err)
+	}
+
+	return &rcstore_protos.ListRollingUpdatesResponse{
+		RollingUpdates: rollingUpdatesToProto(updates),
+	}, nil
+}
+
+func (s Store) DeleteRollingUpdate(ctx context.Context, req *rcstore_protos.DeleteRollingUpdateRequest) (*rcstore_protos.DeleteRollingUpdateResponse, error) {
+	if req.RollId == "" {
+		return nil, grpc.Errorf(codes.InvalidArgument, "roll_id must be set")
+	}
+	rollID := roll_fields.ID(req.RollId)
+
+	// Get returns an empty update rather than an error when there is none
+	update, err := s.rollStore.Get(rollID)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Unavailable, "could not delete rolling update %s: %s", rollID, err)
+	}
+	if update.NewRC == "" {
+		return nil, grpc.Errorf(codes.NotFound, "no rolling update with id %s was found", rollID)
+	}
+
+	trxctx, cancelFunc := transaction.New(ctx)
+	defer cancelFunc()
+	err = s.rollStore.Delete(trxctx, rollID)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Unavailable, "could not delete rolling update %s: %s", rollID, err)
+	}
+
+	err = transaction.MustCommit(trxctx, s.txner)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Unavailable, "could not delete rolling update %s: %s", rollID, err)
+	}
+	return &rcstore_protos.DeleteRollingUpdateResponse{}, nil
+}
+
+func (s Store) WatchRollingUpdates(_ *rcstore_protos.WatchRollingUpdatesRequest, stream rcstore_protos.P2RCStore_WatchRollingUpdatesServer) error {
+	clientCancel := stream.Context().Done()
+	updatesCh, errCh := s.rollStore.Watch(clientCancel, 0)
+	for {
+		select {
+		case <-clientCancel:
+			return nil
+		case updates, ok := <-updatesCh:
+			if !ok {
+				return nil
+			}
+			err := stream.Send(&rcstore_protos.WatchRollingUpdatesResponse{
+				RollingUpdates: rollingUpdatesToProto(updates),
+			})
+			if err != nil {
+				return err
+			}
+		case err, ok := <-errCh:
+			if !ok {
+				return nil
+			}
+			return grpc.Errorf(codes.Unavailable, "could not watch rolling updates: %s", err)
+		}
+	}
+}
+
+func (s Store) GetRollStatus(_ context.Context, req *rcstore_protos.GetRollStatusRequest) (*rcstore_protos.RollStatus, error) {
+	if req.RollId == "" {
+		return nil, grpc.Errorf(codes.InvalidArgument, "roll_id must be set")
+	}
+	rollID := roll_fields.ID(req.RollId)
+
+	status, _, err := s.rollStatusStore.Get(rollID)
+	if err != nil {
+		return nil, convertRollStatusStoreError(rollID, err)
+	}
+	return RollStatusToProto(status), nil
+}
+
+type rollStatusResult struct {
+	status rollstatus.Status
+	err    error
+}
+
+func (s Store) WatchRollStatus(req *rcstore_protos.WatchRollStatusRequest, stream rcstore_protos.P2RCStore_WatchRollStatusServer) error {
+	if req.RollId == "" {
+		return grpc.Errorf(codes.InvalidArgument, "roll_id must be set")
+	}
+	rollID := roll_fields.ID(req.RollId)
+
+	// Like WatchStatus, send the current status and then watch from the
+	// index it was fetched at
+	status, queryMeta, err := s.rollStatusStore.Get(rollID)
+	if err != nil {
+		return convertRollStatusStoreError(rollID, err)
+	}
+	err = stream.Send(RollStatusToProto(status))
+	if err != nil {
+		return err
+	}
+	waitIndex := queryMeta.LastIndex
+
+	clientCancel := stream.Context().Done()
+	resultCh := make(chan rollStatusResult)
+	innerQuit := make(chan struct{})
+	defer close(innerQuit)
+	go func() {
+		defer close(resultCh)
+		for {
+			status, queryMeta, err := s.rollStatusStore.Watch(rollID, waitIndex)
+			if queryMeta != nil {
+				if queryMeta.LastIndex == waitIndex && err == nil {
+					// The watch timed out without a change
+					continue
+				}
+				waitIndex = queryMeta.LastIndex
+			}
+
+			select {
+			case resultCh <- rollStatusResult{status: status, err: err}:
+			case <-innerQuit:
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-clientCancel:
+			return nil
+		case result, ok := <-resultCh:
+			if !ok {
+				return nil
+			}
+			if result.err != nil {
+				return convertRollStatusStoreError(rollID, result.err)
+			}
+			err := stream.Send(RollStatusToProto(result.status))
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func convertRollStatusStoreError(rollID roll_fields.ID, err error) error {
+	if statusstore.IsNoStatus(err) {
+		return grpc.Errorf(codes.NotFound, "no status found for rolling update %s", rollID)
+	}
+	return grpc.Errorf(codes.Unavailable, "could not fetch status for rolling update %s: %s", rollID, err)
+}
+
+func rollingUpdatesToProto(updates []roll_fields.Update) []*rcstore_protos.RollingUpdate {
+	out := make([]*rcstore_protos.RollingUpdate, len(updates))
+	for i, update := range updates {
+		out[i] = RollingUpdateToProto(update)
+	}
+	return out
+}
+
+func RollingUpdateToProto(update roll_fields.Update) *rcstore_protos.RollingUpdate {
+	proto := &rcstore_protos.RollingUpdate{
+		OldRcId:         update.OldRC.String(),
+		NewRcId:         update.NewRC.String(),
+		DesiredReplicas: int64(update.DesiredReplicas),
+		MinimumReplicas: int64(update.MinimumReplicas),
+		LeaveOld:        update.LeaveOld,
+		RollDelay:       int64(update.RollDelay),
+		CanaryReplicas:  int64(update.CanaryReplicas),
+		CanaryBake:      int64(update.CanaryBake),
+		Paused:          update.Paused,
+		PauseReason:     update.PauseReason,
+		PausedBy:        update.PausedBy,
+	}
+	if policy := update.FailurePolicy; policy != nil {
+		proto.FailurePolicy = &rcstore_protos.FailurePolicy{
+			ProgressDeadline:     int64(policy.ProgressDeadline),
+			MaxUnhealthyDuration: int64(policy.MaxUnhealthyDuration),
+			Rollback:             policy.Rollback,
+		}
+		if policy.MaxUnhealthy != nil {
+			proto.FailurePolicy.MaxUnhealthy = &rcstore_protos.Limit{
+				Value: int64(*policy.MaxUnhealthy),
+			}
+		}
+	}
+	if sequence := update.ZoneSequence; sequence != nil {
+		proto.ZoneSequence = &rcstore_protos.ZoneSequence{
+			Label:          sequence.Label,
+			Order:          sequence.Order,
+			Pause:          int64(sequence.Pause),
+			RequireHealthy: sequence.RequireHealthy,
+		}
+	}
+	return proto
+}
+
+func ProtoToRollingUpdate(proto *rcstore_protos.RollingUpdate) roll_fields.Update {
+	update := roll_fields.Update{
+		OldRC:           rc_fields.ID(proto.OldRcId),
+		NewRC:           rc_fields.ID(proto.NewRcId),
+		DesiredReplicas: int(proto.DesiredReplicas),
+		MinimumReplicas: int(proto.MinimumReplicas),
+		LeaveOld:        proto.LeaveOld,
+		RollDelay:       time.Duration(proto.RollDelay),
+		CanaryReplicas:  int(proto.CanaryReplicas),
+		CanaryBake:      time.Duration(proto.CanaryBake),
+		Paused:          proto.Paused,
+		PauseReason:     proto.PauseReason,
+		PausedBy:        proto.PausedBy,
+	}
+	if policy := proto.FailurePolicy; policy != nil {
+		update.FailurePolicy = &roll_fields.FailurePolicy{
+			ProgressDeadline:     time.Duration(policy.ProgressDeadline),
+			MaxUnhealthyDuration: time.Duration(policy.MaxUnhealthyDuration),
+			Rollback:             policy.Rollback,
+		}
+		if policy.MaxUnhealthy != nil {
+			maxUnhealthy := int(policy.MaxUnhealthy.Value)
+			update.FailurePolicy.MaxUnhealthy = &maxUnhealthy
+		}
+	}
+	if sequence := proto.ZoneSequence; sequence != nil {
+		update.ZoneSequence = &roll_fields.ZoneSequence{
+			Label:          sequence.Label,
+			Order:          sequence.Order,
+			Pause:          time.Duration(sequence.Pause),
+			RequireHealthy: sequence.RequireHealthy,
+		}
+	}
+	return update
+}
+
+func RollStatusToProto(status rollstatus.Status) *rcstore_protos.RollStatus {
+	return &rcstore_protos.RollStatus{
+		OldRc:            rollCountsToProto(status.OldRC),
+		NewRc:            rollCountsToProto(status.NewRC),
+		Step:             string(status.Step),
+		BlockingReason:   status.BlockingReason,
+		StartTime:        timeToProto(&status.StartTime),
+		LastProgressTime: timeToProto(&status.LastProgressTime),
+	}
+}
+
+func ProtoToRollStatus(proto *rcstore_protos.RollStatus) rollstatus.Status {
+	return rollstatus.Status{
+		OldRC:            protoToRollCounts(proto.OldRc),
+		NewRC:            protoToRollCounts(proto.NewRc),
+		Step:             rollstatus.Step(proto.Step),
+		BlockingReason:   proto.BlockingReason,
+		StartTime:        protoToTime(proto.StartTime),
+		LastProgressTime: protoToTime(proto.LastProgressTime),
+	}
+}
+
+func rollCountsToProto(counts rollstatus.Counts) *rcstore_protos.RollCounts {
+	return &rcstore_protos.RollCounts{
+		Desired:   int64(counts.Desired),
+		Current:   int64(counts.Current),
+		Real:      int64(counts.Real),
+		Healthy:   int64(counts.Healthy),
+		Unhealthy: int64(counts.Unhealthy),
+		Unknown:   int64(counts.Unknown),
+	}
+}
+
+func protoToRollCounts(proto *rcstore_protos.RollCounts) rollstatus.Counts {
+	if proto == nil {
+		return rollstatus.Counts{}
+	}
+	return rollstatus.Counts{
+		Desired:   int(proto.Desired),
+		Current:   int(proto.Current),
+		Real:      int(proto.Real),
+		Healthy:   int(proto.Healthy),
+		Unhealthy: int(proto.Unhealthy),
+		Unknown:   int(proto.Unknown),
+	}
+}
@@ -0,0 +1,249 @@
+// +build !race
+
+package rcstore
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
+	rcstore_protos "github.com/square/p2/pkg/grpc/rcstore/protos"
+	"github.com/square/p2/pkg/grpc/testutil"
+	roll_fields "github.com/square/p2/pkg/roll/fields"
+	"github.com/square/p2/pkg/store/consul"
+	"github.com/square/p2/pkg/store/consul/consulutil"
+	"github.com/square/p2/pkg/store/consul/statusstore"
+	"github.com/square/p2/pkg/store/consul/statusstore/rollstatus"
+)
+
+func TestScheduleAndDeleteRollingUpdate(t *testing.T) {
+	fixture := consulutil.NewFixture(t)
+	defer fixture.Stop()
+	server := newTestServer(fixture)
+	oldRC := createTestRC(t, server)
+	newRC := createTestRC(t, server)
+	ctx := context.Background()
+
+	update := &rcstore_protos.RollingUpdate{
+		OldRcId:         oldRC.String(),
+		NewRcId:         newRC.String(),
+		DesiredReplicas: 3,
+		MinimumReplicas: 2,
+	}
+	resp, err := server.ScheduleRollingUpdate(ctx, &rcstore_protos.ScheduleRollingUpdateRequest{RollingUpdate: update})
+	if err != nil {
+		t.Fatalf("unexpected error scheduling rolling update: %s", err)
+	}
+	if resp.RollingUpdate.NewRcId != newRC.String() || resp.RollingUpdate.DesiredReplicas != 3 {
+		t.Errorf("unexpected rolling update scheduled: %+v", resp.RollingUpdate)
+	}
+
+	// The RC locks taken by the first call are released asynchronously, so
+	// retry until the conflict is reported rather than the locks
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		_, err = server.ScheduleRollingUpdate(ctx, &rcstore_protos.ScheduleRollingUpdateRequest{RollingUpdate: update})
+		if grpc.Code(err) != codes.Aborted || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if grpc.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expected FailedPrecondition scheduling a conflicting rolling update, got %s", err)
+	}
+
+	listResp, err := server.ListRollingUpdates(ctx, &rcstore_protos.ListRollingUpdatesRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error listing rolling updates: %s", err)
+	}
+	if len(listResp.RollingUpdates) != 1 || listResp.RollingUpdates[0].OldRcId != oldRC.String() {
+		t.Errorf("expected the scheduled rolling update to be listed, got %+v", listResp.RollingUpdates)
+	}
+
+	_, err = server.DeleteRollingUpdate(ctx, &rcstore_protos.DeleteRollingUpdateRequest{RollId: newRC.String()})
+	if err != nil {
+		t.Fatalf("unexpected error deleting rolling update: %s", err)
+	}
+	_, err = server.DeleteRollingUpdate(ctx, &rcstore_protos.DeleteRollingUpdateRequest{RollId: newRC.String()})
+	if grpc.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound deleting a deleted rolling update, got %s", err)
+	}
+}
+
+func TestScheduleRollingUpdateInvalid(t *testing.T) {
+	fixture := consulutil.NewFixture(t)
+	defer fixture.Stop()
+	server := newTestServer(fixture)
+	oldRC := createTestRC(t, server)
+	ctx := context.Background()
+
+	for name, update := range map[string]*rcstore_protos.RollingUpdate{
+		"no new RC": {OldRcId: oldRC.String(), DesiredReplicas: 1},
+		"same RCs":  {OldRcId: oldRC.String(), NewRcId: oldRC.String(), DesiredReplicas: 1},
+		"too many canaries": {
+			OldRcId:         oldRC.String(),
+			NewRcId:         "other",
+			DesiredReplicas: 2,
+			CanaryReplicas:  2,
+		},
+	} {
+		_, err := server.ScheduleRollingUpdate(ctx, &rcstore_protos.ScheduleRollingUpdateRequest{RollingUpdate: update})
+		if grpc.Code(err) != codes.InvalidArgument {
+			t.Errorf("%s: expected InvalidArgument, got %s", name, err)
+		}
+	}
+
+	_, err := server.ScheduleRollingUpdate(ctx, &rcstore_protos.ScheduleRollingUpdateRequest{
+		RollingUpdate: &rcstore_protos.RollingUpdate{OldRcId: oldRC.String(), NewRcId: "nonexistent", DesiredReplicas: 1},
+	})
+	if grpc.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound scheduling a rolling update to a missing RC, got %s", err)
+	}
+}
+
+type fakeWatchRollingUpdatesServer struct {
+	*testutil.FakeServerStream
+	updates chan []*rcstore_protos.RollingUpdate
+}
+
+func (f fakeWatchRollingUpdatesServer) Send(resp *rcstore_protos.WatchRollingUpdatesResponse) error {
+	f.updates <- resp.RollingUpdates
+	return nil
+}
+
+func TestWatchRollingUpdates(t *testing.T) {
+	fixture := consulutil.NewFixture(t)
+	defer fixture.Stop()
+	server := newTestServer(fixture)
+	oldRC := createTestRC(t, server)
+	newRC := createTestRC(t, server)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := fakeWatchRollingUpdatesServer{
+		FakeServerStream: testutil.NewFakeServerStream(ctx),
+		updates:          make(chan []*rcstore_protos.RollingUpdate),
+	}
+	watchErr := make(chan error)
+	go func() {
+		watchErr <- server.WatchRollingUpdates(&rcstore_protos.WatchRollingUpdatesRequest{}, stream)
+	}()
+
+	expectUpdates := func(count int) {
+		for {
+			select {
+			case updates := <-stream.updates:
+				if len(updates) == count {
+					return
+				}
+			case err := <-watchErr:
+				t.Fatalf("watch ended early: %s", err)
+			case <-time.After(5 * time.Second):
+				t.Fatalf("timed out waiting for %d rolling updates", count)
+			}
+		}
+	}
+	expectUpdates(0)
+
+	_, err := server.ScheduleRollingUpdate(ctx, &rcstore_protos.ScheduleRollingUpdateRequest{
+		RollingUpdate: &rcstore_protos.RollingUpdate{OldRcId: oldRC.String(), NewRcId: newRC.String(), DesiredReplicas: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectUpdates(1)
+
+	cancel()
+	select {
+	case err := <-watchErr:
+		if err != nil {
+			t.Errorf("expected the watch to end cleanly when canceled, got %s", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("watch did not end when canceled")
+	}
+}
+
+func TestGetRollStatus(t *testing.T) {
+	fixture := consulutil.NewFixture(t)
+	defer fixture.Stop()
+	server := newTestServer(fixture)
+	rollStatusStore := rollstatus.NewConsul(statusstore.NewConsul(fixture.Client), consul.RollStatusNamespace)
+
+	_, err := server.GetRollStatus(context.Background(), &rcstore_protos.GetRollStatusRequest{RollId: "abc"})
+	if grpc.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound for a missing status, got %s", err)
+	}
+
+	now := time.Now()
+	status := rollstatus.Status{
+		OldRC:            rollstatus.Counts{Desired: 2, Current: 2, Real: 2, Healthy: 2},
+		NewRC:            rollstatus.Counts{Desired: 1, Current: 1, Real: 1, Unhealthy: 1},
+		Step:             rollstatus.StepBlocked,
+		BlockingReason:   "waiting for minimum health",
+		StartTime:        now,
+		LastProgressTime: now,
+	}
+	err = rollStatusStore.Set("abc", status)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := server.GetRollStatus(context.Background(), &rcstore_protos.GetRollStatusRequest{RollId: "abc"})
+	if err != nil {
+		t.Fatalf("unexpected error getting roll status: %s", err)
+	}
+	got := ProtoToRollStatus(resp)
+	if got.OldRC != status.OldRC || got.NewRC != status.NewRC {
+		t.Errorf("counts did not survive the round trip: %+v", got)
+	}
+	if got.Step != rollstatus.StepBlocked || got.BlockingReason != status.BlockingReason {
+		t.Errorf("expected a blocked step, got %s (%s)", got.Step, got.BlockingReason)
+	}
+	if !got.StartTime.Equal(now) || !got.LastProgressTime.Equal(now) {
+		t.Errorf("expected times of %s, got %s and %s", now, got.StartTime, got.LastProgressTime)
+	}
+}
+
+func TestProtoRollingUpdateRoundTrip(t *testing.T) {
+	maxUnhealthy := 0
+	update := roll_fields.Update{
+		OldRC:           "old",
+		NewRC:           "new",
+		DesiredReplicas: 5,
+		MinimumReplicas: 4,
+		LeaveOld:        true,
+		RollDelay:       time.Second,
+		CanaryReplicas:  1,
+		CanaryBake:      time.Minute,
+		FailurePolicy: &roll_fields.FailurePolicy{
+			ProgressDeadline: time.Hour,
+			MaxUnhealthy:     &maxUnhealthy,
+			Rollback:         true,
+		},
+		Paused:      true,
+		PauseReason: "investigating",
+		PausedBy:    "some_user",
+		ZoneSequence: &roll_fields.ZoneSequence{
+			Label:          "zone",
+			Order:          []string{"a", "b"},
+			Pause:          time.Minute,
+			RequireHealthy: true,
+		},
+	}
+
+	got := ProtoToRollingUpdate(RollingUpdateToProto(update))
+	if !reflect.DeepEqual(got, update) {
+		t.Errorf("rolling update did not survive the round trip: expected %+v, got %+v", update, got)
+	}
+
+	update.FailurePolicy.MaxUnhealthy = nil
+	got = ProtoToRollingUpdate(RollingUpdateToProto(update))
+	if got.FailurePolicy.MaxUnhealthy != nil {
+		t.Errorf("expected no unhealthy limit, got %d", *got.FailurePolicy.MaxUnhealthy)
+	}
+}
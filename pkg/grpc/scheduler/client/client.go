@@ -82,7 +82,7 @@ func (c *Client) AllocateNodes(man manifest.Manifest, nodeSelector klabels.Selec
 	return ret, nil
 }
 
-func (c *Client) DeallocateNodes(selector klabels.Selector, nodes []types.NodeName) error {
+func (c *Client) DeallocateNodes(man manifest.Manifest, selector klabels.Selector, nodes []types.NodeName) error {
 	nodeStrings := make([]string, len(nodes))
 	for i, nodeName := range nodes {
 		nodeStrings[i] = nodeName.String()
@@ -91,6 +91,7 @@ func (c *Client) DeallocateNodes(selector klabels.Selector, nodes []types.NodeNa
 	req := &scheduler_protos.DeallocateNodesRequest{
 		NodeSelector:  selector.String(),
 		NodesReleased: nodeStrings,
+		PodId:         man.ID().String(),
 	}
 
 	_, err := c.schedulerClient.DeallocateNodes(context.Background(), req)
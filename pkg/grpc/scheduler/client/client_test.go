@@ -213,7 +213,7 @@ func TestDeallocateNodesHappy(t *testing.T) {
 	}
 
 	selector := klabels.Everything().Add("foo", klabels.EqualsOperator, []string{"bar"})
-	err := client.DeallocateNodes(selector, nodesReleased)
+	err := client.DeallocateNodes(testManifest(), selector, nodesReleased)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -228,6 +228,10 @@ func TestDeallocateNodesHappy(t *testing.T) {
 		t.Errorf("expected node selector in call to be %q but was %q", selector, call.NodeSelector)
 	}
 
+	if call.PodId != "some_pod_id" {
+		t.Errorf("expected pod ID in call to be %q but was %q", "some_pod_id", call.PodId)
+	}
+
 	nodesReleasedString := make([]string, len(nodesReleased))
 	for i, nodeName := range nodesReleased {
 		nodesReleasedString[i] = nodeName.String()
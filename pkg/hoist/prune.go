@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"sort"
 
+	"github.com/square/p2/pkg/artifact"
 	"github.com/square/p2/pkg/util/size"
 )
 
@@ -14,6 +15,11 @@ import (
 // bytes. This method will preserve any directories that are pointed to
 // by the `current` or `last` symlinks. Installations will be removed from
 // oldest to newest.
+//
+// Artifacts kept for delta downloads (see artifact.KeptArtifactInstall) are
+// counted and removed together with the install they were kept for, so the
+// artifacts of `current` and `last` are preserved too. A kept artifact whose
+// install is already gone is pruned on its own.
 func (hl *Launchable) Prune(maxSize size.ByteCount) error {
 	curTarget, err := os.Readlink(hl.CurrentDir())
 	if os.IsNotExist(err) {
@@ -31,13 +37,34 @@ func (hl *Launchable) Prune(maxSize size.ByteCount) error {
 	}
 	lastTarget = filepath.Base(lastTarget)
 
-	installs, err := ioutil.ReadDir(hl.AllInstallsDir())
+	entries, err := ioutil.ReadDir(hl.AllInstallsDir())
 	if os.IsNotExist(err) {
 		return nil
 	} else if err != nil {
 		return err
 	}
 
+	var installs []os.FileInfo
+	keptArtifacts := map[string][]os.FileInfo{}
+	for _, e := range entries {
+		if install, ok := artifact.KeptArtifactInstall(e.Name()); ok && !e.IsDir() {
+			keptArtifacts[install] = append(keptArtifacts[install], e)
+			continue
+		}
+		installs = append(installs, e)
+	}
+	installNames := map[string]bool{}
+	for _, i := range installs {
+		installNames[i.Name()] = true
+	}
+	// kept artifacts without an install are pruned like installs
+	for install, artifacts := range keptArtifacts {
+		if !installNames[install] {
+			installs = append(installs, artifacts...)
+			delete(keptArtifacts, install)
+		}
+	}
+
 	var totalSize size.ByteCount = 0
 
 	installSizes := map[string]size.ByteCount{}
@@ -47,6 +74,9 @@ func (hl *Launchable) Prune(maxSize size.ByteCount) error {
 		if err != nil {
 			return err
 		}
+		for _, kept := range keptArtifacts[i.Name()] {
+			installSize += size.ByteCount(kept.Size())
+		}
 		totalSize += size.ByteCount(installSize)
 		installSizes[i.Name()] = installSize
 	}
@@ -68,6 +98,12 @@ func (hl *Launchable) Prune(maxSize size.ByteCount) error {
 		if err != nil {
 			return err
 		}
+		for _, kept := range keptArtifacts[i.Name()] {
+			err = os.Remove(filepath.Join(hl.AllInstallsDir(), kept.Name()))
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
 		totalSize = totalSize - size.ByteCount(installSize)
 	}
 
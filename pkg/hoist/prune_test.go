@@ -144,3 +144,33 @@ func TestPruneIgnoresCurrentAndLast(t *testing.T) {
 		assertShouldBePruned(t, hl, "third")
 	})
 }
+
+func TestPruneRemovesKeptArtifactsWithTheirInstalls(t *testing.T) {
+	launchableWithInstallations(t, []testInstall{
+		{"current", time.Now().Add(-1000 * time.Hour), 10 * size.Kibibyte},
+		{"second", time.Now().Add(-800 * time.Hour), 10 * size.Kibibyte},
+		{"third", time.Now().Add(-600 * time.Hour), 10 * size.Kibibyte},
+	}, func(hl *Launchable) {
+		keep := func(name string, modTime time.Time) {
+			path := filepath.Join(hl.AllInstallsDir(), name)
+			err := ioutil.WriteFile(path, make([]byte, 10*1024), 0644)
+			Assert(t).IsNil(err, "Should not have erred writing a kept artifact")
+			err = os.Chtimes(path, modTime, modTime)
+			Assert(t).IsNil(err, "Should not have erred setting a kept artifact's mtime")
+		}
+		keep("current.abc123.artifact", time.Now().Add(-1000*time.Hour))
+		keep("second.def456.artifact", time.Now().Add(-800*time.Hour))
+		// the install this was kept for has already been pruned
+		keep("zeroth.789abc.artifact", time.Now().Add(-1200*time.Hour))
+
+		Assert(t).IsNil(hl.Prune(30*size.Kibibyte), "Should not have erred when pruning")
+
+		assertShouldExist(t, hl, "current")
+		_, err := os.Stat(filepath.Join(hl.AllInstallsDir(), "current.abc123.artifact"))
+		Assert(t).IsNil(err, "Should have kept the artifact of the current install")
+		assertShouldBePruned(t, hl, "zeroth.789abc.artifact")
+		assertShouldBePruned(t, hl, "second")
+		assertShouldBePruned(t, hl, "second.def456.artifact")
+		assertShouldExist(t, hl, "third")
+	})
+}
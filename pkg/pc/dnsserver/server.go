@@ -0,0 +1,356 @@
+/*
+Package dnsserver answers DNS queries for the members of pod clusters. Its
+Server is both a dns.Handler and a pcstore.ConcreteSyncer: the pod cluster
+store's WatchAndSync keeps its view of each pod cluster's labeled pods up to
+date, and a health watch per pod ID decides which of them are returned.
+
+A pod cluster is named <cluster>.<pod_id>.<az>.<domain>, e.g.
+production.slug.us-west.p2. A queries return the addresses of its members'
+nodes, and SRV queries return one record per member and port. SRV queries for
+_<port>._tcp.<cluster>.<pod_id>.<az>.<domain> return only the named port.
+Ports are found the same way as for load balancer config; see
+lbconfig.PodPorts.
+*/
+package dnsserver
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"github.com/square/p2/pkg/health"
+	"github.com/square/p2/pkg/health/checker"
+	"github.com/square/p2/pkg/labels"
+	"github.com/square/p2/pkg/logging"
+	"github.com/square/p2/pkg/pc/fields"
+	"github.com/square/p2/pkg/pc/lbconfig"
+	"github.com/square/p2/pkg/store/consul/pcstore"
+	"github.com/square/p2/pkg/types"
+)
+
+const healthWatchDelay = 1 * time.Second
+
+// LookupIP resolves a node name to its addresses, e.g. net.LookupIP
+type LookupIP func(host string) ([]net.IP, error)
+
+type member struct {
+	node  types.NodeName
+	ports map[string]int
+	ips   []net.IP
+}
+
+type cluster struct {
+	id                  fields.ID
+	podID               types.PodID
+	name                string
+	minHealthPercentage fields.MinHealthPercentage
+	members             []member
+}
+
+// podHealth holds the latest health results of a pod ID, shared by all the
+// pod clusters of that pod ID
+type podHealth struct {
+	cancel   context.CancelFunc
+	clusters int
+
+	// nil until the first results arrive
+	results map[types.NodeName]health.Result
+}
+
+type Server struct {
+	domain        string
+	ttl           uint32
+	manifests     lbconfig.ManifestStore
+	healthChecker checker.HealthChecker
+	lookupIP      LookupIP
+	logger        logging.Logger
+
+	mu       sync.RWMutex
+	clusters map[fields.ID]*cluster
+	names    map[string]*cluster
+	health   map[types.PodID]*podHealth
+}
+
+var _ dns.Handler = &Server{}
+var _ pcstore.ConcreteSyncer = &Server{}
+
+// NewServer returns a server answering for names under domain, e.g. "p2",
+// with answers cached by clients for ttl
+func NewServer(
+	domain string,
+	ttl time.Duration,
+	manifests lbconfig.ManifestStore,
+	healthChecker checker.HealthChecker,
+	lookupIP LookupIP,
+	logger logging.Logger,
+) *Server {
+	return &Server{
+		domain:        strings.ToLower(dns.Fqdn(domain)),
+		ttl:           uint32(ttl.Seconds()),
+		manifests:     manifests,
+		healthChecker: healthChecker,
+		lookupIP:      lookupIP,
+		logger:        logger,
+		clusters:      make(map[fields.ID]*cluster),
+		names:         make(map[string]*cluster),
+		health:        make(map[types.PodID]*podHealth),
+	}
+}
+
+// Name returns the DNS name of a pod cluster
+func (s *Server) Name(pc *fields.PodCluster) string {
+	return strings.ToLower(fmt.Sprintf("%s.%s.%s.%s", pc.Name, pc.PodID, pc.AvailabilityZone, s.domain))
+}
+
+// SyncCluster replaces the members of the pod cluster. Node addresses are
+// resolved here rather than for each query.
+func (s *Server) SyncCluster(pc *fields.PodCluster, labeledPods []labels.Labeled) error {
+	synced := &cluster{
+		id:                  pc.ID,
+		podID:               pc.PodID,
+		name:                s.Name(pc),
+		minHealthPercentage: pc.MinHealthPercentage,
+	}
+	for _, pod := range labeledPods {
+		node, podID, err := labels.NodeAndPodIDFromPodLabel(pod)
+		if err != nil {
+			return err
+		}
+		ports, err := lbconfig.PodPorts(s.manifests, pc, node, podID)
+		if err != nil {
+			return err
+		}
+		ips, err := s.lookupIP(node.String())
+		if err != nil {
+			s.logger.WithError(err).WithField("node", node).Warnln("Could not resolve node, it will only be returned in SRV answers")
+		}
+		synced.members = append(synced.members, member{
+			node:  node,
+			ports: ports,
+			ips:   ips,
+		})
+	}
+	sort.Slice(synced.members, func(i, j int) bool {
+		return synced.members[i].node < synced.members[j].node
+	})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if previous, ok := s.clusters[pc.ID]; ok {
+		delete(s.names, previous.name)
+	} else {
+		s.watchHealth(pc.PodID)
+	}
+	s.clusters[pc.ID] = synced
+	s.names[synced.name] = synced
+	return nil
+}
+
+// DeleteCluster stops answering for the pod cluster
+func (s *Server) DeleteCluster(id fields.ID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deleted, ok := s.clusters[id]
+	if !ok {
+		return nil
+	}
+	delete(s.clusters, id)
+	delete(s.names, deleted.name)
+
+	watch := s.health[deleted.podID]
+	watch.clusters--
+	if watch.clusters == 0 {
+		watch.cancel()
+		delete(s.health, deleted.podID)
+	}
+	return nil
+}
+
+// GetInitialClusters returns nothing, since the server starts out answering
+// for no pod clusters
+func (s *Server) GetInitialClusters() ([]fields.ID, error) {
+	return nil, nil
+}
+
+func (s *Server) Type() pcstore.ConcreteSyncerType {
+	return "dns"
+}
+
+// Stop ends the health watches of all pod clusters
+func (s *Server) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for podID, watch := range s.health {
+		watch.cancel()
+		delete(s.health, podID)
+	}
+}
+
+// watchHealth starts watching the health of podID if no other pod cluster
+// has. Must be called with mu held.
+func (s *Server) watchHealth(podID types.PodID) {
+	if watch, ok := s.health[podID]; ok {
+		watch.clusters++
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	watch := &podHealth{
+		cancel:   cancel,
+		clusters: 1,
+	}
+	s.health[podID] = watch
+
+	resultCh := make(chan map[types.NodeName]health.Result)
+	errCh := make(chan error)
+	go func() {
+		s.healthChecker.WatchService(ctx, podID.String(), resultCh, errCh, healthWatchDelay)
+		close(errCh)
+	}()
+	go func() {
+		for err := range errCh {
+			s.logger.WithError(err).WithField("pod_id", podID).Warnln("Could not watch health")
+		}
+	}()
+	go func() {
+		for results := range resultCh {
+			s.mu.Lock()
+			watch.results = results
+			s.mu.Unlock()
+		}
+	}()
+}
+
+// ServeDNS answers A and SRV queries for pod clusters
+func (s *Server) ServeDNS(w dns.ResponseWriter, req *dns.Msg) {
+	resp := new(dns.Msg)
+	resp.SetReply(req)
+	resp.Authoritative = true
+
+	s.mu.RLock()
+	for _, question := range req.Question {
+		s.answer(resp, question)
+	}
+	s.mu.RUnlock()
+
+	err := w.WriteMsg(resp)
+	if err != nil {
+		s.logger.WithError(err).Warnln("Could not write DNS response")
+	}
+}
+
+// answer adds the answer to a question to resp. Must be called with mu
+// held for reading.
+func (s *Server) answer(resp *dns.Msg, question dns.Question) {
+	name := strings.ToLower(question.Name)
+	if !dns.IsSubDomain(s.domain, name) {
+		resp.Rcode = dns.RcodeRefused
+		return
+	}
+
+	portName := ""
+	parts := dns.SplitDomainName(name)
+	if len(parts) > 2 && strings.HasPrefix(parts[0], "_") && parts[1] == "_tcp" {
+		portName = strings.TrimPrefix(parts[0], "_")
+		name = dns.Fqdn(strings.Join(parts[2:], "."))
+	}
+	cluster, ok := s.names[name]
+	if !ok {
+		resp.Rcode = dns.RcodeNameError
+		return
+	}
+
+	members := s.healthyMembers(cluster)
+	switch question.Qtype {
+	case dns.TypeA:
+		if portName != "" {
+			return
+		}
+		for _, member := range members {
+			resp.Answer = append(resp.Answer, s.aRecords(question.Name, member)...)
+		}
+	case dns.TypeSRV:
+		for _, member := range members {
+			target := dns.Fqdn(member.node.String())
+			for _, port := range sortedPortNames(member.ports) {
+				if portName != "" && port != portName {
+					continue
+				}
+				resp.Answer = append(resp.Answer, &dns.SRV{
+					Hdr:      s.header(question.Name, dns.TypeSRV),
+					Priority: 0,
+					Weight:   1,
+					Port:     uint16(member.ports[port]),
+					Target:   target,
+				})
+				resp.Extra = append(resp.Extra, s.aRecords(target, member)...)
+			}
+		}
+	}
+}
+
+// healthyMembers returns the members of the cluster whose health is passing,
+// or all of them if fewer than the pod cluster's minimum health percentage
+// are passing or no health has been read yet. Must be called with mu held
+// for reading.
+func (s *Server) healthyMembers(c *cluster) []member {
+	results := s.health[c.podID].results
+	if results == nil {
+		return c.members
+	}
+
+	var healthy []member
+	for _, member := range c.members {
+		if result, ok := results[member.node]; ok && result.Status == health.Passing {
+			healthy = append(healthy, member)
+		}
+	}
+	if len(healthy)*100 < int(c.minHealthPercentage)*len(c.members) {
+		return c.members
+	}
+	return healthy
+}
+
+func (s *Server) aRecords(name string, m member) []dns.RR {
+	var records []dns.RR
+	for _, ip := range m.ips {
+		ipv4 := ip.To4()
+		if ipv4 == nil {
+			continue
+		}
+		records = append(records, &dns.A{
+			Hdr: s.header(name, dns.TypeA),
+			A:   ipv4,
+		})
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return bytes.Compare(records[i].(*dns.A).A, records[j].(*dns.A).A) < 0
+	})
+	return records
+}
+
+func (s *Server) header(name string, rrtype uint16) dns.RR_Header {
+	return dns.RR_Header{
+		Name:   name,
+		Rrtype: rrtype,
+		Class:  dns.ClassINET,
+		Ttl:    s.ttl,
+	}
+}
+
+func sortedPortNames(ports map[string]int) []string {
+	names := make([]string, 0, len(ports))
+	for name := range ports {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
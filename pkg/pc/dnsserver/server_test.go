@@ -0,0 +1,282 @@
+package dnsserver
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"github.com/square/p2/pkg/health"
+	"github.com/square/p2/pkg/health/checker"
+	"github.com/square/p2/pkg/labels"
+	"github.com/square/p2/pkg/logging"
+	"github.com/square/p2/pkg/manifest"
+	"github.com/square/p2/pkg/pc/fields"
+	"github.com/square/p2/pkg/pods"
+	"github.com/square/p2/pkg/store/consul"
+	"github.com/square/p2/pkg/types"
+)
+
+type fakeManifests map[types.NodeName]manifest.Manifest
+
+func (f fakeManifests) Pod(_ consul.PodPrefix, node types.NodeName, _ types.PodID) (manifest.Manifest, time.Duration, error) {
+	m, ok := f[node]
+	if !ok {
+		return nil, 0, pods.NoCurrentManifest
+	}
+	return m, 0, nil
+}
+
+// fakeHealthChecker sends a single set of results from WatchService, and
+// reports each service whose watch is canceled on stopped
+type fakeHealthChecker struct {
+	checker.HealthChecker
+	states  map[types.NodeName]health.HealthState
+	stopped chan string
+}
+
+func (f fakeHealthChecker) WatchService(
+	ctx context.Context,
+	serviceID string,
+	resultCh chan<- map[types.NodeName]health.Result,
+	_ chan<- error,
+	_ time.Duration,
+) {
+	defer close(resultCh)
+	results := make(map[types.NodeName]health.Result)
+	for node, state := range f.states {
+		results[node] = health.Result{ID: types.PodID(serviceID), Node: node, Status: state}
+	}
+	select {
+	case resultCh <- results:
+	case <-ctx.Done():
+	}
+	<-ctx.Done()
+	f.stopped <- serviceID
+}
+
+var nodeIPs = map[string]string{
+	"node1": "10.0.0.1",
+	"node2": "10.0.0.2",
+	"node3": "10.0.0.3",
+}
+
+func fakeLookupIP(host string) ([]net.IP, error) {
+	ip, ok := nodeIPs[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host}
+	}
+	return []net.IP{net.ParseIP(ip)}, nil
+}
+
+func statusPortManifest(port int) manifest.Manifest {
+	builder := manifest.NewBuilder()
+	builder.SetID("slug")
+	builder.SetStatusPort(port)
+	return builder.GetManifest()
+}
+
+func labeledPods(nodes ...types.NodeName) []labels.Labeled {
+	var out []labels.Labeled
+	for _, node := range nodes {
+		out = append(out, labels.Labeled{
+			LabelType: labels.POD,
+			ID:        labels.MakePodLabelKey(node, "slug"),
+		})
+	}
+	return out
+}
+
+func testPodCluster(minHealth fields.MinHealthPercentage, annotations fields.Annotations) *fields.PodCluster {
+	return &fields.PodCluster{
+		ID:                  "abc",
+		PodID:               "slug",
+		AvailabilityZone:    "us-west",
+		Name:                "production",
+		Annotations:         annotations,
+		MinHealthPercentage: minHealth,
+	}
+}
+
+func newTestServer(states map[types.NodeName]health.HealthState) (*Server, chan string) {
+	stopped := make(chan string, 10)
+	manifests := fakeManifests{
+		"node1": statusPortManifest(8001),
+		"node2": statusPortManifest(8002),
+		"node3": statusPortManifest(8003),
+	}
+	healthChecker := fakeHealthChecker{states: states, stopped: stopped}
+	return NewServer("p2", 30*time.Second, manifests, healthChecker, fakeLookupIP, logging.DefaultLogger), stopped
+}
+
+// syncAndWaitForHealth syncs the pod cluster, then waits for the server to
+// receive the health of its pods
+func syncAndWaitForHealth(t *testing.T, server *Server, pc *fields.PodCluster, pods []labels.Labeled) {
+	err := server.SyncCluster(pc, pods)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		server.mu.RLock()
+		received := server.health[pc.PodID].results != nil
+		server.mu.RUnlock()
+		if received {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for health results")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+type fakeResponseWriter struct {
+	dns.ResponseWriter
+	msg *dns.Msg
+}
+
+func (f *fakeResponseWriter) WriteMsg(msg *dns.Msg) error {
+	f.msg = msg
+	return nil
+}
+
+func query(server *Server, name string, qtype uint16) *dns.Msg {
+	req := new(dns.Msg)
+	req.SetQuestion(name, qtype)
+	w := &fakeResponseWriter{}
+	server.ServeDNS(w, req)
+	return w.msg
+}
+
+func answeredIPs(rrs []dns.RR) []string {
+	var ips []string
+	for _, rr := range rrs {
+		if a, ok := rr.(*dns.A); ok {
+			ips = append(ips, a.A.String())
+		}
+	}
+	return ips
+}
+
+func TestServeA(t *testing.T) {
+	server, _ := newTestServer(map[types.NodeName]health.HealthState{
+		"node1": health.Passing,
+		"node2": health.Critical,
+		"node3": health.Passing,
+	})
+	defer server.Stop()
+	syncAndWaitForHealth(t, server, testPodCluster(0, nil), labeledPods("node3", "node2", "node1"))
+
+	resp := query(server, "Production.Slug.US-West.p2.", dns.TypeA)
+	if resp.Rcode != dns.RcodeSuccess || !resp.Authoritative {
+		t.Fatalf("expected an authoritative answer, got %s", resp)
+	}
+	if ips := answeredIPs(resp.Answer); !reflect.DeepEqual(ips, []string{"10.0.0.1", "10.0.0.3"}) {
+		t.Errorf("expected the healthy members' addresses, got %v", ips)
+	}
+	if resp.Answer[0].Header().Ttl != 30 {
+		t.Errorf("expected a TTL of 30, got %d", resp.Answer[0].Header().Ttl)
+	}
+}
+
+func TestServeFallsBackBelowMinHealth(t *testing.T) {
+	server, _ := newTestServer(map[types.NodeName]health.HealthState{
+		"node1": health.Passing,
+		"node2": health.Critical,
+	})
+	defer server.Stop()
+	syncAndWaitForHealth(t, server, testPodCluster(75, nil), labeledPods("node1", "node2"))
+
+	resp := query(server, "production.slug.us-west.p2.", dns.TypeA)
+	if ips := answeredIPs(resp.Answer); !reflect.DeepEqual(ips, []string{"10.0.0.1", "10.0.0.2"}) {
+		t.Errorf("expected every member below the minimum health, got %v", ips)
+	}
+
+	err := server.SyncCluster(testPodCluster(50, nil), labeledPods("node1", "node2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp = query(server, "production.slug.us-west.p2.", dns.TypeA)
+	if ips := answeredIPs(resp.Answer); !reflect.DeepEqual(ips, []string{"10.0.0.1"}) {
+		t.Errorf("expected only the healthy member at the minimum health, got %v", ips)
+	}
+}
+
+func TestServeSRV(t *testing.T) {
+	server, _ := newTestServer(map[types.NodeName]health.HealthState{
+		"node1": health.Passing,
+		"node2": health.Passing,
+	})
+	defer server.Stop()
+
+	var annotations fields.Annotations
+	err := json.Unmarshal([]byte(`{"load_balancer_ports": {"http": 8080, "admin": 9090}}`), &annotations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	syncAndWaitForHealth(t, server, testPodCluster(0, annotations), labeledPods("node2", "node1"))
+
+	resp := query(server, "production.slug.us-west.p2.", dns.TypeSRV)
+	if len(resp.Answer) != 4 {
+		t.Fatalf("expected a record per member and port, got %s", resp)
+	}
+
+	resp = query(server, "_http._tcp.production.slug.us-west.p2.", dns.TypeSRV)
+	var targets []string
+	for _, rr := range resp.Answer {
+		srv := rr.(*dns.SRV)
+		if srv.Port != 8080 {
+			t.Errorf("expected only the http port, got %d", srv.Port)
+		}
+		targets = append(targets, srv.Target)
+	}
+	if !reflect.DeepEqual(targets, []string{"node1.", "node2."}) {
+		t.Errorf("expected both members as targets, got %v", targets)
+	}
+	if ips := answeredIPs(resp.Extra); !reflect.DeepEqual(ips, []string{"10.0.0.1", "10.0.0.2"}) {
+		t.Errorf("expected the targets' addresses as extra records, got %v", ips)
+	}
+
+	resp = query(server, "_missing._tcp.production.slug.us-west.p2.", dns.TypeSRV)
+	if resp.Rcode != dns.RcodeSuccess || len(resp.Answer) != 0 {
+		t.Errorf("expected no records for a port the pod cluster doesn't have, got %s", resp)
+	}
+}
+
+func TestServeUnknownNames(t *testing.T) {
+	server, stopped := newTestServer(map[types.NodeName]health.HealthState{"node1": health.Passing})
+	defer server.Stop()
+	syncAndWaitForHealth(t, server, testPodCluster(0, nil), labeledPods("node1"))
+
+	resp := query(server, "example.com.", dns.TypeA)
+	if resp.Rcode != dns.RcodeRefused {
+		t.Errorf("expected a refusal for a name outside the domain, got %s", dns.RcodeToString[resp.Rcode])
+	}
+	resp = query(server, "staging.slug.us-west.p2.", dns.TypeA)
+	if resp.Rcode != dns.RcodeNameError {
+		t.Errorf("expected NXDOMAIN for an unknown pod cluster, got %s", dns.RcodeToString[resp.Rcode])
+	}
+
+	err := server.DeleteCluster("abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp = query(server, "production.slug.us-west.p2.", dns.TypeA)
+	if resp.Rcode != dns.RcodeNameError {
+		t.Errorf("expected NXDOMAIN for a deleted pod cluster, got %s", dns.RcodeToString[resp.Rcode])
+	}
+	select {
+	case serviceID := <-stopped:
+		if serviceID != "slug" {
+			t.Errorf("expected the health watch of slug to stop, got %s", serviceID)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("expected the health watch to stop when its last pod cluster was deleted")
+	}
+}
@@ -0,0 +1,165 @@
+package lbconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/square/p2/pkg/pc/fields"
+	"github.com/square/p2/pkg/util"
+)
+
+// Renderer turns the backends of a pod cluster into the configuration of a
+// particular proxy. Renderers must produce the same output for the same input,
+// since the config file is only rewritten (and the proxy reloaded) when the
+// output changes.
+type Renderer interface {
+	// Name identifies the proxy the renderer targets, e.g. "haproxy"
+	Name() string
+
+	// Extension is the file extension, including the leading dot, given to
+	// each pod cluster's config file
+	Extension() string
+
+	// Render returns the config for a pod cluster. backends is sorted and
+	// grouped by port name.
+	Render(pc *fields.PodCluster, backends map[string][]Backend) ([]byte, error)
+}
+
+// RendererFor returns the renderer for the named proxy
+func RendererFor(name string) (Renderer, error) {
+	switch name {
+	case HAProxy{}.Name():
+		return HAProxy{}, nil
+	case Envoy{}.Name():
+		return Envoy{}, nil
+	default:
+		return nil, util.Errorf("no config renderer for %q", name)
+	}
+}
+
+// ClusterName is the name given to the proxy backend serving portName of a
+// pod cluster, e.g. "slug_us-west_production_http"
+func ClusterName(pc *fields.PodCluster, portName string) string {
+	return fmt.Sprintf("%s_%s_%s_%s", pc.PodID, pc.AvailabilityZone, pc.Name, portName)
+}
+
+// HAProxy renders a pod cluster as one HAProxy backend section per port. The
+// files are meant to be loaded alongside the main config, e.g. by passing
+// the output directory to haproxy with -f.
+type HAProxy struct{}
+
+func (HAProxy) Name() string      { return "haproxy" }
+func (HAProxy) Extension() string { return ".cfg" }
+
+func (HAProxy) Render(pc *fields.PodCluster, backends map[string][]Backend) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# Generated by p2 for pod cluster %s. Do not edit.\n", pc.ID)
+	for _, portName := range sortedPortNames(backends) {
+		fmt.Fprintf(&buf, "\nbackend %s\n", ClusterName(pc, portName))
+		for _, backend := range backends[portName] {
+			fmt.Fprintf(&buf, "    server %s %s check\n", strings.Replace(backend.Node.String(), ".", "_", -1), backend.Address())
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// Envoy renders a pod cluster as statically configured Envoy clusters, one
+// per port. Nodes are addressed by name, so the clusters resolve them with
+// STRICT_DNS.
+type Envoy struct{}
+
+func (Envoy) Name() string      { return "envoy" }
+func (Envoy) Extension() string { return ".json" }
+
+type envoyConfig struct {
+	StaticResources envoyStaticResources `json:"static_resources"`
+}
+
+type envoyStaticResources struct {
+	Clusters []envoyCluster `json:"clusters"`
+}
+
+type envoyCluster struct {
+	Name           string              `json:"name"`
+	ConnectTimeout string              `json:"connect_timeout"`
+	Type           string              `json:"type"`
+	LoadAssignment envoyLoadAssignment `json:"load_assignment"`
+}
+
+type envoyLoadAssignment struct {
+	ClusterName string                     `json:"cluster_name"`
+	Endpoints   []envoyLocalityLBEndpoints `json:"endpoints"`
+}
+
+type envoyLocalityLBEndpoints struct {
+	LBEndpoints []envoyLBEndpoint `json:"lb_endpoints"`
+}
+
+type envoyLBEndpoint struct {
+	Endpoint envoyEndpoint `json:"endpoint"`
+}
+
+type envoyEndpoint struct {
+	Address envoyAddress `json:"address"`
+}
+
+type envoyAddress struct {
+	SocketAddress envoySocketAddress `json:"socket_address"`
+}
+
+type envoySocketAddress struct {
+	Address   string `json:"address"`
+	PortValue int    `json:"port_value"`
+}
+
+func (Envoy) Render(pc *fields.PodCluster, backends map[string][]Backend) ([]byte, error) {
+	config := envoyConfig{
+		StaticResources: envoyStaticResources{
+			Clusters: []envoyCluster{},
+		},
+	}
+	for _, portName := range sortedPortNames(backends) {
+		endpoints := []envoyLBEndpoint{}
+		for _, backend := range backends[portName] {
+			endpoints = append(endpoints, envoyLBEndpoint{
+				Endpoint: envoyEndpoint{
+					Address: envoyAddress{
+						SocketAddress: envoySocketAddress{
+							Address:   backend.Node.String(),
+							PortValue: backend.Port,
+						},
+					},
+				},
+			})
+		}
+
+		name := ClusterName(pc, portName)
+		config.StaticResources.Clusters = append(config.StaticResources.Clusters, envoyCluster{
+			Name:           name,
+			ConnectTimeout: "1s",
+			Type:           "STRICT_DNS",
+			LoadAssignment: envoyLoadAssignment{
+				ClusterName: name,
+				Endpoints:   []envoyLocalityLBEndpoints{{LBEndpoints: endpoints}},
+			},
+		})
+	}
+
+	out, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+func sortedPortNames(backends map[string][]Backend) []string {
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
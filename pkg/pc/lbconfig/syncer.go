@@ -0,0 +1,342 @@
+/*
+Package lbconfig renders pod cluster membership into load balancer config
+files. Its Syncer is a pcstore.ConcreteSyncer, so it is driven by the pod
+cluster store's WatchAndSync: each pod cluster gets one file in the output
+directory, rewritten whenever the cluster or its pods change, and the
+configured reload command is run after every change. A failed reload is
+retried on the next sync, even if nothing changed by then.
+*/
+package lbconfig
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/square/p2/pkg/health"
+	"github.com/square/p2/pkg/labels"
+	"github.com/square/p2/pkg/logging"
+	"github.com/square/p2/pkg/manifest"
+	"github.com/square/p2/pkg/pc/fields"
+	"github.com/square/p2/pkg/pods"
+	"github.com/square/p2/pkg/store/consul"
+	"github.com/square/p2/pkg/store/consul/pcstore"
+	"github.com/square/p2/pkg/types"
+	"github.com/square/p2/pkg/util"
+)
+
+// PortsAnnotation is the pod cluster annotation naming the ports to balance
+// across, as a JSON object from port name to port number, e.g.
+// {"load_balancer_ports": {"http": 8080, "admin": 8081}}. Pod clusters without
+// it are balanced across the status port of each pod's manifest.
+const PortsAnnotation = "load_balancer_ports"
+
+// StatusPortName is the port name used for pod clusters balanced across
+// their status ports
+const StatusPortName = "status"
+
+// Backend is a single pod serving one port of a pod cluster
+type Backend struct {
+	Node types.NodeName
+	Port int
+}
+
+func (b Backend) Address() string {
+	return net.JoinHostPort(b.Node.String(), strconv.Itoa(b.Port))
+}
+
+// Subset of consul.Store
+type ManifestStore interface {
+	Pod(podPrefix consul.PodPrefix, nodename types.NodeName, podId types.PodID) (manifest.Manifest, time.Duration, error)
+}
+
+// Subset of checker.HealthChecker
+type HealthChecker interface {
+	Service(serviceID string) (map[types.NodeName]health.Result, error)
+}
+
+type Syncer struct {
+	renderer      Renderer
+	outputDir     string
+	reloadCommand string
+	manifests     ManifestStore
+	healthChecker HealthChecker
+	logger        logging.Logger
+
+	// SyncCluster and DeleteCluster are called concurrently, but reloads
+	// should not overlap, so writes and reloads happen under this lock
+	mu sync.Mutex
+	// reloadPending is set when the last reload failed, so the files on
+	// disk may not be what the proxy is serving
+	reloadPending bool
+}
+
+var _ pcstore.ConcreteSyncer = &Syncer{}
+
+// NewSyncer returns a syncer that writes the config for each pod cluster
+// into outputDir. reloadCommand is run with /bin/sh after any file in
+// outputDir is written or removed; it may be empty.
+func NewSyncer(
+	renderer Renderer,
+	outputDir string,
+	reloadCommand string,
+	manifests ManifestStore,
+	healthChecker HealthChecker,
+	logger logging.Logger,
+) *Syncer {
+	return &Syncer{
+		renderer:      renderer,
+		outputDir:     outputDir,
+		reloadCommand: reloadCommand,
+		manifests:     manifests,
+		healthChecker: healthChecker,
+		logger:        logger,
+	}
+}
+
+// SyncCluster renders the pod cluster's backends and, if the config changed,
+// writes it and reloads the proxy. A reload that failed earlier is retried
+// even if the config did not change.
+//
+// Unhealthy pods are only left out while the rest of the pod cluster meets
+// its MinHealthPercentage. Below that every pod is kept, on the theory that
+// sending traffic to a struggling backend is better than concentrating all
+// of it on the few that are left. Health is read when the cluster is synced,
+// so it is only as fresh as the last pod or pod cluster change, or the
+// periodic re-sync of the pod cluster watch.
+func (s *Syncer) SyncCluster(pc *fields.PodCluster, labeledPods []labels.Labeled) error {
+	logger := s.logger.SubLogger(map[string]interface{}{"pod_cluster": pc.ID})
+
+	healthResults, err := s.healthChecker.Service(pc.PodID.String())
+	if err != nil {
+		return util.Errorf("could not get health of %s: %s", pc.PodID, err)
+	}
+
+	all := make(map[string][]Backend)
+	healthy := make(map[string][]Backend)
+	total, healthyCount := 0, 0
+	for _, pod := range labeledPods {
+		node, podID, err := labels.NodeAndPodIDFromPodLabel(pod)
+		if err != nil {
+			return err
+		}
+
+		podPorts, err := PodPorts(s.manifests, pc, node, podID)
+		if err != nil {
+			return err
+		}
+		if podPorts == nil {
+			logger.Warnf("%s has no status port or %s annotation, leaving it out", pod.ID, PortsAnnotation)
+			continue
+		}
+
+		total++
+		result, ok := healthResults[node]
+		isHealthy := ok && result.Status == health.Passing
+		if isHealthy {
+			healthyCount++
+		}
+		for name, port := range podPorts {
+			backend := Backend{Node: node, Port: port}
+			all[name] = append(all[name], backend)
+			if isHealthy {
+				healthy[name] = append(healthy[name], backend)
+			}
+		}
+	}
+
+	backends := healthy
+	for name := range all {
+		// Keep the backend when none of its pods are healthy, since the
+		// proxy's config may refer to it
+		if _, ok := healthy[name]; !ok {
+			healthy[name] = []Backend{}
+		}
+	}
+	if healthyCount*100 < int(pc.MinHealthPercentage)*total {
+		logger.Warnf(
+			"only %d of %d pods are healthy, below the minimum of %d%%; keeping unhealthy pods as backends",
+			healthyCount,
+			total,
+			pc.MinHealthPercentage,
+		)
+		backends = all
+	}
+	for name := range backends {
+		sort.Slice(backends[name], func(i, j int) bool {
+			return backends[name][i].Address() < backends[name][j].Address()
+		})
+	}
+
+	config, err := s.renderer.Render(pc, backends)
+	if err != nil {
+		return util.Errorf("could not render %s config for %s: %s", s.renderer.Name(), pc.ID, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	changed, err := writeIfChanged(s.configPath(pc.ID), config)
+	if err != nil {
+		return util.Errorf("could not write config for %s: %s", pc.ID, err)
+	}
+	if !changed && !s.reloadPending {
+		return nil
+	}
+	if changed {
+		logger.Infof("Wrote %s config with %d of %d pods", s.renderer.Name(), healthyCount, total)
+	}
+	return s.reload()
+}
+
+// DeleteCluster removes the pod cluster's config and reloads the proxy, or
+// retries a failed reload if the config was already removed
+func (s *Syncer) DeleteCluster(id fields.ID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := os.Remove(s.configPath(id))
+	switch {
+	case os.IsNotExist(err):
+		if !s.reloadPending {
+			return nil
+		}
+	case err != nil:
+		return util.Errorf("could not remove config for %s: %s", id, err)
+	default:
+		s.logger.WithField("pod_cluster", id).Infof("Removed %s config", s.renderer.Name())
+	}
+	return s.reload()
+}
+
+// GetInitialClusters returns the pod clusters that have a config file in the
+// output directory, so that the configs of pod clusters deleted while the
+// syncer was not running are cleaned up.
+func (s *Syncer) GetInitialClusters() ([]fields.ID, error) {
+	files, err := ioutil.ReadDir(s.outputDir)
+	if err != nil {
+		return nil, util.Errorf("could not list %s: %s", s.outputDir, err)
+	}
+
+	var ids []fields.ID
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || strings.HasPrefix(name, ".") || filepath.Ext(name) != s.renderer.Extension() {
+			continue
+		}
+		ids = append(ids, fields.ID(strings.TrimSuffix(name, s.renderer.Extension())))
+	}
+	return ids, nil
+}
+
+func (s *Syncer) Type() pcstore.ConcreteSyncerType {
+	return pcstore.ConcreteSyncerType(fmt.Sprintf("lb_config_%s", s.renderer.Name()))
+}
+
+func (s *Syncer) configPath(id fields.ID) string {
+	return filepath.Join(s.outputDir, id.String()+s.renderer.Extension())
+}
+
+// PodPorts returns the ports served by a pod of the pod cluster, by name:
+// those in the pod cluster's PortsAnnotation, or if it has none, the status
+// port of the pod's manifest. It returns nil if the pod has neither.
+func PodPorts(manifests ManifestStore, pc *fields.PodCluster, node types.NodeName, podID types.PodID) (map[string]int, error) {
+	ports, err := annotatedPorts(pc.Annotations)
+	if err != nil || ports != nil {
+		return ports, err
+	}
+
+	podManifest, _, err := manifests.Pod(consul.INTENT_TREE, node, podID)
+	if err == pods.NoCurrentManifest {
+		return nil, nil
+	} else if err != nil {
+		return nil, util.Errorf("could not read manifest of %s on %s: %s", podID, node, err)
+	}
+	if podManifest.GetStatusPort() == 0 {
+		return nil, nil
+	}
+	return map[string]int{StatusPortName: podManifest.GetStatusPort()}, nil
+}
+
+// reload runs the reload command, remembering whether it failed so that the
+// next sync retries it. s.mu must be held.
+func (s *Syncer) reload() error {
+	if s.reloadCommand == "" {
+		return nil
+	}
+	output, err := exec.Command("/bin/sh", "-c", s.reloadCommand).CombinedOutput()
+	s.reloadPending = err != nil
+	if err != nil {
+		return util.Errorf("reload command %q failed: %s: %s", s.reloadCommand, err, output)
+	}
+	return nil
+}
+
+// annotatedPorts returns the ports in the PortsAnnotation, or nil if the
+// annotation is not set
+func annotatedPorts(annotations fields.Annotations) (map[string]int, error) {
+	raw, ok := annotations[PortsAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	rawPorts, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, util.Errorf("%s annotation must be an object from port name to port, was %v", PortsAnnotation, raw)
+	}
+
+	ports := make(map[string]int, len(rawPorts))
+	for name, rawPort := range rawPorts {
+		var port int
+		switch p := rawPort.(type) {
+		case float64:
+			port = int(p)
+		case int:
+			port = p
+		default:
+			return nil, util.Errorf("port %s in the %s annotation must be a number, was %v", name, PortsAnnotation, rawPort)
+		}
+		if port <= 0 || port > 65535 {
+			return nil, util.Errorf("port %s in the %s annotation is out of range: %d", name, PortsAnnotation, port)
+		}
+		ports[name] = port
+	}
+	return ports, nil
+}
+
+// writeIfChanged atomically replaces path with data, by writing to a
+// temporary file in the same directory and renaming it over path, unless
+// path already holds data
+func writeIfChanged(path string, data []byte) (bool, error) {
+	existing, err := ioutil.ReadFile(path)
+	if err == nil && string(existing) == string(data) {
+		return false, nil
+	} else if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path))
+	if err != nil {
+		return false, err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if errC := tmp.Close(); err == nil {
+		err = errC
+	}
+	if err != nil {
+		return false, err
+	}
+	err = os.Chmod(tmp.Name(), 0644)
+	if err != nil {
+		return false, err
+	}
+	return true, os.Rename(tmp.Name(), path)
+}
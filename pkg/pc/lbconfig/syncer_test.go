@@ -0,0 +1,327 @@
+package lbconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/square/p2/pkg/health"
+	"github.com/square/p2/pkg/labels"
+	"github.com/square/p2/pkg/logging"
+	"github.com/square/p2/pkg/manifest"
+	"github.com/square/p2/pkg/pc/fields"
+	"github.com/square/p2/pkg/pods"
+	"github.com/square/p2/pkg/store/consul"
+	"github.com/square/p2/pkg/types"
+)
+
+type fakeManifests map[types.NodeName]manifest.Manifest
+
+func (f fakeManifests) Pod(_ consul.PodPrefix, node types.NodeName, _ types.PodID) (manifest.Manifest, time.Duration, error) {
+	m, ok := f[node]
+	if !ok {
+		return nil, 0, pods.NoCurrentManifest
+	}
+	return m, 0, nil
+}
+
+type fakeHealth map[types.NodeName]health.HealthState
+
+func (f fakeHealth) Service(serviceID string) (map[types.NodeName]health.Result, error) {
+	results := make(map[types.NodeName]health.Result)
+	for node, state := range f {
+		results[node] = health.Result{ID: types.PodID(serviceID), Node: node, Status: state}
+	}
+	return results, nil
+}
+
+func statusPortManifest(port int) manifest.Manifest {
+	builder := manifest.NewBuilder()
+	builder.SetID("slug")
+	builder.SetStatusPort(port)
+	return builder.GetManifest()
+}
+
+func labeledPods(nodes ...types.NodeName) []labels.Labeled {
+	var out []labels.Labeled
+	for _, node := range nodes {
+		out = append(out, labels.Labeled{
+			LabelType: labels.POD,
+			ID:        labels.MakePodLabelKey(node, "slug"),
+		})
+	}
+	return out
+}
+
+func testPodCluster(minHealth fields.MinHealthPercentage, annotations fields.Annotations) *fields.PodCluster {
+	return &fields.PodCluster{
+		ID:                  "abc",
+		PodID:               "slug",
+		AvailabilityZone:    "us-west",
+		Name:                "production",
+		Annotations:         annotations,
+		MinHealthPercentage: minHealth,
+	}
+}
+
+// newTestSyncer returns a syncer writing to a temporary directory, and a
+// function returning how many times it has reloaded
+func newTestSyncer(t *testing.T, renderer Renderer, manifests fakeManifests, healthStates fakeHealth) (*Syncer, string, func() int) {
+	dir, err := ioutil.TempDir("", "lbconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	reloads := filepath.Join(dir, ".reloads")
+	syncer := NewSyncer(renderer, dir, "echo reloaded >> "+reloads, manifests, healthStates, logging.DefaultLogger)
+
+	reloadCount := func() int {
+		out, err := ioutil.ReadFile(reloads)
+		if os.IsNotExist(err) {
+			return 0
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		return strings.Count(string(out), "reloaded")
+	}
+	return syncer, dir, reloadCount
+}
+
+func TestSyncClusterStatusPorts(t *testing.T) {
+	manifests := fakeManifests{
+		"node1": statusPortManifest(8001),
+		"node2": statusPortManifest(8002),
+	}
+	healthStates := fakeHealth{"node1": health.Passing, "node2": health.Critical}
+	syncer, dir, reloadCount := newTestSyncer(t, HAProxy{}, manifests, healthStates)
+	defer os.RemoveAll(dir)
+
+	// node3 has no manifest, so it has no status port to balance across
+	pc := testPodCluster(0, nil)
+	err := syncer.SyncCluster(pc, labeledPods("node1", "node2", "node3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := ioutil.ReadFile(filepath.Join(dir, "abc.cfg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `# Generated by p2 for pod cluster abc. Do not edit.
+
+backend slug_us-west_production_status
+    server node1 node1:8001 check
+`
+	if string(config) != expected {
+		t.Errorf("expected config:\n%s\ngot:\n%s", expected, config)
+	}
+	if reloadCount() != 1 {
+		t.Errorf("expected one reload after writing the config, got %d", reloadCount())
+	}
+
+	err = syncer.SyncCluster(pc, labeledPods("node1", "node2", "node3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloadCount() != 1 {
+		t.Errorf("expected no reload when the config did not change, got %d", reloadCount())
+	}
+}
+
+func TestSyncClusterRespectsMinHealthPercentage(t *testing.T) {
+	manifests := fakeManifests{
+		"node1": statusPortManifest(8000),
+		"node2": statusPortManifest(8000),
+	}
+	healthStates := fakeHealth{"node1": health.Passing, "node2": health.Critical}
+	syncer, dir, _ := newTestSyncer(t, HAProxy{}, manifests, healthStates)
+	defer os.RemoveAll(dir)
+
+	err := syncer.SyncCluster(testPodCluster(75, nil), labeledPods("node1", "node2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, err := ioutil.ReadFile(filepath.Join(dir, "abc.cfg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(config), "node2:8000") {
+		t.Errorf("expected the unhealthy pod to be kept below the minimum health, got:\n%s", config)
+	}
+
+	err = syncer.SyncCluster(testPodCluster(50, nil), labeledPods("node1", "node2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, err = ioutil.ReadFile(filepath.Join(dir, "abc.cfg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(config), "node2:8000") {
+		t.Errorf("expected the unhealthy pod to be removed at the minimum health, got:\n%s", config)
+	}
+}
+
+func TestSyncClusterAnnotatedPorts(t *testing.T) {
+	healthStates := fakeHealth{"node1": health.Passing, "node2": health.Passing}
+	syncer, dir, _ := newTestSyncer(t, Envoy{}, fakeManifests{}, healthStates)
+	defer os.RemoveAll(dir)
+
+	var annotations fields.Annotations
+	err := json.Unmarshal([]byte(`{"load_balancer_ports": {"http": 8080, "admin": 9090}}`), &annotations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = syncer.SyncCluster(testPodCluster(0, annotations), labeledPods("node2", "node1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configBytes, err := ioutil.ReadFile(filepath.Join(dir, "abc.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var config envoyConfig
+	err = json.Unmarshal(configBytes, &config)
+	if err != nil {
+		t.Fatalf("expected the envoy config to be JSON: %s", err)
+	}
+
+	clusters := config.StaticResources.Clusters
+	if len(clusters) != 2 || clusters[0].Name != "slug_us-west_production_admin" || clusters[1].Name != "slug_us-west_production_http" {
+		t.Fatalf("expected an admin and an http cluster, got %+v", clusters)
+	}
+	var addresses []string
+	for _, endpoint := range clusters[1].LoadAssignment.Endpoints[0].LBEndpoints {
+		address := endpoint.Endpoint.Address.SocketAddress
+		addresses = append(addresses, Backend{Node: types.NodeName(address.Address), Port: address.PortValue}.Address())
+	}
+	if !reflect.DeepEqual(addresses, []string{"node1:8080", "node2:8080"}) {
+		t.Errorf("expected sorted http endpoints, got %v", addresses)
+	}
+}
+
+func TestSyncClusterBadAnnotation(t *testing.T) {
+	syncer, dir, _ := newTestSyncer(t, HAProxy{}, fakeManifests{}, fakeHealth{})
+	defer os.RemoveAll(dir)
+
+	for _, annotations := range []fields.Annotations{
+		{PortsAnnotation: "8080"},
+		{PortsAnnotation: map[string]interface{}{"http": "8080"}},
+		{PortsAnnotation: map[string]interface{}{"http": float64(70000)}},
+	} {
+		err := syncer.SyncCluster(testPodCluster(0, annotations), labeledPods("node1"))
+		if err == nil {
+			t.Errorf("expected an error syncing with annotations %v", annotations)
+		}
+	}
+}
+
+func TestDeleteClusterAndInitialClusters(t *testing.T) {
+	manifests := fakeManifests{"node1": statusPortManifest(8000)}
+	syncer, dir, reloadCount := newTestSyncer(t, HAProxy{}, manifests, fakeHealth{"node1": health.Passing})
+	defer os.RemoveAll(dir)
+
+	other := testPodCluster(0, nil)
+	other.ID = "def"
+	for _, pc := range []*fields.PodCluster{testPodCluster(0, nil), other} {
+		err := syncer.SyncCluster(pc, labeledPods("node1"))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := ioutil.WriteFile(filepath.Join(dir, "unrelated.txt"), nil, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	initial, err := syncer.GetInitialClusters()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Slice(initial, func(i, j int) bool { return initial[i] < initial[j] })
+	if !reflect.DeepEqual(initial, []fields.ID{"abc", "def"}) {
+		t.Errorf("expected the synced pod clusters to be the initial clusters, got %v", initial)
+	}
+
+	err = syncer.DeleteCluster("abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "abc.cfg")); !os.IsNotExist(err) {
+		t.Errorf("expected the config to be removed, got %v", err)
+	}
+	if reloadCount() != 3 {
+		t.Errorf("expected a reload after removing the config, got %d reloads", reloadCount())
+	}
+
+	err = syncer.DeleteCluster("abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloadCount() != 3 {
+		t.Errorf("expected no reload deleting a pod cluster without config, got %d reloads", reloadCount())
+	}
+}
+
+func TestSyncClusterRetriesFailedReload(t *testing.T) {
+	manifests := fakeManifests{"node1": statusPortManifest(8000)}
+	healthStates := fakeHealth{"node1": health.Passing}
+	dir, err := ioutil.TempDir("", "lbconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// the reload fails as long as the "broken" file exists
+	broken := filepath.Join(dir, ".broken")
+	reloads := filepath.Join(dir, ".reloads")
+	reloadCommand := fmt.Sprintf("test ! -e %s && echo reloaded >> %s", broken, reloads)
+	syncer := NewSyncer(HAProxy{}, dir, reloadCommand, manifests, healthStates, logging.DefaultLogger)
+	reloadCount := func() int {
+		out, err := ioutil.ReadFile(reloads)
+		if os.IsNotExist(err) {
+			return 0
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		return strings.Count(string(out), "reloaded")
+	}
+
+	err = ioutil.WriteFile(broken, nil, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pc := testPodCluster(0, nil)
+	err = syncer.SyncCluster(pc, labeledPods("node1"))
+	if err == nil {
+		t.Fatal("expected the failed reload to be returned")
+	}
+
+	err = os.Remove(broken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the config is already on disk, but the proxy never loaded it
+	err = syncer.SyncCluster(pc, labeledPods("node1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloadCount() != 1 {
+		t.Errorf("expected the failed reload to be retried, got %d reloads", reloadCount())
+	}
+
+	err = syncer.SyncCluster(pc, labeledPods("node1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloadCount() != 1 {
+		t.Errorf("expected no reload once the retry succeeded, got %d reloads", reloadCount())
+	}
+}
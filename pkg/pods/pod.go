@@ -477,7 +477,7 @@ func (pod *Pod) Install(manifest manifest.Manifest, verifier auth.ArtifactVerifi
 		return err
 	}
 
-	downloader := artifact.NewLocationDownloader(pod.Fetcher, verifier)
+	downloader := artifact.NewLocationDownloader(pod.Fetcher, verifier, pod.logger)
 	for launchableID, stanza := range manifest.GetLaunchableStanzas() {
 		// TODO: investigate passing in necessary fields to InstallDir()
 		launchable, err := pod.getLaunchable(launchableID, stanza, manifest.RunAsUser(), manifest.UnpackAsUser())
@@ -493,13 +493,26 @@ func (pod *Pod) Install(manifest manifest.Manifest, verifier auth.ArtifactVerifi
 		// TODO: make this code better, probably abstract away launchable installation
 		// into something that understands the types
 		if launchable.Type() == launch.HoistLaunchableType || launchable.Type() == launch.OpenContainerLaunchableType {
-			launchableURL, verificationData, err := artifactRegistry.LocationDataForLaunchable(pod.Id, launchableID, stanza)
+			var baseDigests []string
+			if artifactRegistry.DeltasEnabled() {
+				// Offer the registry the artifacts kept from earlier installs so
+				// that it can send a delta instead of the full artifact
+				baseDigests, err = artifact.KeptArtifactDigests(filepath.Dir(launchable.InstallDir()))
+				if err != nil {
+					pod.logLaunchableWarning(launchable.ServiceID(), err, "Could not list kept artifacts")
+				}
+			}
+			launchableURL, verificationData, delta, err := artifactRegistry.DeltaLocationDataForLaunchable(pod.Id, launchableID, stanza, baseDigests)
 			if err != nil {
 				pod.logLaunchableError(launchable.ServiceID(), err, "Unable to install launchable")
 				return err
 			}
 
-			err = downloader.Download(launchableURL, verificationData, launchable.InstallDir(), manifest.UnpackAsUser())
+			if artifactRegistry.DeltasEnabled() {
+				err = downloader.DownloadDelta(launchableURL, verificationData, delta, launchable.InstallDir(), manifest.UnpackAsUser())
+			} else {
+				err = downloader.Download(launchableURL, verificationData, launchable.InstallDir(), manifest.UnpackAsUser())
+			}
 			if err != nil {
 				pod.logLaunchableError(launchable.ServiceID(), err, "Unable to install launchable")
 				_ = os.Remove(launchable.InstallDir())
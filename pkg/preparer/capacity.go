@@ -0,0 +1,93 @@
+package preparer
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/square/p2/pkg/labels"
+	"github.com/square/p2/pkg/logging"
+	"github.com/square/p2/pkg/scheduler"
+	"github.com/square/p2/pkg/util"
+	"github.com/square/p2/pkg/util/size"
+)
+
+// CapacityConfig sets what part of the node is held back from pods when its
+// capacity is reported.
+type CapacityConfig struct {
+	ReservedCPUs   int            `yaml:"reserved_cpus,omitempty"`
+	ReservedMemory size.ByteCount `yaml:"reserved_memory,omitempty"`
+}
+
+// ReportCapacity labels the node with the CPUs and memory available to pods,
+// which is the host's total less what the config reserves. It does nothing
+// if capacity reporting isn't configured.
+func ReportCapacity(preparerConfig *PreparerConfig, logger logging.Logger) error {
+	capacityConfig := preparerConfig.Capacity
+	if capacityConfig == nil {
+		return nil
+	}
+
+	f, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return util.Errorf("could not read host memory: %s", err)
+	}
+	defer f.Close()
+	memory, err := memTotal(f)
+	if err != nil {
+		return err
+	}
+
+	cpus := runtime.NumCPU() - capacityConfig.ReservedCPUs
+	if cpus < 0 {
+		cpus = 0
+	}
+	if capacityConfig.ReservedMemory > memory {
+		memory = 0
+	} else {
+		memory -= capacityConfig.ReservedMemory
+	}
+
+	client, err := preparerConfig.GetConsulClient()
+	if err != nil {
+		return err
+	}
+	applicator := labels.NewConsulApplicator(client, 0, 0)
+	err = applicator.SetLabels(labels.NODE, preparerConfig.NodeName.String(), map[string]string{
+		scheduler.CPUCapacityLabel:    strconv.Itoa(cpus),
+		scheduler.MemoryCapacityLabel: strconv.FormatUint(uint64(memory), 10),
+	})
+	if err != nil {
+		return util.Errorf("could not label node with its capacity: %s", err)
+	}
+	logger.WithFields(logrus.Fields{
+		"cpus":   cpus,
+		"memory": memory.String(),
+	}).Infoln("Reported node capacity")
+	return nil
+}
+
+// memTotal reads the total memory from the contents of /proc/meminfo.
+func memTotal(meminfo io.Reader) (size.ByteCount, error) {
+	scanner := bufio.NewScanner(meminfo)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || fields[0] != "MemTotal:" {
+			continue
+		}
+		kb, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return 0, util.Errorf("could not parse MemTotal %q: %s", fields[1], err)
+		}
+		return size.ByteCount(kb) * size.Kibibyte, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, util.Errorf("could not read host memory: %s", err)
+	}
+	return 0, util.Errorf("no MemTotal found in /proc/meminfo")
+}
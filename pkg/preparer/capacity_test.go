@@ -0,0 +1,24 @@
+package preparer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/square/p2/pkg/util/size"
+)
+
+func TestMemTotal(t *testing.T) {
+	meminfo := "MemTotal:       16318480 kB\nMemFree:         1194628 kB\n"
+	memory, err := memTotal(strings.NewReader(meminfo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if memory != 16318480*size.Kibibyte {
+		t.Errorf("Expected %s, got %s", 16318480*size.Kibibyte, memory)
+	}
+
+	_, err = memTotal(strings.NewReader("MemFree: 1 kB\n"))
+	if err == nil {
+		t.Error("Expected an error when MemTotal is missing")
+	}
+}
@@ -22,15 +22,19 @@ import (
 	context "golang.org/x/net/context"
 	"golang.org/x/net/http2"
 	"gopkg.in/yaml.v2"
+	klabels "k8s.io/kubernetes/pkg/labels"
 
 	"github.com/square/p2/pkg/artifact"
+	"github.com/square/p2/pkg/artifact/cacheproxy"
 	"github.com/square/p2/pkg/auth"
 	"github.com/square/p2/pkg/constants"
 	"github.com/square/p2/pkg/docker"
 	"github.com/square/p2/pkg/hooks"
+	"github.com/square/p2/pkg/labels"
 	"github.com/square/p2/pkg/launch"
 	"github.com/square/p2/pkg/logging"
 	"github.com/square/p2/pkg/manifest"
+	p2metrics "github.com/square/p2/pkg/metrics"
 	"github.com/square/p2/pkg/osversion"
 	"github.com/square/p2/pkg/pods"
 	"github.com/square/p2/pkg/preparer/podprocess"
@@ -210,6 +214,24 @@ type PreparerConfig struct {
 	// IdleConnTimeout will be set on the preparer's HTTP client transport.
 	IdleConnTimeout time.Duration `yaml:"idle_conn_timeout"`
 
+	// ArtifactDownload configures retries, resumption and bandwidth limits
+	// for artifact downloads. If unset, artifacts are fetched with a single
+	// plain GET.
+	ArtifactDownload *ArtifactDownloadConfig `yaml:"artifact_download,omitempty"`
+
+	// S3 configures access to an S3-compatible object store so that
+	// artifacts, verification files and registry responses may use
+	// s3://bucket/key URIs.
+	S3 *uri.S3Config `yaml:"s3,omitempty"`
+
+	// ArtifactProxy configures pull-through caching proxies that artifact
+	// downloads are attempted through before going to the origin.
+	ArtifactProxy *ArtifactProxyConfig `yaml:"artifact_proxy,omitempty"`
+
+	// Capacity configures labeling the node with the resources it offers
+	// pods, for use by a resource-aware scheduler.
+	Capacity *CapacityConfig `yaml:"capacity,omitempty"`
+
 	podHome string `yaml:"pod_home"`
 
 	// Use a single Store so that all requests go through the same HTTP client.
@@ -220,6 +242,37 @@ type PreparerConfig struct {
 	httpClient    *http.Client
 }
 
+// ArtifactDownloadConfig controls how the preparer downloads artifacts. See
+// uri.ResumableFetcherOptions for the meaning of each field.
+type ArtifactDownloadConfig struct {
+	Retries          int           `yaml:"retries,omitempty"`
+	InitialBackoff   time.Duration `yaml:"initial_backoff,omitempty"`
+	MaxBackoff       time.Duration `yaml:"max_backoff,omitempty"`
+	ProgressInterval time.Duration `yaml:"progress_interval,omitempty"`
+
+	// BandwidthLimit is the maximum combined download rate per second,
+	// expressed as a size such as "50M". Empty means unlimited.
+	BandwidthLimit string `yaml:"bandwidth_limit,omitempty"`
+
+	// Deltas keeps each installed artifact next to its install and offers
+	// it to the artifact registry, so that later versions can be downloaded
+	// as deltas. Kept artifacts count against max_launchable_disk_usage.
+	Deltas bool `yaml:"deltas,omitempty"`
+}
+
+// ArtifactProxyConfig advertises artifact caching proxies (see
+// p2-artifact-proxy), either as a fixed list of URLs or as every node that
+// matches a label selector.
+type ArtifactProxyConfig struct {
+	URLs []string `yaml:"urls,omitempty"`
+
+	// NodeSelector is a label selector for nodes running a proxy, which is
+	// reached at Scheme://<node>:Port
+	NodeSelector string `yaml:"node_selector,omitempty"`
+	Port         int    `yaml:"port,omitempty"`
+	Scheme       string `yaml:"scheme,omitempty"`
+}
+
 // --- Deployer ACL strategies ---
 
 // Configuration fields for the "keyring" auth type
@@ -522,8 +575,9 @@ func New(preparerConfig *PreparerConfig, logger logging.Logger) (*Preparer, erro
 	if err != nil {
 		return nil, err
 	}
-	fetcher := uri.BasicFetcher{
-		Client: httpClient,
+	fetcher, err := getArtifactFetcher(preparerConfig, httpClient, logger)
+	if err != nil {
+		return nil, err
 	}
 
 	var hooksManifest manifest.Manifest
@@ -807,8 +861,9 @@ func getArtifactVerifier(preparerConfig *PreparerConfig, logger *logging.Logger)
 		return nil, err
 	}
 
-	fetcher := uri.BasicFetcher{
-		Client: httpClient,
+	fetcher, err := withS3(preparerConfig, uri.BasicFetcher{Client: httpClient}, httpClient)
+	if err != nil {
+		return nil, err
 	}
 	var verif ManifestVerification
 	switch t, _ := preparerConfig.ArtifactAuth["type"].(string); t {
@@ -837,14 +892,109 @@ func getArtifactVerifier(preparerConfig *PreparerConfig, logger *logging.Logger)
 	}
 }
 
+func getArtifactFetcher(preparerConfig *PreparerConfig, httpClient *http.Client, logger logging.Logger) (uri.Fetcher, error) {
+	var fetcher uri.Fetcher = uri.BasicFetcher{Client: httpClient}
+	if downloadConfig := preparerConfig.ArtifactDownload; downloadConfig != nil {
+		var bytesPerSecond int64
+		if downloadConfig.BandwidthLimit != "" {
+			limit, err := size.Parse(downloadConfig.BandwidthLimit)
+			if err != nil {
+				return nil, util.Errorf("Unparseable value for artifact_download.bandwidth_limit %v, %v", downloadConfig.BandwidthLimit, err)
+			}
+			bytesPerSecond = limit.Int64()
+		}
+
+		fetcher = uri.NewResumableFetcher(httpClient, uri.ResumableFetcherOptions{
+			Retries:          downloadConfig.Retries,
+			InitialBackoff:   downloadConfig.InitialBackoff,
+			MaxBackoff:       downloadConfig.MaxBackoff,
+			BytesPerSecond:   bytesPerSecond,
+			ProgressInterval: downloadConfig.ProgressInterval,
+			Logger: logger.SubLogger(logrus.Fields{
+				"component": "artifact_fetcher",
+			}),
+			MetricsRegistry: p2metrics.Registry,
+		})
+	}
+
+	fetcher, err := withS3(preparerConfig, fetcher, httpClient)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only artifacts go through proxies. Verification files are always
+	// fetched from their origin, so a proxy can't vouch for what it serves.
+	proxyLocator, err := getArtifactProxyLocator(preparerConfig)
+	if err != nil {
+		return nil, err
+	}
+	if proxyLocator != nil {
+		fetcher = uri.NewProxyFetcher(fetcher, proxyLocator, logger.SubLogger(logrus.Fields{
+			"component": "artifact_proxy",
+		}))
+	}
+	return fetcher, nil
+}
+
+func getArtifactProxyLocator(preparerConfig *PreparerConfig) (uri.ProxyLocator, error) {
+	proxyConfig := preparerConfig.ArtifactProxy
+	if proxyConfig == nil {
+		return nil, nil
+	}
+
+	switch {
+	case len(proxyConfig.URLs) > 0 && proxyConfig.NodeSelector != "":
+		return nil, util.Errorf("artifact_proxy must specify only one of urls or node_selector")
+	case len(proxyConfig.URLs) > 0:
+		proxies := make(uri.StaticProxies, 0, len(proxyConfig.URLs))
+		for _, proxyURL := range proxyConfig.URLs {
+			parsed, err := url.Parse(proxyURL)
+			if err != nil {
+				return nil, util.Errorf("Could not parse artifact proxy URL %q: %s", proxyURL, err)
+			}
+			proxies = append(proxies, parsed)
+		}
+		return proxies, nil
+	case proxyConfig.NodeSelector != "":
+		selector, err := klabels.Parse(proxyConfig.NodeSelector)
+		if err != nil {
+			return nil, util.Errorf("Could not parse artifact_proxy.node_selector: %s", err)
+		}
+		if proxyConfig.Port == 0 {
+			return nil, util.Errorf("artifact_proxy.port must be set when using node_selector")
+		}
+		client, err := preparerConfig.GetConsulClient()
+		if err != nil {
+			return nil, err
+		}
+		return cacheproxy.NewLabelLocator(labels.NewConsulApplicator(client, 0, 0), selector, proxyConfig.Scheme, proxyConfig.Port), nil
+	default:
+		return nil, util.Errorf("artifact_proxy must specify urls or node_selector")
+	}
+}
+
+// withS3 wraps a fetcher so that it can also handle s3:// URIs, if an object
+// store is configured.
+func withS3(preparerConfig *PreparerConfig, fetcher uri.Fetcher, httpClient *http.Client) (uri.Fetcher, error) {
+	if preparerConfig.S3 == nil {
+		return fetcher, nil
+	}
+	s3Fetcher, err := uri.NewS3Fetcher(fetcher, httpClient, *preparerConfig.S3)
+	if err != nil {
+		return nil, util.Errorf("error configuring s3: %s", err)
+	}
+	return s3Fetcher, nil
+}
+
 func getArtifactRegistry(preparerConfig *PreparerConfig) (artifact.Registry, error) {
 	httpClient, err := preparerConfig.GetClient(30 * time.Second)
 	if err != nil {
 		return nil, err
 	}
 
-	fetcher := uri.BasicFetcher{
-		Client: httpClient,
+	fetcher, err := withS3(preparerConfig, uri.BasicFetcher{Client: httpClient}, httpClient)
+	if err != nil {
+		return nil, err
 	}
 
 	if preparerConfig.ArtifactRegistryURL == "" {
@@ -857,6 +1007,9 @@ func getArtifactRegistry(preparerConfig *PreparerConfig) (artifact.Registry, err
 		return nil, util.Errorf("Could not parse 'artifact_registry_url': %s", err)
 	}
 
+	if preparerConfig.ArtifactDownload != nil && preparerConfig.ArtifactDownload.Deltas {
+		return artifact.NewDeltaRegistry(url, fetcher, osversion.DefaultDetector), nil
+	}
 	return artifact.NewRegistry(url, fetcher, osversion.DefaultDetector), nil
 }
 
@@ -0,0 +1,86 @@
+package fields
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/square/p2/pkg/util"
+)
+
+// MetricSourceType distinguishes the places an autoscaler can read a metric
+// from.
+type MetricSourceType string
+
+const (
+	// HTTPMetricSource reads the metric from a JSON endpoint at a fixed
+	// URL, such as a monitoring system
+	HTTPMetricSource MetricSourceType = "http"
+
+	// PodMetricSource reads the metric from each of the RC's pods on the
+	// node they run on, and averages the results
+	PodMetricSource MetricSourceType = "pod"
+)
+
+// MetricSource describes where the autoscaler reads an RC's metric from.
+// Either way the metric is read from a JSON document of the form
+// {"value": 12.5}, and is expected to be a per-replica value such as the
+// average request rate or CPU utilization of a pod.
+type MetricSource struct {
+	Type MetricSourceType `json:"type"`
+
+	// URL of the endpoint, for HTTPMetricSource
+	URL string `json:"url,omitempty"`
+
+	// Path requested from each pod, for PodMetricSource. The request is
+	// sent to the pod's status port unless Port is set.
+	Path string `json:"path,omitempty"`
+	Port int    `json:"port,omitempty"`
+}
+
+// AutoscalePolicy opts an RC into having its replica count adjusted by the
+// autoscaler, which aims to keep the metric read from Metric at Target.
+type AutoscalePolicy struct {
+	MinReplicas int `json:"min_replicas"`
+	MaxReplicas int `json:"max_replicas"`
+
+	// The per-replica metric value the autoscaler aims for
+	Target float64 `json:"target"`
+
+	// The minimum time between two changes to the replica count
+	Cooldown time.Duration `json:"cooldown"`
+
+	Metric MetricSource `json:"metric"`
+}
+
+func (p AutoscalePolicy) Validate() error {
+	if p.MinReplicas < 0 {
+		return util.Errorf("autoscale policy must have a non-negative minimum replica count, got %d", p.MinReplicas)
+	}
+	if p.MaxReplicas < p.MinReplicas {
+		return util.Errorf("autoscale policy maximum replica count %d is less than its minimum %d", p.MaxReplicas, p.MinReplicas)
+	}
+	if p.Target <= 0 {
+		return util.Errorf("autoscale policy must have a positive target, got %v", p.Target)
+	}
+	if p.Cooldown < 0 {
+		return util.Errorf("autoscale policy must have a non-negative cooldown, got %s", p.Cooldown)
+	}
+
+	switch p.Metric.Type {
+	case HTTPMetricSource:
+		u, err := url.Parse(p.Metric.URL)
+		if err != nil {
+			return util.Errorf("autoscale policy has an invalid metric URL: %s", err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return util.Errorf("autoscale policy metric URL %q must be http or https", p.Metric.URL)
+		}
+	case PodMetricSource:
+		if p.Metric.Path == "" {
+			return util.Errorf("autoscale policy with a %s metric must have a path", PodMetricSource)
+		}
+	default:
+		return util.Errorf("autoscale policy has unknown metric source type %q", p.Metric.Type)
+	}
+	return nil
+}
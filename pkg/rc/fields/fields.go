@@ -42,6 +42,49 @@ const (
 	StaticStrategy  = Strategy("static_strategy")
 )
 
+// SpreadConstraint limits how unevenly an RC's pods may be spread across the
+// values of a node label, such as an availability zone or rack. The skew is
+// the difference between the number of pods in the most and least populated
+// domains, counting every domain that has an eligible node.
+//
+// Nodes that don't have the label are not part of any domain, and pods are
+// not scheduled on them while the constraint is in place.
+type SpreadConstraint struct {
+	// The node label whose values define the domains
+	TopologyKey string `json:"topology_key"`
+
+	// The largest permitted skew. Must be at least 1.
+	MaxSkew int `json:"max_skew"`
+}
+
+func (c SpreadConstraint) Validate() error {
+	if c.TopologyKey == "" {
+		return util.Errorf("spread constraint must have a topology key")
+	}
+	if c.MaxSkew < 1 {
+		return util.Errorf("spread constraint on %q must have a max skew of at least 1, got %d", c.TopologyKey, c.MaxSkew)
+	}
+	return nil
+}
+
+// ZoneFilter picks out the nodes whose value for a node label is one of a
+// set of values. Rolling updates use it to confine scheduling changes to one
+// zone at a time.
+type ZoneFilter struct {
+	// The node label, e.g. an availability zone label
+	Label string `json:"label"`
+
+	// The label values of the nodes that match
+	Values []string `json:"values"`
+}
+
+func (f ZoneFilter) Validate() error {
+	if f.Label == "" {
+		return util.Errorf("zone filter must have a label")
+	}
+	return nil
+}
+
 // RC holds the runtime state of a Resource Controller as saved in Consul.
 type RC struct {
 	// GUID for this controller
@@ -66,6 +109,20 @@ type RC struct {
 	// Distinguishes between dynamic, static or other strategies for allocating
 	// nodes on which the rc can schedule the manifest.
 	AllocationStrategy Strategy
+
+	// Limits on how unevenly pods may be spread across node label values
+	SpreadConstraints []SpreadConstraint
+
+	// If set, the autoscaler adjusts ReplicasDesired according to this
+	// policy
+	Autoscale *AutoscalePolicy
+
+	// If set, new pods are only scheduled on nodes matching this filter
+	ScheduleZones *ZoneFilter
+
+	// If set, pods on nodes matching this filter are unscheduled before
+	// any others, apart from those on ineligible nodes
+	UnscheduleZones *ZoneFilter
 }
 
 // RawRC defines the JSON format used to store data into Consul. It should only be used
@@ -83,6 +140,13 @@ type RawRC struct {
 	ReplicasDesired    *int     `json:"replicas_desired"`
 	Disabled           bool     `json:"disabled"`
 	AllocationStrategy Strategy `json:"allocation_strategy"`
+
+	SpreadConstraints []SpreadConstraint `json:"spread_constraints,omitempty"`
+
+	Autoscale *AutoscalePolicy `json:"autoscale,omitempty"`
+
+	ScheduleZones   *ZoneFilter `json:"schedule_zones,omitempty"`
+	UnscheduleZones *ZoneFilter `json:"unschedule_zones,omitempty"`
 }
 
 // MarshalJSON implements the json.Marshaler interface for serializing the RC to JSON
@@ -127,6 +191,10 @@ func (rc RC) ToRaw() (RawRC, error) {
 		ReplicasDesired:    &rc.ReplicasDesired,
 		Disabled:           rc.Disabled,
 		AllocationStrategy: rc.AllocationStrategy,
+		SpreadConstraints:  rc.SpreadConstraints,
+		Autoscale:          rc.Autoscale,
+		ScheduleZones:      rc.ScheduleZones,
+		UnscheduleZones:    rc.UnscheduleZones,
 	}, nil
 }
 
@@ -166,6 +234,10 @@ func (rc *RC) UnmarshalJSON(b []byte) error {
 		ReplicasDesired:    *rawRC.ReplicasDesired,
 		Disabled:           rawRC.Disabled,
 		AllocationStrategy: rawRC.AllocationStrategy,
+		SpreadConstraints:  rawRC.SpreadConstraints,
+		Autoscale:          rawRC.Autoscale,
+		ScheduleZones:      rawRC.ScheduleZones,
+		UnscheduleZones:    rawRC.UnscheduleZones,
 	}
 	return nil
 }
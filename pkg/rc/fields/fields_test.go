@@ -2,6 +2,7 @@ package fields
 
 import (
 	"encoding/json"
+	"reflect"
 	"testing"
 
 	. "github.com/anthonybishopric/gotcha"
@@ -42,3 +43,41 @@ func TestZeroUnmarshal(t *testing.T) {
 		t.Errorf("got an error unmarshaling an otherwise-empty RC with a replicas_desired count of 0: %s", err)
 	}
 }
+
+func TestSpreadConstraintsRoundTrip(t *testing.T) {
+	rc1 := RC{
+		ID:                "hello",
+		SpreadConstraints: []SpreadConstraint{{TopologyKey: "availability_zone", MaxSkew: 1}},
+	}
+
+	b, err := json.Marshal(&rc1)
+	Assert(t).IsNil(err, "should have marshaled")
+
+	var rc2 RC
+	err = json.Unmarshal(b, &rc2)
+	Assert(t).IsNil(err, "should have unmarshaled")
+	Assert(t).AreEqual(len(rc2.SpreadConstraints), 1, "spread constraints lost when serialized")
+	Assert(t).AreEqual(rc2.SpreadConstraints[0], rc1.SpreadConstraints[0], "spread constraint changed when serialized")
+
+	Assert(t).IsNotNil(SpreadConstraint{TopologyKey: "rack"}.Validate(), "expected a max skew of 0 to be invalid")
+	Assert(t).IsNotNil(SpreadConstraint{MaxSkew: 1}.Validate(), "expected a missing topology key to be invalid")
+}
+
+func TestZoneFiltersRoundTrip(t *testing.T) {
+	rc1 := RC{
+		ID:              "hello",
+		ScheduleZones:   &ZoneFilter{Label: "availability_zone", Values: []string{"a", "b"}},
+		UnscheduleZones: &ZoneFilter{Label: "availability_zone", Values: []string{"b"}},
+	}
+
+	b, err := json.Marshal(&rc1)
+	Assert(t).IsNil(err, "should have marshaled")
+
+	var rc2 RC
+	err = json.Unmarshal(b, &rc2)
+	Assert(t).IsNil(err, "should have unmarshaled")
+	Assert(t).IsTrue(reflect.DeepEqual(rc2.ScheduleZones, rc1.ScheduleZones), "schedule zones changed when serialized")
+	Assert(t).IsTrue(reflect.DeepEqual(rc2.UnscheduleZones, rc1.UnscheduleZones), "unschedule zones changed when serialized")
+
+	Assert(t).IsNotNil(ZoneFilter{Values: []string{"a"}}.Validate(), "expected a missing label to be invalid")
+}
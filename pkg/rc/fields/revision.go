@@ -0,0 +1,90 @@
+package fields
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/square/p2/pkg/manifest"
+)
+
+// Revision is one entry in the manifest history of an RC. Revision numbers
+// start at 1 and increase by one each time the RC's manifest changes,
+// including across rolling updates, so they can be used to refer to a
+// previous manifest when rolling back.
+type Revision struct {
+	// The position of this entry in the history
+	Number int
+
+	// The SHA of the manifest, as returned by manifest.Manifest.SHA()
+	SHA string
+
+	// Who made the change, if known
+	Author string
+
+	// When the change was recorded
+	Timestamp time.Time
+
+	// The manifest the RC had at this revision
+	Manifest manifest.Manifest
+}
+
+// RawRevision defines the JSON format used to store a Revision in Consul.
+type RawRevision struct {
+	Number    int       `json:"revision"`
+	SHA       string    `json:"sha"`
+	Author    string    `json:"author,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	Manifest  string    `json:"manifest"`
+}
+
+// MarshalJSON implements the json.Marshaler interface. Like RC, a Revision
+// holds a manifest.Manifest interface value which must be wrapped in order to
+// be unmarshaled again.
+func (r Revision) MarshalJSON() ([]byte, error) {
+	var manifestBytes []byte
+	if r.Manifest != nil {
+		var err error
+		manifestBytes, err = r.Manifest.Marshal()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return json.Marshal(RawRevision{
+		Number:    r.Number,
+		SHA:       r.SHA,
+		Author:    r.Author,
+		Timestamp: r.Timestamp,
+		Manifest:  string(manifestBytes),
+	})
+}
+
+var _ json.Marshaler = Revision{}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (r *Revision) UnmarshalJSON(b []byte) error {
+	var raw RawRevision
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	var m manifest.Manifest
+	if raw.Manifest != "" {
+		var err error
+		m, err = manifest.FromBytes([]byte(raw.Manifest))
+		if err != nil {
+			return err
+		}
+	}
+
+	*r = Revision{
+		Number:    raw.Number,
+		SHA:       raw.SHA,
+		Author:    raw.Author,
+		Timestamp: raw.Timestamp,
+		Manifest:  m,
+	}
+	return nil
+}
+
+var _ json.Unmarshaler = &Revision{}
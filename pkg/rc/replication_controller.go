@@ -6,6 +6,7 @@ import (
 	"math"
 	"math/rand"
 	"os"
+	"sort"
 	"time"
 
 	klabels "k8s.io/kubernetes/pkg/labels"
@@ -75,10 +76,11 @@ type Scheduler interface {
 	// DeallocateNodes() indicates to the scheduler that the RC has unscheduled
 	// the pod from these nodes, meaning the scheduler can free the
 	// resource reservations
-	DeallocateNodes(nodeSelector klabels.Selector, nodes []types.NodeName) error
+	DeallocateNodes(manifest manifest.Manifest, nodeSelector klabels.Selector, nodes []types.NodeName) error
 }
 
 var _ Scheduler = &scheduler.ApplicatorScheduler{}
+var _ Scheduler = &scheduler.ResourceScheduler{}
 var _ Scheduler = &grpc_scheduler.Client{}
 
 type ServiceDiscoveryChecker interface {
@@ -104,6 +106,11 @@ type consulStore interface {
 		podId types.PodID,
 	) (manifest.Manifest, time.Duration, error)
 
+	Pods(
+		podPrefix consul.PodPrefix,
+		locations types.PodLocations,
+	) (map[types.PodLocation]manifest.Manifest, error)
+
 	DeletePodTxn(
 		ctx context.Context,
 		podPrefix consul.PodPrefix,
@@ -231,7 +238,19 @@ func (rc *replicationController) WatchDesires(quit <-chan struct{}) <-chan error
 	return errOutChannel
 }
 
+// meetDesires schedules and unschedules pods to move the RC towards its
+// desired state, then records the outcome in the RC's status
 func (rc *replicationController) meetDesires(rcFields fields.RC) error {
+	err := rc.doMeetDesires(rcFields)
+	statusErr := rc.reportStatus(rcFields, err)
+	if statusErr != nil {
+		// Reporting is best effort and must not hold up scheduling
+		rc.logger.WithError(statusErr).Errorln("Could not report RC status")
+	}
+	return err
+}
+
+func (rc *replicationController) doMeetDesires(rcFields fields.RC) error {
 	rc.logger.NoFields().Infof("Handling RC update: desired replicas %d, disabled %v", rcFields.ReplicasDesired, rcFields.Disabled)
 
 	current, err := rc.CurrentPods()
@@ -298,12 +317,25 @@ func (rc *replicationController) addPods(rcFields fields.RC, current types.PodLo
 	// TODO: With Docker or runc we would not be constrained to running only once per node.
 	// So it may be the case that we need to make the Scheduler interface smarter and use it here.
 	possible := types.NewNodeSet(eligible...).Difference(types.NewNodeSet(currentNodes...))
+	if rcFields.ScheduleZones != nil {
+		inZones, err := rc.nodesInZones(rcFields.ScheduleZones)
+		if err != nil {
+			return err
+		}
+		possible = possible.Intersection(inZones)
+	}
 
 	// Users want deterministic ordering of nodes being populated to a new
 	// RC. Move nodes in sorted order by hostname to achieve this
 	possibleSorted := possible.ListNodes()
 	toSchedule := rcFields.ReplicasDesired - len(currentNodes)
 
+	topo, err := rc.loadTopology(rcFields, eligible)
+	if err != nil {
+		return err
+	}
+	placed := append([]types.NodeName{}, currentNodes...)
+
 	rc.logger.NoFields().Infof("Need to schedule %d nodes out of %s", toSchedule, possible)
 
 	txn, cancelFunc := rc.newAuditingTransaction(context.Background(), rcFields, currentNodes)
@@ -330,11 +362,18 @@ func (rc *replicationController) addPods(rcFields fields.RC, current types.PodLo
 			cancelFunc()
 			txn, cancelFunc = rc.newAuditingTransaction(context.Background(), rcFields, txn.Nodes())
 		}
-		if len(possibleSorted) < i+1 {
+		scheduleOn, ok := topo.pickAdd(placed, possibleSorted)
+		if !ok {
 			errMsg := fmt.Sprintf(
 				"Not enough nodes to meet desire: %d replicas desired, %d currentNodes, %d eligible. Scheduled on %d nodes instead.",
 				rcFields.ReplicasDesired, len(currentNodes), len(eligible), i,
 			)
+			if len(possibleSorted) > 0 {
+				errMsg = fmt.Sprintf(
+					"Not enough nodes to meet desire without violating spread constraints: %d replicas desired, %d currentNodes, %d eligible. Scheduled on %d nodes instead.",
+					rcFields.ReplicasDesired, len(currentNodes), len(eligible), i,
+				)
+			}
 			err := rc.alerter.Alert(rc.alertInfo(rcFields, errMsg), alerting.LowUrgency)
 			if err != nil {
 				rc.logger.WithError(err).Errorln("Unable to send alert")
@@ -351,7 +390,8 @@ func (rc *replicationController) addPods(rcFields fields.RC, current types.PodLo
 
 			return util.Errorf(errMsg)
 		}
-		scheduleOn := possibleSorted[i]
+		possibleSorted = withoutNode(possibleSorted, scheduleOn)
+		placed = append(placed, scheduleOn)
 
 		err := rc.schedule(txn, rcFields, scheduleOn)
 		if err != nil {
@@ -404,9 +444,24 @@ func (rc *replicationController) removePods(rcFields fields.RC, current types.Po
 	// TODO: evaluate changes to 'eligible' more frequently
 	ineligible := types.NewNodeSet(currentNodes...).Difference(types.NewNodeSet(eligible...))
 	rest := types.NewNodeSet(currentNodes...).Difference(ineligible)
+	// Then prefer any in the zones the RC is being unscheduled from
+	inZones, err := rc.nodesInZones(rcFields.UnscheduleZones)
+	if err != nil {
+		return err
+	}
+	restInZones := rest.Intersection(inZones)
+	rest = rest.Difference(inZones)
 	toUnschedule := len(current) - rcFields.ReplicasDesired
 	rc.logger.NoFields().Infof("Need to unschedule %d nodes out of %s", toUnschedule, current)
 
+	// Among eligible nodes, remove from the most populated domains first
+	// so that the remaining pods stay spread out
+	topo, err := rc.loadTopology(rcFields, eligible)
+	if err != nil {
+		return err
+	}
+	placed := append([]types.NodeName{}, currentNodes...)
+
 	txn, cancelFunc := rc.newAuditingTransaction(context.Background(), rcFields, currentNodes)
 	defer func() {
 		cancelFunc()
@@ -432,7 +487,12 @@ func (rc *replicationController) removePods(rcFields fields.RC, current types.Po
 		unscheduleFrom, ok := ineligible.PopAny()
 		if !ok {
 			var ok bool
-			unscheduleFrom, ok = rest.PopAny()
+			unscheduleFrom, ok = topo.pickRemove(placed, restInZones.ListNodes())
+			restInZones.DeleteNode(unscheduleFrom)
+			if !ok {
+				unscheduleFrom, ok = topo.pickRemove(placed, rest.ListNodes())
+				rest.DeleteNode(unscheduleFrom)
+			}
 			if !ok {
 				// This should be mathematically impossible unless replicasDesired was negative
 				// commit any queued operations
@@ -451,6 +511,8 @@ func (rc *replicationController) removePods(rcFields fields.RC, current types.Po
 			}
 		}
 
+		placed = withoutNode(placed, unscheduleFrom)
+
 		err := rc.unschedule(txn, rcFields, unscheduleFrom)
 		if err != nil {
 			return err
@@ -824,6 +886,20 @@ func (rc *replicationController) swapNodes(rcFields fields.RC, current types.Pod
 	}
 	newNode := nodes[0]
 
+	if len(rcFields.SpreadConstraints) > 0 {
+		err = rc.checkTransferSpread(rcFields, current, ineligible, newNode)
+		if err != nil {
+			deallocErr := rc.retryDeallocate(rcFields, newNode, allocAttempts)
+			if deallocErr != nil {
+				return &incorrectAllocationError{
+					err:             err,
+					needsDeallocate: newNode,
+				}
+			}
+			return err
+		}
+	}
+
 	err = rc.retryDeallocate(rcFields, ineligible, allocAttempts)
 	if err != nil {
 		return &incorrectAllocationError{
@@ -869,6 +945,31 @@ func (rc *replicationController) swapNodes(rcFields fields.RC, current types.Pod
 	return nil
 }
 
+// checkTransferSpread returns an error if moving a pod from one node to
+// another would take the RC further from satisfying its spread constraints
+func (rc *replicationController) checkTransferSpread(rcFields fields.RC, current types.PodLocations, oldNode types.NodeName, newNode types.NodeName) error {
+	eligible, err := rc.eligibleNodes(rcFields)
+	if err != nil {
+		return err
+	}
+	topo, err := rc.loadTopology(rcFields, eligible)
+	if err != nil {
+		return err
+	}
+
+	before := current.Nodes()
+	after := append(withoutNode(before, oldNode), newNode)
+	for i := range topo.constraints {
+		if _, ok := topo.nodeDomains[i][newNode]; !ok {
+			return util.Errorf("node transfer target %s has no %q label", newNode, topo.constraints[i].TopologyKey)
+		}
+	}
+	if !topo.permits(before, after) {
+		return util.Errorf("node transfer from %s to %s would violate spread constraints", oldNode, newNode)
+	}
+	return nil
+}
+
 func (rc *replicationController) retryDeallocate(rcFields fields.RC, ineligible types.NodeName, attempts int) error {
 	for i := 0; i < attempts; i++ {
 		backoff := time.Duration(math.Pow(float64(i), 2)) * time.Second
@@ -876,7 +977,7 @@ func (rc *replicationController) retryDeallocate(rcFields fields.RC, ineligible
 			backoff = 1 * time.Minute
 		}
 		time.Sleep(backoff)
-		err := rc.scheduler.DeallocateNodes(rcFields.NodeSelector, []types.NodeName{ineligible})
+		err := rc.scheduler.DeallocateNodes(rcFields.Manifest, rcFields.NodeSelector, []types.NodeName{ineligible})
 		if err != nil {
 			rc.logger.WithError(err).Errorf("node transfer deallocate attempt %d failed", i+1)
 			continue
@@ -908,6 +1009,7 @@ func (rc *replicationController) checkMissingArtifacts(rcFields fields.RC) {
 	}
 	podID := rcFields.Manifest.ID()
 	launchableStanzas := rcFields.Manifest.GetLaunchableStanzas()
+	var missing []rcstatus.MissingArtifact
 	for launchableID, launchableStanza := range launchableStanzas {
 		if launchableStanza.LaunchableType == "docker" {
 			// don't check for missing docker images for now
@@ -925,6 +1027,10 @@ func (rc *replicationController) checkMissingArtifacts(rcFields fields.RC) {
 			continue
 		}
 		if !exists {
+			missing = append(missing, rcstatus.MissingArtifact{
+				LaunchableID: launchableID.String(),
+				URL:          artifactUrl.String(),
+			})
 			hostname, _ := os.Hostname()
 			rc.logger.WithFields(logrus.Fields{
 				"Description":  fmt.Sprintf("This RC is missing an artifact at url %s for launchable id %s", artifactUrl, launchableID),
@@ -936,4 +1042,10 @@ func (rc *replicationController) checkMissingArtifacts(rcFields fields.RC) {
 			}).Errorln("RC references artifact not available in the registry")
 		}
 	}
+
+	sort.Slice(missing, func(i, j int) bool { return missing[i].LaunchableID < missing[j].LaunchableID })
+	err := rc.reportMissingArtifacts(missing)
+	if err != nil {
+		rc.logger.WithError(err).Errorln("Could not report missing artifacts")
+	}
 }
@@ -92,7 +92,7 @@ func (s testScheduler) AllocateNodes(manifest manifest.Manifest, nodeSelector kl
 	return []types.NodeName{newTransferNode}, nil
 }
 
-func (s testScheduler) DeallocateNodes(nodeSelector klabels.Selector, nodes []types.NodeName) error {
+func (s testScheduler) DeallocateNodes(man manifest.Manifest, nodeSelector klabels.Selector, nodes []types.NodeName) error {
 	if s.deallocateShouldErr {
 		return util.Errorf("Intentional deallocate error")
 	}
@@ -0,0 +1,243 @@
+package rc
+
+import (
+	"reflect"
+	"sort"
+
+	klabels "k8s.io/kubernetes/pkg/labels"
+
+	"github.com/square/p2/pkg/labels"
+	"github.com/square/p2/pkg/rc/fields"
+	"github.com/square/p2/pkg/store/consul/statusstore/rcstatus"
+	"github.com/square/p2/pkg/types"
+	"github.com/square/p2/pkg/util"
+)
+
+// topology holds what an RC needs to know about node labels to honor its
+// spread constraints. A topology with no constraints places no restrictions
+// on scheduling.
+type topology struct {
+	constraints []fields.SpreadConstraint
+
+	// For each constraint, the domain (label value) of every node that has
+	// the constraint's label
+	nodeDomains []map[types.NodeName]string
+
+	// For each constraint, the domains that have at least one eligible
+	// node. These count towards the skew even when they hold no pods.
+	eligibleDomains []map[string]bool
+}
+
+func (rc *replicationController) loadTopology(rcFields fields.RC, eligible []types.NodeName) (*topology, error) {
+	t := &topology{constraints: rcFields.SpreadConstraints}
+	for _, constraint := range rcFields.SpreadConstraints {
+		selector := klabels.Everything().Add(constraint.TopologyKey, klabels.ExistsOperator, []string{})
+		matches, err := rc.podApplicator.GetMatches(selector, labels.NODE)
+		if err != nil {
+			return nil, util.Errorf("could not fetch node labels for spread constraint on %q: %s", constraint.TopologyKey, err)
+		}
+
+		nodeDomains := make(map[types.NodeName]string, len(matches))
+		for _, match := range matches {
+			nodeDomains[types.NodeName(match.ID)] = match.Labels.Get(constraint.TopologyKey)
+		}
+		eligibleDomains := make(map[string]bool)
+		for _, node := range eligible {
+			if domain, ok := nodeDomains[node]; ok {
+				eligibleDomains[domain] = true
+			}
+		}
+
+		t.nodeDomains = append(t.nodeDomains, nodeDomains)
+		t.eligibleDomains = append(t.eligibleDomains, eligibleDomains)
+	}
+	return t, nil
+}
+
+// podsPerDomain counts the pods placed in each domain of a constraint.
+// Placements on nodes without the constraint's label are not counted.
+func (t *topology) podsPerDomain(i int, placed []types.NodeName) map[string]int {
+	counts := make(map[string]int)
+	for domain := range t.eligibleDomains[i] {
+		counts[domain] = 0
+	}
+	for _, node := range placed {
+		if domain, ok := t.nodeDomains[i][node]; ok {
+			counts[domain]++
+		}
+	}
+	return counts
+}
+
+func skew(counts map[string]int) int {
+	if len(counts) == 0 {
+		return 0
+	}
+	first := true
+	var min, max int
+	for _, count := range counts {
+		if first || count < min {
+			min = count
+		}
+		if first || count > max {
+			max = count
+		}
+		first = false
+	}
+	return max - min
+}
+
+// permits returns true if moving from one placement to another leaves every
+// constraint satisfied, or at least no further from being satisfied.
+func (t *topology) permits(before []types.NodeName, after []types.NodeName) bool {
+	for i, constraint := range t.constraints {
+		afterSkew := skew(t.podsPerDomain(i, after))
+		if afterSkew > constraint.MaxSkew && afterSkew > skew(t.podsPerDomain(i, before)) {
+			return false
+		}
+	}
+	return true
+}
+
+// pickAdd chooses the candidate to schedule the next pod on: the one in the
+// least populated domain, considering constraints in order, among those that
+// don't violate any constraint. Candidates must be sorted; ties go to the
+// first. It returns false if no candidate is acceptable.
+func (t *topology) pickAdd(placed []types.NodeName, candidates []types.NodeName) (types.NodeName, bool) {
+	if len(t.constraints) == 0 {
+		if len(candidates) == 0 {
+			return "", false
+		}
+		return candidates[0], true
+	}
+
+	var best types.NodeName
+	var bestCounts []int
+	found := false
+
+	counts := make([]map[string]int, len(t.constraints))
+	skews := make([]int, len(t.constraints))
+	for i := range t.constraints {
+		counts[i] = t.podsPerDomain(i, placed)
+		skews[i] = skew(counts[i])
+	}
+
+candidates:
+	for _, candidate := range candidates {
+		candidateCounts := make([]int, len(t.constraints))
+		for i, constraint := range t.constraints {
+			domain, ok := t.nodeDomains[i][candidate]
+			if !ok {
+				continue candidates
+			}
+			candidateCounts[i] = counts[i][domain]
+
+			// The same check as permits(), without recounting every
+			// placed pod for each candidate
+			counts[i][domain]++
+			afterSkew := skew(counts[i])
+			counts[i][domain]--
+			if afterSkew > constraint.MaxSkew && afterSkew > skews[i] {
+				continue candidates
+			}
+		}
+		if !found || lessCounts(candidateCounts, bestCounts) {
+			best, bestCounts, found = candidate, candidateCounts, true
+		}
+	}
+	return best, found
+}
+
+// pickRemove chooses the candidate to unschedule a pod from: one on a node
+// outside every domain if there is one, otherwise the one in the most
+// populated domain, considering constraints in order. Candidates must be
+// sorted; ties go to the first.
+func (t *topology) pickRemove(placed []types.NodeName, candidates []types.NodeName) (types.NodeName, bool) {
+	if len(candidates) == 0 {
+		return "", false
+	}
+
+	counts := make([]map[string]int, len(t.constraints))
+	for i := range t.constraints {
+		counts[i] = t.podsPerDomain(i, placed)
+	}
+
+	best := candidates[0]
+	var bestCounts []int
+	for j, candidate := range candidates {
+		candidateCounts := make([]int, len(t.constraints))
+		for i := range t.constraints {
+			domain, ok := t.nodeDomains[i][candidate]
+			if !ok {
+				// Pods outside any domain don't help the spread
+				return candidate, true
+			}
+			candidateCounts[i] = counts[i][domain]
+		}
+		if j == 0 || lessCounts(bestCounts, candidateCounts) {
+			best, bestCounts = candidate, candidateCounts
+		}
+	}
+	return best, true
+}
+
+func lessCounts(a []int, b []int) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
+
+func (t *topology) violations(placed []types.NodeName) []rcstatus.SpreadViolation {
+	var violations []rcstatus.SpreadViolation
+	for i, constraint := range t.constraints {
+		counts := t.podsPerDomain(i, placed)
+		if s := skew(counts); s > constraint.MaxSkew {
+			violations = append(violations, rcstatus.SpreadViolation{
+				TopologyKey:   constraint.TopologyKey,
+				MaxSkew:       constraint.MaxSkew,
+				Skew:          s,
+				PodsPerDomain: counts,
+			})
+		}
+	}
+	return violations
+}
+
+func withoutNode(nodes []types.NodeName, node types.NodeName) []types.NodeName {
+	result := make([]types.NodeName, 0, len(nodes))
+	for _, n := range nodes {
+		if n != node {
+			result = append(result, n)
+		}
+	}
+	return result
+}
+
+// spreadViolations returns the RC's spread constraint violations, logging
+// them if they differ from those last reported.
+func (rc *replicationController) spreadViolations(rcFields fields.RC, placed []types.NodeName, eligible []types.NodeName, reported []rcstatus.SpreadViolation) ([]rcstatus.SpreadViolation, error) {
+	if len(rcFields.SpreadConstraints) == 0 {
+		return nil, nil
+	}
+	topo, err := rc.loadTopology(rcFields, eligible)
+	if err != nil {
+		return nil, err
+	}
+
+	placed = append([]types.NodeName{}, placed...)
+	sort.Slice(placed, func(i, j int) bool { return placed[i] < placed[j] })
+	violations := topo.violations(placed)
+	if reflect.DeepEqual(violations, reported) {
+		return violations, nil
+	}
+	for _, violation := range violations {
+		rc.logger.WithField("topology_key", violation.TopologyKey).Warnf(
+			"Pods are spread with a skew of %d, more than the maximum of %d: %v",
+			violation.Skew, violation.MaxSkew, violation.PodsPerDomain,
+		)
+	}
+	return violations, nil
+}
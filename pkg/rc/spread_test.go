@@ -0,0 +1,218 @@
+// +build !race
+
+package rc
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/square/p2/pkg/labels"
+	"github.com/square/p2/pkg/rc/fields"
+	"github.com/square/p2/pkg/store/consul/rcstore"
+	"github.com/square/p2/pkg/types"
+)
+
+func zoneTopology(maxSkew int, zones map[types.NodeName]string, eligible ...types.NodeName) *topology {
+	eligibleDomains := make(map[string]bool)
+	for _, node := range eligible {
+		if zone, ok := zones[node]; ok {
+			eligibleDomains[zone] = true
+		}
+	}
+	return &topology{
+		constraints:     []fields.SpreadConstraint{{TopologyKey: "zone", MaxSkew: maxSkew}},
+		nodeDomains:     []map[types.NodeName]string{zones},
+		eligibleDomains: []map[string]bool{eligibleDomains},
+	}
+}
+
+func TestPickAddPrefersLeastPopulatedDomain(t *testing.T) {
+	zones := map[types.NodeName]string{
+		"a1": "a", "a2": "a", "a3": "a",
+		"b1": "b", "b2": "b",
+	}
+	topo := zoneTopology(1, zones, "a1", "a2", "a3", "b1", "b2")
+
+	var placed []types.NodeName
+	candidates := []types.NodeName{"a1", "a2", "a3", "b1", "b2"}
+	var order []types.NodeName
+	for {
+		node, ok := topo.pickAdd(placed, candidates)
+		if !ok {
+			break
+		}
+		order = append(order, node)
+		placed = append(placed, node)
+		candidates = withoutNode(candidates, node)
+	}
+
+	expected := []types.NodeName{"a1", "b1", "a2", "b2", "a3"}
+	if len(order) != len(expected) {
+		t.Fatalf("Expected placements %v, got %v", expected, order)
+	}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Fatalf("Expected placements %v, got %v", expected, order)
+		}
+	}
+}
+
+func TestPickAddRespectsMaxSkew(t *testing.T) {
+	zones := map[types.NodeName]string{
+		"a1": "a", "a2": "a", "a3": "a",
+		"b1": "b",
+	}
+	topo := zoneTopology(1, zones, "a1", "a2", "a3", "b1", "unlabeled")
+
+	// Zone b is full, so zone a may only get one pod more than it
+	placed := []types.NodeName{"a1", "b1"}
+	node, ok := topo.pickAdd(placed, []types.NodeName{"a2", "a3", "unlabeled"})
+	if !ok || node != "a2" {
+		t.Fatalf("Expected a2 to be picked, got %q %t", node, ok)
+	}
+	placed = append(placed, node)
+	node, ok = topo.pickAdd(placed, []types.NodeName{"a3", "unlabeled"})
+	if ok {
+		t.Errorf("Expected no node to be acceptable, got %s", node)
+	}
+
+	violations := topo.violations(append(placed, "a3"))
+	if len(violations) != 1 || violations[0].Skew != 2 || violations[0].PodsPerDomain["a"] != 3 {
+		t.Errorf("Expected a skew violation to be reported, got %+v", violations)
+	}
+}
+
+func TestPickRemovePrefersMostPopulatedDomain(t *testing.T) {
+	zones := map[types.NodeName]string{
+		"a1": "a", "a2": "a", "a3": "a",
+		"b1": "b", "b2": "b",
+	}
+	topo := zoneTopology(1, zones, "a1", "a2", "a3", "b1", "b2")
+
+	placed := []types.NodeName{"a1", "a2", "a3", "b1", "b2"}
+	node, ok := topo.pickRemove(placed, placed)
+	if !ok || zones[node] != "a" {
+		t.Fatalf("Expected a node in zone a to be picked, got %q", node)
+	}
+	placed = withoutNode(placed, node)
+
+	// Now the zones are level, ties go to the first candidate
+	node, _ = topo.pickRemove(placed, placed)
+	if node != placed[0] {
+		t.Errorf("Expected %s to be picked, got %s", placed[0], node)
+	}
+}
+
+func TestAddPodsSpreadsAcrossZones(t *testing.T) {
+	rcStore, _, applicator, rc, _, _, rcStatusStore, closeFn := setup(t)
+	defer closeFn()
+
+	for node, zone := range map[string]string{
+		"node1": "a", "node2": "a", "node3": "a", "node4": "a",
+		"node5": "b", "node6": "b",
+	} {
+		for key, value := range map[string]string{"nodeQuality": "good", "zone": zone} {
+			err := applicator.SetLabel(labels.NODE, node, key, value)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	err := rcStore.(*rcstore.ConsulStore).UpdateSpreadConstraints(rc.rcID, []fields.SpreadConstraint{{TopologyKey: "zone", MaxSkew: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = rcStore.SetDesiredReplicas(rc.rcID, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rcFields, err := rcStore.Get(rc.rcID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = rc.meetDesires(rcFields)
+	if err != nil {
+		t.Fatalf("Unexpected error meeting desires: %s", err)
+	}
+
+	assertNodes := func(expected ...types.NodeName) {
+		current, err := rc.CurrentPods()
+		if err != nil {
+			t.Fatal(err)
+		}
+		nodes := current.Nodes()
+		sort.Slice(nodes, func(i, j int) bool { return nodes[i] < nodes[j] })
+		if len(nodes) != len(expected) {
+			t.Fatalf("Expected pods on %v, got %v", expected, nodes)
+		}
+		for i := range expected {
+			if nodes[i] != expected[i] {
+				t.Fatalf("Expected pods on %v, got %v", expected, nodes)
+			}
+		}
+	}
+	assertNodes("node1", "node2", "node5", "node6")
+
+	// Zone b is full, so zone a can take one more pod but a sixth would
+	// exceed the max skew
+	err = rcStore.SetDesiredReplicas(rc.rcID, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rcFields, err = rcStore.Get(rc.rcID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = rc.meetDesires(rcFields)
+	if err == nil {
+		t.Error("Expected an error when spread constraints prevent meeting desires")
+	}
+	assertNodes("node1", "node2", "node3", "node5", "node6")
+
+	// Scaling down removes from the most populated zone once they're uneven
+	err = applicator.SetLabel(labels.NODE, "node6", "zone", "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = rcStore.SetDesiredReplicas(rc.rcID, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rcFields, err = rcStore.Get(rc.rcID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = rc.meetDesires(rcFields)
+	if err != nil {
+		t.Fatalf("Unexpected error meeting desires: %s", err)
+	}
+	assertNodes("node3", "node5", "node6")
+
+	// node3 and node6 are in zone a and node5 in zone b, which is within
+	// the max skew, so there is nothing to report
+	status, _, err := rcStatusStore.Get(rc.rcID)
+	if err == nil && len(status.SpreadViolations) != 0 {
+		t.Errorf("Expected no spread violations, got %+v", status.SpreadViolations)
+	}
+
+	err = applicator.SetLabel(labels.NODE, "node5", "zone", "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = applicator.SetLabel(labels.NODE, "node4", "zone", "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = rc.meetDesires(rcFields)
+	if err != nil {
+		t.Fatalf("Unexpected error meeting desires: %s", err)
+	}
+	status, _, err = rcStatusStore.Get(rc.rcID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status.SpreadViolations) != 1 || status.SpreadViolations[0].Skew != 3 {
+		t.Errorf("Expected a spread violation with skew 3 to be reported, got %+v", status.SpreadViolations)
+	}
+}
@@ -0,0 +1,195 @@
+package rc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/square/p2/pkg/health"
+	"github.com/square/p2/pkg/rc/fields"
+	"github.com/square/p2/pkg/store/consul"
+	"github.com/square/p2/pkg/store/consul/statusstore"
+	"github.com/square/p2/pkg/store/consul/statusstore/rcstatus"
+	"github.com/square/p2/pkg/store/consul/transaction"
+	"github.com/square/p2/pkg/types"
+)
+
+// statusRefreshInterval is how often the RC's status is rewritten when
+// nothing in it has changed, so that its LastUpdateTime shows the RC is still
+// being processed
+const statusRefreshInterval = 5 * time.Minute
+
+// reportStatus refreshes the RC's status document after an attempt to meet
+// its desires, which failed if meetErr is non-nil. Fields that are owned by
+// other processes, such as the node transfer, are left as they are.
+func (rc *replicationController) reportStatus(rcFields fields.RC, meetErr error) error {
+	current, err := rc.CurrentPods()
+	if err != nil {
+		return err
+	}
+	eligible, err := rc.eligibleNodes(rcFields)
+	if err != nil {
+		return err
+	}
+	replicasCurrent, err := rc.countCurrent(rcFields, current)
+	if err != nil {
+		return err
+	}
+	healthy, unhealthy, healthErr := rc.countHealth(rcFields, current)
+	if healthErr != nil {
+		// Health is reported elsewhere too, so don't let an outage of
+		// the health checker hide the rest of the status
+		rc.logger.WithError(healthErr).Warnln("Could not count healthy replicas for RC status")
+	}
+
+	return rc.mutateStatus(func(status *rcstatus.Status, now time.Time) error {
+		status.ReplicasDesired = rcFields.ReplicasDesired
+		status.ReplicasScheduled = len(current)
+		status.IneligibleNodes = rc.checkForIneligible(current, eligible)
+		status.ReplicasCurrent = replicasCurrent
+		if healthErr == nil {
+			status.ReplicasHealthy, status.ReplicasUnhealthy = healthy, unhealthy
+		}
+		var err error
+		status.SpreadViolations, err = rc.spreadViolations(rcFields, current.Nodes(), eligible, status.SpreadViolations)
+		if err != nil {
+			return err
+		}
+
+		status.SetCondition(
+			rcstatus.ReplicasMet,
+			status.ReplicasScheduled == status.ReplicasDesired,
+			fmt.Sprintf("%d of %d desired replicas are scheduled", status.ReplicasScheduled, status.ReplicasDesired),
+			now,
+		)
+		status.SetCondition(
+			rcstatus.Healthy,
+			status.ReplicasScheduled == status.ReplicasDesired && status.ReplicasUnhealthy == 0,
+			fmt.Sprintf("%d of %d scheduled replicas are healthy", status.ReplicasHealthy, status.ReplicasScheduled),
+			now,
+		)
+
+		if meetErr != nil {
+			status.LastError = meetErr.Error()
+			status.LastErrorTime = &now
+		} else {
+			status.LastError = ""
+			status.LastErrorTime = nil
+		}
+		return nil
+	})
+}
+
+// mutateStatus reads the RC's status, applies mutate to it and writes it back
+// with a check-and-set, so that a concurrent update of other fields is never
+// overwritten. The write is skipped if mutate changed nothing but timestamps
+// and the status was refreshed recently.
+func (rc *replicationController) mutateStatus(mutate func(status *rcstatus.Status, now time.Time) error) error {
+	status, queryMeta, err := rc.rcStatusStore.Get(rc.rcID)
+	var modifyIndex uint64
+	switch {
+	case statusstore.IsNoStatus(err):
+		// An index of 0 only creates the status if it still doesn't
+		// exist
+	case err != nil:
+		return err
+	default:
+		modifyIndex = queryMeta.LastIndex
+	}
+	now := time.Now()
+
+	before, err := comparableStatus(status)
+	if err != nil {
+		return err
+	}
+	err = mutate(&status, now)
+	if err != nil {
+		return err
+	}
+	after, err := comparableStatus(status)
+	if err != nil {
+		return err
+	}
+	if modifyIndex != 0 && bytes.Equal(before, after) && now.Sub(status.LastUpdateTime) < statusRefreshInterval {
+		return nil
+	}
+	status.LastUpdateTime = now
+
+	ctx, cancel := transaction.New(context.Background())
+	defer cancel()
+	err = rc.rcStatusStore.CASTxn(ctx, rc.rcID, modifyIndex, status)
+	if err != nil {
+		return err
+	}
+	return transaction.MustCommit(ctx, rc.txner)
+}
+
+// comparableStatus serializes a status without the times at which it was
+// refreshed, which change on every pass
+func comparableStatus(status rcstatus.Status) ([]byte, error) {
+	status.LastUpdateTime = time.Time{}
+	status.LastErrorTime = nil
+	status.MissingArtifactsCheckTime = nil
+	return json.Marshal(status)
+}
+
+// countCurrent counts the scheduled pods whose installed manifest is the
+// RC's, according to the reality tree
+func (rc *replicationController) countCurrent(rcFields fields.RC, current types.PodLocations) (int, error) {
+	wantSHA, err := rcFields.Manifest.SHA()
+	if err != nil {
+		return 0, err
+	}
+
+	installed, err := rc.consulStore.Pods(consul.REALITY_TREE, current)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, podManifest := range installed {
+		sha, err := podManifest.SHA()
+		if err != nil {
+			return 0, err
+		}
+		if sha == wantSHA {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// countHealth counts the scheduled pods that are passing their health checks
+// and those that aren't. Pods without a health result count as unhealthy.
+func (rc *replicationController) countHealth(rcFields fields.RC, current types.PodLocations) (int, int, error) {
+	if len(current) == 0 {
+		return 0, 0, nil
+	}
+	healths, err := rc.healthChecker.Service(rcFields.Manifest.ID().String())
+	if err != nil {
+		return 0, 0, err
+	}
+
+	healthy := 0
+	for _, pod := range current {
+		if result, ok := healths[pod.Node]; ok && result.Status == health.Passing {
+			healthy++
+		}
+	}
+	return healthy, len(current) - healthy, nil
+}
+
+// reportMissingArtifacts records the result of a check for missing artifacts
+func (rc *replicationController) reportMissingArtifacts(missing []rcstatus.MissingArtifact) error {
+	return rc.mutateStatus(func(status *rcstatus.Status, now time.Time) error {
+		status.MissingArtifacts = missing
+		status.MissingArtifactsCheckTime = &now
+		message := "all artifacts are in the registry"
+		if len(missing) > 0 {
+			message = fmt.Sprintf("%d launchables are missing artifacts", len(missing))
+		}
+		status.SetCondition(rcstatus.ArtifactsAvailable, len(missing) == 0, message, now)
+		return nil
+	})
+}
@@ -0,0 +1,112 @@
+// +build !race
+
+package rc
+
+import (
+	"testing"
+
+	"github.com/square/p2/pkg/labels"
+	"github.com/square/p2/pkg/store/consul/statusstore/rcstatus"
+)
+
+func TestMeetDesiresReportsStatus(t *testing.T) {
+	rcStore, _, applicator, rc, _, _, rcStatusStore, closeFn := setup(t)
+	defer closeFn()
+
+	for _, node := range []string{"node1", "node2"} {
+		err := applicator.SetLabel(labels.NODE, node, "nodeQuality", "good")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := applicator.SetLabel(labels.NODE, "node3", "nodeQuality", "bad")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = rcStore.SetDesiredReplicas(rc.rcID, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rcFields, err := rcStore.Get(rc.rcID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = rc.meetDesires(rcFields)
+	if err != nil {
+		t.Fatalf("Unexpected error meeting desires: %s", err)
+	}
+
+	status, _, err := rcStatusStore.Get(rc.rcID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.ReplicasDesired != 2 || status.ReplicasScheduled != 2 {
+		t.Errorf("Expected 2 of 2 replicas to be scheduled, got %d of %d", status.ReplicasScheduled, status.ReplicasDesired)
+	}
+	if status.ReplicasCurrent != 0 {
+		t.Errorf("Expected no replicas to be installed yet, got %d", status.ReplicasCurrent)
+	}
+	if condition, ok := status.Condition(rcstatus.ReplicasMet); !ok || !condition.Status {
+		t.Errorf("Expected the replicas met condition to be true, got %+v", status.Conditions)
+	}
+	if status.LastError != "" || status.LastUpdateTime.IsZero() {
+		t.Errorf("Expected an update time and no error, got %+v", status)
+	}
+
+	// Nothing changed, so the status shouldn't be rewritten
+	_, queryMeta, err := rcStatusStore.Get(rc.rcID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = rc.meetDesires(rcFields)
+	if err != nil {
+		t.Fatalf("Unexpected error meeting desires: %s", err)
+	}
+	_, unchangedMeta, err := rcStatusStore.Get(rc.rcID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unchangedMeta.LastIndex != queryMeta.LastIndex {
+		t.Errorf("Expected an unchanged status not to be written, but its index moved from %d to %d", queryMeta.LastIndex, unchangedMeta.LastIndex)
+	}
+
+	// Only two nodes are eligible, so a third replica can't be scheduled
+	err = rcStore.SetDesiredReplicas(rc.rcID, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rcFields, err = rcStore.Get(rc.rcID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = rc.meetDesires(rcFields)
+	if err == nil {
+		t.Fatal("Expected an error when there are too few eligible nodes")
+	}
+
+	status, _, err = rcStatusStore.Get(rc.rcID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.LastError == "" || status.LastErrorTime == nil {
+		t.Errorf("Expected the error to be recorded, got %+v", status)
+	}
+	if condition, ok := status.Condition(rcstatus.ReplicasMet); !ok || condition.Status {
+		t.Errorf("Expected the replicas met condition to be false, got %+v", status.Conditions)
+	}
+
+	// Making a node ineligible shows up in the status
+	err = applicator.SetLabel(labels.NODE, "node2", "nodeQuality", "bad")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = rc.meetDesires(rcFields)
+	status, _, err = rcStatusStore.Get(rc.rcID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status.IneligibleNodes) != 1 || status.IneligibleNodes[0] != "node2" {
+		t.Errorf("Expected node2 to be reported as ineligible, got %v", status.IneligibleNodes)
+	}
+}
@@ -0,0 +1,36 @@
+package rc
+
+import (
+	klabels "k8s.io/kubernetes/pkg/labels"
+
+	"github.com/square/p2/pkg/labels"
+	"github.com/square/p2/pkg/rc/fields"
+	"github.com/square/p2/pkg/types"
+	"github.com/square/p2/pkg/util"
+)
+
+// nodesInZones returns the set of nodes matching a zone filter. A nil filter
+// matches nothing.
+func (rc *replicationController) nodesInZones(filter *fields.ZoneFilter) (types.NodeSet, error) {
+	inZones := types.NewNodeSet()
+	if filter == nil || len(filter.Values) == 0 {
+		return inZones, nil
+	}
+
+	selector := klabels.Everything().Add(filter.Label, klabels.ExistsOperator, []string{})
+	matches, err := rc.podApplicator.GetMatches(selector, labels.NODE)
+	if err != nil {
+		return types.NodeSet{}, util.Errorf("could not fetch node labels for zone filter on %q: %s", filter.Label, err)
+	}
+
+	values := make(map[string]bool, len(filter.Values))
+	for _, value := range filter.Values {
+		values[value] = true
+	}
+	for _, match := range matches {
+		if values[match.Labels.Get(filter.Label)] {
+			inZones.InsertNode(types.NodeName(match.ID))
+		}
+	}
+	return inZones, nil
+}
@@ -0,0 +1,107 @@
+// +build !race
+
+package rc
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/square/p2/pkg/labels"
+	"github.com/square/p2/pkg/rc/fields"
+	"github.com/square/p2/pkg/store/consul/rcstore"
+	"github.com/square/p2/pkg/store/consul/transaction"
+	"github.com/square/p2/pkg/types"
+)
+
+func TestZoneFiltersConfineScheduling(t *testing.T) {
+	rcStore, _, applicator, rc, _, _, _, closeFn := setup(t)
+	defer closeFn()
+
+	for node, zone := range map[string]string{
+		"node1": "a", "node2": "a",
+		"node3": "b", "node4": "b",
+	} {
+		for key, value := range map[string]string{"nodeQuality": "good", "zone": zone} {
+			err := applicator.SetLabel(labels.NODE, node, key, value)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	setZones := func(scheduleZones *fields.ZoneFilter, unscheduleZones *fields.ZoneFilter) {
+		ctx, cancel := transaction.New(context.Background())
+		defer cancel()
+		err := rcStore.(*rcstore.ConsulStore).SetZoneFiltersTxn(ctx, rc.rcID, scheduleZones, unscheduleZones)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = transaction.MustCommit(ctx, rc.txner)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	meetDesires := func(replicas int) error {
+		err := rcStore.SetDesiredReplicas(rc.rcID, replicas)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rcFields, err := rcStore.Get(rc.rcID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rc.meetDesires(rcFields)
+	}
+	assertNodes := func(expected ...types.NodeName) {
+		current, err := rc.CurrentPods()
+		if err != nil {
+			t.Fatal(err)
+		}
+		nodes := current.Nodes()
+		sort.Slice(nodes, func(i, j int) bool { return nodes[i] < nodes[j] })
+		if len(nodes) != len(expected) {
+			t.Fatalf("Expected pods on %v, got %v", expected, nodes)
+		}
+		for i := range expected {
+			if nodes[i] != expected[i] {
+				t.Fatalf("Expected pods on %v, got %v", expected, nodes)
+			}
+		}
+	}
+
+	// Only zone b is open for scheduling, so the pods skip over zone a
+	setZones(&fields.ZoneFilter{Label: "zone", Values: []string{"b"}}, nil)
+	err := meetDesires(2)
+	if err != nil {
+		t.Fatalf("Unexpected error meeting desires: %s", err)
+	}
+	assertNodes("node3", "node4")
+
+	err = meetDesires(3)
+	if err == nil {
+		t.Error("Expected an error when the zone filter prevents meeting desires")
+	}
+	assertNodes("node3", "node4")
+
+	// Scaling down unschedules from zone b before anywhere else
+	setZones(nil, nil)
+	err = meetDesires(4)
+	if err != nil {
+		t.Fatalf("Unexpected error meeting desires: %s", err)
+	}
+	assertNodes("node1", "node2", "node3", "node4")
+
+	setZones(nil, &fields.ZoneFilter{Label: "zone", Values: []string{"b"}})
+	err = meetDesires(3)
+	if err != nil {
+		t.Fatalf("Unexpected error meeting desires: %s", err)
+	}
+	assertNodes("node1", "node2", "node4")
+
+	err = meetDesires(1)
+	if err != nil {
+		t.Fatalf("Unexpected error meeting desires: %s", err)
+	}
+	assertNodes("node2")
+}
@@ -73,6 +73,19 @@ type Replication interface {
 
 	CompletedCount() int32
 
+	// FinishedCount returns the number of nodes the replication is done
+	// with, whether they were updated, didn't need to be, or failed
+	FinishedCount() int32
+
+	// TimedOutNodes returns the nodes that most recently failed to become
+	// healthy within the timeout for the current manifest
+	TimedOutNodes() []types.NodeName
+
+	// ForgetTimedOutNodes clears the nodes returned by TimedOutNodes, e.g.
+	// once an operator has dealt with them. Nodes that time out again are
+	// recorded again.
+	ForgetTimedOutNodes()
+
 	InProgress() bool
 
 	// SetManifest() can be used to change the manifest while a replication is in progress
@@ -80,8 +93,16 @@ type Replication interface {
 
 	// SetTimeout() is used to change the timeout used for the replication while it is in progress
 	SetTimeout(timeout time.Duration)
+
+	// SetManifestForNode() makes the replication deploy a different
+	// manifest to some nodes, see ManifestForNode
+	SetManifestForNode(ManifestForNode)
 }
 
+// ManifestForNode returns the manifest to deploy to a node given the
+// replication's manifest, e.g. to apply a daemon set's per-node overrides
+type ManifestForNode func(node types.NodeName, man manifest.Manifest) (manifest.Manifest, error)
+
 type Store interface {
 	SetPodTxn(
 		ctx context.Context,
@@ -100,6 +121,7 @@ type replication struct {
 	active         int
 	nodes          []types.NodeName
 	completedCount int32
+	finishedCount  int32
 	store          Store
 	txner          transaction.Txner
 	labeler        Labeler
@@ -135,6 +157,9 @@ type replication struct {
 	// Used to timeout daemon set replications
 	timeout time.Duration
 
+	// Used to customize the manifest for each node. May be nil
+	manifestForNode ManifestForNode
+
 	// Used to log replications that have timed out
 	timedOutReplications      []types.NodeName
 	timedOutReplicationsMutex sync.Mutex
@@ -370,17 +395,17 @@ func (r *replication) Enact() {
 				go func(ctx context.Context, cancel context.CancelFunc) {
 					defer cancel()
 					defer close(exitCh)
+					defer atomic.AddInt32(&r.finishedCount, 1)
 					err := r.updateOne(ctx, node, aggregateHealth)
 					if err == nil {
+						r.forgetTimedOut(node)
 						r.logger.Infof("The host '%v' successfully replicated the pod '%v'", node, r.GetManifest().ID())
 						return
 					}
 
 					switch err {
 					case errTimeout:
-						r.timedOutReplicationsMutex.Lock()
-						r.timedOutReplications = append(r.timedOutReplications, node)
-						r.timedOutReplicationsMutex.Unlock()
+						r.recordTimedOut(node)
 						r.logger.Errorf("The host '%v' timed out during replication for pod '%v'", node, r.GetManifest().ID())
 					case errCancelled:
 						r.logger.Errorf("The host '%v' was cancelled (probably due to an update) during replication for pod '%v'", node, r.GetManifest().ID())
@@ -426,6 +451,9 @@ func (r *replication) SetManifest(man manifest.Manifest) {
 	if oldSHA != newSHA {
 		// reset the completed count to 0 because we changed the manifest
 		atomic.StoreInt32(&r.completedCount, 0)
+
+		// timeouts of the old manifest don't say anything about the new one
+		r.ForgetTimedOutNodes()
 	}
 	r.manifest = man
 }
@@ -436,6 +464,25 @@ func (r *replication) SetTimeout(timeout time.Duration) {
 	r.mu.Unlock()
 }
 
+func (r *replication) SetManifestForNode(manifestForNode ManifestForNode) {
+	r.mu.Lock()
+	r.manifestForNode = manifestForNode
+	r.mu.Unlock()
+}
+
+// nodeManifest returns the manifest to deploy to the given node
+func (r *replication) nodeManifest(node types.NodeName) (manifest.Manifest, error) {
+	r.mu.Lock()
+	man := r.manifest
+	manifestForNode := r.manifestForNode
+	r.mu.Unlock()
+
+	if manifestForNode == nil {
+		return man, nil
+	}
+	return manifestForNode(node, man)
+}
+
 func (r *replication) GetManifest() manifest.Manifest {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -491,7 +538,7 @@ func (r *replication) handleReplicationEnd(session consul.Session, renewalErrCh
 	}
 }
 
-func (r *replication) shouldScheduleForNode(node types.NodeName, logger logging.Logger) bool {
+func (r *replication) shouldScheduleForNode(node types.NodeName, manifest manifest.Manifest, logger logging.Logger) bool {
 	nodeReality, err := r.queryReality(node)
 	switch {
 	case err == pods.NoCurrentManifest:
@@ -508,7 +555,7 @@ func (r *replication) shouldScheduleForNode(node types.NodeName, logger logging.
 			logger.WithError(err).Errorln("Unable to compute manifest SHA for this node. Attempting to schedule anyway")
 			return true
 		}
-		replicationRealitySHA, err := r.GetManifest().SHA()
+		replicationRealitySHA, err := manifest.SHA()
 		if err != nil {
 			logger.WithError(err).Errorln("Unable to compute manifest SHA for this daemon set. Attempting to schedule anyway")
 			return true
@@ -528,11 +575,15 @@ func (r *replication) updateOne(
 	node types.NodeName,
 	aggregateHealth *podHealth,
 ) error {
-	manifest := r.GetManifest()
-
 	nodeLogger := r.logger.SubLogger(logrus.Fields{"node": node})
 
-	if !r.shouldScheduleForNode(node, nodeLogger) {
+	manifest, err := r.nodeManifest(node)
+	if err != nil {
+		nodeLogger.WithError(err).Errorln("Could not compute the manifest for this node")
+		return err
+	}
+
+	if !r.shouldScheduleForNode(node, manifest, nodeLogger) {
 		return nil
 	}
 
@@ -541,7 +592,7 @@ func (r *replication) updateOne(
 
 	targetSHA, _ := manifest.SHA()
 	nodeLogger.WithField("sha", targetSHA).Infoln("Updating node")
-	err := r.store.SetPodTxn(
+	err = r.store.SetPodTxn(
 		ctx,
 		consul.INTENT_TREE,
 		node,
@@ -688,6 +739,48 @@ func (r *replication) CompletedCount() int32 {
 	return atomic.LoadInt32(&r.completedCount)
 }
 
+func (r *replication) FinishedCount() int32 {
+	return atomic.LoadInt32(&r.finishedCount)
+}
+
+func (r *replication) TimedOutNodes() []types.NodeName {
+	r.timedOutReplicationsMutex.Lock()
+	defer r.timedOutReplicationsMutex.Unlock()
+	nodes := make([]types.NodeName, len(r.timedOutReplications))
+	copy(nodes, r.timedOutReplications)
+	return nodes
+}
+
+func (r *replication) ForgetTimedOutNodes() {
+	r.timedOutReplicationsMutex.Lock()
+	defer r.timedOutReplicationsMutex.Unlock()
+	r.timedOutReplications = nil
+}
+
+func (r *replication) recordTimedOut(node types.NodeName) {
+	r.timedOutReplicationsMutex.Lock()
+	defer r.timedOutReplicationsMutex.Unlock()
+	for _, timedOut := range r.timedOutReplications {
+		if timedOut == node {
+			return
+		}
+	}
+	r.timedOutReplications = append(r.timedOutReplications, node)
+}
+
+// forgetTimedOut removes a node from the timed out nodes once a later attempt
+// at it succeeds
+func (r *replication) forgetTimedOut(node types.NodeName) {
+	r.timedOutReplicationsMutex.Lock()
+	defer r.timedOutReplicationsMutex.Unlock()
+	for i, timedOut := range r.timedOutReplications {
+		if timedOut == node {
+			r.timedOutReplications = append(r.timedOutReplications[:i], r.timedOutReplications[i+1:]...)
+			return
+		}
+	}
+}
+
 func (r *replication) InProgress() bool {
 	select {
 	case <-r.quitCh:
@@ -155,3 +155,39 @@ func proccessErrors(errCh chan error, t *testing.T) {
 		t.Errorf("Encountered error: %v", err)
 	}
 }
+
+func TestTimedOutNodes(t *testing.T) {
+	r := &replication{manifest: basicManifest()}
+
+	r.recordTimedOut("node1")
+	r.recordTimedOut("node2")
+	r.recordTimedOut("node1")
+	if timedOut := r.TimedOutNodes(); len(timedOut) != 2 {
+		t.Fatalf("Expected 2 timed out nodes, got %v", timedOut)
+	}
+
+	r.forgetTimedOut("node1")
+	timedOut := r.TimedOutNodes()
+	if len(timedOut) != 1 || timedOut[0] != "node2" {
+		t.Fatalf("Expected only node2 to have timed out, got %v", timedOut)
+	}
+
+	// the same manifest shouldn't reset the timed out nodes
+	r.SetManifest(basicManifest())
+	if timedOut := r.TimedOutNodes(); len(timedOut) != 1 {
+		t.Fatalf("Expected node2 to still be timed out, got %v", timedOut)
+	}
+
+	builder := basicManifest().GetBuilder()
+	builder.SetID("other_pod")
+	r.SetManifest(builder.GetManifest())
+	if timedOut := r.TimedOutNodes(); len(timedOut) != 0 {
+		t.Fatalf("Expected a new manifest to reset the timed out nodes, got %v", timedOut)
+	}
+
+	r.recordTimedOut("node3")
+	r.ForgetTimedOutNodes()
+	if timedOut := r.TimedOutNodes(); len(timedOut) != 0 {
+		t.Fatalf("Expected the timed out nodes to be forgotten, got %v", timedOut)
+	}
+}
@@ -0,0 +1,236 @@
+package roll
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/square/p2/pkg/roll/fields"
+	"github.com/square/p2/pkg/util"
+)
+
+// How long to wait before asking the canary judge again after it failed to
+// give a verdict
+const canaryJudgeRetryInterval = 5 * time.Second
+
+// How long to wait for the canary judge to give a verdict. A judge that takes
+// longer is treated as having no verdict, so that it can't hold up the update
+// indefinitely.
+const canaryJudgeTimeout = 30 * time.Second
+
+type CanaryVerdict string
+
+const (
+	CanaryPass CanaryVerdict = "pass"
+	CanaryFail CanaryVerdict = "fail"
+)
+
+// A CanaryJudge gives an external verdict on the canaries of an update once
+// they have stayed healthy for the update's bake time, e.g. based on error
+// rates or latencies that health checks don't cover. An error means that no
+// verdict could be reached yet, and the judge will be asked again later.
+type CanaryJudge interface {
+	Judge(ctx context.Context, update fields.Update) (CanaryVerdict, error)
+}
+
+// HTTPCanaryJudge asks an HTTP endpoint for canary verdicts. The update is
+// POSTed to the endpoint as JSON, and the endpoint responds with a JSON
+// document of the form {"verdict": "pass"} or {"verdict": "fail"}.
+type HTTPCanaryJudge struct {
+	client *http.Client
+	url    string
+}
+
+func NewHTTPCanaryJudge(client *http.Client, url string) HTTPCanaryJudge {
+	return HTTPCanaryJudge{
+		client: client,
+		url:    url,
+	}
+}
+
+type canaryJudgeRequest struct {
+	ID              string `json:"id"`
+	OldRC           string `json:"old_rc"`
+	NewRC           string `json:"new_rc"`
+	CanaryReplicas  int    `json:"canary_replicas"`
+	DesiredReplicas int    `json:"desired_replicas"`
+}
+
+type canaryJudgeResponse struct {
+	Verdict CanaryVerdict `json:"verdict"`
+}
+
+func (j HTTPCanaryJudge) Judge(ctx context.Context, update fields.Update) (CanaryVerdict, error) {
+	body, err := json.Marshal(canaryJudgeRequest{
+		ID:              update.ID().String(),
+		OldRC:           update.OldRC.String(),
+		NewRC:           update.NewRC.String(),
+		CanaryReplicas:  update.CanaryReplicas,
+		DesiredReplicas: update.DesiredReplicas,
+	})
+	if err != nil {
+		return "", util.Errorf("could not marshal canary judge request: %s", err)
+	}
+
+	req, err := http.NewRequest("POST", j.url, bytes.NewReader(body))
+	if err != nil {
+		return "", util.Errorf("could not build canary judge request: %s", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(ctx)
+
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return "", util.Errorf("could not reach canary judge at %s: %s", j.url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		return "", util.Errorf("canary judge at %s returned status %d", j.url, resp.StatusCode)
+	}
+
+	var verdict canaryJudgeResponse
+	err = json.NewDecoder(resp.Body).Decode(&verdict)
+	if err != nil {
+		return "", util.Errorf("could not decode canary judge response: %s", err)
+	}
+
+	switch verdict.Verdict {
+	case CanaryPass, CanaryFail:
+		return verdict.Verdict, nil
+	}
+	return "", util.Errorf("canary judge at %s returned unknown verdict %q", j.url, verdict.Verdict)
+}
+
+// returned by checkCanaries
+type canaryStep int
+
+const (
+	// the update has no canary stage or is already past it
+	canaryDone canaryStep = iota
+	// the canaries have not all been rolled and become healthy yet
+	canaryRolling
+	// the canaries are healthy and being held for the bake time
+	canaryBaking
+	// the canaries failed and the update should be aborted
+	canaryFailed
+)
+
+// canaryReplicas returns the number of replicas the update rolls and bakes
+// before the others, or 0 if it has no canary stage.
+func (u *update) canaryReplicas() int {
+	if u.CanaryReplicas <= 0 || u.CanaryReplicas >= u.DesiredReplicas {
+		return 0
+	}
+	return u.CanaryReplicas
+}
+
+// pastCanaries reports whether the update is done with its canary stage,
+// given the replicas desired of the new RC. The new RC only gets more than the
+// canaries once they have passed.
+func (u *update) pastCanaries(newDesired int) bool {
+	canaries := u.canaryReplicas()
+	return canaries == 0 || u.canaryPassed || newDesired > canaries
+}
+
+// checkCanaries moves the update through its canary stage given the current
+// state of the new RC.
+//
+// The time the canaries started baking is only kept in memory, so if a
+// different farm picks up the update part way through the bake, the bake
+// starts over. This errs on the side of baking for longer.
+func (u *update) checkCanaries(ctx context.Context, newNodes rcNodeCounts) canaryStep {
+	canaries := u.canaryReplicas()
+	if u.pastCanaries(newNodes.Desired) {
+		// an earlier run of this update may have gotten past the canaries
+		u.canaryPassed = canaries > 0
+		return canaryDone
+	}
+
+	if u.bakeStart.IsZero() {
+		if newNodes.Desired < canaries || newNodes.Healthy < canaries {
+			return canaryRolling
+		}
+		u.bakeStart = time.Now()
+		u.logger.WithFields(logrus.Fields{
+			"canaries": canaries,
+			"bake":     u.CanaryBake,
+		}).Infoln("Canaries are healthy, baking them")
+		return canaryBaking
+	}
+
+	if newNodes.Unhealthy > 0 {
+		u.logger.WithField("new", newNodes.ToString()).Errorln("Canaries became unhealthy while baking")
+		return canaryFailed
+	}
+	if newNodes.Healthy < canaries {
+		// health is unknown for some of the canaries, which is not
+		// enough to fail them but not enough to pass them either
+		return canaryBaking
+	}
+	if time.Since(u.bakeStart) < u.CanaryBake {
+		return canaryBaking
+	}
+
+	if u.canaryJudge != nil {
+		judgeCtx, cancel := context.WithTimeout(ctx, canaryJudgeTimeout)
+		verdict, err := u.canaryJudge.Judge(judgeCtx, u.Update)
+		cancel()
+		if err != nil {
+			u.logger.WithError(err).Errorln("Could not get a verdict on the canaries, will ask again")
+			return canaryBaking
+		}
+		if verdict == CanaryFail {
+			u.logger.NoFields().Errorln("Canary judge failed the canaries")
+			return canaryFailed
+		}
+	}
+
+	u.logger.WithField("bake", u.CanaryBake).Infoln("Canaries passed, continuing update")
+	u.canaryPassed = true
+	return canaryDone
+}
+
+// baking returns whether the canaries are being held for the bake time
+func (u *update) baking() bool {
+	return !u.bakeStart.IsZero() && !u.canaryPassed
+}
+
+// nextBakeCheck returns when the canaries should next be checked, or the zero
+// time if they aren't baking.
+func (u *update) nextBakeCheck() time.Time {
+	if !u.baking() {
+		return time.Time{}
+	}
+	next := u.bakeStart.Add(u.CanaryBake)
+	if time.Now().After(next) {
+		// the bake is over but the judge has no verdict yet
+		next = time.Now().Add(canaryJudgeRetryInterval)
+	}
+	return next
+}
+
+// limitToCanaries caps a step of the roll algorithm so that it does not move
+// more than the canaries to the new RC before they have passed.
+func (u *update) limitToCanaries(newNodes rcNodeCounts, nextRemove, nextAdd int) (int, int) {
+	canaries := u.canaryReplicas()
+	if canaries == 0 || u.canaryPassed {
+		return nextRemove, nextAdd
+	}
+
+	limit := clampToZero(canaries - newNodes.Desired)
+	if nextAdd > limit {
+		nextAdd = limit
+	}
+	if nextRemove > nextAdd {
+		nextRemove = nextAdd
+	}
+	return nextRemove, nextAdd
+}
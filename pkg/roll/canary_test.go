@@ -0,0 +1,306 @@
+// +build !race
+
+package roll
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/square/p2/pkg/audit"
+	"github.com/square/p2/pkg/health"
+	"github.com/square/p2/pkg/logging"
+	rc_fields "github.com/square/p2/pkg/rc/fields"
+	"github.com/square/p2/pkg/roll/fields"
+	"github.com/square/p2/pkg/store/consul/rollstore"
+	"github.com/square/p2/pkg/types"
+
+	. "github.com/anthonybishopric/gotcha"
+)
+
+type fakeCanaryJudge struct {
+	verdict CanaryVerdict
+	err     error
+}
+
+func (j fakeCanaryJudge) Judge(ctx context.Context, update fields.Update) (CanaryVerdict, error) {
+	return j.verdict, j.err
+}
+
+func canaryUpdate(judge CanaryJudge) *update {
+	return &update{
+		Update: fields.Update{
+			DesiredReplicas: 10,
+			CanaryReplicas:  2,
+			CanaryBake:      time.Hour,
+		},
+		logger:      logging.TestLogger(),
+		canaryJudge: judge,
+	}
+}
+
+func TestCanaryReplicasIgnoredIfNotFewerThanDesired(t *testing.T) {
+	u := update{Update: fields.Update{DesiredReplicas: 2, CanaryReplicas: 2}}
+	Assert(t).AreEqual(u.canaryReplicas(), 0, "canaries should be ignored if they would be the whole update")
+}
+
+func TestLimitToCanaries(t *testing.T) {
+	u := canaryUpdate(nil)
+
+	remove, add := u.limitToCanaries(rcNodeCounts{Desired: 0}, 3, 3)
+	Assert(t).AreEqual(add, 2, "should add no more than the canaries")
+	Assert(t).AreEqual(remove, 2, "should remove no more than it adds")
+
+	remove, add = u.limitToCanaries(rcNodeCounts{Desired: 1}, 0, 3)
+	Assert(t).AreEqual(add, 1, "should only add the remaining canaries")
+	Assert(t).AreEqual(remove, 0, "should not remove more when increasing capacity")
+
+	remove, add = u.limitToCanaries(rcNodeCounts{Desired: 2}, 3, 3)
+	Assert(t).AreEqual(add, 0, "should add nothing while canaries haven't passed")
+	Assert(t).AreEqual(remove, 0, "should remove nothing while canaries haven't passed")
+
+	u.canaryPassed = true
+	remove, add = u.limitToCanaries(rcNodeCounts{Desired: 2}, 3, 3)
+	Assert(t).AreEqual(add, 3, "should not limit once canaries passed")
+	Assert(t).AreEqual(remove, 3, "should not limit once canaries passed")
+}
+
+func TestCheckCanariesBakesBeforePassing(t *testing.T) {
+	u := canaryUpdate(fakeCanaryJudge{verdict: CanaryPass})
+
+	Assert(t).AreEqual(u.checkCanaries(context.Background(), rcNodeCounts{Desired: 1, Healthy: 1}), canaryRolling, "should roll until all canaries are scheduled")
+	Assert(t).AreEqual(u.checkCanaries(context.Background(), rcNodeCounts{Desired: 2, Healthy: 1, Unhealthy: 1}), canaryRolling, "should not fail canaries before they have all been healthy")
+	Assert(t).AreEqual(u.checkCanaries(context.Background(), rcNodeCounts{Desired: 2, Healthy: 2}), canaryBaking, "should bake once canaries are healthy")
+	Assert(t).AreEqual(u.checkCanaries(context.Background(), rcNodeCounts{Desired: 2, Healthy: 2}), canaryBaking, "should bake for the bake time")
+
+	u.bakeStart = time.Now().Add(-2 * time.Hour)
+	Assert(t).AreEqual(u.checkCanaries(context.Background(), rcNodeCounts{Desired: 2, Healthy: 1, Unknown: 1}), canaryBaking, "should not pass canaries of unknown health")
+	Assert(t).AreEqual(u.checkCanaries(context.Background(), rcNodeCounts{Desired: 2, Healthy: 2}), canaryDone, "should pass canaries after the bake time")
+	Assert(t).IsTrue(u.canaryPassed, "should remember that canaries passed")
+}
+
+func TestCheckCanariesFailsUnhealthyCanaries(t *testing.T) {
+	u := canaryUpdate(nil)
+
+	Assert(t).AreEqual(u.checkCanaries(context.Background(), rcNodeCounts{Desired: 2, Healthy: 2}), canaryBaking, "should bake once canaries are healthy")
+	Assert(t).AreEqual(u.checkCanaries(context.Background(), rcNodeCounts{Desired: 2, Healthy: 1, Unhealthy: 1}), canaryFailed, "should fail canaries that become unhealthy while baking")
+}
+
+func TestCheckCanariesAsksJudge(t *testing.T) {
+	u := canaryUpdate(fakeCanaryJudge{err: context.DeadlineExceeded})
+	u.bakeStart = time.Now().Add(-2 * time.Hour)
+	Assert(t).AreEqual(u.checkCanaries(context.Background(), rcNodeCounts{Desired: 2, Healthy: 2}), canaryBaking, "should keep baking until the judge gives a verdict")
+
+	u.canaryJudge = fakeCanaryJudge{verdict: CanaryFail}
+	Assert(t).AreEqual(u.checkCanaries(context.Background(), rcNodeCounts{Desired: 2, Healthy: 2}), canaryFailed, "should fail canaries the judge fails")
+}
+
+// deadlineCanaryJudge records the deadline of the context it is asked for a
+// verdict with
+type deadlineCanaryJudge struct {
+	deadline *time.Time
+}
+
+func (j deadlineCanaryJudge) Judge(ctx context.Context, update fields.Update) (CanaryVerdict, error) {
+	*j.deadline, _ = ctx.Deadline()
+	return CanaryPass, nil
+}
+
+func TestCheckCanariesLimitsJudgeTime(t *testing.T) {
+	var deadline time.Time
+	u := canaryUpdate(deadlineCanaryJudge{deadline: &deadline})
+	u.bakeStart = time.Now().Add(-2 * time.Hour)
+
+	Assert(t).AreEqual(u.checkCanaries(context.Background(), rcNodeCounts{Desired: 2, Healthy: 2}), canaryDone, "should pass canaries the judge passes")
+	Assert(t).IsFalse(deadline.IsZero(), "should give the judge a deadline")
+	Assert(t).IsTrue(deadline.Before(time.Now().Add(canaryJudgeTimeout)), "should give the judge no more than the judge timeout")
+}
+
+func TestCheckCanariesAlreadyPassed(t *testing.T) {
+	u := canaryUpdate(nil)
+	Assert(t).AreEqual(u.checkCanaries(context.Background(), rcNodeCounts{Desired: 3}), canaryDone, "should skip canaries an earlier run got past")
+}
+
+func TestHTTPCanaryJudge(t *testing.T) {
+	var received canaryJudgeRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := json.NewDecoder(r.Body).Decode(&received)
+		if err != nil {
+			t.Error(err)
+		}
+		_, _ = w.Write([]byte(`{"verdict": "fail"}`))
+	}))
+	defer server.Close()
+
+	judge := NewHTTPCanaryJudge(http.DefaultClient, server.URL)
+	verdict, err := judge.Judge(context.Background(), fields.Update{
+		OldRC:           "old_rc",
+		NewRC:           "new_rc",
+		DesiredReplicas: 10,
+		CanaryReplicas:  2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	Assert(t).AreEqual(verdict, CanaryFail, "should return the verdict of the endpoint")
+	Assert(t).AreEqual(received.NewRC, "new_rc", "should send the update to the endpoint")
+	Assert(t).AreEqual(received.CanaryReplicas, 2, "should send the update to the endpoint")
+}
+
+func TestRollLoopContinuesAfterCanaryBake(t *testing.T) {
+	nodes := map[types.NodeName]bool{
+		"node1": true,
+		"node2": true,
+		"node3": true,
+	}
+	upd, _, manifest, rcWatcher, f := updateWithHealth(t, 3, 0, nodes, nil, nil, nil, rc_fields.StaticStrategy)
+	defer f()
+	upd.DesiredReplicas = 3
+	upd.MinimumReplicas = 2
+	upd.CanaryReplicas = 1
+	upd.CanaryBake = 10 * time.Millisecond
+
+	healths := make(chan map[types.NodeName]health.Result)
+	checks := map[types.NodeName]health.Result{
+		"node1": {Status: health.Passing},
+		"node2": {Status: health.Passing},
+		"node3": {Status: health.Passing},
+	}
+	upd.hcheck = cannedWatchServiceChecker{
+		watchServiceCh: healths,
+		serviceResult:  checks,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var wg sync.WaitGroup
+	oldRCCh := watchRCOrFail(ctx, t, rcWatcher, upd.OldRC, "old RC", &wg)
+	newRCCh := watchRCOrFail(ctx, t, rcWatcher, upd.NewRC, "new RC", &wg)
+
+	rollLoopResult := make(chan bool)
+	go func() {
+		rollLoopResult <- upd.rollLoop(ctx, manifest.ID(), healths, nil) == rollSucceeded
+		close(rollLoopResult)
+	}()
+
+	assertRCUpdates(t, oldRCCh, 3, "old RC")
+	assertRCUpdates(t, newRCCh, 0, "new RC")
+	healths <- checks
+
+	assertRCUpdates(t, oldRCCh, 2, "old RC")
+	assertRCUpdates(t, newRCCh, 1, "new RC")
+
+	err := transferNode("node1", manifest, upd)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the canary is healthy so it starts baking. no further health
+	// results are sent, the end of the bake must be noticed by the loop
+	healths <- checks
+
+	assertRCUpdates(t, oldRCCh, 1, "old RC")
+	assertRCUpdates(t, newRCCh, 2, "new RC")
+
+	cancel()
+	wg.Wait()
+	assertRollLoopResult(t, rollLoopResult, false)
+}
+
+func TestRunAbortsFailedCanaries(t *testing.T) {
+	nodes := map[types.NodeName]bool{
+		"node1": true,
+		"node2": true,
+		"node3": true,
+	}
+	upd, _, manifest, rcWatcher, f := updateWithHealth(t, 3, 0, nodes, nil, nil, nil, rc_fields.StaticStrategy)
+	defer f()
+	upd.DesiredReplicas = 3
+	upd.MinimumReplicas = 2
+	upd.CanaryReplicas = 1
+	upd.CanaryBake = time.Hour
+	upd.alerter = &fakeAlerter{}
+
+	healths := make(chan map[types.NodeName]health.Result)
+	checks := map[types.NodeName]health.Result{
+		"node1": {Status: health.Passing},
+		"node2": {Status: health.Passing},
+		"node3": {Status: health.Passing},
+	}
+	upd.hcheck = cannedWatchServiceChecker{
+		watchServiceCh: healths,
+		serviceResult:  checks,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var wg sync.WaitGroup
+	oldRCCh := watchRCOrFail(ctx, t, rcWatcher, upd.OldRC, "old RC", &wg)
+	newRCCh := watchRCOrFail(ctx, t, rcWatcher, upd.NewRC, "new RC", &wg)
+
+	rollLoopResult := make(chan bool)
+	go func() {
+		rollLoopResult <- upd.Run(ctx)
+		close(rollLoopResult)
+	}()
+
+	assertRCUpdates(t, oldRCCh, 3, "old RC")
+	assertRCUpdates(t, newRCCh, 0, "new RC")
+	healths <- checks
+
+	assertRCUpdates(t, oldRCCh, 2, "old RC")
+	assertRCUpdates(t, newRCCh, 1, "new RC")
+
+	err := transferNode("node1", manifest, upd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	healths <- checks
+
+	failing := map[types.NodeName]health.Result{
+		"node1": {Status: health.Critical},
+		"node2": {Status: health.Passing},
+		"node3": {Status: health.Passing},
+	}
+	healths <- failing
+
+	assertRCUpdates(t, oldRCCh, 3, "old RC")
+	assertRCUpdates(t, newRCCh, 0, "new RC")
+	assertRollLoopResult(t, rollLoopResult, true)
+
+	cancel()
+	wg.Wait()
+
+	ru, err := upd.rollStore.(rollstore.ConsulStore).Get(upd.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ru.NewRC != "" {
+		t.Fatal("expected RU to be deleted when aborted")
+	}
+
+	als, err := upd.auditLogStore.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(als) != 1 {
+		t.Fatalf("expected 1 audit log record but there were %d", len(als))
+	}
+	for _, al := range als {
+		if al.EventType != audit.RUAbortEvent {
+			t.Fatalf("expected audit log record to have type %q but was %q", audit.RUAbortEvent, al.EventType)
+		}
+		var details audit.RUAbortDetails
+		err = json.Unmarshal([]byte(*al.EventDetails), &details)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !details.RolledBack {
+			t.Error("expected audit log details to say the RU was rolled back")
+		}
+	}
+}
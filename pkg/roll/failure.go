@@ -0,0 +1,118 @@
+package roll
+
+import (
+	"time"
+
+	"github.com/square/p2/pkg/types"
+	"github.com/square/p2/pkg/util"
+)
+
+// The shortest time between two checks of an update's timers, so that
+// repeatedly failing to read health doesn't turn into a busy loop
+const minCheckInterval = 1 * time.Second
+
+// checkFailurePolicy returns an error describing why the update failed if it
+// exceeded one of the limits of its failure policy, or nil otherwise.
+// unhealthyNodes are the nodes where the new RC's replicas are unhealthy.
+//
+// Like the canary bake, the time of the last progress and how long nodes have
+// been unhealthy are only tracked in memory, so they start over when a
+// different farm picks up the update.
+func (u *update) checkFailurePolicy(newNodes rcNodeCounts, unhealthyNodes []types.NodeName) error {
+	policy := u.FailurePolicy
+	if policy == nil {
+		return nil
+	}
+
+	now := time.Now()
+	if u.lastProgress.IsZero() || u.baking() || u.betweenZones() ||
+		newNodes.Desired > u.progressCounts.Desired ||
+		newNodes.Healthy > u.progressCounts.Healthy {
+		u.lastProgress = now
+		u.progressCounts = newNodes
+	}
+
+	stillUnhealthy := make(map[types.NodeName]time.Time, len(unhealthyNodes))
+	for _, node := range unhealthyNodes {
+		since, ok := u.unhealthySince[node]
+		if !ok {
+			since = now
+		}
+		stillUnhealthy[node] = since
+	}
+	u.unhealthySince = stillUnhealthy
+
+	if policy.MaxUnhealthy != nil && newNodes.Unhealthy > *policy.MaxUnhealthy {
+		return util.Errorf("%d new replicas are unhealthy, more than the maximum of %d", newNodes.Unhealthy, *policy.MaxUnhealthy)
+	}
+
+	if policy.MaxUnhealthyDuration > 0 {
+		for node, since := range u.unhealthySince {
+			if now.Sub(since) > policy.MaxUnhealthyDuration {
+				return util.Errorf("new replica on %s has been unhealthy for more than %s", node, policy.MaxUnhealthyDuration)
+			}
+		}
+	}
+
+	if policy.ProgressDeadline > 0 && now.Sub(u.lastProgress) > policy.ProgressDeadline {
+		return util.Errorf("update made no progress for more than %s", policy.ProgressDeadline)
+	}
+
+	return nil
+}
+
+// resetFailurePolicy forgets the progress the update made and how long its
+// new replicas have been unhealthy, so that time the update spent paused
+// doesn't count against its failure policy.
+func (u *update) resetFailurePolicy() {
+	u.lastProgress = time.Time{}
+	u.unhealthySince = nil
+}
+
+// nextFailureCheck returns when the update's failure policy could next trip
+// without any change in health, or the zero time if it can't.
+func (u *update) nextFailureCheck() time.Time {
+	policy := u.FailurePolicy
+	if policy == nil {
+		return time.Time{}
+	}
+
+	var next time.Time
+	if policy.ProgressDeadline > 0 && !u.lastProgress.IsZero() {
+		next = u.lastProgress.Add(policy.ProgressDeadline)
+	}
+	if policy.MaxUnhealthyDuration > 0 {
+		for _, since := range u.unhealthySince {
+			next = earliest(next, since.Add(policy.MaxUnhealthyDuration))
+		}
+	}
+	return next
+}
+
+// checkTimer returns a channel that fires when the update should next be
+// checked even if health hasn't changed, or nil if it needn't be, along with
+// a function to stop the timer. Health checks are only delivered when they
+// change, so without it a canary bake that ends, a deadline that passes, a
+// resumed update or the end of a pause between zones on a quiet service
+// would not be noticed.
+func (u *update) checkTimer() (<-chan time.Time, func()) {
+	next := earliest(u.nextBakeCheck(), earliest(u.nextFailureCheck(), earliest(u.nextPauseCheck(), u.nextZoneCheck())))
+	if next.IsZero() {
+		return nil, func() {}
+	}
+
+	wait := time.Until(next)
+	if wait < minCheckInterval {
+		wait = minCheckInterval
+	}
+	timer := time.NewTimer(wait)
+	return timer.C, func() { timer.Stop() }
+}
+
+// earliest returns the earlier of two times, ignoring zero times
+func earliest(a, b time.Time) time.Time {
+	if a.IsZero() || (!b.IsZero() && b.Before(a)) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,301 @@
+// +build !race
+
+package roll
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/square/p2/pkg/audit"
+	"github.com/square/p2/pkg/health"
+	rc_fields "github.com/square/p2/pkg/rc/fields"
+	"github.com/square/p2/pkg/roll/fields"
+	"github.com/square/p2/pkg/store/consul/rollstore"
+	"github.com/square/p2/pkg/types"
+
+	. "github.com/anthonybishopric/gotcha"
+)
+
+func failurePolicyUpdate(policy fields.FailurePolicy) *update {
+	return &update{
+		Update: fields.Update{
+			DesiredReplicas: 10,
+			FailurePolicy:   &policy,
+		},
+	}
+}
+
+func TestCheckFailurePolicyWithoutPolicy(t *testing.T) {
+	u := &update{}
+	err := u.checkFailurePolicy(rcNodeCounts{Desired: 3, Unhealthy: 3}, []types.NodeName{"node1", "node2", "node3"})
+	Assert(t).IsNil(err, "should never fail an update without a failure policy")
+}
+
+func TestCheckFailurePolicyMaxUnhealthy(t *testing.T) {
+	maxUnhealthy := 1
+	u := failurePolicyUpdate(fields.FailurePolicy{MaxUnhealthy: &maxUnhealthy})
+
+	err := u.checkFailurePolicy(rcNodeCounts{Desired: 2, Healthy: 1, Unhealthy: 1}, []types.NodeName{"node1"})
+	Assert(t).IsNil(err, "should allow up to the maximum unhealthy replicas")
+
+	err = u.checkFailurePolicy(rcNodeCounts{Desired: 2, Unhealthy: 2}, []types.NodeName{"node1", "node2"})
+	Assert(t).IsNotNil(err, "should fail with more than the maximum unhealthy replicas")
+}
+
+func TestCheckFailurePolicyMaxUnhealthyDuration(t *testing.T) {
+	u := failurePolicyUpdate(fields.FailurePolicy{MaxUnhealthyDuration: time.Minute})
+
+	err := u.checkFailurePolicy(rcNodeCounts{Desired: 2, Healthy: 1, Unhealthy: 1}, []types.NodeName{"node1"})
+	Assert(t).IsNil(err, "should allow a node to be unhealthy for a while")
+
+	u.unhealthySince["node1"] = time.Now().Add(-2 * time.Minute)
+	err = u.checkFailurePolicy(rcNodeCounts{Desired: 2, Healthy: 1, Unhealthy: 1}, []types.NodeName{"node2"})
+	Assert(t).IsNil(err, "should forget nodes that became healthy")
+
+	u.unhealthySince["node2"] = time.Now().Add(-2 * time.Minute)
+	err = u.checkFailurePolicy(rcNodeCounts{Desired: 2, Healthy: 1, Unhealthy: 1}, []types.NodeName{"node2"})
+	Assert(t).IsNotNil(err, "should fail if a node stays unhealthy for too long")
+}
+
+func TestCheckFailurePolicyProgressDeadline(t *testing.T) {
+	u := failurePolicyUpdate(fields.FailurePolicy{ProgressDeadline: time.Minute})
+
+	err := u.checkFailurePolicy(rcNodeCounts{Desired: 1}, nil)
+	Assert(t).IsNil(err, "should not fail before the deadline")
+
+	u.lastProgress = time.Now().Add(-2 * time.Minute)
+	err = u.checkFailurePolicy(rcNodeCounts{Desired: 1, Healthy: 1}, nil)
+	Assert(t).IsNil(err, "should count a replica becoming healthy as progress")
+
+	u.lastProgress = time.Now().Add(-2 * time.Minute)
+	err = u.checkFailurePolicy(rcNodeCounts{Desired: 2, Healthy: 1}, nil)
+	Assert(t).IsNil(err, "should count a replica moving to the new RC as progress")
+
+	u.lastProgress = time.Now().Add(-2 * time.Minute)
+	err = u.checkFailurePolicy(rcNodeCounts{Desired: 2, Healthy: 1}, nil)
+	Assert(t).IsNotNil(err, "should fail after the deadline without progress")
+}
+
+func TestCheckFailurePolicyProgressDeadlineWhileBaking(t *testing.T) {
+	u := failurePolicyUpdate(fields.FailurePolicy{ProgressDeadline: time.Minute})
+	u.CanaryReplicas = 1
+	u.bakeStart = time.Now().Add(-time.Hour)
+	u.lastProgress = time.Now().Add(-2 * time.Minute)
+	u.progressCounts = rcNodeCounts{Desired: 1, Healthy: 1}
+
+	err := u.checkFailurePolicy(rcNodeCounts{Desired: 1, Healthy: 1}, nil)
+	Assert(t).IsNil(err, "should not count time spent baking canaries against the deadline")
+}
+
+func TestNextFailureCheck(t *testing.T) {
+	u := failurePolicyUpdate(fields.FailurePolicy{
+		ProgressDeadline:     time.Hour,
+		MaxUnhealthyDuration: time.Minute,
+	})
+	now := time.Now()
+	u.lastProgress = now
+	Assert(t).AreEqual(u.nextFailureCheck(), now.Add(time.Hour), "should check at the progress deadline")
+
+	u.unhealthySince = map[types.NodeName]time.Time{
+		"node1": now,
+		"node2": now.Add(-30 * time.Second),
+	}
+	Assert(t).AreEqual(u.nextFailureCheck(), now.Add(30*time.Second), "should check when the first node has been unhealthy for too long")
+}
+
+func TestRunRollsBackOnFailurePolicy(t *testing.T) {
+	nodes := map[types.NodeName]bool{
+		"node1": true,
+		"node2": true,
+		"node3": true,
+	}
+	upd, _, manifest, rcWatcher, f := updateWithHealth(t, 3, 0, nodes, nil, nil, nil, rc_fields.StaticStrategy)
+	defer f()
+	maxUnhealthy := 0
+	upd.DesiredReplicas = 3
+	upd.MinimumReplicas = 2
+	upd.FailurePolicy = &fields.FailurePolicy{
+		MaxUnhealthy: &maxUnhealthy,
+		Rollback:     true,
+	}
+	alerter := &fakeAlerter{}
+	upd.alerter = alerter
+
+	healths := make(chan map[types.NodeName]health.Result)
+	checks := map[types.NodeName]health.Result{
+		"node1": {Status: health.Passing},
+		"node2": {Status: health.Passing},
+		"node3": {Status: health.Passing},
+	}
+	upd.hcheck = cannedWatchServiceChecker{
+		watchServiceCh: healths,
+		serviceResult:  checks,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var wg sync.WaitGroup
+	oldRCCh := watchRCOrFail(ctx, t, rcWatcher, upd.OldRC, "old RC", &wg)
+	newRCCh := watchRCOrFail(ctx, t, rcWatcher, upd.NewRC, "new RC", &wg)
+
+	runResult := make(chan bool)
+	go func() {
+		runResult <- upd.Run(ctx)
+		close(runResult)
+	}()
+
+	assertRCUpdates(t, oldRCCh, 3, "old RC")
+	assertRCUpdates(t, newRCCh, 0, "new RC")
+	healths <- checks
+
+	assertRCUpdates(t, oldRCCh, 2, "old RC")
+	assertRCUpdates(t, newRCCh, 1, "new RC")
+
+	err := transferNode("node1", manifest, upd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	healths <- map[types.NodeName]health.Result{
+		"node1": {Status: health.Critical},
+		"node2": {Status: health.Passing},
+		"node3": {Status: health.Passing},
+	}
+
+	assertRollLoopResult(t, runResult, true)
+	cancel()
+	wg.Wait()
+
+	if alerter.numCalls != 1 {
+		t.Errorf("expected 1 alert about the failed update but there were %d", alerter.numCalls)
+	}
+
+	rollStore := upd.rollStore.(rollstore.ConsulStore)
+	ru, err := rollStore.Get(upd.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ru.NewRC != "" {
+		t.Fatal("expected failed RU to be deleted")
+	}
+
+	reverse, err := rollStore.Get(fields.ID(upd.OldRC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reverse.OldRC != upd.NewRC || reverse.NewRC != upd.OldRC {
+		t.Fatalf("expected an RU from %s back to %s but found %+v", upd.NewRC, upd.OldRC, reverse)
+	}
+	if reverse.DesiredReplicas != 3 {
+		t.Errorf("expected the reverse RU to want 3 replicas but it wants %d", reverse.DesiredReplicas)
+	}
+
+	als, err := upd.auditLogStore.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(als) != 1 {
+		t.Fatalf("expected 1 audit log record but there were %d", len(als))
+	}
+	for _, al := range als {
+		if al.EventType != audit.RUAbortEvent {
+			t.Fatalf("expected audit log record to have type %q but was %q", audit.RUAbortEvent, al.EventType)
+		}
+		var details audit.RUAbortDetails
+		err = json.Unmarshal([]byte(*al.EventDetails), &details)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !details.RolledBack {
+			t.Error("expected audit log details to say the RU was rolled back")
+		}
+		if details.PodID != testPodID {
+			t.Errorf("expected audit log pod ID to be %q but was %q", testPodID, details.PodID)
+		}
+	}
+}
+
+func TestRunRollsBackPastCanariesOnFailurePolicy(t *testing.T) {
+	oldNodes := map[types.NodeName]bool{
+		"node1": true,
+	}
+	newNodes := map[types.NodeName]bool{
+		"node2": true,
+		"node3": true,
+	}
+	// the update is picked up after an earlier run got past its canary
+	upd, _, _, rcWatcher, f := updateWithHealth(t, 1, 2, oldNodes, newNodes, nil, nil, rc_fields.StaticStrategy)
+	defer f()
+	maxUnhealthy := 0
+	upd.DesiredReplicas = 3
+	upd.MinimumReplicas = 2
+	upd.CanaryReplicas = 1
+	upd.CanaryBake = time.Hour
+	upd.FailurePolicy = &fields.FailurePolicy{
+		MaxUnhealthy: &maxUnhealthy,
+		Rollback:     true,
+	}
+	upd.alerter = &fakeAlerter{}
+
+	healths := make(chan map[types.NodeName]health.Result)
+	checks := map[types.NodeName]health.Result{
+		"node1": {Status: health.Passing},
+		"node2": {Status: health.Passing},
+		"node3": {Status: health.Passing},
+	}
+	upd.hcheck = cannedWatchServiceChecker{
+		watchServiceCh: healths,
+		serviceResult:  checks,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var wg sync.WaitGroup
+	oldRCCh := watchRCOrFail(ctx, t, rcWatcher, upd.OldRC, "old RC", &wg)
+	newRCCh := watchRCOrFail(ctx, t, rcWatcher, upd.NewRC, "new RC", &wg)
+
+	runResult := make(chan bool)
+	go func() {
+		runResult <- upd.Run(ctx)
+		close(runResult)
+	}()
+
+	assertRCUpdates(t, oldRCCh, 1, "old RC")
+	assertRCUpdates(t, newRCCh, 2, "new RC")
+	healths <- map[types.NodeName]health.Result{
+		"node1": {Status: health.Passing},
+		"node2": {Status: health.Critical},
+		"node3": {Status: health.Passing},
+	}
+
+	assertRollLoopResult(t, runResult, true)
+	cancel()
+	wg.Wait()
+
+	// the replicas stay where they are for the reverse RU to move back
+	// gradually, rather than all being moved back like canaries
+	oldRC, err := upd.rcStore.Get(upd.OldRC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newRC, err := upd.rcStore.Get(upd.NewRC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if oldRC.ReplicasDesired != 1 || newRC.ReplicasDesired != 2 {
+		t.Errorf("expected the RCs to keep 1 and 2 replicas but they have %d and %d", oldRC.ReplicasDesired, newRC.ReplicasDesired)
+	}
+
+	reverse, err := upd.rollStore.(rollstore.ConsulStore).Get(fields.ID(upd.OldRC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reverse.OldRC != upd.NewRC || reverse.NewRC != upd.OldRC {
+		t.Fatalf("expected an RU from %s back to %s but found %+v", upd.NewRC, upd.OldRC, reverse)
+	}
+	if reverse.MinimumReplicas != 2 {
+		t.Errorf("expected the reverse RU to keep the minimum of 2 replicas but it has %d", reverse.MinimumReplicas)
+	}
+}
@@ -41,13 +41,19 @@ type UpdateFactory struct {
 	RCStore       ReplicationControllerStore
 	RCStatusStore RCStatusStore
 	RollStore     RollingUpdateStore
-	HealthChecker checker.HealthChecker
-	Labeler       labeler
-	WatchDelay    time.Duration
-	Alerter       alerting.Alerter
+	// RollStatusStore, if set, is where updates publish their progress
+	RollStatusStore RollStatusStore
+	HealthChecker   checker.HealthChecker
+	Labeler         labeler
+	WatchDelay      time.Duration
+	Alerter         alerting.Alerter
 
 	ShouldCreateAuditLogRecords bool
 	AuditLogStore               auditlogstore.ConsulStore
+
+	// CanaryJudge optionally gives a verdict on the canaries of updates
+	// once they have baked, in addition to their health checks
+	CanaryJudge CanaryJudge
 }
 
 type labeler interface {
@@ -96,6 +102,7 @@ func (f UpdateFactory) New(u roll_fields.Update, l logging.Logger, session consu
 		f.RCStore,
 		f.RCStatusStore,
 		f.RollStore,
+		f.RollStatusStore,
 		f.Txner,
 		f.HealthChecker,
 		f.Labeler,
@@ -105,6 +112,7 @@ func (f UpdateFactory) New(u roll_fields.Update, l logging.Logger, session consu
 		f.Alerter,
 		f.ShouldCreateAuditLogRecords,
 		f.AuditLogStore,
+		f.CanaryJudge,
 	)
 }
 
@@ -114,7 +122,9 @@ type RCGetter interface {
 
 type RollingUpdateStore interface {
 	Watch(quit <-chan struct{}, jitterWindow time.Duration) (<-chan []roll_fields.Update, <-chan error)
+	Get(id roll_fields.ID) (roll_fields.Update, error)
 	Delete(ctx context.Context, id roll_fields.ID) error
+	ReplaceTxn(ctx context.Context, id roll_fields.ID, replacement roll_fields.Update) error
 }
 
 // The Farm is responsible for spawning and reaping rolling updates as they are
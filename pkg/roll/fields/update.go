@@ -52,6 +52,82 @@ type Update struct {
 	// unhealthy after being healthy for a short duration. Naive implementations like
 	// p2-replicate do not handle such after-the-fact unhealthiness. Default is 0.
 	RollDelay time.Duration
+
+	// CanaryReplicas, if nonzero, makes the update roll only this many
+	// replicas at first. Once those canaries are healthy the update holds
+	// them for CanaryBake, and aborts if any of them becomes unhealthy in
+	// the meantime (or if the farm's canary judge fails them afterwards).
+	// Otherwise it carries on with the rest of the replicas as usual.
+	// Aborting moves the canaries back to the old RC and deletes the
+	// update, leaving the new RC with no replicas. Canaries are ignored
+	// if there are at least DesiredReplicas of them.
+	CanaryReplicas int
+
+	// CanaryBake is how long the canaries must stay healthy before the
+	// update moves on from them.
+	CanaryBake time.Duration
+
+	// FailurePolicy, if set, makes the update give up when its new
+	// replicas don't become healthy, instead of waiting for them forever.
+	FailurePolicy *FailurePolicy
+
+	// Paused makes the roll farm stop moving replicas to the new RC until
+	// the update is resumed. A batch that was already under way when the
+	// update was paused is allowed to finish. PauseReason and PausedBy
+	// record why and by whom the update was paused.
+	Paused      bool
+	PauseReason string
+	PausedBy    string
+
+	// ZoneSequence, if set, makes the update finish one zone of nodes
+	// before it starts on the next.
+	ZoneSequence *ZoneSequence
+}
+
+// ZoneSequence describes how a rolling update moves through the zones of its
+// nodes, as given by a node label such as an availability zone. Within a zone
+// replicas are moved as usual. Replicas on nodes outside of the listed zones
+// (or without the label) are moved once every zone is done.
+type ZoneSequence struct {
+	// The node label whose values are the zones
+	Label string
+
+	// The zones in the order they are rolled. If empty, the zones of the
+	// old and new RCs' nodes are rolled in sorted order.
+	Order []string
+
+	// Pause is how long the update waits after finishing a zone before it
+	// starts on the next.
+	Pause time.Duration
+
+	// RequireHealthy makes the update wait for all of the new RC's
+	// replicas to be healthy before it starts on the next zone.
+	RequireHealthy bool
+}
+
+// FailurePolicy describes when a rolling update is considered to have failed.
+// Each limit is checked against the new RC's replicas as the update goes, and
+// the update is aborted as soon as one of them is exceeded.
+type FailurePolicy struct {
+	// ProgressDeadline fails the update if it goes this long without
+	// moving a replica to the new RC or a new replica becoming healthy.
+	// Time spent baking canaries doesn't count. Zero means no deadline.
+	ProgressDeadline time.Duration
+
+	// MaxUnhealthy fails the update if more than this many of the new
+	// RC's replicas are unhealthy at once. Nil means no limit.
+	MaxUnhealthy *int
+
+	// MaxUnhealthyDuration fails the update if any of the new RC's
+	// replicas stays unhealthy for longer than this. Zero means no limit.
+	MaxUnhealthyDuration time.Duration
+
+	// Rollback makes a failed update replace itself with an update back
+	// to the old RC. Otherwise the failed update is deleted and both RCs
+	// keep the replicas they had when it failed. Either way, if the
+	// update fails before its canaries have passed, the canaries are
+	// moved back to the old RC.
+	Rollback bool
 }
 
 // Implementation detail: a rolling updates ID matches that of it's NewRC. We may
@@ -296,6 +296,7 @@ func TestLockRCs(t *testing.T) {
 		nil,
 		nil,
 		nil,
+		nil,
 		fixture.Client.KV(),
 		nil,
 		nil,
@@ -305,6 +306,7 @@ func TestLockRCs(t *testing.T) {
 		nil,
 		false,
 		auditlogstore.ConsulStore{},
+		nil,
 	).(*update)
 	lockCtx, lockCancel := transaction.New(context.Background())
 	defer lockCancel()
@@ -0,0 +1,50 @@
+package roll
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// How often a paused update checks whether it has been resumed. Health checks
+// are only delivered when they change, so a paused update on a quiet service
+// would otherwise not notice being resumed.
+const pausePollInterval = 10 * time.Second
+
+// refreshPause reads the update from the rollstore to find out whether it has
+// been paused or resumed since it was last checked. Errors are logged and the
+// last known state is kept.
+func (u *update) refreshPause() {
+	stored, err := u.rollStore.Get(u.ID())
+	if err != nil {
+		u.logger.WithError(err).Errorln("Could not check whether update is paused")
+		return
+	}
+	if stored.ID() != u.ID() {
+		// the update was deleted, the farm will stop it soon
+		return
+	}
+
+	if stored.Paused != u.Paused {
+		if stored.Paused {
+			u.logger.WithFields(logrus.Fields{
+				"reason":    stored.PauseReason,
+				"paused_by": stored.PausedBy,
+			}).Infoln("Update paused")
+		} else {
+			u.logger.NoFields().Infoln("Update resumed")
+		}
+	}
+	u.Paused = stored.Paused
+	u.PauseReason = stored.PauseReason
+	u.PausedBy = stored.PausedBy
+}
+
+// nextPauseCheck returns when a paused update should next check whether it was
+// resumed, or the zero time if it isn't paused.
+func (u *update) nextPauseCheck() time.Time {
+	if !u.Paused {
+		return time.Time{}
+	}
+	return time.Now().Add(pausePollInterval)
+}
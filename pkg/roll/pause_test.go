@@ -0,0 +1,95 @@
+// +build !race
+
+package roll
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/square/p2/pkg/health"
+	rc_fields "github.com/square/p2/pkg/rc/fields"
+	"github.com/square/p2/pkg/store/consul/rollstore"
+	"github.com/square/p2/pkg/store/consul/transaction"
+	"github.com/square/p2/pkg/types"
+)
+
+func TestRollLoopHoldsWhilePaused(t *testing.T) {
+	nodes := map[types.NodeName]bool{
+		"node1": true,
+		"node2": true,
+		"node3": true,
+	}
+	upd, _, manifest, rcWatcher, f := updateWithHealth(t, 3, 0, nodes, nil, nil, nil, rc_fields.StaticStrategy)
+	defer f()
+	upd.DesiredReplicas = 3
+	upd.MinimumReplicas = 2
+	rollStore := upd.rollStore.(rollstore.ConsulStore)
+
+	pauseCtx, cancelPause := transaction.New(context.Background())
+	defer cancelPause()
+	err := rollStore.PauseTxn(pauseCtx, upd.ID(), "investigating", "some_user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = transaction.MustCommit(pauseCtx, upd.txner)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	healths := make(chan map[types.NodeName]health.Result)
+	checks := map[types.NodeName]health.Result{
+		"node1": {Status: health.Passing},
+		"node2": {Status: health.Passing},
+		"node3": {Status: health.Passing},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var wg sync.WaitGroup
+	oldRCCh := watchRCOrFail(ctx, t, rcWatcher, upd.OldRC, "old RC", &wg)
+	newRCCh := watchRCOrFail(ctx, t, rcWatcher, upd.NewRC, "new RC", &wg)
+
+	rollLoopResult := make(chan bool)
+	go func() {
+		rollLoopResult <- upd.rollLoop(ctx, manifest.ID(), healths, nil) == rollSucceeded
+		close(rollLoopResult)
+	}()
+
+	assertRCUpdates(t, oldRCCh, 3, "old RC")
+	assertRCUpdates(t, newRCCh, 0, "new RC")
+
+	// the second send can't happen until the step for the first one is
+	// done, so nothing can have moved by the time it returns
+	healths <- checks
+	healths <- checks
+	newRC, err := upd.rcStore.Get(upd.NewRC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newRC.ReplicasDesired != 0 {
+		t.Fatalf("expected paused update not to move replicas but the new RC wants %d", newRC.ReplicasDesired)
+	}
+	if upd.PausedBy != "some_user" || upd.PauseReason != "investigating" {
+		t.Errorf("expected update to know who paused it and why, but had %q and %q", upd.PausedBy, upd.PauseReason)
+	}
+
+	resumeCtx, cancelResume := transaction.New(context.Background())
+	defer cancelResume()
+	err = rollStore.ResumeTxn(resumeCtx, upd.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = transaction.MustCommit(resumeCtx, upd.txner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	healths <- checks
+
+	assertRCUpdates(t, oldRCCh, 2, "old RC")
+	assertRCUpdates(t, newRCCh, 1, "new RC")
+
+	cancel()
+	wg.Wait()
+	assertRollLoopResult(t, rollLoopResult, false)
+}
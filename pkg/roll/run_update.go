@@ -20,6 +20,7 @@ import (
 	"github.com/square/p2/pkg/store/consul/consulutil"
 	"github.com/square/p2/pkg/store/consul/rcstore"
 	"github.com/square/p2/pkg/store/consul/statusstore/rcstatus"
+	"github.com/square/p2/pkg/store/consul/statusstore/rollstatus"
 	"github.com/square/p2/pkg/store/consul/transaction"
 	"github.com/square/p2/pkg/types"
 	"github.com/square/p2/pkg/util"
@@ -51,6 +52,8 @@ type ReplicationControllerStore interface {
 	TransferReplicaCounts(ctx context.Context, req rcstore.TransferReplicaCountsRequest) error
 	DisableTxn(ctx context.Context, id rcf.ID) error
 	EnableTxn(ctx context.Context, id rcf.ID) error
+	InheritHistoryTxn(ctx context.Context, fromID rcf.ID, toID rcf.ID) error
+	SetZoneFiltersTxn(ctx context.Context, id rcf.ID, scheduleZones *rcf.ZoneFilter, unscheduleZones *rcf.ZoneFilter) error
 }
 
 type Labeler interface {
@@ -79,10 +82,12 @@ type update struct {
 	rcStore       ReplicationControllerStore
 	rcStatusStore RCStatusStore
 	rollStore     RollingUpdateStore
-	rcLocker      ReplicationControllerLocker
-	hcheck        ServiceWatcher
-	labeler       Labeler
-	txner         transaction.Txner
+	// rollStatusStore, if set, is where the update publishes its progress
+	rollStatusStore RollStatusStore
+	rcLocker        ReplicationControllerLocker
+	hcheck          ServiceWatcher
+	labeler         Labeler
+	txner           transaction.Txner
 
 	logger logging.Logger
 
@@ -100,6 +105,32 @@ type update struct {
 	// to signify that the rolling update was successful
 	shouldCreateAuditLogRecords bool
 	auditLogStore               auditlogstore.ConsulStore
+
+	// canaryJudge, if set, gives a verdict on the canaries of updates
+	// that have them once they have baked
+	canaryJudge CanaryJudge
+
+	// progress through the canary stage, see checkCanaries()
+	canaryPassed bool
+	bakeStart    time.Time
+
+	// progress through the failure policy, see checkFailurePolicy()
+	lastProgress   time.Time
+	progressCounts rcNodeCounts
+	unhealthySince map[types.NodeName]time.Time
+
+	// progress through the zone sequence, see checkZones()
+	zone        string
+	zoneStarted bool
+	zoneDoneAt  time.Time
+	zoneTarget  int
+
+	// why the update was aborted, if it was
+	abortReason string
+
+	// when Run started, and the status last published, see publishStatus()
+	startTime  time.Time
+	lastStatus *rollstatus.Status
 }
 
 type RCStatusStore interface {
@@ -118,6 +149,7 @@ func NewUpdate(
 	rcStore ReplicationControllerStore,
 	rcStatusStore RCStatusStore,
 	rollStore RollingUpdateStore,
+	rollStatusStore RollStatusStore,
 	txner transaction.Txner,
 	hcheck ServiceWatcher,
 	labeler Labeler,
@@ -127,7 +159,12 @@ func NewUpdate(
 	alerter alerting.Alerter,
 	shouldCreateAuditLogRecords bool,
 	auditLogStore auditlogstore.ConsulStore,
+	canaryJudge CanaryJudge,
 ) Update {
+	if alerter == nil {
+		alerter = alerting.NewNop()
+	}
+
 	logger = logger.SubLogger(logrus.Fields{
 		"desired_replicas": f.DesiredReplicas,
 		"minimum_replicas": f.MinimumReplicas,
@@ -139,6 +176,7 @@ func NewUpdate(
 		rcStore:                     rcStore,
 		rcStatusStore:               rcStatusStore,
 		rollStore:                   rollStore,
+		rollStatusStore:             rollStatusStore,
 		txner:                       txner,
 		hcheck:                      hcheck,
 		labeler:                     labeler,
@@ -148,6 +186,7 @@ func NewUpdate(
 		consulClient:                consulClient,
 		auditLogStore:               auditLogStore,
 		shouldCreateAuditLogRecords: shouldCreateAuditLogRecords,
+		canaryJudge:                 canaryJudge,
 	}
 }
 
@@ -195,6 +234,7 @@ func RetryOrQuit(ctx context.Context, f func() error, logger logging.Logger, err
 // have a consul transaction value stored in it and cleanup operations such as
 // deleting the old RC will be added to it when applicable.
 func (u *update) Run(ctx context.Context) (ret bool) {
+	u.startTime = time.Now()
 	u.logger.Infoln("creating a session for this RU")
 	hostname, err := os.Hostname()
 	if err != nil {
@@ -323,11 +363,21 @@ func (u *update) Run(ctx context.Context) (ret bool) {
 	go u.hcheck.WatchService(watchServiceCtx, string(newFields.Manifest.ID()), hChecks, hErrs, watchDelay)
 	defer watchServiceCancel()
 
-	if updateSucceeded := u.rollLoop(checkRCLocksCtx, newFields.Manifest.ID(), hChecks, hErrs); !updateSucceeded {
+	result := u.rollLoop(checkRCLocksCtx, newFields.Manifest.ID(), hChecks, hErrs)
+	if result == rollQuit {
 		// We were asked to quit. Do so without cleaning old RC.
 		return false
 	}
 
+	// this is done before building the cleanup transaction, which may
+	// also change the RCs
+	if !u.clearZoneFilters(checkRCLocksCtx) {
+		return false
+	}
+	if result == rollAborted {
+		return u.abort(ctx, cleanupCtx)
+	}
+
 	// rollout complete, clean up old RC if told to do so
 	if !u.LeaveOld {
 		ok = u.cleanupOldRC(cleanupCtx)
@@ -337,6 +387,16 @@ func (u *update) Run(ctx context.Context) (ret bool) {
 		}
 	}
 
+	// carry the manifest history of the old RC over to the new one so
+	// that revision numbers continue across the update
+	if !RetryOrQuit(
+		cleanupCtx,
+		func() error {
+			return u.rcStore.InheritHistoryTxn(cleanupCtx, u.OldRC, u.NewRC)
+		}, u.logger, "Could not carry over RC history") {
+		return false
+	}
+
 	err = u.rollStore.Delete(cleanupCtx, u.ID())
 	if err != nil {
 		// this error is really bad because we can't recover from it
@@ -350,36 +410,157 @@ func (u *update) Run(ctx context.Context) (ret bool) {
 		return false
 	}
 
-	if u.shouldCreateAuditLogRecords {
-		succeeded := true
-		canceled := false
-		details, err := audit.NewRUCompletionEventDetails(u.ID(), succeeded, canceled, u.labeler)
-		if err != nil {
-			u.logger.WithError(err).Errorln("could not create RU completion audit log record")
-			u.mustAlert(
-				context.Background(),
-				"could not build RU deletion transaction due to audit log operation",
-				"ru-deletion-txn"+u.ID().String(),
-				err,
-			)
-			return false
+	succeeded := true
+	if !u.addCompletionRecord(cleanupCtx, succeeded) {
+		return false
+	}
+
+	// return true here, but note that it might become false because of the
+	// deferred performCleanup() function
+	return true
+}
+
+// abort stops an update that failed and alerts operators about it. It adds
+// operations to the cleanup transaction that undo the update as far as it
+// asks for:
+//   - if the update fails before its canaries have passed, the canaries are
+//     moved back to the old RC and the update is deleted. Whether they have
+//     passed is judged from the new RC, since a farm that picked up the update
+//     part way through may not have seen them pass.
+//   - otherwise, if its failure policy asks for a rollback, the update is
+//     replaced by one from the new RC back to the old RC
+//   - otherwise the update is deleted, and both RCs keep their replicas
+func (u *update) abort(ctx context.Context, cleanupCtx context.Context) bool {
+	u.logger.WithField("reason", u.abortReason).Errorln("Aborting update")
+
+	var oldRC, newRC rcf.RC
+	if !RetryOrQuit(
+		cleanupCtx,
+		func() error {
+			var err error
+			oldRC, err = u.rcStore.Get(u.OldRC)
+			if err != nil {
+				return err
+			}
+			newRC, err = u.rcStore.Get(u.NewRC)
+			return err
+		}, u.logger, "Could not read RCs to abort update") {
+		return false
+	}
+
+	var rolledBack bool
+	var err error
+	switch {
+	case !u.pastCanaries(newRC.ReplicasDesired):
+		u.logger.NoFields().Infoln("Moving canaries back to the old RC")
+		replicas := newRC.ReplicasDesired
+		err = u.rcStore.TransferReplicaCounts(cleanupCtx, rcstore.TransferReplicaCountsRequest{
+			ToRCID:               u.OldRC,
+			FromRCID:             u.NewRC,
+			ReplicasToAdd:        &replicas,
+			ReplicasToRemove:     &replicas,
+			StartingToReplicas:   &oldRC.ReplicasDesired,
+			StartingFromReplicas: &newRC.ReplicasDesired,
+		})
+		if err == nil {
+			err = u.rollStore.Delete(cleanupCtx, u.ID())
 		}
+		rolledBack = true
+	case u.FailurePolicy != nil && u.FailurePolicy.Rollback:
+		u.logger.NoFields().Infoln("Replacing update with one back to the old RC")
+		err = u.rollStore.ReplaceTxn(cleanupCtx, u.ID(), fields.Update{
+			OldRC:           u.NewRC,
+			NewRC:           u.OldRC,
+			DesiredReplicas: oldRC.ReplicasDesired + newRC.ReplicasDesired,
+			MinimumReplicas: u.MinimumReplicas,
+			RollDelay:       u.RollDelay,
+		})
+		rolledBack = true
+	default:
+		err = u.rollStore.Delete(cleanupCtx, u.ID())
+	}
+	if err != nil {
+		u.mustAlert(
+			ctx,
+			"could not build transaction to abort RU",
+			"ru-abort-txn"+u.ID().String(),
+			err,
+		)
+		return false
+	}
 
-		err = u.auditLogStore.Create(cleanupCtx, audit.RUCompletionEvent, details)
+	if u.shouldCreateAuditLogRecords {
+		details, err := audit.NewRUAbortEventDetails(u.ID(), u.abortReason, rolledBack, u.labeler)
+		if err == nil {
+			err = u.auditLogStore.Create(cleanupCtx, audit.RUAbortEvent, details)
+		}
 		if err != nil {
-			u.logger.WithError(err).Errorln("could not add audit log operation to transaction")
+			u.logger.WithError(err).Errorln("could not add RU abort audit log record to transaction")
 			u.mustAlert(
-				context.Background(),
-				"could not build RU deletion transaction due to audit log operation",
-				"ru-deletion-txn"+u.ID().String(),
+				ctx,
+				"could not build RU abort transaction due to audit log operation",
+				"ru-abort-txn"+u.ID().String(),
 				err,
 			)
 			return false
 		}
 	}
 
-	// return true here, but note that it might become false because of the
-	// deferred performCleanup() function
+	err = u.alerter.Alert(alerting.AlertInfo{
+		Description: fmt.Sprintf("rolling update was aborted: %s", u.abortReason),
+		IncidentKey: "ru-abort-" + u.ID().String(),
+		Details: struct {
+			RUID       string `json:"ru_id"`
+			OldRCID    string `json:"old_rc_id"`
+			NewRCID    string `json:"new_rc_id"`
+			Reason     string `json:"reason"`
+			RolledBack bool   `json:"rolled_back"`
+		}{
+			RUID:       u.ID().String(),
+			OldRCID:    u.OldRC.String(),
+			NewRCID:    u.NewRC.String(),
+			Reason:     u.abortReason,
+			RolledBack: rolledBack,
+		},
+	}, alerting.LowUrgency)
+	if err != nil {
+		u.logger.WithError(err).Errorln("could not send alert about aborted RU")
+	}
+
+	return true
+}
+
+// addCompletionRecord adds an audit log record of the update finishing to
+// the cleanup transaction, if the update creates audit log records.
+func (u *update) addCompletionRecord(cleanupCtx context.Context, succeeded bool) bool {
+	if !u.shouldCreateAuditLogRecords {
+		return true
+	}
+
+	canceled := false
+	details, err := audit.NewRUCompletionEventDetails(u.ID(), succeeded, canceled, u.labeler)
+	if err != nil {
+		u.logger.WithError(err).Errorln("could not create RU completion audit log record")
+		u.mustAlert(
+			context.Background(),
+			"could not build RU deletion transaction due to audit log operation",
+			"ru-deletion-txn"+u.ID().String(),
+			err,
+		)
+		return false
+	}
+
+	err = u.auditLogStore.Create(cleanupCtx, audit.RUCompletionEvent, details)
+	if err != nil {
+		u.logger.WithError(err).Errorln("could not add audit log operation to transaction")
+		u.mustAlert(
+			context.Background(),
+			"could not build RU deletion transaction due to audit log operation",
+			"ru-deletion-txn"+u.ID().String(),
+			err,
+		)
+		return false
+	}
 	return true
 }
 
@@ -480,8 +661,24 @@ func (u *update) cleanupOldRC(ctx context.Context) bool {
 	return true
 }
 
-// returns true if roll succeeded, false if asked to quit.
-func (u *update) rollLoop(ctx context.Context, podID types.PodID, hChecks <-chan map[types.NodeName]health.Result, hErrs <-chan error) bool {
+// returned by rollLoop and rollStep
+type rollResult int
+
+const (
+	// the roll should go on
+	rollContinue rollResult = iota
+	// the new RC has all of its replicas
+	rollSucceeded
+	// the roll was asked to quit
+	rollQuit
+	// the roll failed and should be undone
+	rollAborted
+)
+
+// rollLoop moves replicas from the old RC to the new one as health allows,
+// until the new RC has all of its replicas, the roll is asked to quit or it
+// has to be aborted.
+func (u *update) rollLoop(ctx context.Context, podID types.PodID, hChecks <-chan map[types.NodeName]health.Result, hErrs <-chan error) rollResult {
 	for {
 		// Select on just the quit channel before entering the select with both quit and hChecks. This protects against a situation where
 		// hChecks and quit are both ready, and hChecks might be chosen due to the random choice semantics of select {}. If multiple
@@ -489,121 +686,204 @@ func (u *update) rollLoop(ctx context.Context, podID types.PodID, hChecks <-chan
 		// handling the same RU.
 		select {
 		case <-ctx.Done():
-			return false
+			return rollQuit
 		default:
 		}
 
+		var checks map[types.NodeName]health.Result
+		checkTimer, stopCheckTimer := u.checkTimer()
 		select {
 		case <-ctx.Done():
-			return false
+			stopCheckTimer()
+			return rollQuit
 		case err := <-hErrs:
 			u.logger.WithError(err).Errorln("Could not read health checks")
-		case checks := <-hChecks:
-			newNodes, err := u.countHealthy(u.NewRC, checks)
+		case checks = <-hChecks:
+		case <-checkTimer:
+			var err error
+			checks, err = u.hcheck.Service(podID.String())
 			if err != nil {
-				u.logger.WithErrorAndFields(err, logrus.Fields{
-					"new": newNodes.ToString(),
-				}).Errorln("Could not count nodes on new RC")
-				break
+				u.logger.WithError(err).Errorln("Could not read health checks")
 			}
-			oldNodes, err := u.countHealthy(u.OldRC, checks)
-			if err != nil {
-				u.logger.WithErrorAndFields(err, logrus.Fields{
-					"old": oldNodes,
-				}).Errorln("Could not count nodes on old RC")
-				break
+		}
+		stopCheckTimer()
+		if checks == nil {
+			continue
+		}
+
+		if result := u.rollStep(ctx, podID, checks); result != rollContinue {
+			return result
+		}
+	}
+}
+
+// rollStep makes a single step of the roll given the latest health checks.
+func (u *update) rollStep(ctx context.Context, podID types.PodID, checks map[types.NodeName]health.Result) rollResult {
+	newNodes, unhealthyNodes, err := u.countHealthyNodes(u.NewRC, checks)
+	if err != nil {
+		u.logger.WithErrorAndFields(err, logrus.Fields{
+			"new": newNodes.ToString(),
+		}).Errorln("Could not count nodes on new RC")
+		return rollContinue
+	}
+	oldNodes, err := u.countHealthy(u.OldRC, checks)
+	if err != nil {
+		u.logger.WithErrorAndFields(err, logrus.Fields{
+			"old": oldNodes,
+		}).Errorln("Could not count nodes on old RC")
+		return rollContinue
+	}
+
+	nextAction := u.shouldStop(oldNodes, newNodes)
+	if nextAction == ruShouldTerminate {
+		u.logger.WithFields(logrus.Fields{
+			"old": oldNodes.ToString(),
+			"new": newNodes.ToString(),
+		}).Debugln("Upgrade complete")
+		return rollSucceeded
+	}
+
+	// a paused update holds off on everything, including tripping its
+	// failure policy, so that an operator can look into it undisturbed
+	u.refreshPause()
+	if u.Paused {
+		u.logger.WithFields(logrus.Fields{
+			"old": oldNodes.ToString(),
+			"new": newNodes.ToString(),
+		}).Debugln("Update is paused")
+		u.resetFailurePolicy()
+		u.publishStatus(rollstatus.StepPaused, fmt.Sprintf("paused by %s: %s", u.PausedBy, u.PauseReason), oldNodes, newNodes)
+		return rollContinue
+	}
+
+	if err := u.checkFailurePolicy(newNodes, unhealthyNodes); err != nil {
+		u.logger.WithErrorAndFields(err, logrus.Fields{
+			"old": oldNodes.ToString(),
+			"new": newNodes.ToString(),
+		}).Errorln("Update failed")
+		u.abortReason = err.Error()
+		return rollAborted
+	}
+
+	if nextAction == ruShouldBlock {
+		u.logger.WithFields(logrus.Fields{
+			"old": oldNodes.ToString(),
+			"new": newNodes.ToString(),
+		}).Debugln("Upgrade almost complete, blocking for more healthy new nodes")
+		u.publishStatus(rollstatus.StepBlocked, "waiting for the new RC to schedule all of its replicas", oldNodes, newNodes)
+		return rollContinue
+	}
+
+	switch u.checkCanaries(ctx, newNodes) {
+	case canaryFailed:
+		u.abortReason = "canaries failed"
+		return rollAborted
+	case canaryBaking:
+		u.logger.WithField("new", newNodes.ToString()).Debugln("Baking canaries")
+		u.publishStatus(rollstatus.StepBakingCanaries, "", oldNodes, newNodes)
+		return rollContinue
+	}
+
+	if u.ZoneSequence != nil {
+		target, reason, err := u.checkZones(ctx, oldNodes, newNodes)
+		if err != nil {
+			u.logger.WithError(err).Errorln("Could not check zones")
+			return rollContinue
+		}
+		if reason != "" {
+			u.logger.WithFields(logrus.Fields{
+				"old": oldNodes.ToString(),
+				"new": newNodes.ToString(),
+			}).Debugln("Waiting between zones")
+			u.publishStatus(rollstatus.StepBlocked, reason, oldNodes, newNodes)
+			return rollContinue
+		}
+		u.zoneTarget = target
+	}
+
+	nextRemove, nextAdd := rollAlgorithm(u.rollAlgorithmParams(oldNodes, newNodes))
+	nextRemove, nextAdd = u.limitToCanaries(newNodes, nextRemove, nextAdd)
+	if nextRemove > 0 || nextAdd > 0 {
+		// apply the delay only if we've already added to the new RC, since there's
+		// no value in sitting around doing nothing before anything has happened.
+		if newNodes.Desired > 0 && u.RollDelay > time.Duration(0) {
+			u.logger.WithField("delay", u.RollDelay).Infof("Waiting %v before continuing deploy", u.RollDelay)
+			u.publishStatus(rollstatus.StepBlocked, fmt.Sprintf("waiting for the roll delay of %s", u.RollDelay), oldNodes, newNodes)
+
+			select {
+			case <-time.After(u.RollDelay):
+			case <-ctx.Done():
+				return rollQuit
 			}
 
-			if nextAction := u.shouldStop(oldNodes, newNodes); nextAction == ruShouldTerminate {
-				u.logger.WithFields(logrus.Fields{
-					"old": oldNodes.ToString(),
-					"new": newNodes.ToString(),
-				}).Debugln("Upgrade complete")
-				return true
-			} else if nextAction == ruShouldBlock {
-				u.logger.WithFields(logrus.Fields{
-					"old": oldNodes.ToString(),
-					"new": newNodes.ToString(),
-				}).Debugln("Upgrade almost complete, blocking for more healthy new nodes")
-				break
+			// determine the new value of `next`, which may have changed
+			// following the delay.
+			nextRemove, nextAdd, err = u.shouldRollAfterDelay(podID)
+
+			if err != nil {
+				u.logger.NoFields().Errorln(err)
+				return rollContinue
 			}
 
-			nextRemove, nextAdd := rollAlgorithm(u.rollAlgorithmParams(oldNodes, newNodes))
-			if nextRemove > 0 || nextAdd > 0 {
-				// apply the delay only if we've already added to the new RC, since there's
-				// no value in sitting around doing nothing before anything has happened.
-				if newNodes.Desired > 0 && u.RollDelay > time.Duration(0) {
-					u.logger.WithField("delay", u.RollDelay).Infof("Waiting %v before continuing deploy", u.RollDelay)
-
-					select {
-					case <-time.After(u.RollDelay):
-					case <-ctx.Done():
-						return false
-					}
-
-					// determine the new value of `next`, which may have changed
-					// following the delay.
-					nextRemove, nextAdd, err = u.shouldRollAfterDelay(podID)
-
-					if err != nil {
-						u.logger.NoFields().Errorln(err)
-						break
-					}
-				}
+			// the update may have been paused during the delay
+			u.refreshPause()
+			if u.Paused {
+				return rollContinue
+			}
+		}
 
-				u.logger.WithFields(logrus.Fields{
-					"old":        oldNodes.ToString(),
-					"new":        newNodes.ToString(),
-					"nextRemove": nextRemove,
-					"nextAdd":    nextAdd,
-				}).Infof("Adding %d new nodes and removing %d old nodes", nextAdd, nextRemove)
-				transferReq := rcstore.TransferReplicaCountsRequest{
-					ToRCID:               u.NewRC,
-					FromRCID:             u.OldRC,
-					ReplicasToAdd:        &nextAdd,
-					ReplicasToRemove:     &nextRemove,
-					StartingToReplicas:   &newNodes.Desired,
-					StartingFromReplicas: &oldNodes.Desired,
-				}
+		u.logger.WithFields(logrus.Fields{
+			"old":        oldNodes.ToString(),
+			"new":        newNodes.ToString(),
+			"nextRemove": nextRemove,
+			"nextAdd":    nextAdd,
+		}).Infof("Adding %d new nodes and removing %d old nodes", nextAdd, nextRemove)
+		u.publishStatus(rollstatus.StepRolling, "", oldNodes, newNodes)
+		transferReq := rcstore.TransferReplicaCountsRequest{
+			ToRCID:               u.NewRC,
+			FromRCID:             u.OldRC,
+			ReplicasToAdd:        &nextAdd,
+			ReplicasToRemove:     &nextRemove,
+			StartingToReplicas:   &newNodes.Desired,
+			StartingFromReplicas: &oldNodes.Desired,
+		}
 
-				// branch off of the passed ctx which implicitly ensures that RC locks are held
-				transferCtx, cancel := transaction.New(ctx)
-				err = u.rcStore.TransferReplicaCounts(transferCtx, transferReq)
-				if err != nil {
-					// this error is really bad because it means
-					// the transaction has exceeded 64
-					// operations. only a code change can fix
-					// this
-					cancel()
-					u.logger.WithError(err).Errorln("could not update RC replica counts")
-
-					// the panic will be caught by our recover()
-					panic(fmt.Sprintf("could not update RC replica counts: %s", err))
-				}
+		// branch off of the passed ctx which implicitly ensures that RC locks are held
+		transferCtx, cancel := transaction.New(ctx)
+		defer cancel()
+		err = u.rcStore.TransferReplicaCounts(transferCtx, transferReq)
+		if err != nil {
+			// this error is really bad because it means
+			// the transaction has exceeded 64
+			// operations. only a code change can fix
+			// this
+			u.logger.WithError(err).Errorln("could not update RC replica counts")
+
+			// the panic will be caught by our recover()
+			panic(fmt.Sprintf("could not update RC replica counts: %s", err))
+		}
 
-				err := transaction.MustCommit(transferCtx, u.txner)
-				if err != nil {
-					// This can happen for a few reasons:
-					// 1) a CAS violation in the operations added
-					// to the context by TransferReplicaCounts().
-					// This can be fixed by starting this for
-					// loop over
-					// 2) a CAS violation due to not holding the RC locks anymore. That should only occur if our session died
-					// which should cause our context to be canceled which means we'll exit soon
-					// 3) a temporary consul unavailability issue. breaking and starting the loop again should be a natural
-					// retry
-					u.logger.WithError(err).Errorln("could not update RC replica counts")
-					break
-				}
-				cancel()
-			} else {
-				u.logger.WithFields(logrus.Fields{
-					"old": oldNodes.ToString(),
-					"new": newNodes.ToString(),
-				}).Debugln("Blocking for more healthy nodes")
-			}
+		err := transaction.MustCommit(transferCtx, u.txner)
+		if err != nil {
+			// This can happen for a few reasons:
+			// 1) a CAS violation in the operations added
+			// to the context by TransferReplicaCounts().
+			// This can be fixed by starting this for
+			// loop over
+			// 2) a CAS violation due to not holding the RC locks anymore. That should only occur if our session died
+			// which should cause our context to be canceled which means we'll exit soon
+			// 3) a temporary consul unavailability issue. breaking and starting the loop again should be a natural
+			// retry
+			u.logger.WithError(err).Errorln("could not update RC replica counts")
 		}
+	} else {
+		u.logger.WithFields(logrus.Fields{
+			"old": oldNodes.ToString(),
+			"new": newNodes.ToString(),
+		}).Debugln("Blocking for more healthy nodes")
+		u.publishStatus(rollstatus.StepBlocked, u.blockingReason(newNodes), oldNodes, newNodes)
 	}
+	return rollContinue
 }
 
 func (u *update) shouldStop(oldNodes, newNodes rcNodeCounts) ruStep {
@@ -767,20 +1047,28 @@ func (r rcNodeCounts) ToString() string {
 }
 
 func (u *update) countHealthy(id rcf.ID, checks map[types.NodeName]health.Result) (rcNodeCounts, error) {
+	ret, _, err := u.countHealthyNodes(id, checks)
+	return ret, err
+}
+
+// countHealthyNodes is like countHealthy but also returns the nodes where the
+// RC's pods are unhealthy.
+func (u *update) countHealthyNodes(id rcf.ID, checks map[types.NodeName]health.Result) (rcNodeCounts, []types.NodeName, error) {
 	ret := rcNodeCounts{}
+	var unhealthy []types.NodeName
 	rcFields, err := u.rcStore.Get(id)
 	if rcstore.IsNotExist(err) {
 		err := util.Errorf("RC %s did not exist", id)
-		return ret, err
+		return ret, nil, err
 	} else if err != nil {
-		return ret, err
+		return ret, nil, err
 	}
 
 	ret.Desired = rcFields.ReplicasDesired
 
 	currentPods, err := rc.CurrentPods(id, u.labeler)
 	if err != nil {
-		return ret, err
+		return ret, nil, err
 	}
 	ret.Current = len(currentPods)
 
@@ -796,7 +1084,7 @@ func (u *update) countHealthy(id rcf.ID, checks map[types.NodeName]health.Result
 		// TODO: is reality checking an rc-layer concern?
 		realManifest, _, err := u.consuls.Pod(consul.REALITY_TREE, node, rcFields.Manifest.ID())
 		if err != nil && err != pods.NoCurrentManifest {
-			return ret, err
+			return ret, nil, err
 		}
 
 		// if realManifest is nil, we use an empty string for comparison purposes against rc
@@ -819,12 +1107,13 @@ func (u *update) countHealthy(id rcf.ID, checks map[types.NodeName]health.Result
 				ret.Unknown++
 			} else {
 				ret.Unhealthy++
+				unhealthy = append(unhealthy, node)
 			}
 		} else {
 			ret.Unknown++
 		}
 	}
-	return ret, err
+	return ret, unhealthy, err
 }
 
 func (u *update) currentNodeIDs() ([]types.NodeName, error) {
@@ -865,6 +1154,7 @@ func (u *update) shouldRollAfterDelay(podID types.PodID) (int, int, error) {
 	}
 
 	afterDelayRemove, afterDelayAdd := rollAlgorithm(u.rollAlgorithmParams(afterDelayOld, afterDelayNew))
+	afterDelayRemove, afterDelayAdd = u.limitToCanaries(afterDelayNew, afterDelayRemove, afterDelayAdd)
 
 	if afterDelayRemove <= 0 && afterDelayAdd <= 0 {
 		return 0, 0, util.Errorf("No nodes can be safely updated after %v roll delay, will wait again", u.RollDelay)
@@ -890,6 +1180,10 @@ func (u *update) rollAlgorithmParams(oldHealth, newHealth rcNodeCounts) (oldHeal
 	oldDesired = oldHealth.Desired
 	newDesired = newHealth.Desired
 	targetDesired = u.DesiredReplicas
+	if u.ZoneSequence != nil {
+		// a zone sequenced update rolls one zone at a time
+		targetDesired = u.zoneTarget
+	}
 	minHealthy = u.MinimumReplicas
 	return
 }
@@ -0,0 +1,68 @@
+package roll
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/square/p2/pkg/roll/fields"
+	"github.com/square/p2/pkg/store/consul/statusstore/rollstatus"
+)
+
+type RollStatusStore interface {
+	Set(id fields.ID, status rollstatus.Status) error
+}
+
+func (r rcNodeCounts) toStatusCounts() rollstatus.Counts {
+	return rollstatus.Counts{
+		Desired:   r.Desired,
+		Current:   r.Current,
+		Real:      r.Real,
+		Healthy:   r.Healthy,
+		Unhealthy: r.Unhealthy,
+		Unknown:   r.Unknown,
+	}
+}
+
+// publishStatus writes the update's status to the status store so that its
+// progress can be followed without reading the farm's logs. Nothing is written
+// if the status hasn't changed since it was last published. Failing to write
+// it is logged but otherwise doesn't affect the update.
+func (u *update) publishStatus(step rollstatus.Step, blockingReason string, oldNodes, newNodes rcNodeCounts) {
+	if u.rollStatusStore == nil {
+		return
+	}
+
+	status := rollstatus.Status{
+		OldRC:            oldNodes.toStatusCounts(),
+		NewRC:            newNodes.toStatusCounts(),
+		Step:             step,
+		BlockingReason:   blockingReason,
+		StartTime:        u.startTime,
+		LastProgressTime: u.startTime,
+	}
+	if u.lastStatus != nil {
+		status.LastProgressTime = u.lastStatus.LastProgressTime
+		if status.NewRC.Desired > u.lastStatus.NewRC.Desired || status.NewRC.Healthy > u.lastStatus.NewRC.Healthy {
+			status.LastProgressTime = time.Now()
+		}
+		if status == *u.lastStatus {
+			return
+		}
+	}
+
+	err := u.rollStatusStore.Set(u.ID(), status)
+	if err != nil {
+		u.logger.WithError(err).Errorln("Could not publish update status")
+		return
+	}
+	u.lastStatus = &status
+}
+
+// blockingReason describes why a step of the update couldn't move any
+// replicas.
+func (u *update) blockingReason(newNodes rcNodeCounts) string {
+	if canaries := u.canaryReplicas(); canaries > 0 && !u.canaryPassed && newNodes.Desired >= canaries {
+		return "waiting for the canaries to become healthy"
+	}
+	return fmt.Sprintf("waiting for more healthy replicas to keep the minimum of %d", u.MinimumReplicas)
+}
@@ -0,0 +1,115 @@
+// +build !race
+
+package roll
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/square/p2/pkg/health"
+	"github.com/square/p2/pkg/logging"
+	rc_fields "github.com/square/p2/pkg/rc/fields"
+	"github.com/square/p2/pkg/roll/fields"
+	"github.com/square/p2/pkg/store/consul/statusstore"
+	"github.com/square/p2/pkg/store/consul/statusstore/rollstatus"
+	"github.com/square/p2/pkg/types"
+
+	. "github.com/anthonybishopric/gotcha"
+)
+
+type fakeRollStatusStore struct {
+	writes []rollstatus.Status
+}
+
+func (f *fakeRollStatusStore) Set(id fields.ID, status rollstatus.Status) error {
+	f.writes = append(f.writes, status)
+	return nil
+}
+
+func TestPublishStatusTracksProgress(t *testing.T) {
+	store := &fakeRollStatusStore{}
+	u := &update{
+		Update:          fields.Update{NewRC: "new_rc", DesiredReplicas: 3},
+		rollStatusStore: store,
+		logger:          logging.TestLogger(),
+		startTime:       time.Now().Add(-time.Hour),
+	}
+
+	u.publishStatus(rollstatus.StepRolling, "", rcNodeCounts{Desired: 3, Healthy: 3}, rcNodeCounts{})
+	Assert(t).AreEqual(len(store.writes), 1, "should publish the first status")
+	Assert(t).IsTrue(store.writes[0].LastProgressTime.Equal(u.startTime), "should count the start of the update as progress")
+
+	u.publishStatus(rollstatus.StepRolling, "", rcNodeCounts{Desired: 3, Healthy: 3}, rcNodeCounts{})
+	Assert(t).AreEqual(len(store.writes), 1, "should not publish a status that didn't change")
+
+	u.publishStatus(rollstatus.StepBlocked, "waiting", rcNodeCounts{Desired: 2, Healthy: 3}, rcNodeCounts{Desired: 1})
+	Assert(t).AreEqual(len(store.writes), 2, "should publish a changed status")
+	Assert(t).IsTrue(store.writes[1].LastProgressTime.After(u.startTime), "should count a replica moving to the new RC as progress")
+	Assert(t).AreEqual(store.writes[1].BlockingReason, "waiting", "should publish the blocking reason")
+	Assert(t).AreEqual(store.writes[1].NewRC.Desired, 1, "should publish the new RC's counts")
+
+	progress := store.writes[1].LastProgressTime
+	u.publishStatus(rollstatus.StepBlocked, "waiting", rcNodeCounts{Desired: 2, Healthy: 2}, rcNodeCounts{Desired: 1, Unhealthy: 1})
+	Assert(t).AreEqual(len(store.writes), 3, "should publish a changed status")
+	Assert(t).IsTrue(store.writes[2].LastProgressTime.Equal(progress), "should not count an unhealthy replica as progress")
+}
+
+func TestRollLoopPublishesStatus(t *testing.T) {
+	nodes := map[types.NodeName]bool{
+		"node1": true,
+		"node2": true,
+		"node3": true,
+	}
+	upd, _, manifest, rcWatcher, f := updateWithHealth(t, 3, 0, nodes, nil, nil, nil, rc_fields.StaticStrategy)
+	defer f()
+	upd.DesiredReplicas = 3
+	upd.MinimumReplicas = 3
+	upd.startTime = time.Now()
+	statusStore := rollstatus.NewConsul(statusstore.NewConsul(upd.consulClient), "test")
+	upd.rollStatusStore = statusStore
+
+	healths := make(chan map[types.NodeName]health.Result)
+	checks := map[types.NodeName]health.Result{
+		"node1": {Status: health.Passing},
+		"node2": {Status: health.Passing},
+		"node3": {Status: health.Passing},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var wg sync.WaitGroup
+	oldRCCh := watchRCOrFail(ctx, t, rcWatcher, upd.OldRC, "old RC", &wg)
+	newRCCh := watchRCOrFail(ctx, t, rcWatcher, upd.NewRC, "new RC", &wg)
+
+	rollLoopResult := make(chan bool)
+	go func() {
+		rollLoopResult <- upd.rollLoop(ctx, manifest.ID(), healths, nil) == rollSucceeded
+		close(rollLoopResult)
+	}()
+
+	assertRCUpdates(t, oldRCCh, 3, "old RC")
+	assertRCUpdates(t, newRCCh, 0, "new RC")
+
+	// the minimum is all of the replicas so the update can't move any.
+	// the second send can't happen until the step for the first one is
+	// done
+	healths <- checks
+	healths <- checks
+
+	status, _, err := statusStore.Get(upd.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	Assert(t).AreEqual(status.Step, rollstatus.StepBlocked, "should publish that the update is blocked")
+	Assert(t).AreEqual(status.BlockingReason, "waiting for more healthy replicas to keep the minimum of 3", "should publish why the update is blocked")
+	Assert(t).AreEqual(status.OldRC.Desired, 3, "should publish the old RC's counts")
+	Assert(t).AreEqual(status.OldRC.Healthy, 3, "should publish the old RC's counts")
+	Assert(t).AreEqual(status.NewRC.Desired, 0, "should publish the new RC's counts")
+	Assert(t).IsTrue(status.StartTime.Equal(upd.startTime), "should publish when the update started")
+
+	cancel()
+	wg.Wait()
+	assertRollLoopResult(t, rollLoopResult, false)
+}
@@ -1229,7 +1229,7 @@ func TestRollLoopMigrateFromZero(t *testing.T) {
 	rollLoopResult := make(chan bool)
 
 	go func() {
-		rollLoopResult <- upd.rollLoop(ctx, manifest.ID(), healths, nil)
+		rollLoopResult <- upd.rollLoop(ctx, manifest.ID(), healths, nil) == rollSucceeded
 		close(rollLoopResult)
 	}()
 
@@ -1295,7 +1295,7 @@ func TestRollLoopStallsIfUnhealthy(t *testing.T) {
 	rollLoopResult := make(chan bool)
 
 	go func() {
-		rollLoopResult <- upd.rollLoop(ctx, manifest.ID(), healths, nil)
+		rollLoopResult <- upd.rollLoop(ctx, manifest.ID(), healths, nil) == rollSucceeded
 		close(rollLoopResult)
 	}()
 
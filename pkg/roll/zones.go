@@ -0,0 +1,228 @@
+package roll
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	klabels "k8s.io/kubernetes/pkg/labels"
+
+	"github.com/square/p2/pkg/labels"
+	"github.com/square/p2/pkg/rc"
+	rcf "github.com/square/p2/pkg/rc/fields"
+	"github.com/square/p2/pkg/store/consul/transaction"
+	"github.com/square/p2/pkg/types"
+	"github.com/square/p2/pkg/util"
+
+	"github.com/sirupsen/logrus"
+)
+
+// checkZones moves a zone sequenced update along its zones. It works out
+// which zone is being rolled, which is the first one in the sequence where the
+// old RC still has pods, and points the RCs' zone filters at it so that the
+// new RC only schedules pods in the zones rolled so far and the old RC
+// unschedules pods from the current zone first. Once every zone is done, the
+// filters are removed and the remaining replicas are rolled as usual.
+//
+// It returns the number of replicas the new RC should have when the current
+// zone is done, which takes the place of DesiredReplicas in the roll
+// algorithm, or a reason the update has to wait instead.
+//
+// Like the canary bake, the time a zone was done is only tracked in memory,
+// so the pause between zones starts over when a different farm picks up the
+// update.
+func (u *update) checkZones(ctx context.Context, oldNodes, newNodes rcNodeCounts) (int, string, error) {
+	seq := u.ZoneSequence
+
+	nodeZones, err := u.nodeZones()
+	if err != nil {
+		return 0, "", err
+	}
+	oldPods, err := rc.CurrentPods(u.OldRC, u.labeler)
+	if err != nil {
+		return 0, "", err
+	}
+	newPods, err := rc.CurrentPods(u.NewRC, u.labeler)
+	if err != nil {
+		return 0, "", err
+	}
+
+	order := seq.Order
+	if len(order) == 0 {
+		order = derivedZoneOrder(nodeZones, append(oldPods.Nodes(), newPods.Nodes()...))
+	}
+
+	oldPerZone := make(map[string]int)
+	for _, node := range oldPods.Nodes() {
+		if zone, ok := nodeZones[node]; ok {
+			oldPerZone[zone]++
+		}
+	}
+
+	// the zone being rolled, or -1 once every zone is done
+	current := -1
+	for i, zone := range order {
+		if oldPerZone[zone] > 0 {
+			current = i
+			break
+		}
+	}
+	zoneName := "the nodes outside of the update's zones"
+	if current >= 0 {
+		zoneName = fmt.Sprintf("zone %s", order[current])
+	}
+
+	if !u.zoneStarted || zoneName != u.zone {
+		if u.zoneStarted {
+			if u.zoneDoneAt.IsZero() {
+				u.logger.WithField("zone", u.zone).Infoln("Zone is done")
+				u.zoneDoneAt = time.Now()
+			}
+			if u.betweenZones() {
+				return 0, fmt.Sprintf("waiting %s before moving on to %s", seq.Pause, zoneName), nil
+			}
+			if seq.RequireHealthy && (newNodes.Healthy < newNodes.Desired || newNodes.Current < newNodes.Desired) {
+				return 0, fmt.Sprintf("waiting for the new replicas to be healthy before moving on to %s", zoneName), nil
+			}
+		}
+		u.logger.WithField("zone", zoneName).Infoln("Rolling the next zone")
+		u.zone = zoneName
+		u.zoneStarted = true
+		u.zoneDoneAt = time.Time{}
+	}
+
+	var scheduleZones, unscheduleZones *rcf.ZoneFilter
+	if current >= 0 {
+		scheduleZones = &rcf.ZoneFilter{Label: seq.Label, Values: order[:current+1]}
+		unscheduleZones = &rcf.ZoneFilter{Label: seq.Label, Values: order[current : current+1]}
+	}
+	err = u.setZoneFilters(ctx, scheduleZones, unscheduleZones)
+	if err != nil {
+		return 0, "", err
+	}
+
+	if current < 0 {
+		return u.DesiredReplicas, "", nil
+	}
+
+	// the old RC may not have gotten around to unscheduling the pods it
+	// no longer wants, which it removes from this zone first
+	remaining := oldPerZone[order[current]] - (oldNodes.Current - oldNodes.Desired)
+	if remaining <= 0 {
+		return 0, fmt.Sprintf("waiting for the old RC to leave %s", zoneName), nil
+	}
+	target := newNodes.Desired + remaining
+	if target > u.DesiredReplicas {
+		target = u.DesiredReplicas
+	}
+	return target, "", nil
+}
+
+// nodeZones returns the zone of every node that has the zone sequence's label.
+func (u *update) nodeZones() (map[types.NodeName]string, error) {
+	label := u.ZoneSequence.Label
+	selector := klabels.Everything().Add(label, klabels.ExistsOperator, []string{})
+	matches, err := u.labeler.GetMatches(selector, labels.NODE)
+	if err != nil {
+		return nil, util.Errorf("could not fetch node labels for zone label %q: %s", label, err)
+	}
+
+	nodeZones := make(map[types.NodeName]string, len(matches))
+	for _, match := range matches {
+		nodeZones[types.NodeName(match.ID)] = match.Labels.Get(label)
+	}
+	return nodeZones, nil
+}
+
+// derivedZoneOrder returns the zones of the given nodes in sorted order.
+func derivedZoneOrder(nodeZones map[types.NodeName]string, nodes []types.NodeName) []string {
+	seen := make(map[string]bool)
+	var order []string
+	for _, node := range nodes {
+		zone, ok := nodeZones[node]
+		if ok && !seen[zone] {
+			seen[zone] = true
+			order = append(order, zone)
+		}
+	}
+	sort.Strings(order)
+	return order
+}
+
+// setZoneFilters sets the zone filters of the new RC's scheduling and the old
+// RC's unscheduling, if they aren't set that way already.
+func (u *update) setZoneFilters(ctx context.Context, scheduleZones, unscheduleZones *rcf.ZoneFilter) error {
+	newRC, err := u.rcStore.Get(u.NewRC)
+	if err != nil {
+		return err
+	}
+	oldRC, err := u.rcStore.Get(u.OldRC)
+	if err != nil {
+		return err
+	}
+	if zoneFilterEqual(newRC.ScheduleZones, scheduleZones) && newRC.UnscheduleZones == nil &&
+		oldRC.ScheduleZones == nil && zoneFilterEqual(oldRC.UnscheduleZones, unscheduleZones) {
+		return nil
+	}
+
+	u.logger.WithFields(logrus.Fields{
+		"schedule_zones":   scheduleZones,
+		"unschedule_zones": unscheduleZones,
+	}).Infoln("Setting zone filters")
+
+	// branch off of the passed ctx which implicitly ensures that RC locks are held
+	zoneCtx, cancel := transaction.New(ctx)
+	defer cancel()
+	err = u.rcStore.SetZoneFiltersTxn(zoneCtx, u.NewRC, scheduleZones, nil)
+	if err != nil {
+		return err
+	}
+	err = u.rcStore.SetZoneFiltersTxn(zoneCtx, u.OldRC, nil, unscheduleZones)
+	if err != nil {
+		return err
+	}
+	return transaction.MustCommit(zoneCtx, u.txner)
+}
+
+// clearZoneFilters removes the zone filters of a zone sequenced update from
+// its RCs once it is over, so that they schedule anywhere again.
+func (u *update) clearZoneFilters(ctx context.Context) bool {
+	if u.ZoneSequence == nil {
+		return true
+	}
+	return RetryOrQuit(ctx, func() error {
+		return u.setZoneFilters(ctx, nil, nil)
+	}, u.logger, "Could not clear zone filters")
+}
+
+// betweenZones returns true if the update finished a zone and is waiting
+// for the pause before the next one.
+func (u *update) betweenZones() bool {
+	return u.ZoneSequence != nil && !u.zoneDoneAt.IsZero() &&
+		time.Now().Before(u.zoneDoneAt.Add(u.ZoneSequence.Pause))
+}
+
+// nextZoneCheck returns when the pause between zones ends, or the zero time if
+// the update isn't waiting for one.
+func (u *update) nextZoneCheck() time.Time {
+	if !u.betweenZones() {
+		return time.Time{}
+	}
+	return u.zoneDoneAt.Add(u.ZoneSequence.Pause)
+}
+
+func zoneFilterEqual(a, b *rcf.ZoneFilter) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a.Label != b.Label || len(a.Values) != len(b.Values) {
+		return false
+	}
+	for i := range a.Values {
+		if a.Values[i] != b.Values[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,126 @@
+// +build !race
+
+package roll
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/square/p2/pkg/labels"
+	rc_fields "github.com/square/p2/pkg/rc/fields"
+	"github.com/square/p2/pkg/roll/fields"
+	"github.com/square/p2/pkg/types"
+
+	. "github.com/anthonybishopric/gotcha"
+)
+
+func TestDerivedZoneOrder(t *testing.T) {
+	nodeZones := map[types.NodeName]string{
+		"node1": "b",
+		"node2": "a",
+		"node3": "b",
+	}
+	order := derivedZoneOrder(nodeZones, []types.NodeName{"node1", "node2", "node3", "node4"})
+	Assert(t).IsTrue(reflect.DeepEqual(order, []string{"a", "b"}), "expected the zones of the nodes in sorted order")
+}
+
+func TestCheckZonesRollsOneZoneAtATime(t *testing.T) {
+	nodes := map[types.NodeName]bool{
+		"node1": true,
+		"node2": true,
+		"node3": true,
+		"node4": true,
+	}
+	upd, _, manifest, _, f := updateWithHealth(t, 4, 0, nodes, nil, nil, nil, rc_fields.StaticStrategy)
+	defer f()
+	upd.DesiredReplicas = 4
+	upd.ZoneSequence = &fields.ZoneSequence{
+		Label:          "zone",
+		Pause:          time.Hour,
+		RequireHealthy: true,
+	}
+
+	labeler := upd.labeler.(testLabeler)
+	for node, zone := range map[string]string{"node1": "b", "node2": "b", "node3": "a", "node4": "a"} {
+		err := labeler.SetLabel(labels.NODE, node, "zone", zone)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	assertFilters := func(scheduleZones, unscheduleZones []string) {
+		newRC, err := upd.rcStore.Get(upd.NewRC)
+		if err != nil {
+			t.Fatal(err)
+		}
+		oldRC, err := upd.rcStore.Get(upd.OldRC)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var expectSchedule, expectUnschedule *rc_fields.ZoneFilter
+		if scheduleZones != nil {
+			expectSchedule = &rc_fields.ZoneFilter{Label: "zone", Values: scheduleZones}
+		}
+		if unscheduleZones != nil {
+			expectUnschedule = &rc_fields.ZoneFilter{Label: "zone", Values: unscheduleZones}
+		}
+		if !zoneFilterEqual(newRC.ScheduleZones, expectSchedule) {
+			t.Errorf("Expected the new RC to schedule in %v, got %+v", scheduleZones, newRC.ScheduleZones)
+		}
+		if !zoneFilterEqual(oldRC.UnscheduleZones, expectUnschedule) {
+			t.Errorf("Expected the old RC to unschedule from %v, got %+v", unscheduleZones, oldRC.UnscheduleZones)
+		}
+	}
+	rollZone := func(nodes ...types.NodeName) {
+		for _, node := range nodes {
+			err := transferNode(node, manifest, upd)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	ctx := context.Background()
+	target, reason, err := upd.checkZones(ctx, rcNodeCounts{Desired: 4, Current: 4}, rcNodeCounts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	Assert(t).AreEqual(reason, "", "should not wait before the first zone")
+	Assert(t).AreEqual(target, 2, "should only roll the replicas in the first zone")
+	assertFilters([]string{"a"}, []string{"a"})
+
+	rollZone("node3", "node4")
+	_, reason, err = upd.checkZones(ctx, rcNodeCounts{Desired: 2, Current: 2}, rcNodeCounts{Desired: 2, Current: 2, Healthy: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	Assert(t).AreEqual(reason, "waiting 1h0m0s before moving on to zone b", "should pause between zones")
+	Assert(t).IsFalse(upd.nextZoneCheck().IsZero(), "should check again when the pause ends")
+
+	upd.zoneDoneAt = time.Now().Add(-2 * time.Hour)
+	_, reason, err = upd.checkZones(ctx, rcNodeCounts{Desired: 2, Current: 2}, rcNodeCounts{Desired: 2, Current: 2, Healthy: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	Assert(t).AreEqual(reason, "waiting for the new replicas to be healthy before moving on to zone b", "should wait for the new replicas to be healthy")
+
+	target, reason, err = upd.checkZones(ctx, rcNodeCounts{Desired: 2, Current: 2}, rcNodeCounts{Desired: 2, Current: 2, Healthy: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	Assert(t).AreEqual(reason, "", "should move on once the pause is over and the new replicas are healthy")
+	Assert(t).AreEqual(target, 4, "should roll the replicas in the second zone")
+	assertFilters([]string{"a", "b"}, []string{"b"})
+
+	rollZone("node1", "node2")
+	upd.ZoneSequence.Pause = 0
+	target, reason, err = upd.checkZones(ctx, rcNodeCounts{}, rcNodeCounts{Desired: 4, Current: 4, Healthy: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	Assert(t).AreEqual(reason, "", "should not wait once every zone is done")
+	Assert(t).AreEqual(target, 4, "should roll the rest of the replicas")
+	assertFilters(nil, nil)
+}
@@ -0,0 +1,351 @@
+package scheduler
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	klabels "k8s.io/kubernetes/pkg/labels"
+
+	"github.com/square/p2/pkg/labels"
+	"github.com/square/p2/pkg/manifest"
+	"github.com/square/p2/pkg/store/consul"
+	"github.com/square/p2/pkg/store/consul/reservationstore"
+	"github.com/square/p2/pkg/types"
+	"github.com/square/p2/pkg/util"
+)
+
+// DefaultReservationTTL is how long a reservation holds capacity on a node
+// without the pod being scheduled there. Once the pod is scheduled, its
+// manifest accounts for the capacity instead.
+const DefaultReservationTTL = 10 * time.Minute
+
+// DefaultUsageCacheTTL is how long the resources requested by scheduled pods
+// are cached for. Every replication controller asks for eligible nodes on
+// each pass, and listing every pod's intent each time would put a lot of load
+// on consul. A stale view is safe: a reservation counts until its pod shows
+// up as scheduled, and a pod that was unscheduled only holds on to its room
+// for a little longer.
+const DefaultUsageCacheTTL = 5 * time.Second
+
+// Policy decides which of the nodes with room for a pod are allocated first.
+type Policy string
+
+const (
+	// BinpackPolicy fills the fullest nodes first, leaving whole nodes free
+	// for large pods
+	BinpackPolicy Policy = "binpack"
+
+	// SpreadPolicy fills the emptiest nodes first, spreading load evenly
+	SpreadPolicy Policy = "spread"
+)
+
+func ParsePolicy(s string) (Policy, error) {
+	switch p := Policy(s); p {
+	case BinpackPolicy, SpreadPolicy:
+		return p, nil
+	}
+	return "", util.Errorf("unknown scheduling policy %q, expected %q or %q", s, BinpackPolicy, SpreadPolicy)
+}
+
+type PodLister interface {
+	AllPods(podPrefix consul.PodPrefix) ([]consul.ManifestResult, time.Duration, error)
+}
+
+type ReservationStore interface {
+	List() (map[types.NodeName]reservationstore.NodeReservations, error)
+	Mutate(node types.NodeName, mutator func(reservationstore.NodeReservations) (reservationstore.NodeReservations, error)) error
+}
+
+// ResourceScheduler places pods on nodes that have room for them. A node's
+// capacity comes from its CPUCapacityLabel and MemoryCapacityLabel labels,
+// and a pod's request from the cgroups in its manifest. The resources used on
+// a node are those requested by the pods scheduled there, plus reservations
+// made by AllocateNodes for pods that are about to be.
+type ResourceScheduler struct {
+	applicator     NodeLabeler
+	pods           PodLister
+	reservations   ReservationStore
+	policy         Policy
+	reservationTTL time.Duration
+	usageCacheTTL  time.Duration
+
+	// scheduled caches what the pods scheduled on each node request, as of
+	// scheduledAt
+	scheduledMux sync.Mutex
+	scheduled    map[types.NodeName]map[types.PodID]Resources
+	scheduledAt  time.Time
+}
+
+func NewResourceScheduler(
+	applicator NodeLabeler,
+	pods PodLister,
+	reservations ReservationStore,
+	policy Policy,
+) *ResourceScheduler {
+	return &ResourceScheduler{
+		applicator:     applicator,
+		pods:           pods,
+		reservations:   reservations,
+		policy:         policy,
+		reservationTTL: DefaultReservationTTL,
+		usageCacheTTL:  DefaultUsageCacheTTL,
+	}
+}
+
+type nodeState struct {
+	node     types.NodeName
+	capacity Capacity
+
+	// What other pods use on the node
+	used Resources
+
+	// Whether the pod being placed is already scheduled on, or allocated,
+	// the node
+	hasPod bool
+}
+
+// usage tallies what the scheduled pods and live reservations use on each
+// node, leaving out the pod being placed.
+type usage struct {
+	scheduled map[types.NodeName]map[types.PodID]Resources
+	now       time.Time
+	ttl       time.Duration
+}
+
+func (u usage) live(node types.NodeName, reservation reservationstore.Reservation) bool {
+	if _, ok := u.scheduled[node][reservation.PodID]; ok {
+		return false
+	}
+	return u.now.Sub(reservation.ReservedAt) < u.ttl
+}
+
+func (u usage) used(node types.NodeName, podID types.PodID, reservations reservationstore.NodeReservations) (Resources, bool) {
+	var used Resources
+	hasPod := false
+	for id, request := range u.scheduled[node] {
+		if id == podID {
+			hasPod = true
+			continue
+		}
+		used = used.Add(request)
+	}
+	for _, reservation := range reservations {
+		if !u.live(node, reservation) {
+			continue
+		}
+		if reservation.PodID == podID {
+			hasPod = true
+			continue
+		}
+		used = used.Add(Resources{CPUs: reservation.CPUs, Memory: reservation.Memory})
+	}
+	return used, hasPod
+}
+
+// loadUsage tallies what the scheduled pods request, reusing the last tally
+// if it is younger than the cache TTL. Concurrent callers share a single
+// listing of the intent tree. The returned maps must not be modified.
+func (s *ResourceScheduler) loadUsage() (usage, error) {
+	s.scheduledMux.Lock()
+	defer s.scheduledMux.Unlock()
+
+	now := time.Now()
+	if s.scheduled == nil || now.Sub(s.scheduledAt) >= s.usageCacheTTL {
+		results, _, err := s.pods.AllPods(consul.INTENT_TREE)
+		if err != nil {
+			return usage{}, util.Errorf("could not list scheduled pods: %s", err)
+		}
+
+		scheduled := make(map[types.NodeName]map[types.PodID]Resources)
+		for _, result := range results {
+			node := result.PodLocation.Node
+			if scheduled[node] == nil {
+				scheduled[node] = make(map[types.PodID]Resources)
+			}
+			podID := result.Manifest.ID()
+			scheduled[node][podID] = scheduled[node][podID].Add(ManifestResources(result.Manifest))
+		}
+		s.scheduled = scheduled
+		s.scheduledAt = now
+	}
+	return usage{scheduled: s.scheduled, now: now, ttl: s.reservationTTL}, nil
+}
+
+func (s *ResourceScheduler) nodeStates(podID types.PodID, selector klabels.Selector) ([]nodeState, usage, error) {
+	matches, err := s.applicator.GetMatches(selector, labels.NODE)
+	if err != nil {
+		return nil, usage{}, err
+	}
+	u, err := s.loadUsage()
+	if err != nil {
+		return nil, usage{}, err
+	}
+	reservations, err := s.reservations.List()
+	if err != nil {
+		return nil, usage{}, util.Errorf("could not list reservations: %s", err)
+	}
+
+	states := make([]nodeState, 0, len(matches))
+	for _, match := range matches {
+		capacity, err := NodeCapacity(match)
+		if err != nil {
+			return nil, usage{}, err
+		}
+		node := types.NodeName(match.ID)
+		used, hasPod := u.used(node, podID, reservations[node])
+		states = append(states, nodeState{
+			node:     node,
+			capacity: capacity,
+			used:     used,
+			hasPod:   hasPod,
+		})
+	}
+	return states, u, nil
+}
+
+// EligibleNodes returns the nodes matching the selector that have room for
+// the pod, as well as those it is already on. A pod is never made ineligible
+// because of what other pods use, so overcommitting a node won't cause pods
+// to move away from it.
+func (s *ResourceScheduler) EligibleNodes(man manifest.Manifest, selector klabels.Selector) ([]types.NodeName, error) {
+	states, _, err := s.nodeStates(man.ID(), selector)
+	if err != nil {
+		return nil, err
+	}
+
+	request := ManifestResources(man)
+	var result []types.NodeName
+	for _, state := range states {
+		if state.hasPod || state.capacity.Fits(state.used, request) {
+			result = append(result, state.node)
+		}
+	}
+	return result, nil
+}
+
+// AllocateNodes reserves room for the pod on nodes matching the selector that
+// don't already have it, choosing them according to the scheduler's policy.
+// If fewer nodes than requested have room, no nodes are allocated and an
+// error is returned, unless force is set, in which case as many as possible
+// are allocated.
+func (s *ResourceScheduler) AllocateNodes(man manifest.Manifest, selector klabels.Selector, allocationCount int, force bool) ([]types.NodeName, error) {
+	states, u, err := s.nodeStates(man.ID(), selector)
+	if err != nil {
+		return nil, err
+	}
+
+	request := ManifestResources(man)
+	var candidates []nodeState
+	for _, state := range states {
+		if !state.hasPod && state.capacity.Fits(state.used, request) {
+			candidates = append(candidates, state)
+		}
+	}
+	s.sortCandidates(candidates, request)
+
+	reservation := reservationstore.Reservation{
+		PodID:        man.ID(),
+		CPUs:         request.CPUs,
+		Memory:       request.Memory,
+		NodeSelector: selector.String(),
+		ReservedAt:   u.now,
+	}
+	var allocated []types.NodeName
+	for _, candidate := range candidates {
+		if len(allocated) == allocationCount {
+			break
+		}
+		ok, err := s.reserve(candidate, reservation, u)
+		if err != nil {
+			_ = s.release(allocated, matchingPod(man.ID()))
+			return nil, err
+		}
+		if ok {
+			allocated = append(allocated, candidate.node)
+		}
+	}
+
+	if len(allocated) < allocationCount && !force {
+		err = s.release(allocated, matchingPod(man.ID()))
+		if err != nil {
+			return nil, util.Errorf("only %d of %d nodes had room for %s, and releasing them failed: %s", len(allocated), allocationCount, man.ID(), err)
+		}
+		return nil, util.Errorf("only %d of %d nodes had room for %s", len(allocated), allocationCount, man.ID())
+	}
+	return allocated, nil
+}
+
+// reserve records a reservation on a node, checking again that it fits
+// against the node's current reservations. Reservations that have expired or
+// been superseded by their pod being scheduled are dropped along the way.
+func (s *ResourceScheduler) reserve(state nodeState, reservation reservationstore.Reservation, u usage) (bool, error) {
+	fits := false
+	err := s.reservations.Mutate(state.node, func(reservations reservationstore.NodeReservations) (reservationstore.NodeReservations, error) {
+		for k, r := range reservations {
+			if !u.live(state.node, r) {
+				delete(reservations, k)
+			}
+		}
+		used, _ := u.used(state.node, reservation.PodID, reservations)
+		request := Resources{CPUs: reservation.CPUs, Memory: reservation.Memory}
+		fits = state.capacity.Fits(used, request)
+		if fits {
+			reservations[reservation.PodID] = reservation
+		}
+		return reservations, nil
+	})
+	if err != nil {
+		return false, util.Errorf("could not reserve room on %s: %s", state.node, err)
+	}
+	return fits, nil
+}
+
+func (s *ResourceScheduler) sortCandidates(candidates []nodeState, request Resources) {
+	sort.Slice(candidates, func(i, j int) bool {
+		a := candidates[i].capacity.freeFraction(candidates[i].used.Add(request))
+		b := candidates[j].capacity.freeFraction(candidates[j].used.Add(request))
+		if a != b {
+			if s.policy == SpreadPolicy {
+				return a > b
+			}
+			return a < b
+		}
+		return candidates[i].node < candidates[j].node
+	})
+}
+
+// DeallocateNodes releases the reservations made for the manifest's pod and
+// selector on the given nodes. Nodes without one are ignored. Reservations
+// held by other pods that share the selector are left alone.
+func (s *ResourceScheduler) DeallocateNodes(man manifest.Manifest, selector klabels.Selector, nodes []types.NodeName) error {
+	podID := man.ID()
+	nodeSelector := selector.String()
+	return s.release(nodes, func(reservation reservationstore.Reservation) bool {
+		return reservation.PodID == podID && reservation.NodeSelector == nodeSelector
+	})
+}
+
+func matchingPod(podID types.PodID) func(reservationstore.Reservation) bool {
+	return func(reservation reservationstore.Reservation) bool {
+		return reservation.PodID == podID
+	}
+}
+
+// release deletes the reservations on the given nodes that match.
+func (s *ResourceScheduler) release(nodes []types.NodeName, matches func(reservationstore.Reservation) bool) error {
+	for _, node := range nodes {
+		err := s.reservations.Mutate(node, func(reservations reservationstore.NodeReservations) (reservationstore.NodeReservations, error) {
+			for podID, reservation := range reservations {
+				if matches(reservation) {
+					delete(reservations, podID)
+				}
+			}
+			return reservations, nil
+		})
+		if err != nil {
+			return util.Errorf("could not release reservation on %s: %s", node, err)
+		}
+	}
+	return nil
+}
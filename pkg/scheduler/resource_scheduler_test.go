@@ -0,0 +1,283 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	klabels "k8s.io/kubernetes/pkg/labels"
+
+	"github.com/square/p2/pkg/cgroups"
+	"github.com/square/p2/pkg/labels"
+	"github.com/square/p2/pkg/manifest"
+	"github.com/square/p2/pkg/store/consul"
+	"github.com/square/p2/pkg/store/consul/consulutil"
+	"github.com/square/p2/pkg/store/consul/reservationstore"
+	"github.com/square/p2/pkg/types"
+	"github.com/square/p2/pkg/util/size"
+)
+
+type fakePodLister []consul.ManifestResult
+
+func (f fakePodLister) AllPods(consul.PodPrefix) ([]consul.ManifestResult, time.Duration, error) {
+	return f, 0, nil
+}
+
+func podManifest(id types.PodID, cpus int, memory size.ByteCount) manifest.Manifest {
+	builder := manifest.NewBuilder()
+	builder.SetID(id)
+	builder.SetResourceLimits(manifest.ResourceLimitsStanza{
+		Cgroup: &cgroups.Config{CPUs: cpus, Memory: memory},
+	})
+	return builder.GetManifest()
+}
+
+func scheduled(node types.NodeName, man manifest.Manifest) consul.ManifestResult {
+	return consul.ManifestResult{
+		Manifest:    man,
+		PodLocation: types.PodLocation{Node: node, PodID: man.ID()},
+	}
+}
+
+func setupNodes(t *testing.T, capacities map[string]string) labels.ApplicatorWithoutWatches {
+	applicator := labels.NewFakeApplicator()
+	for node, cpus := range capacities {
+		err := applicator.SetLabels(labels.NODE, node, map[string]string{
+			"pool":              "web",
+			CPUCapacityLabel:    cpus,
+			MemoryCapacityLabel: "16G",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return applicator
+}
+
+var webPool = klabels.Everything().Add("pool", klabels.EqualsOperator, []string{"web"})
+
+func TestAllocateNodesPolicies(t *testing.T) {
+	applicator := setupNodes(t, map[string]string{"node1": "8", "node2": "8", "node3": "8"})
+	pods := fakePodLister{
+		scheduled("node1", podManifest("other", 6, size.Gibibyte)),
+		scheduled("node2", podManifest("other2", 2, size.Gibibyte)),
+	}
+	man := podManifest("web", 2, size.Gibibyte)
+
+	for policy, expected := range map[Policy]types.NodeName{
+		BinpackPolicy: "node1",
+		SpreadPolicy:  "node3",
+	} {
+		reservations := reservationstore.NewConsul(consulutil.NewFakeClient(), 0)
+		sched := NewResourceScheduler(applicator, pods, reservations, policy)
+		nodes, err := sched.AllocateNodes(man, webPool, 1, false)
+		if err != nil {
+			t.Fatalf("%s: unexpected error allocating nodes: %s", policy, err)
+		}
+		if len(nodes) != 1 || nodes[0] != expected {
+			t.Errorf("%s: expected %s to be allocated, got %v", policy, expected, nodes)
+		}
+	}
+}
+
+func TestAllocateNodesHonorsReservations(t *testing.T) {
+	applicator := setupNodes(t, map[string]string{"node1": "4", "node2": "4"})
+	pods := fakePodLister{
+		scheduled("node1", podManifest("web", 2, size.Gibibyte)),
+	}
+	reservations := reservationstore.NewConsul(consulutil.NewFakeClient(), 0)
+	sched := NewResourceScheduler(applicator, pods, reservations, BinpackPolicy)
+
+	// node1 already runs the pod, so only node2 can be allocated
+	_, err := sched.AllocateNodes(podManifest("web", 2, size.Gibibyte), webPool, 2, false)
+	if err == nil {
+		t.Fatal("Expected an error when too few nodes have room")
+	}
+	nodes, err := sched.AllocateNodes(podManifest("web", 2, size.Gibibyte), webPool, 2, true)
+	if err != nil {
+		t.Fatalf("Unexpected error forcing allocation: %s", err)
+	}
+	if len(nodes) != 1 || nodes[0] != "node2" {
+		t.Fatalf("Expected node2 to be allocated, got %v", nodes)
+	}
+
+	// The reservation on node2 leaves no room for a pod needing 3 CPUs
+	// anywhere, even though nothing is scheduled on node2 yet
+	big := podManifest("big", 3, size.Gibibyte)
+	eligible, err := sched.EligibleNodes(big, webPool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(eligible) != 0 {
+		t.Errorf("Expected no eligible nodes, got %v", eligible)
+	}
+
+	// Releasing the reservation frees the room up again
+	err = sched.DeallocateNodes(podManifest("web", 2, size.Gibibyte), webPool, []types.NodeName{"node2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	eligible, err = sched.EligibleNodes(big, webPool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(eligible) != 1 || eligible[0] != "node2" {
+		t.Errorf("Expected node2 to be eligible, got %v", eligible)
+	}
+}
+
+func TestDeallocateNodesKeepsOtherPodsReservations(t *testing.T) {
+	applicator := setupNodes(t, map[string]string{"node1": "4"})
+	reservations := reservationstore.NewConsul(consulutil.NewFakeClient(), 0)
+	sched := NewResourceScheduler(applicator, fakePodLister{}, reservations, BinpackPolicy)
+
+	// Two pods share the selector and each reserve half of node1
+	for _, podID := range []types.PodID{"web", "api"} {
+		nodes, err := sched.AllocateNodes(podManifest(podID, 2, size.Gibibyte), webPool, 1, false)
+		if err != nil {
+			t.Fatalf("Unexpected error allocating nodes for %s: %s", podID, err)
+		}
+		if len(nodes) != 1 || nodes[0] != "node1" {
+			t.Fatalf("Expected node1 to be allocated for %s, got %v", podID, nodes)
+		}
+	}
+
+	// Releasing web's reservation must not release api's as well
+	err := sched.DeallocateNodes(podManifest("web", 2, size.Gibibyte), webPool, []types.NodeName{"node1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	eligible, err := sched.EligibleNodes(podManifest("big", 3, size.Gibibyte), webPool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(eligible) != 0 {
+		t.Errorf("Expected api's reservation to keep node1 from fitting 3 CPUs, got %v", eligible)
+	}
+	eligible, err = sched.EligibleNodes(podManifest("small", 2, size.Gibibyte), webPool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(eligible) != 1 || eligible[0] != "node1" {
+		t.Errorf("Expected node1 to fit 2 CPUs after web's release, got %v", eligible)
+	}
+}
+
+func TestEligibleNodesKeepsOvercommittedPods(t *testing.T) {
+	applicator := setupNodes(t, map[string]string{"node1": "4"})
+	pods := fakePodLister{
+		scheduled("node1", podManifest("web", 3, size.Gibibyte)),
+		scheduled("node1", podManifest("other", 3, size.Gibibyte)),
+	}
+	reservations := reservationstore.NewConsul(consulutil.NewFakeClient(), 0)
+	sched := NewResourceScheduler(applicator, pods, reservations, BinpackPolicy)
+
+	eligible, err := sched.EligibleNodes(podManifest("web", 3, size.Gibibyte), webPool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(eligible) != 1 {
+		t.Errorf("Expected the node the pod runs on to stay eligible, got %v", eligible)
+	}
+	eligible, err = sched.EligibleNodes(podManifest("new", 1, size.Gibibyte), webPool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(eligible) != 0 {
+		t.Errorf("Expected a full node to be ineligible for new pods, got %v", eligible)
+	}
+}
+
+func TestReservationsExpire(t *testing.T) {
+	applicator := setupNodes(t, map[string]string{"node1": "4"})
+	reservations := reservationstore.NewConsul(consulutil.NewFakeClient(), 0)
+	sched := NewResourceScheduler(applicator, fakePodLister{}, reservations, BinpackPolicy)
+
+	_, err := sched.AllocateNodes(podManifest("web", 4, size.Gibibyte), webPool, 1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = sched.AllocateNodes(podManifest("other", 4, size.Gibibyte), webPool, 1, false)
+	if err == nil {
+		t.Fatal("Expected the reservation to leave no room")
+	}
+
+	sched.reservationTTL = 0
+	nodes, err := sched.AllocateNodes(podManifest("other", 4, size.Gibibyte), webPool, 1, false)
+	if err != nil {
+		t.Fatalf("Expected the expired reservation to be replaced: %s", err)
+	}
+	if len(nodes) != 1 {
+		t.Errorf("Expected node1 to be allocated, got %v", nodes)
+	}
+}
+
+func TestReservationsForPodsSharingASelector(t *testing.T) {
+	applicator := setupNodes(t, map[string]string{"node1": "4"})
+	reservations := reservationstore.NewConsul(consulutil.NewFakeClient(), 0)
+	sched := NewResourceScheduler(applicator, fakePodLister{}, reservations, BinpackPolicy)
+
+	// Two replication controllers with the same selector each reserve half
+	// of the node
+	for _, podID := range []types.PodID{"web", "api"} {
+		nodes, err := sched.AllocateNodes(podManifest(podID, 2, size.Gibibyte), webPool, 1, false)
+		if err != nil {
+			t.Fatalf("Unexpected error allocating a node for %s: %s", podID, err)
+		}
+		if len(nodes) != 1 {
+			t.Fatalf("Expected node1 to be allocated for %s, got %v", podID, nodes)
+		}
+	}
+
+	all, err := reservations.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all["node1"]) != 2 {
+		t.Fatalf("Expected a reservation for each pod, got %v", all["node1"])
+	}
+	eligible, err := sched.EligibleNodes(podManifest("other", 1, size.Gibibyte), webPool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(eligible) != 0 {
+		t.Errorf("Expected both reservations to fill the node, got %v", eligible)
+	}
+}
+
+type countingPodLister struct {
+	fakePodLister
+	calls int
+}
+
+func (c *countingPodLister) AllPods(prefix consul.PodPrefix) ([]consul.ManifestResult, time.Duration, error) {
+	c.calls++
+	return c.fakePodLister.AllPods(prefix)
+}
+
+func TestScheduledPodsAreCached(t *testing.T) {
+	applicator := setupNodes(t, map[string]string{"node1": "4"})
+	pods := &countingPodLister{}
+	reservations := reservationstore.NewConsul(consulutil.NewFakeClient(), 0)
+	sched := NewResourceScheduler(applicator, pods, reservations, BinpackPolicy)
+
+	for i := 0; i < 3; i++ {
+		_, err := sched.EligibleNodes(podManifest("web", 1, size.Gibibyte), webPool)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if pods.calls != 1 {
+		t.Errorf("Expected scheduled pods to be listed once, got %d listings", pods.calls)
+	}
+
+	// Once the cache expires, newly scheduled pods are seen
+	pods.fakePodLister = fakePodLister{scheduled("node1", podManifest("other", 4, size.Gibibyte))}
+	sched.usageCacheTTL = 0
+	eligible, err := sched.EligibleNodes(podManifest("web", 1, size.Gibibyte), webPool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pods.calls != 2 || len(eligible) != 0 {
+		t.Errorf("Expected the full node to be seen after the cache expired, got %v after %d listings", eligible, pods.calls)
+	}
+}
@@ -0,0 +1,100 @@
+package scheduler
+
+import (
+	"strconv"
+
+	"github.com/square/p2/pkg/labels"
+	"github.com/square/p2/pkg/manifest"
+	"github.com/square/p2/pkg/util"
+	"github.com/square/p2/pkg/util/size"
+)
+
+const (
+	// CPUCapacityLabel is the node label holding the number of logical CPUs
+	// that pods may use on the node
+	CPUCapacityLabel = "capacity_cpus"
+
+	// MemoryCapacityLabel is the node label holding the amount of memory
+	// that pods may use on the node, e.g. "64G"
+	MemoryCapacityLabel = "capacity_memory"
+)
+
+// Resources is an amount of CPU and memory, either requested by a pod or
+// available on a node.
+type Resources struct {
+	CPUs   int
+	Memory size.ByteCount
+}
+
+func (r Resources) Add(o Resources) Resources {
+	return Resources{CPUs: r.CPUs + o.CPUs, Memory: r.Memory + o.Memory}
+}
+
+// ManifestResources returns the resources a pod asks for. The pod's cgroup
+// bounds everything in it, so it is used when present. Otherwise the request
+// is the sum of its launchables' cgroups.
+func ManifestResources(man manifest.Manifest) Resources {
+	if limits := man.GetResourceLimits(); limits.Cgroup != nil {
+		return Resources{CPUs: limits.Cgroup.CPUs, Memory: limits.Cgroup.Memory}
+	}
+
+	var r Resources
+	for _, stanza := range man.GetLaunchableStanzas() {
+		r = r.Add(Resources{CPUs: stanza.CgroupConfig.CPUs, Memory: stanza.CgroupConfig.Memory})
+	}
+	return r
+}
+
+// Capacity is what a node can offer pods. A dimension is only limited if the
+// node is labeled with it.
+type Capacity struct {
+	Resources
+	CPULimited    bool
+	MemoryLimited bool
+}
+
+// NodeCapacity reads a node's capacity from its labels.
+func NodeCapacity(node labels.Labeled) (Capacity, error) {
+	var c Capacity
+	if node.Labels.Has(CPUCapacityLabel) {
+		cpus, err := strconv.Atoi(node.Labels.Get(CPUCapacityLabel))
+		if err != nil {
+			return Capacity{}, util.Errorf("node %s has an invalid %s label: %s", node.ID, CPUCapacityLabel, err)
+		}
+		c.CPUs, c.CPULimited = cpus, true
+	}
+	if node.Labels.Has(MemoryCapacityLabel) {
+		memory, err := size.Parse(node.Labels.Get(MemoryCapacityLabel))
+		if err != nil {
+			return Capacity{}, util.Errorf("node %s has an invalid %s label: %s", node.ID, MemoryCapacityLabel, err)
+		}
+		c.Memory, c.MemoryLimited = memory, true
+	}
+	return c, nil
+}
+
+// Fits returns true if the capacity can hold the request on top of what is
+// already used.
+func (c Capacity) Fits(used Resources, request Resources) bool {
+	after := used.Add(request)
+	if c.CPULimited && after.CPUs > c.CPUs {
+		return false
+	}
+	if c.MemoryLimited && after.Memory > c.Memory {
+		return false
+	}
+	return true
+}
+
+// freeFraction is the share of the node left free after the given usage,
+// averaged across dimensions. Unlimited dimensions count as entirely free.
+func (c Capacity) freeFraction(used Resources) float64 {
+	cpu, memory := 1.0, 1.0
+	if c.CPULimited && c.CPUs > 0 {
+		cpu = float64(c.CPUs-used.CPUs) / float64(c.CPUs)
+	}
+	if c.MemoryLimited && c.Memory > 0 {
+		memory = (float64(c.Memory) - float64(used.Memory)) / float64(c.Memory)
+	}
+	return (cpu + memory) / 2
+}
@@ -248,3 +248,76 @@ func (a AuditingStore) UpdateTimeout(
 
 	return ds, nil
 }
+
+// UpdateRollingStrategy sets the rolling strategy of the daemon set. A nil
+// strategy makes the daemon set roll out manifest changes to all of its
+// nodes at once.
+func (a AuditingStore) UpdateRollingStrategy(
+	ctx context.Context,
+	id fields.ID,
+	strategy *fields.RollingStrategy,
+	user string,
+) (fields.DaemonSet, error) {
+	if strategy != nil {
+		err := strategy.Validate()
+		if err != nil {
+			return fields.DaemonSet{}, err
+		}
+	}
+
+	mutator := func(ds fields.DaemonSet) (fields.DaemonSet, error) {
+		ds.RollingStrategy = strategy
+		return ds, nil
+	}
+
+	ds, err := a.innerStore.MutateDSTxn(ctx, id, mutator)
+	if err != nil {
+		return fields.DaemonSet{}, err
+	}
+
+	details, err := audit.NewDaemonSetDetails(ds, user)
+	if err != nil {
+		return fields.DaemonSet{}, err
+	}
+	err = a.auditLogStore.Create(ctx, audit.DSModifiedEvent, details)
+	if err != nil {
+		return fields.DaemonSet{}, util.Errorf("could not create audit log record for daemon set rolling strategy update: %s", err)
+	}
+
+	return ds, nil
+}
+
+// UpdateFailureThreshold sets the failure threshold of the daemon set. An
+// empty threshold removes it.
+func (a AuditingStore) UpdateFailureThreshold(
+	ctx context.Context,
+	id fields.ID,
+	threshold fields.IntOrPercent,
+	user string,
+) (fields.DaemonSet, error) {
+	_, err := threshold.Resolve(100, false)
+	if err != nil {
+		return fields.DaemonSet{}, err
+	}
+
+	mutator := func(ds fields.DaemonSet) (fields.DaemonSet, error) {
+		ds.FailureThreshold = threshold
+		return ds, nil
+	}
+
+	ds, err := a.innerStore.MutateDSTxn(ctx, id, mutator)
+	if err != nil {
+		return fields.DaemonSet{}, err
+	}
+
+	details, err := audit.NewDaemonSetDetails(ds, user)
+	if err != nil {
+		return fields.DaemonSet{}, err
+	}
+	err = a.auditLogStore.Create(ctx, audit.DSModifiedEvent, details)
+	if err != nil {
+		return fields.DaemonSet{}, util.Errorf("could not create audit log record for daemon set failure threshold update: %s", err)
+	}
+
+	return ds, nil
+}
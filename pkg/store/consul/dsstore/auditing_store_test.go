@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/square/p2/pkg/audit"
+	"github.com/square/p2/pkg/ds/fields"
 	"github.com/square/p2/pkg/logging"
 	"github.com/square/p2/pkg/manifest"
 	"github.com/square/p2/pkg/store/consul/auditlogstore"
@@ -859,6 +860,86 @@ func TestUpdateTimeout(t *testing.T) {
 	}
 }
 
+func TestUpdateRollingStrategyAndFailureThreshold(t *testing.T) {
+	fixture := consulutil.NewFixture(t)
+	defer fixture.Stop()
+
+	logger := logging.TestLogger()
+	dsStore := NewConsul(fixture.Client, 0, &logger)
+	auditLogStore := auditlogstore.NewConsulStore(fixture.Client.KV())
+
+	auditingStore := NewAuditingStore(dsStore, auditLogStore)
+
+	ctx, cancel := transaction.New(context.Background())
+	defer cancel()
+	ds, err := dsStore.Create(ctx, testManifest(), 1, "some_name", klabels.Everything(), "some_pod", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = transaction.MustCommit(ctx, fixture.Client.KV())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel = transaction.New(context.Background())
+	defer cancel()
+	_, err = auditingStore.UpdateRollingStrategy(ctx, ds.ID, &fields.RollingStrategy{}, "some_user")
+	if err == nil {
+		t.Fatal("expected an error for a rolling strategy that allows no progress")
+	}
+	_, err = auditingStore.UpdateFailureThreshold(ctx, ds.ID, "150%", "some_user")
+	if err == nil {
+		t.Fatal("expected an error for a failure threshold over 100%")
+	}
+
+	strategy := fields.RollingStrategy{MaxUnavailable: "10%"}
+	_, err = auditingStore.UpdateRollingStrategy(ctx, ds.ID, &strategy, "some_user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = transaction.MustCommit(ctx, fixture.Client.KV())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Each update is a check-and-set of the daemon set, so they can't share
+	// a transaction
+	ctx, cancel = transaction.New(context.Background())
+	defer cancel()
+	_, err = auditingStore.UpdateFailureThreshold(ctx, ds.ID, "2", "some_user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = transaction.MustCommit(ctx, fixture.Client.KV())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds, _, err = dsStore.Get(ds.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ds.RollingStrategy == nil || *ds.RollingStrategy != strategy {
+		t.Errorf("expected rolling strategy %+v but was %+v", strategy, ds.RollingStrategy)
+	}
+	if ds.FailureThreshold != "2" {
+		t.Errorf("expected failure threshold %q but was %q", "2", ds.FailureThreshold)
+	}
+
+	alMap, err := auditLogStore.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alMap) != 2 {
+		t.Fatalf("expected 2 audit logs but there were %d", len(alMap))
+	}
+	for _, v := range alMap {
+		if v.EventType != audit.DSModifiedEvent {
+			t.Errorf("expected audit log record with type %q but was %q", audit.DSModifiedEvent, v.EventType)
+		}
+	}
+}
+
 func testManifest() manifest.Manifest {
 	builder := manifest.NewBuilder()
 	builder.SetID("some_pod")
@@ -31,6 +31,8 @@ const (
 	// Don't change this, it affects where status keys are read and written from
 	PreparerPodStatusNamespace statusstore.Namespace = "preparer"
 	RCStatusNamespace          statusstore.Namespace = "replication_controller"
+	AutoscaleStatusNamespace   statusstore.Namespace = "autoscaler"
+	RollStatusNamespace        statusstore.Namespace = "roll_farm"
 )
 
 type ManifestResult struct {
@@ -332,6 +334,56 @@ func (c consulStore) Pod(podPrefix PodPrefix, nodename types.NodeName, podId typ
 	return manifest, writeMeta.RequestTime, err
 }
 
+// Pods reads the pod manifests at many locations under a tree, batching the
+// reads into as few transactions as possible. Locations without a manifest
+// are left out of the result.
+func (c consulStore) Pods(podPrefix PodPrefix, locations types.PodLocations) (map[types.PodLocation]manifest.Manifest, error) {
+	ret := make(map[types.PodLocation]manifest.Manifest, len(locations))
+	for start := 0; start < len(locations); start += transaction.MaxOperations {
+		end := start + transaction.MaxOperations
+		if end > len(locations) {
+			end = len(locations)
+		}
+
+		keys := make(map[string]types.PodLocation, end-start)
+		ops := make(api.KVTxnOps, 0, end-start)
+		for _, location := range locations[start:end] {
+			key, err := PodPath(podPrefix, location.Node, location.PodID)
+			if err != nil {
+				return nil, err
+			}
+			keys[key] = location
+			// A "get" fails the whole transaction if the key doesn't
+			// exist, so list the key as a prefix instead
+			ops = append(ops, &api.KVTxnOp{
+				Verb: api.KVGetTree,
+				Key:  key,
+			})
+		}
+
+		ok, resp, _, err := c.client.KV().Txn(ops, nil)
+		if err != nil {
+			return nil, util.Errorf("could not read pods under %s: %s", podPrefix, err)
+		}
+		if !ok {
+			return nil, util.Errorf("could not read pods under %s: %s", podPrefix, transaction.TxnErrorsToString(resp.Errors))
+		}
+		for _, pair := range resp.Results {
+			// The prefix of one pod's key may match other pods' keys too
+			location, ok := keys[pair.Key]
+			if !ok {
+				continue
+			}
+			podManifest, err := manifest.FromBytes(pair.Value)
+			if err != nil {
+				return nil, err
+			}
+			ret[location] = podManifest
+		}
+	}
+	return ret, nil
+}
+
 // ListPods reads all the pod manifests from the key-value store for a
 // specified host under a given tree. In the event of an error, the nil slice
 // is returned.
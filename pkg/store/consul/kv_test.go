@@ -449,3 +449,47 @@ func testManifest(id types.PodID) manifest.Manifest {
 	builder.SetID(id)
 	return builder.GetManifest()
 }
+
+func TestPods(t *testing.T) {
+	f := NewConsulTestFixture(t)
+	defer f.Close()
+
+	// More locations than fit in one transaction, with a pod whose ID is a
+	// prefix of another's
+	var locations types.PodLocations
+	for i := 0; i < 100; i++ {
+		node := types.NodeName(fmt.Sprintf("node%d", i))
+		locations = append(locations, types.PodLocation{Node: node, PodID: "foo"})
+		if i%2 == 1 {
+			continue
+		}
+		builder := manifest.NewBuilder()
+		builder.SetID("foo")
+		_, err := f.Store.SetPod(REALITY_TREE, node, builder.GetManifest())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	builder := manifest.NewBuilder()
+	builder.SetID("foobar")
+	_, err := f.Store.SetPod(REALITY_TREE, "node1", builder.GetManifest())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manifests, err := f.Store.Pods(REALITY_TREE, locations)
+	if err != nil {
+		t.Fatalf("Unexpected error reading pods: %s", err)
+	}
+	if len(manifests) != 50 {
+		t.Errorf("Expected a manifest for every even node, got %d manifests", len(manifests))
+	}
+	for location, podManifest := range manifests {
+		if podManifest.ID() != location.PodID {
+			t.Errorf("Expected the manifest at %v to be for %s, got %s", location, location.PodID, podManifest.ID())
+		}
+	}
+	if _, ok := manifests[types.PodLocation{Node: "node1", PodID: "foo"}]; ok {
+		t.Error("Expected no manifest for foo on node1")
+	}
+}
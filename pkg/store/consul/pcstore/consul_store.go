@@ -342,7 +342,10 @@ func (s *ConsulStore) FindWhereLabeled(podID types.PodID,
 		Add(fields.ClusterNameLabel, klabels.EqualsOperator, []string{clusterName.String()})
 
 	podClusters, err := s.labeler.GetMatches(sel, labels.PC)
-	if err != nil {
+	if err == labels.NoLabelsFound {
+		// No pod cluster has been labeled yet
+		return nil, nil
+	} else if err != nil {
 		return nil, err
 	}
 	ret := make([]fields.PodCluster, len(podClusters))
@@ -0,0 +1,63 @@
+// +build !race
+
+package pcstore
+
+import (
+	"testing"
+
+	"github.com/square/p2/pkg/labels"
+	"github.com/square/p2/pkg/logging"
+	"github.com/square/p2/pkg/pc/fields"
+	rc_fields "github.com/square/p2/pkg/rc/fields"
+	"github.com/square/p2/pkg/store/consul/consultest"
+	"github.com/square/p2/pkg/store/consul/consulutil"
+	"github.com/square/p2/pkg/types"
+
+	klabels "k8s.io/kubernetes/pkg/labels"
+)
+
+// The consul applicator returns labels.NoLabelsFound when nothing of a type
+// has been labeled, which must not prevent the first pod cluster from being
+// created
+func TestCreateWithNoLabeledPodClusters(t *testing.T) {
+	fixture := consulutil.NewFixture(t)
+	defer fixture.Stop()
+
+	applicator := labels.NewConsulApplicator(fixture.Client, 0, 0)
+	store := NewConsul(fixture.Client, applicator, labels.DefaultAggregationRate, applicator, &logging.DefaultLogger)
+
+	podID := types.PodID("pod_id")
+	az := fields.AvailabilityZone("us-west")
+	clusterName := fields.ClusterName("cluster_name")
+
+	found, err := store.FindWhereLabeled(podID, az, clusterName)
+	if err != nil {
+		t.Fatalf("Unexpected error finding pod clusters with no labels: %s", err)
+	}
+	if len(found) != 0 {
+		t.Fatalf("Expected no pod clusters but found %d", len(found))
+	}
+
+	selector := klabels.Set{
+		fields.PodIDLabel:            podID.String(),
+		fields.AvailabilityZoneLabel: az.String(),
+		fields.ClusterNameLabel:      clusterName.String(),
+	}.AsSelector()
+	pc, err := store.Create(podID, az, clusterName, selector, fields.Annotations{}, rc_fields.StaticStrategy, 0, consultest.NewSession())
+	if err != nil {
+		t.Fatalf("Unable to create the first pod cluster: %s", err)
+	}
+
+	found, err = store.FindWhereLabeled(podID, az, clusterName)
+	if err != nil {
+		t.Fatalf("Could not find pod clusters: %s", err)
+	}
+	if len(found) != 1 || found[0].ID != pc.ID {
+		t.Fatalf("Expected to find pod cluster %s but found %v", pc.ID, found)
+	}
+
+	_, err = store.Create(podID, az, clusterName, selector, fields.Annotations{}, rc_fields.StaticStrategy, 0, consultest.NewSession())
+	if err != PodClusterAlreadyExists {
+		t.Errorf("Expected a second create to fail with %q, got %v", PodClusterAlreadyExists, err)
+	}
+}
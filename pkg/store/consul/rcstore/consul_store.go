@@ -111,7 +111,9 @@ func NewConsul(client consulutil.ConsulClient, labeler RCLabeler, retries int) *
 // Create creates a replication controller with the specified manifest and selectors.
 // The node selector is used to determine what nodes the replication controller may schedule on.
 // The pod label set is applied to every pod the replication controller schedules.
-// The additionalLabels label set is applied to the RCs own labels
+// The additionalLabels label set is applied to the RCs own labels.
+// The RC, its labels and the first revision of its history are written in a
+// single transaction.
 func (s *ConsulStore) Create(
 	manifest manifest.Manifest,
 	nodeSelector klabels.Selector,
@@ -128,12 +130,18 @@ func (s *ConsulStore) Create(
 	podLabels[types.ClusterNameLabel] = clusterName.String()
 	podLabels[types.AvailabilityZoneLabel] = availabilityZone.String()
 
-	rc, err := s.innerCreate(manifest, nodeSelector, podLabels, allocationStrategy)
+	template := fields.RC{
+		Manifest:           manifest,
+		NodeSelector:       nodeSelector,
+		PodLabels:          podLabels,
+		AllocationStrategy: allocationStrategy,
+	}
+	rc, err := s.innerCreate(template, additionalLabels)
 
 	// TODO: measure whether retries are is important in practice
 	for i := 0; i < s.retries; i++ {
 		if _, ok := err.(CASError); ok {
-			rc, err = s.innerCreate(manifest, nodeSelector, podLabels, allocationStrategy)
+			rc, err = s.innerCreate(template, additionalLabels)
 		} else {
 			break
 		}
@@ -142,13 +150,6 @@ func (s *ConsulStore) Create(
 		return fields.RC{}, err
 	}
 
-	// labels do not need to be retried, consul labeler does that itself
-	labelsToSet := s.computeLabels(rc, additionalLabels)
-	err = s.labeler.SetLabels(labels.RC, rc.ID.String(), labelsToSet)
-	if err != nil {
-		return fields.RC{}, err
-	}
-
 	return rc, nil
 }
 
@@ -163,7 +164,41 @@ func (s *ConsulStore) CreateTxn(
 	additionalLabels klabels.Set,
 	allocationStrategy fields.Strategy,
 ) (fields.RC, error) {
-	rc, err := s.innerCreateTxn(ctx, manifest, nodeSelector, podLabels, allocationStrategy)
+	return s.createTxn(ctx, fields.RC{
+		Manifest:           manifest,
+		NodeSelector:       nodeSelector,
+		PodLabels:          podLabels,
+		AllocationStrategy: allocationStrategy,
+	}, additionalLabels)
+}
+
+// CreateReplacementTxn is like CreateTxn, but the new RC also carries over
+// the spread constraints and autoscale policy of oldRC, which it is meant to
+// replace. Rolling updates use this so that settings made on an RC survive the
+// update.
+func (s *ConsulStore) CreateReplacementTxn(
+	ctx context.Context,
+	oldRC fields.RC,
+	manifest manifest.Manifest,
+	nodeSelector klabels.Selector,
+	availabilityZone pc_fields.AvailabilityZone,
+	clusterName pc_fields.ClusterName,
+	podLabels klabels.Set,
+	additionalLabels klabels.Set,
+	allocationStrategy fields.Strategy,
+) (fields.RC, error) {
+	return s.createTxn(ctx, fields.RC{
+		Manifest:           manifest,
+		NodeSelector:       nodeSelector,
+		PodLabels:          podLabels,
+		AllocationStrategy: allocationStrategy,
+		SpreadConstraints:  oldRC.SpreadConstraints,
+		Autoscale:          oldRC.Autoscale,
+	}, additionalLabels)
+}
+
+func (s *ConsulStore) createTxn(ctx context.Context, template fields.RC, additionalLabels klabels.Set) (fields.RC, error) {
+	rc, err := s.innerCreateTxn(ctx, template)
 	if err != nil {
 		return fields.RC{}, err
 	}
@@ -175,66 +210,44 @@ func (s *ConsulStore) CreateTxn(
 		return fields.RC{}, err
 	}
 
-	return rc, nil
-}
-
-// these parts of Create may require a retry
-func (s *ConsulStore) innerCreate(manifest manifest.Manifest, nodeSelector klabels.Selector, podLabels klabels.Set, allocationStrategy fields.Strategy) (fields.RC, error) {
-	id := fields.ID(uuid.Must(uuid.NewV4()).String())
-	rcp, err := s.rcPath(id)
+	err = s.recordRevisionTxn(ctx, rc.ID, rc.Manifest)
 	if err != nil {
 		return fields.RC{}, err
 	}
 
-	rc := fields.RC{
-		ID:                 id,
-		Manifest:           manifest,
-		NodeSelector:       nodeSelector,
-		PodLabels:          podLabels,
-		ReplicasDesired:    0,
-		Disabled:           false,
-		AllocationStrategy: allocationStrategy,
-	}
+	return rc, nil
+}
 
-	jsonRC, err := json.Marshal(rc)
+// these parts of Create may require a retry
+func (s *ConsulStore) innerCreate(template fields.RC, additionalLabels klabels.Set) (fields.RC, error) {
+	ctx, cancel := transaction.New(context.Background())
+	defer cancel()
+
+	rc, err := s.createTxn(ctx, template, additionalLabels)
 	if err != nil {
-		return fields.RC{}, util.Errorf("Could not marshal RC as json: %s", err)
+		return fields.RC{}, err
 	}
-	success, _, err := s.kv.CAS(&api.KVPair{
-		Key:   rcp,
-		Value: jsonRC,
-		// the chance of the UUID already existing is vanishingly small, but
-		// technically not impossible, so we should use the CAS index to guard
-		// against duplicate UUIDs
-		ModifyIndex: 0,
-	}, nil)
 
+	err = s.commit(ctx, rc.ID)
 	if err != nil {
-		return fields.RC{}, consulutil.NewKVError("cas", rcp, err)
-	}
-	if !success {
-		return fields.RC{}, CASError(rcp)
+		return fields.RC{}, err
 	}
 	return rc, nil
 }
 
-// TODO: replace innerCreate() with this function
-func (s *ConsulStore) innerCreateTxn(ctx context.Context, manifest manifest.Manifest, nodeSelector klabels.Selector, podLabels klabels.Set, allocationStrategy fields.Strategy) (fields.RC, error) {
+// The new RC has the settings of template, but a new ID, no replicas and is
+// enabled.
+func (s *ConsulStore) innerCreateTxn(ctx context.Context, template fields.RC) (fields.RC, error) {
 	id := fields.ID(uuid.Must(uuid.NewV4()).String())
 	rcp, err := s.rcPath(id)
 	if err != nil {
 		return fields.RC{}, err
 	}
 
-	rc := fields.RC{
-		ID:                 id,
-		Manifest:           manifest,
-		NodeSelector:       nodeSelector,
-		PodLabels:          podLabels,
-		ReplicasDesired:    0,
-		Disabled:           false,
-		AllocationStrategy: allocationStrategy,
-	}
+	rc := template
+	rc.ID = id
+	rc.ReplicasDesired = 0
+	rc.Disabled = false
 
 	jsonRC, err := json.Marshal(rc)
 	if err != nil {
@@ -662,22 +675,40 @@ func (s *ConsulStore) CASDesiredReplicas(id fields.ID, expected int, n int) erro
 	})
 }
 
+// CASDesiredReplicasTxn is like CASDesiredReplicas but adds the KV
+// operations to ctx rather than applying them immediately.
+func (s *ConsulStore) CASDesiredReplicasTxn(ctx context.Context, id fields.ID, expected int, n int) error {
+	return s.mutateRCTxn(ctx, id, func(rc fields.RC) (fields.RC, error) {
+		if rc.ReplicasDesired != expected {
+			return rc, fmt.Errorf("replication controller %s has %d desired replicas instead of %d, not setting to %d", rc.ID, rc.ReplicasDesired, expected, n)
+		}
+		rc.ReplicasDesired = n
+		return rc, nil
+	})
+}
+
 // Delete removes the RC with the given ID the targeted RC, returning an error
 // if it does not exist.  Normally an RC can only be deleted if its desired
 // replica count is zero; pass force=true to override this check.
 func (s *ConsulStore) Delete(id fields.ID, force bool) error {
-	return s.retryMutate(id, func(rc fields.RC) (fields.RC, error) {
+	err := s.retryMutate(id, func(rc fields.RC) (fields.RC, error) {
 		if !force && rc.ReplicasDesired != 0 {
 			return fields.RC{}, fmt.Errorf("replication controller %s has %d desired replicas (must reduce to 0 before deleting)", rc.ID, rc.ReplicasDesired)
 		}
 		return fields.RC{}, nil
 	})
+	if err != nil {
+		return err
+	}
+
+	// Like the labels, the history is left dangling if this fails
+	return s.deleteHistory(id)
 }
 
 // DeleteTxn adds a deletion operation to the passed context rather than
 // immediately deleting ig
 func (s *ConsulStore) DeleteTxn(ctx context.Context, id fields.ID, force bool) error {
-	return s.mutateRCTxn(ctx, id, func(rc fields.RC) (fields.RC, error) {
+	err := s.mutateRCTxn(ctx, id, func(rc fields.RC) (fields.RC, error) {
 		if force {
 			return fields.RC{}, nil
 		}
@@ -688,15 +719,50 @@ func (s *ConsulStore) DeleteTxn(ctx context.Context, id fields.ID, force bool) e
 
 		return fields.RC{}, nil
 	})
+	if err != nil {
+		return err
+	}
+
+	return s.deleteHistoryTxn(ctx, id)
 }
 
 // UpdateManifest will set the manifest on the RC at the given ID. Be careful with this function!
 func (s *ConsulStore) UpdateManifest(id fields.ID, man manifest.Manifest) error {
-	manifestUpdater := func(rc fields.RC) (fields.RC, error) {
+	err := s.updateManifest(id, man)
+	for i := 0; i < s.retries; i++ {
+		if _, ok := err.(CASError); ok {
+			err = s.updateManifest(id, man)
+		} else {
+			break
+		}
+	}
+	return err
+}
+
+func (s *ConsulStore) updateManifest(id fields.ID, man manifest.Manifest) error {
+	ctx, cancel := transaction.New(context.Background())
+	defer cancel()
+
+	err := s.UpdateManifestTxn(ctx, id, man)
+	if err != nil {
+		return err
+	}
+	return s.commit(ctx, id)
+}
+
+// UpdateManifestTxn adds the KV operations required to set the manifest on the
+// RC at the given ID and record it in the RC's history to ctx. The revision is
+// attributed to the author set with WithAuthor, if any.
+func (s *ConsulStore) UpdateManifestTxn(ctx context.Context, id fields.ID, man manifest.Manifest) error {
+	err := s.mutateRCTxn(ctx, id, func(rc fields.RC) (fields.RC, error) {
 		rc.Manifest = man
 		return rc, nil
+	})
+	if err != nil {
+		return err
 	}
-	return s.retryMutate(id, manifestUpdater)
+
+	return s.recordRevisionTxn(ctx, id, man)
 }
 
 func (s *ConsulStore) UpdateStrategy(id fields.ID, strategy fields.Strategy) error {
@@ -711,6 +777,109 @@ func (s *ConsulStore) UpdateStrategy(id fields.ID, strategy fields.Strategy) err
 	return s.retryMutate(id, strategyUpdater)
 }
 
+// UpdateSpreadConstraints replaces the spread constraints of the RC at the
+// given ID. An empty list removes all constraints.
+func (s *ConsulStore) UpdateSpreadConstraints(id fields.ID, constraints []fields.SpreadConstraint) error {
+	for _, constraint := range constraints {
+		err := constraint.Validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	spreadUpdater := func(rc fields.RC) (fields.RC, error) {
+		rc.SpreadConstraints = constraints
+		return rc, nil
+	}
+	return s.retryMutate(id, spreadUpdater)
+}
+
+// UpdateAutoscalePolicy sets the autoscale policy of the RC at the given ID.
+// A nil policy opts the RC out of autoscaling.
+func (s *ConsulStore) UpdateAutoscalePolicy(id fields.ID, policy *fields.AutoscalePolicy) error {
+	if policy != nil {
+		err := policy.Validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	autoscaleUpdater := func(rc fields.RC) (fields.RC, error) {
+		rc.Autoscale = policy
+		return rc, nil
+	}
+	return s.retryMutate(id, autoscaleUpdater)
+}
+
+// SetSpreadConstraintsTxn adds an operation to the transaction that replaces
+// the spread constraints of the RC at the given ID.
+func (s *ConsulStore) SetSpreadConstraintsTxn(ctx context.Context, id fields.ID, constraints []fields.SpreadConstraint) error {
+	for _, constraint := range constraints {
+		err := constraint.Validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.mutateRCTxn(ctx, id, func(rc fields.RC) (fields.RC, error) {
+		rc.SpreadConstraints = constraints
+		return rc, nil
+	})
+}
+
+// SetAutoscalePolicyTxn adds an operation to the transaction that sets the
+// autoscale policy of the RC at the given ID. A nil policy opts the RC out of
+// autoscaling.
+func (s *ConsulStore) SetAutoscalePolicyTxn(ctx context.Context, id fields.ID, policy *fields.AutoscalePolicy) error {
+	if policy != nil {
+		err := policy.Validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.mutateRCTxn(ctx, id, func(rc fields.RC) (fields.RC, error) {
+		rc.Autoscale = policy
+		return rc, nil
+	})
+}
+
+// SetZoneFiltersTxn adds an operation to the transaction that replaces the
+// zone filters of the RC at the given ID. A nil filter removes it.
+func (s *ConsulStore) SetZoneFiltersTxn(ctx context.Context, id fields.ID, scheduleZones *fields.ZoneFilter, unscheduleZones *fields.ZoneFilter) error {
+	for _, filter := range []*fields.ZoneFilter{scheduleZones, unscheduleZones} {
+		if filter != nil {
+			err := filter.Validate()
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return s.mutateRCTxn(ctx, id, func(rc fields.RC) (fields.RC, error) {
+		rc.ScheduleZones = scheduleZones
+		rc.UnscheduleZones = unscheduleZones
+		return rc, nil
+	})
+}
+
+// commit applies the transaction in ctx. A rolled back transaction is reported
+// as a CASError on the RC with the given ID so that callers may retry it.
+func (s *ConsulStore) commit(ctx context.Context, id fields.ID) error {
+	ok, _, err := transaction.Commit(ctx, s.kv)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		rcp, err := s.rcPath(id)
+		if err != nil {
+			return err
+		}
+		return CASError(rcp)
+	}
+	return nil
+}
+
 // TODO: this function is almost a verbatim copy of pkg/labels retryMutate, can
 // we find some way to combine them?
 func (s *ConsulStore) retryMutate(id fields.ID, mutator func(fields.RC) (fields.RC, error)) error {
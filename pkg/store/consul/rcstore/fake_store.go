@@ -88,6 +88,24 @@ func (s *fakeStore) CreateTxn(
 	panic("transactions not implemented in fake rc store")
 }
 
+func (s *fakeStore) CreateReplacementTxn(
+	ctx context.Context,
+	oldRC fields.RC,
+	manifest manifest.Manifest,
+	nodeSelector labels.Selector,
+	availabilityZone pc_fields.AvailabilityZone,
+	clusterName pc_fields.ClusterName,
+	podLabels labels.Set,
+	additionalLabels labels.Set,
+	allocationStrategy fields.Strategy,
+) (fields.RC, error) {
+	panic("transactions not implemented in fake rc store")
+}
+
+func (s *fakeStore) SetZoneFiltersTxn(ctx context.Context, id fields.ID, scheduleZones *fields.ZoneFilter, unscheduleZones *fields.ZoneFilter) error {
+	panic("transactions not implemented in fake rc store")
+}
+
 func (s *fakeStore) Get(id fields.ID) (fields.RC, error) {
 	entry, ok := s.rcs[id]
 	if !ok {
@@ -0,0 +1,381 @@
+package rcstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+
+	"github.com/square/p2/pkg/manifest"
+	"github.com/square/p2/pkg/rc/fields"
+	"github.com/square/p2/pkg/store/consul/consulutil"
+	"github.com/square/p2/pkg/store/consul/transaction"
+	"github.com/square/p2/pkg/util"
+)
+
+// The manifest history of each RC is kept under its own tree rather than
+// beneath rcTree, because everything under rcTree is expected to be an RC.
+// Each revision is stored in its own key, rc_history/<rc id>/<number>, so no
+// single key has to hold more than one manifest.
+const rcHistoryTree string = "rc_history"
+
+// RevisionHistoryLimit is the number of revisions retained for each RC. Once
+// the limit is reached the oldest revisions are discarded.
+const RevisionHistoryLimit = 10
+
+var NoRevision error = errors.New("No such revision")
+
+func IsNoRevision(err error) bool {
+	return err == NoRevision
+}
+
+type authorKey struct{}
+
+// WithAuthor returns a context that causes any revisions recorded by the
+// store's Txn functions to be attributed to author.
+func WithAuthor(ctx context.Context, author string) context.Context {
+	return context.WithValue(ctx, authorKey{}, author)
+}
+
+func authorFromContext(ctx context.Context) string {
+	author, _ := ctx.Value(authorKey{}).(string)
+	return author
+}
+
+// History returns the recorded manifest revisions of the RC with the given
+// ID, oldest first. An RC that predates revision tracking has an empty
+// history.
+func (s *ConsulStore) History(id fields.ID) ([]fields.Revision, error) {
+	pairs, err := s.historyPairs(id)
+	if err != nil {
+		return nil, err
+	}
+	return pairsToHistory(id, pairs)
+}
+
+// GetRevision returns the revision with the given number from the history of
+// the RC with the given ID. Returns NoRevision if the revision doesn't exist
+// or has been discarded.
+func (s *ConsulStore) GetRevision(id fields.ID, number int) (fields.Revision, error) {
+	revisionPath, err := s.revisionPath(id, number)
+	if err != nil {
+		return fields.Revision{}, err
+	}
+
+	kvp, _, err := s.kv.Get(revisionPath, nil)
+	if err != nil {
+		return fields.Revision{}, consulutil.NewKVError("get", revisionPath, err)
+	}
+	if kvp == nil {
+		return fields.Revision{}, NoRevision
+	}
+
+	var revision fields.Revision
+	err = json.Unmarshal(kvp.Value, &revision)
+	if err != nil {
+		return fields.Revision{}, util.Errorf("Could not unmarshal revision %d of RC %s as json: %s", number, id, err)
+	}
+	return revision, nil
+}
+
+// InheritHistoryTxn adds the KV operations required to prepend the history of
+// the RC identified by fromID to the history of the RC identified by toID.
+// This is used when a rolling update completes so that the new RC's revision
+// numbers carry on from the old one's.
+func (s *ConsulStore) InheritHistoryTxn(ctx context.Context, fromID fields.ID, toID fields.ID) error {
+	fromHistory, err := s.History(fromID)
+	if err != nil {
+		return err
+	}
+
+	if len(fromHistory) == 0 {
+		// The old RC predates revision tracking, its current manifest
+		// is the best we can do
+		fromRC, err := s.Get(fromID)
+		if err == NoReplicationController {
+			// nothing to inherit
+			return nil
+		} else if err != nil {
+			return err
+		}
+		fromHistory, _, err = appendRevision(nil, fromRC.Manifest, "", time.Time{})
+		if err != nil {
+			return err
+		}
+	}
+
+	toPairs, err := s.historyPairs(toID)
+	if err != nil {
+		return err
+	}
+	toHistory, err := pairsToHistory(toID, toPairs)
+	if err != nil {
+		return err
+	}
+
+	merged := fromHistory
+	for _, revision := range toHistory {
+		last := merged[len(merged)-1]
+		if last.SHA == revision.SHA {
+			continue
+		}
+		revision.Number = last.Number + 1
+		merged = append(merged, revision)
+	}
+
+	// The revisions of the new RC are renumbered, so its history is
+	// replaced wholesale. Fail if it changes in the meantime.
+	for _, pair := range toPairs {
+		err = transaction.Add(ctx, api.KVTxnOp{
+			Verb:  api.KVCheckIndex,
+			Key:   pair.Key,
+			Index: pair.ModifyIndex,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	err = s.deleteHistoryTxn(ctx, toID)
+	if err != nil {
+		return err
+	}
+
+	for _, revision := range trimHistory(merged) {
+		pair, err := s.revisionPair(toID, revision)
+		if err != nil {
+			return err
+		}
+		err = transaction.Add(ctx, api.KVTxnOp{
+			Verb:  string(api.KVSet),
+			Key:   pair.Key,
+			Value: pair.Value,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordRevisionTxn adds the KV operations required to append man to the
+// history of the RC with the given ID to ctx. Nothing is recorded if man is the
+// same as the latest revision.
+func (s *ConsulStore) recordRevisionTxn(ctx context.Context, id fields.ID, man manifest.Manifest) error {
+	pair, discarded, err := s.nextRevision(id, man, authorFromContext(ctx))
+	if err != nil {
+		return err
+	}
+	if pair == nil {
+		return nil
+	}
+
+	err = transaction.Add(ctx, api.KVTxnOp{
+		Verb:  api.KVCAS,
+		Key:   pair.Key,
+		Value: pair.Value,
+		Index: pair.ModifyIndex,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, old := range discarded {
+		err = transaction.Add(ctx, api.KVTxnOp{
+			Verb:  api.KVDeleteCAS,
+			Key:   old.Key,
+			Index: old.ModifyIndex,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteHistoryTxn adds the KV operation required to delete the history of
+// the RC with the given ID.
+func (s *ConsulStore) deleteHistoryTxn(ctx context.Context, id fields.ID) error {
+	historyPrefix, err := s.historyPrefix(id)
+	if err != nil {
+		return err
+	}
+
+	return transaction.Add(ctx, api.KVTxnOp{
+		Verb: api.KVDeleteTree,
+		Key:  historyPrefix,
+	})
+}
+
+// deleteHistory is like deleteHistoryTxn but deletes the history right away.
+func (s *ConsulStore) deleteHistory(id fields.ID) error {
+	pairs, err := s.historyPairs(id)
+	if err != nil {
+		return err
+	}
+
+	return s.deletePairs(pairs)
+}
+
+func (s *ConsulStore) deletePairs(pairs api.KVPairs) error {
+	for _, pair := range pairs {
+		success, _, err := s.kv.DeleteCAS(pair, nil)
+		if err != nil {
+			return consulutil.NewKVError("delete-cas", pair.Key, err)
+		}
+		if !success {
+			return CASError(pair.Key)
+		}
+	}
+	return nil
+}
+
+// nextRevision returns the KV pair that appends man to the history of the RC
+// with the given ID, along with the pairs of the revisions that it pushes
+// beyond RevisionHistoryLimit. The returned pair is nil if man is the same as
+// the latest revision. Its index is 0 because the key must not exist yet,
+// which guards against two writers recording the same revision number.
+func (s *ConsulStore) nextRevision(id fields.ID, man manifest.Manifest, author string) (*api.KVPair, api.KVPairs, error) {
+	pairs, err := s.historyPairs(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	history, err := pairsToHistory(id, pairs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	history, changed, err := appendRevision(history, man, author, time.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+	if !changed {
+		return nil, nil, nil
+	}
+
+	pair, err := s.revisionPair(id, history[len(history)-1])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	kept := trimHistory(history)
+	discardedNumbers := make(map[int]bool)
+	for _, revision := range history[:len(history)-len(kept)] {
+		discardedNumbers[revision.Number] = true
+	}
+	var discarded api.KVPairs
+	for _, old := range pairs {
+		number, err := strconv.Atoi(path.Base(old.Key))
+		if err == nil && discardedNumbers[number] {
+			discarded = append(discarded, old)
+		}
+	}
+
+	return pair, discarded, nil
+}
+
+func (s *ConsulStore) revisionPair(id fields.ID, revision fields.Revision) (*api.KVPair, error) {
+	revisionPath, err := s.revisionPath(id, revision.Number)
+	if err != nil {
+		return nil, err
+	}
+
+	revisionBytes, err := json.Marshal(revision)
+	if err != nil {
+		return nil, util.Errorf("Could not marshal RC revision as JSON: %s", err)
+	}
+
+	return &api.KVPair{
+		Key:   revisionPath,
+		Value: revisionBytes,
+	}, nil
+}
+
+// historyPairs returns the KV pairs holding the revisions of the RC with the
+// given ID, in no particular order.
+func (s *ConsulStore) historyPairs(id fields.ID) (api.KVPairs, error) {
+	historyPrefix, err := s.historyPrefix(id)
+	if err != nil {
+		return nil, err
+	}
+
+	pairs, _, err := s.kv.List(historyPrefix, nil)
+	if err != nil {
+		return nil, consulutil.NewKVError("list", historyPrefix, err)
+	}
+	return pairs, nil
+}
+
+// pairsToHistory decodes the revisions held in pairs, oldest first.
+func pairsToHistory(id fields.ID, pairs api.KVPairs) ([]fields.Revision, error) {
+	history := make([]fields.Revision, 0, len(pairs))
+	for _, pair := range pairs {
+		var revision fields.Revision
+		err := json.Unmarshal(pair.Value, &revision)
+		if err != nil {
+			return nil, util.Errorf("Could not unmarshal history of RC %s as json: %s", id, err)
+		}
+		history = append(history, revision)
+	}
+
+	sort.Slice(history, func(i, j int) bool {
+		return history[i].Number < history[j].Number
+	})
+	return history, nil
+}
+
+func (s *ConsulStore) historyPrefix(rcID fields.ID) (string, error) {
+	if rcID == "" {
+		return "", util.Errorf("History path requested for empty RC id")
+	}
+
+	return path.Join(rcHistoryTree, string(rcID)) + "/", nil
+}
+
+func (s *ConsulStore) revisionPath(rcID fields.ID, number int) (string, error) {
+	historyPrefix, err := s.historyPrefix(rcID)
+	if err != nil {
+		return "", err
+	}
+
+	return historyPrefix + strconv.Itoa(number), nil
+}
+
+// appendRevision returns history with a new revision for man added to the
+// end. The returned bool is false, and history is returned unchanged, if man
+// has the same SHA as the latest revision.
+func appendRevision(history []fields.Revision, man manifest.Manifest, author string, now time.Time) ([]fields.Revision, bool, error) {
+	sha, err := man.SHA()
+	if err != nil {
+		return nil, false, util.Errorf("Could not compute manifest SHA: %s", err)
+	}
+
+	number := 1
+	if len(history) > 0 {
+		last := history[len(history)-1]
+		if last.SHA == sha {
+			return history, false, nil
+		}
+		number = last.Number + 1
+	}
+
+	return append(history, fields.Revision{
+		Number:    number,
+		SHA:       sha,
+		Author:    author,
+		Timestamp: now,
+		Manifest:  man,
+	}), true, nil
+}
+
+// trimHistory discards the oldest revisions beyond RevisionHistoryLimit.
+func trimHistory(history []fields.Revision) []fields.Revision {
+	if len(history) <= RevisionHistoryLimit {
+		return history
+	}
+	return history[len(history)-RevisionHistoryLimit:]
+}
@@ -0,0 +1,74 @@
+package rcstore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/square/p2/pkg/manifest"
+	"github.com/square/p2/pkg/rc/fields"
+	"github.com/square/p2/pkg/types"
+)
+
+func manifestWithID(id types.PodID) manifest.Manifest {
+	builder := manifest.NewBuilder()
+	builder.SetID(id)
+	return builder.GetManifest()
+}
+
+func TestAppendRevision(t *testing.T) {
+	now := time.Now()
+	history, changed, err := appendRevision(nil, manifestWithID("first"), "alice", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changed {
+		t.Fatal("expected appending to an empty history to change it")
+	}
+	if len(history) != 1 || history[0].Number != 1 {
+		t.Fatalf("expected a single revision numbered 1, got %+v", history)
+	}
+	if history[0].Author != "alice" || !history[0].Timestamp.Equal(now) {
+		t.Errorf("expected revision by alice at %s, got %q at %s", now, history[0].Author, history[0].Timestamp)
+	}
+
+	history, changed, err = appendRevision(history, manifestWithID("first"), "bob", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed || len(history) != 1 {
+		t.Fatalf("expected an unchanged manifest to not be recorded, got %+v", history)
+	}
+
+	history, changed, err = appendRevision(history, manifestWithID("second"), "bob", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changed || len(history) != 2 || history[1].Number != 2 {
+		t.Fatalf("expected a second revision numbered 2, got %+v", history)
+	}
+	sha, err := manifestWithID("second").SHA()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if history[1].SHA != sha {
+		t.Errorf("expected revision SHA to be %s but was %s", sha, history[1].SHA)
+	}
+}
+
+func TestTrimHistory(t *testing.T) {
+	var history []fields.Revision
+	for i := 1; i <= RevisionHistoryLimit+3; i++ {
+		history = append(history, fields.Revision{Number: i})
+	}
+
+	trimmed := trimHistory(history)
+	if len(trimmed) != RevisionHistoryLimit {
+		t.Fatalf("expected %d revisions to be kept, got %d", RevisionHistoryLimit, len(trimmed))
+	}
+	if trimmed[0].Number != 4 {
+		t.Errorf("expected the oldest revisions to be discarded, first remaining was %d", trimmed[0].Number)
+	}
+	if trimmed[len(trimmed)-1].Number != RevisionHistoryLimit+3 {
+		t.Errorf("expected the newest revision to be kept, last remaining was %d", trimmed[len(trimmed)-1].Number)
+	}
+}
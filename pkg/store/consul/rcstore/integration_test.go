@@ -10,10 +10,12 @@ import (
 
 	"github.com/square/p2/pkg/labels"
 	"github.com/square/p2/pkg/manifest"
+	pc_fields "github.com/square/p2/pkg/pc/fields"
 	rcfields "github.com/square/p2/pkg/rc/fields"
 	"github.com/square/p2/pkg/store/consul"
 	"github.com/square/p2/pkg/store/consul/consulutil"
 	"github.com/square/p2/pkg/store/consul/transaction"
+	"github.com/square/p2/pkg/types"
 
 	"github.com/gofrs/uuid"
 	klabels "k8s.io/kubernetes/pkg/labels"
@@ -464,8 +466,262 @@ func TestLockForNodeTransfer(t *testing.T) {
 	wg.Wait()
 }
 
+func TestFindWhereLabeled(t *testing.T) {
+	fixture := consulutil.NewFixture(t)
+	defer fixture.Stop()
+
+	applicator := labels.NewConsulApplicator(fixture.Client, 0, 0)
+	rcstore := NewConsul(fixture.Client, applicator, 1)
+
+	manB := manifest.NewBuilder()
+	manB.SetID("testPod")
+	podID := types.PodID("testPod")
+	az := pc_fields.AvailabilityZone("az")
+	cn := pc_fields.ClusterName("cn")
+	rcLabels := klabels.Set{}
+	rcLabels[pc_fields.PodIDLabel] = "testPod"
+	rcLabels[pc_fields.AvailabilityZoneLabel] = "az"
+	rcLabels[pc_fields.ClusterNameLabel] = "cn"
+	_, err := rcstore.Create(manB.GetManifest(), nil, az, cn, klabels.Set{}, rcLabels, rcfields.StaticStrategy)
+	if err != nil {
+		t.Errorf("Caught error creating RC: %v", err)
+	}
+
+	labeled, err := rcstore.FindWhereLabeled(podID, az, cn)
+	if err != nil {
+		t.Errorf("Caught error in FindWhereLabeled: %v", err)
+	}
+	if len(labeled) != 1 {
+		t.Errorf("Found wrong number of RCs")
+	}
+	if labeled[0].Manifest.ID() != podID {
+		t.Errorf("Found wrong RC. Expected %s, got %s", podID, labeled[0].Manifest.ID())
+	}
+}
+
 func testManifest() manifest.Manifest {
 	builder := manifest.NewBuilder()
 	builder.SetID("some_pod_id")
 	return builder.GetManifest()
 }
+
+func TestHistoryRecordsManifestChanges(t *testing.T) {
+	fixture := consulutil.NewFixture(t)
+	defer fixture.Stop()
+
+	applicator := labels.NewConsulApplicator(fixture.Client, 0, 0)
+	store := NewConsul(fixture.Client, applicator, 0)
+
+	ctx, cancelFunc := transaction.New(WithAuthor(context.Background(), "alice"))
+	defer cancelFunc()
+	rc, err := store.CreateTxn(ctx, testManifest(), klabels.Everything(), "some_az", "some_cn", nil, nil, "some_strategy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = transaction.MustCommit(ctx, fixture.Client.KV())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	builder := testManifest().GetBuilder()
+	builder.SetRunAsUser("someone_else")
+	newManifest := builder.GetManifest()
+
+	ctx, cancelFunc = transaction.New(WithAuthor(context.Background(), "bob"))
+	defer cancelFunc()
+	err = store.UpdateManifestTxn(ctx, rc.ID, newManifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = transaction.MustCommit(ctx, fixture.Client.KV())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	history, err := store.History(rc.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("expected 2 revisions but there were %d", len(history))
+	}
+	if history[0].Number != 1 || history[0].Author != "alice" {
+		t.Errorf("expected revision 1 by alice, got revision %d by %q", history[0].Number, history[0].Author)
+	}
+	if history[1].Number != 2 || history[1].Author != "bob" {
+		t.Errorf("expected revision 2 by bob, got revision %d by %q", history[1].Number, history[1].Author)
+	}
+
+	revision, err := store.GetRevision(rc.ID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if revision.Manifest.RunAsUser() != testManifest().RunAsUser() {
+		t.Errorf("expected revision 1 to have the original manifest, but it runs as %q", revision.Manifest.RunAsUser())
+	}
+
+	_, err = store.GetRevision(rc.ID, 3)
+	if !IsNoRevision(err) {
+		t.Errorf("expected NoRevision for a revision that doesn't exist, got %v", err)
+	}
+
+	err = store.Delete(rc.ID, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	history, err = store.History(rc.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 0 {
+		t.Errorf("expected history to be deleted along with the RC but found %d revisions", len(history))
+	}
+}
+
+func TestCreateRecordsFirstRevision(t *testing.T) {
+	fixture := consulutil.NewFixture(t)
+	defer fixture.Stop()
+
+	applicator := labels.NewConsulApplicator(fixture.Client, 0, 0)
+	store := NewConsul(fixture.Client, applicator, 0)
+
+	rc, err := store.Create(testManifest(), klabels.Everything(), "some_az", "some_cn", nil, nil, "some_strategy")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	history, err := store.History(rc.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 || history[0].Number != 1 {
+		t.Fatalf("expected the RC to be created with revision 1, got %+v", history)
+	}
+
+	rcLabels, err := applicator.GetLabels(labels.RC, rc.ID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rcLabels.Labels[pc_fields.PodIDLabel] != "some_pod_id" {
+		t.Errorf("expected the RC to be labeled with its pod ID, got %v", rcLabels.Labels)
+	}
+}
+
+func TestHistoryDiscardsOldestRevisions(t *testing.T) {
+	fixture := consulutil.NewFixture(t)
+	defer fixture.Stop()
+
+	applicator := labels.NewConsulApplicator(fixture.Client, 0, 0)
+	store := NewConsul(fixture.Client, applicator, 0)
+
+	rc, err := store.Create(testManifest(), klabels.Everything(), "some_az", "some_cn", nil, nil, "some_strategy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < RevisionHistoryLimit+2; i++ {
+		builder := testManifest().GetBuilder()
+		builder.SetRunAsUser(fmt.Sprintf("user%d", i))
+		err = store.UpdateManifest(rc.ID, builder.GetManifest())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	history, err := store.History(rc.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != RevisionHistoryLimit {
+		t.Fatalf("expected %d revisions but there were %d", RevisionHistoryLimit, len(history))
+	}
+	if history[0].Number != 4 || history[len(history)-1].Number != RevisionHistoryLimit+3 {
+		t.Errorf("expected revisions 4 through %d, got %d through %d", RevisionHistoryLimit+3, history[0].Number, history[len(history)-1].Number)
+	}
+
+	// each revision is kept in its own key
+	keys, _, err := fixture.Client.KV().Keys(rcHistoryTree+"/"+rc.ID.String()+"/", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != RevisionHistoryLimit {
+		t.Errorf("expected a key for each of the %d revisions, got %s", RevisionHistoryLimit, keys)
+	}
+
+	_, err = store.GetRevision(rc.ID, 3)
+	if !IsNoRevision(err) {
+		t.Errorf("expected NoRevision for a discarded revision, got %v", err)
+	}
+	revision, err := store.GetRevision(rc.ID, RevisionHistoryLimit+3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if revision.Manifest.RunAsUser() != fmt.Sprintf("user%d", RevisionHistoryLimit+1) {
+		t.Errorf("expected the latest revision to have the latest manifest, but it runs as %q", revision.Manifest.RunAsUser())
+	}
+}
+
+func TestInheritHistoryTxn(t *testing.T) {
+	fixture := consulutil.NewFixture(t)
+	defer fixture.Stop()
+
+	applicator := labels.NewConsulApplicator(fixture.Client, 0, 0)
+	store := NewConsul(fixture.Client, applicator, 0)
+
+	builder := testManifest().GetBuilder()
+	builder.SetRunAsUser("someone_else")
+	secondManifest := builder.GetManifest()
+
+	oldRC, err := store.Create(testManifest(), klabels.Everything(), "some_az", "some_cn", nil, nil, "some_strategy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.UpdateManifest(oldRC.ID, secondManifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// roll back to the first manifest
+	newRC, err := store.Create(testManifest(), klabels.Everything(), "some_az", "some_cn", nil, nil, "some_strategy")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancelFunc := transaction.New(context.Background())
+	defer cancelFunc()
+	err = store.InheritHistoryTxn(ctx, oldRC.ID, newRC.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.DeleteTxn(ctx, oldRC.ID, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = transaction.MustCommit(ctx, fixture.Client.KV())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	history, err := store.History(newRC.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 3 {
+		t.Fatalf("expected 3 revisions but there were %d", len(history))
+	}
+	for i, revision := range history {
+		if revision.Number != i+1 {
+			t.Errorf("expected revision at position %d to be numbered %d, was %d", i, i+1, revision.Number)
+		}
+	}
+	if history[0].SHA != history[2].SHA {
+		t.Errorf("expected the latest revision to have the SHA of revision 1 (%s) but it was %s", history[0].SHA, history[2].SHA)
+	}
+
+	oldHistory, err := store.History(oldRC.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(oldHistory) != 0 {
+		t.Errorf("expected old RC history to be deleted with the RC but found %d revisions", len(oldHistory))
+	}
+}
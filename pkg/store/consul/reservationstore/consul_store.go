@@ -0,0 +1,162 @@
+// Package reservationstore records the resources that a scheduler has set
+// aside on nodes for pods that have not necessarily been scheduled yet.
+package reservationstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+
+	"github.com/square/p2/pkg/store/consul/consulutil"
+	"github.com/square/p2/pkg/types"
+	"github.com/square/p2/pkg/util"
+	"github.com/square/p2/pkg/util/size"
+)
+
+const reservationTree string = "scheduler_reservations"
+
+// Reservation is the share of a node's capacity set aside for a pod.
+type Reservation struct {
+	PodID  types.PodID    `json:"pod_id"`
+	CPUs   int            `json:"cpus,omitempty"`
+	Memory size.ByteCount `json:"memory,omitempty"`
+
+	// NodeSelector is the selector of the replication controller the
+	// reservation was allocated for. A deallocation only identifies the
+	// selector, so it is used to find the reservations to release.
+	NodeSelector string `json:"node_selector,omitempty"`
+
+	// ReservedAt lets a scheduler expire reservations that were never
+	// followed by the pod being scheduled
+	ReservedAt time.Time `json:"reserved_at"`
+}
+
+// NodeReservations holds every reservation on a node, keyed by the ID of the
+// pod it was made for. A node runs at most one copy of a pod, so a pod has at
+// most one reservation on it.
+type NodeReservations map[types.PodID]Reservation
+
+type consulKV interface {
+	Get(key string, opts *api.QueryOptions) (*api.KVPair, *api.QueryMeta, error)
+	List(prefix string, opts *api.QueryOptions) (api.KVPairs, *api.QueryMeta, error)
+	CAS(pair *api.KVPair, opts *api.WriteOptions) (bool, *api.WriteMeta, error)
+	DeleteCAS(pair *api.KVPair, opts *api.WriteOptions) (bool, *api.WriteMeta, error)
+}
+
+type ConsulStore struct {
+	kv      consulKV
+	retries int
+}
+
+func NewConsul(client consulutil.ConsulClient, retries int) *ConsulStore {
+	return &ConsulStore{
+		kv:      client.KV(),
+		retries: retries,
+	}
+}
+
+// CASError is returned when a node's reservations were changed by someone
+// else between being read and being written.
+type CASError string
+
+func (e CASError) Error() string {
+	return fmt.Sprintf("Could not check-and-set key %q", string(e))
+}
+
+// List returns the reservations on every node that has any.
+func (s *ConsulStore) List() (map[types.NodeName]NodeReservations, error) {
+	pairs, _, err := s.kv.List(reservationTree+"/", nil)
+	if err != nil {
+		return nil, consulutil.NewKVError("list", reservationTree+"/", err)
+	}
+
+	ret := make(map[types.NodeName]NodeReservations, len(pairs))
+	for _, pair := range pairs {
+		node := types.NodeName(strings.TrimPrefix(pair.Key, reservationTree+"/"))
+		reservations, err := unmarshal(pair)
+		if err != nil {
+			return nil, err
+		}
+		ret[node] = reservations
+	}
+	return ret, nil
+}
+
+// Mutate atomically replaces a node's reservations with the result of
+// mutator, retrying if they change concurrently. The mutator may be called
+// more than once, and an error from it aborts the mutation. Returning no
+// reservations removes the node's record.
+func (s *ConsulStore) Mutate(node types.NodeName, mutator func(NodeReservations) (NodeReservations, error)) error {
+	err := s.mutate(node, mutator)
+	for i := 0; i < s.retries; i++ {
+		if _, ok := err.(CASError); ok {
+			err = s.mutate(node, mutator)
+		} else {
+			break
+		}
+	}
+	return err
+}
+
+func (s *ConsulStore) mutate(node types.NodeName, mutator func(NodeReservations) (NodeReservations, error)) error {
+	key := path.Join(reservationTree, node.String())
+	pair, _, err := s.kv.Get(key, nil)
+	if err != nil {
+		return consulutil.NewKVError("get", key, err)
+	}
+
+	var modifyIndex uint64
+	reservations := make(NodeReservations)
+	if pair != nil {
+		modifyIndex = pair.ModifyIndex
+		reservations, err = unmarshal(pair)
+		if err != nil {
+			return err
+		}
+	}
+
+	reservations, err = mutator(reservations)
+	if err != nil {
+		return err
+	}
+
+	if len(reservations) == 0 {
+		if pair == nil {
+			return nil
+		}
+		ok, _, err := s.kv.DeleteCAS(&api.KVPair{Key: key, ModifyIndex: modifyIndex}, nil)
+		if err != nil {
+			return consulutil.NewKVError("delete-cas", key, err)
+		}
+		if !ok {
+			return CASError(key)
+		}
+		return nil
+	}
+
+	b, err := json.Marshal(reservations)
+	if err != nil {
+		return util.Errorf("could not marshal reservations for %s as JSON: %s", node, err)
+	}
+	ok, _, err := s.kv.CAS(&api.KVPair{Key: key, Value: b, ModifyIndex: modifyIndex}, nil)
+	if err != nil {
+		return consulutil.NewKVError("cas", key, err)
+	}
+	if !ok {
+		return CASError(key)
+	}
+	return nil
+}
+
+func unmarshal(pair *api.KVPair) (NodeReservations, error) {
+	reservations := make(NodeReservations)
+	err := json.Unmarshal(pair.Value, &reservations)
+	if err != nil {
+		return nil, util.Errorf("could not unmarshal reservations at %s: %s", pair.Key, err)
+	}
+	return reservations, nil
+}
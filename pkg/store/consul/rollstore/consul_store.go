@@ -22,6 +22,8 @@ import (
 	"github.com/square/p2/pkg/store/consul"
 	"github.com/square/p2/pkg/store/consul/consulutil"
 	"github.com/square/p2/pkg/store/consul/rcstore"
+	"github.com/square/p2/pkg/store/consul/statusstore"
+	"github.com/square/p2/pkg/store/consul/statusstore/rollstatus"
 	"github.com/square/p2/pkg/store/consul/transaction"
 	"github.com/square/p2/pkg/util"
 )
@@ -72,6 +74,19 @@ type ReplicationControllerStore interface {
 		additionalLabels klabels.Set,
 		allocationStrategy rc_fields.Strategy,
 	) (rc_fields.RC, error)
+	CreateReplacementTxn(
+		ctx context.Context,
+		oldRC rc_fields.RC,
+		manifest manifest.Manifest,
+		nodeSelector klabels.Selector,
+		availabilityZone pc_fields.AvailabilityZone,
+		clusterName pc_fields.ClusterName,
+		podLabels klabels.Set,
+		additionalLabels klabels.Set,
+		allocationStrategy rc_fields.Strategy,
+	) (rc_fields.RC, error)
+	Get(id rc_fields.ID) (rc_fields.RC, error)
+	SetZoneFiltersTxn(ctx context.Context, id rc_fields.ID, scheduleZones *rc_fields.ZoneFilter, unscheduleZones *rc_fields.ZoneFilter) error
 	Delete(id rc_fields.ID, force bool) error
 	UpdateCreationLockPath(rcID rc_fields.ID) (string, error)
 
@@ -87,6 +102,12 @@ type ReplicationControllerStore interface {
 	) (rc_fields.RC, error)
 }
 
+// A subset of rollstatus.ConsulStore, used to delete the status of a rolling
+// update along with it
+type RollStatusStore interface {
+	DeleteTxn(ctx context.Context, id roll_fields.ID) error
+}
+
 type ConsulStore struct {
 	kv KV
 
@@ -102,6 +123,10 @@ type ConsulStore struct {
 	// label selector (see labelRCSpecifier)
 	labeler RollLabeler
 
+	// Where the roll farm publishes the progress of updates. Deleting an
+	// update deletes its status too.
+	statusStore RollStatusStore
+
 	logger logging.Logger
 }
 
@@ -111,10 +136,11 @@ func NewConsul(c consulutil.ConsulClient, labeler RollLabeler, logger *logging.L
 	}
 	return ConsulStore{
 		kv:      c.KV(),
-		rcstore: rcstore.NewConsul(c, labeler, 3),
-		logger:  *logger,
-		labeler: labeler,
-		store:   consul.NewConsulStore(c),
+		rcstore:     rcstore.NewConsul(c, labeler, 3),
+		logger:      *logger,
+		labeler:     labeler,
+		store:       consul.NewConsulStore(c),
+		statusStore: rollstatus.NewConsul(statusstore.NewConsul(c), consul.RollStatusNamespace),
 	}
 }
 
@@ -322,6 +348,44 @@ func (s ConsulStore) CreateRollingUpdateFromOneExistingRCWithID(
 	newRCLabels klabels.Set,
 	rollLabels klabels.Set,
 	newAllocationStrategy rc_fields.Strategy,
+) (roll_fields.Update, error) {
+	u := roll_fields.Update{
+		OldRC:           oldRCID,
+		DesiredReplicas: desiredReplicas,
+		MinimumReplicas: minimumReplicas,
+		LeaveOld:        leaveOld,
+		RollDelay:       rollDelay,
+	}
+	return s.CreateRollingUpdateFromOneExistingRC(
+		ctx,
+		u,
+		availabilityZone,
+		clusterName,
+		newRCManifest,
+		newRCNodeSelector,
+		newRCPodLabels,
+		newRCLabels,
+		rollLabels,
+		newAllocationStrategy,
+	)
+}
+
+// CreateRollingUpdateFromOneExistingRC is like
+// CreateRollingUpdateFromOneExistingRCWithID, except that the update is
+// described by u so that options such as canaries and a failure policy can
+// be set from the start. u.OldRC is the old RC, and u.NewRC is replaced by
+// the ID of the new RC.
+func (s ConsulStore) CreateRollingUpdateFromOneExistingRC(
+	ctx context.Context,
+	u roll_fields.Update,
+	availabilityZone pc_fields.AvailabilityZone,
+	clusterName pc_fields.ClusterName,
+	newRCManifest manifest.Manifest,
+	newRCNodeSelector klabels.Selector,
+	newRCPodLabels klabels.Set,
+	newRCLabels klabels.Set,
+	rollLabels klabels.Set,
+	newAllocationStrategy rc_fields.Strategy,
 ) (roll_fields.Update, error) {
 	session, err := s.newRUCreationSession()
 	if err != nil {
@@ -333,7 +397,7 @@ func (s ConsulStore) CreateRollingUpdateFromOneExistingRCWithID(
 		_ = session.Destroy()
 	}()
 
-	rcIDs := rc_fields.IDs{oldRCID}
+	rcIDs := rc_fields.IDs{u.OldRC}
 	err = s.lockRCs(ctx, rcIDs, session)
 	if err != nil {
 		return roll_fields.Update{}, err
@@ -344,7 +408,13 @@ func (s ConsulStore) CreateRollingUpdateFromOneExistingRCWithID(
 		return roll_fields.Update{}, err
 	}
 
-	rc, err := s.rcstore.CreateTxn(ctx, newRCManifest, newRCNodeSelector, availabilityZone, clusterName, newRCPodLabels, newRCLabels, newAllocationStrategy)
+	// The new RC carries over the old RC's settings, if there is one
+	oldRC, err := s.rcstore.Get(u.OldRC)
+	if err != nil && err != rcstore.NoReplicationController {
+		return roll_fields.Update{}, err
+	}
+
+	rc, err := s.rcstore.CreateReplacementTxn(ctx, oldRC, newRCManifest, newRCNodeSelector, availabilityZone, clusterName, newRCPodLabels, newRCLabels, newAllocationStrategy)
 	if err != nil {
 		return roll_fields.Update{}, err
 	}
@@ -366,15 +436,7 @@ func (s ConsulStore) CreateRollingUpdateFromOneExistingRCWithID(
 		return roll_fields.Update{}, err
 	}
 
-	u := roll_fields.Update{
-		OldRC:           oldRCID,
-		NewRC:           newRCID,
-		DesiredReplicas: desiredReplicas,
-		MinimumReplicas: minimumReplicas,
-		LeaveOld:        leaveOld,
-		RollDelay:       rollDelay,
-	}
-
+	u.NewRC = newRCID
 	return u, s.createRU(ctx, u, rollLabels, session.Session())
 }
 
@@ -485,13 +547,20 @@ func (s ConsulStore) CreateRollingUpdateFromOneMaybeExistingWithLabelSelector(
 	return u, s.createRU(ctx, u, rollLabels, session.Session())
 }
 
-// Delete deletes a rolling update based on its ID.
+// Delete adds operations to ctx that delete a rolling update based on its ID,
+// along with its labels and status. If the update is zone sequenced, the zone
+// filters it set on its RCs are removed as well.
 func (s ConsulStore) Delete(ctx context.Context, id roll_fields.ID) error {
 	key, err := RollPath(id)
 	if err != nil {
 		return err
 	}
 
+	err = s.clearZoneFiltersTxn(ctx, id)
+	if err != nil {
+		return err
+	}
+
 	err = transaction.Add(ctx, api.KVTxnOp{
 		Verb: api.KVDelete,
 		Key:  key,
@@ -505,6 +574,132 @@ func (s ConsulStore) Delete(ctx context.Context, id roll_fields.ID) error {
 		return err
 	}
 
+	if s.statusStore != nil {
+		err = s.statusStore.DeleteTxn(ctx, id)
+		if err != nil {
+			return util.Errorf("could not add RU status deletion operation to transaction: %s", err)
+		}
+	}
+
+	return nil
+}
+
+// clearZoneFiltersTxn adds operations to ctx that remove the zone filters from
+// the RCs of a zone sequenced update. The roll farm removes them itself when
+// an update finishes, but an update that is deleted part way through would
+// otherwise leave its RCs scheduling in, or unscheduling from, only some of
+// the zones. RCs that have no filters are left alone, so that the
+// operations don't conflict with other changes the farm makes to them in the
+// same transaction.
+func (s ConsulStore) clearZoneFiltersTxn(ctx context.Context, id roll_fields.ID) error {
+	u, err := s.Get(id)
+	if err != nil {
+		return err
+	}
+	if u.ZoneSequence == nil {
+		return nil
+	}
+
+	for _, rcID := range []rc_fields.ID{u.NewRC, u.OldRC} {
+		rc, err := s.rcstore.Get(rcID)
+		if err == rcstore.NoReplicationController {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if rc.ScheduleZones == nil && rc.UnscheduleZones == nil {
+			continue
+		}
+
+		err = s.rcstore.SetZoneFiltersTxn(ctx, rcID, nil, nil)
+		if err != nil {
+			return util.Errorf("could not add zone filter removal for RC %s to transaction: %s", rcID, err)
+		}
+	}
+	return nil
+}
+
+// ReplaceTxn adds operations to ctx that delete the rolling update with the
+// given ID and create replacement in its place, carrying over its labels.
+// Unlike the Create functions, it doesn't lock the RCs or check for
+// conflicting updates: it's meant for the farm running the update being
+// replaced, whose RC locks keep other updates from being created for them.
+func (s ConsulStore) ReplaceTxn(ctx context.Context, id roll_fields.ID, replacement roll_fields.Update) error {
+	rollLabels, err := s.labeler.GetLabels(labels.RU, id.String())
+	if err != nil {
+		return err
+	}
+
+	err = s.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.createRU(ctx, replacement, rollLabels.Labels, "")
+}
+
+// PauseTxn adds an operation to ctx that pauses the rolling update with the
+// given ID, recording why and by whom it was paused. The roll farm stops
+// moving replicas for a paused update until it is resumed. The operation
+// fails if the update changes before the transaction is committed.
+func (s ConsulStore) PauseTxn(ctx context.Context, id roll_fields.ID, reason string, user string) error {
+	return s.mutateTxn(ctx, id, func(u roll_fields.Update) roll_fields.Update {
+		u.Paused = true
+		u.PauseReason = reason
+		u.PausedBy = user
+		return u
+	})
+}
+
+// ResumeTxn adds an operation to ctx that resumes the paused rolling update
+// with the given ID. The operation fails if the update changes before the
+// transaction is committed.
+func (s ConsulStore) ResumeTxn(ctx context.Context, id roll_fields.ID) error {
+	return s.mutateTxn(ctx, id, func(u roll_fields.Update) roll_fields.Update {
+		u.Paused = false
+		u.PauseReason = ""
+		u.PausedBy = ""
+		return u
+	})
+}
+
+// mutateTxn reads the rolling update with the given ID and adds an operation
+// to ctx that writes it back as changed by mutator, as long as it hasn't
+// been changed since it was read.
+func (s ConsulStore) mutateTxn(ctx context.Context, id roll_fields.ID, mutator func(roll_fields.Update) roll_fields.Update) error {
+	key, err := RollPath(id)
+	if err != nil {
+		return err
+	}
+
+	kvp, _, err := s.kv.Get(key, nil)
+	if err != nil {
+		return consulutil.NewKVError("get", key, err)
+	}
+	if kvp == nil {
+		return util.Errorf("no rolling update with ID %s", id)
+	}
+
+	u, err := kvpToRU(kvp)
+	if err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(mutator(u))
+	if err != nil {
+		return util.Errorf("could not marshal rolling update as json: %s", err)
+	}
+
+	err = transaction.Add(ctx, api.KVTxnOp{
+		Verb:  api.KVCAS,
+		Key:   key,
+		Value: b,
+		Index: kvp.ModifyIndex,
+	})
+	if err != nil {
+		return util.Errorf("could not add rolling update modification to transaction: %s", err)
+	}
+
 	return nil
 }
 
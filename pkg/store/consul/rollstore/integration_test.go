@@ -5,6 +5,7 @@ package rollstore
 import (
 	"context"
 	"encoding/json"
+	"reflect"
 	"testing"
 	"time"
 
@@ -14,6 +15,8 @@ import (
 	"github.com/square/p2/pkg/store/consul"
 	"github.com/square/p2/pkg/store/consul/consulutil"
 	"github.com/square/p2/pkg/store/consul/rcstore"
+	"github.com/square/p2/pkg/store/consul/statusstore"
+	"github.com/square/p2/pkg/store/consul/statusstore/rollstatus"
 	"github.com/square/p2/pkg/store/consul/transaction"
 	"github.com/square/p2/pkg/types"
 
@@ -161,6 +164,76 @@ func TestCreateRollingUpdateFromOneExistingRCWithID(t *testing.T) {
 	}
 }
 
+func TestCreateRollingUpdateFromOneExistingRCWithIDCarriesOverRCSettings(t *testing.T) {
+	fixture := consulutil.NewFixture(t)
+	defer fixture.Stop()
+
+	rollstore, rcStore := newRollStoreWithRealConsul(t, fixture, nil)
+	rcs := rcstore.NewConsul(fixture.Client, labels.NewConsulApplicator(fixture.Client, 0, 0), 0)
+
+	oldRC, err := rcs.Create(testManifest(), testNodeSelector(), "some_az", "some_cn", podLabels(), nil, "some_strategy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	constraints := []rc_fields.SpreadConstraint{{TopologyKey: "availability_zone", MaxSkew: 1}}
+	err = rcs.UpdateSpreadConstraints(oldRC.ID, constraints)
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy := &rc_fields.AutoscalePolicy{
+		MinReplicas: 1,
+		MaxReplicas: 5,
+		Target:      10,
+		Cooldown:    time.Minute,
+		Metric: rc_fields.MetricSource{
+			Type: rc_fields.HTTPMetricSource,
+			URL:  "http://metrics.example.com/load",
+		},
+	}
+	err = rcs.UpdateAutoscalePolicy(oldRC.ID, policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txn, cancelFunc := transaction.New(context.Background())
+	defer cancelFunc()
+	newUpdate, err := rollstore.CreateRollingUpdateFromOneExistingRCWithID(
+		txn,
+		oldRC.ID,
+		1,
+		0,
+		false,
+		0,
+		"some_az",
+		"some_cn",
+		testManifest(),
+		testNodeSelector(),
+		podLabels(),
+		nil,
+		nil,
+		"some_strategy",
+	)
+	if err != nil {
+		t.Fatalf("Unable to create rolling update: %s", err)
+	}
+
+	err = transaction.MustCommit(txn, fixture.Client.KV())
+	if err != nil {
+		t.Fatalf("unexpected error committing update transaction: %s", err)
+	}
+
+	newRC, err := rcStore.Get(newUpdate.NewRC)
+	if err != nil {
+		t.Fatalf("Shouldn't have failed to fetch new RC: %s", err)
+	}
+	if !reflect.DeepEqual(newRC.SpreadConstraints, constraints) {
+		t.Errorf("Expected the new RC to have the old RC's spread constraints %v, got %v", constraints, newRC.SpreadConstraints)
+	}
+	if !reflect.DeepEqual(newRC.Autoscale, policy) {
+		t.Errorf("Expected the new RC to have the old RC's autoscale policy %+v, got %+v", policy, newRC.Autoscale)
+	}
+}
+
 func TestCreateRollingUpdateFromOneExistingRCWithIDMutualExclusion(t *testing.T) {
 	fixture := consulutil.NewFixture(t)
 	defer fixture.Stop()
@@ -510,6 +583,201 @@ func TestCreateRollingUpdateFromOneMaybeExistingWithLabelSelectorFailsWhenConfli
 
 }
 
+func TestPauseAndResume(t *testing.T) {
+	fixture := consulutil.NewFixture(t)
+	defer fixture.Stop()
+	update := fields.Update{
+		NewRC:           "new_rc",
+		OldRC:           "old_rc",
+		DesiredReplicas: 3,
+	}
+	rollstore, _ := newRollStoreWithRealConsul(t, fixture, []fields.Update{update})
+
+	txn, cancelFunc := transaction.New(context.Background())
+	defer cancelFunc()
+	err := rollstore.PauseTxn(txn, update.ID(), "investigating errors", "some_user")
+	if err != nil {
+		t.Fatalf("Unexpected error pausing update: %s", err)
+	}
+	err = transaction.MustCommit(txn, fixture.Client.KV())
+	if err != nil {
+		t.Fatalf("Unexpected error committing pause transaction: %s", err)
+	}
+
+	stored, err := rollstore.Get(update.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !stored.Paused {
+		t.Error("Expected update to be paused")
+	}
+	if stored.PauseReason != "investigating errors" {
+		t.Errorf("Expected pause reason to be recorded but was %q", stored.PauseReason)
+	}
+	if stored.PausedBy != "some_user" {
+		t.Errorf("Expected pausing user to be recorded but was %q", stored.PausedBy)
+	}
+	if stored.DesiredReplicas != 3 {
+		t.Errorf("Expected pausing to leave the rest of the update alone, desired replicas was %d", stored.DesiredReplicas)
+	}
+
+	txn, cancelFunc = transaction.New(context.Background())
+	defer cancelFunc()
+	err = rollstore.ResumeTxn(txn, update.ID())
+	if err != nil {
+		t.Fatalf("Unexpected error resuming update: %s", err)
+	}
+
+	// change the update before the resume is committed, which should
+	// make it fail
+	concurrent, cancelConcurrent := transaction.New(context.Background())
+	defer cancelConcurrent()
+	err = rollstore.PauseTxn(concurrent, update.ID(), "another reason", "another_user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = transaction.MustCommit(concurrent, fixture.Client.KV())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = transaction.MustCommit(txn, fixture.Client.KV())
+	if err == nil {
+		t.Fatal("Expected resume to fail after the update changed")
+	}
+
+	txn, cancelFunc = transaction.New(context.Background())
+	defer cancelFunc()
+	err = rollstore.ResumeTxn(txn, update.ID())
+	if err != nil {
+		t.Fatalf("Unexpected error resuming update: %s", err)
+	}
+	err = transaction.MustCommit(txn, fixture.Client.KV())
+	if err != nil {
+		t.Fatalf("Unexpected error committing resume transaction: %s", err)
+	}
+
+	stored, err = rollstore.Get(update.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Paused || stored.PauseReason != "" || stored.PausedBy != "" {
+		t.Errorf("Expected update to be resumed but was %+v", stored)
+	}
+}
+
+func TestPauseMissingUpdate(t *testing.T) {
+	fixture := consulutil.NewFixture(t)
+	defer fixture.Stop()
+	rollstore, _ := newRollStoreWithRealConsul(t, fixture, nil)
+
+	txn, cancelFunc := transaction.New(context.Background())
+	defer cancelFunc()
+	err := rollstore.PauseTxn(txn, "missing", "reason", "some_user")
+	if err == nil {
+		t.Fatal("Expected an error pausing an update that doesn't exist")
+	}
+}
+
+func TestDeleteDeletesStatus(t *testing.T) {
+	fixture := consulutil.NewFixture(t)
+	defer fixture.Stop()
+	update := fields.Update{
+		OldRC: "old_rc",
+		NewRC: "new_rc",
+	}
+	rollstore, _ := newRollStoreWithRealConsul(t, fixture, []fields.Update{update})
+
+	statusStore := rollstatus.NewConsul(statusstore.NewConsul(fixture.Client), consul.RollStatusNamespace)
+	err := statusStore.Set(update.ID(), rollstatus.Status{Step: rollstatus.StepRolling})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txn, cancelFunc := transaction.New(context.Background())
+	defer cancelFunc()
+	err = rollstore.Delete(txn, update.ID())
+	if err != nil {
+		t.Fatalf("Unexpected error deleting update: %s", err)
+	}
+	err = transaction.MustCommit(txn, fixture.Client.KV())
+	if err != nil {
+		t.Fatalf("Unexpected error committing deletion: %s", err)
+	}
+
+	stored, err := rollstore.Get(update.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.NewRC != "" {
+		t.Errorf("Expected the update to be deleted but found %+v", stored)
+	}
+	_, _, err = statusStore.Get(update.ID())
+	if !statusstore.IsNoStatus(err) {
+		t.Errorf("Expected the update's status to be deleted with it, got %v", err)
+	}
+}
+
+func TestDeleteClearsZoneFilters(t *testing.T) {
+	fixture := consulutil.NewFixture(t)
+	defer fixture.Stop()
+
+	rcs := rcstore.NewConsul(fixture.Client, labels.NewConsulApplicator(fixture.Client, 0, 0), 0)
+	oldRC, err := rcs.Create(testManifest(), testNodeSelector(), "some_az", "some_cn", podLabels(), nil, "some_strategy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newRC, err := rcs.Create(testManifest(), testNodeSelector(), "some_az", "some_cn", podLabels(), nil, "some_strategy")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the update was deleted while it was rolling the first zone
+	zones := &rc_fields.ZoneFilter{Label: "availability_zone", Values: []string{"zone_a"}}
+	txn, cancelFunc := transaction.New(context.Background())
+	defer cancelFunc()
+	err = rcs.SetZoneFiltersTxn(txn, newRC.ID, zones, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = rcs.SetZoneFiltersTxn(txn, oldRC.ID, nil, zones)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = transaction.MustCommit(txn, fixture.Client.KV())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	update := fields.Update{
+		OldRC:        oldRC.ID,
+		NewRC:        newRC.ID,
+		ZoneSequence: &fields.ZoneSequence{Label: "availability_zone"},
+	}
+	rollstore, _ := newRollStoreWithRealConsul(t, fixture, []fields.Update{update})
+
+	txn, cancelFunc = transaction.New(context.Background())
+	defer cancelFunc()
+	err = rollstore.Delete(txn, update.ID())
+	if err != nil {
+		t.Fatalf("Unexpected error deleting update: %s", err)
+	}
+	err = transaction.MustCommit(txn, fixture.Client.KV())
+	if err != nil {
+		t.Fatalf("Unexpected error committing deletion: %s", err)
+	}
+
+	for _, rcID := range []rc_fields.ID{oldRC.ID, newRC.ID} {
+		rc, err := rcs.Get(rcID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rc.ScheduleZones != nil || rc.UnscheduleZones != nil {
+			t.Errorf("Expected the zone filters of RC %s to be cleared, got %+v and %+v", rcID, rc.ScheduleZones, rc.UnscheduleZones)
+		}
+	}
+}
+
 func newRollStoreWithRealConsul(t *testing.T, fixture consulutil.Fixture, entries []fields.Update) (*ConsulStore, testRCStore) {
 	for _, u := range entries {
 		path, err := RollPath(fields.ID(u.NewRC))
@@ -533,10 +801,11 @@ func newRollStoreWithRealConsul(t *testing.T, fixture consulutil.Fixture, entrie
 	applicator := labels.NewConsulApplicator(fixture.Client, 0, 0)
 	rcStore := rcstore.NewConsul(fixture.Client, applicator, 0)
 	return &ConsulStore{
-		kv:      fixture.Client.KV(),
-		store:   consul.NewConsulStore(fixture.Client),
-		rcstore: rcStore,
-		labeler: applicator,
+		kv:          fixture.Client.KV(),
+		store:       consul.NewConsulStore(fixture.Client),
+		rcstore:     rcStore,
+		labeler:     applicator,
+		statusStore: rollstatus.NewConsul(statusstore.NewConsul(fixture.Client), consul.RollStatusNamespace),
 	}, rcStore
 }
 
@@ -0,0 +1,23 @@
+package autoscalestatus
+
+import "time"
+
+// Status records the autoscaler's view of an RC. It is kept apart from the
+// RC farm's status so that the two don't overwrite each other.
+type Status struct {
+	// LastScaleTime is when the autoscaler last changed the RC's replica
+	// count. The RC's cooldown is measured from it.
+	LastScaleTime *time.Time `json:"last_scale_time,omitempty"`
+
+	// LastMetricValue is the metric value read by the latest evaluation
+	LastMetricValue float64 `json:"last_metric_value"`
+
+	// RecommendedReplicas is the replica count the latest evaluation
+	// arrived at, before the cooldown was taken into account
+	RecommendedReplicas int `json:"recommended_replicas"`
+
+	// LastError is why the latest evaluation failed, if it did
+	LastError string `json:"last_error,omitempty"`
+
+	LastEvaluationTime time.Time `json:"last_evaluation_time"`
+}
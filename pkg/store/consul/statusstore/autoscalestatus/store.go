@@ -0,0 +1,61 @@
+package autoscalestatus
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/square/p2/pkg/rc/fields"
+	"github.com/square/p2/pkg/store/consul/statusstore"
+	"github.com/square/p2/pkg/util"
+
+	"github.com/hashicorp/consul/api"
+)
+
+type ConsulStore struct {
+	statusStore statusstore.Store
+
+	// The consul implementation statusstore.Store formats keys like
+	// /status/<resource-type>/<resource-id>/<namespace>. The namespace
+	// portion is useful if multiple subsystems need to record their
+	// own view of a resource.
+	namespace statusstore.Namespace
+}
+
+func NewConsul(statusStore statusstore.Store, namespace statusstore.Namespace) ConsulStore {
+	return ConsulStore{
+		statusStore: statusStore,
+		namespace:   namespace,
+	}
+}
+
+func (c ConsulStore) Get(rcID fields.ID) (Status, *api.QueryMeta, error) {
+	if rcID == "" {
+		return Status{}, nil, util.Errorf("Provided replication controller ID was empty")
+	}
+
+	rawStatus, queryMeta, err := c.statusStore.GetStatus(statusstore.RC, statusstore.ResourceID(rcID), c.namespace)
+	if err != nil {
+		return Status{}, queryMeta, err
+	}
+
+	var status Status
+	err = json.Unmarshal(rawStatus.Bytes(), &status)
+	if err != nil {
+		return Status{}, queryMeta, util.Errorf("Could not unmarshal raw status as autoscale status: %s", err)
+	}
+
+	return status, queryMeta, nil
+}
+
+func (c ConsulStore) SetTxn(ctx context.Context, rcID fields.ID, status Status) error {
+	if rcID == "" {
+		return util.Errorf("Provided replication controller ID was empty")
+	}
+
+	bytes, err := json.Marshal(status)
+	if err != nil {
+		return util.Errorf("Could not marshal autoscale status as json bytes: %s", err)
+	}
+
+	return c.statusStore.SetTxn(ctx, statusstore.RC, statusstore.ResourceID(rcID), c.namespace, statusstore.Status(bytes))
+}
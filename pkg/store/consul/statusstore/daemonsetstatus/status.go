@@ -1,5 +1,12 @@
 package daemonsetstatus
 
+import (
+	"time"
+
+	dsfields "github.com/square/p2/pkg/ds/fields"
+	"github.com/square/p2/pkg/types"
+)
+
 type Status struct {
 	// ManifestSHA is the sha of the manifest that was most recently
 	// deployed
@@ -12,4 +19,82 @@ type Status struct {
 	NodesDeployed int `json:"nodes_deployed"`
 
 	ReplicationInProgress bool `json:"replication_in_progress"`
+
+	// Rolling is the progress of a daemon set with a rolling strategy
+	// through its batches. It is the zero value for other daemon sets.
+	Rolling RollingStatus `json:"rolling"`
+
+	// FailureBudgetExceeded is set when more nodes failed to become
+	// healthy within the timeout than the daemon set's failure threshold
+	// allows, which halts and disables the daemon set
+	FailureBudgetExceeded bool `json:"failure_budget_exceeded,omitempty"`
+
+	// FailedNodes are the nodes that failed to become healthy within the
+	// timeout for the current manifest, or those that did when the failure
+	// budget was exceeded
+	FailedNodes []types.NodeName `json:"failed_nodes,omitempty"`
+
+	// Nodes breaks down the daemon set's nodes by how far along they are.
+	// It is sampled less often than the rest of the status because it
+	// requires reading each node's reality
+	Nodes NodeStatus `json:"nodes"`
+
+	// ContendsWith is the ID of another daemon set with the same pod ID
+	// whose node selector overlaps with this one's, if there is one
+	ContendsWith dsfields.ID `json:"contends_with,omitempty"`
+}
+
+// NodeStatus counts the daemon set's nodes in each stage of being deployed
+type NodeStatus struct {
+	// Eligible is the number of nodes selected by the daemon set's node
+	// selector
+	Eligible int `json:"eligible"`
+
+	// Scheduled is the number of nodes the daemon set has scheduled its
+	// pod on
+	Scheduled int `json:"scheduled"`
+
+	// Current is the number of scheduled nodes running ManifestSHA, or
+	// the daemon set's override of it for the node
+	Current int `json:"current"`
+
+	// Healthy and Unhealthy are the number of scheduled nodes whose pod is
+	// passing its health check, and whose pod is not. Nodes without a
+	// health result are in neither.
+	Healthy   int `json:"healthy"`
+	Unhealthy int `json:"unhealthy"`
+
+	// Pending is the number of eligible nodes that aren't current yet and
+	// haven't failed
+	Pending int `json:"pending"`
+
+	// OldSHA are the scheduled nodes running a manifest other than the one
+	// they should be
+	OldSHA []types.NodeName `json:"old_sha,omitempty"`
+}
+
+// RollingStatus describes where a daemon set with a rolling strategy is in
+// rolling out its nodes
+type RollingStatus struct {
+	// BatchesCompleted is the number of batches finished since the
+	// daemon set's farm started handling it
+	BatchesCompleted int `json:"batches_completed"`
+
+	// BatchSize is the number of nodes in the batch in progress, or zero
+	// between batches
+	BatchSize int `json:"batch_size"`
+
+	// NodesRemaining is the number of nodes that are waiting for a batch
+	NodesRemaining int `json:"nodes_remaining"`
+
+	// Unavailable is the number of the daemon set's nodes whose pod was
+	// unhealthy when the last batch was sized
+	Unavailable int `json:"unavailable"`
+
+	// BlockingReason is what the rollout is waiting on between batches,
+	// e.g. the batch delay or unhealthy nodes
+	BlockingReason string `json:"blocking_reason,omitempty"`
+
+	// LastBatchTime is when the last batch finished
+	LastBatchTime time.Time `json:"last_batch_time,omitempty"`
 }
@@ -46,6 +46,26 @@ func (c ConsulStore) Get(dsID dsfields.ID) (Status, *api.QueryMeta, error) {
 	return dsStatus, queryMeta, nil
 }
 
+// Watch returns the daemon set's status once it has changed since waitIndex,
+// which may be taken from the QueryMeta of a previous Get or Watch
+func (c ConsulStore) Watch(dsID dsfields.ID, waitIndex uint64) (Status, *api.QueryMeta, error) {
+	if dsID == "" {
+		return Status{}, nil, util.Errorf("provided daemon set ID was empty")
+	}
+
+	status, queryMeta, err := c.statusStore.WatchStatus(statusstore.DS, statusstore.ResourceID(dsID), c.namespace, waitIndex)
+	if err != nil {
+		return Status{}, queryMeta, err
+	}
+
+	dsStatus, err := statusToDSStatus(status)
+	if err != nil {
+		return Status{}, queryMeta, err
+	}
+
+	return dsStatus, queryMeta, nil
+}
+
 func (c ConsulStore) CASTxn(ctx context.Context, dsID dsfields.ID, modifyIndex uint64, status Status) error {
 	rawStatus, err := dsStatusToStatus(status)
 	if err != nil {
@@ -2,6 +2,7 @@ package rcstatus
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/square/p2/pkg/store/consul/statusstore"
 	"github.com/square/p2/pkg/types"
@@ -10,6 +11,115 @@ import (
 
 type Status struct {
 	NodeTransfer *NodeTransfer `json:"node_transfer"`
+
+	// SpreadViolations lists the RC's spread constraints that its current
+	// pods do not satisfy
+	SpreadViolations []SpreadViolation `json:"spread_violations,omitempty"`
+
+	// The fields below are refreshed each time the RC tries to meet its
+	// desires
+
+	ReplicasDesired int `json:"replicas_desired"`
+	// ReplicasScheduled counts the pods in the intent tree
+	ReplicasScheduled int `json:"replicas_scheduled"`
+	// ReplicasCurrent counts the scheduled pods whose installed manifest
+	// matches the RC's
+	ReplicasCurrent   int `json:"replicas_current"`
+	ReplicasHealthy   int `json:"replicas_healthy"`
+	ReplicasUnhealthy int `json:"replicas_unhealthy"`
+
+	// IneligibleNodes are scheduled nodes the RC's selector no longer
+	// matches
+	IneligibleNodes []types.NodeName `json:"ineligible_nodes,omitempty"`
+
+	Conditions []Condition `json:"conditions,omitempty"`
+
+	// LastError is why the RC last failed to meet its desires. It is
+	// cleared by the next successful attempt.
+	LastError     string     `json:"last_error,omitempty"`
+	LastErrorTime *time.Time `json:"last_error_time,omitempty"`
+
+	// LastUpdateTime is when the status was last written. The RC only
+	// rewrites an unchanged status every few minutes, so LastErrorTime may
+	// be older than the most recent failure.
+	LastUpdateTime time.Time `json:"last_update_time"`
+
+	// MissingArtifacts is refreshed by the RC's periodic check for
+	// artifacts that are not in the registry
+	MissingArtifacts          []MissingArtifact `json:"missing_artifacts,omitempty"`
+	MissingArtifactsCheckTime *time.Time        `json:"missing_artifacts_check_time,omitempty"`
+}
+
+type ConditionType string
+
+const (
+	// ReplicasMet is true when as many pods are scheduled as desired
+	ReplicasMet ConditionType = "replicas_met"
+
+	// Healthy is true when every desired replica is scheduled and healthy
+	Healthy ConditionType = "healthy"
+
+	// ArtifactsAvailable is false when the last check found artifacts
+	// missing from the registry
+	ArtifactsAvailable ConditionType = "artifacts_available"
+)
+
+// Condition is an aspect of the RC's state that is either true or not, along
+// with when that last changed
+type Condition struct {
+	Type               ConditionType `json:"type"`
+	Status             bool          `json:"status"`
+	Message            string        `json:"message,omitempty"`
+	LastTransitionTime time.Time     `json:"last_transition_time"`
+}
+
+// SetCondition records a condition, keeping its transition time if its
+// status has not changed
+func (s *Status) SetCondition(conditionType ConditionType, status bool, message string, now time.Time) {
+	for i, condition := range s.Conditions {
+		if condition.Type != conditionType {
+			continue
+		}
+		if condition.Status != status {
+			s.Conditions[i].LastTransitionTime = now
+		}
+		s.Conditions[i].Status = status
+		s.Conditions[i].Message = message
+		return
+	}
+	s.Conditions = append(s.Conditions, Condition{
+		Type:               conditionType,
+		Status:             status,
+		Message:            message,
+		LastTransitionTime: now,
+	})
+}
+
+// Condition returns the condition of the given type, if it has been set
+func (s Status) Condition(conditionType ConditionType) (Condition, bool) {
+	for _, condition := range s.Conditions {
+		if condition.Type == conditionType {
+			return condition, true
+		}
+	}
+	return Condition{}, false
+}
+
+// MissingArtifact is a launchable whose artifact could not be found in the
+// registry
+type MissingArtifact struct {
+	LaunchableID string `json:"launchable_id"`
+	URL          string `json:"url"`
+}
+
+// SpreadViolation records a spread constraint whose max skew is exceeded
+type SpreadViolation struct {
+	TopologyKey string `json:"topology_key"`
+	MaxSkew     int    `json:"max_skew"`
+	Skew        int    `json:"skew"`
+
+	// The number of pods in each domain, keyed by node label value
+	PodsPerDomain map[string]int `json:"pods_per_domain"`
 }
 
 type NodeTransferID string
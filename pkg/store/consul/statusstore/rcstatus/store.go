@@ -45,6 +45,26 @@ func (c ConsulStore) Get(rcID fields.ID) (Status, *api.QueryMeta, error) {
 	return status, queryMeta, nil
 }
 
+// Watch returns the RC's status once it has changed since waitIndex, which
+// may be taken from the QueryMeta of a previous Get or Watch
+func (c ConsulStore) Watch(rcID fields.ID, waitIndex uint64) (Status, *api.QueryMeta, error) {
+	if rcID == "" {
+		return Status{}, nil, util.Errorf("Provided replication controller ID was empty")
+	}
+
+	rawStatus, queryMeta, err := c.statusStore.WatchStatus(statusstore.RC, statusstore.ResourceID(rcID), c.namespace, waitIndex)
+	if err != nil {
+		return Status{}, queryMeta, err
+	}
+
+	status, err := rawStatusToStatus(rawStatus)
+	if err != nil {
+		return Status{}, queryMeta, err
+	}
+
+	return status, queryMeta, nil
+}
+
 func (c ConsulStore) Set(rcID fields.ID, status Status) error {
 	if rcID == "" {
 		return util.Errorf("Provided replication controller ID was empty")
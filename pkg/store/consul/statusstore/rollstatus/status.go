@@ -0,0 +1,55 @@
+package rollstatus
+
+import "time"
+
+// Step is the stage a rolling update is in
+type Step string
+
+const (
+	// the update is moving replicas to the new RC
+	StepRolling Step = "rolling"
+	// the update can't move replicas right now, see BlockingReason
+	StepBlocked Step = "blocked"
+	// the update's canaries are healthy and it is holding them for the
+	// canary bake time
+	StepBakingCanaries Step = "baking_canaries"
+	// the update was paused by an operator
+	StepPaused Step = "paused"
+)
+
+// Counts are the replica counts of one of the RCs of a rolling update
+type Counts struct {
+	// the number of replicas the RC wants
+	Desired int `json:"desired"`
+	// the number of nodes the RC has scheduled itself on
+	Current int `json:"current"`
+	// the number of current and non-ineligible nodes that have finished
+	// scheduling
+	Real int `json:"real"`
+	// the number of real nodes that are healthy
+	Healthy int `json:"healthy"`
+	// the number of real nodes that are unhealthy
+	Unhealthy int `json:"unhealthy"`
+	// the number of real nodes that are of unknown health
+	Unknown int `json:"unknown"`
+}
+
+// Status is the roll farm's view of a rolling update in progress. It is
+// written as the update moves along and deleted when the update is.
+type Status struct {
+	OldRC Counts `json:"old_rc"`
+	NewRC Counts `json:"new_rc"`
+
+	Step Step `json:"step"`
+
+	// BlockingReason is what the update is waiting on when it isn't moving
+	// replicas, e.g. the minimum health or the roll delay
+	BlockingReason string `json:"blocking_reason,omitempty"`
+
+	// StartTime is when the farm currently running the update started it
+	StartTime time.Time `json:"start_time"`
+
+	// LastProgressTime is when a replica last moved to the new RC or
+	// became healthy on it
+	LastProgressTime time.Time `json:"last_progress_time"`
+}
@@ -0,0 +1,107 @@
+package rollstatus
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/square/p2/pkg/roll/fields"
+	"github.com/square/p2/pkg/store/consul/statusstore"
+	"github.com/square/p2/pkg/util"
+
+	"github.com/hashicorp/consul/api"
+)
+
+type ConsulStore struct {
+	statusStore statusstore.Store
+
+	// The consul implementation statusstore.Store formats keys like
+	// /status/<resource-type>/<resource-id>/<namespace>. The namespace
+	// portion is useful if multiple subsystems need to record their
+	// own view of a resource.
+	namespace statusstore.Namespace
+}
+
+func NewConsul(statusStore statusstore.Store, namespace statusstore.Namespace) ConsulStore {
+	return ConsulStore{
+		statusStore: statusStore,
+		namespace:   namespace,
+	}
+}
+
+func (c ConsulStore) Get(id fields.ID) (Status, *api.QueryMeta, error) {
+	if id == "" {
+		return Status{}, nil, util.Errorf("Provided rolling update ID was empty")
+	}
+
+	rawStatus, queryMeta, err := c.statusStore.GetStatus(statusstore.RU, statusstore.ResourceID(id), c.namespace)
+	if err != nil {
+		return Status{}, queryMeta, err
+	}
+
+	status, err := rawStatusToStatus(rawStatus)
+	if err != nil {
+		return Status{}, queryMeta, err
+	}
+
+	return status, queryMeta, nil
+}
+
+// Watch returns the rolling update's status once it has changed since
+// waitIndex, which may be taken from the QueryMeta of a previous Get or Watch
+func (c ConsulStore) Watch(id fields.ID, waitIndex uint64) (Status, *api.QueryMeta, error) {
+	if id == "" {
+		return Status{}, nil, util.Errorf("Provided rolling update ID was empty")
+	}
+
+	rawStatus, queryMeta, err := c.statusStore.WatchStatus(statusstore.RU, statusstore.ResourceID(id), c.namespace, waitIndex)
+	if err != nil {
+		return Status{}, queryMeta, err
+	}
+
+	status, err := rawStatusToStatus(rawStatus)
+	if err != nil {
+		return Status{}, queryMeta, err
+	}
+
+	return status, queryMeta, nil
+}
+
+func (c ConsulStore) Set(id fields.ID, status Status) error {
+	if id == "" {
+		return util.Errorf("Provided rolling update ID was empty")
+	}
+
+	rawStatus, err := statusToRawStatus(status)
+	if err != nil {
+		return err
+	}
+
+	return c.statusStore.SetStatus(statusstore.RU, statusstore.ResourceID(id), c.namespace, rawStatus)
+}
+
+func (c ConsulStore) DeleteTxn(ctx context.Context, id fields.ID) error {
+	if id == "" {
+		return util.Errorf("Provided rolling update ID was empty")
+	}
+
+	return c.statusStore.DeleteStatusTxn(ctx, statusstore.RU, statusstore.ResourceID(id), c.namespace)
+}
+
+func rawStatusToStatus(rawStatus statusstore.Status) (Status, error) {
+	var status Status
+	err := json.Unmarshal(rawStatus.Bytes(), &status)
+	if err != nil {
+		return Status{}, util.Errorf("Could not unmarshal raw status as rolling update status: %s", err)
+	}
+
+	return status, nil
+}
+
+func statusToRawStatus(status Status) (statusstore.Status, error) {
+	bytes, err := json.Marshal(status)
+	if err != nil {
+		return nil, util.Errorf("Could not marshal rolling update status as json bytes: %s", err)
+	}
+
+	return statusstore.Status(bytes), nil
+}
@@ -0,0 +1,68 @@
+package rollstatus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/square/p2/pkg/roll/fields"
+	"github.com/square/p2/pkg/store/consul/consulutil"
+	"github.com/square/p2/pkg/store/consul/statusstore"
+	"github.com/square/p2/pkg/store/consul/transaction"
+)
+
+func TestSetGetAndDeleteStatus(t *testing.T) {
+	fixture := consulutil.NewFixture(t)
+	defer fixture.Stop()
+	store := NewConsul(statusstore.NewConsul(fixture.Client), "test")
+	id := fields.ID("ru_id")
+
+	_, _, err := store.Get(id)
+	if !statusstore.IsNoStatus(err) {
+		t.Fatalf("Expected no status error, got: %s", err)
+	}
+
+	start := time.Now().UTC().Truncate(time.Second)
+	status := Status{
+		OldRC:            Counts{Desired: 2, Healthy: 2},
+		NewRC:            Counts{Desired: 1, Healthy: 1},
+		Step:             StepBlocked,
+		BlockingReason:   "waiting for the roll delay",
+		StartTime:        start,
+		LastProgressTime: start,
+	}
+	err = store.Set(id, status)
+	if err != nil {
+		t.Fatalf("Unexpected error setting status: %s", err)
+	}
+
+	stored, _, err := store.Get(id)
+	if err != nil {
+		t.Fatalf("Unexpected error getting status: %s", err)
+	}
+	if stored.OldRC != status.OldRC || stored.NewRC != status.NewRC {
+		t.Errorf("Expected counts %+v and %+v but got %+v and %+v", status.OldRC, status.NewRC, stored.OldRC, stored.NewRC)
+	}
+	if stored.Step != status.Step || stored.BlockingReason != status.BlockingReason {
+		t.Errorf("Expected step %q (%q) but got %q (%q)", status.Step, status.BlockingReason, stored.Step, stored.BlockingReason)
+	}
+	if !stored.StartTime.Equal(start) || !stored.LastProgressTime.Equal(start) {
+		t.Errorf("Expected times to be %s but were %s and %s", start, stored.StartTime, stored.LastProgressTime)
+	}
+
+	ctx, cancel := transaction.New(context.Background())
+	defer cancel()
+	err = store.DeleteTxn(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = transaction.MustCommit(ctx, fixture.Client.KV())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, err = store.Get(id)
+	if !statusstore.IsNoStatus(err) {
+		t.Fatalf("Expected no status error after deleting, got: %s", err)
+	}
+}
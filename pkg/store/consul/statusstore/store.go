@@ -21,6 +21,7 @@ const (
 	POD = ResourceType("pods")
 	DS  = ResourceType("daemon_sets")
 	RC  = ResourceType("replication_controllers")
+	RU  = ResourceType("rolling_updates")
 )
 
 // Unfortunately each ResourceType will carry along with it a different "ID"
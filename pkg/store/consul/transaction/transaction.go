@@ -19,8 +19,9 @@ import (
 // collisions in the map.
 type contextKeyType struct{}
 
-// Per https://www.consul.io/api/txn.html
-const maxAllowedOperations = 64
+// MaxOperations is the most operations a single transaction may contain, per
+// https://www.consul.io/api/txn.html
+const MaxOperations = 64
 
 var (
 	ErrTooManyOperations = errors.New("consul transactions cannot have more than 64 operations")
@@ -65,7 +66,7 @@ func Add(ctx context.Context, op api.KVTxnOp) error {
 		return util.Errorf("transaction was already committed")
 	}
 
-	if len(*txn.kvOps) == maxAllowedOperations {
+	if len(*txn.kvOps) == MaxOperations {
 		return ErrTooManyOperations
 	}
 	*txn.kvOps = append(*txn.kvOps, &op)
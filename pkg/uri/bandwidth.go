@@ -0,0 +1,77 @@
+package uri
+
+import (
+	"sync"
+	"time"
+)
+
+// bandwidthLimiter throttles reads shared by any number of concurrent
+// downloads. It hands out byte-sized tokens that accrue at the configured
+// rate, holding at most one second of transfer. Tokens are computed from the
+// time elapsed since the last refill rather than refilled one at a time, so
+// rates finer than a byte per nanosecond are honored exactly.
+type bandwidthLimiter struct {
+	mux            sync.Mutex
+	bytesPerSecond int64
+	available      int64
+	refilled       time.Time
+}
+
+func newBandwidthLimiter(bytesPerSecond int64) *bandwidthLimiter {
+	return &bandwidthLimiter{
+		bytesPerSecond: bytesPerSecond,
+		available:      bytesPerSecond,
+		refilled:       time.Now(),
+	}
+}
+
+// refill adds the tokens earned since the last refill. The time accounted
+// for only advances by the tokens actually added, so fractions of a byte are
+// carried over to the next refill instead of being lost. b.mux must be held.
+func (b *bandwidthLimiter) refill(now time.Time) {
+	elapsed := now.Sub(b.refilled)
+	if elapsed >= time.Second {
+		b.available = b.bytesPerSecond
+		b.refilled = now
+		return
+	}
+	earned := int64(elapsed.Seconds() * float64(b.bytesPerSecond))
+	if earned <= 0 {
+		return
+	}
+	b.available += earned
+	b.refilled = b.refilled.Add(b.durationOf(earned))
+	if b.available >= b.bytesPerSecond {
+		b.available = b.bytesPerSecond
+		b.refilled = now
+	}
+}
+
+// durationOf returns how long it takes to earn n tokens
+func (b *bandwidthLimiter) durationOf(n int64) time.Duration {
+	return time.Duration(float64(n) / float64(b.bytesPerSecond) * float64(time.Second))
+}
+
+// wait blocks until at least some of p may be read, and returns the prefix
+// of p that the caller is allowed to fill.
+func (b *bandwidthLimiter) wait(p []byte) []byte {
+	want := int64(len(p))
+	if want > b.bytesPerSecond {
+		want = b.bytesPerSecond
+	}
+	for {
+		b.mux.Lock()
+		b.refill(time.Now())
+		if b.available > 0 {
+			// Take what is available rather than waiting for the full amount
+			if want > b.available {
+				want = b.available
+			}
+			b.available -= want
+			b.mux.Unlock()
+			return p[:want]
+		}
+		b.mux.Unlock()
+		time.Sleep(b.durationOf(want))
+	}
+}
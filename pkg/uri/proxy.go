@@ -0,0 +1,156 @@
+package uri
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/square/p2/pkg/logging"
+	"github.com/square/p2/pkg/util"
+)
+
+// ProxyFetchPath is the path on a caching proxy that serves origin URIs. The
+// origin is passed in the ProxyURLParam query parameter.
+const (
+	ProxyFetchPath = "/fetch"
+	ProxyURLParam  = "url"
+)
+
+// A ProxyLocator returns the base URLs of the caching proxies that may be
+// used to fetch remote URIs.
+type ProxyLocator interface {
+	Proxies() ([]*url.URL, error)
+}
+
+// StaticProxies is a ProxyLocator for a fixed list of proxies.
+type StaticProxies []*url.URL
+
+func (s StaticProxies) Proxies() ([]*url.URL, error) {
+	return s, nil
+}
+
+// An OriginFetcher is a Fetcher that may serve data from a copy, such as a
+// cache, rather than from its origin. Origin returns a Fetcher that always
+// goes to the origin, for callers that find the copy they were served fails
+// verification.
+type OriginFetcher interface {
+	Fetcher
+	Origin() Fetcher
+}
+
+// ProxyFetcher fetches remote URIs through a pull-through caching proxy when
+// one is available, and falls back to fetching from the origin if every
+// proxy fails. Proxies are tried in an order determined by the URI being
+// fetched, so that every node asks the same proxy for the same artifact and
+// only one copy of it is cached.
+//
+// Data served by a proxy is not trusted any more than data served by the
+// origin: callers are expected to verify artifacts as usual, and to fetch
+// them again from Origin() if a proxied copy fails verification.
+type ProxyFetcher struct {
+	fetcher Fetcher
+	locator ProxyLocator
+	logger  logging.Logger
+}
+
+var _ OriginFetcher = &ProxyFetcher{}
+
+// NewProxyFetcher wraps a fetcher. The wrapped fetcher is used both to talk
+// to the proxies and to reach the origin.
+func NewProxyFetcher(fetcher Fetcher, locator ProxyLocator, logger logging.Logger) *ProxyFetcher {
+	if fetcher == nil {
+		fetcher = DefaultFetcher
+	}
+	return &ProxyFetcher{
+		fetcher: fetcher,
+		locator: locator,
+		logger:  logger,
+	}
+}
+
+func (f *ProxyFetcher) Open(u *url.URL) (io.ReadCloser, error) {
+	for _, proxied := range f.proxiedURLs(u) {
+		reader, err := f.fetcher.Open(proxied)
+		if err == nil {
+			return reader, nil
+		}
+		f.logger.WithError(err).WithFields(logrus.Fields{
+			"url":   u.String(),
+			"proxy": proxied.Host,
+		}).Warnln("Could not fetch through artifact proxy")
+	}
+	return f.fetcher.Open(u)
+}
+
+func (f *ProxyFetcher) Head(u *url.URL) (*http.Response, error) {
+	for _, proxied := range f.proxiedURLs(u) {
+		resp, err := f.fetcher.Head(proxied)
+		if err == nil && resp.StatusCode < http.StatusInternalServerError {
+			return resp, nil
+		}
+		if err == nil {
+			_ = resp.Body.Close()
+			err = util.Errorf("proxy returned status: %s", resp.Status)
+		}
+		f.logger.WithError(err).WithFields(logrus.Fields{
+			"url":   u.String(),
+			"proxy": proxied.Host,
+		}).Warnln("Could not reach artifact proxy")
+	}
+	return f.fetcher.Head(u)
+}
+
+func (f *ProxyFetcher) CopyLocal(srcUri *url.URL, dstPath string) error {
+	return copyLocal(f, srcUri, dstPath)
+}
+
+// Origin returns the wrapped fetcher, which bypasses the proxies.
+func (f *ProxyFetcher) Origin() Fetcher {
+	return f.fetcher
+}
+
+// proxiedURLs returns the URLs at which each proxy serves u, in the order
+// they should be tried. Local files are never proxied.
+func (f *ProxyFetcher) proxiedURLs(u *url.URL) []*url.URL {
+	switch u.Scheme {
+	case "http", "https", S3Scheme:
+	default:
+		return nil
+	}
+
+	proxies, err := f.locator.Proxies()
+	if err != nil {
+		f.logger.WithError(err).Warnln("Could not locate artifact proxies")
+		return nil
+	}
+
+	origin := u.String()
+	ranked := make([]*url.URL, len(proxies))
+	copy(ranked, proxies)
+	// Rendezvous hashing: each (proxy, origin) pair gets a score and the
+	// highest score wins, so adding or removing a proxy only moves the
+	// artifacts that proxy was responsible for
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return proxyScore(ranked[i], origin) > proxyScore(ranked[j], origin)
+	})
+
+	proxied := make([]*url.URL, 0, len(ranked))
+	for _, proxy := range ranked {
+		p := *proxy
+		p.Path = strings.TrimSuffix(p.Path, "/") + ProxyFetchPath
+		p.RawQuery = url.Values{ProxyURLParam: []string{origin}}.Encode()
+		proxied = append(proxied, &p)
+	}
+	return proxied
+}
+
+func proxyScore(proxy *url.URL, origin string) uint64 {
+	sum := sha256.Sum256([]byte(proxy.String() + "\x00" + origin))
+	return binary.BigEndian.Uint64(sum[:8])
+}
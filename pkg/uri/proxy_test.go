@@ -0,0 +1,99 @@
+package uri
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/square/p2/pkg/logging"
+)
+
+func TestProxyFetcherPrefersProxy(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != ProxyFetchPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		proxied = append(proxied, r.URL.Query().Get(ProxyURLParam))
+		_, _ = w.Write([]byte("from proxy"))
+	}))
+	defer proxy.Close()
+
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("from origin"))
+	}))
+	defer origin.Close()
+
+	proxyURL, _ := url.Parse(proxy.URL)
+	fetcher := NewProxyFetcher(nil, StaticProxies{proxyURL}, logging.TestLogger())
+
+	artifactURL, _ := url.Parse(origin.URL + "/myapp_abc123.tar.gz")
+	reader, err := fetcher.Open(artifactURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contents, _ := ioutil.ReadAll(reader)
+	_ = reader.Close()
+	if string(contents) != "from proxy" {
+		t.Errorf("Expected artifact to be served by the proxy, got %q", string(contents))
+	}
+	if len(proxied) != 1 || proxied[0] != artifactURL.String() {
+		t.Errorf("Expected proxy to be asked for %s, got %v", artifactURL, proxied)
+	}
+}
+
+func TestProxyFetcherFallsBackToOrigin(t *testing.T) {
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer proxy.Close()
+
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("from origin"))
+	}))
+	defer origin.Close()
+
+	badProxy, _ := url.Parse(proxy.URL)
+	deadProxy, _ := url.Parse("http://127.0.0.1:1")
+	fetcher := NewProxyFetcher(nil, StaticProxies{badProxy, deadProxy}, logging.TestLogger())
+
+	artifactURL, _ := url.Parse(origin.URL + "/myapp_abc123.tar.gz")
+	reader, err := fetcher.Open(artifactURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contents, _ := ioutil.ReadAll(reader)
+	_ = reader.Close()
+	if string(contents) != "from origin" {
+		t.Errorf("Expected artifact to be served by the origin, got %q", string(contents))
+	}
+}
+
+func TestProxyFetcherOrderIsStablePerURI(t *testing.T) {
+	var proxies StaticProxies
+	for _, host := range []string{"proxy1:8181", "proxy2:8181", "proxy3:8181"} {
+		proxies = append(proxies, &url.URL{Scheme: "http", Host: host})
+	}
+	fetcher := NewProxyFetcher(nil, proxies, logging.TestLogger())
+	reversed := NewProxyFetcher(nil, StaticProxies{proxies[2], proxies[1], proxies[0]}, logging.TestLogger())
+
+	artifactURL, _ := url.Parse("https://artifacts.example.com/myapp_abc123.tar.gz")
+	first := fetcher.proxiedURLs(artifactURL)
+	second := reversed.proxiedURLs(artifactURL)
+	if len(first) != 3 || len(second) != 3 {
+		t.Fatalf("Expected every proxy to be tried, got %v and %v", first, second)
+	}
+	for i := range first {
+		if first[i].String() != second[i].String() {
+			t.Errorf("Expected proxy order not to depend on configuration order: %v vs %v", first, second)
+		}
+	}
+
+	local, _ := url.Parse("file:///tmp/myapp_abc123.tar.gz")
+	if proxied := fetcher.proxiedURLs(local); len(proxied) != 0 {
+		t.Errorf("Expected local files not to be proxied, got %v", proxied)
+	}
+}
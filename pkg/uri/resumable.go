@@ -0,0 +1,385 @@
+package uri
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+	"github.com/sirupsen/logrus"
+
+	"github.com/square/p2/pkg/logging"
+	"github.com/square/p2/pkg/util"
+)
+
+const (
+	// DefaultRetries is the number of times a ResumableFetcher will retry a
+	// failed request if none is configured
+	DefaultRetries = 5
+
+	// DefaultInitialBackoff is the amount of time a ResumableFetcher waits
+	// before its first retry. The wait doubles on each subsequent retry up to
+	// DefaultMaxBackoff
+	DefaultInitialBackoff = 1 * time.Second
+	DefaultMaxBackoff     = 1 * time.Minute
+
+	// DefaultProgressInterval is how often download progress is logged
+	DefaultProgressInterval = 30 * time.Second
+)
+
+// ResumableFetcherOptions configures a ResumableFetcher. Zero values are
+// replaced by the defaults above.
+type ResumableFetcherOptions struct {
+	// Retries is the number of times a request will be reattempted after a
+	// failure. Negative values disable retries.
+	Retries int
+
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	// BytesPerSecond caps the combined transfer rate of every download
+	// performed through the fetcher. Zero means unlimited.
+	BytesPerSecond int64
+
+	ProgressInterval time.Duration
+
+	Logger logging.Logger
+
+	// MetricsRegistry receives download progress metrics. If nil, no
+	// metrics are recorded.
+	MetricsRegistry metrics.Registry
+}
+
+// ResumableFetcher is a Fetcher that behaves like BasicFetcher except that
+// HTTP transfers are retried with exponential backoff and, when interrupted,
+// resumed from the last byte received using HTTP range requests. All
+// transfers share a single bandwidth limit so that deploys don't saturate a
+// node's network link.
+type ResumableFetcher struct {
+	client  *http.Client
+	basic   BasicFetcher
+	opts    ResumableFetcherOptions
+	limiter *bandwidthLimiter
+
+	bytesCounter   metrics.Counter
+	retryCounter   metrics.Counter
+	resumeCounter  metrics.Counter
+	downloadsGauge metrics.Gauge
+	inFlight       int64
+	inFlightMux    sync.Mutex
+}
+
+var _ Fetcher = &ResumableFetcher{}
+
+func NewResumableFetcher(client *http.Client, opts ResumableFetcherOptions) *ResumableFetcher {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if opts.Retries == 0 {
+		opts.Retries = DefaultRetries
+	} else if opts.Retries < 0 {
+		opts.Retries = 0
+	}
+	if opts.InitialBackoff <= 0 {
+		opts.InitialBackoff = DefaultInitialBackoff
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = DefaultMaxBackoff
+	}
+	if opts.ProgressInterval <= 0 {
+		opts.ProgressInterval = DefaultProgressInterval
+	}
+	if opts.Logger.Entry == nil {
+		opts.Logger = logging.DefaultLogger
+	}
+	registry := opts.MetricsRegistry
+	if registry == nil {
+		registry = metrics.NewRegistry()
+	}
+
+	f := &ResumableFetcher{
+		client: client,
+		basic:  BasicFetcher{Client: client},
+		opts:   opts,
+
+		bytesCounter:   metrics.GetOrRegisterCounter("artifact_download_bytes", registry),
+		retryCounter:   metrics.GetOrRegisterCounter("artifact_download_retries", registry),
+		resumeCounter:  metrics.GetOrRegisterCounter("artifact_download_resumes", registry),
+		downloadsGauge: metrics.GetOrRegisterGauge("artifact_downloads_in_flight", registry),
+	}
+	if opts.BytesPerSecond > 0 {
+		f.limiter = newBandwidthLimiter(opts.BytesPerSecond)
+	}
+	return f
+}
+
+// Open returns a stream of the data at the given URI. For HTTP URIs the
+// returned reader transparently resumes the transfer if the connection drops.
+func (f *ResumableFetcher) Open(u *url.URL) (io.ReadCloser, error) {
+	switch u.Scheme {
+	case "http", "https":
+	default:
+		return f.basic.Open(u)
+	}
+	return f.OpenWithRequestHook(u, nil)
+}
+
+// OpenWithRequestHook opens an HTTP URI like Open, but calls prepare on every
+// request it sends, including the range requests that resume an interrupted
+// transfer. This lets callers add headers that depend on the rest of the
+// request, such as a signature covering the Range header.
+func (f *ResumableFetcher) OpenWithRequestHook(u *url.URL, prepare func(*http.Request) error) (io.ReadCloser, error) {
+	r := &resumableReader{
+		fetcher: f,
+		url:     u,
+		prepare: prepare,
+		logger: f.opts.Logger.SubLogger(logrus.Fields{
+			"url": u.String(),
+		}),
+		started:      time.Now(),
+		lastProgress: time.Now(),
+	}
+	err := r.connect()
+	if err != nil {
+		return nil, err
+	}
+	f.trackInFlight(1)
+	return r, nil
+}
+
+// Head issues a HEAD request for the URI, retrying on transport errors and
+// server errors.
+func (f *ResumableFetcher) Head(u *url.URL) (*http.Response, error) {
+	var resp *http.Response
+	err := f.withRetries(u, func() (bool, error) {
+		var err error
+		resp, err = f.client.Head(u.String())
+		if err != nil {
+			return true, err
+		}
+		if resp.StatusCode >= http.StatusInternalServerError {
+			_ = resp.Body.Close()
+			return true, util.Errorf("%q: HTTP server returned status: %s", u.String(), resp.Status)
+		}
+		return false, nil
+	})
+	return resp, err
+}
+
+func (f *ResumableFetcher) CopyLocal(srcUri *url.URL, dstPath string) error {
+	return copyLocal(f, srcUri, dstPath)
+}
+
+// withRetries invokes attempt until it succeeds, returns a non-retryable
+// error or the configured number of retries is exhausted.
+func (f *ResumableFetcher) withRetries(u *url.URL, attempt func() (retryable bool, err error)) error {
+	backoff := f.opts.InitialBackoff
+	for i := 0; ; i++ {
+		retryable, err := attempt()
+		if err == nil {
+			return nil
+		}
+		if !retryable || i >= f.opts.Retries {
+			return err
+		}
+
+		f.retryCounter.Inc(1)
+		f.opts.Logger.WithError(err).WithFields(logrus.Fields{
+			"url":     u.String(),
+			"attempt": i + 1,
+			"backoff": backoff,
+		}).Warnln("Artifact request failed, retrying")
+		time.Sleep(backoff)
+		backoff *= 2
+		if backoff > f.opts.MaxBackoff {
+			backoff = f.opts.MaxBackoff
+		}
+	}
+}
+
+func (f *ResumableFetcher) trackInFlight(delta int64) {
+	f.inFlightMux.Lock()
+	defer f.inFlightMux.Unlock()
+	f.inFlight += delta
+	f.downloadsGauge.Update(f.inFlight)
+}
+
+// resumableReader streams the body of an HTTP response. If reading the body
+// fails partway through, it reissues the request with a Range header starting
+// at the first byte that has not yet been returned.
+type resumableReader struct {
+	fetcher *ResumableFetcher
+	url     *url.URL
+	prepare func(*http.Request) error
+	logger  logging.Logger
+
+	body io.ReadCloser
+
+	// offset is the number of bytes returned to the caller so far
+	offset int64
+	// size is the total size of the resource, or -1 if unknown
+	size int64
+	// validator is the ETag or Last-Modified value of the first response,
+	// sent as If-Range so that a resumed transfer never splices together
+	// two different versions of the resource
+	validator string
+
+	retries      int
+	started      time.Time
+	lastProgress time.Time
+	closed       bool
+}
+
+// connect (re)establishes the response body, starting at r.offset.
+func (r *resumableReader) connect() error {
+	return r.fetcher.withRetries(r.url, func() (bool, error) {
+		req, err := http.NewRequest("GET", r.url.String(), nil)
+		if err != nil {
+			return false, err
+		}
+		if r.offset > 0 {
+			req.Header.Set("Range", fmt.Sprintf("bytes=%d-", r.offset))
+			if r.validator != "" {
+				req.Header.Set("If-Range", r.validator)
+			}
+		}
+		if r.prepare != nil {
+			err = r.prepare(req)
+			if err != nil {
+				return true, err
+			}
+		}
+
+		resp, err := r.fetcher.client.Do(req)
+		if err != nil {
+			return true, err
+		}
+
+		switch {
+		case resp.StatusCode == http.StatusOK && r.offset == 0:
+			r.size = resp.ContentLength
+			r.validator = resp.Header.Get("ETag")
+			if r.validator == "" {
+				r.validator = resp.Header.Get("Last-Modified")
+			}
+		case resp.StatusCode == http.StatusPartialContent && r.offset > 0:
+			start, err := contentRangeStart(resp.Header.Get("Content-Range"))
+			if err != nil || start != r.offset {
+				_ = resp.Body.Close()
+				return false, util.Errorf("%q: server resumed at unexpected position (%q)", r.url.String(), resp.Header.Get("Content-Range"))
+			}
+			r.fetcher.resumeCounter.Inc(1)
+			r.logger.WithField("offset", r.offset).Infoln("Resumed artifact download")
+		case resp.StatusCode == http.StatusOK:
+			// The server ignored the range request. If we sent a validator
+			// this means the resource changed underneath us, otherwise the
+			// server doesn't support ranges and the prefix we already have
+			// has to be skipped.
+			if r.validator != "" {
+				_ = resp.Body.Close()
+				return false, util.Errorf("%q: resource changed while it was being downloaded", r.url.String())
+			}
+			_, err = io.CopyN(ioutil.Discard, resp.Body, r.offset)
+			if err != nil {
+				_ = resp.Body.Close()
+				return true, err
+			}
+		case resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests:
+			_ = resp.Body.Close()
+			return true, util.Errorf("%q: HTTP server returned status: %s", r.url.String(), resp.Status)
+		default:
+			_ = resp.Body.Close()
+			return false, util.Errorf("%q: HTTP server returned status: %s", r.url.String(), resp.Status)
+		}
+
+		r.body = resp.Body
+		return false, nil
+	})
+}
+
+func (r *resumableReader) Read(p []byte) (int, error) {
+	if r.closed {
+		return 0, util.Errorf("%q: read from closed download", r.url.String())
+	}
+	if r.fetcher.limiter != nil {
+		p = r.fetcher.limiter.wait(p)
+	}
+
+	n, err := r.body.Read(p)
+	r.offset += int64(n)
+	r.fetcher.bytesCounter.Inc(int64(n))
+	r.reportProgress()
+
+	if err == nil || (err == io.EOF && (r.size < 0 || r.offset >= r.size)) {
+		if err == io.EOF {
+			r.logger.WithFields(logrus.Fields{
+				"bytes":    r.offset,
+				"duration": time.Since(r.started),
+				"retries":  r.retries,
+			}).Infoln("Finished artifact download")
+		}
+		return n, err
+	}
+
+	// The transfer was cut short. Reconnect from the current offset; the
+	// bytes already read are still returned to the caller.
+	r.logger.WithError(err).WithField("offset", r.offset).Warnln("Artifact download interrupted")
+	_ = r.body.Close()
+	if r.retries >= r.fetcher.opts.Retries {
+		return n, util.Errorf("%q: download failed after %d retries: %s", r.url.String(), r.retries, err)
+	}
+	r.retries++
+	r.fetcher.retryCounter.Inc(1)
+	if connErr := r.connect(); connErr != nil {
+		r.body = eofReader{}
+		return n, connErr
+	}
+	return n, nil
+}
+
+func (r *resumableReader) reportProgress() {
+	if time.Since(r.lastProgress) < r.fetcher.opts.ProgressInterval {
+		return
+	}
+	r.lastProgress = time.Now()
+	r.logger.WithFields(logrus.Fields{
+		"bytes": r.offset,
+		"total": r.size,
+	}).Infoln("Artifact download in progress")
+}
+
+func (r *resumableReader) Close() error {
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+	r.fetcher.trackInFlight(-1)
+	return r.body.Close()
+}
+
+// contentRangeStart parses the first byte position out of a Content-Range
+// header of the form "bytes 100-199/200".
+func contentRangeStart(header string) (int64, error) {
+	var start, end int64
+	var total string
+	_, err := fmt.Sscanf(header, "bytes %d-%d/%s", &start, &end, &total)
+	if err != nil {
+		return 0, err
+	}
+	if total != "*" {
+		if _, err := strconv.ParseInt(total, 10, 64); err != nil {
+			return 0, err
+		}
+	}
+	return start, nil
+}
+
+type eofReader struct{}
+
+func (eofReader) Read([]byte) (int, error) { return 0, io.EOF }
+func (eofReader) Close() error             { return nil }
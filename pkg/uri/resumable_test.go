@@ -0,0 +1,217 @@
+package uri
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+// flakyServer serves content, but cuts off the first response after
+// breakAfter bytes. Subsequent requests are served normally, honoring Range
+// headers.
+type flakyServer struct {
+	content    []byte
+	breakAfter int
+	failures   int
+
+	mux      sync.Mutex
+	requests []*http.Request
+}
+
+func (s *flakyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.Lock()
+	s.requests = append(s.requests, r)
+	requestNum := len(s.requests)
+	s.mux.Unlock()
+
+	if requestNum <= s.failures {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	if requestNum == s.failures+1 && s.breakAfter > 0 {
+		w.Header().Set("ETag", `"v1"`)
+		// Advertise the full length so that the client sees the truncated
+		// body as an error
+		w.Header().Set("Content-Length", strconv.Itoa(len(s.content)))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(s.content[:s.breakAfter])
+		w.(http.Flusher).Flush()
+		// Hijack and close the connection to simulate a network failure
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			_ = conn.Close()
+		}
+		return
+	}
+
+	w.Header().Set("ETag", `"v1"`)
+	http.ServeContent(w, r, "artifact.tar.gz", time.Time{}, bytes.NewReader(s.content))
+}
+
+func testFetcher(registry metrics.Registry) *ResumableFetcher {
+	return NewResumableFetcher(nil, ResumableFetcherOptions{
+		Retries:         3,
+		InitialBackoff:  time.Millisecond,
+		MaxBackoff:      time.Millisecond,
+		MetricsRegistry: registry,
+	})
+}
+
+func TestResumableFetcherResumesInterruptedDownload(t *testing.T) {
+	content := []byte(strings.Repeat("0123456789", 1000000))
+	server := &flakyServer{content: content, breakAfter: 12345}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL + "/artifact.tar.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registry := metrics.NewRegistry()
+	reader, err := testFetcher(registry).Open(u)
+	if err != nil {
+		t.Fatalf("Unexpected error opening download: %s", err)
+	}
+	defer reader.Close()
+
+	downloaded, err := ioutil.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("Unexpected error reading download: %s", err)
+	}
+	if !bytes.Equal(downloaded, server.content) {
+		t.Fatalf("Downloaded %d bytes that did not match the %d served", len(downloaded), len(server.content))
+	}
+
+	server.mux.Lock()
+	defer server.mux.Unlock()
+	if len(server.requests) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(server.requests))
+	}
+	if rangeHeader := server.requests[1].Header.Get("Range"); rangeHeader != "bytes=12345-" {
+		t.Errorf("Expected resumed request to ask for 'bytes=12345-', got %q", rangeHeader)
+	}
+	if ifRange := server.requests[1].Header.Get("If-Range"); ifRange != `"v1"` {
+		t.Errorf("Expected resumed request to send the ETag as If-Range, got %q", ifRange)
+	}
+	if resumes := metrics.GetOrRegisterCounter("artifact_download_resumes", registry).Count(); resumes != 1 {
+		t.Errorf("Expected 1 resume to be recorded, got %d", resumes)
+	}
+	if downloadedBytes := metrics.GetOrRegisterCounter("artifact_download_bytes", registry).Count(); downloadedBytes != int64(len(server.content)) {
+		t.Errorf("Expected %d bytes to be recorded, got %d", len(server.content), downloadedBytes)
+	}
+}
+
+func TestResumableFetcherRetriesServerErrors(t *testing.T) {
+	server := &flakyServer{content: []byte("artifact contents"), failures: 2}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL + "/artifact.tar.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := testFetcher(nil).Open(u)
+	if err != nil {
+		t.Fatalf("Expected download to succeed after retries: %s", err)
+	}
+	defer reader.Close()
+
+	downloaded, err := ioutil.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(downloaded) != "artifact contents" {
+		t.Errorf("Unexpected download contents %q", string(downloaded))
+	}
+}
+
+func TestResumableFetcherGivesUpAfterRetries(t *testing.T) {
+	server := &flakyServer{content: []byte("artifact contents"), failures: 10}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL + "/artifact.tar.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = testFetcher(nil).Open(u)
+	if err == nil {
+		t.Fatal("Expected download to fail once retries were exhausted")
+	}
+
+	server.mux.Lock()
+	defer server.mux.Unlock()
+	if len(server.requests) != 4 {
+		t.Errorf("Expected 1 request and 3 retries, got %d requests", len(server.requests))
+	}
+}
+
+func TestResumableFetcherLimitsBandwidth(t *testing.T) {
+	content := []byte(strings.Repeat("x", 3000))
+	ts := httptest.NewServer(&flakyServer{content: content})
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL + "/artifact.tar.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fetcher := NewResumableFetcher(nil, ResumableFetcherOptions{BytesPerSecond: 1000})
+	start := time.Now()
+	reader, err := fetcher.Open(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+	downloaded, err := ioutil.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(downloaded) != len(content) {
+		t.Fatalf("Expected %d bytes, got %d", len(content), len(downloaded))
+	}
+
+	// The bucket starts with one second's worth of tokens, so 3000 bytes at
+	// 1000 bytes/second should take about two seconds
+	if elapsed := time.Since(start); elapsed < 1500*time.Millisecond {
+		t.Errorf("Expected bandwidth limit to slow the download, but it took only %s", elapsed)
+	}
+}
+
+func TestBandwidthLimiterRefillRate(t *testing.T) {
+	// 600 MB/s is not a whole number of nanoseconds per byte
+	limiter := newBandwidthLimiter(600 * 1000 * 1000)
+	start := limiter.refilled
+	limiter.available = 0
+
+	limiter.refill(start.Add(time.Millisecond))
+	if limiter.available != 600*1000 {
+		t.Errorf("Expected 600000 bytes after a millisecond, got %d", limiter.available)
+	}
+
+	// Fractions of a byte carry over between refills
+	limiter = newBandwidthLimiter(3)
+	start = limiter.refilled
+	limiter.available = 0
+	limiter.refill(start.Add(500 * time.Millisecond))
+	if limiter.available != 1 {
+		t.Errorf("Expected 1 byte after half a second, got %d", limiter.available)
+	}
+	limiter.refill(start.Add(700 * time.Millisecond))
+	if limiter.available != 2 {
+		t.Errorf("Expected 2 bytes after 700ms, got %d", limiter.available)
+	}
+}
@@ -0,0 +1,392 @@
+package uri
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/square/p2/pkg/util"
+)
+
+const (
+	S3Scheme = "s3"
+
+	s3Algorithm   = "AWS4-HMAC-SHA256"
+	s3Service     = "s3"
+	s3DateFormat  = "20060102T150405Z"
+	s3ScopeFormat = "20060102"
+
+	// hex encoded SHA-256 of an empty payload, which is what every GET and
+	// HEAD request sends
+	emptyPayloadSHA256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+	// credentials fetched from a metadata endpoint are refreshed this long
+	// before they expire
+	s3CredentialsRefreshWindow = 5 * time.Minute
+)
+
+// S3Config describes how to reach an S3-compatible object store. Static
+// credentials take precedence over CredentialsURL.
+type S3Config struct {
+	// Endpoint is the base URL of the object store, e.g.
+	// "https://s3.us-west-2.amazonaws.com"
+	Endpoint string `yaml:"endpoint"`
+	Region   string `yaml:"region"`
+
+	AccessKeyID     string `yaml:"access_key_id,omitempty"`
+	SecretAccessKey string `yaml:"secret_access_key,omitempty"`
+	SessionToken    string `yaml:"session_token,omitempty"`
+
+	// CredentialsURL is a local endpoint serving temporary credentials as
+	// JSON with the keys "AccessKeyId", "SecretAccessKey", "Token" and
+	// "Expiration", in the style of an instance metadata service.
+	CredentialsURL string `yaml:"credentials_url,omitempty"`
+
+	// By default, objects are addressed as <endpoint>/<bucket>/<key>. When
+	// VirtualHostedStyle is set, they are addressed as
+	// <bucket>.<endpoint host>/<key> instead.
+	VirtualHostedStyle bool `yaml:"virtual_hosted_style,omitempty"`
+}
+
+// S3Credentials are used to sign requests to the object store.
+type S3Credentials struct {
+	AccessKeyID     string    `json:"AccessKeyId"`
+	SecretAccessKey string    `json:"SecretAccessKey"`
+	SessionToken    string    `json:"Token"`
+	Expiration      time.Time `json:"Expiration"`
+}
+
+type S3CredentialsProvider interface {
+	Credentials() (S3Credentials, error)
+}
+
+type staticS3Credentials S3Credentials
+
+func (c staticS3Credentials) Credentials() (S3Credentials, error) {
+	return S3Credentials(c), nil
+}
+
+// metadataS3Credentials fetches temporary credentials from a local HTTP
+// endpoint and caches them until shortly before they expire.
+type metadataS3Credentials struct {
+	client *http.Client
+	url    string
+
+	mux    sync.Mutex
+	cached S3Credentials
+}
+
+func (m *metadataS3Credentials) Credentials() (S3Credentials, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if m.cached.AccessKeyID != "" && time.Now().Add(s3CredentialsRefreshWindow).Before(m.cached.Expiration) {
+		return m.cached, nil
+	}
+
+	resp, err := m.client.Get(m.url)
+	if err != nil {
+		return S3Credentials{}, util.Errorf("could not fetch S3 credentials: %s", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return S3Credentials{}, util.Errorf("could not fetch S3 credentials: %q returned status %s", m.url, resp.Status)
+	}
+
+	var creds S3Credentials
+	err = json.NewDecoder(resp.Body).Decode(&creds)
+	if err != nil {
+		return S3Credentials{}, util.Errorf("could not parse S3 credentials from %q: %s", m.url, err)
+	}
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return S3Credentials{}, util.Errorf("incomplete S3 credentials returned by %q", m.url)
+	}
+	m.cached = creds
+	return creds, nil
+}
+
+// S3Fetcher handles URIs of the form s3://bucket/key by issuing SigV4 signed
+// requests to an S3-compatible object store. URIs with any other scheme are
+// passed to the wrapped Fetcher. If the wrapped Fetcher is a ResumableFetcher,
+// objects are downloaded through it so that they are retried, resumed and
+// bandwidth limited like any other artifact.
+type S3Fetcher struct {
+	fetcher     Fetcher
+	client      *http.Client
+	endpoint    *url.URL
+	config      S3Config
+	credentials S3CredentialsProvider
+
+	// now is swapped out by tests
+	now func() time.Time
+}
+
+var _ Fetcher = &S3Fetcher{}
+
+func NewS3Fetcher(fetcher Fetcher, client *http.Client, config S3Config) (*S3Fetcher, error) {
+	if fetcher == nil {
+		fetcher = DefaultFetcher
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if config.Endpoint == "" {
+		return nil, util.Errorf("S3 endpoint must be configured")
+	}
+	if config.Region == "" {
+		return nil, util.Errorf("S3 region must be configured")
+	}
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil {
+		return nil, util.Errorf("could not parse S3 endpoint %q: %s", config.Endpoint, err)
+	}
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+		return nil, util.Errorf("S3 endpoint %q must use http or https", config.Endpoint)
+	}
+
+	var credentials S3CredentialsProvider
+	switch {
+	case config.AccessKeyID != "" || config.SecretAccessKey != "":
+		if config.AccessKeyID == "" || config.SecretAccessKey == "" {
+			return nil, util.Errorf("both an S3 access key ID and secret access key must be configured")
+		}
+		credentials = staticS3Credentials{
+			AccessKeyID:     config.AccessKeyID,
+			SecretAccessKey: config.SecretAccessKey,
+			SessionToken:    config.SessionToken,
+		}
+	case config.CredentialsURL != "":
+		credentials = &metadataS3Credentials{
+			client: client,
+			url:    config.CredentialsURL,
+		}
+	default:
+		return nil, util.Errorf("S3 credentials or a credentials URL must be configured")
+	}
+
+	return &S3Fetcher{
+		fetcher:     fetcher,
+		client:      client,
+		endpoint:    endpoint,
+		config:      config,
+		credentials: credentials,
+		now:         time.Now,
+	}, nil
+}
+
+func (f *S3Fetcher) Open(u *url.URL) (io.ReadCloser, error) {
+	if u.Scheme != S3Scheme {
+		return f.fetcher.Open(u)
+	}
+
+	if resumable, ok := f.fetcher.(*ResumableFetcher); ok {
+		objectURL, err := f.objectURL(u)
+		if err != nil {
+			return nil, err
+		}
+		return resumable.OpenWithRequestHook(objectURL, f.sign)
+	}
+
+	resp, err := f.do("GET", u)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, util.Errorf("%q: object store returned status: %s", u.String(), resp.Status)
+	}
+	return resp.Body, nil
+}
+
+func (f *S3Fetcher) Head(u *url.URL) (*http.Response, error) {
+	if u.Scheme != S3Scheme {
+		return f.fetcher.Head(u)
+	}
+	return f.do("HEAD", u)
+}
+
+func (f *S3Fetcher) CopyLocal(srcUri *url.URL, dstPath string) error {
+	if srcUri.Scheme != S3Scheme {
+		return f.fetcher.CopyLocal(srcUri, dstPath)
+	}
+	return copyLocal(f, srcUri, dstPath)
+}
+
+func (f *S3Fetcher) do(method string, u *url.URL) (*http.Response, error) {
+	objectURL, err := f.objectURL(u)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(method, objectURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	err = f.sign(req)
+	if err != nil {
+		return nil, err
+	}
+	return f.client.Do(req)
+}
+
+// sign adds SigV4 headers to a request for an object. It must be called
+// after every other header that will be sent has been set.
+func (f *S3Fetcher) sign(req *http.Request) error {
+	creds, err := f.credentials.Credentials()
+	if err != nil {
+		return err
+	}
+	signS3Request(req, creds, f.config.Region, f.now())
+	return nil
+}
+
+// objectURL translates s3://bucket/key into the object store's HTTP URL.
+func (f *S3Fetcher) objectURL(u *url.URL) (*url.URL, error) {
+	bucket := u.Host
+	key := strings.TrimPrefix(u.Path, "/")
+	if bucket == "" || key == "" {
+		return nil, util.Errorf("%q: S3 URIs must have the form s3://bucket/key", u.String())
+	}
+
+	objectURL := *f.endpoint
+	basePath := strings.TrimSuffix(f.endpoint.Path, "/")
+	if f.config.VirtualHostedStyle {
+		objectURL.Host = bucket + "." + f.endpoint.Host
+		objectURL.Path = basePath + "/" + key
+	} else {
+		objectURL.Path = basePath + "/" + bucket + "/" + key
+	}
+	objectURL.RawPath = ""
+	objectURL.RawQuery = u.RawQuery
+	return &objectURL, nil
+}
+
+// signS3Request adds AWS Signature Version 4 headers to a request with an
+// empty body. The host, the range and all x-amz-* headers are signed.
+func signS3Request(req *http.Request, creds S3Credentials, region string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format(s3DateFormat)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", emptyPayloadSHA256)
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+
+	headers := map[string]string{
+		"host": req.URL.Host,
+	}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, "x-amz-") || lower == "range" {
+			headers[lower] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&canonicalHeaders, "%s:%s\n", name, headers[name])
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		s3EscapePath(req.URL.Path),
+		s3CanonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		emptyPayloadSHA256,
+	}, "\n")
+
+	scope := strings.Join([]string{now.Format(s3ScopeFormat), region, s3Service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{
+		s3Algorithm,
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+creds.SecretAccessKey), now.Format(s3ScopeFormat))
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, s3Service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm,
+		creds.AccessKeyID,
+		scope,
+		signedHeaders,
+		signature,
+	))
+}
+
+// s3EscapePath URI-encodes each segment of an object path as SigV4 requires.
+func s3EscapePath(path string) string {
+	if path == "" {
+		return "/"
+	}
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = s3Escape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+func s3CanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, s3Escape(key)+"="+s3Escape(value))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// s3Escape percent-encodes everything except the unreserved characters
+// A-Z, a-z, 0-9, '-', '.', '_' and '~'.
+func s3Escape(s string) string {
+	var escaped strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') ||
+			c == '-' || c == '.' || c == '_' || c == '~' {
+			escaped.WriteByte(c)
+		} else {
+			fmt.Fprintf(&escaped, "%%%02X", c)
+		}
+	}
+	return escaped.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -75,7 +75,12 @@ func (f BasicFetcher) Head(u *url.URL) (*http.Response, error) {
 	return f.Client.Head(u.String())
 }
 
-func (f BasicFetcher) CopyLocal(srcUri *url.URL, dstPath string) (err error) {
+func (f BasicFetcher) CopyLocal(srcUri *url.URL, dstPath string) error {
+	return copyLocal(f, srcUri, dstPath)
+}
+
+// copyLocal implements CopyLocal in terms of a Fetcher's Open method.
+func copyLocal(f Fetcher, srcUri *url.URL, dstPath string) (err error) {
 	src, err := f.Open(srcUri)
 	if err != nil {
 		return